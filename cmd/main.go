@@ -5,15 +5,42 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"strconv"
 
 	"golang.org/x/crypto/bcrypt"
-	_ "modernc.org/sqlite"
 
 	"beer_oclock/internal/db"
+	"beer_oclock/internal/ean"
+	"beer_oclock/internal/events"
+	"beer_oclock/internal/mail"
+	"beer_oclock/internal/notify"
 	"beer_oclock/internal/server"
+	"beer_oclock/internal/store/badges"
+	"beer_oclock/internal/store/barcodes"
 	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/blobs"
 	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/budgets"
+	"beer_oclock/internal/store/comments"
+	"beer_oclock/internal/store/drinklog"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/emails"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/outbox"
+	"beer_oclock/internal/store/photos"
+	"beer_oclock/internal/store/resets"
+	"beer_oclock/internal/store/schedules"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/shouts"
+	"beer_oclock/internal/store/social"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/store/tags"
+	"beer_oclock/internal/store/tastings"
 	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/store/venues"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/store/wishlist"
 
 	_ "github.com/joho/godotenv/autoload" // Automatically load .env file
 )
@@ -23,18 +50,25 @@ func main() {
 
 	port := 9000
 
-	dbPool, err := sql.Open("sqlite", "db.sqlite")
-	if err != nil {
-		logger.Fatalf("Error when opening database: %s", err)
+	// SQLite by default, or PostgreSQL for a shared instance with more concurrent writers
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = db.DriverSqlite
+	}
+	dataSource := os.Getenv("DATABASE_URL")
+	if dataSource == "" && driver == db.DriverSqlite {
+		dataSource = "db.sqlite"
 	}
 
-	log.Println("Initializing database...")
-	if err := db.GenSchema(dbPool); err != nil {
-		log.Fatal(err)
+	log.Printf("Initializing %s database...", driver)
+	dbPool, queries, err := db.Open(driver, dataSource)
+	if err != nil {
+		logger.Fatalf("Error when opening database: %s", err)
 	}
+	defer dbPool.Close()
 
 	logger.Print("Creating users store..")
-	userStore := users.NewUserStore(db.New(dbPool), logger)
+	userStore := users.NewUserStore(queries, logger)
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
 	if err != nil {
 		logger.Fatalf("Error when hashing password: %s", err)
@@ -42,19 +76,183 @@ func main() {
 	userStore.AddUser(context.Background(), db.AddUserParams{
 		Username:     "saltytaro",
 		PasswordHash: string(passwordHash),
+		IsAdmin:      true,
 	})
 
+	// Changes to brewers and beers are published for the pages showing them to update
+	bus := events.NewBus()
+
 	logger.Print("Creating brewers store..")
-	brewerStore := brewers.NewBrewerStore(db.New(dbPool), logger)
+	brewerStore := brewers.NewPublishingBrewerStore(brewers.NewBrewerStore(queries, logger), bus)
 	brewerStore.AddBrewer(context.Background(), db.AddBrewerParams{
 		Name:     "Felon's",
 		Location: sql.NullString{Valid: true, String: "Brisbane"},
 	})
 
 	logger.Print("Creating beers store...")
-	beerStore := beers.NewBeerStore(db.New(dbPool), logger)
+	beerStore := beers.NewPublishingBeerStore(beers.NewBeerStore(queries, logger), bus)
+
+	logger.Print("Creating styles store...")
+	styleStore := styles.NewStyleStore(queries, logger)
+	if added, err := styles.Seed(context.Background(), styleStore); err != nil {
+		logger.Fatalf("Error when seeding styles: %s", err)
+	} else if added > 0 {
+		logger.Printf("Added %d styles", added)
+	}
+	if migrated, err := styles.MigrateBeerStyles(context.Background(), styleStore, beerStore, logger); err != nil {
+		logger.Fatalf("Error when migrating beer styles: %s", err)
+	} else if migrated > 0 {
+		logger.Printf("Mapped the styles of %d beers", migrated)
+	}
+
+	logger.Print("Creating scorecards store...")
+	scorecardStore := scorecards.NewScorecardStore(queries, logger)
+
+	logger.Print("Creating tags store...")
+	tagStore := tags.NewTagStore(queries, logger)
+	if added, err := tags.Seed(context.Background(), tagStore); err != nil {
+		logger.Fatalf("Error when seeding tags: %s", err)
+	} else if added > 0 {
+		logger.Printf("Added %d tags", added)
+	}
+
+	logger.Print("Creating photos store...")
+	photoStore := photos.NewPhotoStore(queries, logger)
+	photoDir := os.Getenv("PHOTO_DIR")
+	if photoDir == "" {
+		photoDir = "photos"
+	}
+	blobStore, err := blobs.NewFileBlobStore(photoDir, logger)
+	if err != nil {
+		logger.Fatalf("Error when creating photo directory: %s", err)
+	}
+
+	logger.Print("Creating barcodes store...")
+	barcodeStore := barcodes.NewBarcodeStore(queries, logger)
+	barcodeLookup := ean.DefaultFixtureLookup()
+	if fixturesPath := os.Getenv("BARCODE_FIXTURES"); fixturesPath != "" {
+		fixtures, err := os.Open(fixturesPath)
+		if err != nil {
+			logger.Fatalf("Error when opening barcode fixtures: %s", err)
+		}
+		barcodeLookup, err = ean.NewFixtureLookup(fixtures)
+		fixtures.Close()
+		if err != nil {
+			logger.Fatalf("Error when reading barcode fixtures: %s", err)
+		}
+	}
+
+	logger.Print("Creating stock store...")
+	stockStore := stock.NewStockStore(queries, logger)
 
-	srv, err := server.NewServer(logger, port, userStore, brewerStore, beerStore)
+	logger.Print("Creating budget store...")
+	budgetStore := budgets.NewBudgetStore(queries, logger)
+
+	logger.Print("Creating drink store...")
+	drinkStore := drinklog.NewDrinkStore(queries, logger)
+
+	logger.Print("Creating goal store...")
+	goalStore := goals.NewGoalStore(queries, logger)
+
+	logger.Print("Creating schedule store...")
+	scheduleStore := schedules.NewScheduleStore(queries, logger)
+
+	// Reminders are logged unless REMINDER_FILE says where to write them
+	var notifier notify.Notifier = notify.NewLogNotifier(logger)
+	if reminderFile := os.Getenv("REMINDER_FILE"); reminderFile != "" {
+		fileNotifier, err := notify.NewFileNotifier(reminderFile)
+		if err != nil {
+			logger.Fatalf("Error when opening reminder file: %s", err)
+		}
+		defer fileNotifier.Close()
+		notifier = fileNotifier
+	}
+
+	logger.Print("Creating email stores...")
+	emailStore := emails.NewEmailStore(queries, logger)
+	resetStore := resets.NewResetStore(queries, logger)
+	outboxStore := outbox.NewOutboxStore(queries, logger)
+
+	logger.Print("Creating webhook store...")
+	webhookStore := webhooks.NewWebhookStore(queries, logger)
+
+	logger.Print("Creating drinking session store...")
+	drinkSessionStore := drinksessions.NewSessionStore(queries, logger)
+
+	logger.Print("Creating venue store...")
+	venueStore := venues.NewVenueStore(queries, logger)
+
+	logger.Print("Creating shout store...")
+	shoutStore := shouts.NewShoutStore(queries, logger)
+
+	logger.Print("Creating social store...")
+	socialStore := social.NewSocialStore(queries, logger)
+
+	logger.Print("Creating comment store...")
+	commentStore := comments.NewCommentStore(queries, logger)
+
+	logger.Print("Creating tasting store...")
+	tastingStore := tastings.NewTastingStore(queries, logger)
+
+	logger.Print("Creating badge store...")
+	badgeStore := badges.NewBadgeStore(queries, logger)
+
+	logger.Print("Creating wishlist store...")
+	wishlistStore := wishlist.NewWishlistStore(queries, logger)
+
+	// Emails are logged unless SMTP_HOST says where to send them
+	var mailer mail.Sender = mail.NewLogSender(logger)
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpPort := 587
+		if smtpPortStr := os.Getenv("SMTP_PORT"); smtpPortStr != "" {
+			smtpPort, err = strconv.Atoi(smtpPortStr)
+			if err != nil {
+				logger.Fatalf("SMTP_PORT must be a port number: %s", err)
+			}
+		}
+		smtpFrom := os.Getenv("SMTP_FROM")
+		if smtpFrom == "" {
+			smtpFrom = "Beer O'clock <beer@" + smtpHost + ">"
+		}
+		mailer, err = mail.NewSMTPSender(smtpHost, smtpPort, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), smtpFrom)
+		if err != nil {
+			logger.Fatalf("Error when setting up email: %s", err)
+		}
+	}
+
+	srv, err := server.NewServer(logger, port, server.Stores{
+		Users:         userStore,
+		Brewers:       brewerStore,
+		Beers:         beerStore,
+		Styles:        styleStore,
+		Scorecards:    scorecardStore,
+		Tags:          tagStore,
+		Photos:        photoStore,
+		Barcodes:      barcodeStore,
+		Stock:         stockStore,
+		Budgets:       budgetStore,
+		Drinks:        drinkStore,
+		Goals:         goalStore,
+		Schedules:     scheduleStore,
+		Emails:        emailStore,
+		Resets:        resetStore,
+		Outbox:        outboxStore,
+		Webhooks:      webhookStore,
+		DrinkSessions: drinkSessionStore,
+		Venues:        venueStore,
+		Shouts:        shoutStore,
+		Social:        socialStore,
+		Comments:      commentStore,
+		Tastings:      tastingStore,
+		Badges:        badgeStore,
+		Wishlist:      wishlistStore,
+		Blobs:         blobStore,
+		Lookup:        barcodeLookup,
+		Notifier:      notifier,
+		Mailer:        mailer,
+		Events:        bus,
+		Queries:       queries,
+	})
 	if err != nil {
 		logger.Fatalf("Error when creating server: %s", err)
 		os.Exit(1)
@@ -0,0 +1,101 @@
+// Package charts lays out bar charts, which the templates draw as SVG, so the stats pages don't
+// need any JavaScript
+package charts
+
+import "math"
+
+// A value to be shown as a bar
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// A bar placed on a chart, in SVG user units with the origin at the top left
+type PlacedBar struct {
+	Bar
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// The bars of a chart placed inside a Width by Height view box. Max is the value the longest bar
+// could have, which the axis goes up to.
+type Chart struct {
+	Width  float64
+	Height float64
+	Max    float64
+	Bars   []PlacedBar
+}
+
+// The room left around the bars for their labels and values
+const (
+	LabelHeight = 20
+	ValueHeight = 14
+	LabelWidth  = 140
+	ValueWidth  = 40
+	// The gap between bars, as a fraction of the room each one has
+	gap = 0.2
+)
+
+// Rounds up to the next 1, 2 or 5 times a power of ten, so the axis stops somewhere round
+func NiceMax(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if value <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// Lays the bars out left to right, growing up from a baseline with the labels below it
+func Vertical(bars []Bar, width float64, height float64) Chart {
+	chart := Chart{Width: width, Height: height, Max: NiceMax(maxValue(bars))}
+	if len(bars) == 0 {
+		return chart
+	}
+
+	slot := width / float64(len(bars))
+	plot := height - LabelHeight - ValueHeight
+	for i, bar := range bars {
+		h := plot * max(bar.Value, 0) / chart.Max
+		chart.Bars = append(chart.Bars, PlacedBar{
+			Bar:    bar,
+			X:      float64(i)*slot + slot*gap/2,
+			Y:      ValueHeight + plot - h,
+			Width:  slot * (1 - gap),
+			Height: h,
+		})
+	}
+	return chart
+}
+
+// Lays the bars out top to bottom, each barHeight high, growing right with the labels to their
+// left. The chart is as high as the bars need.
+func Horizontal(bars []Bar, width float64, barHeight float64) Chart {
+	chart := Chart{Width: width, Height: barHeight * float64(len(bars)), Max: NiceMax(maxValue(bars))}
+
+	plot := width - LabelWidth - ValueWidth
+	for i, bar := range bars {
+		chart.Bars = append(chart.Bars, PlacedBar{
+			Bar:    bar,
+			X:      LabelWidth,
+			Y:      float64(i)*barHeight + barHeight*gap/2,
+			Width:  plot * max(bar.Value, 0) / chart.Max,
+			Height: barHeight * (1 - gap),
+		})
+	}
+	return chart
+}
+
+func maxValue(bars []Bar) float64 {
+	largest := 0.0
+	for _, bar := range bars {
+		largest = max(largest, bar.Value)
+	}
+	return largest
+}
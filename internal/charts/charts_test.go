@@ -0,0 +1,51 @@
+package charts
+
+import "testing"
+
+func TestNiceMax(t *testing.T) {
+	for value, want := range map[float64]float64{0: 1, -3: 1, 0.3: 0.5, 1: 1, 1.5: 2, 3: 5, 7: 10, 10: 10, 11: 20, 45: 50, 120: 200} {
+		if got := NiceMax(value); got != want {
+			t.Errorf("NiceMax(%v): got %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestVertical(t *testing.T) {
+	chart := Vertical([]Bar{{"Mon", 10}, {"Tue", 5}, {"Wed", 0}, {"Thu", 2.5}}, 400, 134)
+	if chart.Max != 10 || len(chart.Bars) != 4 {
+		t.Fatalf("Vertical: got %+v", chart)
+	}
+
+	// 100 units high between the values and the labels, and 100 units wide for each bar
+	for i, want := range []PlacedBar{
+		{X: 10, Y: 14, Width: 80, Height: 100},
+		{X: 110, Y: 64, Width: 80, Height: 50},
+		{X: 210, Y: 114, Width: 80, Height: 0},
+		{X: 310, Y: 89, Width: 80, Height: 25},
+	} {
+		got := chart.Bars[i]
+		if got.X != want.X || got.Y != want.Y || got.Width != want.Width || got.Height != want.Height {
+			t.Errorf("Vertical bar %d: got %+v, want %+v", i, got, want)
+		}
+	}
+	if chart.Bars[1].Label != "Tue" || chart.Bars[1].Value != 5 {
+		t.Errorf("Vertical: got %+v, want the bar it was given", chart.Bars[1])
+	}
+
+	if empty := Vertical(nil, 400, 134); len(empty.Bars) != 0 || empty.Max != 1 {
+		t.Errorf("Vertical with no bars: got %+v", empty)
+	}
+}
+
+func TestHorizontal(t *testing.T) {
+	chart := Horizontal([]Bar{{"Pale Ale", 4}, {"Stout", 3}}, 380, 20)
+	if chart.Height != 40 || chart.Max != 5 {
+		t.Fatalf("Horizontal: got %+v", chart)
+	}
+	if got := chart.Bars[0]; got.X != LabelWidth || got.Y != 2 || got.Width != 160 || got.Height != 16 {
+		t.Errorf("Horizontal bar 0: got %+v", got)
+	}
+	if got := chart.Bars[1]; got.Y != 22 || got.Width != 120 {
+		t.Errorf("Horizontal bar 1: got %+v", got)
+	}
+}
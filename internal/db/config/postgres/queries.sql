@@ -0,0 +1,1332 @@
+/* === CONTACTS === */
+
+-- name: AddUser :one
+INSERT INTO users (username, password_hash, is_admin)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetUserById :one
+SELECT * 
+FROM users
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUserByUsername :one
+SELECT *
+FROM users
+WHERE username = $1 AND deleted_at IS NULL;
+
+-- name: GetUsers :many
+SELECT *
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedUsers :many
+SELECT *
+FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeUser :one
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
+-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: SetUserLastLogin :exec
+UPDATE users
+SET last_login = now()
+WHERE id = $1;
+
+-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $1
+WHERE id = $2;
+
+/* === BREWERS === */
+
+-- name: AddBrewer :one
+INSERT INTO brewers (name, location)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetBrewerById :one
+SELECT *
+FROM brewers
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetBrewers :many
+SELECT *
+FROM brewers
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: DeleteBrewer :one
+UPDATE brewers
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreBrewer :one
+UPDATE brewers
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedBrewers :many
+SELECT *
+FROM brewers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeBrewer :one
+DELETE FROM brewers
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedBrewers :execrows
+DELETE FROM brewers
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
+-- name: CountBrewers :one
+SELECT COUNT(*)
+FROM brewers
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+/* === BEERS === */
+
+-- name: AddBeer :one
+INSERT INTO beers (name, brewer_id, style, abv, rating, notes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetBeerById :one
+SELECT *
+FROM beers
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetBeers :many
+SELECT *
+FROM beers
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: DeleteBeer :one
+UPDATE beers
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreBeer :one
+UPDATE beers
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedBeers :many
+SELECT *
+FROM beers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeBeer :one
+DELETE FROM beers
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedBeers :execrows
+DELETE FROM beers
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
+-- name: CountBeers :one
+SELECT COUNT(*)
+FROM beers
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: UpdateBeer :one
+UPDATE beers
+SET 
+    name = coalesce(sqlc.narg('name'), name),
+    brewer_id = coalesce(sqlc.narg('brewer_id'), brewer_id),
+    style = coalesce(sqlc.narg('style'), style),
+    abv = coalesce(sqlc.narg('abv'), abv),
+    rating = coalesce(sqlc.narg('rating'), rating),
+    notes = coalesce(sqlc.narg('notes'), notes)
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RevertBeer :one
+UPDATE beers
+SET name = $1, brewer_id = $2, style = $3, abv = $4, rating = $5, notes = $6
+WHERE id = $7 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SearchBeers :many
+SELECT *
+FROM beers
+WHERE (name ILIKE '%' || sqlc.arg('query') || '%' OR style ILIKE '%' || sqlc.arg('query') || '%' OR notes ILIKE '%' || sqlc.arg('query') || '%')
+    AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetBeerStyles :many
+SELECT DISTINCT style
+FROM beers
+WHERE style IS NOT NULL AND style != ''
+ORDER BY style;
+
+-- name: RenameBeerStyle :many
+UPDATE beers
+SET style = sqlc.arg('new_style')
+WHERE style = sqlc.arg('old_style')
+RETURNING *;
+
+-- name: SetBeerRating :exec
+UPDATE beers
+SET rating = $1
+WHERE id = $2;
+
+/* === BEER REVISIONS === */
+
+-- name: AddBeerRevision :one
+INSERT INTO beer_revisions (beer_id, user_id, name, brewer_id, style, abv, rating, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetBeerRevision :one
+SELECT *
+FROM beer_revisions
+WHERE id = $1 AND beer_id = $2;
+
+-- name: GetBeerRevisions :many
+SELECT sqlc.embed(beer_revisions), users.username AS author, brewers.name AS brewer_name
+FROM beer_revisions
+LEFT JOIN users ON users.id = beer_revisions.user_id
+LEFT JOIN brewers ON brewers.id = beer_revisions.brewer_id
+WHERE beer_revisions.beer_id = $1
+ORDER BY beer_revisions.id;
+
+/* === STYLES === */
+
+-- name: AddStyle :one
+INSERT INTO styles (code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetStyleById :one
+SELECT *
+FROM styles
+WHERE id = $1;
+
+-- name: GetStyleByName :one
+SELECT *
+FROM styles
+WHERE lower(name) = lower(sqlc.arg('name'));
+
+-- name: GetStyles :many
+SELECT *
+FROM styles
+ORDER BY id;
+
+-- name: SearchStyles :many
+SELECT *
+FROM styles
+WHERE name ILIKE '%' || sqlc.arg('query') || '%' OR category ILIKE '%' || sqlc.arg('query') || '%' OR lower(code) = lower(sqlc.arg('query'))
+ORDER BY id
+LIMIT sqlc.arg('max_results')::bigint;
+
+/* === SCORECARDS === */
+
+-- name: GetScorecardWeights :one
+SELECT *
+FROM scorecard_weights
+WHERE id = 1;
+
+-- name: SetScorecardWeights :one
+INSERT INTO scorecard_weights (id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES (1, $1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall
+RETURNING *;
+
+-- name: SaveScorecard :one
+INSERT INTO scorecards (beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+ON CONFLICT (beer_id, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    total = excluded.total,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetScorecard :one
+SELECT *
+FROM scorecards
+WHERE beer_id = $1 AND user_id = $2;
+
+-- name: GetBeerScorecards :many
+SELECT sqlc.embed(scorecards), users.username
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+WHERE scorecards.beer_id = $1
+ORDER BY scorecards.total DESC, scorecards.id;
+
+-- name: GetBeerScoreAverages :one
+SELECT
+    COUNT(*) AS count,
+    COALESCE(AVG(aroma), 0)::double precision AS aroma,
+    COALESCE(AVG(appearance), 0)::double precision AS appearance,
+    COALESCE(AVG(flavour), 0)::double precision AS flavour,
+    COALESCE(AVG(mouthfeel), 0)::double precision AS mouthfeel,
+    COALESCE(AVG(overall), 0)::double precision AS overall,
+    COALESCE(AVG(total), 0)::double precision AS total
+FROM scorecards
+WHERE beer_id = $1;
+
+-- name: GetScorecards :many
+SELECT *
+FROM scorecards
+ORDER BY id;
+
+-- name: SetScorecardTotal :exec
+UPDATE scorecards
+SET total = $1
+WHERE id = $2;
+
+/* === TAGS === */
+
+-- name: AddTag :one
+INSERT INTO tags (name, category)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetTagByName :one
+SELECT *
+FROM tags
+WHERE name = $1;
+
+-- name: GetTags :many
+SELECT *
+FROM tags
+ORDER BY name;
+
+-- name: GetTagCounts :many
+SELECT sqlc.embed(tags), COUNT(beers.id) AS beer_count
+FROM tags
+LEFT JOIN beer_tags ON beer_tags.tag_id = tags.id
+LEFT JOIN beers ON beers.id = beer_tags.beer_id AND beers.deleted_at IS NULL
+GROUP BY tags.id
+ORDER BY tags.name;
+
+-- name: GetBeerTags :many
+SELECT tags.*
+FROM tags
+JOIN beer_tags ON beer_tags.tag_id = tags.id
+WHERE beer_tags.beer_id = $1
+ORDER BY tags.name;
+
+-- name: GetAllBeerTags :many
+SELECT beer_tags.beer_id, sqlc.embed(tags)
+FROM beer_tags
+JOIN tags ON tags.id = beer_tags.tag_id
+ORDER BY beer_tags.beer_id, tags.name;
+
+-- name: AddBeerTag :exec
+INSERT INTO beer_tags (beer_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ClearBeerTags :exec
+DELETE FROM beer_tags
+WHERE beer_id = $1;
+
+-- name: GetBeersByTag :many
+SELECT beers.*
+FROM beers
+JOIN beer_tags ON beer_tags.beer_id = beers.id
+JOIN tags ON tags.id = beer_tags.tag_id
+WHERE tags.name = $1 AND beers.deleted_at IS NULL
+ORDER BY beers.id;
+
+/* === LABEL PHOTOS === */
+
+-- name: AddLabelPhoto :one
+INSERT INTO label_photos (beer_id, user_id, content_type, size, width, height, blob_key, thumb_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetLabelPhoto :one
+SELECT *
+FROM label_photos
+WHERE id = $1;
+
+-- name: GetBeerLabelPhotos :many
+SELECT *
+FROM label_photos
+WHERE beer_id = $1
+ORDER BY id;
+
+-- name: DeleteLabelPhoto :one
+DELETE FROM label_photos
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBeerLabelPhotos :many
+DELETE FROM label_photos
+WHERE beer_id = $1
+RETURNING *;
+
+/* === BARCODES === */
+
+-- name: AddBarcode :one
+-- Codes of beers in the trash are taken over, but not those of other beers
+INSERT INTO barcodes (code, beer_id)
+VALUES ($1, $2)
+ON CONFLICT (code) DO UPDATE
+SET beer_id = excluded.beer_id, created_at = CURRENT_TIMESTAMP
+WHERE barcodes.beer_id IN (SELECT id FROM beers WHERE deleted_at IS NOT NULL)
+RETURNING *;
+
+-- name: GetBarcode :one
+SELECT barcodes.*
+FROM barcodes
+JOIN beers ON beers.id = barcodes.beer_id
+WHERE barcodes.code = $1 AND beers.deleted_at IS NULL;
+
+-- name: GetBeerBarcodes :many
+SELECT *
+FROM barcodes
+WHERE beer_id = $1
+ORDER BY code;
+
+-- name: DeleteBarcode :one
+DELETE FROM barcodes
+WHERE code = $1
+RETURNING *;
+
+/* === STOCK === */
+
+-- name: AddStock :one
+INSERT INTO stock (beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetStock :one
+SELECT *
+FROM stock
+WHERE id = $1;
+
+-- name: GetFridge :many
+-- Soonest best-before first, with the entries which don't have one last
+SELECT sqlc.embed(stock), beers.name AS beer_name
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+ORDER BY stock.best_before IS NULL, stock.best_before, stock.purchased_on, stock.id;
+
+-- name: GetStockLevels :many
+SELECT stock.beer_id, CAST(SUM(stock.quantity) AS BIGINT) AS quantity
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+GROUP BY stock.beer_id
+ORDER BY stock.beer_id;
+
+-- name: TakeStock :one
+-- Takes from the entry which goes off first
+UPDATE stock
+SET quantity = quantity - 1
+WHERE id = (
+    SELECT id
+    FROM stock AS s
+    WHERE s.beer_id = $1 AND s.quantity > 0
+    ORDER BY s.best_before IS NULL, s.best_before, s.purchased_on, s.id
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: DeleteStock :one
+DELETE FROM stock
+WHERE id = $1
+RETURNING *;
+
+/* === SPENDING === */
+
+-- Beers in the trash still count, since the money's been spent
+
+-- name: GetSpendingByUser :many
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(users.username, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetSpendingByBrewer :many
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(brewers.name, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetSpendingByStyle :many
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(beers.style, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetUserSpending :many
+SELECT stock.currency, CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total
+FROM stock
+WHERE stock.user_id = $1 AND stock.price IS NOT NULL AND stock.purchased_on >= $2
+GROUP BY stock.currency
+ORDER BY stock.currency;
+
+-- name: GetPurchases :many
+SELECT
+    sqlc.embed(stock),
+    beers.name AS beer_name,
+    beers.abv,
+    beers.style,
+    brewers.name AS brewer_name,
+    users.username
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.purchased_on >= $1
+ORDER BY stock.purchased_on, stock.id;
+
+/* === BUDGETS === */
+
+-- name: SetBudget :one
+INSERT INTO budgets (user_id, amount, currency)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET amount = excluded.amount, currency = excluded.currency, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetBudget :one
+SELECT *
+FROM budgets
+WHERE user_id = $1;
+
+-- name: DeleteBudget :one
+DELETE FROM budgets
+WHERE user_id = $1
+RETURNING *;
+
+/* === DRINKS === */
+
+-- name: AddDrink :one
+INSERT INTO drinks (user_id, beer_id, serving_ml, drunk_at, drunk_on)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetDrink :one
+SELECT *
+FROM drinks
+WHERE id = $1;
+
+-- The user's drinks on the days from since up to but not including until, latest first. Beers
+-- in the trash are still included, since they were still drunk.
+-- name: GetUserDrinks :many
+SELECT sqlc.embed(drinks), beers.name AS beer_name, beers.abv
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+ORDER BY drinks.drunk_at DESC, drinks.id DESC;
+
+-- name: DeleteDrink :one
+DELETE FROM drinks
+WHERE id = $1
+RETURNING *;
+
+/* === STATS === */
+
+-- name: GetDrinksPerDay :many
+SELECT
+    drinks.drunk_on,
+    COUNT(*) AS drinks,
+    CAST(SUM(drinks.serving_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY drinks.drunk_on
+ORDER BY drinks.drunk_on;
+
+-- Drinks counted by the whole number of their ABV, e.g. 4.8% is counted under 4. CAST on its own
+-- rounds in PostgreSQL, rather than truncating like SQLite.
+-- name: GetAbvDistribution :many
+SELECT
+    CAST(FLOOR(beers.abv) AS BIGINT) AS abv,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetTopStyles :many
+SELECT
+    beers.style AS name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+    AND beers.style IS NOT NULL
+GROUP BY beers.style
+ORDER BY drinks DESC, beers.style
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- name: GetTopBrewers :many
+SELECT
+    brewers.name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+JOIN brewers ON brewers.id = beers.brewer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY brewers.id, brewers.name
+ORDER BY drinks DESC, brewers.name
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The different beers drunk, counted by the whole number of their rating, e.g. 7.5 is counted
+-- under 7
+-- name: GetRatingHistogram :many
+SELECT
+    CAST(FLOOR(beers.rating) AS BIGINT) AS rating,
+    COUNT(DISTINCT beers.id) AS beers
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+    AND beers.rating IS NOT NULL
+GROUP BY 1
+ORDER BY 1;
+
+/* === GOALS === */
+
+-- name: SetGoals :one
+INSERT INTO goals (user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET weekly_limit = excluded.weekly_limit,
+    alcohol_free_days = excluded.alcohol_free_days,
+    confirm_over_limit = excluded.confirm_over_limit,
+    time_zone = excluded.time_zone,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetGoals :one
+SELECT *
+FROM goals
+WHERE user_id = $1;
+
+-- name: DeleteGoals :one
+DELETE FROM goals
+WHERE user_id = $1
+RETURNING *;
+
+/* === SCHEDULES === */
+
+-- name: AddSchedule :one
+INSERT INTO schedules (user_id, weekday, minute, remind_before)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetSchedule :one
+SELECT *
+FROM schedules
+WHERE id = $1;
+
+-- name: GetUserSchedules :many
+SELECT *
+FROM schedules
+WHERE user_id = $1
+ORDER BY weekday, minute;
+
+-- The schedules of everyone who isn't deleted, with where they are for the scheduler
+-- name: GetAllSchedules :many
+SELECT sqlc.embed(schedules), users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM schedules
+JOIN users ON users.id = schedules.user_id
+LEFT JOIN goals ON goals.user_id = schedules.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY schedules.id;
+
+-- name: SetScheduleReminded :exec
+UPDATE schedules
+SET reminded_at = $1
+WHERE id = $2;
+
+-- name: DeleteSchedule :one
+DELETE FROM schedules
+WHERE id = $1
+RETURNING *;
+
+/* === EMAILS === */
+
+-- name: SetUserEmail :one
+INSERT INTO user_emails (user_id, email, weekly_summary, reminders)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET email = excluded.email,
+    weekly_summary = excluded.weekly_summary,
+    reminders = excluded.reminders,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetUserEmail :one
+SELECT *
+FROM user_emails
+WHERE user_id = $1;
+
+-- name: GetUserEmailByAddress :one
+SELECT *
+FROM user_emails
+WHERE email = $1;
+
+-- The addresses of everyone who isn't deleted, with where they are for the weekly summaries
+-- name: GetUserEmails :many
+SELECT sqlc.embed(user_emails), users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM user_emails
+JOIN users ON users.id = user_emails.user_id
+LEFT JOIN goals ON goals.user_id = user_emails.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY user_emails.user_id;
+
+-- name: SetSummarySent :exec
+UPDATE user_emails
+SET summary_sent_on = $1
+WHERE user_id = $2;
+
+-- name: DeleteUserEmail :one
+DELETE FROM user_emails
+WHERE user_id = $1
+RETURNING *;
+
+/* === PASSWORD RESETS === */
+
+-- name: AddPasswordReset :one
+INSERT INTO password_resets (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT *
+FROM password_resets
+WHERE token_hash = $1;
+
+-- Only the first use of a link counts, so it can't be used twice at once
+-- name: UsePasswordReset :execrows
+UPDATE password_resets
+SET used_at = $1
+WHERE token_hash = $2 AND used_at IS NULL;
+
+/* === OUTBOX === */
+
+-- name: QueueEmail :one
+INSERT INTO outbox (to_address, subject, text_body, html_body, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetDueEmails :many
+SELECT *
+FROM outbox
+WHERE next_attempt_at IS NOT NULL AND next_attempt_at <= sqlc.arg('now')
+ORDER BY next_attempt_at, id
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The latest emails first, for seeing what's been sent and what's stuck
+-- name: GetOutbox :many
+SELECT *
+FROM outbox
+ORDER BY id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- name: SetEmailSent :exec
+UPDATE outbox
+SET attempts = attempts + 1, sent_at = sqlc.arg('sent_at'), next_attempt_at = NULL, last_error = ''
+WHERE id = sqlc.arg('id');
+
+-- Records a failed attempt, and when to try again, or NULL to give up
+-- name: SetEmailFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = sqlc.arg('last_error'), next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id');
+
+/* === WEBHOOKS === */
+
+-- name: AddWebhook :one
+INSERT INTO webhooks (url, secret, events)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT *
+FROM webhooks
+WHERE id = $1;
+
+-- name: GetWebhooks :many
+SELECT *
+FROM webhooks
+ORDER BY id;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1
+RETURNING *;
+
+-- name: QueueDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- Deliveries to try now, with where to send them and the secret to sign them with
+-- name: GetDueDeliveries :many
+SELECT sqlc.embed(webhook_deliveries), webhooks.url, webhooks.secret
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.next_attempt_at IS NOT NULL AND webhook_deliveries.next_attempt_at <= sqlc.arg('now')
+ORDER BY webhook_deliveries.next_attempt_at, webhook_deliveries.id
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The latest deliveries first, for the delivery log
+-- name: GetDeliveries :many
+SELECT sqlc.embed(webhook_deliveries), webhooks.url
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+ORDER BY webhook_deliveries.id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- name: SetDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = sqlc.arg('last_status'), delivered_at = sqlc.arg('delivered_at'), next_attempt_at = NULL, last_error = ''
+WHERE id = sqlc.arg('id');
+
+-- Records a failed attempt, and when to try again, or NULL to give up
+-- name: SetDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = sqlc.arg('last_status'), last_error = sqlc.arg('last_error'), next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id');
+
+/* === DRINKING SESSIONS === */
+
+-- name: AddDrinkingSession :one
+INSERT INTO drinking_sessions (name, created_by, started_at, share_token)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetDrinkingSession :one
+SELECT * FROM drinking_sessions
+WHERE id = $1;
+
+-- name: GetDrinkingSessionByToken :one
+SELECT * FROM drinking_sessions
+WHERE share_token = $1;
+
+-- The sessions the user is in, latest first
+-- name: GetUserDrinkingSessions :many
+SELECT drinking_sessions.*
+FROM drinking_sessions
+JOIN drinking_session_participants ON drinking_session_participants.session_id = drinking_sessions.id
+WHERE drinking_session_participants.user_id = $1
+ORDER BY drinking_sessions.started_at DESC, drinking_sessions.id DESC;
+
+-- name: EndDrinkingSession :one
+UPDATE drinking_sessions
+SET ended_at = $1
+WHERE id = $2
+RETURNING *;
+
+-- Deletes the session along with its participants and venues. The drinks had in it are kept, just
+-- no longer in a session.
+-- name: DeleteDrinkingSession :one
+DELETE FROM drinking_sessions
+WHERE id = $1
+RETURNING *;
+
+-- name: AddSessionParticipant :exec
+INSERT INTO drinking_session_participants (session_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- The session's participants in the order they joined, leaving out deleted users
+-- name: GetSessionParticipants :many
+SELECT users.id AS user_id, users.username, drinking_session_participants.joined_at
+FROM drinking_session_participants
+JOIN users ON users.id = drinking_session_participants.user_id
+WHERE drinking_session_participants.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY drinking_session_participants.joined_at, users.id;
+
+-- name: AddSessionVenue :one
+INSERT INTO drinking_session_venues (session_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetSessionVenues :many
+SELECT * FROM drinking_session_venues
+WHERE session_id = $1
+ORDER BY id;
+
+-- name: AddSessionDrink :exec
+INSERT INTO drinking_session_drinks (drink_id, session_id)
+VALUES ($1, $2);
+
+-- The drinks had in the session in the order they were had, with who had them, leaving out
+-- deleted users like the participants do
+-- name: GetSessionDrinks :many
+SELECT sqlc.embed(drinks), beers.name AS beer_name, beers.abv, users.username
+FROM drinking_session_drinks
+JOIN drinks ON drinks.id = drinking_session_drinks.drink_id
+JOIN beers ON beers.id = drinks.beer_id
+JOIN users ON users.id = drinks.user_id
+WHERE drinking_session_drinks.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY drinks.drunk_at, drinks.id;
+
+/* === VENUES === */
+
+-- name: AddVenue :one
+INSERT INTO venues (name, address, type, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetVenue :one
+SELECT * FROM venues
+WHERE id = $1;
+
+-- name: GetVenues :many
+SELECT * FROM venues
+ORDER BY name, id;
+
+-- The venues with coordinates inside the box, for narrowing down which are near somewhere
+-- name: GetVenuesInBox :many
+SELECT * FROM venues
+WHERE latitude >= sqlc.arg('min_latitude')::double precision AND latitude <= sqlc.arg('max_latitude')::double precision
+AND longitude >= sqlc.arg('min_longitude')::double precision AND longitude <= sqlc.arg('max_longitude')::double precision;
+
+-- Deletes the venue along with the check-ins at it
+-- name: DeleteVenue :one
+DELETE FROM venues
+WHERE id = $1
+RETURNING *;
+
+-- name: AddCheckIn :one
+INSERT INTO check_ins (user_id, beer_id, venue_id, checked_in_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetCheckIn :one
+SELECT * FROM check_ins
+WHERE id = $1;
+
+-- name: DeleteCheckIn :one
+DELETE FROM check_ins
+WHERE id = $1
+RETURNING *;
+
+-- The user's check-ins with where they were and what they had, latest first
+-- name: GetUserCheckIns :many
+SELECT sqlc.embed(check_ins), beers.name AS beer_name, venues.name AS venue_name
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN venues ON venues.id = check_ins.venue_id
+WHERE check_ins.user_id = sqlc.arg('user_id')
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The check-ins at the venue with who had what, latest first, leaving out deleted users
+-- name: GetVenueCheckIns :many
+SELECT sqlc.embed(check_ins), beers.name AS beer_name, users.username
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN users ON users.id = check_ins.user_id
+WHERE check_ins.venue_id = sqlc.arg('venue_id') AND users.deleted_at IS NULL
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The beers had most at the venue, most first and then by name
+-- name: GetVenueTopBeers :many
+SELECT beers.id AS beer_id, beers.name, COUNT(*) AS check_ins
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+WHERE check_ins.venue_id = sqlc.arg('venue_id')
+GROUP BY beers.id, beers.name
+ORDER BY check_ins DESC, beers.name
+LIMIT sqlc.arg('max_results')::bigint;
+
+/* === ROUNDS === */
+
+-- name: AddRound :one
+INSERT INTO rounds (session_id, bought_by, cost, currency, bought_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: AddRoundRecipient :exec
+INSERT INTO round_recipients (round_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetRound :one
+SELECT * FROM rounds
+WHERE id = $1;
+
+-- The rounds bought in the session with who bought them, in the order they were bought, leaving
+-- out those bought by deleted users
+-- name: GetSessionRounds :many
+SELECT sqlc.embed(rounds), users.username
+FROM rounds
+JOIN users ON users.id = rounds.bought_by
+WHERE rounds.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY rounds.bought_at, rounds.id;
+
+-- Who each round in the session was bought for, leaving out deleted users
+-- name: GetSessionRoundRecipients :many
+SELECT round_recipients.round_id, round_recipients.user_id, users.username
+FROM round_recipients
+JOIN rounds ON rounds.id = round_recipients.round_id
+JOIN users ON users.id = round_recipients.user_id
+WHERE rounds.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY round_recipients.round_id, round_recipients.user_id;
+
+-- Deletes the round along with who it was bought for
+-- name: DeleteRound :one
+DELETE FROM rounds
+WHERE id = $1
+RETURNING *;
+
+/* === FOLLOWS === */
+
+-- name: Follow :exec
+INSERT INTO follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: Unfollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- Who the user follows, leaving out deleted users
+-- name: GetFollowing :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = $1 AND users.deleted_at IS NULL
+ORDER BY users.username;
+
+-- Who follows the user, leaving out deleted users
+-- name: GetFollowers :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = $1 AND users.deleted_at IS NULL
+ORDER BY users.username;
+
+-- name: SetPrivacy :one
+INSERT INTO user_privacy (user_id, visibility)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET visibility = excluded.visibility, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetPrivacy :one
+SELECT *
+FROM user_privacy
+WHERE user_id = $1;
+
+/* === ACTIVITIES === */
+
+-- name: AddActivity :one
+INSERT INTO activities (user_id, kind, beer_id, brewer_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- The activity of the users the viewer follows, newest first, from before the given id. Only
+-- activity the viewer may see is included: that of public users, and that of users who keep it to
+-- their friends when they and the viewer follow each other. Deleted users, beers and brewers are
+-- left out.
+-- name: GetFeed :many
+SELECT sqlc.embed(activities), users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+JOIN follows ON follows.followee_id = activities.user_id AND follows.follower_id = sqlc.arg('viewer_id')::bigint
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.id < sqlc.arg('before')::bigint
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = sqlc.arg('viewer_id')::bigint
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+-- The user's activity as the viewer may see it, newest first, from before the given id. Users
+-- always see their own activity; others see it as they would in their feed.
+-- name: GetUserActivities :many
+SELECT sqlc.embed(activities), users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.user_id = sqlc.arg('user_id')::bigint
+    AND activities.id < sqlc.arg('before')::bigint
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        activities.user_id = sqlc.arg('viewer_id')::bigint
+        OR user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = sqlc.arg('viewer_id')::bigint
+            )
+            AND EXISTS (
+                SELECT 1 FROM follows AS forth
+                WHERE forth.follower_id = sqlc.arg('viewer_id')::bigint AND forth.followee_id = activities.user_id
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT sqlc.arg('max_results')::bigint;
+
+/* === COMMENTS === */
+
+-- name: AddComment :one
+INSERT INTO comments (author_id, beer_id, brewer_id, parent_id, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM comments
+WHERE id = $1;
+
+-- The comments on the beer or brewer with who wrote them, oldest first, including deleted ones
+-- but leaving out those by deleted users. Only one of the ids is given.
+-- name: GetComments :many
+SELECT sqlc.embed(comments), users.username
+FROM comments
+JOIN users ON users.id = comments.author_id
+WHERE (comments.beer_id = sqlc.narg('beer_id') OR comments.brewer_id = sqlc.narg('brewer_id'))
+    AND users.deleted_at IS NULL
+ORDER BY comments.id;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET body = $1, edited_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- Blanks the comment, keeping it for the replies to it
+-- name: DeleteComment :one
+UPDATE comments
+SET body = '', deleted_at = CURRENT_TIMESTAMP, moderated = $1
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: AddReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, emoji)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteReaction :execrows
+DELETE FROM comment_reactions
+WHERE comment_id = $1 AND user_id = $2 AND emoji = $3;
+
+-- The reactions to the comments on the beer or brewer, leaving out those of deleted users. Only
+-- one of the ids is given.
+-- name: GetReactions :many
+SELECT comment_reactions.comment_id, comment_reactions.user_id, comment_reactions.emoji
+FROM comment_reactions
+JOIN comments ON comments.id = comment_reactions.comment_id
+JOIN users ON users.id = comment_reactions.user_id
+WHERE (comments.beer_id = sqlc.narg('beer_id') OR comments.brewer_id = sqlc.narg('brewer_id'))
+    AND users.deleted_at IS NULL
+ORDER BY comment_reactions.comment_id, comment_reactions.created_at;
+
+/* === TASTINGS === */
+
+-- name: AddTasting :one
+INSERT INTO tastings (name, organiser_id, held_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTasting :one
+SELECT *
+FROM tastings
+WHERE id = $1;
+
+-- name: GetTastings :many
+SELECT sqlc.embed(tastings), users.username
+FROM tastings
+LEFT JOIN users ON users.id = tastings.organiser_id
+ORDER BY tastings.held_at DESC, tastings.id DESC;
+
+-- name: DeleteTasting :one
+DELETE FROM tastings
+WHERE id = $1
+RETURNING *;
+
+-- name: RevealTasting :one
+UPDATE tastings
+SET revealed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revealed_at IS NULL
+RETURNING *;
+
+-- name: AddPour :one
+INSERT INTO tasting_pours (tasting_id, number, beer_id)
+SELECT sqlc.arg('tasting_id')::bigint, COALESCE(MAX(number), 0) + 1, sqlc.arg('beer_id')::bigint
+FROM tasting_pours
+WHERE tasting_id = sqlc.arg('tasting_id')
+RETURNING *;
+
+-- name: DeletePour :execrows
+DELETE FROM tasting_pours
+WHERE tasting_id = $1 AND number = $2;
+
+-- name: GetPours :many
+SELECT tasting_pours.*, beers.name AS beer_name, brewers.name AS brewer_name
+FROM tasting_pours
+JOIN beers ON beers.id = tasting_pours.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE tasting_pours.tasting_id = $1
+ORDER BY tasting_pours.number;
+
+-- name: SaveTastingScorecard :one
+INSERT INTO tasting_scorecards (tasting_id, number, user_id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (tasting_id, number, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetTastingScorecards :many
+SELECT sqlc.embed(tasting_scorecards), users.username
+FROM tasting_scorecards
+JOIN users ON users.id = tasting_scorecards.user_id
+WHERE tasting_scorecards.tasting_id = $1 AND users.deleted_at IS NULL
+ORDER BY tasting_scorecards.number, users.username;
+
+/* === BADGES === */
+
+-- name: GetAddedBeerFacts :many
+SELECT beer_revisions.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, beer_revisions.created_at AS at
+FROM beer_revisions
+JOIN users ON users.id = beer_revisions.user_id
+JOIN beers ON beers.id = beer_revisions.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beer_revisions.id IN (SELECT MIN(first.id) FROM beer_revisions AS first GROUP BY first.beer_id)
+    AND beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (beer_revisions.user_id = sqlc.narg('user_id') OR sqlc.narg('user_id') IS NULL)
+    AND beer_revisions.created_at >= sqlc.arg('since') AND beer_revisions.created_at < sqlc.arg('until')
+ORDER BY beer_revisions.created_at, beers.id;
+
+-- name: GetRatedBeerFacts :many
+SELECT scorecards.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, scorecards.created_at AS at
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+JOIN beers ON beers.id = scorecards.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (scorecards.user_id = sqlc.narg('user_id') OR sqlc.narg('user_id') IS NULL)
+    AND scorecards.created_at >= sqlc.arg('since') AND scorecards.created_at < sqlc.arg('until')
+ORDER BY scorecards.created_at, beers.id;
+
+/* === WISHLIST === */
+
+-- name: AddWish :one
+INSERT INTO wishlist (user_id, beer_id, priority, note)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateWish :one
+UPDATE wishlist
+SET priority = $1, note = $2
+WHERE user_id = $3 AND beer_id = $4
+RETURNING *;
+
+-- name: DeleteWish :execrows
+DELETE FROM wishlist
+WHERE user_id = $1 AND beer_id = $2;
+
+-- name: GetWishlist :many
+SELECT sqlc.embed(wishlist), sqlc.embed(beers), brewers.name AS brewer_name
+FROM wishlist
+JOIN beers ON beers.id = wishlist.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE wishlist.user_id = $1 AND beers.deleted_at IS NULL
+ORDER BY wishlist.priority IS NULL, wishlist.priority, wishlist.created_at, beers.id;
@@ -0,0 +1,482 @@
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    last_login TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS brewers (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    location TEXT,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS beers (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    brewer_id BIGINT,
+    style TEXT,
+    abv DOUBLE PRECISION NOT NULL,
+    rating DOUBLE PRECISION,
+    notes TEXT,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE SET NULL,
+    CONSTRAINT unique_brewer_beer UNIQUE (name, brewer_id)
+);
+CREATE TABLE IF NOT EXISTS beer_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    beer_id BIGINT NOT NULL,
+    user_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name TEXT NOT NULL,
+    brewer_id BIGINT,
+    style TEXT,
+    abv DOUBLE PRECISION NOT NULL,
+    rating DOUBLE PRECISION,
+    notes TEXT,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS styles (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    category TEXT NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    abv_min DOUBLE PRECISION NOT NULL,
+    abv_max DOUBLE PRECISION NOT NULL,
+    ibu_min DOUBLE PRECISION NOT NULL,
+    ibu_max DOUBLE PRECISION NOT NULL,
+    srm_min DOUBLE PRECISION NOT NULL,
+    srm_max DOUBLE PRECISION NOT NULL,
+    description TEXT NOT NULL
+);
+
+-- The household's weighting of each part of a scorecard, a single row
+CREATE TABLE IF NOT EXISTS scorecard_weights (
+    id BIGINT PRIMARY KEY CHECK (id = 1),
+    aroma DOUBLE PRECISION NOT NULL,
+    appearance DOUBLE PRECISION NOT NULL,
+    flavour DOUBLE PRECISION NOT NULL,
+    mouthfeel DOUBLE PRECISION NOT NULL,
+    overall DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS scorecards (
+    id BIGSERIAL PRIMARY KEY,
+    beer_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    aroma DOUBLE PRECISION NOT NULL,
+    appearance DOUBLE PRECISION NOT NULL,
+    flavour DOUBLE PRECISION NOT NULL,
+    mouthfeel DOUBLE PRECISION NOT NULL,
+    overall DOUBLE PRECISION NOT NULL,
+    total DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_beer_user_scorecard UNIQUE (beer_id, user_id)
+);
+
+-- Flavour tags, either from the curated vocabulary, which have a flavour wheel category, or made
+-- up by users. Names are stored lowercase.
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    category TEXT
+);
+
+CREATE TABLE IF NOT EXISTS beer_tags (
+    beer_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    PRIMARY KEY (beer_id, tag_id),
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- Photos of beer labels. The images themselves are kept in blob storage under the keys.
+CREATE TABLE IF NOT EXISTS label_photos (
+    id BIGSERIAL PRIMARY KEY,
+    beer_id BIGINT NOT NULL,
+    user_id BIGINT,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    width BIGINT NOT NULL,
+    height BIGINT NOT NULL,
+    blob_key TEXT NOT NULL,
+    thumb_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- The EAN-8, EAN-13 and UPC-A codes printed on beers' cans and bottles. A beer can have several,
+-- e.g. one for a single can and one for a slab.
+CREATE TABLE IF NOT EXISTS barcodes (
+    code TEXT PRIMARY KEY,
+    beer_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+
+-- What's in the fridge. Each purchase of a beer is an entry, which is taken from one at a time.
+-- Empty entries are kept as the record of what was spent. The price is of each container, in the
+-- currency's ISO 4217 code.
+CREATE TABLE IF NOT EXISTS stock (
+    id BIGSERIAL PRIMARY KEY,
+    beer_id BIGINT NOT NULL,
+    user_id BIGINT,
+    quantity BIGINT NOT NULL,
+    bought BIGINT NOT NULL,
+    container_ml BIGINT NOT NULL,
+    purchased_on DATE NOT NULL,
+    best_before DATE,
+    price DOUBLE PRECISION,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- How much each user wants to spend on beer a month
+CREATE TABLE IF NOT EXISTS budgets (
+    user_id BIGINT PRIMARY KEY,
+    amount DOUBLE PRECISION NOT NULL,
+    currency TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Each drink a user has had. drunk_on is the day it was had where they were, which is what the
+-- stats count by, so a drink late on a Friday night is on Friday wherever the server is.
+CREATE TABLE IF NOT EXISTS drinks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    beer_id BIGINT NOT NULL,
+    serving_ml BIGINT NOT NULL,
+    drunk_at TIMESTAMPTZ NOT NULL,
+    drunk_on DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinks_user_drunk_on ON drinks (user_id, drunk_on);
+
+-- Each user's weekly drinking goals, where a goal that's NULL isn't set. The limit is in standard
+-- drinks. The time zone is the IANA name of where the user is, which their days and weeks are
+-- counted in, or empty for wherever the server is.
+CREATE TABLE IF NOT EXISTS goals (
+    user_id BIGINT PRIMARY KEY,
+    weekly_limit DOUBLE PRECISION,
+    alcohol_free_days BIGINT,
+    confirm_over_limit BOOLEAN NOT NULL DEFAULT FALSE,
+    time_zone TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- The times each week a user calls beer o'clock, such as Friday at 17:00 where they are. The
+-- weekday counts from Sunday as 0, the minute from midnight, and a reminder goes out remind_before
+-- minutes ahead. reminded_at is when the last reminder went out, so each is only sent once.
+CREATE TABLE IF NOT EXISTS schedules (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    weekday BIGINT NOT NULL,
+    minute BIGINT NOT NULL,
+    remind_before BIGINT NOT NULL DEFAULT 0,
+    reminded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_user_schedule UNIQUE (user_id, weekday, minute)
+);
+
+-- Where to email each user, and which emails they want. summary_sent_on is the Monday starting the
+-- last week a summary went out for, so each week's is only sent once.
+CREATE TABLE IF NOT EXISTS user_emails (
+    user_id BIGINT PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    weekly_summary BOOLEAN NOT NULL DEFAULT TRUE,
+    reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    summary_sent_on DATE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Links for resetting forgotten passwords. Only a hash of each link's token is kept, so the links
+-- can't be rebuilt from the database.
+CREATE TABLE IF NOT EXISTS password_resets (
+    token_hash TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Emails waiting to be sent, and the ones which have been. Failed sends are tried again at
+-- next_attempt_at, which is NULL once the email's been sent or given up on.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    to_address TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS outbox_next_attempt_at ON outbox (next_attempt_at);
+
+-- Where to send events such as beer.created, for hooking the app up to other systems. events is a
+-- comma separated list of the event types the webhook wants, and the secret signs each payload.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Each event sent, or waiting to be sent, to a webhook. Like the outbox, failed deliveries are
+-- tried again at next_attempt_at, which is NULL once delivered or given up on. last_status is the
+-- HTTP status of the last attempt, or NULL if there wasn't a response.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_status BIGINT,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+-- Drinks grouped into a named session, e.g. a night at the pub. A session is still going while
+-- ended_at is NULL. Anyone with the share token can see a read-only summary of it.
+CREATE TABLE IF NOT EXISTS drinking_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_by BIGINT,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    share_token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS drinking_session_participants (
+    session_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, user_id),
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinking_session_participants_user_id ON drinking_session_participants (user_id);
+
+-- The places a session went, in the order they were added
+CREATE TABLE IF NOT EXISTS drinking_session_venues (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE
+);
+
+-- Which session each drink was had in, if any. A drink is only ever in one session.
+CREATE TABLE IF NOT EXISTS drinking_session_drinks (
+    drink_id BIGINT PRIMARY KEY,
+    session_id BIGINT NOT NULL,
+    FOREIGN KEY (drink_id) REFERENCES drinks(id) ON DELETE CASCADE,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinking_session_drinks_session_id ON drinking_session_drinks (session_id);
+
+-- Places beers are drunk. type is bar, bottle-shop or home. The coordinates are in degrees, and
+-- either both set or both NULL.
+CREATE TABLE IF NOT EXISTS venues (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS venues_latitude_longitude ON venues (latitude, longitude);
+
+-- Where each user had a beer, and when
+CREATE TABLE IF NOT EXISTS check_ins (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    beer_id BIGINT NOT NULL,
+    venue_id BIGINT NOT NULL,
+    checked_in_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS check_ins_user_id ON check_ins (user_id);
+CREATE INDEX IF NOT EXISTS check_ins_venue_id ON check_ins (venue_id);
+
+-- The rounds bought in a drinking session. Whoever bought one shouted the drinks of everyone in
+-- round_recipients, usually including themselves. The cost is of the whole round if anyone kept
+-- track, in the currency's ISO 4217 code.
+CREATE TABLE IF NOT EXISTS rounds (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL,
+    bought_by BIGINT NOT NULL,
+    cost DOUBLE PRECISION,
+    currency TEXT NOT NULL,
+    bought_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (bought_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS rounds_session_id ON rounds (session_id);
+
+CREATE TABLE IF NOT EXISTS round_recipients (
+    round_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (round_id, user_id),
+    FOREIGN KEY (round_id) REFERENCES rounds(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Who follows whom. Following is one way; two users who follow each other are friends.
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id BIGINT NOT NULL,
+    followee_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id),
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS follows_followee_id ON follows (followee_id);
+
+-- Who can see each user's activity: public, friends or private. Users without a row are public.
+CREATE TABLE IF NOT EXISTS user_privacy (
+    user_id BIGINT PRIMARY KEY,
+    visibility TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- What each user did, for the feed: kind is beer-added, beer-edited or brewer-added, with the
+-- beer or brewer it was done to
+CREATE TABLE IF NOT EXISTS activities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    beer_id BIGINT,
+    brewer_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS activities_user_id ON activities (user_id);
+
+-- What users have said about beers and brewers. Each comment is on exactly one of them, and
+-- replies point at the comment they're replying to. Deleting a comment blanks it but keeps it,
+-- so the replies to it still have somewhere to hang; moderated is set when an admin deleted it
+-- rather than its author.
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGSERIAL PRIMARY KEY,
+    author_id BIGINT NOT NULL,
+    beer_id BIGINT,
+    brewer_id BIGINT,
+    parent_id BIGINT,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    moderated BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK ((beer_id IS NULL) <> (brewer_id IS NULL)),
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS comments_beer_id ON comments (beer_id);
+CREATE INDEX IF NOT EXISTS comments_brewer_id ON comments (brewer_id);
+
+-- Who reacted to which comment with which emoji. Each user can react with each emoji once.
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id, emoji),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Blind tastings, where beers are poured as numbered glasses and scored without knowing which is
+-- which. The organiser reveals them when everyone's done, after which nothing can change.
+CREATE TABLE IF NOT EXISTS tastings (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    organiser_id BIGINT,
+    held_at TIMESTAMPTZ NOT NULL,
+    revealed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organiser_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- The beer in each numbered pour, numbered from 1 in each tasting
+CREATE TABLE IF NOT EXISTS tasting_pours (
+    tasting_id BIGINT NOT NULL,
+    number BIGINT NOT NULL,
+    beer_id BIGINT NOT NULL,
+    PRIMARY KEY (tasting_id, number),
+    FOREIGN KEY (tasting_id) REFERENCES tastings(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    CONSTRAINT unique_tasting_beer UNIQUE (tasting_id, beer_id)
+);
+
+-- Each participant's scores for a pour, out of 10 like scorecards. They're copied into the beer's
+-- scorecards at the reveal.
+CREATE TABLE IF NOT EXISTS tasting_scorecards (
+    tasting_id BIGINT NOT NULL,
+    number BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    aroma DOUBLE PRECISION NOT NULL,
+    appearance DOUBLE PRECISION NOT NULL,
+    flavour DOUBLE PRECISION NOT NULL,
+    mouthfeel DOUBLE PRECISION NOT NULL,
+    overall DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tasting_id, number, user_id),
+    FOREIGN KEY (tasting_id, number) REFERENCES tasting_pours(tasting_id, number) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS tasting_scorecards_user_id ON tasting_scorecards (user_id);
+
+-- The beers each user wants to try, with how much they want to from 1 (most) to 3 (least). A beer
+-- comes off the list when the user rates it.
+CREATE TABLE IF NOT EXISTS wishlist (
+    user_id BIGINT NOT NULL,
+    beer_id BIGINT NOT NULL,
+    priority BIGINT,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, beer_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS wishlist_beer_id ON wishlist (beer_id);
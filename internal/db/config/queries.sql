@@ -1,38 +1,67 @@
 /* === CONTACTS === */
 
 -- name: AddUser :one
-INSERT INTO users (username, password_hash) 
-VALUES (?, ?)
+INSERT INTO users (username, password_hash, is_admin)
+VALUES (?, ?, ?)
 RETURNING *;
 
 -- name: GetUserById :one
 SELECT * 
 FROM users
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- name: GetUserByUsername :one
 SELECT *
 FROM users
-WHERE username = ?;
+WHERE username = ? AND deleted_at IS NULL;
 
 -- name: GetUsers :many
 SELECT *
-FROM users;
+FROM users
+WHERE deleted_at IS NULL;
 
 -- name: DeleteUser :one
+UPDATE users
+SET deleted_at = datetime()
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedUsers :many
+SELECT *
+FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeUser :one
 DELETE FROM users
-WHERE id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
 RETURNING *;
 
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
 -- name: CountUsers :one
 SELECT COUNT(*)
-FROM users;
+FROM users
+WHERE deleted_at IS NULL;
 
 -- name: SetUserLastLogin :exec
 UPDATE users
 SET last_login = datetime()
 WHERE id = ?;
 
+-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = ?
+WHERE id = ?;
+
 /* === BREWERS === */
 
 -- name: AddBrewer :one
@@ -43,20 +72,44 @@ RETURNING *;
 -- name: GetBrewerById :one
 SELECT *
 FROM brewers
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- name: GetBrewers :many
 SELECT *
-FROM brewers;
+FROM brewers
+WHERE deleted_at IS NULL;
 
 -- name: DeleteBrewer :one
+UPDATE brewers
+SET deleted_at = datetime()
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreBrewer :one
+UPDATE brewers
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedBrewers :many
+SELECT *
+FROM brewers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeBrewer :one
 DELETE FROM brewers
-WHERE id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
 RETURNING *;
 
+-- name: PurgeDeletedBrewers :execrows
+DELETE FROM brewers
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
 -- name: CountBrewers :one
 SELECT COUNT(*)
-FROM brewers;
+FROM brewers
+WHERE deleted_at IS NULL;
 
 /* === BEERS === */
 
@@ -68,20 +121,44 @@ RETURNING *;
 -- name: GetBeerById :one
 SELECT *
 FROM beers
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- name: GetBeers :many
 SELECT *
-FROM beers;
+FROM beers
+WHERE deleted_at IS NULL;
 
 -- name: DeleteBeer :one
+UPDATE beers
+SET deleted_at = datetime()
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreBeer :one
+UPDATE beers
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedBeers :many
+SELECT *
+FROM beers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeBeer :one
 DELETE FROM beers
-WHERE id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
 RETURNING *;
 
+-- name: PurgeDeletedBeers :execrows
+DELETE FROM beers
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('deleted_before');
+
 -- name: CountBeers :one
 SELECT COUNT(*)
-FROM beers;
+FROM beers
+WHERE deleted_at IS NULL;
 
 -- name: UpdateBeer :one
 UPDATE beers
@@ -92,10 +169,1156 @@ SET
     abv = coalesce(sqlc.narg('abv'), abv),
     rating = coalesce(sqlc.narg('rating'), rating),
     notes = coalesce(sqlc.narg('notes'), notes)
-WHERE id = sqlc.arg('id')
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RevertBeer :one
+UPDATE beers
+SET name = ?, brewer_id = ?, style = ?, abv = ?, rating = ?, notes = ?
+WHERE id = ? AND deleted_at IS NULL
 RETURNING *;
 
 -- name: SearchBeers :many
 SELECT *
 FROM beers
-WHERE name LIKE '%' || sqlc.arg('query') || '%' OR style LIKE '%' || sqlc.arg('query') || '%' OR notes LIKE '%' || sqlc.arg('query') || '%';
\ No newline at end of file
+WHERE (name LIKE '%' || sqlc.arg('query') || '%' OR style LIKE '%' || sqlc.arg('query') || '%' OR notes LIKE '%' || sqlc.arg('query') || '%')
+    AND deleted_at IS NULL;
+
+-- name: GetBeerStyles :many
+SELECT DISTINCT style
+FROM beers
+WHERE style IS NOT NULL AND style != ''
+ORDER BY style;
+
+-- name: RenameBeerStyle :many
+UPDATE beers
+SET style = sqlc.arg('new_style')
+WHERE style = sqlc.arg('old_style')
+RETURNING *;
+
+-- name: SetBeerRating :exec
+UPDATE beers
+SET rating = ?
+WHERE id = ?;
+
+/* === BEER REVISIONS === */
+
+-- name: AddBeerRevision :one
+INSERT INTO beer_revisions (beer_id, user_id, name, brewer_id, style, abv, rating, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetBeerRevision :one
+SELECT *
+FROM beer_revisions
+WHERE id = ? AND beer_id = ?;
+
+-- name: GetBeerRevisions :many
+SELECT sqlc.embed(beer_revisions), users.username AS author, brewers.name AS brewer_name
+FROM beer_revisions
+LEFT JOIN users ON users.id = beer_revisions.user_id
+LEFT JOIN brewers ON brewers.id = beer_revisions.brewer_id
+WHERE beer_revisions.beer_id = ?
+ORDER BY beer_revisions.id;
+
+/* === STYLES === */
+
+-- name: AddStyle :one
+INSERT INTO styles (code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetStyleById :one
+SELECT *
+FROM styles
+WHERE id = ?;
+
+-- name: GetStyleByName :one
+SELECT *
+FROM styles
+WHERE lower(name) = lower(sqlc.arg('name'));
+
+-- name: GetStyles :many
+SELECT *
+FROM styles
+ORDER BY id;
+
+-- name: SearchStyles :many
+SELECT *
+FROM styles
+WHERE name LIKE '%' || sqlc.arg('query') || '%' OR category LIKE '%' || sqlc.arg('query') || '%' OR lower(code) = lower(sqlc.arg('query'))
+ORDER BY id
+LIMIT sqlc.arg('max_results');
+
+/* === SCORECARDS === */
+
+-- name: GetScorecardWeights :one
+SELECT *
+FROM scorecard_weights
+WHERE id = 1;
+
+-- name: SetScorecardWeights :one
+INSERT INTO scorecard_weights (id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES (1, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall
+RETURNING *;
+
+-- name: SaveScorecard :one
+INSERT INTO scorecards (beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (beer_id, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    total = excluded.total,
+    updated_at = datetime()
+RETURNING *;
+
+-- name: GetScorecard :one
+SELECT *
+FROM scorecards
+WHERE beer_id = ? AND user_id = ?;
+
+-- name: GetBeerScorecards :many
+SELECT sqlc.embed(scorecards), users.username
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+WHERE scorecards.beer_id = ?
+ORDER BY scorecards.total DESC, scorecards.id;
+
+-- name: GetBeerScoreAverages :one
+SELECT
+    COUNT(*) AS count,
+    CAST(COALESCE(AVG(aroma), 0) AS REAL) AS aroma,
+    CAST(COALESCE(AVG(appearance), 0) AS REAL) AS appearance,
+    CAST(COALESCE(AVG(flavour), 0) AS REAL) AS flavour,
+    CAST(COALESCE(AVG(mouthfeel), 0) AS REAL) AS mouthfeel,
+    CAST(COALESCE(AVG(overall), 0) AS REAL) AS overall,
+    CAST(COALESCE(AVG(total), 0) AS REAL) AS total
+FROM scorecards
+WHERE beer_id = ?;
+
+-- name: GetScorecards :many
+SELECT *
+FROM scorecards
+ORDER BY id;
+
+-- name: SetScorecardTotal :exec
+UPDATE scorecards
+SET total = ?
+WHERE id = ?;
+
+/* === TAGS === */
+
+-- name: AddTag :one
+INSERT INTO tags (name, category)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetTagByName :one
+SELECT *
+FROM tags
+WHERE name = ?;
+
+-- name: GetTags :many
+SELECT *
+FROM tags
+ORDER BY name;
+
+-- name: GetTagCounts :many
+SELECT sqlc.embed(tags), COUNT(beers.id) AS beer_count
+FROM tags
+LEFT JOIN beer_tags ON beer_tags.tag_id = tags.id
+LEFT JOIN beers ON beers.id = beer_tags.beer_id AND beers.deleted_at IS NULL
+GROUP BY tags.id
+ORDER BY tags.name;
+
+-- name: GetBeerTags :many
+SELECT tags.*
+FROM tags
+JOIN beer_tags ON beer_tags.tag_id = tags.id
+WHERE beer_tags.beer_id = ?
+ORDER BY tags.name;
+
+-- name: GetAllBeerTags :many
+SELECT beer_tags.beer_id, sqlc.embed(tags)
+FROM beer_tags
+JOIN tags ON tags.id = beer_tags.tag_id
+ORDER BY beer_tags.beer_id, tags.name;
+
+-- name: AddBeerTag :exec
+INSERT INTO beer_tags (beer_id, tag_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: ClearBeerTags :exec
+DELETE FROM beer_tags
+WHERE beer_id = ?;
+
+-- name: GetBeersByTag :many
+SELECT beers.*
+FROM beers
+JOIN beer_tags ON beer_tags.beer_id = beers.id
+JOIN tags ON tags.id = beer_tags.tag_id
+WHERE tags.name = ? AND beers.deleted_at IS NULL
+ORDER BY beers.id;
+
+/* === LABEL PHOTOS === */
+
+-- name: AddLabelPhoto :one
+INSERT INTO label_photos (beer_id, user_id, content_type, size, width, height, blob_key, thumb_key)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetLabelPhoto :one
+SELECT *
+FROM label_photos
+WHERE id = ?;
+
+-- name: GetBeerLabelPhotos :many
+SELECT *
+FROM label_photos
+WHERE beer_id = ?
+ORDER BY id;
+
+-- name: DeleteLabelPhoto :one
+DELETE FROM label_photos
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteBeerLabelPhotos :many
+DELETE FROM label_photos
+WHERE beer_id = ?
+RETURNING *;
+
+/* === BARCODES === */
+
+-- name: AddBarcode :one
+-- Codes of beers in the trash are taken over, but not those of other beers
+INSERT INTO barcodes (code, beer_id)
+VALUES (?, ?)
+ON CONFLICT (code) DO UPDATE
+SET beer_id = excluded.beer_id, created_at = CURRENT_TIMESTAMP
+WHERE barcodes.beer_id IN (SELECT id FROM beers WHERE deleted_at IS NOT NULL)
+RETURNING *;
+
+-- name: GetBarcode :one
+SELECT barcodes.*
+FROM barcodes
+JOIN beers ON beers.id = barcodes.beer_id
+WHERE barcodes.code = ? AND beers.deleted_at IS NULL;
+
+-- name: GetBeerBarcodes :many
+SELECT *
+FROM barcodes
+WHERE beer_id = ?
+ORDER BY code;
+
+-- name: DeleteBarcode :one
+DELETE FROM barcodes
+WHERE code = ?
+RETURNING *;
+
+/* === STOCK === */
+
+-- name: AddStock :one
+INSERT INTO stock (beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetStock :one
+SELECT *
+FROM stock
+WHERE id = ?;
+
+-- name: GetFridge :many
+-- Soonest best-before first, with the entries which don't have one last
+SELECT sqlc.embed(stock), beers.name AS beer_name
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+ORDER BY stock.best_before IS NULL, stock.best_before, stock.purchased_on, stock.id;
+
+-- name: GetStockLevels :many
+SELECT stock.beer_id, CAST(SUM(stock.quantity) AS BIGINT) AS quantity
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+GROUP BY stock.beer_id
+ORDER BY stock.beer_id;
+
+-- name: TakeStock :one
+-- Takes from the entry which goes off first
+UPDATE stock
+SET quantity = quantity - 1
+WHERE id = (
+    SELECT id
+    FROM stock AS s
+    WHERE s.beer_id = ? AND s.quantity > 0
+    ORDER BY s.best_before IS NULL, s.best_before, s.purchased_on, s.id
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: DeleteStock :one
+DELETE FROM stock
+WHERE id = ?
+RETURNING *;
+
+/* === SPENDING === */
+
+-- Beers in the trash still count, since the money's been spent
+
+-- name: GetSpendingByUser :many
+SELECT
+    CAST(substr(stock.purchased_on, 1, 7) AS TEXT) AS month,
+    stock.currency,
+    COALESCE(users.username, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS REAL) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS REAL) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= ?
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetSpendingByBrewer :many
+SELECT
+    CAST(substr(stock.purchased_on, 1, 7) AS TEXT) AS month,
+    stock.currency,
+    COALESCE(brewers.name, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS REAL) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS REAL) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= ?
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetSpendingByStyle :many
+SELECT
+    CAST(substr(stock.purchased_on, 1, 7) AS TEXT) AS month,
+    stock.currency,
+    COALESCE(beers.style, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS REAL) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS REAL) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= ?
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3;
+
+-- name: GetUserSpending :many
+SELECT stock.currency, CAST(SUM(stock.bought * stock.price) AS REAL) AS total
+FROM stock
+WHERE stock.user_id = ? AND stock.price IS NOT NULL AND stock.purchased_on >= ?
+GROUP BY stock.currency
+ORDER BY stock.currency;
+
+-- name: GetPurchases :many
+SELECT
+    sqlc.embed(stock),
+    beers.name AS beer_name,
+    beers.abv,
+    beers.style,
+    brewers.name AS brewer_name,
+    users.username
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.purchased_on >= ?
+ORDER BY stock.purchased_on, stock.id;
+
+/* === BUDGETS === */
+
+-- name: SetBudget :one
+INSERT INTO budgets (user_id, amount, currency)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET amount = excluded.amount, currency = excluded.currency, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetBudget :one
+SELECT *
+FROM budgets
+WHERE user_id = ?;
+
+-- name: DeleteBudget :one
+DELETE FROM budgets
+WHERE user_id = ?
+RETURNING *;
+
+/* === DRINKS === */
+
+-- name: AddDrink :one
+INSERT INTO drinks (user_id, beer_id, serving_ml, drunk_at, drunk_on)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetDrink :one
+SELECT *
+FROM drinks
+WHERE id = ?;
+
+-- The user's drinks on the days from since up to but not including until, latest first. Beers
+-- in the trash are still included, since they were still drunk.
+-- name: GetUserDrinks :many
+SELECT sqlc.embed(drinks), beers.name AS beer_name, beers.abv
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+ORDER BY drinks.drunk_at DESC, drinks.id DESC;
+
+-- name: DeleteDrink :one
+DELETE FROM drinks
+WHERE id = ?
+RETURNING *;
+
+/* === STATS === */
+
+-- name: GetDrinksPerDay :many
+SELECT
+    drinks.drunk_on,
+    COUNT(*) AS drinks,
+    CAST(SUM(drinks.serving_ml * beers.abv / 100.0) AS REAL) AS alcohol_ml
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY drinks.drunk_on
+ORDER BY drinks.drunk_on;
+
+-- Drinks counted by the whole number of their ABV, e.g. 4.8% is counted under 4
+-- name: GetAbvDistribution :many
+SELECT
+    CAST(beers.abv AS INTEGER) AS abv,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetTopStyles :many
+SELECT
+    beers.style AS name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+    AND beers.style IS NOT NULL
+GROUP BY beers.style
+ORDER BY drinks DESC, beers.style
+LIMIT sqlc.arg('max_results');
+
+-- name: GetTopBrewers :many
+SELECT
+    brewers.name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+JOIN brewers ON brewers.id = beers.brewer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+GROUP BY brewers.id, brewers.name
+ORDER BY drinks DESC, brewers.name
+LIMIT sqlc.arg('max_results');
+
+-- The different beers drunk, counted by the whole number of their rating, e.g. 7.5 is counted
+-- under 7
+-- name: GetRatingHistogram :many
+SELECT
+    CAST(beers.rating AS INTEGER) AS rating,
+    COUNT(DISTINCT beers.id) AS beers
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = sqlc.arg('user_id') AND drinks.drunk_on >= sqlc.arg('since') AND drinks.drunk_on < sqlc.arg('until')
+    AND beers.rating IS NOT NULL
+GROUP BY 1
+ORDER BY 1;
+
+/* === GOALS === */
+
+-- name: SetGoals :one
+INSERT INTO goals (user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET weekly_limit = excluded.weekly_limit,
+    alcohol_free_days = excluded.alcohol_free_days,
+    confirm_over_limit = excluded.confirm_over_limit,
+    time_zone = excluded.time_zone,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetGoals :one
+SELECT *
+FROM goals
+WHERE user_id = ?;
+
+-- name: DeleteGoals :one
+DELETE FROM goals
+WHERE user_id = ?
+RETURNING *;
+
+/* === SCHEDULES === */
+
+-- name: AddSchedule :one
+INSERT INTO schedules (user_id, weekday, minute, remind_before)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetSchedule :one
+SELECT *
+FROM schedules
+WHERE id = ?;
+
+-- name: GetUserSchedules :many
+SELECT *
+FROM schedules
+WHERE user_id = ?
+ORDER BY weekday, minute;
+
+-- The schedules of everyone who isn't deleted, with where they are for the scheduler
+-- name: GetAllSchedules :many
+SELECT sqlc.embed(schedules), users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM schedules
+JOIN users ON users.id = schedules.user_id
+LEFT JOIN goals ON goals.user_id = schedules.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY schedules.id;
+
+-- name: SetScheduleReminded :exec
+UPDATE schedules
+SET reminded_at = ?
+WHERE id = ?;
+
+-- name: DeleteSchedule :one
+DELETE FROM schedules
+WHERE id = ?
+RETURNING *;
+
+/* === EMAILS === */
+
+-- name: SetUserEmail :one
+INSERT INTO user_emails (user_id, email, weekly_summary, reminders)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET email = excluded.email,
+    weekly_summary = excluded.weekly_summary,
+    reminders = excluded.reminders,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetUserEmail :one
+SELECT *
+FROM user_emails
+WHERE user_id = ?;
+
+-- name: GetUserEmailByAddress :one
+SELECT *
+FROM user_emails
+WHERE email = ?;
+
+-- The addresses of everyone who isn't deleted, with where they are for the weekly summaries
+-- name: GetUserEmails :many
+SELECT sqlc.embed(user_emails), users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM user_emails
+JOIN users ON users.id = user_emails.user_id
+LEFT JOIN goals ON goals.user_id = user_emails.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY user_emails.user_id;
+
+-- name: SetSummarySent :exec
+UPDATE user_emails
+SET summary_sent_on = ?
+WHERE user_id = ?;
+
+-- name: DeleteUserEmail :one
+DELETE FROM user_emails
+WHERE user_id = ?
+RETURNING *;
+
+/* === PASSWORD RESETS === */
+
+-- name: AddPasswordReset :one
+INSERT INTO password_resets (token_hash, user_id, expires_at)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT *
+FROM password_resets
+WHERE token_hash = ?;
+
+-- Only the first use of a link counts, so it can't be used twice at once
+-- name: UsePasswordReset :execrows
+UPDATE password_resets
+SET used_at = ?
+WHERE token_hash = ? AND used_at IS NULL;
+
+/* === OUTBOX === */
+
+-- name: QueueEmail :one
+INSERT INTO outbox (to_address, subject, text_body, html_body, next_attempt_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetDueEmails :many
+SELECT *
+FROM outbox
+WHERE next_attempt_at IS NOT NULL AND next_attempt_at <= sqlc.arg('now')
+ORDER BY next_attempt_at, id
+LIMIT sqlc.arg('max_results');
+
+-- The latest emails first, for seeing what's been sent and what's stuck
+-- name: GetOutbox :many
+SELECT *
+FROM outbox
+ORDER BY id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: SetEmailSent :exec
+UPDATE outbox
+SET attempts = attempts + 1, sent_at = sqlc.arg('sent_at'), next_attempt_at = NULL, last_error = ''
+WHERE id = sqlc.arg('id');
+
+-- Records a failed attempt, and when to try again, or NULL to give up
+-- name: SetEmailFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = sqlc.arg('last_error'), next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id');
+
+/* === WEBHOOKS === */
+
+-- name: AddWebhook :one
+INSERT INTO webhooks (url, secret, events)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT *
+FROM webhooks
+WHERE id = ?;
+
+-- name: GetWebhooks :many
+SELECT *
+FROM webhooks
+ORDER BY id;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = ?
+RETURNING *;
+
+-- name: QueueDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- Deliveries to try now, with where to send them and the secret to sign them with
+-- name: GetDueDeliveries :many
+SELECT sqlc.embed(webhook_deliveries), webhooks.url, webhooks.secret
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.next_attempt_at IS NOT NULL AND webhook_deliveries.next_attempt_at <= sqlc.arg('now')
+ORDER BY webhook_deliveries.next_attempt_at, webhook_deliveries.id
+LIMIT sqlc.arg('max_results');
+
+-- The latest deliveries first, for the delivery log
+-- name: GetDeliveries :many
+SELECT sqlc.embed(webhook_deliveries), webhooks.url
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+ORDER BY webhook_deliveries.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: SetDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = sqlc.arg('last_status'), delivered_at = sqlc.arg('delivered_at'), next_attempt_at = NULL, last_error = ''
+WHERE id = sqlc.arg('id');
+
+-- Records a failed attempt, and when to try again, or NULL to give up
+-- name: SetDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = sqlc.arg('last_status'), last_error = sqlc.arg('last_error'), next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id');
+
+/* === DRINKING SESSIONS === */
+
+-- name: AddDrinkingSession :one
+INSERT INTO drinking_sessions (name, created_by, started_at, share_token)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetDrinkingSession :one
+SELECT * FROM drinking_sessions
+WHERE id = ?;
+
+-- name: GetDrinkingSessionByToken :one
+SELECT * FROM drinking_sessions
+WHERE share_token = ?;
+
+-- The sessions the user is in, latest first
+-- name: GetUserDrinkingSessions :many
+SELECT drinking_sessions.*
+FROM drinking_sessions
+JOIN drinking_session_participants ON drinking_session_participants.session_id = drinking_sessions.id
+WHERE drinking_session_participants.user_id = ?
+ORDER BY drinking_sessions.started_at DESC, drinking_sessions.id DESC;
+
+-- name: EndDrinkingSession :one
+UPDATE drinking_sessions
+SET ended_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- Deletes the session along with its participants and venues. The drinks had in it are kept, just
+-- no longer in a session.
+-- name: DeleteDrinkingSession :one
+DELETE FROM drinking_sessions
+WHERE id = ?
+RETURNING *;
+
+-- name: AddSessionParticipant :exec
+INSERT INTO drinking_session_participants (session_id, user_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- The session's participants in the order they joined, leaving out deleted users
+-- name: GetSessionParticipants :many
+SELECT users.id AS user_id, users.username, drinking_session_participants.joined_at
+FROM drinking_session_participants
+JOIN users ON users.id = drinking_session_participants.user_id
+WHERE drinking_session_participants.session_id = ? AND users.deleted_at IS NULL
+ORDER BY drinking_session_participants.joined_at, users.id;
+
+-- name: AddSessionVenue :one
+INSERT INTO drinking_session_venues (session_id, name)
+VALUES (?, ?)
+RETURNING *;
+
+-- name: GetSessionVenues :many
+SELECT * FROM drinking_session_venues
+WHERE session_id = ?
+ORDER BY id;
+
+-- name: AddSessionDrink :exec
+INSERT INTO drinking_session_drinks (drink_id, session_id)
+VALUES (?, ?);
+
+-- The drinks had in the session in the order they were had, with who had them, leaving out
+-- deleted users like the participants do
+-- name: GetSessionDrinks :many
+SELECT sqlc.embed(drinks), beers.name AS beer_name, beers.abv, users.username
+FROM drinking_session_drinks
+JOIN drinks ON drinks.id = drinking_session_drinks.drink_id
+JOIN beers ON beers.id = drinks.beer_id
+JOIN users ON users.id = drinks.user_id
+WHERE drinking_session_drinks.session_id = ? AND users.deleted_at IS NULL
+ORDER BY drinks.drunk_at, drinks.id;
+
+/* === VENUES === */
+
+-- name: AddVenue :one
+INSERT INTO venues (name, address, type, latitude, longitude)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetVenue :one
+SELECT * FROM venues
+WHERE id = ?;
+
+-- name: GetVenues :many
+SELECT * FROM venues
+ORDER BY name, id;
+
+-- The venues with coordinates inside the box, for narrowing down which are near somewhere
+-- name: GetVenuesInBox :many
+SELECT * FROM venues
+WHERE latitude >= CAST(sqlc.arg('min_latitude') AS REAL) AND latitude <= CAST(sqlc.arg('max_latitude') AS REAL)
+AND longitude >= CAST(sqlc.arg('min_longitude') AS REAL) AND longitude <= CAST(sqlc.arg('max_longitude') AS REAL);
+
+-- Deletes the venue along with the check-ins at it
+-- name: DeleteVenue :one
+DELETE FROM venues
+WHERE id = ?
+RETURNING *;
+
+-- name: AddCheckIn :one
+INSERT INTO check_ins (user_id, beer_id, venue_id, checked_in_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetCheckIn :one
+SELECT * FROM check_ins
+WHERE id = ?;
+
+-- name: DeleteCheckIn :one
+DELETE FROM check_ins
+WHERE id = ?
+RETURNING *;
+
+-- The user's check-ins with where they were and what they had, latest first
+-- name: GetUserCheckIns :many
+SELECT sqlc.embed(check_ins), beers.name AS beer_name, venues.name AS venue_name
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN venues ON venues.id = check_ins.venue_id
+WHERE check_ins.user_id = sqlc.arg('user_id')
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- The check-ins at the venue with who had what, latest first, leaving out deleted users
+-- name: GetVenueCheckIns :many
+SELECT sqlc.embed(check_ins), beers.name AS beer_name, users.username
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN users ON users.id = check_ins.user_id
+WHERE check_ins.venue_id = sqlc.arg('venue_id') AND users.deleted_at IS NULL
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- The beers had most at the venue, most first and then by name
+-- name: GetVenueTopBeers :many
+SELECT beers.id AS beer_id, beers.name, COUNT(*) AS check_ins
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+WHERE check_ins.venue_id = sqlc.arg('venue_id')
+GROUP BY beers.id, beers.name
+ORDER BY check_ins DESC, beers.name
+LIMIT sqlc.arg('max_results');
+
+/* === ROUNDS === */
+
+-- name: AddRound :one
+INSERT INTO rounds (session_id, bought_by, cost, currency, bought_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: AddRoundRecipient :exec
+INSERT INTO round_recipients (round_id, user_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: GetRound :one
+SELECT * FROM rounds
+WHERE id = ?;
+
+-- The rounds bought in the session with who bought them, in the order they were bought, leaving
+-- out those bought by deleted users
+-- name: GetSessionRounds :many
+SELECT sqlc.embed(rounds), users.username
+FROM rounds
+JOIN users ON users.id = rounds.bought_by
+WHERE rounds.session_id = ? AND users.deleted_at IS NULL
+ORDER BY rounds.bought_at, rounds.id;
+
+-- Who each round in the session was bought for, leaving out deleted users
+-- name: GetSessionRoundRecipients :many
+SELECT round_recipients.round_id, round_recipients.user_id, users.username
+FROM round_recipients
+JOIN rounds ON rounds.id = round_recipients.round_id
+JOIN users ON users.id = round_recipients.user_id
+WHERE rounds.session_id = ? AND users.deleted_at IS NULL
+ORDER BY round_recipients.round_id, round_recipients.user_id;
+
+-- Deletes the round along with who it was bought for
+-- name: DeleteRound :one
+DELETE FROM rounds
+WHERE id = ?
+RETURNING *;
+
+/* === FOLLOWS === */
+
+-- name: Follow :exec
+INSERT INTO follows (follower_id, followee_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: Unfollow :exec
+DELETE FROM follows
+WHERE follower_id = ? AND followee_id = ?;
+
+-- Who the user follows, leaving out deleted users
+-- name: GetFollowing :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = ? AND users.deleted_at IS NULL
+ORDER BY users.username;
+
+-- Who follows the user, leaving out deleted users
+-- name: GetFollowers :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = ? AND users.deleted_at IS NULL
+ORDER BY users.username;
+
+-- name: SetPrivacy :one
+INSERT INTO user_privacy (user_id, visibility)
+VALUES (?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET visibility = excluded.visibility, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetPrivacy :one
+SELECT *
+FROM user_privacy
+WHERE user_id = ?;
+
+/* === ACTIVITIES === */
+
+-- name: AddActivity :one
+INSERT INTO activities (user_id, kind, beer_id, brewer_id)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- The activity of the users the viewer follows, newest first, from before the given id. Only
+-- activity the viewer may see is included: that of public users, and that of users who keep it to
+-- their friends when they and the viewer follow each other. Deleted users, beers and brewers are
+-- left out.
+-- name: GetFeed :many
+SELECT sqlc.embed(activities), users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+JOIN follows ON follows.followee_id = activities.user_id AND follows.follower_id = sqlc.arg('viewer_id')
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.id < sqlc.arg('before')
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = sqlc.arg('viewer_id')
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- The user's activity as the viewer may see it, newest first, from before the given id. Users
+-- always see their own activity; others see it as they would in their feed.
+-- name: GetUserActivities :many
+SELECT sqlc.embed(activities), users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.user_id = sqlc.arg('user_id')
+    AND activities.id < sqlc.arg('before')
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        activities.user_id = sqlc.arg('viewer_id')
+        OR user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = sqlc.arg('viewer_id')
+            )
+            AND EXISTS (
+                SELECT 1 FROM follows AS forth
+                WHERE forth.follower_id = sqlc.arg('viewer_id') AND forth.followee_id = activities.user_id
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT sqlc.arg('max_results');
+
+/* === COMMENTS === */
+
+-- name: AddComment :one
+INSERT INTO comments (author_id, beer_id, brewer_id, parent_id, body)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM comments
+WHERE id = ?;
+
+-- The comments on the beer or brewer with who wrote them, oldest first, including deleted ones
+-- but leaving out those by deleted users. Only one of the ids is given.
+-- name: GetComments :many
+SELECT sqlc.embed(comments), users.username
+FROM comments
+JOIN users ON users.id = comments.author_id
+WHERE (comments.beer_id = sqlc.narg('beer_id') OR comments.brewer_id = sqlc.narg('brewer_id'))
+    AND users.deleted_at IS NULL
+ORDER BY comments.id;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET body = ?, edited_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- Blanks the comment, keeping it for the replies to it
+-- name: DeleteComment :one
+UPDATE comments
+SET body = '', deleted_at = CURRENT_TIMESTAMP, moderated = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: AddReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, emoji)
+VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteReaction :execrows
+DELETE FROM comment_reactions
+WHERE comment_id = ? AND user_id = ? AND emoji = ?;
+
+-- The reactions to the comments on the beer or brewer, leaving out those of deleted users. Only
+-- one of the ids is given.
+-- name: GetReactions :many
+SELECT comment_reactions.comment_id, comment_reactions.user_id, comment_reactions.emoji
+FROM comment_reactions
+JOIN comments ON comments.id = comment_reactions.comment_id
+JOIN users ON users.id = comment_reactions.user_id
+WHERE (comments.beer_id = sqlc.narg('beer_id') OR comments.brewer_id = sqlc.narg('brewer_id'))
+    AND users.deleted_at IS NULL
+ORDER BY comment_reactions.comment_id, comment_reactions.created_at;
+
+/* === TASTINGS === */
+
+-- name: AddTasting :one
+INSERT INTO tastings (name, organiser_id, held_at)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetTasting :one
+SELECT *
+FROM tastings
+WHERE id = ?;
+
+-- name: GetTastings :many
+SELECT sqlc.embed(tastings), users.username
+FROM tastings
+LEFT JOIN users ON users.id = tastings.organiser_id
+ORDER BY tastings.held_at DESC, tastings.id DESC;
+
+-- name: DeleteTasting :one
+DELETE FROM tastings
+WHERE id = ?
+RETURNING *;
+
+-- name: RevealTasting :one
+UPDATE tastings
+SET revealed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND revealed_at IS NULL
+RETURNING *;
+
+-- name: AddPour :one
+INSERT INTO tasting_pours (tasting_id, number, beer_id)
+SELECT sqlc.arg('tasting_id'), COALESCE(MAX(number), 0) + 1, sqlc.arg('beer_id')
+FROM tasting_pours
+WHERE tasting_id = sqlc.arg('tasting_id')
+RETURNING *;
+
+-- name: DeletePour :execrows
+DELETE FROM tasting_pours
+WHERE tasting_id = ? AND number = ?;
+
+-- name: GetPours :many
+SELECT tasting_pours.*, beers.name AS beer_name, brewers.name AS brewer_name
+FROM tasting_pours
+JOIN beers ON beers.id = tasting_pours.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE tasting_pours.tasting_id = ?
+ORDER BY tasting_pours.number;
+
+-- name: SaveTastingScorecard :one
+INSERT INTO tasting_scorecards (tasting_id, number, user_id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (tasting_id, number, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetTastingScorecards :many
+SELECT sqlc.embed(tasting_scorecards), users.username
+FROM tasting_scorecards
+JOIN users ON users.id = tasting_scorecards.user_id
+WHERE tasting_scorecards.tasting_id = ? AND users.deleted_at IS NULL
+ORDER BY tasting_scorecards.number, users.username;
+
+/* === BADGES === */
+
+-- name: GetAddedBeerFacts :many
+SELECT beer_revisions.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, beer_revisions.created_at AS at
+FROM beer_revisions
+JOIN users ON users.id = beer_revisions.user_id
+JOIN beers ON beers.id = beer_revisions.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beer_revisions.id IN (SELECT MIN(first.id) FROM beer_revisions AS first GROUP BY first.beer_id)
+    AND beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (beer_revisions.user_id = sqlc.narg('user_id') OR sqlc.narg('user_id') IS NULL)
+    AND beer_revisions.created_at >= sqlc.arg('since') AND beer_revisions.created_at < sqlc.arg('until')
+ORDER BY beer_revisions.created_at, beers.id;
+
+-- name: GetRatedBeerFacts :many
+SELECT scorecards.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, scorecards.created_at AS at
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+JOIN beers ON beers.id = scorecards.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (scorecards.user_id = sqlc.narg('user_id') OR sqlc.narg('user_id') IS NULL)
+    AND scorecards.created_at >= sqlc.arg('since') AND scorecards.created_at < sqlc.arg('until')
+ORDER BY scorecards.created_at, beers.id;
+
+/* === WISHLIST === */
+
+-- name: AddWish :one
+INSERT INTO wishlist (user_id, beer_id, priority, note)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateWish :one
+UPDATE wishlist
+SET priority = ?, note = ?
+WHERE user_id = ? AND beer_id = ?
+RETURNING *;
+
+-- name: DeleteWish :execrows
+DELETE FROM wishlist
+WHERE user_id = ? AND beer_id = ?;
+
+-- name: GetWishlist :many
+SELECT sqlc.embed(wishlist), sqlc.embed(beers), brewers.name AS brewer_name
+FROM wishlist
+JOIN beers ON beers.id = wishlist.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE wishlist.user_id = ? AND beers.deleted_at IS NULL
+ORDER BY wishlist.priority IS NULL, wishlist.priority, wishlist.created_at, beers.id;
@@ -4,14 +4,17 @@ CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     username TEXT NOT NULL UNIQUE,
     password_hash TEXT NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    last_login TIMESTAMP
+    last_login TIMESTAMP,
+    deleted_at TIMESTAMP
 );
 
 CREATE TABLE IF NOT EXISTS brewers (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     name TEXT NOT NULL UNIQUE,
-    location TEXT
+    location TEXT,
+    deleted_at TIMESTAMP
 );
 
 CREATE TABLE IF NOT EXISTS beers (
@@ -22,6 +25,460 @@ CREATE TABLE IF NOT EXISTS beers (
     abv REAL NOT NULL,
     rating REAL,
     notes TEXT,
+    deleted_at TIMESTAMP,
     FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE SET NULL,
     CONSTRAINT unique_brewer_beer UNIQUE (name, brewer_id)
-);
\ No newline at end of file
+);
+CREATE TABLE IF NOT EXISTS beer_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name TEXT NOT NULL,
+    brewer_id INTEGER,
+    style TEXT,
+    abv REAL NOT NULL,
+    rating REAL,
+    notes TEXT,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS styles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    category TEXT NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    abv_min REAL NOT NULL,
+    abv_max REAL NOT NULL,
+    ibu_min REAL NOT NULL,
+    ibu_max REAL NOT NULL,
+    srm_min REAL NOT NULL,
+    srm_max REAL NOT NULL,
+    description TEXT NOT NULL
+);
+
+-- The household's weighting of each part of a scorecard, a single row
+CREATE TABLE IF NOT EXISTS scorecard_weights (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    aroma REAL NOT NULL,
+    appearance REAL NOT NULL,
+    flavour REAL NOT NULL,
+    mouthfeel REAL NOT NULL,
+    overall REAL NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS scorecards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    aroma REAL NOT NULL,
+    appearance REAL NOT NULL,
+    flavour REAL NOT NULL,
+    mouthfeel REAL NOT NULL,
+    overall REAL NOT NULL,
+    total REAL NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_beer_user_scorecard UNIQUE (beer_id, user_id)
+);
+
+-- Flavour tags, either from the curated vocabulary, which have a flavour wheel category, or made
+-- up by users. Names are stored lowercase.
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    category TEXT
+);
+
+CREATE TABLE IF NOT EXISTS beer_tags (
+    beer_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (beer_id, tag_id),
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- Photos of beer labels. The images themselves are kept in blob storage under the keys.
+CREATE TABLE IF NOT EXISTS label_photos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    blob_key TEXT NOT NULL,
+    thumb_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- The EAN-8, EAN-13 and UPC-A codes printed on beers' cans and bottles. A beer can have several,
+-- e.g. one for a single can and one for a slab.
+CREATE TABLE IF NOT EXISTS barcodes (
+    code TEXT PRIMARY KEY,
+    beer_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+
+-- What's in the fridge. Each purchase of a beer is an entry, which is taken from one at a time.
+-- Empty entries are kept as the record of what was spent. The price is of each container, in the
+-- currency's ISO 4217 code.
+CREATE TABLE IF NOT EXISTS stock (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER,
+    quantity INTEGER NOT NULL,
+    bought INTEGER NOT NULL,
+    container_ml INTEGER NOT NULL,
+    purchased_on DATE NOT NULL,
+    best_before DATE,
+    price REAL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- How much each user wants to spend on beer a month
+CREATE TABLE IF NOT EXISTS budgets (
+    user_id INTEGER PRIMARY KEY,
+    amount REAL NOT NULL,
+    currency TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Each drink a user has had. drunk_on is the day it was had where they were, which is what the
+-- stats count by, so a drink late on a Friday night is on Friday wherever the server is.
+CREATE TABLE IF NOT EXISTS drinks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    beer_id INTEGER NOT NULL,
+    serving_ml INTEGER NOT NULL,
+    drunk_at TIMESTAMP NOT NULL,
+    drunk_on DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinks_user_drunk_on ON drinks (user_id, drunk_on);
+
+-- Each user's weekly drinking goals, where a goal that's NULL isn't set. The limit is in standard
+-- drinks. The time zone is the IANA name of where the user is, which their days and weeks are
+-- counted in, or empty for wherever the server is.
+CREATE TABLE IF NOT EXISTS goals (
+    user_id INTEGER PRIMARY KEY,
+    weekly_limit REAL,
+    alcohol_free_days INTEGER,
+    confirm_over_limit BOOLEAN NOT NULL DEFAULT 0,
+    time_zone TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- The times each week a user calls beer o'clock, such as Friday at 17:00 where they are. The
+-- weekday counts from Sunday as 0, the minute from midnight, and a reminder goes out remind_before
+-- minutes ahead. reminded_at is when the last reminder went out, so each is only sent once.
+CREATE TABLE IF NOT EXISTS schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL,
+    minute INTEGER NOT NULL,
+    remind_before INTEGER NOT NULL DEFAULT 0,
+    reminded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_user_schedule UNIQUE (user_id, weekday, minute)
+);
+
+-- Where to email each user, and which emails they want. summary_sent_on is the Monday starting the
+-- last week a summary went out for, so each week's is only sent once.
+CREATE TABLE IF NOT EXISTS user_emails (
+    user_id INTEGER PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    weekly_summary BOOLEAN NOT NULL DEFAULT 1,
+    reminders BOOLEAN NOT NULL DEFAULT 1,
+    summary_sent_on DATE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Links for resetting forgotten passwords. Only a hash of each link's token is kept, so the links
+-- can't be rebuilt from the database.
+CREATE TABLE IF NOT EXISTS password_resets (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Emails waiting to be sent, and the ones which have been. Failed sends are tried again at
+-- next_attempt_at, which is NULL once the email's been sent or given up on.
+CREATE TABLE IF NOT EXISTS outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    to_address TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS outbox_next_attempt_at ON outbox (next_attempt_at);
+
+-- Where to send events such as beer.created, for hooking the app up to other systems. events is a
+-- comma separated list of the event types the webhook wants, and the secret signs each payload.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Each event sent, or waiting to be sent, to a webhook. Like the outbox, failed deliveries are
+-- tried again at next_attempt_at, which is NULL once delivered or given up on. last_status is the
+-- HTTP status of the last attempt, or NULL if there wasn't a response.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_status INTEGER,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+-- Drinks grouped into a named session, e.g. a night at the pub. A session is still going while
+-- ended_at is NULL. Anyone with the share token can see a read-only summary of it.
+CREATE TABLE IF NOT EXISTS drinking_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created_by INTEGER,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    share_token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS drinking_session_participants (
+    session_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, user_id),
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinking_session_participants_user_id ON drinking_session_participants (user_id);
+
+-- The places a session went, in the order they were added
+CREATE TABLE IF NOT EXISTS drinking_session_venues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE
+);
+
+-- Which session each drink was had in, if any. A drink is only ever in one session.
+CREATE TABLE IF NOT EXISTS drinking_session_drinks (
+    drink_id INTEGER PRIMARY KEY,
+    session_id INTEGER NOT NULL,
+    FOREIGN KEY (drink_id) REFERENCES drinks(id) ON DELETE CASCADE,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS drinking_session_drinks_session_id ON drinking_session_drinks (session_id);
+
+-- Places beers are drunk. type is bar, bottle-shop or home. The coordinates are in degrees, and
+-- either both set or both NULL.
+CREATE TABLE IF NOT EXISTS venues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL,
+    latitude REAL,
+    longitude REAL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS venues_latitude_longitude ON venues (latitude, longitude);
+
+-- Where each user had a beer, and when
+CREATE TABLE IF NOT EXISTS check_ins (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    beer_id INTEGER NOT NULL,
+    venue_id INTEGER NOT NULL,
+    checked_in_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS check_ins_user_id ON check_ins (user_id);
+CREATE INDEX IF NOT EXISTS check_ins_venue_id ON check_ins (venue_id);
+
+-- The rounds bought in a drinking session. Whoever bought one shouted the drinks of everyone in
+-- round_recipients, usually including themselves. The cost is of the whole round if anyone kept
+-- track, in the currency's ISO 4217 code.
+CREATE TABLE IF NOT EXISTS rounds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    bought_by INTEGER NOT NULL,
+    cost REAL,
+    currency TEXT NOT NULL,
+    bought_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES drinking_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (bought_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS rounds_session_id ON rounds (session_id);
+
+CREATE TABLE IF NOT EXISTS round_recipients (
+    round_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (round_id, user_id),
+    FOREIGN KEY (round_id) REFERENCES rounds(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Who follows whom. Following is one way; two users who follow each other are friends.
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INTEGER NOT NULL,
+    followee_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id),
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS follows_followee_id ON follows (followee_id);
+
+-- Who can see each user's activity: public, friends or private. Users without a row are public.
+CREATE TABLE IF NOT EXISTS user_privacy (
+    user_id INTEGER PRIMARY KEY,
+    visibility TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- What each user did, for the feed: kind is beer-added, beer-edited or brewer-added, with the
+-- beer or brewer it was done to
+CREATE TABLE IF NOT EXISTS activities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    beer_id INTEGER,
+    brewer_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS activities_user_id ON activities (user_id);
+
+-- What users have said about beers and brewers. Each comment is on exactly one of them, and
+-- replies point at the comment they're replying to. Deleting a comment blanks it but keeps it,
+-- so the replies to it still have somewhere to hang; moderated is set when an admin deleted it
+-- rather than its author.
+CREATE TABLE IF NOT EXISTS comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    author_id INTEGER NOT NULL,
+    beer_id INTEGER,
+    brewer_id INTEGER,
+    parent_id INTEGER,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    moderated BOOLEAN NOT NULL DEFAULT 0,
+    CHECK ((beer_id IS NULL) <> (brewer_id IS NULL)),
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS comments_beer_id ON comments (beer_id);
+CREATE INDEX IF NOT EXISTS comments_brewer_id ON comments (brewer_id);
+
+-- Who reacted to which comment with which emoji. Each user can react with each emoji once.
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id, emoji),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Blind tastings, where beers are poured as numbered glasses and scored without knowing which is
+-- which. The organiser reveals them when everyone's done, after which nothing can change.
+CREATE TABLE IF NOT EXISTS tastings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    organiser_id INTEGER,
+    held_at TIMESTAMP NOT NULL,
+    revealed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organiser_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- The beer in each numbered pour, numbered from 1 in each tasting
+CREATE TABLE IF NOT EXISTS tasting_pours (
+    tasting_id INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    beer_id INTEGER NOT NULL,
+    PRIMARY KEY (tasting_id, number),
+    FOREIGN KEY (tasting_id) REFERENCES tastings(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    CONSTRAINT unique_tasting_beer UNIQUE (tasting_id, beer_id)
+);
+
+-- Each participant's scores for a pour, out of 10 like scorecards. They're copied into the beer's
+-- scorecards at the reveal.
+CREATE TABLE IF NOT EXISTS tasting_scorecards (
+    tasting_id INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    aroma REAL NOT NULL,
+    appearance REAL NOT NULL,
+    flavour REAL NOT NULL,
+    mouthfeel REAL NOT NULL,
+    overall REAL NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tasting_id, number, user_id),
+    FOREIGN KEY (tasting_id, number) REFERENCES tasting_pours(tasting_id, number) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS tasting_scorecards_user_id ON tasting_scorecards (user_id);
+
+-- The beers each user wants to try, with how much they want to from 1 (most) to 3 (least). A beer
+-- comes off the list when the user rates it.
+CREATE TABLE IF NOT EXISTS wishlist (
+    user_id INTEGER NOT NULL,
+    beer_id INTEGER NOT NULL,
+    priority INTEGER,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, beer_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS wishlist_beer_id ON wishlist (beer_id);
@@ -12,10 +12,120 @@ import (
 //go:embed config/schema.sql
 var schemaGenSql string
 
+//go:embed config/postgres/schema.sql
+var postgresSchemaGenSql string
+
+// A column added to a table after the table was first released. CREATE TABLE IF NOT EXISTS leaves
+// the tables in an existing database as they were, so the column is added when it's missing.
+type addedColumn struct {
+	table      string
+	column     string
+	definition string
+	// Fills in the column for the rows already there, if its default isn't right for them
+	backfill string
+}
+
+var sqliteAddedColumns = []addedColumn{
+	{
+		table:      "users",
+		column:     "is_admin",
+		definition: "BOOLEAN NOT NULL DEFAULT 0",
+		// The seeded account is the admin in a new database, so it is in an upgraded one too
+		backfill: "UPDATE users SET is_admin = 1 WHERE username = 'saltytaro'",
+	},
+	{table: "users", column: "deleted_at", definition: "TIMESTAMP"},
+	{table: "brewers", column: "deleted_at", definition: "TIMESTAMP"},
+	{table: "beers", column: "deleted_at", definition: "TIMESTAMP"},
+	stockBought("INTEGER"),
+	stockCurrency,
+	// SQLite can't add a column defaulting to the current time, but every scorecard saved sets it
+	scorecardCreatedAt("TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00'"),
+}
+
+var postgresAddedColumns = []addedColumn{
+	stockBought("BIGINT"),
+	stockCurrency,
+	scorecardCreatedAt("TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP"),
+}
+
+// What was in the fridge before is taken to be what was bought, and in the default currency
+func stockBought(integer string) addedColumn {
+	return addedColumn{
+		table:      "stock",
+		column:     "bought",
+		definition: integer + " NOT NULL DEFAULT 0",
+		backfill:   "UPDATE stock SET bought = quantity",
+	}
+}
+
+var stockCurrency = addedColumn{table: "stock", column: "currency", definition: "TEXT NOT NULL DEFAULT 'AUD'"}
+
+// When a scorecard was first saved isn't known for those already there, so it's taken to be when
+// it was last saved
+func scorecardCreatedAt(definition string) addedColumn {
+	return addedColumn{
+		table:      "scorecards",
+		column:     "created_at",
+		definition: definition,
+		backfill:   "UPDATE scorecards SET created_at = updated_at",
+	}
+}
+
+const sqliteColumnExists = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+
+const postgresColumnExists = `SELECT COUNT(*) FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2`
+
+// Adds each column which isn't in the database yet, along with its backfill, so it's safe to run
+// every time the database is opened
+func addColumns(ctx context.Context, dbPool *sql.DB, columns []addedColumn, columnExists string) error {
+	for _, c := range columns {
+		var count int
+		if err := dbPool.QueryRowContext(ctx, columnExists, c.table, c.column).Scan(&count); err != nil {
+			return fmt.Errorf("error checking for column %s.%s: %w", c.table, c.column, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		tx, err := dbPool.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition))
+		if err == nil && c.backfill != "" {
+			_, err = tx.ExecContext(ctx, c.backfill)
+		}
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			tx.Rollback()
+		}
+		if err != nil {
+			return fmt.Errorf("error adding column %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
 func GenSchema(dbPool *sql.DB) error {
 	_, err := dbPool.ExecContext(context.Background(), schemaGenSql)
 	if err != nil {
 		return fmt.Errorf("error initializing database: %w", err)
 	}
+	if err := addColumns(context.Background(), dbPool, sqliteAddedColumns, sqliteColumnExists); err != nil {
+		return fmt.Errorf("error migrating database: %w", err)
+	}
+	return nil
+}
+
+func GenPostgresSchema(dbPool *sql.DB) error {
+	_, err := dbPool.ExecContext(context.Background(), postgresSchemaGenSql)
+	if err != nil {
+		return fmt.Errorf("error initializing database: %w", err)
+	}
+	if err := addColumns(context.Background(), dbPool, postgresAddedColumns, postgresColumnExists); err != nil {
+		return fmt.Errorf("error migrating database: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// The tables as they were before any columns were added to them, with the fridge from before it
+// recorded spending and scorecards from before they recorded when they were first saved
+const originalSchema = `
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_login TIMESTAMP
+);
+
+CREATE TABLE brewers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    location TEXT
+);
+
+CREATE TABLE beers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    brewer_id INTEGER,
+    style TEXT,
+    abv REAL NOT NULL,
+    rating REAL,
+    notes TEXT,
+    FOREIGN KEY (brewer_id) REFERENCES brewers(id) ON DELETE SET NULL,
+    CONSTRAINT unique_brewer_beer UNIQUE (name, brewer_id)
+);
+
+CREATE TABLE stock (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER,
+    quantity INTEGER NOT NULL,
+    container_ml INTEGER NOT NULL,
+    purchased_on DATE NOT NULL,
+    best_before DATE,
+    price REAL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE scorecards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    beer_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    aroma REAL NOT NULL,
+    appearance REAL NOT NULL,
+    flavour REAL NOT NULL,
+    mouthfeel REAL NOT NULL,
+    overall REAL NOT NULL,
+    total REAL NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (beer_id) REFERENCES beers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_beer_user_scorecard UNIQUE (beer_id, user_id)
+);
+
+INSERT INTO users (username, password_hash) VALUES ('saltytaro', 'hash'), ('guest', 'hash');
+INSERT INTO brewers (name, location) VALUES ('Felon''s', 'Brisbane');
+INSERT INTO beers (name, brewer_id, abv, rating) VALUES ('Pale', 1, 5, 7);
+INSERT INTO stock (beer_id, user_id, quantity, container_ml, purchased_on, price) VALUES (1, 1, 4, 375, '2025-03-01', 20);
+INSERT INTO scorecards (beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, updated_at)
+VALUES (1, 2, 7, 7, 7, 7, 7, 7, '2025-03-02 18:30:00');
+`
+
+func TestGenSchemaUpgrades(t *testing.T) {
+	dbPool, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbPool.Close()
+	// Every connection to :memory: gets its own empty database, so stick to one
+	dbPool.SetMaxOpenConns(1)
+
+	if _, err := dbPool.Exec(originalSchema); err != nil {
+		t.Fatalf("creating original schema: %v", err)
+	}
+	// Opening it again finds nothing left to add
+	for i := 0; i < 2; i++ {
+		if err := GenSchema(dbPool); err != nil {
+			t.Fatalf("generating schema %d: %v", i+1, err)
+		}
+	}
+
+	queries := New(dbPool)
+	ctx := context.Background()
+	admin, err := queries.GetUserById(ctx, 1)
+	if err != nil || !admin.IsAdmin || admin.DeletedAt.Valid {
+		t.Errorf("got seeded user %+v, %v, want an admin who isn't deleted", admin, err)
+	}
+	if guest, err := queries.GetUserById(ctx, 2); err != nil || guest.IsAdmin {
+		t.Errorf("got guest %+v, %v, want not an admin", guest, err)
+	}
+	beers, err := queries.GetBeers(ctx)
+	if err != nil || len(beers) != 1 || beers[0].Name != "Pale" {
+		t.Errorf("got beers %+v, %v", beers, err)
+	}
+	brewers, err := queries.GetBrewers(ctx)
+	if err != nil || len(brewers) != 1 {
+		t.Errorf("got brewers %+v, %v", brewers, err)
+	}
+	var bought int64
+	var currency string
+	if err := dbPool.QueryRow("SELECT bought, currency FROM stock WHERE id = 1").Scan(&bought, &currency); err != nil || bought != 4 || currency != "AUD" {
+		t.Errorf("got stock bought %d in %q, %v, want 4 in AUD", bought, currency, err)
+	}
+
+	scorecard, err := queries.GetScorecard(ctx, GetScorecardParams{BeerID: 1, UserID: 2})
+	if err != nil || scorecard.CreatedAt.IsZero() || !scorecard.CreatedAt.Equal(scorecard.UpdatedAt) {
+		t.Errorf("got scorecard %+v, %v, want it created when it was last saved", scorecard, err)
+	}
+	// Saving it again keeps when it was first saved
+	scorecard, err = queries.SaveScorecard(ctx, SaveScorecardParams{BeerID: 1, UserID: 2, Aroma: 8, Appearance: 8, Flavour: 8, Mouthfeel: 8, Overall: 8, Total: 8})
+	if err != nil || scorecard.CreatedAt.Year() != 2025 || scorecard.UpdatedAt.Year() == 2025 {
+		t.Errorf("got scorecard saved again %+v, %v, want it still created in 2025", scorecard, err)
+	}
+}
@@ -0,0 +1,357 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+
+package pgdb
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Activity struct {
+	ID        int64
+	UserID    int64
+	Kind      string
+	BeerID    sql.NullInt64
+	BrewerID  sql.NullInt64
+	CreatedAt time.Time
+}
+
+type Barcode struct {
+	Code      string
+	BeerID    int64
+	CreatedAt time.Time
+}
+
+type Beer struct {
+	ID        int64
+	Name      string
+	BrewerID  sql.NullInt64
+	Style     sql.NullString
+	Abv       float64
+	Rating    sql.NullFloat64
+	Notes     sql.NullString
+	DeletedAt sql.NullTime
+}
+
+type BeerRevision struct {
+	ID        int64
+	BeerID    int64
+	UserID    sql.NullInt64
+	CreatedAt time.Time
+	Name      string
+	BrewerID  sql.NullInt64
+	Style     sql.NullString
+	Abv       float64
+	Rating    sql.NullFloat64
+	Notes     sql.NullString
+}
+
+type BeerTag struct {
+	BeerID int64
+	TagID  int64
+}
+
+type Brewer struct {
+	ID        int64
+	Name      string
+	Location  sql.NullString
+	DeletedAt sql.NullTime
+}
+
+type Budget struct {
+	UserID    int64
+	Amount    float64
+	Currency  string
+	UpdatedAt time.Time
+}
+
+type CheckIn struct {
+	ID          int64
+	UserID      int64
+	BeerID      int64
+	VenueID     int64
+	CheckedInAt time.Time
+	CreatedAt   time.Time
+}
+
+type Comment struct {
+	ID        int64
+	AuthorID  int64
+	BeerID    sql.NullInt64
+	BrewerID  sql.NullInt64
+	ParentID  sql.NullInt64
+	Body      string
+	CreatedAt time.Time
+	EditedAt  sql.NullTime
+	DeletedAt sql.NullTime
+	Moderated bool
+}
+
+type CommentReaction struct {
+	CommentID int64
+	UserID    int64
+	Emoji     string
+	CreatedAt time.Time
+}
+
+type Drink struct {
+	ID        int64
+	UserID    int64
+	BeerID    int64
+	ServingMl int64
+	DrunkAt   time.Time
+	DrunkOn   time.Time
+	CreatedAt time.Time
+}
+
+type DrinkingSession struct {
+	ID         int64
+	Name       string
+	CreatedBy  sql.NullInt64
+	StartedAt  time.Time
+	EndedAt    sql.NullTime
+	ShareToken string
+	CreatedAt  time.Time
+}
+
+type DrinkingSessionDrink struct {
+	DrinkID   int64
+	SessionID int64
+}
+
+type DrinkingSessionParticipant struct {
+	SessionID int64
+	UserID    int64
+	JoinedAt  time.Time
+}
+
+type DrinkingSessionVenue struct {
+	ID        int64
+	SessionID int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type Follow struct {
+	FollowerID int64
+	FolloweeID int64
+	CreatedAt  time.Time
+}
+
+type Goal struct {
+	UserID           int64
+	WeeklyLimit      sql.NullFloat64
+	AlcoholFreeDays  sql.NullInt64
+	ConfirmOverLimit bool
+	TimeZone         string
+	UpdatedAt        time.Time
+}
+
+type LabelPhoto struct {
+	ID          int64
+	BeerID      int64
+	UserID      sql.NullInt64
+	ContentType string
+	Size        int64
+	Width       int64
+	Height      int64
+	BlobKey     string
+	ThumbKey    string
+	CreatedAt   time.Time
+}
+
+type Outbox struct {
+	ID            int64
+	ToAddress     string
+	Subject       string
+	TextBody      string
+	HtmlBody      string
+	Attempts      int64
+	NextAttemptAt sql.NullTime
+	LastError     string
+	SentAt        sql.NullTime
+	CreatedAt     time.Time
+}
+
+type PasswordReset struct {
+	TokenHash string
+	UserID    int64
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type Round struct {
+	ID        int64
+	SessionID int64
+	BoughtBy  int64
+	Cost      sql.NullFloat64
+	Currency  string
+	BoughtAt  time.Time
+	CreatedAt time.Time
+}
+
+type RoundRecipient struct {
+	RoundID int64
+	UserID  int64
+}
+
+type Schedule struct {
+	ID           int64
+	UserID       int64
+	Weekday      int64
+	Minute       int64
+	RemindBefore int64
+	RemindedAt   sql.NullTime
+	CreatedAt    time.Time
+}
+
+type Scorecard struct {
+	ID         int64
+	BeerID     int64
+	UserID     int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+	Total      float64
+	UpdatedAt  time.Time
+	CreatedAt  time.Time
+}
+
+type ScorecardWeight struct {
+	ID         int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+}
+
+type Stock struct {
+	ID          int64
+	BeerID      int64
+	UserID      sql.NullInt64
+	Quantity    int64
+	Bought      int64
+	ContainerMl int64
+	PurchasedOn time.Time
+	BestBefore  sql.NullTime
+	Price       sql.NullFloat64
+	Currency    string
+	CreatedAt   time.Time
+}
+
+type Style struct {
+	ID          int64
+	Code        string
+	Category    string
+	Name        string
+	AbvMin      float64
+	AbvMax      float64
+	IbuMin      float64
+	IbuMax      float64
+	SrmMin      float64
+	SrmMax      float64
+	Description string
+}
+
+type Tag struct {
+	ID       int64
+	Name     string
+	Category sql.NullString
+}
+
+type Tasting struct {
+	ID          int64
+	Name        string
+	OrganiserID sql.NullInt64
+	HeldAt      time.Time
+	RevealedAt  sql.NullTime
+	CreatedAt   time.Time
+}
+
+type TastingPour struct {
+	TastingID int64
+	Number    int64
+	BeerID    int64
+}
+
+type TastingScorecard struct {
+	TastingID  int64
+	Number     int64
+	UserID     int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+	UpdatedAt  time.Time
+}
+
+type User struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	IsAdmin      bool
+	CreatedAt    sql.NullTime
+	LastLogin    sql.NullTime
+	DeletedAt    sql.NullTime
+}
+
+type UserEmail struct {
+	UserID        int64
+	Email         string
+	WeeklySummary bool
+	Reminders     bool
+	SummarySentOn sql.NullTime
+	UpdatedAt     time.Time
+}
+
+type UserPrivacy struct {
+	UserID     int64
+	Visibility string
+	UpdatedAt  time.Time
+}
+
+type Venue struct {
+	ID        int64
+	Name      string
+	Address   string
+	Type      string
+	Latitude  sql.NullFloat64
+	Longitude sql.NullFloat64
+	CreatedAt time.Time
+}
+
+type Webhook struct {
+	ID        int64
+	Url       string
+	Secret    string
+	Events    string
+	CreatedAt time.Time
+}
+
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	Event         string
+	Payload       string
+	Attempts      int64
+	NextAttemptAt sql.NullTime
+	LastStatus    sql.NullInt64
+	LastError     string
+	DeliveredAt   sql.NullTime
+	CreatedAt     time.Time
+}
+
+type Wishlist struct {
+	UserID    int64
+	BeerID    int64
+	Priority  sql.NullInt64
+	Note      sql.NullString
+	CreatedAt time.Time
+}
@@ -0,0 +1,5999 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: queries.sql
+
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const addActivity = `-- name: AddActivity :one
+
+INSERT INTO activities (user_id, kind, beer_id, brewer_id)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, kind, beer_id, brewer_id, created_at
+`
+
+type AddActivityParams struct {
+	UserID   int64
+	Kind     string
+	BeerID   sql.NullInt64
+	BrewerID sql.NullInt64
+}
+
+// === ACTIVITIES ===
+func (q *Queries) AddActivity(ctx context.Context, arg AddActivityParams) (Activity, error) {
+	row := q.db.QueryRowContext(ctx, addActivity,
+		arg.UserID,
+		arg.Kind,
+		arg.BeerID,
+		arg.BrewerID,
+	)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.BeerID,
+		&i.BrewerID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addBarcode = `-- name: AddBarcode :one
+
+INSERT INTO barcodes (code, beer_id)
+VALUES ($1, $2)
+ON CONFLICT (code) DO UPDATE
+SET beer_id = excluded.beer_id, created_at = CURRENT_TIMESTAMP
+WHERE barcodes.beer_id IN (SELECT id FROM beers WHERE deleted_at IS NOT NULL)
+RETURNING code, beer_id, created_at
+`
+
+type AddBarcodeParams struct {
+	Code   string
+	BeerID int64
+}
+
+// === BARCODES ===
+// Codes of beers in the trash are taken over, but not those of other beers
+func (q *Queries) AddBarcode(ctx context.Context, arg AddBarcodeParams) (Barcode, error) {
+	row := q.db.QueryRowContext(ctx, addBarcode, arg.Code, arg.BeerID)
+	var i Barcode
+	err := row.Scan(&i.Code, &i.BeerID, &i.CreatedAt)
+	return i, err
+}
+
+const addBeer = `-- name: AddBeer :one
+
+INSERT INTO beers (name, brewer_id, style, abv, rating, notes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+type AddBeerParams struct {
+	Name     string
+	BrewerID sql.NullInt64
+	Style    sql.NullString
+	Abv      float64
+	Rating   sql.NullFloat64
+	Notes    sql.NullString
+}
+
+// === BEERS ===
+func (q *Queries) AddBeer(ctx context.Context, arg AddBeerParams) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, addBeer,
+		arg.Name,
+		arg.BrewerID,
+		arg.Style,
+		arg.Abv,
+		arg.Rating,
+		arg.Notes,
+	)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const addBeerRevision = `-- name: AddBeerRevision :one
+
+INSERT INTO beer_revisions (beer_id, user_id, name, brewer_id, style, abv, rating, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, beer_id, user_id, created_at, name, brewer_id, style, abv, rating, notes
+`
+
+type AddBeerRevisionParams struct {
+	BeerID   int64
+	UserID   sql.NullInt64
+	Name     string
+	BrewerID sql.NullInt64
+	Style    sql.NullString
+	Abv      float64
+	Rating   sql.NullFloat64
+	Notes    sql.NullString
+}
+
+// === BEER REVISIONS ===
+func (q *Queries) AddBeerRevision(ctx context.Context, arg AddBeerRevisionParams) (BeerRevision, error) {
+	row := q.db.QueryRowContext(ctx, addBeerRevision,
+		arg.BeerID,
+		arg.UserID,
+		arg.Name,
+		arg.BrewerID,
+		arg.Style,
+		arg.Abv,
+		arg.Rating,
+		arg.Notes,
+	)
+	var i BeerRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+	)
+	return i, err
+}
+
+const addBeerTag = `-- name: AddBeerTag :exec
+INSERT INTO beer_tags (beer_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddBeerTagParams struct {
+	BeerID int64
+	TagID  int64
+}
+
+func (q *Queries) AddBeerTag(ctx context.Context, arg AddBeerTagParams) error {
+	_, err := q.db.ExecContext(ctx, addBeerTag, arg.BeerID, arg.TagID)
+	return err
+}
+
+const addBrewer = `-- name: AddBrewer :one
+
+INSERT INTO brewers (name, location)
+VALUES ($1, $2)
+RETURNING id, name, location, deleted_at
+`
+
+type AddBrewerParams struct {
+	Name     string
+	Location sql.NullString
+}
+
+// === BREWERS ===
+func (q *Queries) AddBrewer(ctx context.Context, arg AddBrewerParams) (Brewer, error) {
+	row := q.db.QueryRowContext(ctx, addBrewer, arg.Name, arg.Location)
+	var i Brewer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Location,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const addCheckIn = `-- name: AddCheckIn :one
+INSERT INTO check_ins (user_id, beer_id, venue_id, checked_in_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, beer_id, venue_id, checked_in_at, created_at
+`
+
+type AddCheckInParams struct {
+	UserID      int64
+	BeerID      int64
+	VenueID     int64
+	CheckedInAt time.Time
+}
+
+func (q *Queries) AddCheckIn(ctx context.Context, arg AddCheckInParams) (CheckIn, error) {
+	row := q.db.QueryRowContext(ctx, addCheckIn,
+		arg.UserID,
+		arg.BeerID,
+		arg.VenueID,
+		arg.CheckedInAt,
+	)
+	var i CheckIn
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.VenueID,
+		&i.CheckedInAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addComment = `-- name: AddComment :one
+
+INSERT INTO comments (author_id, beer_id, brewer_id, parent_id, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, author_id, beer_id, brewer_id, parent_id, body, created_at, edited_at, deleted_at, moderated
+`
+
+type AddCommentParams struct {
+	AuthorID int64
+	BeerID   sql.NullInt64
+	BrewerID sql.NullInt64
+	ParentID sql.NullInt64
+	Body     string
+}
+
+// === COMMENTS ===
+func (q *Queries) AddComment(ctx context.Context, arg AddCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, addComment,
+		arg.AuthorID,
+		arg.BeerID,
+		arg.BrewerID,
+		arg.ParentID,
+		arg.Body,
+	)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.BeerID,
+		&i.BrewerID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+		&i.Moderated,
+	)
+	return i, err
+}
+
+const addDrink = `-- name: AddDrink :one
+
+INSERT INTO drinks (user_id, beer_id, serving_ml, drunk_at, drunk_on)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, beer_id, serving_ml, drunk_at, drunk_on, created_at
+`
+
+type AddDrinkParams struct {
+	UserID    int64
+	BeerID    int64
+	ServingMl int64
+	DrunkAt   time.Time
+	DrunkOn   time.Time
+}
+
+// === DRINKS ===
+func (q *Queries) AddDrink(ctx context.Context, arg AddDrinkParams) (Drink, error) {
+	row := q.db.QueryRowContext(ctx, addDrink,
+		arg.UserID,
+		arg.BeerID,
+		arg.ServingMl,
+		arg.DrunkAt,
+		arg.DrunkOn,
+	)
+	var i Drink
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.ServingMl,
+		&i.DrunkAt,
+		&i.DrunkOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addDrinkingSession = `-- name: AddDrinkingSession :one
+
+INSERT INTO drinking_sessions (name, created_by, started_at, share_token)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, created_by, started_at, ended_at, share_token, created_at
+`
+
+type AddDrinkingSessionParams struct {
+	Name       string
+	CreatedBy  sql.NullInt64
+	StartedAt  time.Time
+	ShareToken string
+}
+
+// === DRINKING SESSIONS ===
+func (q *Queries) AddDrinkingSession(ctx context.Context, arg AddDrinkingSessionParams) (DrinkingSession, error) {
+	row := q.db.QueryRowContext(ctx, addDrinkingSession,
+		arg.Name,
+		arg.CreatedBy,
+		arg.StartedAt,
+		arg.ShareToken,
+	)
+	var i DrinkingSession
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ShareToken,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addLabelPhoto = `-- name: AddLabelPhoto :one
+
+INSERT INTO label_photos (beer_id, user_id, content_type, size, width, height, blob_key, thumb_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, beer_id, user_id, content_type, size, width, height, blob_key, thumb_key, created_at
+`
+
+type AddLabelPhotoParams struct {
+	BeerID      int64
+	UserID      sql.NullInt64
+	ContentType string
+	Size        int64
+	Width       int64
+	Height      int64
+	BlobKey     string
+	ThumbKey    string
+}
+
+// === LABEL PHOTOS ===
+func (q *Queries) AddLabelPhoto(ctx context.Context, arg AddLabelPhotoParams) (LabelPhoto, error) {
+	row := q.db.QueryRowContext(ctx, addLabelPhoto,
+		arg.BeerID,
+		arg.UserID,
+		arg.ContentType,
+		arg.Size,
+		arg.Width,
+		arg.Height,
+		arg.BlobKey,
+		arg.ThumbKey,
+	)
+	var i LabelPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.ContentType,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.BlobKey,
+		&i.ThumbKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addPasswordReset = `-- name: AddPasswordReset :one
+
+INSERT INTO password_resets (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING token_hash, user_id, expires_at, used_at, created_at
+`
+
+type AddPasswordResetParams struct {
+	TokenHash string
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+// === PASSWORD RESETS ===
+func (q *Queries) AddPasswordReset(ctx context.Context, arg AddPasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, addPasswordReset, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	var i PasswordReset
+	err := row.Scan(
+		&i.TokenHash,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addPour = `-- name: AddPour :one
+INSERT INTO tasting_pours (tasting_id, number, beer_id)
+SELECT $1::bigint, COALESCE(MAX(number), 0) + 1, $2::bigint
+FROM tasting_pours
+WHERE tasting_id = $1
+RETURNING tasting_id, number, beer_id
+`
+
+type AddPourParams struct {
+	TastingID int64
+	BeerID    int64
+}
+
+func (q *Queries) AddPour(ctx context.Context, arg AddPourParams) (TastingPour, error) {
+	row := q.db.QueryRowContext(ctx, addPour, arg.TastingID, arg.BeerID)
+	var i TastingPour
+	err := row.Scan(&i.TastingID, &i.Number, &i.BeerID)
+	return i, err
+}
+
+const addReaction = `-- name: AddReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, emoji)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type AddReactionParams struct {
+	CommentID int64
+	UserID    int64
+	Emoji     string
+}
+
+func (q *Queries) AddReaction(ctx context.Context, arg AddReactionParams) error {
+	_, err := q.db.ExecContext(ctx, addReaction, arg.CommentID, arg.UserID, arg.Emoji)
+	return err
+}
+
+const addRound = `-- name: AddRound :one
+
+INSERT INTO rounds (session_id, bought_by, cost, currency, bought_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, session_id, bought_by, cost, currency, bought_at, created_at
+`
+
+type AddRoundParams struct {
+	SessionID int64
+	BoughtBy  int64
+	Cost      sql.NullFloat64
+	Currency  string
+	BoughtAt  time.Time
+}
+
+// === ROUNDS ===
+func (q *Queries) AddRound(ctx context.Context, arg AddRoundParams) (Round, error) {
+	row := q.db.QueryRowContext(ctx, addRound,
+		arg.SessionID,
+		arg.BoughtBy,
+		arg.Cost,
+		arg.Currency,
+		arg.BoughtAt,
+	)
+	var i Round
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.BoughtBy,
+		&i.Cost,
+		&i.Currency,
+		&i.BoughtAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addRoundRecipient = `-- name: AddRoundRecipient :exec
+INSERT INTO round_recipients (round_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddRoundRecipientParams struct {
+	RoundID int64
+	UserID  int64
+}
+
+func (q *Queries) AddRoundRecipient(ctx context.Context, arg AddRoundRecipientParams) error {
+	_, err := q.db.ExecContext(ctx, addRoundRecipient, arg.RoundID, arg.UserID)
+	return err
+}
+
+const addSchedule = `-- name: AddSchedule :one
+
+INSERT INTO schedules (user_id, weekday, minute, remind_before)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, weekday, minute, remind_before, reminded_at, created_at
+`
+
+type AddScheduleParams struct {
+	UserID       int64
+	Weekday      int64
+	Minute       int64
+	RemindBefore int64
+}
+
+// === SCHEDULES ===
+func (q *Queries) AddSchedule(ctx context.Context, arg AddScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, addSchedule,
+		arg.UserID,
+		arg.Weekday,
+		arg.Minute,
+		arg.RemindBefore,
+	)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Weekday,
+		&i.Minute,
+		&i.RemindBefore,
+		&i.RemindedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addSessionDrink = `-- name: AddSessionDrink :exec
+INSERT INTO drinking_session_drinks (drink_id, session_id)
+VALUES ($1, $2)
+`
+
+type AddSessionDrinkParams struct {
+	DrinkID   int64
+	SessionID int64
+}
+
+func (q *Queries) AddSessionDrink(ctx context.Context, arg AddSessionDrinkParams) error {
+	_, err := q.db.ExecContext(ctx, addSessionDrink, arg.DrinkID, arg.SessionID)
+	return err
+}
+
+const addSessionParticipant = `-- name: AddSessionParticipant :exec
+INSERT INTO drinking_session_participants (session_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddSessionParticipantParams struct {
+	SessionID int64
+	UserID    int64
+}
+
+func (q *Queries) AddSessionParticipant(ctx context.Context, arg AddSessionParticipantParams) error {
+	_, err := q.db.ExecContext(ctx, addSessionParticipant, arg.SessionID, arg.UserID)
+	return err
+}
+
+const addSessionVenue = `-- name: AddSessionVenue :one
+INSERT INTO drinking_session_venues (session_id, name)
+VALUES ($1, $2)
+RETURNING id, session_id, name, created_at
+`
+
+type AddSessionVenueParams struct {
+	SessionID int64
+	Name      string
+}
+
+func (q *Queries) AddSessionVenue(ctx context.Context, arg AddSessionVenueParams) (DrinkingSessionVenue, error) {
+	row := q.db.QueryRowContext(ctx, addSessionVenue, arg.SessionID, arg.Name)
+	var i DrinkingSessionVenue
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addStock = `-- name: AddStock :one
+
+INSERT INTO stock (beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency, created_at
+`
+
+type AddStockParams struct {
+	BeerID      int64
+	UserID      sql.NullInt64
+	Quantity    int64
+	Bought      int64
+	ContainerMl int64
+	PurchasedOn time.Time
+	BestBefore  sql.NullTime
+	Price       sql.NullFloat64
+	Currency    string
+}
+
+// === STOCK ===
+func (q *Queries) AddStock(ctx context.Context, arg AddStockParams) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, addStock,
+		arg.BeerID,
+		arg.UserID,
+		arg.Quantity,
+		arg.Bought,
+		arg.ContainerMl,
+		arg.PurchasedOn,
+		arg.BestBefore,
+		arg.Price,
+		arg.Currency,
+	)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Quantity,
+		&i.Bought,
+		&i.ContainerMl,
+		&i.PurchasedOn,
+		&i.BestBefore,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addStyle = `-- name: AddStyle :one
+
+INSERT INTO styles (code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT DO NOTHING
+RETURNING id, code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description
+`
+
+type AddStyleParams struct {
+	Code        string
+	Category    string
+	Name        string
+	AbvMin      float64
+	AbvMax      float64
+	IbuMin      float64
+	IbuMax      float64
+	SrmMin      float64
+	SrmMax      float64
+	Description string
+}
+
+// === STYLES ===
+func (q *Queries) AddStyle(ctx context.Context, arg AddStyleParams) (Style, error) {
+	row := q.db.QueryRowContext(ctx, addStyle,
+		arg.Code,
+		arg.Category,
+		arg.Name,
+		arg.AbvMin,
+		arg.AbvMax,
+		arg.IbuMin,
+		arg.IbuMax,
+		arg.SrmMin,
+		arg.SrmMax,
+		arg.Description,
+	)
+	var i Style
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Category,
+		&i.Name,
+		&i.AbvMin,
+		&i.AbvMax,
+		&i.IbuMin,
+		&i.IbuMax,
+		&i.SrmMin,
+		&i.SrmMax,
+		&i.Description,
+	)
+	return i, err
+}
+
+const addTag = `-- name: AddTag :one
+
+INSERT INTO tags (name, category)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+RETURNING id, name, category
+`
+
+type AddTagParams struct {
+	Name     string
+	Category sql.NullString
+}
+
+// === TAGS ===
+func (q *Queries) AddTag(ctx context.Context, arg AddTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, addTag, arg.Name, arg.Category)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Name, &i.Category)
+	return i, err
+}
+
+const addTasting = `-- name: AddTasting :one
+
+INSERT INTO tastings (name, organiser_id, held_at)
+VALUES ($1, $2, $3)
+RETURNING id, name, organiser_id, held_at, revealed_at, created_at
+`
+
+type AddTastingParams struct {
+	Name        string
+	OrganiserID sql.NullInt64
+	HeldAt      time.Time
+}
+
+// === TASTINGS ===
+func (q *Queries) AddTasting(ctx context.Context, arg AddTastingParams) (Tasting, error) {
+	row := q.db.QueryRowContext(ctx, addTasting, arg.Name, arg.OrganiserID, arg.HeldAt)
+	var i Tasting
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganiserID,
+		&i.HeldAt,
+		&i.RevealedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addUser = `-- name: AddUser :one
+
+INSERT INTO users (username, password_hash, is_admin)
+VALUES ($1, $2, $3)
+RETURNING id, username, password_hash, is_admin, created_at, last_login, deleted_at
+`
+
+type AddUserParams struct {
+	Username     string
+	PasswordHash string
+	IsAdmin      bool
+}
+
+// === CONTACTS ===
+func (q *Queries) AddUser(ctx context.Context, arg AddUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, addUser, arg.Username, arg.PasswordHash, arg.IsAdmin)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const addVenue = `-- name: AddVenue :one
+
+INSERT INTO venues (name, address, type, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, address, type, latitude, longitude, created_at
+`
+
+type AddVenueParams struct {
+	Name      string
+	Address   string
+	Type      string
+	Latitude  sql.NullFloat64
+	Longitude sql.NullFloat64
+}
+
+// === VENUES ===
+func (q *Queries) AddVenue(ctx context.Context, arg AddVenueParams) (Venue, error) {
+	row := q.db.QueryRowContext(ctx, addVenue,
+		arg.Name,
+		arg.Address,
+		arg.Type,
+		arg.Latitude,
+		arg.Longitude,
+	)
+	var i Venue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Type,
+		&i.Latitude,
+		&i.Longitude,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addWebhook = `-- name: AddWebhook :one
+
+INSERT INTO webhooks (url, secret, events)
+VALUES ($1, $2, $3)
+RETURNING id, url, secret, events, created_at
+`
+
+type AddWebhookParams struct {
+	Url    string
+	Secret string
+	Events string
+}
+
+// === WEBHOOKS ===
+func (q *Queries) AddWebhook(ctx context.Context, arg AddWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, addWebhook, arg.Url, arg.Secret, arg.Events)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addWish = `-- name: AddWish :one
+
+INSERT INTO wishlist (user_id, beer_id, priority, note)
+VALUES ($1, $2, $3, $4)
+RETURNING user_id, beer_id, priority, note, created_at
+`
+
+type AddWishParams struct {
+	UserID   int64
+	BeerID   int64
+	Priority sql.NullInt64
+	Note     sql.NullString
+}
+
+// === WISHLIST ===
+func (q *Queries) AddWish(ctx context.Context, arg AddWishParams) (Wishlist, error) {
+	row := q.db.QueryRowContext(ctx, addWish,
+		arg.UserID,
+		arg.BeerID,
+		arg.Priority,
+		arg.Note,
+	)
+	var i Wishlist
+	err := row.Scan(
+		&i.UserID,
+		&i.BeerID,
+		&i.Priority,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const clearBeerTags = `-- name: ClearBeerTags :exec
+DELETE FROM beer_tags
+WHERE beer_id = $1
+`
+
+func (q *Queries) ClearBeerTags(ctx context.Context, beerID int64) error {
+	_, err := q.db.ExecContext(ctx, clearBeerTags, beerID)
+	return err
+}
+
+const countBeers = `-- name: CountBeers :one
+SELECT COUNT(*)
+FROM beers
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) CountBeers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBeers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBrewers = `-- name: CountBrewers :one
+SELECT COUNT(*)
+FROM brewers
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) CountBrewers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBrewers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteBarcode = `-- name: DeleteBarcode :one
+DELETE FROM barcodes
+WHERE code = $1
+RETURNING code, beer_id, created_at
+`
+
+func (q *Queries) DeleteBarcode(ctx context.Context, code string) (Barcode, error) {
+	row := q.db.QueryRowContext(ctx, deleteBarcode, code)
+	var i Barcode
+	err := row.Scan(&i.Code, &i.BeerID, &i.CreatedAt)
+	return i, err
+}
+
+const deleteBeer = `-- name: DeleteBeer :one
+UPDATE beers
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+func (q *Queries) DeleteBeer(ctx context.Context, id int64) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, deleteBeer, id)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteBeerLabelPhotos = `-- name: DeleteBeerLabelPhotos :many
+DELETE FROM label_photos
+WHERE beer_id = $1
+RETURNING id, beer_id, user_id, content_type, size, width, height, blob_key, thumb_key, created_at
+`
+
+func (q *Queries) DeleteBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error) {
+	rows, err := q.db.QueryContext(ctx, deleteBeerLabelPhotos, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabelPhoto
+	for rows.Next() {
+		var i LabelPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.BeerID,
+			&i.UserID,
+			&i.ContentType,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.BlobKey,
+			&i.ThumbKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteBrewer = `-- name: DeleteBrewer :one
+UPDATE brewers
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, location, deleted_at
+`
+
+func (q *Queries) DeleteBrewer(ctx context.Context, id int64) (Brewer, error) {
+	row := q.db.QueryRowContext(ctx, deleteBrewer, id)
+	var i Brewer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Location,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteBudget = `-- name: DeleteBudget :one
+DELETE FROM budgets
+WHERE user_id = $1
+RETURNING user_id, amount, currency, updated_at
+`
+
+func (q *Queries) DeleteBudget(ctx context.Context, userID int64) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, deleteBudget, userID)
+	var i Budget
+	err := row.Scan(
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCheckIn = `-- name: DeleteCheckIn :one
+DELETE FROM check_ins
+WHERE id = $1
+RETURNING id, user_id, beer_id, venue_id, checked_in_at, created_at
+`
+
+func (q *Queries) DeleteCheckIn(ctx context.Context, id int64) (CheckIn, error) {
+	row := q.db.QueryRowContext(ctx, deleteCheckIn, id)
+	var i CheckIn
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.VenueID,
+		&i.CheckedInAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteComment = `-- name: DeleteComment :one
+UPDATE comments
+SET body = '', deleted_at = CURRENT_TIMESTAMP, moderated = $1
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, author_id, beer_id, brewer_id, parent_id, body, created_at, edited_at, deleted_at, moderated
+`
+
+type DeleteCommentParams struct {
+	Moderated bool
+	ID        int64
+}
+
+// Blanks the comment, keeping it for the replies to it
+func (q *Queries) DeleteComment(ctx context.Context, arg DeleteCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, deleteComment, arg.Moderated, arg.ID)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.BeerID,
+		&i.BrewerID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+		&i.Moderated,
+	)
+	return i, err
+}
+
+const deleteDrink = `-- name: DeleteDrink :one
+DELETE FROM drinks
+WHERE id = $1
+RETURNING id, user_id, beer_id, serving_ml, drunk_at, drunk_on, created_at
+`
+
+func (q *Queries) DeleteDrink(ctx context.Context, id int64) (Drink, error) {
+	row := q.db.QueryRowContext(ctx, deleteDrink, id)
+	var i Drink
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.ServingMl,
+		&i.DrunkAt,
+		&i.DrunkOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteDrinkingSession = `-- name: DeleteDrinkingSession :one
+DELETE FROM drinking_sessions
+WHERE id = $1
+RETURNING id, name, created_by, started_at, ended_at, share_token, created_at
+`
+
+// Deletes the session along with its participants and venues. The drinks had in it are kept, just
+// no longer in a session.
+func (q *Queries) DeleteDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error) {
+	row := q.db.QueryRowContext(ctx, deleteDrinkingSession, id)
+	var i DrinkingSession
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ShareToken,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteGoals = `-- name: DeleteGoals :one
+DELETE FROM goals
+WHERE user_id = $1
+RETURNING user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone, updated_at
+`
+
+func (q *Queries) DeleteGoals(ctx context.Context, userID int64) (Goal, error) {
+	row := q.db.QueryRowContext(ctx, deleteGoals, userID)
+	var i Goal
+	err := row.Scan(
+		&i.UserID,
+		&i.WeeklyLimit,
+		&i.AlcoholFreeDays,
+		&i.ConfirmOverLimit,
+		&i.TimeZone,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteLabelPhoto = `-- name: DeleteLabelPhoto :one
+DELETE FROM label_photos
+WHERE id = $1
+RETURNING id, beer_id, user_id, content_type, size, width, height, blob_key, thumb_key, created_at
+`
+
+func (q *Queries) DeleteLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error) {
+	row := q.db.QueryRowContext(ctx, deleteLabelPhoto, id)
+	var i LabelPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.ContentType,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.BlobKey,
+		&i.ThumbKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePour = `-- name: DeletePour :execrows
+DELETE FROM tasting_pours
+WHERE tasting_id = $1 AND number = $2
+`
+
+type DeletePourParams struct {
+	TastingID int64
+	Number    int64
+}
+
+func (q *Queries) DeletePour(ctx context.Context, arg DeletePourParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePour, arg.TastingID, arg.Number)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteReaction = `-- name: DeleteReaction :execrows
+DELETE FROM comment_reactions
+WHERE comment_id = $1 AND user_id = $2 AND emoji = $3
+`
+
+type DeleteReactionParams struct {
+	CommentID int64
+	UserID    int64
+	Emoji     string
+}
+
+func (q *Queries) DeleteReaction(ctx context.Context, arg DeleteReactionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteReaction, arg.CommentID, arg.UserID, arg.Emoji)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRound = `-- name: DeleteRound :one
+DELETE FROM rounds
+WHERE id = $1
+RETURNING id, session_id, bought_by, cost, currency, bought_at, created_at
+`
+
+// Deletes the round along with who it was bought for
+func (q *Queries) DeleteRound(ctx context.Context, id int64) (Round, error) {
+	row := q.db.QueryRowContext(ctx, deleteRound, id)
+	var i Round
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.BoughtBy,
+		&i.Cost,
+		&i.Currency,
+		&i.BoughtAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteSchedule = `-- name: DeleteSchedule :one
+DELETE FROM schedules
+WHERE id = $1
+RETURNING id, user_id, weekday, minute, remind_before, reminded_at, created_at
+`
+
+func (q *Queries) DeleteSchedule(ctx context.Context, id int64) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, deleteSchedule, id)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Weekday,
+		&i.Minute,
+		&i.RemindBefore,
+		&i.RemindedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteStock = `-- name: DeleteStock :one
+DELETE FROM stock
+WHERE id = $1
+RETURNING id, beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency, created_at
+`
+
+func (q *Queries) DeleteStock(ctx context.Context, id int64) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, deleteStock, id)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Quantity,
+		&i.Bought,
+		&i.ContainerMl,
+		&i.PurchasedOn,
+		&i.BestBefore,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTasting = `-- name: DeleteTasting :one
+DELETE FROM tastings
+WHERE id = $1
+RETURNING id, name, organiser_id, held_at, revealed_at, created_at
+`
+
+func (q *Queries) DeleteTasting(ctx context.Context, id int64) (Tasting, error) {
+	row := q.db.QueryRowContext(ctx, deleteTasting, id)
+	var i Tasting
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganiserID,
+		&i.HeldAt,
+		&i.RevealedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, password_hash, is_admin, created_at, last_login, deleted_at
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, deleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteUserEmail = `-- name: DeleteUserEmail :one
+DELETE FROM user_emails
+WHERE user_id = $1
+RETURNING user_id, email, weekly_summary, reminders, summary_sent_on, updated_at
+`
+
+func (q *Queries) DeleteUserEmail(ctx context.Context, userID int64) (UserEmail, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserEmail, userID)
+	var i UserEmail
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.WeeklySummary,
+		&i.Reminders,
+		&i.SummarySentOn,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteVenue = `-- name: DeleteVenue :one
+DELETE FROM venues
+WHERE id = $1
+RETURNING id, name, address, type, latitude, longitude, created_at
+`
+
+// Deletes the venue along with the check-ins at it
+func (q *Queries) DeleteVenue(ctx context.Context, id int64) (Venue, error) {
+	row := q.db.QueryRowContext(ctx, deleteVenue, id)
+	var i Venue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Type,
+		&i.Latitude,
+		&i.Longitude,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1
+RETURNING id, url, secret, events, created_at
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id int64) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, deleteWebhook, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWish = `-- name: DeleteWish :execrows
+DELETE FROM wishlist
+WHERE user_id = $1 AND beer_id = $2
+`
+
+type DeleteWishParams struct {
+	UserID int64
+	BeerID int64
+}
+
+func (q *Queries) DeleteWish(ctx context.Context, arg DeleteWishParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWish, arg.UserID, arg.BeerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endDrinkingSession = `-- name: EndDrinkingSession :one
+UPDATE drinking_sessions
+SET ended_at = $1
+WHERE id = $2
+RETURNING id, name, created_by, started_at, ended_at, share_token, created_at
+`
+
+type EndDrinkingSessionParams struct {
+	EndedAt sql.NullTime
+	ID      int64
+}
+
+func (q *Queries) EndDrinkingSession(ctx context.Context, arg EndDrinkingSessionParams) (DrinkingSession, error) {
+	row := q.db.QueryRowContext(ctx, endDrinkingSession, arg.EndedAt, arg.ID)
+	var i DrinkingSession
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ShareToken,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const follow = `-- name: Follow :exec
+
+INSERT INTO follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type FollowParams struct {
+	FollowerID int64
+	FolloweeID int64
+}
+
+// === FOLLOWS ===
+func (q *Queries) Follow(ctx context.Context, arg FollowParams) error {
+	_, err := q.db.ExecContext(ctx, follow, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const getAbvDistribution = `-- name: GetAbvDistribution :many
+SELECT
+    CAST(FLOOR(beers.abv) AS BIGINT) AS abv,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetAbvDistributionParams struct {
+	UserID int64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetAbvDistributionRow struct {
+	Abv    int64
+	Drinks int64
+}
+
+// Drinks counted by the whole number of their ABV, e.g. 4.8% is counted under 4. CAST on its own
+// rounds in PostgreSQL, rather than truncating like SQLite.
+func (q *Queries) GetAbvDistribution(ctx context.Context, arg GetAbvDistributionParams) ([]GetAbvDistributionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAbvDistribution, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAbvDistributionRow
+	for rows.Next() {
+		var i GetAbvDistributionRow
+		if err := rows.Scan(&i.Abv, &i.Drinks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAddedBeerFacts = `-- name: GetAddedBeerFacts :many
+
+SELECT beer_revisions.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, beer_revisions.created_at AS at
+FROM beer_revisions
+JOIN users ON users.id = beer_revisions.user_id
+JOIN beers ON beers.id = beer_revisions.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beer_revisions.id IN (SELECT MIN(first.id) FROM beer_revisions AS first GROUP BY first.beer_id)
+    AND beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (beer_revisions.user_id = $1 OR $1 IS NULL)
+    AND beer_revisions.created_at >= $2 AND beer_revisions.created_at < $3
+ORDER BY beer_revisions.created_at, beers.id
+`
+
+type GetAddedBeerFactsParams struct {
+	UserID sql.NullInt64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetAddedBeerFactsRow struct {
+	UserID         sql.NullInt64
+	Username       string
+	BeerID         int64
+	Style          sql.NullString
+	StyleCategory  sql.NullString
+	BrewerID       sql.NullInt64
+	BrewerLocation sql.NullString
+	At             time.Time
+}
+
+// === BADGES ===
+func (q *Queries) GetAddedBeerFacts(ctx context.Context, arg GetAddedBeerFactsParams) ([]GetAddedBeerFactsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAddedBeerFacts, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAddedBeerFactsRow
+	for rows.Next() {
+		var i GetAddedBeerFactsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.BeerID,
+			&i.Style,
+			&i.StyleCategory,
+			&i.BrewerID,
+			&i.BrewerLocation,
+			&i.At,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllBeerTags = `-- name: GetAllBeerTags :many
+SELECT beer_tags.beer_id, tags.id, tags.name, tags.category
+FROM beer_tags
+JOIN tags ON tags.id = beer_tags.tag_id
+ORDER BY beer_tags.beer_id, tags.name
+`
+
+type GetAllBeerTagsRow struct {
+	BeerID int64
+	Tag    Tag
+}
+
+func (q *Queries) GetAllBeerTags(ctx context.Context) ([]GetAllBeerTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllBeerTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllBeerTagsRow
+	for rows.Next() {
+		var i GetAllBeerTagsRow
+		if err := rows.Scan(
+			&i.BeerID,
+			&i.Tag.ID,
+			&i.Tag.Name,
+			&i.Tag.Category,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllSchedules = `-- name: GetAllSchedules :many
+SELECT schedules.id, schedules.user_id, schedules.weekday, schedules.minute, schedules.remind_before, schedules.reminded_at, schedules.created_at, users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM schedules
+JOIN users ON users.id = schedules.user_id
+LEFT JOIN goals ON goals.user_id = schedules.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY schedules.id
+`
+
+type GetAllSchedulesRow struct {
+	Schedule Schedule
+	Username string
+	TimeZone string
+}
+
+// The schedules of everyone who isn't deleted, with where they are for the scheduler
+func (q *Queries) GetAllSchedules(ctx context.Context) ([]GetAllSchedulesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllSchedulesRow
+	for rows.Next() {
+		var i GetAllSchedulesRow
+		if err := rows.Scan(
+			&i.Schedule.ID,
+			&i.Schedule.UserID,
+			&i.Schedule.Weekday,
+			&i.Schedule.Minute,
+			&i.Schedule.RemindBefore,
+			&i.Schedule.RemindedAt,
+			&i.Schedule.CreatedAt,
+			&i.Username,
+			&i.TimeZone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBarcode = `-- name: GetBarcode :one
+SELECT barcodes.code, barcodes.beer_id, barcodes.created_at
+FROM barcodes
+JOIN beers ON beers.id = barcodes.beer_id
+WHERE barcodes.code = $1 AND beers.deleted_at IS NULL
+`
+
+func (q *Queries) GetBarcode(ctx context.Context, code string) (Barcode, error) {
+	row := q.db.QueryRowContext(ctx, getBarcode, code)
+	var i Barcode
+	err := row.Scan(&i.Code, &i.BeerID, &i.CreatedAt)
+	return i, err
+}
+
+const getBeerBarcodes = `-- name: GetBeerBarcodes :many
+SELECT code, beer_id, created_at
+FROM barcodes
+WHERE beer_id = $1
+ORDER BY code
+`
+
+func (q *Queries) GetBeerBarcodes(ctx context.Context, beerID int64) ([]Barcode, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerBarcodes, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Barcode
+	for rows.Next() {
+		var i Barcode
+		if err := rows.Scan(&i.Code, &i.BeerID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeerById = `-- name: GetBeerById :one
+SELECT id, name, brewer_id, style, abv, rating, notes, deleted_at
+FROM beers
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetBeerById(ctx context.Context, id int64) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, getBeerById, id)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBeerLabelPhotos = `-- name: GetBeerLabelPhotos :many
+SELECT id, beer_id, user_id, content_type, size, width, height, blob_key, thumb_key, created_at
+FROM label_photos
+WHERE beer_id = $1
+ORDER BY id
+`
+
+func (q *Queries) GetBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerLabelPhotos, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabelPhoto
+	for rows.Next() {
+		var i LabelPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.BeerID,
+			&i.UserID,
+			&i.ContentType,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.BlobKey,
+			&i.ThumbKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeerRevision = `-- name: GetBeerRevision :one
+SELECT id, beer_id, user_id, created_at, name, brewer_id, style, abv, rating, notes
+FROM beer_revisions
+WHERE id = $1 AND beer_id = $2
+`
+
+type GetBeerRevisionParams struct {
+	ID     int64
+	BeerID int64
+}
+
+func (q *Queries) GetBeerRevision(ctx context.Context, arg GetBeerRevisionParams) (BeerRevision, error) {
+	row := q.db.QueryRowContext(ctx, getBeerRevision, arg.ID, arg.BeerID)
+	var i BeerRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+	)
+	return i, err
+}
+
+const getBeerRevisions = `-- name: GetBeerRevisions :many
+SELECT beer_revisions.id, beer_revisions.beer_id, beer_revisions.user_id, beer_revisions.created_at, beer_revisions.name, beer_revisions.brewer_id, beer_revisions.style, beer_revisions.abv, beer_revisions.rating, beer_revisions.notes, users.username AS author, brewers.name AS brewer_name
+FROM beer_revisions
+LEFT JOIN users ON users.id = beer_revisions.user_id
+LEFT JOIN brewers ON brewers.id = beer_revisions.brewer_id
+WHERE beer_revisions.beer_id = $1
+ORDER BY beer_revisions.id
+`
+
+type GetBeerRevisionsRow struct {
+	BeerRevision BeerRevision
+	Author       sql.NullString
+	BrewerName   sql.NullString
+}
+
+func (q *Queries) GetBeerRevisions(ctx context.Context, beerID int64) ([]GetBeerRevisionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerRevisions, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBeerRevisionsRow
+	for rows.Next() {
+		var i GetBeerRevisionsRow
+		if err := rows.Scan(
+			&i.BeerRevision.ID,
+			&i.BeerRevision.BeerID,
+			&i.BeerRevision.UserID,
+			&i.BeerRevision.CreatedAt,
+			&i.BeerRevision.Name,
+			&i.BeerRevision.BrewerID,
+			&i.BeerRevision.Style,
+			&i.BeerRevision.Abv,
+			&i.BeerRevision.Rating,
+			&i.BeerRevision.Notes,
+			&i.Author,
+			&i.BrewerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeerScoreAverages = `-- name: GetBeerScoreAverages :one
+SELECT
+    COUNT(*) AS count,
+    COALESCE(AVG(aroma), 0)::double precision AS aroma,
+    COALESCE(AVG(appearance), 0)::double precision AS appearance,
+    COALESCE(AVG(flavour), 0)::double precision AS flavour,
+    COALESCE(AVG(mouthfeel), 0)::double precision AS mouthfeel,
+    COALESCE(AVG(overall), 0)::double precision AS overall,
+    COALESCE(AVG(total), 0)::double precision AS total
+FROM scorecards
+WHERE beer_id = $1
+`
+
+type GetBeerScoreAveragesRow struct {
+	Count      int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+	Total      float64
+}
+
+func (q *Queries) GetBeerScoreAverages(ctx context.Context, beerID int64) (GetBeerScoreAveragesRow, error) {
+	row := q.db.QueryRowContext(ctx, getBeerScoreAverages, beerID)
+	var i GetBeerScoreAveragesRow
+	err := row.Scan(
+		&i.Count,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+		&i.Total,
+	)
+	return i, err
+}
+
+const getBeerScorecards = `-- name: GetBeerScorecards :many
+SELECT scorecards.id, scorecards.beer_id, scorecards.user_id, scorecards.aroma, scorecards.appearance, scorecards.flavour, scorecards.mouthfeel, scorecards.overall, scorecards.total, scorecards.updated_at, scorecards.created_at, users.username
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+WHERE scorecards.beer_id = $1
+ORDER BY scorecards.total DESC, scorecards.id
+`
+
+type GetBeerScorecardsRow struct {
+	Scorecard Scorecard
+	Username  string
+}
+
+func (q *Queries) GetBeerScorecards(ctx context.Context, beerID int64) ([]GetBeerScorecardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerScorecards, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBeerScorecardsRow
+	for rows.Next() {
+		var i GetBeerScorecardsRow
+		if err := rows.Scan(
+			&i.Scorecard.ID,
+			&i.Scorecard.BeerID,
+			&i.Scorecard.UserID,
+			&i.Scorecard.Aroma,
+			&i.Scorecard.Appearance,
+			&i.Scorecard.Flavour,
+			&i.Scorecard.Mouthfeel,
+			&i.Scorecard.Overall,
+			&i.Scorecard.Total,
+			&i.Scorecard.UpdatedAt,
+			&i.Scorecard.CreatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeerStyles = `-- name: GetBeerStyles :many
+SELECT DISTINCT style
+FROM beers
+WHERE style IS NOT NULL AND style != ''
+ORDER BY style
+`
+
+func (q *Queries) GetBeerStyles(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerStyles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var style sql.NullString
+		if err := rows.Scan(&style); err != nil {
+			return nil, err
+		}
+		items = append(items, style)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeerTags = `-- name: GetBeerTags :many
+SELECT tags.id, tags.name, tags.category
+FROM tags
+JOIN beer_tags ON beer_tags.tag_id = tags.id
+WHERE beer_tags.beer_id = $1
+ORDER BY tags.name
+`
+
+func (q *Queries) GetBeerTags(ctx context.Context, beerID int64) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getBeerTags, beerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(&i.ID, &i.Name, &i.Category); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeers = `-- name: GetBeers :many
+SELECT id, name, brewer_id, style, abv, rating, notes, deleted_at
+FROM beers
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) GetBeers(ctx context.Context) ([]Beer, error) {
+	rows, err := q.db.QueryContext(ctx, getBeers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Beer
+	for rows.Next() {
+		var i Beer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BrewerID,
+			&i.Style,
+			&i.Abv,
+			&i.Rating,
+			&i.Notes,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeersByTag = `-- name: GetBeersByTag :many
+SELECT beers.id, beers.name, beers.brewer_id, beers.style, beers.abv, beers.rating, beers.notes, beers.deleted_at
+FROM beers
+JOIN beer_tags ON beer_tags.beer_id = beers.id
+JOIN tags ON tags.id = beer_tags.tag_id
+WHERE tags.name = $1 AND beers.deleted_at IS NULL
+ORDER BY beers.id
+`
+
+func (q *Queries) GetBeersByTag(ctx context.Context, name string) ([]Beer, error) {
+	rows, err := q.db.QueryContext(ctx, getBeersByTag, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Beer
+	for rows.Next() {
+		var i Beer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BrewerID,
+			&i.Style,
+			&i.Abv,
+			&i.Rating,
+			&i.Notes,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBrewerById = `-- name: GetBrewerById :one
+SELECT id, name, location, deleted_at
+FROM brewers
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetBrewerById(ctx context.Context, id int64) (Brewer, error) {
+	row := q.db.QueryRowContext(ctx, getBrewerById, id)
+	var i Brewer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Location,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBrewers = `-- name: GetBrewers :many
+SELECT id, name, location, deleted_at
+FROM brewers
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) GetBrewers(ctx context.Context) ([]Brewer, error) {
+	rows, err := q.db.QueryContext(ctx, getBrewers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Brewer
+	for rows.Next() {
+		var i Brewer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Location,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBudget = `-- name: GetBudget :one
+SELECT user_id, amount, currency, updated_at
+FROM budgets
+WHERE user_id = $1
+`
+
+func (q *Queries) GetBudget(ctx context.Context, userID int64) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, getBudget, userID)
+	var i Budget
+	err := row.Scan(
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCheckIn = `-- name: GetCheckIn :one
+SELECT id, user_id, beer_id, venue_id, checked_in_at, created_at FROM check_ins
+WHERE id = $1
+`
+
+func (q *Queries) GetCheckIn(ctx context.Context, id int64) (CheckIn, error) {
+	row := q.db.QueryRowContext(ctx, getCheckIn, id)
+	var i CheckIn
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.VenueID,
+		&i.CheckedInAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getComment = `-- name: GetComment :one
+SELECT id, author_id, beer_id, brewer_id, parent_id, body, created_at, edited_at, deleted_at, moderated FROM comments
+WHERE id = $1
+`
+
+func (q *Queries) GetComment(ctx context.Context, id int64) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, getComment, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.BeerID,
+		&i.BrewerID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+		&i.Moderated,
+	)
+	return i, err
+}
+
+const getComments = `-- name: GetComments :many
+SELECT comments.id, comments.author_id, comments.beer_id, comments.brewer_id, comments.parent_id, comments.body, comments.created_at, comments.edited_at, comments.deleted_at, comments.moderated, users.username
+FROM comments
+JOIN users ON users.id = comments.author_id
+WHERE (comments.beer_id = $1 OR comments.brewer_id = $2)
+    AND users.deleted_at IS NULL
+ORDER BY comments.id
+`
+
+type GetCommentsParams struct {
+	BeerID   sql.NullInt64
+	BrewerID sql.NullInt64
+}
+
+type GetCommentsRow struct {
+	Comment  Comment
+	Username string
+}
+
+// The comments on the beer or brewer with who wrote them, oldest first, including deleted ones
+// but leaving out those by deleted users. Only one of the ids is given.
+func (q *Queries) GetComments(ctx context.Context, arg GetCommentsParams) ([]GetCommentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getComments, arg.BeerID, arg.BrewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCommentsRow
+	for rows.Next() {
+		var i GetCommentsRow
+		if err := rows.Scan(
+			&i.Comment.ID,
+			&i.Comment.AuthorID,
+			&i.Comment.BeerID,
+			&i.Comment.BrewerID,
+			&i.Comment.ParentID,
+			&i.Comment.Body,
+			&i.Comment.CreatedAt,
+			&i.Comment.EditedAt,
+			&i.Comment.DeletedAt,
+			&i.Comment.Moderated,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedBeers = `-- name: GetDeletedBeers :many
+SELECT id, name, brewer_id, style, abv, rating, notes, deleted_at
+FROM beers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedBeers(ctx context.Context) ([]Beer, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedBeers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Beer
+	for rows.Next() {
+		var i Beer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BrewerID,
+			&i.Style,
+			&i.Abv,
+			&i.Rating,
+			&i.Notes,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedBrewers = `-- name: GetDeletedBrewers :many
+SELECT id, name, location, deleted_at
+FROM brewers
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedBrewers(ctx context.Context) ([]Brewer, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedBrewers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Brewer
+	for rows.Next() {
+		var i Brewer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Location,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedUsers = `-- name: GetDeletedUsers :many
+SELECT id, username, password_hash, is_admin, created_at, last_login, deleted_at
+FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.PasswordHash,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.LastLogin,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeliveries = `-- name: GetDeliveries :many
+SELECT webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.last_status, webhook_deliveries.last_error, webhook_deliveries.delivered_at, webhook_deliveries.created_at, webhooks.url
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+ORDER BY webhook_deliveries.id DESC
+LIMIT $1::bigint
+`
+
+type GetDeliveriesRow struct {
+	WebhookDelivery WebhookDelivery
+	Url             string
+}
+
+// The latest deliveries first, for the delivery log
+func (q *Queries) GetDeliveries(ctx context.Context, maxResults int64) ([]GetDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDeliveries, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDeliveriesRow
+	for rows.Next() {
+		var i GetDeliveriesRow
+		if err := rows.Scan(
+			&i.WebhookDelivery.ID,
+			&i.WebhookDelivery.WebhookID,
+			&i.WebhookDelivery.Event,
+			&i.WebhookDelivery.Payload,
+			&i.WebhookDelivery.Attempts,
+			&i.WebhookDelivery.NextAttemptAt,
+			&i.WebhookDelivery.LastStatus,
+			&i.WebhookDelivery.LastError,
+			&i.WebhookDelivery.DeliveredAt,
+			&i.WebhookDelivery.CreatedAt,
+			&i.Url,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDrink = `-- name: GetDrink :one
+SELECT id, user_id, beer_id, serving_ml, drunk_at, drunk_on, created_at
+FROM drinks
+WHERE id = $1
+`
+
+func (q *Queries) GetDrink(ctx context.Context, id int64) (Drink, error) {
+	row := q.db.QueryRowContext(ctx, getDrink, id)
+	var i Drink
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BeerID,
+		&i.ServingMl,
+		&i.DrunkAt,
+		&i.DrunkOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDrinkingSession = `-- name: GetDrinkingSession :one
+SELECT id, name, created_by, started_at, ended_at, share_token, created_at FROM drinking_sessions
+WHERE id = $1
+`
+
+func (q *Queries) GetDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error) {
+	row := q.db.QueryRowContext(ctx, getDrinkingSession, id)
+	var i DrinkingSession
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ShareToken,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDrinkingSessionByToken = `-- name: GetDrinkingSessionByToken :one
+SELECT id, name, created_by, started_at, ended_at, share_token, created_at FROM drinking_sessions
+WHERE share_token = $1
+`
+
+func (q *Queries) GetDrinkingSessionByToken(ctx context.Context, shareToken string) (DrinkingSession, error) {
+	row := q.db.QueryRowContext(ctx, getDrinkingSessionByToken, shareToken)
+	var i DrinkingSession
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ShareToken,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDrinksPerDay = `-- name: GetDrinksPerDay :many
+
+SELECT
+    drinks.drunk_on,
+    COUNT(*) AS drinks,
+    CAST(SUM(drinks.serving_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+GROUP BY drinks.drunk_on
+ORDER BY drinks.drunk_on
+`
+
+type GetDrinksPerDayParams struct {
+	UserID int64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetDrinksPerDayRow struct {
+	DrunkOn   time.Time
+	Drinks    int64
+	AlcoholMl float64
+}
+
+// === STATS ===
+func (q *Queries) GetDrinksPerDay(ctx context.Context, arg GetDrinksPerDayParams) ([]GetDrinksPerDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDrinksPerDay, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDrinksPerDayRow
+	for rows.Next() {
+		var i GetDrinksPerDayRow
+		if err := rows.Scan(&i.DrunkOn, &i.Drinks, &i.AlcoholMl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueDeliveries = `-- name: GetDueDeliveries :many
+SELECT webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.last_status, webhook_deliveries.last_error, webhook_deliveries.delivered_at, webhook_deliveries.created_at, webhooks.url, webhooks.secret
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.next_attempt_at IS NOT NULL AND webhook_deliveries.next_attempt_at <= $1
+ORDER BY webhook_deliveries.next_attempt_at, webhook_deliveries.id
+LIMIT $2::bigint
+`
+
+type GetDueDeliveriesParams struct {
+	Now        sql.NullTime
+	MaxResults int64
+}
+
+type GetDueDeliveriesRow struct {
+	WebhookDelivery WebhookDelivery
+	Url             string
+	Secret          string
+}
+
+// Deliveries to try now, with where to send them and the secret to sign them with
+func (q *Queries) GetDueDeliveries(ctx context.Context, arg GetDueDeliveriesParams) ([]GetDueDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDueDeliveries, arg.Now, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDueDeliveriesRow
+	for rows.Next() {
+		var i GetDueDeliveriesRow
+		if err := rows.Scan(
+			&i.WebhookDelivery.ID,
+			&i.WebhookDelivery.WebhookID,
+			&i.WebhookDelivery.Event,
+			&i.WebhookDelivery.Payload,
+			&i.WebhookDelivery.Attempts,
+			&i.WebhookDelivery.NextAttemptAt,
+			&i.WebhookDelivery.LastStatus,
+			&i.WebhookDelivery.LastError,
+			&i.WebhookDelivery.DeliveredAt,
+			&i.WebhookDelivery.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueEmails = `-- name: GetDueEmails :many
+SELECT id, to_address, subject, text_body, html_body, attempts, next_attempt_at, last_error, sent_at, created_at
+FROM outbox
+WHERE next_attempt_at IS NOT NULL AND next_attempt_at <= $1
+ORDER BY next_attempt_at, id
+LIMIT $2::bigint
+`
+
+type GetDueEmailsParams struct {
+	Now        sql.NullTime
+	MaxResults int64
+}
+
+func (q *Queries) GetDueEmails(ctx context.Context, arg GetDueEmailsParams) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, getDueEmails, arg.Now, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.ToAddress,
+			&i.Subject,
+			&i.TextBody,
+			&i.HtmlBody,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeed = `-- name: GetFeed :many
+SELECT activities.id, activities.user_id, activities.kind, activities.beer_id, activities.brewer_id, activities.created_at, users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+JOIN follows ON follows.followee_id = activities.user_id AND follows.follower_id = $1::bigint
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.id < $2::bigint
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = $1::bigint
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT $3::bigint
+`
+
+type GetFeedParams struct {
+	ViewerID   int64
+	Before     int64
+	MaxResults int64
+}
+
+type GetFeedRow struct {
+	Activity   Activity
+	Username   string
+	BeerName   sql.NullString
+	BrewerName sql.NullString
+}
+
+// The activity of the users the viewer follows, newest first, from before the given id. Only
+// activity the viewer may see is included: that of public users, and that of users who keep it to
+// their friends when they and the viewer follow each other. Deleted users, beers and brewers are
+// left out.
+func (q *Queries) GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeed, arg.ViewerID, arg.Before, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedRow
+	for rows.Next() {
+		var i GetFeedRow
+		if err := rows.Scan(
+			&i.Activity.ID,
+			&i.Activity.UserID,
+			&i.Activity.Kind,
+			&i.Activity.BeerID,
+			&i.Activity.BrewerID,
+			&i.Activity.CreatedAt,
+			&i.Username,
+			&i.BeerName,
+			&i.BrewerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowers = `-- name: GetFollowers :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = $1 AND users.deleted_at IS NULL
+ORDER BY users.username
+`
+
+type GetFollowersRow struct {
+	ID       int64
+	Username string
+}
+
+// Who follows the user, leaving out deleted users
+func (q *Queries) GetFollowers(ctx context.Context, followeeID int64) ([]GetFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowers, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowersRow
+	for rows.Next() {
+		var i GetFollowersRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowing = `-- name: GetFollowing :many
+SELECT users.id, users.username
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = $1 AND users.deleted_at IS NULL
+ORDER BY users.username
+`
+
+type GetFollowingRow struct {
+	ID       int64
+	Username string
+}
+
+// Who the user follows, leaving out deleted users
+func (q *Queries) GetFollowing(ctx context.Context, followerID int64) ([]GetFollowingRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowing, followerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowingRow
+	for rows.Next() {
+		var i GetFollowingRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFridge = `-- name: GetFridge :many
+SELECT stock.id, stock.beer_id, stock.user_id, stock.quantity, stock.bought, stock.container_ml, stock.purchased_on, stock.best_before, stock.price, stock.currency, stock.created_at, beers.name AS beer_name
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+ORDER BY stock.best_before IS NULL, stock.best_before, stock.purchased_on, stock.id
+`
+
+type GetFridgeRow struct {
+	Stock    Stock
+	BeerName string
+}
+
+// Soonest best-before first, with the entries which don't have one last
+func (q *Queries) GetFridge(ctx context.Context) ([]GetFridgeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFridge)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFridgeRow
+	for rows.Next() {
+		var i GetFridgeRow
+		if err := rows.Scan(
+			&i.Stock.ID,
+			&i.Stock.BeerID,
+			&i.Stock.UserID,
+			&i.Stock.Quantity,
+			&i.Stock.Bought,
+			&i.Stock.ContainerMl,
+			&i.Stock.PurchasedOn,
+			&i.Stock.BestBefore,
+			&i.Stock.Price,
+			&i.Stock.Currency,
+			&i.Stock.CreatedAt,
+			&i.BeerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGoals = `-- name: GetGoals :one
+SELECT user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone, updated_at
+FROM goals
+WHERE user_id = $1
+`
+
+func (q *Queries) GetGoals(ctx context.Context, userID int64) (Goal, error) {
+	row := q.db.QueryRowContext(ctx, getGoals, userID)
+	var i Goal
+	err := row.Scan(
+		&i.UserID,
+		&i.WeeklyLimit,
+		&i.AlcoholFreeDays,
+		&i.ConfirmOverLimit,
+		&i.TimeZone,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLabelPhoto = `-- name: GetLabelPhoto :one
+SELECT id, beer_id, user_id, content_type, size, width, height, blob_key, thumb_key, created_at
+FROM label_photos
+WHERE id = $1
+`
+
+func (q *Queries) GetLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error) {
+	row := q.db.QueryRowContext(ctx, getLabelPhoto, id)
+	var i LabelPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.ContentType,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.BlobKey,
+		&i.ThumbKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOutbox = `-- name: GetOutbox :many
+SELECT id, to_address, subject, text_body, html_body, attempts, next_attempt_at, last_error, sent_at, created_at
+FROM outbox
+ORDER BY id DESC
+LIMIT $1::bigint
+`
+
+// The latest emails first, for seeing what's been sent and what's stuck
+func (q *Queries) GetOutbox(ctx context.Context, maxResults int64) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, getOutbox, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.ToAddress,
+			&i.Subject,
+			&i.TextBody,
+			&i.HtmlBody,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPasswordReset = `-- name: GetPasswordReset :one
+SELECT token_hash, user_id, expires_at, used_at, created_at
+FROM password_resets
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetPasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, getPasswordReset, tokenHash)
+	var i PasswordReset
+	err := row.Scan(
+		&i.TokenHash,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPours = `-- name: GetPours :many
+SELECT tasting_pours.tasting_id, tasting_pours.number, tasting_pours.beer_id, beers.name AS beer_name, brewers.name AS brewer_name
+FROM tasting_pours
+JOIN beers ON beers.id = tasting_pours.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE tasting_pours.tasting_id = $1
+ORDER BY tasting_pours.number
+`
+
+type GetPoursRow struct {
+	TastingID  int64
+	Number     int64
+	BeerID     int64
+	BeerName   string
+	BrewerName sql.NullString
+}
+
+func (q *Queries) GetPours(ctx context.Context, tastingID int64) ([]GetPoursRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPours, tastingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPoursRow
+	for rows.Next() {
+		var i GetPoursRow
+		if err := rows.Scan(
+			&i.TastingID,
+			&i.Number,
+			&i.BeerID,
+			&i.BeerName,
+			&i.BrewerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPrivacy = `-- name: GetPrivacy :one
+SELECT user_id, visibility, updated_at
+FROM user_privacy
+WHERE user_id = $1
+`
+
+func (q *Queries) GetPrivacy(ctx context.Context, userID int64) (UserPrivacy, error) {
+	row := q.db.QueryRowContext(ctx, getPrivacy, userID)
+	var i UserPrivacy
+	err := row.Scan(&i.UserID, &i.Visibility, &i.UpdatedAt)
+	return i, err
+}
+
+const getPurchases = `-- name: GetPurchases :many
+SELECT
+    stock.id, stock.beer_id, stock.user_id, stock.quantity, stock.bought, stock.container_ml, stock.purchased_on, stock.best_before, stock.price, stock.currency, stock.created_at,
+    beers.name AS beer_name,
+    beers.abv,
+    beers.style,
+    brewers.name AS brewer_name,
+    users.username
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.purchased_on >= $1
+ORDER BY stock.purchased_on, stock.id
+`
+
+type GetPurchasesRow struct {
+	Stock      Stock
+	BeerName   string
+	Abv        float64
+	Style      sql.NullString
+	BrewerName sql.NullString
+	Username   sql.NullString
+}
+
+func (q *Queries) GetPurchases(ctx context.Context, purchasedOn time.Time) ([]GetPurchasesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPurchases, purchasedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPurchasesRow
+	for rows.Next() {
+		var i GetPurchasesRow
+		if err := rows.Scan(
+			&i.Stock.ID,
+			&i.Stock.BeerID,
+			&i.Stock.UserID,
+			&i.Stock.Quantity,
+			&i.Stock.Bought,
+			&i.Stock.ContainerMl,
+			&i.Stock.PurchasedOn,
+			&i.Stock.BestBefore,
+			&i.Stock.Price,
+			&i.Stock.Currency,
+			&i.Stock.CreatedAt,
+			&i.BeerName,
+			&i.Abv,
+			&i.Style,
+			&i.BrewerName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatedBeerFacts = `-- name: GetRatedBeerFacts :many
+SELECT scorecards.user_id, users.username, beers.id AS beer_id, beers.style, styles.category AS style_category, beers.brewer_id, brewers.location AS brewer_location, scorecards.created_at AS at
+FROM scorecards
+JOIN users ON users.id = scorecards.user_id
+JOIN beers ON beers.id = scorecards.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+LEFT JOIN styles ON lower(styles.name) = lower(beers.style)
+WHERE beers.deleted_at IS NULL AND users.deleted_at IS NULL
+    AND (scorecards.user_id = $1 OR $1 IS NULL)
+    AND scorecards.created_at >= $2 AND scorecards.created_at < $3
+ORDER BY scorecards.created_at, beers.id
+`
+
+type GetRatedBeerFactsParams struct {
+	UserID sql.NullInt64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetRatedBeerFactsRow struct {
+	UserID         int64
+	Username       string
+	BeerID         int64
+	Style          sql.NullString
+	StyleCategory  sql.NullString
+	BrewerID       sql.NullInt64
+	BrewerLocation sql.NullString
+	At             time.Time
+}
+
+func (q *Queries) GetRatedBeerFacts(ctx context.Context, arg GetRatedBeerFactsParams) ([]GetRatedBeerFactsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRatedBeerFacts, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatedBeerFactsRow
+	for rows.Next() {
+		var i GetRatedBeerFactsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.BeerID,
+			&i.Style,
+			&i.StyleCategory,
+			&i.BrewerID,
+			&i.BrewerLocation,
+			&i.At,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingHistogram = `-- name: GetRatingHistogram :many
+SELECT
+    CAST(FLOOR(beers.rating) AS BIGINT) AS rating,
+    COUNT(DISTINCT beers.id) AS beers
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+    AND beers.rating IS NOT NULL
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetRatingHistogramParams struct {
+	UserID int64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetRatingHistogramRow struct {
+	Rating int64
+	Beers  int64
+}
+
+// The different beers drunk, counted by the whole number of their rating, e.g. 7.5 is counted
+// under 7
+func (q *Queries) GetRatingHistogram(ctx context.Context, arg GetRatingHistogramParams) ([]GetRatingHistogramRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRatingHistogram, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingHistogramRow
+	for rows.Next() {
+		var i GetRatingHistogramRow
+		if err := rows.Scan(&i.Rating, &i.Beers); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReactions = `-- name: GetReactions :many
+SELECT comment_reactions.comment_id, comment_reactions.user_id, comment_reactions.emoji
+FROM comment_reactions
+JOIN comments ON comments.id = comment_reactions.comment_id
+JOIN users ON users.id = comment_reactions.user_id
+WHERE (comments.beer_id = $1 OR comments.brewer_id = $2)
+    AND users.deleted_at IS NULL
+ORDER BY comment_reactions.comment_id, comment_reactions.created_at
+`
+
+type GetReactionsParams struct {
+	BeerID   sql.NullInt64
+	BrewerID sql.NullInt64
+}
+
+type GetReactionsRow struct {
+	CommentID int64
+	UserID    int64
+	Emoji     string
+}
+
+// The reactions to the comments on the beer or brewer, leaving out those of deleted users. Only
+// one of the ids is given.
+func (q *Queries) GetReactions(ctx context.Context, arg GetReactionsParams) ([]GetReactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReactions, arg.BeerID, arg.BrewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReactionsRow
+	for rows.Next() {
+		var i GetReactionsRow
+		if err := rows.Scan(&i.CommentID, &i.UserID, &i.Emoji); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRound = `-- name: GetRound :one
+SELECT id, session_id, bought_by, cost, currency, bought_at, created_at FROM rounds
+WHERE id = $1
+`
+
+func (q *Queries) GetRound(ctx context.Context, id int64) (Round, error) {
+	row := q.db.QueryRowContext(ctx, getRound, id)
+	var i Round
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.BoughtBy,
+		&i.Cost,
+		&i.Currency,
+		&i.BoughtAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSchedule = `-- name: GetSchedule :one
+SELECT id, user_id, weekday, minute, remind_before, reminded_at, created_at
+FROM schedules
+WHERE id = $1
+`
+
+func (q *Queries) GetSchedule(ctx context.Context, id int64) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, getSchedule, id)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Weekday,
+		&i.Minute,
+		&i.RemindBefore,
+		&i.RemindedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getScorecard = `-- name: GetScorecard :one
+SELECT id, beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, updated_at, created_at
+FROM scorecards
+WHERE beer_id = $1 AND user_id = $2
+`
+
+type GetScorecardParams struct {
+	BeerID int64
+	UserID int64
+}
+
+func (q *Queries) GetScorecard(ctx context.Context, arg GetScorecardParams) (Scorecard, error) {
+	row := q.db.QueryRowContext(ctx, getScorecard, arg.BeerID, arg.UserID)
+	var i Scorecard
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+		&i.Total,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getScorecardWeights = `-- name: GetScorecardWeights :one
+
+SELECT id, aroma, appearance, flavour, mouthfeel, overall
+FROM scorecard_weights
+WHERE id = 1
+`
+
+// === SCORECARDS ===
+func (q *Queries) GetScorecardWeights(ctx context.Context) (ScorecardWeight, error) {
+	row := q.db.QueryRowContext(ctx, getScorecardWeights)
+	var i ScorecardWeight
+	err := row.Scan(
+		&i.ID,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+	)
+	return i, err
+}
+
+const getScorecards = `-- name: GetScorecards :many
+SELECT id, beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, updated_at, created_at
+FROM scorecards
+ORDER BY id
+`
+
+func (q *Queries) GetScorecards(ctx context.Context) ([]Scorecard, error) {
+	rows, err := q.db.QueryContext(ctx, getScorecards)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Scorecard
+	for rows.Next() {
+		var i Scorecard
+		if err := rows.Scan(
+			&i.ID,
+			&i.BeerID,
+			&i.UserID,
+			&i.Aroma,
+			&i.Appearance,
+			&i.Flavour,
+			&i.Mouthfeel,
+			&i.Overall,
+			&i.Total,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionDrinks = `-- name: GetSessionDrinks :many
+SELECT drinks.id, drinks.user_id, drinks.beer_id, drinks.serving_ml, drinks.drunk_at, drinks.drunk_on, drinks.created_at, beers.name AS beer_name, beers.abv, users.username
+FROM drinking_session_drinks
+JOIN drinks ON drinks.id = drinking_session_drinks.drink_id
+JOIN beers ON beers.id = drinks.beer_id
+JOIN users ON users.id = drinks.user_id
+WHERE drinking_session_drinks.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY drinks.drunk_at, drinks.id
+`
+
+type GetSessionDrinksRow struct {
+	Drink    Drink
+	BeerName string
+	Abv      float64
+	Username string
+}
+
+// The drinks had in the session in the order they were had, with who had them, leaving out
+// deleted users like the participants do
+func (q *Queries) GetSessionDrinks(ctx context.Context, sessionID int64) ([]GetSessionDrinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionDrinks, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionDrinksRow
+	for rows.Next() {
+		var i GetSessionDrinksRow
+		if err := rows.Scan(
+			&i.Drink.ID,
+			&i.Drink.UserID,
+			&i.Drink.BeerID,
+			&i.Drink.ServingMl,
+			&i.Drink.DrunkAt,
+			&i.Drink.DrunkOn,
+			&i.Drink.CreatedAt,
+			&i.BeerName,
+			&i.Abv,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionParticipants = `-- name: GetSessionParticipants :many
+SELECT users.id AS user_id, users.username, drinking_session_participants.joined_at
+FROM drinking_session_participants
+JOIN users ON users.id = drinking_session_participants.user_id
+WHERE drinking_session_participants.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY drinking_session_participants.joined_at, users.id
+`
+
+type GetSessionParticipantsRow struct {
+	UserID   int64
+	Username string
+	JoinedAt time.Time
+}
+
+// The session's participants in the order they joined, leaving out deleted users
+func (q *Queries) GetSessionParticipants(ctx context.Context, sessionID int64) ([]GetSessionParticipantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionParticipants, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionParticipantsRow
+	for rows.Next() {
+		var i GetSessionParticipantsRow
+		if err := rows.Scan(&i.UserID, &i.Username, &i.JoinedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionRoundRecipients = `-- name: GetSessionRoundRecipients :many
+SELECT round_recipients.round_id, round_recipients.user_id, users.username
+FROM round_recipients
+JOIN rounds ON rounds.id = round_recipients.round_id
+JOIN users ON users.id = round_recipients.user_id
+WHERE rounds.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY round_recipients.round_id, round_recipients.user_id
+`
+
+type GetSessionRoundRecipientsRow struct {
+	RoundID  int64
+	UserID   int64
+	Username string
+}
+
+// Who each round in the session was bought for, leaving out deleted users
+func (q *Queries) GetSessionRoundRecipients(ctx context.Context, sessionID int64) ([]GetSessionRoundRecipientsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionRoundRecipients, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionRoundRecipientsRow
+	for rows.Next() {
+		var i GetSessionRoundRecipientsRow
+		if err := rows.Scan(&i.RoundID, &i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionRounds = `-- name: GetSessionRounds :many
+SELECT rounds.id, rounds.session_id, rounds.bought_by, rounds.cost, rounds.currency, rounds.bought_at, rounds.created_at, users.username
+FROM rounds
+JOIN users ON users.id = rounds.bought_by
+WHERE rounds.session_id = $1 AND users.deleted_at IS NULL
+ORDER BY rounds.bought_at, rounds.id
+`
+
+type GetSessionRoundsRow struct {
+	Round    Round
+	Username string
+}
+
+// The rounds bought in the session with who bought them, in the order they were bought, leaving
+// out those bought by deleted users
+func (q *Queries) GetSessionRounds(ctx context.Context, sessionID int64) ([]GetSessionRoundsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionRounds, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionRoundsRow
+	for rows.Next() {
+		var i GetSessionRoundsRow
+		if err := rows.Scan(
+			&i.Round.ID,
+			&i.Round.SessionID,
+			&i.Round.BoughtBy,
+			&i.Round.Cost,
+			&i.Round.Currency,
+			&i.Round.BoughtAt,
+			&i.Round.CreatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionVenues = `-- name: GetSessionVenues :many
+SELECT id, session_id, name, created_at FROM drinking_session_venues
+WHERE session_id = $1
+ORDER BY id
+`
+
+func (q *Queries) GetSessionVenues(ctx context.Context, sessionID int64) ([]DrinkingSessionVenue, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionVenues, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DrinkingSessionVenue
+	for rows.Next() {
+		var i DrinkingSessionVenue
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Name,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSpendingByBrewer = `-- name: GetSpendingByBrewer :many
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(brewers.name, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3
+`
+
+type GetSpendingByBrewerRow struct {
+	Month      string
+	Currency   string
+	Name       string
+	Containers int64
+	Total      float64
+	AlcoholMl  float64
+}
+
+func (q *Queries) GetSpendingByBrewer(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByBrewerRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSpendingByBrewer, purchasedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSpendingByBrewerRow
+	for rows.Next() {
+		var i GetSpendingByBrewerRow
+		if err := rows.Scan(
+			&i.Month,
+			&i.Currency,
+			&i.Name,
+			&i.Containers,
+			&i.Total,
+			&i.AlcoholMl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSpendingByStyle = `-- name: GetSpendingByStyle :many
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(beers.style, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3
+`
+
+type GetSpendingByStyleRow struct {
+	Month      string
+	Currency   string
+	Name       string
+	Containers int64
+	Total      float64
+	AlcoholMl  float64
+}
+
+func (q *Queries) GetSpendingByStyle(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByStyleRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSpendingByStyle, purchasedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSpendingByStyleRow
+	for rows.Next() {
+		var i GetSpendingByStyleRow
+		if err := rows.Scan(
+			&i.Month,
+			&i.Currency,
+			&i.Name,
+			&i.Containers,
+			&i.Total,
+			&i.AlcoholMl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSpendingByUser = `-- name: GetSpendingByUser :many
+
+
+SELECT
+    to_char(stock.purchased_on, 'YYYY-MM') AS month,
+    stock.currency,
+    COALESCE(users.username, '') AS name,
+    CAST(SUM(stock.bought) AS BIGINT) AS containers,
+    CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total,
+    CAST(SUM(stock.bought * stock.container_ml * beers.abv / 100.0) AS DOUBLE PRECISION) AS alcohol_ml
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+LEFT JOIN users ON users.id = stock.user_id
+WHERE stock.price IS NOT NULL AND stock.purchased_on >= $1
+GROUP BY 1, stock.currency, 3
+ORDER BY 1 DESC, total DESC, 3
+`
+
+type GetSpendingByUserRow struct {
+	Month      string
+	Currency   string
+	Name       string
+	Containers int64
+	Total      float64
+	AlcoholMl  float64
+}
+
+// === SPENDING ===
+// Beers in the trash still count, since the money's been spent
+func (q *Queries) GetSpendingByUser(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSpendingByUser, purchasedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSpendingByUserRow
+	for rows.Next() {
+		var i GetSpendingByUserRow
+		if err := rows.Scan(
+			&i.Month,
+			&i.Currency,
+			&i.Name,
+			&i.Containers,
+			&i.Total,
+			&i.AlcoholMl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStock = `-- name: GetStock :one
+SELECT id, beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency, created_at
+FROM stock
+WHERE id = $1
+`
+
+func (q *Queries) GetStock(ctx context.Context, id int64) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, getStock, id)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Quantity,
+		&i.Bought,
+		&i.ContainerMl,
+		&i.PurchasedOn,
+		&i.BestBefore,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStockLevels = `-- name: GetStockLevels :many
+SELECT stock.beer_id, CAST(SUM(stock.quantity) AS BIGINT) AS quantity
+FROM stock
+JOIN beers ON beers.id = stock.beer_id
+WHERE beers.deleted_at IS NULL AND stock.quantity > 0
+GROUP BY stock.beer_id
+ORDER BY stock.beer_id
+`
+
+type GetStockLevelsRow struct {
+	BeerID   int64
+	Quantity int64
+}
+
+func (q *Queries) GetStockLevels(ctx context.Context) ([]GetStockLevelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStockLevels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStockLevelsRow
+	for rows.Next() {
+		var i GetStockLevelsRow
+		if err := rows.Scan(&i.BeerID, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStyleById = `-- name: GetStyleById :one
+SELECT id, code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description
+FROM styles
+WHERE id = $1
+`
+
+func (q *Queries) GetStyleById(ctx context.Context, id int64) (Style, error) {
+	row := q.db.QueryRowContext(ctx, getStyleById, id)
+	var i Style
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Category,
+		&i.Name,
+		&i.AbvMin,
+		&i.AbvMax,
+		&i.IbuMin,
+		&i.IbuMax,
+		&i.SrmMin,
+		&i.SrmMax,
+		&i.Description,
+	)
+	return i, err
+}
+
+const getStyleByName = `-- name: GetStyleByName :one
+SELECT id, code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description
+FROM styles
+WHERE lower(name) = lower($1)
+`
+
+func (q *Queries) GetStyleByName(ctx context.Context, name string) (Style, error) {
+	row := q.db.QueryRowContext(ctx, getStyleByName, name)
+	var i Style
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Category,
+		&i.Name,
+		&i.AbvMin,
+		&i.AbvMax,
+		&i.IbuMin,
+		&i.IbuMax,
+		&i.SrmMin,
+		&i.SrmMax,
+		&i.Description,
+	)
+	return i, err
+}
+
+const getStyles = `-- name: GetStyles :many
+SELECT id, code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description
+FROM styles
+ORDER BY id
+`
+
+func (q *Queries) GetStyles(ctx context.Context) ([]Style, error) {
+	rows, err := q.db.QueryContext(ctx, getStyles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Style
+	for rows.Next() {
+		var i Style
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Category,
+			&i.Name,
+			&i.AbvMin,
+			&i.AbvMax,
+			&i.IbuMin,
+			&i.IbuMax,
+			&i.SrmMin,
+			&i.SrmMax,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, name, category
+FROM tags
+WHERE name = $1
+`
+
+func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByName, name)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Name, &i.Category)
+	return i, err
+}
+
+const getTagCounts = `-- name: GetTagCounts :many
+SELECT tags.id, tags.name, tags.category, COUNT(beers.id) AS beer_count
+FROM tags
+LEFT JOIN beer_tags ON beer_tags.tag_id = tags.id
+LEFT JOIN beers ON beers.id = beer_tags.beer_id AND beers.deleted_at IS NULL
+GROUP BY tags.id
+ORDER BY tags.name
+`
+
+type GetTagCountsRow struct {
+	Tag       Tag
+	BeerCount int64
+}
+
+func (q *Queries) GetTagCounts(ctx context.Context) ([]GetTagCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagCountsRow
+	for rows.Next() {
+		var i GetTagCountsRow
+		if err := rows.Scan(
+			&i.Tag.ID,
+			&i.Tag.Name,
+			&i.Tag.Category,
+			&i.BeerCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTags = `-- name: GetTags :many
+SELECT id, name, category
+FROM tags
+ORDER BY name
+`
+
+func (q *Queries) GetTags(ctx context.Context) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(&i.ID, &i.Name, &i.Category); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasting = `-- name: GetTasting :one
+SELECT id, name, organiser_id, held_at, revealed_at, created_at
+FROM tastings
+WHERE id = $1
+`
+
+func (q *Queries) GetTasting(ctx context.Context, id int64) (Tasting, error) {
+	row := q.db.QueryRowContext(ctx, getTasting, id)
+	var i Tasting
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganiserID,
+		&i.HeldAt,
+		&i.RevealedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTastingScorecards = `-- name: GetTastingScorecards :many
+SELECT tasting_scorecards.tasting_id, tasting_scorecards.number, tasting_scorecards.user_id, tasting_scorecards.aroma, tasting_scorecards.appearance, tasting_scorecards.flavour, tasting_scorecards.mouthfeel, tasting_scorecards.overall, tasting_scorecards.updated_at, users.username
+FROM tasting_scorecards
+JOIN users ON users.id = tasting_scorecards.user_id
+WHERE tasting_scorecards.tasting_id = $1 AND users.deleted_at IS NULL
+ORDER BY tasting_scorecards.number, users.username
+`
+
+type GetTastingScorecardsRow struct {
+	TastingScorecard TastingScorecard
+	Username         string
+}
+
+func (q *Queries) GetTastingScorecards(ctx context.Context, tastingID int64) ([]GetTastingScorecardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTastingScorecards, tastingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTastingScorecardsRow
+	for rows.Next() {
+		var i GetTastingScorecardsRow
+		if err := rows.Scan(
+			&i.TastingScorecard.TastingID,
+			&i.TastingScorecard.Number,
+			&i.TastingScorecard.UserID,
+			&i.TastingScorecard.Aroma,
+			&i.TastingScorecard.Appearance,
+			&i.TastingScorecard.Flavour,
+			&i.TastingScorecard.Mouthfeel,
+			&i.TastingScorecard.Overall,
+			&i.TastingScorecard.UpdatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTastings = `-- name: GetTastings :many
+SELECT tastings.id, tastings.name, tastings.organiser_id, tastings.held_at, tastings.revealed_at, tastings.created_at, users.username
+FROM tastings
+LEFT JOIN users ON users.id = tastings.organiser_id
+ORDER BY tastings.held_at DESC, tastings.id DESC
+`
+
+type GetTastingsRow struct {
+	Tasting  Tasting
+	Username sql.NullString
+}
+
+func (q *Queries) GetTastings(ctx context.Context) ([]GetTastingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTastings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTastingsRow
+	for rows.Next() {
+		var i GetTastingsRow
+		if err := rows.Scan(
+			&i.Tasting.ID,
+			&i.Tasting.Name,
+			&i.Tasting.OrganiserID,
+			&i.Tasting.HeldAt,
+			&i.Tasting.RevealedAt,
+			&i.Tasting.CreatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTopBrewers = `-- name: GetTopBrewers :many
+SELECT
+    brewers.name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+JOIN brewers ON brewers.id = beers.brewer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+GROUP BY brewers.id, brewers.name
+ORDER BY drinks DESC, brewers.name
+LIMIT $4::bigint
+`
+
+type GetTopBrewersParams struct {
+	UserID     int64
+	Since      time.Time
+	Until      time.Time
+	MaxResults int64
+}
+
+type GetTopBrewersRow struct {
+	Name   string
+	Drinks int64
+}
+
+func (q *Queries) GetTopBrewers(ctx context.Context, arg GetTopBrewersParams) ([]GetTopBrewersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopBrewers,
+		arg.UserID,
+		arg.Since,
+		arg.Until,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopBrewersRow
+	for rows.Next() {
+		var i GetTopBrewersRow
+		if err := rows.Scan(&i.Name, &i.Drinks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTopStyles = `-- name: GetTopStyles :many
+SELECT
+    beers.style AS name,
+    COUNT(*) AS drinks
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+    AND beers.style IS NOT NULL
+GROUP BY beers.style
+ORDER BY drinks DESC, beers.style
+LIMIT $4::bigint
+`
+
+type GetTopStylesParams struct {
+	UserID     int64
+	Since      time.Time
+	Until      time.Time
+	MaxResults int64
+}
+
+type GetTopStylesRow struct {
+	Name   sql.NullString
+	Drinks int64
+}
+
+func (q *Queries) GetTopStyles(ctx context.Context, arg GetTopStylesParams) ([]GetTopStylesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopStyles,
+		arg.UserID,
+		arg.Since,
+		arg.Until,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopStylesRow
+	for rows.Next() {
+		var i GetTopStylesRow
+		if err := rows.Scan(&i.Name, &i.Drinks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActivities = `-- name: GetUserActivities :many
+SELECT activities.id, activities.user_id, activities.kind, activities.beer_id, activities.brewer_id, activities.created_at, users.username, beers.name AS beer_name, brewers.name AS brewer_name
+FROM activities
+JOIN users ON users.id = activities.user_id
+LEFT JOIN user_privacy ON user_privacy.user_id = activities.user_id
+LEFT JOIN beers ON beers.id = activities.beer_id
+LEFT JOIN brewers ON brewers.id = activities.brewer_id
+WHERE activities.user_id = $1::bigint
+    AND activities.id < $2::bigint
+    AND users.deleted_at IS NULL
+    AND beers.deleted_at IS NULL
+    AND brewers.deleted_at IS NULL
+    AND (
+        activities.user_id = $3::bigint
+        OR user_privacy.visibility IS NULL
+        OR user_privacy.visibility = 'public'
+        OR (
+            user_privacy.visibility = 'friends'
+            AND EXISTS (
+                SELECT 1 FROM follows AS back
+                WHERE back.follower_id = activities.user_id AND back.followee_id = $3::bigint
+            )
+            AND EXISTS (
+                SELECT 1 FROM follows AS forth
+                WHERE forth.follower_id = $3::bigint AND forth.followee_id = activities.user_id
+            )
+        )
+    )
+ORDER BY activities.id DESC
+LIMIT $4::bigint
+`
+
+type GetUserActivitiesParams struct {
+	UserID     int64
+	Before     int64
+	ViewerID   int64
+	MaxResults int64
+}
+
+type GetUserActivitiesRow struct {
+	Activity   Activity
+	Username   string
+	BeerName   sql.NullString
+	BrewerName sql.NullString
+}
+
+// The user's activity as the viewer may see it, newest first, from before the given id. Users
+// always see their own activity; others see it as they would in their feed.
+func (q *Queries) GetUserActivities(ctx context.Context, arg GetUserActivitiesParams) ([]GetUserActivitiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserActivities,
+		arg.UserID,
+		arg.Before,
+		arg.ViewerID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserActivitiesRow
+	for rows.Next() {
+		var i GetUserActivitiesRow
+		if err := rows.Scan(
+			&i.Activity.ID,
+			&i.Activity.UserID,
+			&i.Activity.Kind,
+			&i.Activity.BeerID,
+			&i.Activity.BrewerID,
+			&i.Activity.CreatedAt,
+			&i.Username,
+			&i.BeerName,
+			&i.BrewerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserById = `-- name: GetUserById :one
+SELECT id, username, password_hash, is_admin, created_at, last_login, deleted_at 
+FROM users
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserById(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserById, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, username, password_hash, is_admin, created_at, last_login, deleted_at
+FROM users
+WHERE username = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserCheckIns = `-- name: GetUserCheckIns :many
+SELECT check_ins.id, check_ins.user_id, check_ins.beer_id, check_ins.venue_id, check_ins.checked_in_at, check_ins.created_at, beers.name AS beer_name, venues.name AS venue_name
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN venues ON venues.id = check_ins.venue_id
+WHERE check_ins.user_id = $1
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT $2::bigint
+`
+
+type GetUserCheckInsParams struct {
+	UserID     int64
+	MaxResults int64
+}
+
+type GetUserCheckInsRow struct {
+	CheckIn   CheckIn
+	BeerName  string
+	VenueName string
+}
+
+// The user's check-ins with where they were and what they had, latest first
+func (q *Queries) GetUserCheckIns(ctx context.Context, arg GetUserCheckInsParams) ([]GetUserCheckInsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserCheckIns, arg.UserID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserCheckInsRow
+	for rows.Next() {
+		var i GetUserCheckInsRow
+		if err := rows.Scan(
+			&i.CheckIn.ID,
+			&i.CheckIn.UserID,
+			&i.CheckIn.BeerID,
+			&i.CheckIn.VenueID,
+			&i.CheckIn.CheckedInAt,
+			&i.CheckIn.CreatedAt,
+			&i.BeerName,
+			&i.VenueName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserDrinkingSessions = `-- name: GetUserDrinkingSessions :many
+SELECT drinking_sessions.id, drinking_sessions.name, drinking_sessions.created_by, drinking_sessions.started_at, drinking_sessions.ended_at, drinking_sessions.share_token, drinking_sessions.created_at
+FROM drinking_sessions
+JOIN drinking_session_participants ON drinking_session_participants.session_id = drinking_sessions.id
+WHERE drinking_session_participants.user_id = $1
+ORDER BY drinking_sessions.started_at DESC, drinking_sessions.id DESC
+`
+
+// The sessions the user is in, latest first
+func (q *Queries) GetUserDrinkingSessions(ctx context.Context, userID int64) ([]DrinkingSession, error) {
+	rows, err := q.db.QueryContext(ctx, getUserDrinkingSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DrinkingSession
+	for rows.Next() {
+		var i DrinkingSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedBy,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ShareToken,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserDrinks = `-- name: GetUserDrinks :many
+SELECT drinks.id, drinks.user_id, drinks.beer_id, drinks.serving_ml, drinks.drunk_at, drinks.drunk_on, drinks.created_at, beers.name AS beer_name, beers.abv
+FROM drinks
+JOIN beers ON beers.id = drinks.beer_id
+WHERE drinks.user_id = $1 AND drinks.drunk_on >= $2 AND drinks.drunk_on < $3
+ORDER BY drinks.drunk_at DESC, drinks.id DESC
+`
+
+type GetUserDrinksParams struct {
+	UserID int64
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetUserDrinksRow struct {
+	Drink    Drink
+	BeerName string
+	Abv      float64
+}
+
+// The user's drinks on the days from since up to but not including until, latest first. Beers
+// in the trash are still included, since they were still drunk.
+func (q *Queries) GetUserDrinks(ctx context.Context, arg GetUserDrinksParams) ([]GetUserDrinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserDrinks, arg.UserID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserDrinksRow
+	for rows.Next() {
+		var i GetUserDrinksRow
+		if err := rows.Scan(
+			&i.Drink.ID,
+			&i.Drink.UserID,
+			&i.Drink.BeerID,
+			&i.Drink.ServingMl,
+			&i.Drink.DrunkAt,
+			&i.Drink.DrunkOn,
+			&i.Drink.CreatedAt,
+			&i.BeerName,
+			&i.Abv,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserEmail = `-- name: GetUserEmail :one
+SELECT user_id, email, weekly_summary, reminders, summary_sent_on, updated_at
+FROM user_emails
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserEmail(ctx context.Context, userID int64) (UserEmail, error) {
+	row := q.db.QueryRowContext(ctx, getUserEmail, userID)
+	var i UserEmail
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.WeeklySummary,
+		&i.Reminders,
+		&i.SummarySentOn,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserEmailByAddress = `-- name: GetUserEmailByAddress :one
+SELECT user_id, email, weekly_summary, reminders, summary_sent_on, updated_at
+FROM user_emails
+WHERE email = $1
+`
+
+func (q *Queries) GetUserEmailByAddress(ctx context.Context, email string) (UserEmail, error) {
+	row := q.db.QueryRowContext(ctx, getUserEmailByAddress, email)
+	var i UserEmail
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.WeeklySummary,
+		&i.Reminders,
+		&i.SummarySentOn,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserEmails = `-- name: GetUserEmails :many
+SELECT user_emails.user_id, user_emails.email, user_emails.weekly_summary, user_emails.reminders, user_emails.summary_sent_on, user_emails.updated_at, users.username, COALESCE(goals.time_zone, '') AS time_zone
+FROM user_emails
+JOIN users ON users.id = user_emails.user_id
+LEFT JOIN goals ON goals.user_id = user_emails.user_id
+WHERE users.deleted_at IS NULL
+ORDER BY user_emails.user_id
+`
+
+type GetUserEmailsRow struct {
+	UserEmail UserEmail
+	Username  string
+	TimeZone  string
+}
+
+// The addresses of everyone who isn't deleted, with where they are for the weekly summaries
+func (q *Queries) GetUserEmails(ctx context.Context) ([]GetUserEmailsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserEmails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserEmailsRow
+	for rows.Next() {
+		var i GetUserEmailsRow
+		if err := rows.Scan(
+			&i.UserEmail.UserID,
+			&i.UserEmail.Email,
+			&i.UserEmail.WeeklySummary,
+			&i.UserEmail.Reminders,
+			&i.UserEmail.SummarySentOn,
+			&i.UserEmail.UpdatedAt,
+			&i.Username,
+			&i.TimeZone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSchedules = `-- name: GetUserSchedules :many
+SELECT id, user_id, weekday, minute, remind_before, reminded_at, created_at
+FROM schedules
+WHERE user_id = $1
+ORDER BY weekday, minute
+`
+
+func (q *Queries) GetUserSchedules(ctx context.Context, userID int64) ([]Schedule, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSchedules, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Schedule
+	for rows.Next() {
+		var i Schedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Weekday,
+			&i.Minute,
+			&i.RemindBefore,
+			&i.RemindedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSpending = `-- name: GetUserSpending :many
+SELECT stock.currency, CAST(SUM(stock.bought * stock.price) AS DOUBLE PRECISION) AS total
+FROM stock
+WHERE stock.user_id = $1 AND stock.price IS NOT NULL AND stock.purchased_on >= $2
+GROUP BY stock.currency
+ORDER BY stock.currency
+`
+
+type GetUserSpendingParams struct {
+	UserID      sql.NullInt64
+	PurchasedOn time.Time
+}
+
+type GetUserSpendingRow struct {
+	Currency string
+	Total    float64
+}
+
+func (q *Queries) GetUserSpending(ctx context.Context, arg GetUserSpendingParams) ([]GetUserSpendingRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSpending, arg.UserID, arg.PurchasedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSpendingRow
+	for rows.Next() {
+		var i GetUserSpendingRow
+		if err := rows.Scan(&i.Currency, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT id, username, password_hash, is_admin, created_at, last_login, deleted_at
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.PasswordHash,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.LastLogin,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenue = `-- name: GetVenue :one
+SELECT id, name, address, type, latitude, longitude, created_at FROM venues
+WHERE id = $1
+`
+
+func (q *Queries) GetVenue(ctx context.Context, id int64) (Venue, error) {
+	row := q.db.QueryRowContext(ctx, getVenue, id)
+	var i Venue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Type,
+		&i.Latitude,
+		&i.Longitude,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getVenueCheckIns = `-- name: GetVenueCheckIns :many
+SELECT check_ins.id, check_ins.user_id, check_ins.beer_id, check_ins.venue_id, check_ins.checked_in_at, check_ins.created_at, beers.name AS beer_name, users.username
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+JOIN users ON users.id = check_ins.user_id
+WHERE check_ins.venue_id = $1 AND users.deleted_at IS NULL
+ORDER BY check_ins.checked_in_at DESC, check_ins.id DESC
+LIMIT $2::bigint
+`
+
+type GetVenueCheckInsParams struct {
+	VenueID    int64
+	MaxResults int64
+}
+
+type GetVenueCheckInsRow struct {
+	CheckIn  CheckIn
+	BeerName string
+	Username string
+}
+
+// The check-ins at the venue with who had what, latest first, leaving out deleted users
+func (q *Queries) GetVenueCheckIns(ctx context.Context, arg GetVenueCheckInsParams) ([]GetVenueCheckInsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVenueCheckIns, arg.VenueID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVenueCheckInsRow
+	for rows.Next() {
+		var i GetVenueCheckInsRow
+		if err := rows.Scan(
+			&i.CheckIn.ID,
+			&i.CheckIn.UserID,
+			&i.CheckIn.BeerID,
+			&i.CheckIn.VenueID,
+			&i.CheckIn.CheckedInAt,
+			&i.CheckIn.CreatedAt,
+			&i.BeerName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenueTopBeers = `-- name: GetVenueTopBeers :many
+SELECT beers.id AS beer_id, beers.name, COUNT(*) AS check_ins
+FROM check_ins
+JOIN beers ON beers.id = check_ins.beer_id
+WHERE check_ins.venue_id = $1
+GROUP BY beers.id, beers.name
+ORDER BY check_ins DESC, beers.name
+LIMIT $2::bigint
+`
+
+type GetVenueTopBeersParams struct {
+	VenueID    int64
+	MaxResults int64
+}
+
+type GetVenueTopBeersRow struct {
+	BeerID   int64
+	Name     string
+	CheckIns int64
+}
+
+// The beers had most at the venue, most first and then by name
+func (q *Queries) GetVenueTopBeers(ctx context.Context, arg GetVenueTopBeersParams) ([]GetVenueTopBeersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVenueTopBeers, arg.VenueID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVenueTopBeersRow
+	for rows.Next() {
+		var i GetVenueTopBeersRow
+		if err := rows.Scan(&i.BeerID, &i.Name, &i.CheckIns); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenues = `-- name: GetVenues :many
+SELECT id, name, address, type, latitude, longitude, created_at FROM venues
+ORDER BY name, id
+`
+
+func (q *Queries) GetVenues(ctx context.Context) ([]Venue, error) {
+	rows, err := q.db.QueryContext(ctx, getVenues)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Venue
+	for rows.Next() {
+		var i Venue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Address,
+			&i.Type,
+			&i.Latitude,
+			&i.Longitude,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenuesInBox = `-- name: GetVenuesInBox :many
+SELECT id, name, address, type, latitude, longitude, created_at FROM venues
+WHERE latitude >= $1::double precision AND latitude <= $2::double precision
+AND longitude >= $3::double precision AND longitude <= $4::double precision
+`
+
+type GetVenuesInBoxParams struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// The venues with coordinates inside the box, for narrowing down which are near somewhere
+func (q *Queries) GetVenuesInBox(ctx context.Context, arg GetVenuesInBoxParams) ([]Venue, error) {
+	rows, err := q.db.QueryContext(ctx, getVenuesInBox,
+		arg.MinLatitude,
+		arg.MaxLatitude,
+		arg.MinLongitude,
+		arg.MaxLongitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Venue
+	for rows.Next() {
+		var i Venue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Address,
+			&i.Type,
+			&i.Latitude,
+			&i.Longitude,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, url, secret, events, created_at
+FROM webhooks
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhook(ctx context.Context, id int64) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, url, secret, events, created_at
+FROM webhooks
+ORDER BY id
+`
+
+func (q *Queries) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWishlist = `-- name: GetWishlist :many
+SELECT wishlist.user_id, wishlist.beer_id, wishlist.priority, wishlist.note, wishlist.created_at, beers.id, beers.name, beers.brewer_id, beers.style, beers.abv, beers.rating, beers.notes, beers.deleted_at, brewers.name AS brewer_name
+FROM wishlist
+JOIN beers ON beers.id = wishlist.beer_id
+LEFT JOIN brewers ON brewers.id = beers.brewer_id
+WHERE wishlist.user_id = $1 AND beers.deleted_at IS NULL
+ORDER BY wishlist.priority IS NULL, wishlist.priority, wishlist.created_at, beers.id
+`
+
+type GetWishlistRow struct {
+	Wishlist   Wishlist
+	Beer       Beer
+	BrewerName sql.NullString
+}
+
+func (q *Queries) GetWishlist(ctx context.Context, userID int64) ([]GetWishlistRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWishlist, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWishlistRow
+	for rows.Next() {
+		var i GetWishlistRow
+		if err := rows.Scan(
+			&i.Wishlist.UserID,
+			&i.Wishlist.BeerID,
+			&i.Wishlist.Priority,
+			&i.Wishlist.Note,
+			&i.Wishlist.CreatedAt,
+			&i.Beer.ID,
+			&i.Beer.Name,
+			&i.Beer.BrewerID,
+			&i.Beer.Style,
+			&i.Beer.Abv,
+			&i.Beer.Rating,
+			&i.Beer.Notes,
+			&i.Beer.DeletedAt,
+			&i.BrewerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeBeer = `-- name: PurgeBeer :one
+DELETE FROM beers
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+func (q *Queries) PurgeBeer(ctx context.Context, id int64) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, purgeBeer, id)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const purgeBrewer = `-- name: PurgeBrewer :one
+DELETE FROM brewers
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, location, deleted_at
+`
+
+func (q *Queries) PurgeBrewer(ctx context.Context, id int64) (Brewer, error) {
+	row := q.db.QueryRowContext(ctx, purgeBrewer, id)
+	var i Brewer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Location,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const purgeDeletedBeers = `-- name: PurgeDeletedBeers :execrows
+DELETE FROM beers
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedBeers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedBeers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedBrewers = `-- name: PurgeDeletedBrewers :execrows
+DELETE FROM brewers
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedBrewers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedBrewers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeUser = `-- name: PurgeUser :one
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, username, password_hash, is_admin, created_at, last_login, deleted_at
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, purgeUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const queueDelivery = `-- name: QueueDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, webhook_id, event, payload, attempts, next_attempt_at, last_status, last_error, delivered_at, created_at
+`
+
+type QueueDeliveryParams struct {
+	WebhookID     int64
+	Event         string
+	Payload       string
+	NextAttemptAt sql.NullTime
+}
+
+func (q *Queries) QueueDelivery(ctx context.Context, arg QueueDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, queueDelivery,
+		arg.WebhookID,
+		arg.Event,
+		arg.Payload,
+		arg.NextAttemptAt,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatus,
+		&i.LastError,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const queueEmail = `-- name: QueueEmail :one
+
+INSERT INTO outbox (to_address, subject, text_body, html_body, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, to_address, subject, text_body, html_body, attempts, next_attempt_at, last_error, sent_at, created_at
+`
+
+type QueueEmailParams struct {
+	ToAddress     string
+	Subject       string
+	TextBody      string
+	HtmlBody      string
+	NextAttemptAt sql.NullTime
+}
+
+// === OUTBOX ===
+func (q *Queries) QueueEmail(ctx context.Context, arg QueueEmailParams) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, queueEmail,
+		arg.ToAddress,
+		arg.Subject,
+		arg.TextBody,
+		arg.HtmlBody,
+		arg.NextAttemptAt,
+	)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.ToAddress,
+		&i.Subject,
+		&i.TextBody,
+		&i.HtmlBody,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.SentAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const renameBeerStyle = `-- name: RenameBeerStyle :many
+UPDATE beers
+SET style = $1
+WHERE style = $2
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+type RenameBeerStyleParams struct {
+	NewStyle sql.NullString
+	OldStyle sql.NullString
+}
+
+func (q *Queries) RenameBeerStyle(ctx context.Context, arg RenameBeerStyleParams) ([]Beer, error) {
+	rows, err := q.db.QueryContext(ctx, renameBeerStyle, arg.NewStyle, arg.OldStyle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Beer
+	for rows.Next() {
+		var i Beer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BrewerID,
+			&i.Style,
+			&i.Abv,
+			&i.Rating,
+			&i.Notes,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreBeer = `-- name: RestoreBeer :one
+UPDATE beers
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+func (q *Queries) RestoreBeer(ctx context.Context, id int64) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, restoreBeer, id)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreBrewer = `-- name: RestoreBrewer :one
+UPDATE brewers
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, location, deleted_at
+`
+
+func (q *Queries) RestoreBrewer(ctx context.Context, id int64) (Brewer, error) {
+	row := q.db.QueryRowContext(ctx, restoreBrewer, id)
+	var i Brewer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Location,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, username, password_hash, is_admin, created_at, last_login, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.LastLogin,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const revealTasting = `-- name: RevealTasting :one
+UPDATE tastings
+SET revealed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revealed_at IS NULL
+RETURNING id, name, organiser_id, held_at, revealed_at, created_at
+`
+
+func (q *Queries) RevealTasting(ctx context.Context, id int64) (Tasting, error) {
+	row := q.db.QueryRowContext(ctx, revealTasting, id)
+	var i Tasting
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganiserID,
+		&i.HeldAt,
+		&i.RevealedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const revertBeer = `-- name: RevertBeer :one
+UPDATE beers
+SET name = $1, brewer_id = $2, style = $3, abv = $4, rating = $5, notes = $6
+WHERE id = $7 AND deleted_at IS NULL
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+type RevertBeerParams struct {
+	Name     string
+	BrewerID sql.NullInt64
+	Style    sql.NullString
+	Abv      float64
+	Rating   sql.NullFloat64
+	Notes    sql.NullString
+	ID       int64
+}
+
+func (q *Queries) RevertBeer(ctx context.Context, arg RevertBeerParams) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, revertBeer,
+		arg.Name,
+		arg.BrewerID,
+		arg.Style,
+		arg.Abv,
+		arg.Rating,
+		arg.Notes,
+		arg.ID,
+	)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const saveScorecard = `-- name: SaveScorecard :one
+INSERT INTO scorecards (beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+ON CONFLICT (beer_id, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    total = excluded.total,
+    updated_at = now()
+RETURNING id, beer_id, user_id, aroma, appearance, flavour, mouthfeel, overall, total, updated_at, created_at
+`
+
+type SaveScorecardParams struct {
+	BeerID     int64
+	UserID     int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+	Total      float64
+}
+
+func (q *Queries) SaveScorecard(ctx context.Context, arg SaveScorecardParams) (Scorecard, error) {
+	row := q.db.QueryRowContext(ctx, saveScorecard,
+		arg.BeerID,
+		arg.UserID,
+		arg.Aroma,
+		arg.Appearance,
+		arg.Flavour,
+		arg.Mouthfeel,
+		arg.Overall,
+		arg.Total,
+	)
+	var i Scorecard
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+		&i.Total,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const saveTastingScorecard = `-- name: SaveTastingScorecard :one
+INSERT INTO tasting_scorecards (tasting_id, number, user_id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (tasting_id, number, user_id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING tasting_id, number, user_id, aroma, appearance, flavour, mouthfeel, overall, updated_at
+`
+
+type SaveTastingScorecardParams struct {
+	TastingID  int64
+	Number     int64
+	UserID     int64
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+}
+
+func (q *Queries) SaveTastingScorecard(ctx context.Context, arg SaveTastingScorecardParams) (TastingScorecard, error) {
+	row := q.db.QueryRowContext(ctx, saveTastingScorecard,
+		arg.TastingID,
+		arg.Number,
+		arg.UserID,
+		arg.Aroma,
+		arg.Appearance,
+		arg.Flavour,
+		arg.Mouthfeel,
+		arg.Overall,
+	)
+	var i TastingScorecard
+	err := row.Scan(
+		&i.TastingID,
+		&i.Number,
+		&i.UserID,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const searchBeers = `-- name: SearchBeers :many
+SELECT id, name, brewer_id, style, abv, rating, notes, deleted_at
+FROM beers
+WHERE (name ILIKE '%' || $1 || '%' OR style ILIKE '%' || $1 || '%' OR notes ILIKE '%' || $1 || '%')
+    AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) SearchBeers(ctx context.Context, query sql.NullString) ([]Beer, error) {
+	rows, err := q.db.QueryContext(ctx, searchBeers, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Beer
+	for rows.Next() {
+		var i Beer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BrewerID,
+			&i.Style,
+			&i.Abv,
+			&i.Rating,
+			&i.Notes,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchStyles = `-- name: SearchStyles :many
+SELECT id, code, category, name, abv_min, abv_max, ibu_min, ibu_max, srm_min, srm_max, description
+FROM styles
+WHERE name ILIKE '%' || $1 || '%' OR category ILIKE '%' || $1 || '%' OR lower(code) = lower($1)
+ORDER BY id
+LIMIT $2::bigint
+`
+
+type SearchStylesParams struct {
+	Query      sql.NullString
+	MaxResults int64
+}
+
+func (q *Queries) SearchStyles(ctx context.Context, arg SearchStylesParams) ([]Style, error) {
+	rows, err := q.db.QueryContext(ctx, searchStyles, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Style
+	for rows.Next() {
+		var i Style
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Category,
+			&i.Name,
+			&i.AbvMin,
+			&i.AbvMax,
+			&i.IbuMin,
+			&i.IbuMax,
+			&i.SrmMin,
+			&i.SrmMax,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setBeerRating = `-- name: SetBeerRating :exec
+UPDATE beers
+SET rating = $1
+WHERE id = $2
+`
+
+type SetBeerRatingParams struct {
+	Rating sql.NullFloat64
+	ID     int64
+}
+
+func (q *Queries) SetBeerRating(ctx context.Context, arg SetBeerRatingParams) error {
+	_, err := q.db.ExecContext(ctx, setBeerRating, arg.Rating, arg.ID)
+	return err
+}
+
+const setBudget = `-- name: SetBudget :one
+
+INSERT INTO budgets (user_id, amount, currency)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET amount = excluded.amount, currency = excluded.currency, updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, amount, currency, updated_at
+`
+
+type SetBudgetParams struct {
+	UserID   int64
+	Amount   float64
+	Currency string
+}
+
+// === BUDGETS ===
+func (q *Queries) SetBudget(ctx context.Context, arg SetBudgetParams) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, setBudget, arg.UserID, arg.Amount, arg.Currency)
+	var i Budget
+	err := row.Scan(
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setDeliveryDelivered = `-- name: SetDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = $1, delivered_at = $2, next_attempt_at = NULL, last_error = ''
+WHERE id = $3
+`
+
+type SetDeliveryDeliveredParams struct {
+	LastStatus  sql.NullInt64
+	DeliveredAt sql.NullTime
+	ID          int64
+}
+
+func (q *Queries) SetDeliveryDelivered(ctx context.Context, arg SetDeliveryDeliveredParams) error {
+	_, err := q.db.ExecContext(ctx, setDeliveryDelivered, arg.LastStatus, arg.DeliveredAt, arg.ID)
+	return err
+}
+
+const setDeliveryFailed = `-- name: SetDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, last_status = $1, last_error = $2, next_attempt_at = $3
+WHERE id = $4
+`
+
+type SetDeliveryFailedParams struct {
+	LastStatus    sql.NullInt64
+	LastError     string
+	NextAttemptAt sql.NullTime
+	ID            int64
+}
+
+// Records a failed attempt, and when to try again, or NULL to give up
+func (q *Queries) SetDeliveryFailed(ctx context.Context, arg SetDeliveryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, setDeliveryFailed,
+		arg.LastStatus,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const setEmailFailed = `-- name: SetEmailFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+WHERE id = $3
+`
+
+type SetEmailFailedParams struct {
+	LastError     string
+	NextAttemptAt sql.NullTime
+	ID            int64
+}
+
+// Records a failed attempt, and when to try again, or NULL to give up
+func (q *Queries) SetEmailFailed(ctx context.Context, arg SetEmailFailedParams) error {
+	_, err := q.db.ExecContext(ctx, setEmailFailed, arg.LastError, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const setEmailSent = `-- name: SetEmailSent :exec
+UPDATE outbox
+SET attempts = attempts + 1, sent_at = $1, next_attempt_at = NULL, last_error = ''
+WHERE id = $2
+`
+
+type SetEmailSentParams struct {
+	SentAt sql.NullTime
+	ID     int64
+}
+
+func (q *Queries) SetEmailSent(ctx context.Context, arg SetEmailSentParams) error {
+	_, err := q.db.ExecContext(ctx, setEmailSent, arg.SentAt, arg.ID)
+	return err
+}
+
+const setGoals = `-- name: SetGoals :one
+
+INSERT INTO goals (user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET weekly_limit = excluded.weekly_limit,
+    alcohol_free_days = excluded.alcohol_free_days,
+    confirm_over_limit = excluded.confirm_over_limit,
+    time_zone = excluded.time_zone,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, weekly_limit, alcohol_free_days, confirm_over_limit, time_zone, updated_at
+`
+
+type SetGoalsParams struct {
+	UserID           int64
+	WeeklyLimit      sql.NullFloat64
+	AlcoholFreeDays  sql.NullInt64
+	ConfirmOverLimit bool
+	TimeZone         string
+}
+
+// === GOALS ===
+func (q *Queries) SetGoals(ctx context.Context, arg SetGoalsParams) (Goal, error) {
+	row := q.db.QueryRowContext(ctx, setGoals,
+		arg.UserID,
+		arg.WeeklyLimit,
+		arg.AlcoholFreeDays,
+		arg.ConfirmOverLimit,
+		arg.TimeZone,
+	)
+	var i Goal
+	err := row.Scan(
+		&i.UserID,
+		&i.WeeklyLimit,
+		&i.AlcoholFreeDays,
+		&i.ConfirmOverLimit,
+		&i.TimeZone,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setPrivacy = `-- name: SetPrivacy :one
+INSERT INTO user_privacy (user_id, visibility)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET visibility = excluded.visibility, updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, visibility, updated_at
+`
+
+type SetPrivacyParams struct {
+	UserID     int64
+	Visibility string
+}
+
+func (q *Queries) SetPrivacy(ctx context.Context, arg SetPrivacyParams) (UserPrivacy, error) {
+	row := q.db.QueryRowContext(ctx, setPrivacy, arg.UserID, arg.Visibility)
+	var i UserPrivacy
+	err := row.Scan(&i.UserID, &i.Visibility, &i.UpdatedAt)
+	return i, err
+}
+
+const setScheduleReminded = `-- name: SetScheduleReminded :exec
+UPDATE schedules
+SET reminded_at = $1
+WHERE id = $2
+`
+
+type SetScheduleRemindedParams struct {
+	RemindedAt sql.NullTime
+	ID         int64
+}
+
+func (q *Queries) SetScheduleReminded(ctx context.Context, arg SetScheduleRemindedParams) error {
+	_, err := q.db.ExecContext(ctx, setScheduleReminded, arg.RemindedAt, arg.ID)
+	return err
+}
+
+const setScorecardTotal = `-- name: SetScorecardTotal :exec
+UPDATE scorecards
+SET total = $1
+WHERE id = $2
+`
+
+type SetScorecardTotalParams struct {
+	Total float64
+	ID    int64
+}
+
+func (q *Queries) SetScorecardTotal(ctx context.Context, arg SetScorecardTotalParams) error {
+	_, err := q.db.ExecContext(ctx, setScorecardTotal, arg.Total, arg.ID)
+	return err
+}
+
+const setScorecardWeights = `-- name: SetScorecardWeights :one
+INSERT INTO scorecard_weights (id, aroma, appearance, flavour, mouthfeel, overall)
+VALUES (1, $1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET
+    aroma = excluded.aroma,
+    appearance = excluded.appearance,
+    flavour = excluded.flavour,
+    mouthfeel = excluded.mouthfeel,
+    overall = excluded.overall
+RETURNING id, aroma, appearance, flavour, mouthfeel, overall
+`
+
+type SetScorecardWeightsParams struct {
+	Aroma      float64
+	Appearance float64
+	Flavour    float64
+	Mouthfeel  float64
+	Overall    float64
+}
+
+func (q *Queries) SetScorecardWeights(ctx context.Context, arg SetScorecardWeightsParams) (ScorecardWeight, error) {
+	row := q.db.QueryRowContext(ctx, setScorecardWeights,
+		arg.Aroma,
+		arg.Appearance,
+		arg.Flavour,
+		arg.Mouthfeel,
+		arg.Overall,
+	)
+	var i ScorecardWeight
+	err := row.Scan(
+		&i.ID,
+		&i.Aroma,
+		&i.Appearance,
+		&i.Flavour,
+		&i.Mouthfeel,
+		&i.Overall,
+	)
+	return i, err
+}
+
+const setSummarySent = `-- name: SetSummarySent :exec
+UPDATE user_emails
+SET summary_sent_on = $1
+WHERE user_id = $2
+`
+
+type SetSummarySentParams struct {
+	SummarySentOn sql.NullTime
+	UserID        int64
+}
+
+func (q *Queries) SetSummarySent(ctx context.Context, arg SetSummarySentParams) error {
+	_, err := q.db.ExecContext(ctx, setSummarySent, arg.SummarySentOn, arg.UserID)
+	return err
+}
+
+const setUserEmail = `-- name: SetUserEmail :one
+
+INSERT INTO user_emails (user_id, email, weekly_summary, reminders)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET email = excluded.email,
+    weekly_summary = excluded.weekly_summary,
+    reminders = excluded.reminders,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, email, weekly_summary, reminders, summary_sent_on, updated_at
+`
+
+type SetUserEmailParams struct {
+	UserID        int64
+	Email         string
+	WeeklySummary bool
+	Reminders     bool
+}
+
+// === EMAILS ===
+func (q *Queries) SetUserEmail(ctx context.Context, arg SetUserEmailParams) (UserEmail, error) {
+	row := q.db.QueryRowContext(ctx, setUserEmail,
+		arg.UserID,
+		arg.Email,
+		arg.WeeklySummary,
+		arg.Reminders,
+	)
+	var i UserEmail
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.WeeklySummary,
+		&i.Reminders,
+		&i.SummarySentOn,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setUserLastLogin = `-- name: SetUserLastLogin :exec
+UPDATE users
+SET last_login = now()
+WHERE id = $1
+`
+
+func (q *Queries) SetUserLastLogin(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, setUserLastLogin, id)
+	return err
+}
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $1
+WHERE id = $2
+`
+
+type SetUserPasswordParams struct {
+	PasswordHash string
+	ID           int64
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
+
+const takeStock = `-- name: TakeStock :one
+UPDATE stock
+SET quantity = quantity - 1
+WHERE id = (
+    SELECT id
+    FROM stock AS s
+    WHERE s.beer_id = $1 AND s.quantity > 0
+    ORDER BY s.best_before IS NULL, s.best_before, s.purchased_on, s.id
+    LIMIT 1
+)
+RETURNING id, beer_id, user_id, quantity, bought, container_ml, purchased_on, best_before, price, currency, created_at
+`
+
+// Takes from the entry which goes off first
+func (q *Queries) TakeStock(ctx context.Context, beerID int64) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, takeStock, beerID)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.BeerID,
+		&i.UserID,
+		&i.Quantity,
+		&i.Bought,
+		&i.ContainerMl,
+		&i.PurchasedOn,
+		&i.BestBefore,
+		&i.Price,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const unfollow = `-- name: Unfollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type UnfollowParams struct {
+	FollowerID int64
+	FolloweeID int64
+}
+
+func (q *Queries) Unfollow(ctx context.Context, arg UnfollowParams) error {
+	_, err := q.db.ExecContext(ctx, unfollow, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const updateBeer = `-- name: UpdateBeer :one
+UPDATE beers
+SET 
+    name = coalesce($1, name),
+    brewer_id = coalesce($2, brewer_id),
+    style = coalesce($3, style),
+    abv = coalesce($4, abv),
+    rating = coalesce($5, rating),
+    notes = coalesce($6, notes)
+WHERE id = $7 AND deleted_at IS NULL
+RETURNING id, name, brewer_id, style, abv, rating, notes, deleted_at
+`
+
+type UpdateBeerParams struct {
+	Name     sql.NullString
+	BrewerID sql.NullInt64
+	Style    sql.NullString
+	Abv      sql.NullFloat64
+	Rating   sql.NullFloat64
+	Notes    sql.NullString
+	ID       int64
+}
+
+func (q *Queries) UpdateBeer(ctx context.Context, arg UpdateBeerParams) (Beer, error) {
+	row := q.db.QueryRowContext(ctx, updateBeer,
+		arg.Name,
+		arg.BrewerID,
+		arg.Style,
+		arg.Abv,
+		arg.Rating,
+		arg.Notes,
+		arg.ID,
+	)
+	var i Beer
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.BrewerID,
+		&i.Style,
+		&i.Abv,
+		&i.Rating,
+		&i.Notes,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateComment = `-- name: UpdateComment :one
+UPDATE comments
+SET body = $1, edited_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, author_id, beer_id, brewer_id, parent_id, body, created_at, edited_at, deleted_at, moderated
+`
+
+type UpdateCommentParams struct {
+	Body string
+	ID   int64
+}
+
+func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, updateComment, arg.Body, arg.ID)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.BeerID,
+		&i.BrewerID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+		&i.Moderated,
+	)
+	return i, err
+}
+
+const updateWish = `-- name: UpdateWish :one
+UPDATE wishlist
+SET priority = $1, note = $2
+WHERE user_id = $3 AND beer_id = $4
+RETURNING user_id, beer_id, priority, note, created_at
+`
+
+type UpdateWishParams struct {
+	Priority sql.NullInt64
+	Note     sql.NullString
+	UserID   int64
+	BeerID   int64
+}
+
+func (q *Queries) UpdateWish(ctx context.Context, arg UpdateWishParams) (Wishlist, error) {
+	row := q.db.QueryRowContext(ctx, updateWish,
+		arg.Priority,
+		arg.Note,
+		arg.UserID,
+		arg.BeerID,
+	)
+	var i Wishlist
+	err := row.Scan(
+		&i.UserID,
+		&i.BeerID,
+		&i.Priority,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const usePasswordReset = `-- name: UsePasswordReset :execrows
+UPDATE password_resets
+SET used_at = $1
+WHERE token_hash = $2 AND used_at IS NULL
+`
+
+type UsePasswordResetParams struct {
+	UsedAt    sql.NullTime
+	TokenHash string
+}
+
+// Only the first use of a link counts, so it can't be used twice at once
+func (q *Queries) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, usePasswordReset, arg.UsedAt, arg.TokenHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,1094 @@
+package db
+
+import (
+	"beer_oclock/internal/db/pgdb"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// The queries generated for PostgreSQL, adapted to the same Querier interface as the SQLite ones.
+// Both are generated from schemas with the same shape, so the models and params convert directly
+// between the two packages.
+type postgresQueries struct {
+	q *pgdb.Queries
+	// What the queries go through, for beginning transactions
+	db DBTX
+}
+
+var _ Querier = postgresQueries{}
+
+func NewPostgres(db DBTX) Querier {
+	return postgresQueries{q: pgdb.New(db), db: db}
+}
+
+// Converts each of the rows, keeping a nil slice nil like the generated code does
+func convertAll[From any, To any](rows []From, convert func(From) To) []To {
+	if rows == nil {
+		return nil
+	}
+	converted := make([]To, len(rows))
+	for i, row := range rows {
+		converted[i] = convert(row)
+	}
+	return converted
+}
+
+func toUser(u pgdb.User) User                                         { return User(u) }
+func toBrewer(b pgdb.Brewer) Brewer                                   { return Brewer(b) }
+func toBeer(b pgdb.Beer) Beer                                         { return Beer(b) }
+func toBeerRevision(r pgdb.BeerRevision) BeerRevision                 { return BeerRevision(r) }
+func toStyle(s pgdb.Style) Style                                      { return Style(s) }
+func toScorecard(s pgdb.Scorecard) Scorecard                          { return Scorecard(s) }
+func toTag(t pgdb.Tag) Tag                                            { return Tag(t) }
+func toLabelPhoto(p pgdb.LabelPhoto) LabelPhoto                       { return LabelPhoto(p) }
+func toBarcode(b pgdb.Barcode) Barcode                                { return Barcode(b) }
+func toStock(s pgdb.Stock) Stock                                      { return Stock(s) }
+func toBudget(b pgdb.Budget) Budget                                   { return Budget(b) }
+func toDrink(d pgdb.Drink) Drink                                      { return Drink(d) }
+func toGoal(g pgdb.Goal) Goal                                         { return Goal(g) }
+func toSchedule(s pgdb.Schedule) Schedule                             { return Schedule(s) }
+func toUserEmail(e pgdb.UserEmail) UserEmail                          { return UserEmail(e) }
+func toPasswordReset(r pgdb.PasswordReset) PasswordReset              { return PasswordReset(r) }
+func toOutbox(o pgdb.Outbox) Outbox                                   { return Outbox(o) }
+func toWebhook(w pgdb.Webhook) Webhook                                { return Webhook(w) }
+func toWebhookDelivery(d pgdb.WebhookDelivery) WebhookDelivery        { return WebhookDelivery(d) }
+func toDrinkingSession(s pgdb.DrinkingSession) DrinkingSession        { return DrinkingSession(s) }
+func toSessionVenue(v pgdb.DrinkingSessionVenue) DrinkingSessionVenue { return DrinkingSessionVenue(v) }
+func toVenue(v pgdb.Venue) Venue                                      { return Venue(v) }
+func toCheckIn(c pgdb.CheckIn) CheckIn                                { return CheckIn(c) }
+func toRound(r pgdb.Round) Round                                      { return Round(r) }
+func toActivity(a pgdb.Activity) Activity                             { return Activity(a) }
+func toUserPrivacy(p pgdb.UserPrivacy) UserPrivacy                    { return UserPrivacy(p) }
+func toComment(c pgdb.Comment) Comment                                { return Comment(c) }
+func toTasting(t pgdb.Tasting) Tasting                                { return Tasting(t) }
+func toTastingPour(p pgdb.TastingPour) TastingPour                    { return TastingPour(p) }
+func toTastingScorecard(s pgdb.TastingScorecard) TastingScorecard     { return TastingScorecard(s) }
+func toWishlist(w pgdb.Wishlist) Wishlist                             { return Wishlist(w) }
+
+/* === CONTACTS === */
+
+func (p postgresQueries) AddUser(ctx context.Context, arg AddUserParams) (User, error) {
+	user, err := p.q.AddUser(ctx, pgdb.AddUserParams(arg))
+	return toUser(user), err
+}
+
+func (p postgresQueries) GetUserById(ctx context.Context, id int64) (User, error) {
+	user, err := p.q.GetUserById(ctx, id)
+	return toUser(user), err
+}
+
+func (p postgresQueries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	user, err := p.q.GetUserByUsername(ctx, username)
+	return toUser(user), err
+}
+
+func (p postgresQueries) GetUsers(ctx context.Context) ([]User, error) {
+	users, err := p.q.GetUsers(ctx)
+	return convertAll(users, toUser), err
+}
+
+func (p postgresQueries) DeleteUser(ctx context.Context, id int64) (User, error) {
+	user, err := p.q.DeleteUser(ctx, id)
+	return toUser(user), err
+}
+
+func (p postgresQueries) RestoreUser(ctx context.Context, id int64) (User, error) {
+	user, err := p.q.RestoreUser(ctx, id)
+	return toUser(user), err
+}
+
+func (p postgresQueries) GetDeletedUsers(ctx context.Context) ([]User, error) {
+	users, err := p.q.GetDeletedUsers(ctx)
+	return convertAll(users, toUser), err
+}
+
+func (p postgresQueries) PurgeUser(ctx context.Context, id int64) (User, error) {
+	user, err := p.q.PurgeUser(ctx, id)
+	return toUser(user), err
+}
+
+func (p postgresQueries) PurgeDeletedUsers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	return p.q.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+func (p postgresQueries) CountUsers(ctx context.Context) (int64, error) {
+	return p.q.CountUsers(ctx)
+}
+
+func (p postgresQueries) SetUserLastLogin(ctx context.Context, id int64) error {
+	return p.q.SetUserLastLogin(ctx, id)
+}
+
+func (p postgresQueries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	return p.q.SetUserPassword(ctx, pgdb.SetUserPasswordParams(arg))
+}
+
+/* === BREWERS === */
+
+func (p postgresQueries) AddBrewer(ctx context.Context, arg AddBrewerParams) (Brewer, error) {
+	brewer, err := p.q.AddBrewer(ctx, pgdb.AddBrewerParams(arg))
+	return toBrewer(brewer), err
+}
+
+func (p postgresQueries) GetBrewerById(ctx context.Context, id int64) (Brewer, error) {
+	brewer, err := p.q.GetBrewerById(ctx, id)
+	return toBrewer(brewer), err
+}
+
+func (p postgresQueries) GetBrewers(ctx context.Context) ([]Brewer, error) {
+	brewers, err := p.q.GetBrewers(ctx)
+	return convertAll(brewers, toBrewer), err
+}
+
+func (p postgresQueries) DeleteBrewer(ctx context.Context, id int64) (Brewer, error) {
+	brewer, err := p.q.DeleteBrewer(ctx, id)
+	return toBrewer(brewer), err
+}
+
+func (p postgresQueries) RestoreBrewer(ctx context.Context, id int64) (Brewer, error) {
+	brewer, err := p.q.RestoreBrewer(ctx, id)
+	return toBrewer(brewer), err
+}
+
+func (p postgresQueries) GetDeletedBrewers(ctx context.Context) ([]Brewer, error) {
+	brewers, err := p.q.GetDeletedBrewers(ctx)
+	return convertAll(brewers, toBrewer), err
+}
+
+func (p postgresQueries) PurgeBrewer(ctx context.Context, id int64) (Brewer, error) {
+	brewer, err := p.q.PurgeBrewer(ctx, id)
+	return toBrewer(brewer), err
+}
+
+func (p postgresQueries) PurgeDeletedBrewers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	return p.q.PurgeDeletedBrewers(ctx, deletedBefore)
+}
+
+func (p postgresQueries) CountBrewers(ctx context.Context) (int64, error) {
+	return p.q.CountBrewers(ctx)
+}
+
+/* === BEERS === */
+
+func (p postgresQueries) AddBeer(ctx context.Context, arg AddBeerParams) (Beer, error) {
+	beer, err := p.q.AddBeer(ctx, pgdb.AddBeerParams(arg))
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) GetBeerById(ctx context.Context, id int64) (Beer, error) {
+	beer, err := p.q.GetBeerById(ctx, id)
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) GetBeers(ctx context.Context) ([]Beer, error) {
+	beers, err := p.q.GetBeers(ctx)
+	return convertAll(beers, toBeer), err
+}
+
+func (p postgresQueries) DeleteBeer(ctx context.Context, id int64) (Beer, error) {
+	beer, err := p.q.DeleteBeer(ctx, id)
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) RestoreBeer(ctx context.Context, id int64) (Beer, error) {
+	beer, err := p.q.RestoreBeer(ctx, id)
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) GetDeletedBeers(ctx context.Context) ([]Beer, error) {
+	beers, err := p.q.GetDeletedBeers(ctx)
+	return convertAll(beers, toBeer), err
+}
+
+func (p postgresQueries) PurgeBeer(ctx context.Context, id int64) (Beer, error) {
+	beer, err := p.q.PurgeBeer(ctx, id)
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) PurgeDeletedBeers(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	return p.q.PurgeDeletedBeers(ctx, deletedBefore)
+}
+
+func (p postgresQueries) CountBeers(ctx context.Context) (int64, error) {
+	return p.q.CountBeers(ctx)
+}
+
+func (p postgresQueries) UpdateBeer(ctx context.Context, arg UpdateBeerParams) (Beer, error) {
+	beer, err := p.q.UpdateBeer(ctx, pgdb.UpdateBeerParams(arg))
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) RevertBeer(ctx context.Context, arg RevertBeerParams) (Beer, error) {
+	beer, err := p.q.RevertBeer(ctx, pgdb.RevertBeerParams(arg))
+	return toBeer(beer), err
+}
+
+func (p postgresQueries) SearchBeers(ctx context.Context, query sql.NullString) ([]Beer, error) {
+	beers, err := p.q.SearchBeers(ctx, query)
+	return convertAll(beers, toBeer), err
+}
+
+func (p postgresQueries) GetBeerStyles(ctx context.Context) ([]sql.NullString, error) {
+	return p.q.GetBeerStyles(ctx)
+}
+
+func (p postgresQueries) RenameBeerStyle(ctx context.Context, arg RenameBeerStyleParams) ([]Beer, error) {
+	beers, err := p.q.RenameBeerStyle(ctx, pgdb.RenameBeerStyleParams(arg))
+	return convertAll(beers, toBeer), err
+}
+
+func (p postgresQueries) SetBeerRating(ctx context.Context, arg SetBeerRatingParams) error {
+	return p.q.SetBeerRating(ctx, pgdb.SetBeerRatingParams(arg))
+}
+
+/* === BEER REVISIONS === */
+
+func (p postgresQueries) AddBeerRevision(ctx context.Context, arg AddBeerRevisionParams) (BeerRevision, error) {
+	revision, err := p.q.AddBeerRevision(ctx, pgdb.AddBeerRevisionParams(arg))
+	return toBeerRevision(revision), err
+}
+
+func (p postgresQueries) GetBeerRevision(ctx context.Context, arg GetBeerRevisionParams) (BeerRevision, error) {
+	revision, err := p.q.GetBeerRevision(ctx, pgdb.GetBeerRevisionParams(arg))
+	return toBeerRevision(revision), err
+}
+
+func (p postgresQueries) GetBeerRevisions(ctx context.Context, beerID int64) ([]GetBeerRevisionsRow, error) {
+	rows, err := p.q.GetBeerRevisions(ctx, beerID)
+	return convertAll(rows, func(r pgdb.GetBeerRevisionsRow) GetBeerRevisionsRow {
+		return GetBeerRevisionsRow{
+			BeerRevision: toBeerRevision(r.BeerRevision),
+			Author:       r.Author,
+			BrewerName:   r.BrewerName,
+		}
+	}), err
+}
+
+/* === STYLES === */
+
+func (p postgresQueries) AddStyle(ctx context.Context, arg AddStyleParams) (Style, error) {
+	style, err := p.q.AddStyle(ctx, pgdb.AddStyleParams(arg))
+	return toStyle(style), err
+}
+
+func (p postgresQueries) GetStyleById(ctx context.Context, id int64) (Style, error) {
+	style, err := p.q.GetStyleById(ctx, id)
+	return toStyle(style), err
+}
+
+func (p postgresQueries) GetStyleByName(ctx context.Context, name string) (Style, error) {
+	style, err := p.q.GetStyleByName(ctx, name)
+	return toStyle(style), err
+}
+
+func (p postgresQueries) GetStyles(ctx context.Context) ([]Style, error) {
+	styles, err := p.q.GetStyles(ctx)
+	return convertAll(styles, toStyle), err
+}
+
+func (p postgresQueries) SearchStyles(ctx context.Context, arg SearchStylesParams) ([]Style, error) {
+	styles, err := p.q.SearchStyles(ctx, pgdb.SearchStylesParams(arg))
+	return convertAll(styles, toStyle), err
+}
+
+/* === SCORECARDS === */
+
+func (p postgresQueries) GetScorecardWeights(ctx context.Context) (ScorecardWeight, error) {
+	weights, err := p.q.GetScorecardWeights(ctx)
+	return ScorecardWeight(weights), err
+}
+
+func (p postgresQueries) SetScorecardWeights(ctx context.Context, arg SetScorecardWeightsParams) (ScorecardWeight, error) {
+	weights, err := p.q.SetScorecardWeights(ctx, pgdb.SetScorecardWeightsParams(arg))
+	return ScorecardWeight(weights), err
+}
+
+func (p postgresQueries) SaveScorecard(ctx context.Context, arg SaveScorecardParams) (Scorecard, error) {
+	scorecard, err := p.q.SaveScorecard(ctx, pgdb.SaveScorecardParams(arg))
+	return toScorecard(scorecard), err
+}
+
+func (p postgresQueries) GetScorecard(ctx context.Context, arg GetScorecardParams) (Scorecard, error) {
+	scorecard, err := p.q.GetScorecard(ctx, pgdb.GetScorecardParams(arg))
+	return toScorecard(scorecard), err
+}
+
+func (p postgresQueries) GetBeerScorecards(ctx context.Context, beerID int64) ([]GetBeerScorecardsRow, error) {
+	rows, err := p.q.GetBeerScorecards(ctx, beerID)
+	return convertAll(rows, func(r pgdb.GetBeerScorecardsRow) GetBeerScorecardsRow {
+		return GetBeerScorecardsRow{Scorecard: toScorecard(r.Scorecard), Username: r.Username}
+	}), err
+}
+
+func (p postgresQueries) GetBeerScoreAverages(ctx context.Context, beerID int64) (GetBeerScoreAveragesRow, error) {
+	averages, err := p.q.GetBeerScoreAverages(ctx, beerID)
+	return GetBeerScoreAveragesRow(averages), err
+}
+
+func (p postgresQueries) GetScorecards(ctx context.Context) ([]Scorecard, error) {
+	scorecards, err := p.q.GetScorecards(ctx)
+	return convertAll(scorecards, toScorecard), err
+}
+
+func (p postgresQueries) SetScorecardTotal(ctx context.Context, arg SetScorecardTotalParams) error {
+	return p.q.SetScorecardTotal(ctx, pgdb.SetScorecardTotalParams(arg))
+}
+
+/* === TAGS === */
+
+func (p postgresQueries) AddTag(ctx context.Context, arg AddTagParams) (Tag, error) {
+	tag, err := p.q.AddTag(ctx, pgdb.AddTagParams(arg))
+	return toTag(tag), err
+}
+
+func (p postgresQueries) GetTagByName(ctx context.Context, name string) (Tag, error) {
+	tag, err := p.q.GetTagByName(ctx, name)
+	return toTag(tag), err
+}
+
+func (p postgresQueries) GetTags(ctx context.Context) ([]Tag, error) {
+	tags, err := p.q.GetTags(ctx)
+	return convertAll(tags, toTag), err
+}
+
+func (p postgresQueries) GetTagCounts(ctx context.Context) ([]GetTagCountsRow, error) {
+	rows, err := p.q.GetTagCounts(ctx)
+	return convertAll(rows, func(r pgdb.GetTagCountsRow) GetTagCountsRow {
+		return GetTagCountsRow{Tag: toTag(r.Tag), BeerCount: r.BeerCount}
+	}), err
+}
+
+func (p postgresQueries) GetBeerTags(ctx context.Context, beerID int64) ([]Tag, error) {
+	tags, err := p.q.GetBeerTags(ctx, beerID)
+	return convertAll(tags, toTag), err
+}
+
+func (p postgresQueries) GetAllBeerTags(ctx context.Context) ([]GetAllBeerTagsRow, error) {
+	rows, err := p.q.GetAllBeerTags(ctx)
+	return convertAll(rows, func(r pgdb.GetAllBeerTagsRow) GetAllBeerTagsRow {
+		return GetAllBeerTagsRow{BeerID: r.BeerID, Tag: toTag(r.Tag)}
+	}), err
+}
+
+func (p postgresQueries) AddBeerTag(ctx context.Context, arg AddBeerTagParams) error {
+	return p.q.AddBeerTag(ctx, pgdb.AddBeerTagParams(arg))
+}
+
+func (p postgresQueries) ClearBeerTags(ctx context.Context, beerID int64) error {
+	return p.q.ClearBeerTags(ctx, beerID)
+}
+
+func (p postgresQueries) GetBeersByTag(ctx context.Context, name string) ([]Beer, error) {
+	beers, err := p.q.GetBeersByTag(ctx, name)
+	return convertAll(beers, toBeer), err
+}
+
+/* === LABEL PHOTOS === */
+
+func (p postgresQueries) AddLabelPhoto(ctx context.Context, arg AddLabelPhotoParams) (LabelPhoto, error) {
+	photo, err := p.q.AddLabelPhoto(ctx, pgdb.AddLabelPhotoParams(arg))
+	return toLabelPhoto(photo), err
+}
+
+func (p postgresQueries) GetLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error) {
+	photo, err := p.q.GetLabelPhoto(ctx, id)
+	return toLabelPhoto(photo), err
+}
+
+func (p postgresQueries) GetBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error) {
+	photos, err := p.q.GetBeerLabelPhotos(ctx, beerID)
+	return convertAll(photos, toLabelPhoto), err
+}
+
+func (p postgresQueries) DeleteLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error) {
+	photo, err := p.q.DeleteLabelPhoto(ctx, id)
+	return toLabelPhoto(photo), err
+}
+
+func (p postgresQueries) DeleteBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error) {
+	photos, err := p.q.DeleteBeerLabelPhotos(ctx, beerID)
+	return convertAll(photos, toLabelPhoto), err
+}
+
+/* === BARCODES === */
+
+func (p postgresQueries) AddBarcode(ctx context.Context, arg AddBarcodeParams) (Barcode, error) {
+	barcode, err := p.q.AddBarcode(ctx, pgdb.AddBarcodeParams(arg))
+	return toBarcode(barcode), err
+}
+
+func (p postgresQueries) GetBarcode(ctx context.Context, code string) (Barcode, error) {
+	barcode, err := p.q.GetBarcode(ctx, code)
+	return toBarcode(barcode), err
+}
+
+func (p postgresQueries) GetBeerBarcodes(ctx context.Context, beerID int64) ([]Barcode, error) {
+	barcodes, err := p.q.GetBeerBarcodes(ctx, beerID)
+	return convertAll(barcodes, toBarcode), err
+}
+
+func (p postgresQueries) DeleteBarcode(ctx context.Context, code string) (Barcode, error) {
+	barcode, err := p.q.DeleteBarcode(ctx, code)
+	return toBarcode(barcode), err
+}
+
+/* === STOCK === */
+
+func (p postgresQueries) AddStock(ctx context.Context, arg AddStockParams) (Stock, error) {
+	stock, err := p.q.AddStock(ctx, pgdb.AddStockParams(arg))
+	return toStock(stock), err
+}
+
+func (p postgresQueries) GetStock(ctx context.Context, id int64) (Stock, error) {
+	stock, err := p.q.GetStock(ctx, id)
+	return toStock(stock), err
+}
+
+func (p postgresQueries) GetFridge(ctx context.Context) ([]GetFridgeRow, error) {
+	rows, err := p.q.GetFridge(ctx)
+	return convertAll(rows, func(r pgdb.GetFridgeRow) GetFridgeRow {
+		return GetFridgeRow{Stock: toStock(r.Stock), BeerName: r.BeerName}
+	}), err
+}
+
+func (p postgresQueries) GetStockLevels(ctx context.Context) ([]GetStockLevelsRow, error) {
+	rows, err := p.q.GetStockLevels(ctx)
+	return convertAll(rows, func(r pgdb.GetStockLevelsRow) GetStockLevelsRow {
+		return GetStockLevelsRow(r)
+	}), err
+}
+
+func (p postgresQueries) TakeStock(ctx context.Context, beerID int64) (Stock, error) {
+	stock, err := p.q.TakeStock(ctx, beerID)
+	return toStock(stock), err
+}
+
+func (p postgresQueries) DeleteStock(ctx context.Context, id int64) (Stock, error) {
+	stock, err := p.q.DeleteStock(ctx, id)
+	return toStock(stock), err
+}
+
+/* === SPENDING === */
+
+func (p postgresQueries) GetSpendingByUser(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByUserRow, error) {
+	rows, err := p.q.GetSpendingByUser(ctx, purchasedOn)
+	return convertAll(rows, func(r pgdb.GetSpendingByUserRow) GetSpendingByUserRow { return GetSpendingByUserRow(r) }), err
+}
+
+func (p postgresQueries) GetSpendingByBrewer(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByBrewerRow, error) {
+	rows, err := p.q.GetSpendingByBrewer(ctx, purchasedOn)
+	return convertAll(rows, func(r pgdb.GetSpendingByBrewerRow) GetSpendingByBrewerRow { return GetSpendingByBrewerRow(r) }), err
+}
+
+func (p postgresQueries) GetSpendingByStyle(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByStyleRow, error) {
+	rows, err := p.q.GetSpendingByStyle(ctx, purchasedOn)
+	return convertAll(rows, func(r pgdb.GetSpendingByStyleRow) GetSpendingByStyleRow { return GetSpendingByStyleRow(r) }), err
+}
+
+func (p postgresQueries) GetUserSpending(ctx context.Context, arg GetUserSpendingParams) ([]GetUserSpendingRow, error) {
+	rows, err := p.q.GetUserSpending(ctx, pgdb.GetUserSpendingParams(arg))
+	return convertAll(rows, func(r pgdb.GetUserSpendingRow) GetUserSpendingRow { return GetUserSpendingRow(r) }), err
+}
+
+func (p postgresQueries) GetPurchases(ctx context.Context, purchasedOn time.Time) ([]GetPurchasesRow, error) {
+	rows, err := p.q.GetPurchases(ctx, purchasedOn)
+	return convertAll(rows, func(r pgdb.GetPurchasesRow) GetPurchasesRow {
+		return GetPurchasesRow{
+			Stock:      toStock(r.Stock),
+			BeerName:   r.BeerName,
+			Abv:        r.Abv,
+			Style:      r.Style,
+			BrewerName: r.BrewerName,
+			Username:   r.Username,
+		}
+	}), err
+}
+
+/* === BUDGETS === */
+
+func (p postgresQueries) SetBudget(ctx context.Context, arg SetBudgetParams) (Budget, error) {
+	budget, err := p.q.SetBudget(ctx, pgdb.SetBudgetParams(arg))
+	return toBudget(budget), err
+}
+
+func (p postgresQueries) GetBudget(ctx context.Context, userID int64) (Budget, error) {
+	budget, err := p.q.GetBudget(ctx, userID)
+	return toBudget(budget), err
+}
+
+func (p postgresQueries) DeleteBudget(ctx context.Context, userID int64) (Budget, error) {
+	budget, err := p.q.DeleteBudget(ctx, userID)
+	return toBudget(budget), err
+}
+
+/* === DRINKS === */
+
+func (p postgresQueries) AddDrink(ctx context.Context, arg AddDrinkParams) (Drink, error) {
+	drink, err := p.q.AddDrink(ctx, pgdb.AddDrinkParams(arg))
+	return toDrink(drink), err
+}
+
+func (p postgresQueries) GetDrink(ctx context.Context, id int64) (Drink, error) {
+	drink, err := p.q.GetDrink(ctx, id)
+	return toDrink(drink), err
+}
+
+func (p postgresQueries) GetUserDrinks(ctx context.Context, arg GetUserDrinksParams) ([]GetUserDrinksRow, error) {
+	rows, err := p.q.GetUserDrinks(ctx, pgdb.GetUserDrinksParams(arg))
+	return convertAll(rows, func(r pgdb.GetUserDrinksRow) GetUserDrinksRow {
+		return GetUserDrinksRow{Drink: toDrink(r.Drink), BeerName: r.BeerName, Abv: r.Abv}
+	}), err
+}
+
+func (p postgresQueries) DeleteDrink(ctx context.Context, id int64) (Drink, error) {
+	drink, err := p.q.DeleteDrink(ctx, id)
+	return toDrink(drink), err
+}
+
+/* === STATS === */
+
+func (p postgresQueries) GetDrinksPerDay(ctx context.Context, arg GetDrinksPerDayParams) ([]GetDrinksPerDayRow, error) {
+	rows, err := p.q.GetDrinksPerDay(ctx, pgdb.GetDrinksPerDayParams(arg))
+	return convertAll(rows, func(r pgdb.GetDrinksPerDayRow) GetDrinksPerDayRow { return GetDrinksPerDayRow(r) }), err
+}
+
+func (p postgresQueries) GetAbvDistribution(ctx context.Context, arg GetAbvDistributionParams) ([]GetAbvDistributionRow, error) {
+	rows, err := p.q.GetAbvDistribution(ctx, pgdb.GetAbvDistributionParams(arg))
+	return convertAll(rows, func(r pgdb.GetAbvDistributionRow) GetAbvDistributionRow { return GetAbvDistributionRow(r) }), err
+}
+
+func (p postgresQueries) GetTopStyles(ctx context.Context, arg GetTopStylesParams) ([]GetTopStylesRow, error) {
+	rows, err := p.q.GetTopStyles(ctx, pgdb.GetTopStylesParams(arg))
+	return convertAll(rows, func(r pgdb.GetTopStylesRow) GetTopStylesRow { return GetTopStylesRow(r) }), err
+}
+
+func (p postgresQueries) GetTopBrewers(ctx context.Context, arg GetTopBrewersParams) ([]GetTopBrewersRow, error) {
+	rows, err := p.q.GetTopBrewers(ctx, pgdb.GetTopBrewersParams(arg))
+	return convertAll(rows, func(r pgdb.GetTopBrewersRow) GetTopBrewersRow { return GetTopBrewersRow(r) }), err
+}
+
+func (p postgresQueries) GetRatingHistogram(ctx context.Context, arg GetRatingHistogramParams) ([]GetRatingHistogramRow, error) {
+	rows, err := p.q.GetRatingHistogram(ctx, pgdb.GetRatingHistogramParams(arg))
+	return convertAll(rows, func(r pgdb.GetRatingHistogramRow) GetRatingHistogramRow { return GetRatingHistogramRow(r) }), err
+}
+
+/* === GOALS === */
+
+func (p postgresQueries) SetGoals(ctx context.Context, arg SetGoalsParams) (Goal, error) {
+	goal, err := p.q.SetGoals(ctx, pgdb.SetGoalsParams(arg))
+	return toGoal(goal), err
+}
+
+func (p postgresQueries) GetGoals(ctx context.Context, userID int64) (Goal, error) {
+	goal, err := p.q.GetGoals(ctx, userID)
+	return toGoal(goal), err
+}
+
+func (p postgresQueries) DeleteGoals(ctx context.Context, userID int64) (Goal, error) {
+	goal, err := p.q.DeleteGoals(ctx, userID)
+	return toGoal(goal), err
+}
+
+/* === SCHEDULES === */
+
+func (p postgresQueries) AddSchedule(ctx context.Context, arg AddScheduleParams) (Schedule, error) {
+	schedule, err := p.q.AddSchedule(ctx, pgdb.AddScheduleParams(arg))
+	return toSchedule(schedule), err
+}
+
+func (p postgresQueries) GetSchedule(ctx context.Context, id int64) (Schedule, error) {
+	schedule, err := p.q.GetSchedule(ctx, id)
+	return toSchedule(schedule), err
+}
+
+func (p postgresQueries) GetUserSchedules(ctx context.Context, userID int64) ([]Schedule, error) {
+	schedules, err := p.q.GetUserSchedules(ctx, userID)
+	return convertAll(schedules, toSchedule), err
+}
+
+func (p postgresQueries) GetAllSchedules(ctx context.Context) ([]GetAllSchedulesRow, error) {
+	rows, err := p.q.GetAllSchedules(ctx)
+	return convertAll(rows, func(r pgdb.GetAllSchedulesRow) GetAllSchedulesRow {
+		return GetAllSchedulesRow{Schedule: toSchedule(r.Schedule), Username: r.Username, TimeZone: r.TimeZone}
+	}), err
+}
+
+func (p postgresQueries) SetScheduleReminded(ctx context.Context, arg SetScheduleRemindedParams) error {
+	return p.q.SetScheduleReminded(ctx, pgdb.SetScheduleRemindedParams(arg))
+}
+
+func (p postgresQueries) DeleteSchedule(ctx context.Context, id int64) (Schedule, error) {
+	schedule, err := p.q.DeleteSchedule(ctx, id)
+	return toSchedule(schedule), err
+}
+
+/* === EMAILS === */
+
+func (p postgresQueries) SetUserEmail(ctx context.Context, arg SetUserEmailParams) (UserEmail, error) {
+	email, err := p.q.SetUserEmail(ctx, pgdb.SetUserEmailParams(arg))
+	return toUserEmail(email), err
+}
+
+func (p postgresQueries) GetUserEmail(ctx context.Context, userID int64) (UserEmail, error) {
+	email, err := p.q.GetUserEmail(ctx, userID)
+	return toUserEmail(email), err
+}
+
+func (p postgresQueries) GetUserEmailByAddress(ctx context.Context, email string) (UserEmail, error) {
+	userEmail, err := p.q.GetUserEmailByAddress(ctx, email)
+	return toUserEmail(userEmail), err
+}
+
+func (p postgresQueries) GetUserEmails(ctx context.Context) ([]GetUserEmailsRow, error) {
+	rows, err := p.q.GetUserEmails(ctx)
+	return convertAll(rows, func(r pgdb.GetUserEmailsRow) GetUserEmailsRow {
+		return GetUserEmailsRow{UserEmail: toUserEmail(r.UserEmail), Username: r.Username, TimeZone: r.TimeZone}
+	}), err
+}
+
+func (p postgresQueries) SetSummarySent(ctx context.Context, arg SetSummarySentParams) error {
+	return p.q.SetSummarySent(ctx, pgdb.SetSummarySentParams(arg))
+}
+
+func (p postgresQueries) DeleteUserEmail(ctx context.Context, userID int64) (UserEmail, error) {
+	email, err := p.q.DeleteUserEmail(ctx, userID)
+	return toUserEmail(email), err
+}
+
+/* === PASSWORD RESETS === */
+
+func (p postgresQueries) AddPasswordReset(ctx context.Context, arg AddPasswordResetParams) (PasswordReset, error) {
+	reset, err := p.q.AddPasswordReset(ctx, pgdb.AddPasswordResetParams(arg))
+	return toPasswordReset(reset), err
+}
+
+func (p postgresQueries) GetPasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error) {
+	reset, err := p.q.GetPasswordReset(ctx, tokenHash)
+	return toPasswordReset(reset), err
+}
+
+func (p postgresQueries) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (int64, error) {
+	return p.q.UsePasswordReset(ctx, pgdb.UsePasswordResetParams(arg))
+}
+
+/* === OUTBOX === */
+
+func (p postgresQueries) QueueEmail(ctx context.Context, arg QueueEmailParams) (Outbox, error) {
+	email, err := p.q.QueueEmail(ctx, pgdb.QueueEmailParams(arg))
+	return toOutbox(email), err
+}
+
+func (p postgresQueries) GetDueEmails(ctx context.Context, arg GetDueEmailsParams) ([]Outbox, error) {
+	emails, err := p.q.GetDueEmails(ctx, pgdb.GetDueEmailsParams(arg))
+	return convertAll(emails, toOutbox), err
+}
+
+func (p postgresQueries) GetOutbox(ctx context.Context, maxResults int64) ([]Outbox, error) {
+	emails, err := p.q.GetOutbox(ctx, maxResults)
+	return convertAll(emails, toOutbox), err
+}
+
+func (p postgresQueries) SetEmailSent(ctx context.Context, arg SetEmailSentParams) error {
+	return p.q.SetEmailSent(ctx, pgdb.SetEmailSentParams(arg))
+}
+
+func (p postgresQueries) SetEmailFailed(ctx context.Context, arg SetEmailFailedParams) error {
+	return p.q.SetEmailFailed(ctx, pgdb.SetEmailFailedParams(arg))
+}
+
+/* === WEBHOOKS === */
+
+func (p postgresQueries) AddWebhook(ctx context.Context, arg AddWebhookParams) (Webhook, error) {
+	webhook, err := p.q.AddWebhook(ctx, pgdb.AddWebhookParams(arg))
+	return toWebhook(webhook), err
+}
+
+func (p postgresQueries) GetWebhook(ctx context.Context, id int64) (Webhook, error) {
+	webhook, err := p.q.GetWebhook(ctx, id)
+	return toWebhook(webhook), err
+}
+
+func (p postgresQueries) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	webhooks, err := p.q.GetWebhooks(ctx)
+	return convertAll(webhooks, toWebhook), err
+}
+
+func (p postgresQueries) DeleteWebhook(ctx context.Context, id int64) (Webhook, error) {
+	webhook, err := p.q.DeleteWebhook(ctx, id)
+	return toWebhook(webhook), err
+}
+
+func (p postgresQueries) QueueDelivery(ctx context.Context, arg QueueDeliveryParams) (WebhookDelivery, error) {
+	delivery, err := p.q.QueueDelivery(ctx, pgdb.QueueDeliveryParams(arg))
+	return toWebhookDelivery(delivery), err
+}
+
+func (p postgresQueries) GetDueDeliveries(ctx context.Context, arg GetDueDeliveriesParams) ([]GetDueDeliveriesRow, error) {
+	rows, err := p.q.GetDueDeliveries(ctx, pgdb.GetDueDeliveriesParams(arg))
+	return convertAll(rows, func(r pgdb.GetDueDeliveriesRow) GetDueDeliveriesRow {
+		return GetDueDeliveriesRow{
+			WebhookDelivery: toWebhookDelivery(r.WebhookDelivery),
+			Url:             r.Url,
+			Secret:          r.Secret,
+		}
+	}), err
+}
+
+func (p postgresQueries) GetDeliveries(ctx context.Context, maxResults int64) ([]GetDeliveriesRow, error) {
+	rows, err := p.q.GetDeliveries(ctx, maxResults)
+	return convertAll(rows, func(r pgdb.GetDeliveriesRow) GetDeliveriesRow {
+		return GetDeliveriesRow{WebhookDelivery: toWebhookDelivery(r.WebhookDelivery), Url: r.Url}
+	}), err
+}
+
+func (p postgresQueries) SetDeliveryDelivered(ctx context.Context, arg SetDeliveryDeliveredParams) error {
+	return p.q.SetDeliveryDelivered(ctx, pgdb.SetDeliveryDeliveredParams(arg))
+}
+
+func (p postgresQueries) SetDeliveryFailed(ctx context.Context, arg SetDeliveryFailedParams) error {
+	return p.q.SetDeliveryFailed(ctx, pgdb.SetDeliveryFailedParams(arg))
+}
+
+/* === DRINKING SESSIONS === */
+
+func (p postgresQueries) AddDrinkingSession(ctx context.Context, arg AddDrinkingSessionParams) (DrinkingSession, error) {
+	session, err := p.q.AddDrinkingSession(ctx, pgdb.AddDrinkingSessionParams(arg))
+	return toDrinkingSession(session), err
+}
+
+func (p postgresQueries) GetDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error) {
+	session, err := p.q.GetDrinkingSession(ctx, id)
+	return toDrinkingSession(session), err
+}
+
+func (p postgresQueries) GetDrinkingSessionByToken(ctx context.Context, shareToken string) (DrinkingSession, error) {
+	session, err := p.q.GetDrinkingSessionByToken(ctx, shareToken)
+	return toDrinkingSession(session), err
+}
+
+func (p postgresQueries) GetUserDrinkingSessions(ctx context.Context, userID int64) ([]DrinkingSession, error) {
+	sessions, err := p.q.GetUserDrinkingSessions(ctx, userID)
+	return convertAll(sessions, toDrinkingSession), err
+}
+
+func (p postgresQueries) EndDrinkingSession(ctx context.Context, arg EndDrinkingSessionParams) (DrinkingSession, error) {
+	session, err := p.q.EndDrinkingSession(ctx, pgdb.EndDrinkingSessionParams(arg))
+	return toDrinkingSession(session), err
+}
+
+func (p postgresQueries) DeleteDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error) {
+	session, err := p.q.DeleteDrinkingSession(ctx, id)
+	return toDrinkingSession(session), err
+}
+
+func (p postgresQueries) AddSessionParticipant(ctx context.Context, arg AddSessionParticipantParams) error {
+	return p.q.AddSessionParticipant(ctx, pgdb.AddSessionParticipantParams(arg))
+}
+
+func (p postgresQueries) GetSessionParticipants(ctx context.Context, sessionID int64) ([]GetSessionParticipantsRow, error) {
+	rows, err := p.q.GetSessionParticipants(ctx, sessionID)
+	return convertAll(rows, func(r pgdb.GetSessionParticipantsRow) GetSessionParticipantsRow {
+		return GetSessionParticipantsRow(r)
+	}), err
+}
+
+func (p postgresQueries) AddSessionVenue(ctx context.Context, arg AddSessionVenueParams) (DrinkingSessionVenue, error) {
+	venue, err := p.q.AddSessionVenue(ctx, pgdb.AddSessionVenueParams(arg))
+	return toSessionVenue(venue), err
+}
+
+func (p postgresQueries) GetSessionVenues(ctx context.Context, sessionID int64) ([]DrinkingSessionVenue, error) {
+	venues, err := p.q.GetSessionVenues(ctx, sessionID)
+	return convertAll(venues, toSessionVenue), err
+}
+
+func (p postgresQueries) AddSessionDrink(ctx context.Context, arg AddSessionDrinkParams) error {
+	return p.q.AddSessionDrink(ctx, pgdb.AddSessionDrinkParams(arg))
+}
+
+func (p postgresQueries) GetSessionDrinks(ctx context.Context, sessionID int64) ([]GetSessionDrinksRow, error) {
+	rows, err := p.q.GetSessionDrinks(ctx, sessionID)
+	return convertAll(rows, func(r pgdb.GetSessionDrinksRow) GetSessionDrinksRow {
+		return GetSessionDrinksRow{Drink: toDrink(r.Drink), BeerName: r.BeerName, Abv: r.Abv, Username: r.Username}
+	}), err
+}
+
+/* === VENUES === */
+
+func (p postgresQueries) AddVenue(ctx context.Context, arg AddVenueParams) (Venue, error) {
+	venue, err := p.q.AddVenue(ctx, pgdb.AddVenueParams(arg))
+	return toVenue(venue), err
+}
+
+func (p postgresQueries) GetVenue(ctx context.Context, id int64) (Venue, error) {
+	venue, err := p.q.GetVenue(ctx, id)
+	return toVenue(venue), err
+}
+
+func (p postgresQueries) GetVenues(ctx context.Context) ([]Venue, error) {
+	venues, err := p.q.GetVenues(ctx)
+	return convertAll(venues, toVenue), err
+}
+
+func (p postgresQueries) GetVenuesInBox(ctx context.Context, arg GetVenuesInBoxParams) ([]Venue, error) {
+	venues, err := p.q.GetVenuesInBox(ctx, pgdb.GetVenuesInBoxParams(arg))
+	return convertAll(venues, toVenue), err
+}
+
+func (p postgresQueries) DeleteVenue(ctx context.Context, id int64) (Venue, error) {
+	venue, err := p.q.DeleteVenue(ctx, id)
+	return toVenue(venue), err
+}
+
+func (p postgresQueries) AddCheckIn(ctx context.Context, arg AddCheckInParams) (CheckIn, error) {
+	checkIn, err := p.q.AddCheckIn(ctx, pgdb.AddCheckInParams(arg))
+	return toCheckIn(checkIn), err
+}
+
+func (p postgresQueries) GetCheckIn(ctx context.Context, id int64) (CheckIn, error) {
+	checkIn, err := p.q.GetCheckIn(ctx, id)
+	return toCheckIn(checkIn), err
+}
+
+func (p postgresQueries) DeleteCheckIn(ctx context.Context, id int64) (CheckIn, error) {
+	checkIn, err := p.q.DeleteCheckIn(ctx, id)
+	return toCheckIn(checkIn), err
+}
+
+func (p postgresQueries) GetUserCheckIns(ctx context.Context, arg GetUserCheckInsParams) ([]GetUserCheckInsRow, error) {
+	rows, err := p.q.GetUserCheckIns(ctx, pgdb.GetUserCheckInsParams(arg))
+	return convertAll(rows, func(r pgdb.GetUserCheckInsRow) GetUserCheckInsRow {
+		return GetUserCheckInsRow{CheckIn: toCheckIn(r.CheckIn), BeerName: r.BeerName, VenueName: r.VenueName}
+	}), err
+}
+
+func (p postgresQueries) GetVenueCheckIns(ctx context.Context, arg GetVenueCheckInsParams) ([]GetVenueCheckInsRow, error) {
+	rows, err := p.q.GetVenueCheckIns(ctx, pgdb.GetVenueCheckInsParams(arg))
+	return convertAll(rows, func(r pgdb.GetVenueCheckInsRow) GetVenueCheckInsRow {
+		return GetVenueCheckInsRow{CheckIn: toCheckIn(r.CheckIn), BeerName: r.BeerName, Username: r.Username}
+	}), err
+}
+
+func (p postgresQueries) GetVenueTopBeers(ctx context.Context, arg GetVenueTopBeersParams) ([]GetVenueTopBeersRow, error) {
+	rows, err := p.q.GetVenueTopBeers(ctx, pgdb.GetVenueTopBeersParams(arg))
+	return convertAll(rows, func(r pgdb.GetVenueTopBeersRow) GetVenueTopBeersRow { return GetVenueTopBeersRow(r) }), err
+}
+
+/* === ROUNDS === */
+
+func (p postgresQueries) AddRound(ctx context.Context, arg AddRoundParams) (Round, error) {
+	round, err := p.q.AddRound(ctx, pgdb.AddRoundParams(arg))
+	return toRound(round), err
+}
+
+func (p postgresQueries) AddRoundRecipient(ctx context.Context, arg AddRoundRecipientParams) error {
+	return p.q.AddRoundRecipient(ctx, pgdb.AddRoundRecipientParams(arg))
+}
+
+func (p postgresQueries) GetRound(ctx context.Context, id int64) (Round, error) {
+	round, err := p.q.GetRound(ctx, id)
+	return toRound(round), err
+}
+
+func (p postgresQueries) GetSessionRounds(ctx context.Context, sessionID int64) ([]GetSessionRoundsRow, error) {
+	rows, err := p.q.GetSessionRounds(ctx, sessionID)
+	return convertAll(rows, func(r pgdb.GetSessionRoundsRow) GetSessionRoundsRow {
+		return GetSessionRoundsRow{Round: toRound(r.Round), Username: r.Username}
+	}), err
+}
+
+func (p postgresQueries) GetSessionRoundRecipients(ctx context.Context, sessionID int64) ([]GetSessionRoundRecipientsRow, error) {
+	rows, err := p.q.GetSessionRoundRecipients(ctx, sessionID)
+	return convertAll(rows, func(r pgdb.GetSessionRoundRecipientsRow) GetSessionRoundRecipientsRow {
+		return GetSessionRoundRecipientsRow(r)
+	}), err
+}
+
+func (p postgresQueries) DeleteRound(ctx context.Context, id int64) (Round, error) {
+	round, err := p.q.DeleteRound(ctx, id)
+	return toRound(round), err
+}
+
+/* === FOLLOWS === */
+
+func (p postgresQueries) Follow(ctx context.Context, arg FollowParams) error {
+	return p.q.Follow(ctx, pgdb.FollowParams(arg))
+}
+
+func (p postgresQueries) Unfollow(ctx context.Context, arg UnfollowParams) error {
+	return p.q.Unfollow(ctx, pgdb.UnfollowParams(arg))
+}
+
+func (p postgresQueries) GetFollowing(ctx context.Context, followerID int64) ([]GetFollowingRow, error) {
+	rows, err := p.q.GetFollowing(ctx, followerID)
+	return convertAll(rows, func(r pgdb.GetFollowingRow) GetFollowingRow { return GetFollowingRow(r) }), err
+}
+
+func (p postgresQueries) GetFollowers(ctx context.Context, followeeID int64) ([]GetFollowersRow, error) {
+	rows, err := p.q.GetFollowers(ctx, followeeID)
+	return convertAll(rows, func(r pgdb.GetFollowersRow) GetFollowersRow { return GetFollowersRow(r) }), err
+}
+
+func (p postgresQueries) SetPrivacy(ctx context.Context, arg SetPrivacyParams) (UserPrivacy, error) {
+	privacy, err := p.q.SetPrivacy(ctx, pgdb.SetPrivacyParams(arg))
+	return toUserPrivacy(privacy), err
+}
+
+func (p postgresQueries) GetPrivacy(ctx context.Context, userID int64) (UserPrivacy, error) {
+	privacy, err := p.q.GetPrivacy(ctx, userID)
+	return toUserPrivacy(privacy), err
+}
+
+/* === ACTIVITIES === */
+
+func (p postgresQueries) AddActivity(ctx context.Context, arg AddActivityParams) (Activity, error) {
+	activity, err := p.q.AddActivity(ctx, pgdb.AddActivityParams(arg))
+	return toActivity(activity), err
+}
+
+func (p postgresQueries) GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow, error) {
+	rows, err := p.q.GetFeed(ctx, pgdb.GetFeedParams(arg))
+	return convertAll(rows, func(r pgdb.GetFeedRow) GetFeedRow {
+		return GetFeedRow{Activity: toActivity(r.Activity), Username: r.Username, BeerName: r.BeerName, BrewerName: r.BrewerName}
+	}), err
+}
+
+func (p postgresQueries) GetUserActivities(ctx context.Context, arg GetUserActivitiesParams) ([]GetUserActivitiesRow, error) {
+	rows, err := p.q.GetUserActivities(ctx, pgdb.GetUserActivitiesParams(arg))
+	return convertAll(rows, func(r pgdb.GetUserActivitiesRow) GetUserActivitiesRow {
+		return GetUserActivitiesRow{Activity: toActivity(r.Activity), Username: r.Username, BeerName: r.BeerName, BrewerName: r.BrewerName}
+	}), err
+}
+
+/* === COMMENTS === */
+
+func (p postgresQueries) AddComment(ctx context.Context, arg AddCommentParams) (Comment, error) {
+	comment, err := p.q.AddComment(ctx, pgdb.AddCommentParams(arg))
+	return toComment(comment), err
+}
+
+func (p postgresQueries) GetComment(ctx context.Context, id int64) (Comment, error) {
+	comment, err := p.q.GetComment(ctx, id)
+	return toComment(comment), err
+}
+
+func (p postgresQueries) GetComments(ctx context.Context, arg GetCommentsParams) ([]GetCommentsRow, error) {
+	rows, err := p.q.GetComments(ctx, pgdb.GetCommentsParams(arg))
+	return convertAll(rows, func(r pgdb.GetCommentsRow) GetCommentsRow {
+		return GetCommentsRow{Comment: toComment(r.Comment), Username: r.Username}
+	}), err
+}
+
+func (p postgresQueries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error) {
+	comment, err := p.q.UpdateComment(ctx, pgdb.UpdateCommentParams(arg))
+	return toComment(comment), err
+}
+
+func (p postgresQueries) DeleteComment(ctx context.Context, arg DeleteCommentParams) (Comment, error) {
+	comment, err := p.q.DeleteComment(ctx, pgdb.DeleteCommentParams(arg))
+	return toComment(comment), err
+}
+
+func (p postgresQueries) AddReaction(ctx context.Context, arg AddReactionParams) error {
+	return p.q.AddReaction(ctx, pgdb.AddReactionParams(arg))
+}
+
+func (p postgresQueries) DeleteReaction(ctx context.Context, arg DeleteReactionParams) (int64, error) {
+	return p.q.DeleteReaction(ctx, pgdb.DeleteReactionParams(arg))
+}
+
+func (p postgresQueries) GetReactions(ctx context.Context, arg GetReactionsParams) ([]GetReactionsRow, error) {
+	rows, err := p.q.GetReactions(ctx, pgdb.GetReactionsParams(arg))
+	return convertAll(rows, func(r pgdb.GetReactionsRow) GetReactionsRow { return GetReactionsRow(r) }), err
+}
+
+/* === TASTINGS === */
+
+func (p postgresQueries) AddTasting(ctx context.Context, arg AddTastingParams) (Tasting, error) {
+	tasting, err := p.q.AddTasting(ctx, pgdb.AddTastingParams(arg))
+	return toTasting(tasting), err
+}
+
+func (p postgresQueries) GetTasting(ctx context.Context, id int64) (Tasting, error) {
+	tasting, err := p.q.GetTasting(ctx, id)
+	return toTasting(tasting), err
+}
+
+func (p postgresQueries) GetTastings(ctx context.Context) ([]GetTastingsRow, error) {
+	rows, err := p.q.GetTastings(ctx)
+	return convertAll(rows, func(r pgdb.GetTastingsRow) GetTastingsRow {
+		return GetTastingsRow{Tasting: toTasting(r.Tasting), Username: r.Username}
+	}), err
+}
+
+func (p postgresQueries) DeleteTasting(ctx context.Context, id int64) (Tasting, error) {
+	tasting, err := p.q.DeleteTasting(ctx, id)
+	return toTasting(tasting), err
+}
+
+func (p postgresQueries) RevealTasting(ctx context.Context, id int64) (Tasting, error) {
+	tasting, err := p.q.RevealTasting(ctx, id)
+	return toTasting(tasting), err
+}
+
+func (p postgresQueries) AddPour(ctx context.Context, arg AddPourParams) (TastingPour, error) {
+	pour, err := p.q.AddPour(ctx, pgdb.AddPourParams(arg))
+	return toTastingPour(pour), err
+}
+
+func (p postgresQueries) DeletePour(ctx context.Context, arg DeletePourParams) (int64, error) {
+	return p.q.DeletePour(ctx, pgdb.DeletePourParams(arg))
+}
+
+func (p postgresQueries) GetPours(ctx context.Context, tastingID int64) ([]GetPoursRow, error) {
+	rows, err := p.q.GetPours(ctx, tastingID)
+	return convertAll(rows, func(r pgdb.GetPoursRow) GetPoursRow { return GetPoursRow(r) }), err
+}
+
+func (p postgresQueries) SaveTastingScorecard(ctx context.Context, arg SaveTastingScorecardParams) (TastingScorecard, error) {
+	scorecard, err := p.q.SaveTastingScorecard(ctx, pgdb.SaveTastingScorecardParams(arg))
+	return toTastingScorecard(scorecard), err
+}
+
+func (p postgresQueries) GetTastingScorecards(ctx context.Context, tastingID int64) ([]GetTastingScorecardsRow, error) {
+	rows, err := p.q.GetTastingScorecards(ctx, tastingID)
+	return convertAll(rows, func(r pgdb.GetTastingScorecardsRow) GetTastingScorecardsRow {
+		return GetTastingScorecardsRow{TastingScorecard: toTastingScorecard(r.TastingScorecard), Username: r.Username}
+	}), err
+}
+
+/* === BADGES === */
+
+func (p postgresQueries) GetAddedBeerFacts(ctx context.Context, arg GetAddedBeerFactsParams) ([]GetAddedBeerFactsRow, error) {
+	rows, err := p.q.GetAddedBeerFacts(ctx, pgdb.GetAddedBeerFactsParams(arg))
+	return convertAll(rows, func(r pgdb.GetAddedBeerFactsRow) GetAddedBeerFactsRow { return GetAddedBeerFactsRow(r) }), err
+}
+
+func (p postgresQueries) GetRatedBeerFacts(ctx context.Context, arg GetRatedBeerFactsParams) ([]GetRatedBeerFactsRow, error) {
+	rows, err := p.q.GetRatedBeerFacts(ctx, pgdb.GetRatedBeerFactsParams(arg))
+	return convertAll(rows, func(r pgdb.GetRatedBeerFactsRow) GetRatedBeerFactsRow { return GetRatedBeerFactsRow(r) }), err
+}
+
+/* === WISHLIST === */
+
+func (p postgresQueries) AddWish(ctx context.Context, arg AddWishParams) (Wishlist, error) {
+	wish, err := p.q.AddWish(ctx, pgdb.AddWishParams(arg))
+	return toWishlist(wish), err
+}
+
+func (p postgresQueries) UpdateWish(ctx context.Context, arg UpdateWishParams) (Wishlist, error) {
+	wish, err := p.q.UpdateWish(ctx, pgdb.UpdateWishParams(arg))
+	return toWishlist(wish), err
+}
+
+func (p postgresQueries) DeleteWish(ctx context.Context, arg DeleteWishParams) (int64, error) {
+	return p.q.DeleteWish(ctx, pgdb.DeleteWishParams(arg))
+}
+
+func (p postgresQueries) GetWishlist(ctx context.Context, userID int64) ([]GetWishlistRow, error) {
+	rows, err := p.q.GetWishlist(ctx, userID)
+	return convertAll(rows, func(r pgdb.GetWishlistRow) GetWishlistRow {
+		return GetWishlistRow{Wishlist: toWishlist(r.Wishlist), Beer: toBeer(r.Beer), BrewerName: r.BrewerName}
+	}), err
+}
@@ -0,0 +1,277 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Querier interface {
+	// === ACTIVITIES ===
+	AddActivity(ctx context.Context, arg AddActivityParams) (Activity, error)
+	// === BARCODES ===
+	// Codes of beers in the trash are taken over, but not those of other beers
+	AddBarcode(ctx context.Context, arg AddBarcodeParams) (Barcode, error)
+	// === BEERS ===
+	AddBeer(ctx context.Context, arg AddBeerParams) (Beer, error)
+	// === BEER REVISIONS ===
+	AddBeerRevision(ctx context.Context, arg AddBeerRevisionParams) (BeerRevision, error)
+	AddBeerTag(ctx context.Context, arg AddBeerTagParams) error
+	// === BREWERS ===
+	AddBrewer(ctx context.Context, arg AddBrewerParams) (Brewer, error)
+	AddCheckIn(ctx context.Context, arg AddCheckInParams) (CheckIn, error)
+	// === COMMENTS ===
+	AddComment(ctx context.Context, arg AddCommentParams) (Comment, error)
+	// === DRINKS ===
+	AddDrink(ctx context.Context, arg AddDrinkParams) (Drink, error)
+	// === DRINKING SESSIONS ===
+	AddDrinkingSession(ctx context.Context, arg AddDrinkingSessionParams) (DrinkingSession, error)
+	// === LABEL PHOTOS ===
+	AddLabelPhoto(ctx context.Context, arg AddLabelPhotoParams) (LabelPhoto, error)
+	// === PASSWORD RESETS ===
+	AddPasswordReset(ctx context.Context, arg AddPasswordResetParams) (PasswordReset, error)
+	AddPour(ctx context.Context, arg AddPourParams) (TastingPour, error)
+	AddReaction(ctx context.Context, arg AddReactionParams) error
+	// === ROUNDS ===
+	AddRound(ctx context.Context, arg AddRoundParams) (Round, error)
+	AddRoundRecipient(ctx context.Context, arg AddRoundRecipientParams) error
+	// === SCHEDULES ===
+	AddSchedule(ctx context.Context, arg AddScheduleParams) (Schedule, error)
+	AddSessionDrink(ctx context.Context, arg AddSessionDrinkParams) error
+	AddSessionParticipant(ctx context.Context, arg AddSessionParticipantParams) error
+	AddSessionVenue(ctx context.Context, arg AddSessionVenueParams) (DrinkingSessionVenue, error)
+	// === STOCK ===
+	AddStock(ctx context.Context, arg AddStockParams) (Stock, error)
+	// === STYLES ===
+	AddStyle(ctx context.Context, arg AddStyleParams) (Style, error)
+	// === TAGS ===
+	AddTag(ctx context.Context, arg AddTagParams) (Tag, error)
+	// === TASTINGS ===
+	AddTasting(ctx context.Context, arg AddTastingParams) (Tasting, error)
+	// === CONTACTS ===
+	AddUser(ctx context.Context, arg AddUserParams) (User, error)
+	// === VENUES ===
+	AddVenue(ctx context.Context, arg AddVenueParams) (Venue, error)
+	// === WEBHOOKS ===
+	AddWebhook(ctx context.Context, arg AddWebhookParams) (Webhook, error)
+	// === WISHLIST ===
+	AddWish(ctx context.Context, arg AddWishParams) (Wishlist, error)
+	ClearBeerTags(ctx context.Context, beerID int64) error
+	CountBeers(ctx context.Context) (int64, error)
+	CountBrewers(ctx context.Context) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
+	DeleteBarcode(ctx context.Context, code string) (Barcode, error)
+	DeleteBeer(ctx context.Context, id int64) (Beer, error)
+	DeleteBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error)
+	DeleteBrewer(ctx context.Context, id int64) (Brewer, error)
+	DeleteBudget(ctx context.Context, userID int64) (Budget, error)
+	DeleteCheckIn(ctx context.Context, id int64) (CheckIn, error)
+	// Blanks the comment, keeping it for the replies to it
+	DeleteComment(ctx context.Context, arg DeleteCommentParams) (Comment, error)
+	DeleteDrink(ctx context.Context, id int64) (Drink, error)
+	// Deletes the session along with its participants and venues. The drinks had in it are kept, just
+	// no longer in a session.
+	DeleteDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error)
+	DeleteGoals(ctx context.Context, userID int64) (Goal, error)
+	DeleteLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error)
+	DeletePour(ctx context.Context, arg DeletePourParams) (int64, error)
+	DeleteReaction(ctx context.Context, arg DeleteReactionParams) (int64, error)
+	// Deletes the round along with who it was bought for
+	DeleteRound(ctx context.Context, id int64) (Round, error)
+	DeleteSchedule(ctx context.Context, id int64) (Schedule, error)
+	DeleteStock(ctx context.Context, id int64) (Stock, error)
+	DeleteTasting(ctx context.Context, id int64) (Tasting, error)
+	DeleteUser(ctx context.Context, id int64) (User, error)
+	DeleteUserEmail(ctx context.Context, userID int64) (UserEmail, error)
+	// Deletes the venue along with the check-ins at it
+	DeleteVenue(ctx context.Context, id int64) (Venue, error)
+	DeleteWebhook(ctx context.Context, id int64) (Webhook, error)
+	DeleteWish(ctx context.Context, arg DeleteWishParams) (int64, error)
+	EndDrinkingSession(ctx context.Context, arg EndDrinkingSessionParams) (DrinkingSession, error)
+	// === FOLLOWS ===
+	Follow(ctx context.Context, arg FollowParams) error
+	// Drinks counted by the whole number of their ABV, e.g. 4.8% is counted under 4
+	GetAbvDistribution(ctx context.Context, arg GetAbvDistributionParams) ([]GetAbvDistributionRow, error)
+	// === BADGES ===
+	GetAddedBeerFacts(ctx context.Context, arg GetAddedBeerFactsParams) ([]GetAddedBeerFactsRow, error)
+	GetAllBeerTags(ctx context.Context) ([]GetAllBeerTagsRow, error)
+	// The schedules of everyone who isn't deleted, with where they are for the scheduler
+	GetAllSchedules(ctx context.Context) ([]GetAllSchedulesRow, error)
+	GetBarcode(ctx context.Context, code string) (Barcode, error)
+	GetBeerBarcodes(ctx context.Context, beerID int64) ([]Barcode, error)
+	GetBeerById(ctx context.Context, id int64) (Beer, error)
+	GetBeerLabelPhotos(ctx context.Context, beerID int64) ([]LabelPhoto, error)
+	GetBeerRevision(ctx context.Context, arg GetBeerRevisionParams) (BeerRevision, error)
+	GetBeerRevisions(ctx context.Context, beerID int64) ([]GetBeerRevisionsRow, error)
+	GetBeerScoreAverages(ctx context.Context, beerID int64) (GetBeerScoreAveragesRow, error)
+	GetBeerScorecards(ctx context.Context, beerID int64) ([]GetBeerScorecardsRow, error)
+	GetBeerStyles(ctx context.Context) ([]sql.NullString, error)
+	GetBeerTags(ctx context.Context, beerID int64) ([]Tag, error)
+	GetBeers(ctx context.Context) ([]Beer, error)
+	GetBeersByTag(ctx context.Context, name string) ([]Beer, error)
+	GetBrewerById(ctx context.Context, id int64) (Brewer, error)
+	GetBrewers(ctx context.Context) ([]Brewer, error)
+	GetBudget(ctx context.Context, userID int64) (Budget, error)
+	GetCheckIn(ctx context.Context, id int64) (CheckIn, error)
+	GetComment(ctx context.Context, id int64) (Comment, error)
+	// The comments on the beer or brewer with who wrote them, oldest first, including deleted ones
+	// but leaving out those by deleted users. Only one of the ids is given.
+	GetComments(ctx context.Context, arg GetCommentsParams) ([]GetCommentsRow, error)
+	GetDeletedBeers(ctx context.Context) ([]Beer, error)
+	GetDeletedBrewers(ctx context.Context) ([]Brewer, error)
+	GetDeletedUsers(ctx context.Context) ([]User, error)
+	// The latest deliveries first, for the delivery log
+	GetDeliveries(ctx context.Context, maxResults int64) ([]GetDeliveriesRow, error)
+	GetDrink(ctx context.Context, id int64) (Drink, error)
+	GetDrinkingSession(ctx context.Context, id int64) (DrinkingSession, error)
+	GetDrinkingSessionByToken(ctx context.Context, shareToken string) (DrinkingSession, error)
+	// === STATS ===
+	GetDrinksPerDay(ctx context.Context, arg GetDrinksPerDayParams) ([]GetDrinksPerDayRow, error)
+	// Deliveries to try now, with where to send them and the secret to sign them with
+	GetDueDeliveries(ctx context.Context, arg GetDueDeliveriesParams) ([]GetDueDeliveriesRow, error)
+	GetDueEmails(ctx context.Context, arg GetDueEmailsParams) ([]Outbox, error)
+	// The activity of the users the viewer follows, newest first, from before the given id. Only
+	// activity the viewer may see is included: that of public users, and that of users who keep it to
+	// their friends when they and the viewer follow each other. Deleted users, beers and brewers are
+	// left out.
+	GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow, error)
+	// Who follows the user, leaving out deleted users
+	GetFollowers(ctx context.Context, followeeID int64) ([]GetFollowersRow, error)
+	// Who the user follows, leaving out deleted users
+	GetFollowing(ctx context.Context, followerID int64) ([]GetFollowingRow, error)
+	// Soonest best-before first, with the entries which don't have one last
+	GetFridge(ctx context.Context) ([]GetFridgeRow, error)
+	GetGoals(ctx context.Context, userID int64) (Goal, error)
+	GetLabelPhoto(ctx context.Context, id int64) (LabelPhoto, error)
+	// The latest emails first, for seeing what's been sent and what's stuck
+	GetOutbox(ctx context.Context, maxResults int64) ([]Outbox, error)
+	GetPasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error)
+	GetPours(ctx context.Context, tastingID int64) ([]GetPoursRow, error)
+	GetPrivacy(ctx context.Context, userID int64) (UserPrivacy, error)
+	GetPurchases(ctx context.Context, purchasedOn time.Time) ([]GetPurchasesRow, error)
+	GetRatedBeerFacts(ctx context.Context, arg GetRatedBeerFactsParams) ([]GetRatedBeerFactsRow, error)
+	// The different beers drunk, counted by the whole number of their rating, e.g. 7.5 is counted
+	// under 7
+	GetRatingHistogram(ctx context.Context, arg GetRatingHistogramParams) ([]GetRatingHistogramRow, error)
+	// The reactions to the comments on the beer or brewer, leaving out those of deleted users. Only
+	// one of the ids is given.
+	GetReactions(ctx context.Context, arg GetReactionsParams) ([]GetReactionsRow, error)
+	GetRound(ctx context.Context, id int64) (Round, error)
+	GetSchedule(ctx context.Context, id int64) (Schedule, error)
+	GetScorecard(ctx context.Context, arg GetScorecardParams) (Scorecard, error)
+	// === SCORECARDS ===
+	GetScorecardWeights(ctx context.Context) (ScorecardWeight, error)
+	GetScorecards(ctx context.Context) ([]Scorecard, error)
+	// The drinks had in the session in the order they were had, with who had them, leaving out
+	// deleted users like the participants do
+	GetSessionDrinks(ctx context.Context, sessionID int64) ([]GetSessionDrinksRow, error)
+	// The session's participants in the order they joined, leaving out deleted users
+	GetSessionParticipants(ctx context.Context, sessionID int64) ([]GetSessionParticipantsRow, error)
+	// Who each round in the session was bought for, leaving out deleted users
+	GetSessionRoundRecipients(ctx context.Context, sessionID int64) ([]GetSessionRoundRecipientsRow, error)
+	// The rounds bought in the session with who bought them, in the order they were bought, leaving
+	// out those bought by deleted users
+	GetSessionRounds(ctx context.Context, sessionID int64) ([]GetSessionRoundsRow, error)
+	GetSessionVenues(ctx context.Context, sessionID int64) ([]DrinkingSessionVenue, error)
+	GetSpendingByBrewer(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByBrewerRow, error)
+	GetSpendingByStyle(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByStyleRow, error)
+	// === SPENDING ===
+	// Beers in the trash still count, since the money's been spent
+	GetSpendingByUser(ctx context.Context, purchasedOn time.Time) ([]GetSpendingByUserRow, error)
+	GetStock(ctx context.Context, id int64) (Stock, error)
+	GetStockLevels(ctx context.Context) ([]GetStockLevelsRow, error)
+	GetStyleById(ctx context.Context, id int64) (Style, error)
+	GetStyleByName(ctx context.Context, name string) (Style, error)
+	GetStyles(ctx context.Context) ([]Style, error)
+	GetTagByName(ctx context.Context, name string) (Tag, error)
+	GetTagCounts(ctx context.Context) ([]GetTagCountsRow, error)
+	GetTags(ctx context.Context) ([]Tag, error)
+	GetTasting(ctx context.Context, id int64) (Tasting, error)
+	GetTastingScorecards(ctx context.Context, tastingID int64) ([]GetTastingScorecardsRow, error)
+	GetTastings(ctx context.Context) ([]GetTastingsRow, error)
+	GetTopBrewers(ctx context.Context, arg GetTopBrewersParams) ([]GetTopBrewersRow, error)
+	GetTopStyles(ctx context.Context, arg GetTopStylesParams) ([]GetTopStylesRow, error)
+	// The user's activity as the viewer may see it, newest first, from before the given id. Users
+	// always see their own activity; others see it as they would in their feed.
+	GetUserActivities(ctx context.Context, arg GetUserActivitiesParams) ([]GetUserActivitiesRow, error)
+	GetUserById(ctx context.Context, id int64) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	// The user's check-ins with where they were and what they had, latest first
+	GetUserCheckIns(ctx context.Context, arg GetUserCheckInsParams) ([]GetUserCheckInsRow, error)
+	// The sessions the user is in, latest first
+	GetUserDrinkingSessions(ctx context.Context, userID int64) ([]DrinkingSession, error)
+	// The user's drinks on the days from since up to but not including until, latest first. Beers
+	// in the trash are still included, since they were still drunk.
+	GetUserDrinks(ctx context.Context, arg GetUserDrinksParams) ([]GetUserDrinksRow, error)
+	GetUserEmail(ctx context.Context, userID int64) (UserEmail, error)
+	GetUserEmailByAddress(ctx context.Context, email string) (UserEmail, error)
+	// The addresses of everyone who isn't deleted, with where they are for the weekly summaries
+	GetUserEmails(ctx context.Context) ([]GetUserEmailsRow, error)
+	GetUserSchedules(ctx context.Context, userID int64) ([]Schedule, error)
+	GetUserSpending(ctx context.Context, arg GetUserSpendingParams) ([]GetUserSpendingRow, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	GetVenue(ctx context.Context, id int64) (Venue, error)
+	// The check-ins at the venue with who had what, latest first, leaving out deleted users
+	GetVenueCheckIns(ctx context.Context, arg GetVenueCheckInsParams) ([]GetVenueCheckInsRow, error)
+	// The beers had most at the venue, most first and then by name
+	GetVenueTopBeers(ctx context.Context, arg GetVenueTopBeersParams) ([]GetVenueTopBeersRow, error)
+	GetVenues(ctx context.Context) ([]Venue, error)
+	// The venues with coordinates inside the box, for narrowing down which are near somewhere
+	GetVenuesInBox(ctx context.Context, arg GetVenuesInBoxParams) ([]Venue, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWishlist(ctx context.Context, userID int64) ([]GetWishlistRow, error)
+	PurgeBeer(ctx context.Context, id int64) (Beer, error)
+	PurgeBrewer(ctx context.Context, id int64) (Brewer, error)
+	PurgeDeletedBeers(ctx context.Context, deletedBefore sql.NullTime) (int64, error)
+	PurgeDeletedBrewers(ctx context.Context, deletedBefore sql.NullTime) (int64, error)
+	PurgeDeletedUsers(ctx context.Context, deletedBefore sql.NullTime) (int64, error)
+	PurgeUser(ctx context.Context, id int64) (User, error)
+	QueueDelivery(ctx context.Context, arg QueueDeliveryParams) (WebhookDelivery, error)
+	// === OUTBOX ===
+	QueueEmail(ctx context.Context, arg QueueEmailParams) (Outbox, error)
+	RenameBeerStyle(ctx context.Context, arg RenameBeerStyleParams) ([]Beer, error)
+	RestoreBeer(ctx context.Context, id int64) (Beer, error)
+	RestoreBrewer(ctx context.Context, id int64) (Brewer, error)
+	RestoreUser(ctx context.Context, id int64) (User, error)
+	RevealTasting(ctx context.Context, id int64) (Tasting, error)
+	RevertBeer(ctx context.Context, arg RevertBeerParams) (Beer, error)
+	SaveScorecard(ctx context.Context, arg SaveScorecardParams) (Scorecard, error)
+	SaveTastingScorecard(ctx context.Context, arg SaveTastingScorecardParams) (TastingScorecard, error)
+	SearchBeers(ctx context.Context, query sql.NullString) ([]Beer, error)
+	SearchStyles(ctx context.Context, arg SearchStylesParams) ([]Style, error)
+	SetBeerRating(ctx context.Context, arg SetBeerRatingParams) error
+	// === BUDGETS ===
+	SetBudget(ctx context.Context, arg SetBudgetParams) (Budget, error)
+	SetDeliveryDelivered(ctx context.Context, arg SetDeliveryDeliveredParams) error
+	// Records a failed attempt, and when to try again, or NULL to give up
+	SetDeliveryFailed(ctx context.Context, arg SetDeliveryFailedParams) error
+	// Records a failed attempt, and when to try again, or NULL to give up
+	SetEmailFailed(ctx context.Context, arg SetEmailFailedParams) error
+	SetEmailSent(ctx context.Context, arg SetEmailSentParams) error
+	// === GOALS ===
+	SetGoals(ctx context.Context, arg SetGoalsParams) (Goal, error)
+	SetPrivacy(ctx context.Context, arg SetPrivacyParams) (UserPrivacy, error)
+	SetScheduleReminded(ctx context.Context, arg SetScheduleRemindedParams) error
+	SetScorecardTotal(ctx context.Context, arg SetScorecardTotalParams) error
+	SetScorecardWeights(ctx context.Context, arg SetScorecardWeightsParams) (ScorecardWeight, error)
+	SetSummarySent(ctx context.Context, arg SetSummarySentParams) error
+	// === EMAILS ===
+	SetUserEmail(ctx context.Context, arg SetUserEmailParams) (UserEmail, error)
+	SetUserLastLogin(ctx context.Context, id int64) error
+	SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error
+	// Takes from the entry which goes off first
+	TakeStock(ctx context.Context, beerID int64) (Stock, error)
+	Unfollow(ctx context.Context, arg UnfollowParams) error
+	UpdateBeer(ctx context.Context, arg UpdateBeerParams) (Beer, error)
+	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error)
+	UpdateWish(ctx context.Context, arg UpdateWishParams) (Wishlist, error)
+	// Only the first use of a link counts, so it can't be used twice at once
+	UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (int64, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// What can begin a transaction, like the *sql.DB the queries were made with
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// The transaction a context is in, and the queries which go through it
+type txKey struct{}
+
+type txValue struct {
+	tx      *sql.Tx
+	queries Querier
+	// What to do once the transaction's committed
+	afterCommit *[]func()
+}
+
+// Runs fn with queries which all go through one transaction, committing it if fn succeeds and
+// rolling it back if it doesn't. The context fn is given carries the transaction, so stores called
+// with it join the transaction through Joined. When the context is already in a transaction, fn
+// runs in a savepoint of it instead, so a failed statement doesn't spoil the rest of the outer
+// transaction. Queries which can't begin a transaction are passed to fn as they are.
+func InTx(ctx context.Context, queries Querier, fn func(ctx context.Context, queries Querier) error) error {
+	if outer, ok := ctx.Value(txKey{}).(txValue); ok {
+		if _, err := outer.tx.ExecContext(ctx, "SAVEPOINT nested"); err != nil {
+			return err
+		}
+		pending := len(*outer.afterCommit)
+		if err := fn(ctx, outer.queries); err != nil {
+			outer.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT nested")
+			// What was rolled back won't be committed
+			*outer.afterCommit = (*outer.afterCommit)[:pending]
+			return err
+		}
+		_, err := outer.tx.ExecContext(ctx, "RELEASE SAVEPOINT nested")
+		return err
+	}
+
+	var beginner txBeginner
+	var withTx func(tx *sql.Tx) Querier
+	switch q := queries.(type) {
+	case *Queries:
+		beginner, _ = q.db.(txBeginner)
+		withTx = func(tx *sql.Tx) Querier { return q.WithTx(tx) }
+	case postgresQueries:
+		beginner, _ = q.db.(txBeginner)
+		withTx = func(tx *sql.Tx) Querier { return postgresQueries{q: q.q.WithTx(tx), db: tx} }
+	}
+	if beginner == nil {
+		return fn(ctx, queries)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	txQueries := withTx(tx)
+	afterCommit := []func(){}
+	txCtx := context.WithValue(ctx, txKey{}, txValue{tx: tx, queries: txQueries, afterCommit: &afterCommit})
+	if err := fn(txCtx, txQueries); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, f := range afterCommit {
+		f()
+	}
+	return nil
+}
+
+// Runs f once the transaction the context is in has been committed, and not at all if it's rolled
+// back, or straight away if the context isn't in a transaction
+func AfterCommit(ctx context.Context, f func()) {
+	if outer, ok := ctx.Value(txKey{}).(txValue); ok {
+		*outer.afterCommit = append(*outer.afterCommit, f)
+		return
+	}
+	f()
+}
+
+// The queries of the transaction the context is in, or the given queries if it isn't in one
+func Joined(ctx context.Context, queries Querier) Querier {
+	if outer, ok := ctx.Value(txKey{}).(txValue); ok {
+		return outer.queries
+	}
+	return queries
+}
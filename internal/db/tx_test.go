@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestInTx(t *testing.T) {
+	dbPool, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbPool.Close()
+	// Every connection to :memory: gets its own empty database, so stick to one
+	dbPool.SetMaxOpenConns(1)
+	if err := GenSchema(dbPool); err != nil {
+		t.Fatal(err)
+	}
+	queries := New(dbPool)
+	ctx := context.Background()
+	failed := errors.New("failed")
+	var committed []string
+
+	// A nested transaction which fails is rolled back on its own, and the queries joined through
+	// the context go through the outer transaction
+	err = InTx(ctx, queries, func(ctx context.Context, txQueries Querier) error {
+		if _, err := txQueries.AddBrewer(ctx, AddBrewerParams{Name: "Felon's"}); err != nil {
+			return err
+		}
+		AfterCommit(ctx, func() { committed = append(committed, "Felon's") })
+		err := InTx(ctx, queries, func(ctx context.Context, txQueries Querier) error {
+			txQueries.AddBrewer(ctx, AddBrewerParams{Name: "Balter"})
+			AfterCommit(ctx, func() { committed = append(committed, "Balter") })
+			return failed
+		})
+		if err != failed {
+			t.Errorf("nested transaction: got %v, want %v", err, failed)
+		}
+		_, err = Joined(ctx, queries).AddBrewer(ctx, AddBrewerParams{Name: "Stone & Wood"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A transaction which fails is rolled back
+	err = InTx(ctx, queries, func(ctx context.Context, txQueries Querier) error {
+		txQueries.AddBrewer(ctx, AddBrewerParams{Name: "Bentspoke"})
+		AfterCommit(ctx, func() { committed = append(committed, "Bentspoke") })
+		return failed
+	})
+	if err != failed {
+		t.Errorf("transaction: got %v, want %v", err, failed)
+	}
+
+	brewers, err := queries.GetBrewers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, brewer := range brewers {
+		names = append(names, brewer.Name)
+	}
+	if len(names) != 2 || names[0] != "Felon's" || names[1] != "Stone & Wood" {
+		t.Errorf("brewers: got %v, want [Felon's Stone & Wood]", names)
+	}
+	// Only what was committed is followed up on
+	if len(committed) != 1 || committed[0] != "Felon's" {
+		t.Errorf("after commit: got %v, want [Felon's]", committed)
+	}
+}
@@ -0,0 +1,27 @@
+// Package drinks works out how much alcohol is in a drink, in standard drinks
+package drinks
+
+// The alcohol in an Australian standard drink, in grams. Other countries use between 8 and 14.
+const GramsPerStandardDrink = 10.0
+
+// How much a millilitre of ethanol weighs, in grams
+const EthanolDensity = 0.789
+
+// The standard drinks in the given millilitres of pure alcohol
+func StandardDrinksOfAlcohol(alcoholMl float64) float64 {
+	return alcoholMl * EthanolDensity / GramsPerStandardDrink
+}
+
+// The standard drinks in a serving of a beer with the ABV, as a percentage
+func StandardDrinks(servingMl float64, abv float64) float64 {
+	return StandardDrinksOfAlcohol(servingMl * abv / 100)
+}
+
+// What each standard drink costs when the price is for the given standard drinks. It's false for
+// drinks without any alcohol, where it doesn't mean anything.
+func CostPerStandardDrink(price float64, standardDrinks float64) (float64, bool) {
+	if standardDrinks <= 0 {
+		return 0, false
+	}
+	return price / standardDrinks, true
+}
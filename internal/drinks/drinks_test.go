@@ -0,0 +1,34 @@
+package drinks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStandardDrinks(t *testing.T) {
+	for _, tc := range []struct {
+		servingMl float64
+		abv       float64
+		want      float64
+	}{
+		// As printed on the labels
+		{375, 4.8, 1.4},
+		{375, 3.5, 1.0},
+		{750, 13.5, 8.0},
+		{30, 40, 0.9},
+		{375, 0, 0},
+	} {
+		if got := StandardDrinks(tc.servingMl, tc.abv); math.Abs(got-tc.want) > 0.05 {
+			t.Errorf("StandardDrinks(%v, %v): got %.2f, want %.1f", tc.servingMl, tc.abv, got, tc.want)
+		}
+	}
+}
+
+func TestCostPerStandardDrink(t *testing.T) {
+	if cost, ok := CostPerStandardDrink(6, StandardDrinks(375, 4.8)); !ok || math.Abs(cost-4.22) > 0.01 {
+		t.Errorf("cost of a can: got %.2f, %v", cost, ok)
+	}
+	if _, ok := CostPerStandardDrink(6, StandardDrinks(375, 0)); ok {
+		t.Error("expected no cost per standard drink of an alcohol-free beer")
+	}
+}
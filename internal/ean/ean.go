@@ -0,0 +1,63 @@
+// Package ean checks the barcodes printed on cans and bottles, and looks up the products they're
+// for
+package ean
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrInvalidBarcode struct {
+	Code   string
+	Reason string
+}
+
+func (e ErrInvalidBarcode) Error() string {
+	return fmt.Sprintf("barcode %s %s", e.Code, e.Reason)
+}
+
+// Checks an EAN-8, EAN-13 or UPC-A barcode, ignoring spaces and dashes, and returns it in the form
+// it's stored in. UPC-A codes are EAN-13 codes starting with 0, so they're given the 0 and a can
+// scanned either way finds the same beer.
+func Parse(code string) (string, error) {
+	code = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidBarcode{Code: code, Reason: "must only contain digits"}
+		}
+	}
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	default:
+		return "", ErrInvalidBarcode{Code: code, Reason: "must be 8, 12 or 13 digits long"}
+	}
+
+	last := len(code) - 1
+	if CheckDigit(code[:last]) != code[last] {
+		return "", ErrInvalidBarcode{Code: code, Reason: "has the wrong check digit"}
+	}
+	return code, nil
+}
+
+// The check digit which goes on the end of the other digits. Counting from the right, the digits
+// are weighted 3, 1, 3, 1... and the check digit brings the sum up to a multiple of 10.
+func CheckDigit(digits string) byte {
+	sum := 0
+	for i := range len(digits) {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,65 @@
+package ean
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		code string
+		want string
+	}{
+		{"96385074", "96385074"},           // EAN-8
+		{"4006381333931", "4006381333931"}, // EAN-13
+		{"400 6381-333931", "4006381333931"},
+		{"036000291452", "0036000291452"}, // UPC-A
+		{"0036000291452", "0036000291452"},
+	} {
+		if got, err := Parse(tc.code); err != nil || got != tc.want {
+			t.Errorf("Parse(%q): got %q, %v, want %q", tc.code, got, err, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		code   string
+		reason string
+	}{
+		{"", "must be 8, 12 or 13 digits long"},
+		{"1234567", "must be 8, 12 or 13 digits long"},
+		{"12345678901234", "must be 8, 12 or 13 digits long"},
+		{"40063813339x1", "must only contain digits"},
+		{"96385075", "has the wrong check digit"},
+		{"4006381333932", "has the wrong check digit"},
+		{"036000291453", "has the wrong check digit"},
+		// Swapping neighbouring digits is the mistake the weights are there to catch
+		{"4006383133931", "has the wrong check digit"},
+	} {
+		_, err := Parse(tc.code)
+		if e, ok := err.(ErrInvalidBarcode); !ok || e.Reason != tc.reason {
+			t.Errorf("Parse(%q): got %v, want %q", tc.code, err, tc.reason)
+		}
+	}
+}
+
+func TestFixtureLookup(t *testing.T) {
+	ctx := context.Background()
+	fl := DefaultFixtureLookup()
+
+	product, err := fl.LookupProduct(ctx, "2000000000039")
+	if err != nil || product.Name != "Draught" || product.Brewer != "Guinness" || product.Abv != 4.2 {
+		t.Errorf("looking up product: got %+v, %v", product, err)
+	}
+	// UPC-A fixtures are found by their EAN-13 form
+	if product, err := fl.LookupProduct(ctx, "0200000000004"); err != nil || product.Name != "Pacific Ale" {
+		t.Errorf("looking up UPC-A product: got %+v, %v", product, err)
+	}
+	if _, err := fl.LookupProduct(ctx, "4006381333931"); err != (ErrProductNotFound{Code: "4006381333931"}) {
+		t.Errorf("looking up unknown product: got %v", err)
+	}
+
+	if _, err := NewFixtureLookup(strings.NewReader(`[{"code": "4006381333932", "name": "Typo"}]`)); err == nil {
+		t.Error("expected an error for a fixture with a wrong check digit")
+	}
+}
@@ -0,0 +1,81 @@
+package ean
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// What an external database knows about the product with a barcode, used to fill in the form for
+// adding a beer. Anything which isn't known is left empty.
+type Product struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Brewer string  `json:"brewer"`
+	Style  string  `json:"style"`
+	Abv    float64 `json:"abv"`
+}
+
+// Looks barcodes up somewhere other than our own beers, e.g. an online product database
+type Lookup interface {
+	LookupProduct(ctx context.Context, code string) (Product, error)
+}
+
+var _ Lookup = (*FixtureLookup)(nil)
+
+type ErrProductNotFound struct {
+	Code string
+}
+
+func (e ErrProductNotFound) Error() string {
+	return fmt.Sprintf("no product found with barcode %s", e.Code)
+}
+
+// The products FixtureLookup knows by default
+//
+//go:embed fixtures.json
+var fixturesJson []byte
+
+// A Lookup which knows a fixed list of products, for tests and for running without a network
+type FixtureLookup struct {
+	products map[string]Product
+}
+
+// Reads the products from a JSON list, checking their barcodes
+func NewFixtureLookup(r io.Reader) (*FixtureLookup, error) {
+	var products []Product
+	if err := json.NewDecoder(r).Decode(&products); err != nil {
+		return nil, err
+	}
+
+	fl := &FixtureLookup{products: make(map[string]Product, len(products))}
+	for _, product := range products {
+		code, err := Parse(product.Code)
+		if err != nil {
+			return nil, err
+		}
+		product.Code = code
+		fl.products[code] = product
+	}
+	return fl, nil
+}
+
+// A FixtureLookup of the embedded products
+func DefaultFixtureLookup() *FixtureLookup {
+	fl, err := NewFixtureLookup(bytes.NewReader(fixturesJson))
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded fixtures.json: %v", err))
+	}
+	return fl
+}
+
+func (fl *FixtureLookup) LookupProduct(ctx context.Context, code string) (Product, error) {
+	product, ok := fl.products[code]
+	if !ok {
+		return Product{}, ErrProductNotFound{Code: code}
+	}
+	return product, nil
+}
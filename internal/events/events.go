@@ -0,0 +1,96 @@
+// Package events is an in-process publish/subscribe bus the stores tell about changes on, so open
+// pages can update without being reloaded
+package events
+
+import "sync"
+
+// What changed
+const (
+	Beers   = "beers"
+	Brewers = "brewers"
+)
+
+// How it changed
+const (
+	Created  = "created"
+	Updated  = "updated"
+	Deleted  = "deleted"
+	Restored = "restored"
+)
+
+// A change to a beer or brewer
+type Event struct {
+	// What changed, e.g. Beers
+	Topic string
+	// How it changed, e.g. Created
+	Action string
+	// The id of the beer or brewer
+	ID int64
+}
+
+// Where stores publish events
+type Publisher interface {
+	Publish(event Event)
+}
+
+var _ Publisher = (*Bus)(nil)
+
+// Passes each event published to everyone subscribed at the time. Publishing never waits for
+// subscribers, so ones which fall behind miss events rather than holding up the stores.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+// Subscribes to every event published from now on, buffering up to the given number of them.
+// The channel is closed once unsubscribe is called or the bus is closed.
+func (b *Bus) Subscribe(buffer int) (events <-chan Event, unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := make(chan Event, buffer)
+	if b.closed {
+		close(subscriber)
+		return subscriber, func() {}
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	return subscriber, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Closes every subscriber's channel, for when the server's shutting down so anything waiting on
+// events stops. Events published afterwards go nowhere.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers {
+		close(subscriber)
+	}
+	clear(b.subscribers)
+	b.closed = true
+}
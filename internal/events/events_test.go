@@ -0,0 +1,59 @@
+package events
+
+import "testing"
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	first, unsubscribeFirst := bus.Subscribe(1)
+	second, unsubscribeSecond := bus.Subscribe(1)
+	defer unsubscribeSecond()
+
+	created := Event{Topic: Beers, Action: Created, ID: 1}
+	bus.Publish(created)
+	if got := <-first; got != created {
+		t.Errorf("first subscriber got %+v, want %+v", got, created)
+	}
+	if got := <-second; got != created {
+		t.Errorf("second subscriber got %+v, want %+v", got, created)
+	}
+
+	// A full subscriber misses events rather than holding up publishing
+	bus.Publish(Event{Topic: Brewers, Action: Deleted, ID: 2})
+	bus.Publish(Event{Topic: Brewers, Action: Restored, ID: 2})
+	if got := <-first; got.Action != Deleted {
+		t.Errorf("got %+v, want the brewer deleted", got)
+	}
+	select {
+	case got := <-first:
+		t.Errorf("got %+v after the buffer was full", got)
+	default:
+	}
+
+	// Unsubscribing closes the channel, and can be done more than once
+	unsubscribeFirst()
+	unsubscribeFirst()
+	<-second
+	bus.Publish(created)
+	if _, ok := <-first; ok {
+		t.Error("got an event after unsubscribing")
+	}
+	if got := <-second; got != created {
+		t.Errorf("second subscriber got %+v, want %+v", got, created)
+	}
+}
+
+func TestBusClose(t *testing.T) {
+	bus := NewBus()
+	before, unsubscribe := bus.Subscribe(1)
+	bus.Close()
+	unsubscribe()
+
+	if _, ok := <-before; ok {
+		t.Error("got an event after closing")
+	}
+	after, _ := bus.Subscribe(1)
+	bus.Publish(Event{Topic: Beers, Action: Created, ID: 1})
+	if _, ok := <-after; ok {
+		t.Error("subscribing after closing got an event")
+	}
+}
@@ -0,0 +1,116 @@
+// Package images checks uploaded images and makes thumbnails of them, using only the standard
+// library's decoders
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"slices"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// The content types which can be uploaded, as sniffed from the data rather than trusting the name
+// or header the browser sent
+var ContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// The most pixels an image can have, so a small file can't decompress into gigabytes of memory
+const MaxPixels = 40_000_000
+
+type ErrUnsupportedType struct {
+	ContentType string
+}
+
+func (e ErrUnsupportedType) Error() string {
+	return fmt.Sprintf("images of type %s are not supported", e.ContentType)
+}
+
+type ErrTooManyPixels struct {
+	Width  int
+	Height int
+}
+
+func (e ErrTooManyPixels) Error() string {
+	return fmt.Sprintf("a %dx%d image is too large", e.Width, e.Height)
+}
+
+// Decodes an uploaded image, returning its sniffed content type
+func Decode(data []byte) (image.Image, string, error) {
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(ContentTypes, contentType) {
+		return nil, "", ErrUnsupportedType{ContentType: contentType}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, "", ErrTooManyPixels{Width: config.Width, Height: config.Height}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	return img, contentType, nil
+}
+
+// Scales the image down to fit within size x size, keeping its aspect ratio. Each pixel of the
+// thumbnail is the average of the pixels it covers, and transparency is flattened onto white since
+// thumbnails are JPEGs. Images which already fit aren't scaled up.
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := srcW, srcH
+	if srcW > size || srcH > size {
+		if srcW >= srcH {
+			dstW, dstH = size, max(1, srcH*size/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*size/srcH), size
+		}
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for dy := 0; dy < dstH; dy++ {
+		y0, y1 := dy*srcH/dstH, max((dy+1)*srcH/dstH, dy*srcH/dstH+1)
+		for dx := 0; dx < dstW; dx++ {
+			x0, x1 := dx*srcW/dstW, max((dx+1)*srcW/dstW, dx*srcW/dstW+1)
+
+			var r, g, b, n int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					r += int(row[x*4])
+					g += int(row[x*4+1])
+					b += int(row[x*4+2])
+					n++
+				}
+			}
+			i := dy*dst.Stride + dx*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// Thumbnails are always JPEGs
+const ThumbnailContentType = "image/jpeg"
+
+func EncodeThumbnail(w io.Writer, thumb image.Image) error {
+	return jpeg.Encode(w, thumb, &jpeg.Options{Quality: 85})
+}
@@ -0,0 +1,71 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func encodePng(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	decoded, contentType, err := Decode(encodePng(t, img))
+	if err != nil || contentType != "image/png" || decoded.Bounds().Dx() != 4 {
+		t.Errorf("decoding png: got %v, %q, %v", decoded.Bounds(), contentType, err)
+	}
+
+	if _, _, err := Decode([]byte("<html><body>not an image</body></html>")); err != (ErrUnsupportedType{ContentType: "text/html; charset=utf-8"}) {
+		t.Errorf("decoding html: got %v", err)
+	}
+
+	// Only the header is read for the size, so this doesn't allocate the pixels
+	huge := encodePng(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+	copy(huge[16:24], []byte{0, 0, 0x4e, 0x20, 0, 0, 0x4e, 0x20}) // 20000x20000
+	binary.BigEndian.PutUint32(huge[29:33], crc32.ChecksumIEEE(huge[12:29]))
+	if _, _, err := Decode(huge); err != (ErrTooManyPixels{Width: 20000, Height: 20000}) {
+		t.Errorf("decoding huge png: got %v", err)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	// Left half black, right half white
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			if x < 200 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+
+	thumb := Thumbnail(img, 100)
+	if thumb.Bounds() != image.Rect(0, 0, 100, 50) {
+		t.Fatalf("thumbnail bounds: got %v", thumb.Bounds())
+	}
+	if c := thumb.RGBAAt(10, 10); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("left of thumbnail: got %v", c)
+	}
+	if c := thumb.RGBAAt(90, 40); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("right of thumbnail: got %v", c)
+	}
+
+	// Small images keep their size, and transparency becomes white
+	small := Thumbnail(image.NewRGBA(image.Rect(0, 0, 30, 60)), 100)
+	if small.Bounds() != image.Rect(0, 0, 30, 60) || small.RGBAAt(0, 0) != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("small thumbnail: got %v, %v", small.Bounds(), small.RGBAAt(0, 0))
+	}
+}
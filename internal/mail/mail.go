@@ -0,0 +1,196 @@
+// Package mail sends emails over SMTP, as multipart messages with both an HTML and a plain text
+// version of the body
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An email to send. Text is the plain text version of HTML, for mail clients which don't show
+// HTML.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sends emails somewhere
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+var _ Sender = (*SMTPSender)(nil)
+var _ Sender = (*LogSender)(nil)
+
+// How long sending one email can take before it's given up on, unless the context ends sooner
+const sendTimeout = 30 * time.Second
+
+// Sends emails through an SMTP server, using STARTTLS when the server offers it
+type SMTPSender struct {
+	host string
+	port int
+	auth smtp.Auth
+	from mail.Address
+}
+
+// Sends from the from address through the server at host and port, logging in as username if it's
+// given. The from address can have a name, like "Beer O'Clock <beer@example.com>".
+func NewSMTPSender(host string, port int, username string, password string, from string) (*SMTPSender, error) {
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", from, err)
+	}
+
+	sender := &SMTPSender{host: host, port: port, from: *fromAddress}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid to address %q: %w", message.To, err)
+	}
+	body, err := Compose(s.from, *to, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.host, strconv.Itoa(s.port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Logs emails rather than sending them, for running without an SMTP server
+type LogSender struct {
+	logger *log.Logger
+}
+
+func NewLogSender(logger *log.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, message Message) error {
+	s.logger.Printf("emailing %s: %s:\n%s", message.To, message.Subject, message.Text)
+	return nil
+}
+
+// Builds the email as it's sent, headers and all, with the plain text version first so clients
+// which can show HTML pick that instead
+func Compose(from mail.Address, to mail.Address, message Message, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	headers := []struct{ name, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", messageId(from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()})},
+	}
+	var header bytes.Buffer
+	for _, h := range headers {
+		fmt.Fprintf(&header, "%s: %s\r\n", h.name, h.value)
+	}
+	header.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(header.Bytes(), buf.Bytes()...), nil
+}
+
+// A unique id for an email, at the from address's domain like mail clients make them
+func messageId(from mail.Address) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndexByte(from.Address, '@'); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"beer_oclock/internal/mail/mailtest"
+)
+
+func TestSMTPSender(t *testing.T) {
+	server := mailtest.NewServer(t)
+	sender, err := NewSMTPSender(server.Host(), server.Port(), "", "", "Beer O'Clock <beer@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := Message{
+		To:      "guest@example.com",
+		Subject: "Prost! 🍺 It's beer o'clock",
+		Text:    "Time for a beer.\n\nA line which is long enough that quoted-printable has to wrap it, as it's over seventy-six characters.\n",
+		HTML:    `<p>Time for a <a href="http://localhost:9000/">beer</a>.</p>`,
+	}
+	if err := sender.Send(context.Background(), message); err != nil {
+		t.Fatalf("Error when sending: %v", err)
+	}
+
+	received := server.Received()
+	if len(received) != 1 {
+		t.Fatalf("Got %d emails, want 1", len(received))
+	}
+	if received[0].From != "beer@example.com" {
+		t.Errorf("Got from %q, want beer@example.com", received[0].From)
+	}
+	if len(received[0].To) != 1 || received[0].To[0] != "guest@example.com" {
+		t.Errorf("Got to %q, want guest@example.com", received[0].To)
+	}
+
+	email, err := received[0].Email()
+	if err != nil {
+		t.Fatalf("Error when reading email: %v", err)
+	}
+	if email.Subject != message.Subject {
+		t.Errorf("Got subject %q, want %q", email.Subject, message.Subject)
+	}
+	if got := email.Header.Get("From"); !strings.Contains(got, "<beer@example.com>") {
+		t.Errorf("Got From header %q, want it to be from beer@example.com", got)
+	}
+	if email.Text != message.Text {
+		t.Errorf("Got text %q, want %q", email.Text, message.Text)
+	}
+	if email.HTML != message.HTML {
+		t.Errorf("Got HTML %q, want %q", email.HTML, message.HTML)
+	}
+}
+
+func TestSMTPSenderFailure(t *testing.T) {
+	server := mailtest.NewServer(t)
+	sender, err := NewSMTPSender(server.Host(), server.Port(), "", "", "beer@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := Message{To: "guest@example.com", Subject: "Hello", Text: "Hello\n", HTML: "<p>Hello</p>"}
+
+	server.FailNext(1)
+	if err := sender.Send(context.Background(), message); err == nil {
+		t.Error("Got no error when the server turned the email away")
+	}
+	if err := sender.Send(context.Background(), message); err != nil {
+		t.Errorf("Error when sending again: %v", err)
+	}
+	if got := len(server.Received()); got != 1 {
+		t.Errorf("Got %d emails, want 1", got)
+	}
+}
+
+func TestNewSMTPSenderInvalidFrom(t *testing.T) {
+	if _, err := NewSMTPSender("localhost", 25, "", "", "not an address"); err == nil {
+		t.Error("Got no error for an invalid from address")
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo\n"},
+		{"headings", "<h1>Title</h1><p>Body</p>", "Title\n\nBody\n"},
+		{"whitespace", "<p>\n\t\tSpread   over\n\t\tlines\n\t</p>", "Spread over lines\n"},
+		{"inline elements", "<p>A <strong>bold</strong> move</p>", "A bold move\n"},
+		{"entities", "<p>Fish &amp; chips, it&#39;s &lt;great&gt;</p>", "Fish & chips, it's <great>\n"},
+		{"links", `<p><a href="http://x/reset?token=a&amp;b">Reset it</a></p>`, "Reset it (http://x/reset?token=a&b)\n"},
+		{"links to themselves", `<p><a href="http://x/">http://x/</a></p>`, "http://x/\n"},
+		{"lists", "<ul><li>One</li><li>Two</li></ul>", "One\nTwo\n"},
+		{"tables", "<table><tr><td>Drinks</td><td>12</td></tr><tr><td>Days</td><td>3</td></tr></table>", "Drinks 12\nDays 3\n"},
+		{"hidden", "<html><head><title>T</title><style>p { color: red }</style></head><body><p>Shown</p></body></html>", "Shown\n"},
+		{"line breaks", "<p>One<br>Two<br/>Three</p>", "One\nTwo\nThree\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PlainText(tc.html); got != tc.want {
+				t.Errorf("PlainText(%q) = %q, want %q", tc.html, got, tc.want)
+			}
+		})
+	}
+}
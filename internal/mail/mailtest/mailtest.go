@@ -0,0 +1,214 @@
+// Package mailtest provides an SMTP server which runs in the test's process, so sending emails
+// can be tested without a real one
+package mailtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// An email the server was sent
+type Received struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// A bare-bones SMTP server which keeps the emails it's sent. It doesn't offer STARTTLS or AUTH, so
+// senders have to be set up without a username.
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	received []Received
+	failures int
+}
+
+// Starts a server on a random local port, which is stopped when the test finishes
+func NewServer(t testing.TB) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error when starting SMTP server: %v", err)
+	}
+	s := &Server{listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *Server) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Makes the server turn away the next n emails with a temporary failure, like a server which is
+// down or too busy
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// The emails the server's been sent, oldest first
+func (s *Server) Received() []Received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Received{}, s.received...)
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) bool {
+		return tp.PrintfLine(format, args...) == nil
+	}
+
+	if !reply("220 mailtest ready") {
+		return
+	}
+	var current Received
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250 mailtest")
+		case "MAIL":
+			current = Received{From: address(arg)}
+			reply("250 OK")
+		case "RCPT":
+			current.To = append(current.To, address(arg))
+			reply("250 OK")
+		case "DATA":
+			if s.fail() {
+				reply("451 Try again later")
+				continue
+			}
+			if !reply("354 Go ahead") {
+				return
+			}
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.Data = data
+			s.mu.Lock()
+			s.received = append(s.received, current)
+			s.mu.Unlock()
+			current = Received{}
+			reply("250 OK")
+		case "RSET":
+			current = Received{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// Whether to turn away this email, counting it towards the ones FailNext asked for
+func (s *Server) fail() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return true
+	}
+	return false
+}
+
+// The address from a MAIL FROM:<address> or RCPT TO:<address> argument
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, "<")
+	addr, _, _ = strings.Cut(addr, ">")
+	return addr
+}
+
+// The email's headers, and its plain text and HTML bodies decoded
+type Email struct {
+	Header  mail.Header
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Reads the multipart email back into its parts
+func (r Received) Email() (Email, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(r.Data))
+	if err != nil {
+		return Email{}, err
+	}
+	email := Email{Header: msg.Header}
+	email.Subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		return Email{}, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return Email{}, err
+	}
+	if mediaType != "multipart/alternative" {
+		return Email{}, fmt.Errorf("email is %s rather than multipart/alternative", mediaType)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Email{}, err
+		}
+		// NextPart decodes quoted-printable itself and drops the header, so only decode the
+		// ones it doesn't
+		var body io.Reader = part
+		if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+			body = quotedprintable.NewReader(part)
+		}
+		contents, err := io.ReadAll(body)
+		if err != nil {
+			return Email{}, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "text/plain":
+			email.Text = string(contents)
+		case "text/html":
+			email.HTML = string(contents)
+		}
+	}
+	return email, nil
+}
@@ -0,0 +1,136 @@
+package mail
+
+import (
+	"html"
+	"strings"
+)
+
+// The elements which start a new line in the plain text version
+var blockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true, "div": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true,
+	"hr": true, "li": true, "ol": true, "p": true, "section": true, "table": true, "tr": true,
+	"ul": true,
+}
+
+// The elements whose contents aren't part of the plain text version
+var hiddenElements = map[string]bool{"head": true, "script": true, "style": true, "title": true}
+
+// Turns the HTML version of an email into the plain text one, keeping its paragraphs and putting
+// the addresses of links after them. It's only meant for the simple HTML of the app's own emails.
+func PlainText(body string) string {
+	var text strings.Builder
+	var hidden string
+	var href string
+	linkStart := 0
+
+	for len(body) > 0 {
+		start := strings.IndexByte(body, '<')
+		if start < 0 {
+			start = len(body)
+		}
+		if hidden == "" {
+			writeText(&text, html.UnescapeString(body[:start]))
+		}
+		body = body[start:]
+		if body == "" {
+			break
+		}
+
+		end := strings.IndexByte(body, '>')
+		if end < 0 {
+			break
+		}
+		tag := body[1:end]
+		body = body[end+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		name, attrs, _ := strings.Cut(strings.TrimPrefix(tag, "/"), " ")
+		name = strings.ToLower(strings.TrimSuffix(name, "/"))
+
+		switch {
+		case hiddenElements[name]:
+			if closing {
+				hidden = ""
+			} else {
+				hidden = name
+			}
+		case hidden != "":
+		case name == "a" && !closing:
+			href = attribute(attrs, "href")
+			linkStart = text.Len()
+		case name == "a" && closing:
+			// Links whose text is already their address don't need it twice
+			if href != "" && strings.TrimSpace(text.String()[linkStart:]) != href {
+				text.WriteString(" (" + href + ")")
+			}
+			href = ""
+		case name == "td" || name == "th":
+			if closing {
+				text.WriteByte(' ')
+			}
+		case blockElements[name]:
+			newLine(&text)
+			// Paragraphs and headings are followed by a blank line
+			if name == "p" || name[0] == 'h' && name != "hr" && closing {
+				text.WriteByte('\n')
+			}
+		}
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	// No more than one blank line in a row
+	joined := strings.Join(lines, "\n")
+	for strings.Contains(joined, "\n\n\n") {
+		joined = strings.ReplaceAll(joined, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(joined) + "\n"
+}
+
+// Writes text from between tags, with its runs of whitespace collapsed to a space like a browser
+// shows them
+func writeText(text *strings.Builder, s string) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		if s != "" && !endsWith(text, " ") && !endsWith(text, "\n") && text.Len() > 0 {
+			text.WriteByte(' ')
+		}
+		return
+	}
+	if isSpace(s[0]) && !endsWith(text, " ") && !endsWith(text, "\n") && text.Len() > 0 {
+		text.WriteByte(' ')
+	}
+	text.WriteString(strings.Join(fields, " "))
+	if isSpace(s[len(s)-1]) {
+		text.WriteByte(' ')
+	}
+}
+
+// Starts a new line unless the text is already at the start of one
+func newLine(text *strings.Builder) {
+	if text.Len() > 0 && !endsWith(text, "\n") {
+		text.WriteByte('\n')
+	}
+}
+
+func endsWith(text *strings.Builder, suffix string) bool {
+	return strings.HasSuffix(text.String(), suffix)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// The value of the attribute in a tag's attributes, which are quoted with double quotes as templ
+// writes them
+func attribute(attrs string, name string) string {
+	_, value, ok := strings.Cut(attrs, name+`="`)
+	if !ok {
+		return ""
+	}
+	value, _, _ = strings.Cut(value, `"`)
+	return html.UnescapeString(value)
+}
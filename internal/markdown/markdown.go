@@ -0,0 +1,164 @@
+// Package markdown renders the small part of Markdown that comments need as HTML. Nothing in the
+// source is ever passed through as HTML: all text is escaped, and links only go to http, https
+// and mailto URLs, so what comes out is safe to put straight into a page.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	bulletItem  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	numberItem  = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	quoteLine   = regexp.MustCompile(`^>\s?(.*)$`)
+	link        = regexp.MustCompile(`\[([^\]\n]+)\]\(([^)\s]+)\)`)
+	strong      = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	emphasis    = regexp.MustCompile(`(^|[^\w*])(\*|_)(\S(?:.*?\S)?)(\*|_)([^\w*]|$)`)
+	placeholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// The URL schemes links may use
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// Renders the source as HTML. Paragraphs are separated by blank lines, and a single line break
+// stays one. Also understood are lists starting with -, * or + or numbered 1., quotes starting
+// with >, code fenced with ```, `code`, **bold**, *emphasis* and [links](https://example.com).
+func Render(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	// The lines of the block being collected, and what kind of block it is
+	var block []string
+	kind := ""
+	flush := func() {
+		switch kind {
+		case "p":
+			out.WriteString("<p>" + inlines(block) + "</p>")
+		case "blockquote":
+			out.WriteString("<blockquote><p>" + inlines(block) + "</p></blockquote>")
+		case "ul", "ol":
+			out.WriteString("<" + kind + ">")
+			for _, item := range block {
+				out.WriteString("<li>" + Inline(item) + "</li>")
+			}
+			out.WriteString("</" + kind + ">")
+		case "pre":
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(block, "\n")) + "</code></pre>")
+		}
+		block, kind = nil, ""
+	}
+
+	for _, line := range lines {
+		if kind == "pre" {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				flush()
+			} else {
+				block = append(block, line)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		lineKind, text := "p", trimmed
+		if m := bulletItem.FindStringSubmatch(trimmed); m != nil {
+			lineKind, text = "ul", m[1]
+		} else if m := numberItem.FindStringSubmatch(trimmed); m != nil {
+			lineKind, text = "ol", m[1]
+		} else if m := quoteLine.FindStringSubmatch(trimmed); m != nil {
+			lineKind, text = "blockquote", m[1]
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			kind = "pre"
+		case lineKind != kind:
+			flush()
+			block, kind = []string{text}, lineKind
+		default:
+			block = append(block, text)
+		}
+	}
+	// A code block without its closing fence runs to the end
+	flush()
+	return out.String()
+}
+
+// The lines as HTML, with the line breaks kept
+func inlines(lines []string) string {
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		rendered[i] = Inline(line)
+	}
+	return strings.Join(rendered, "<br>")
+}
+
+// Renders the formatting within a line as HTML: `code`, **bold**, *emphasis* and links
+func Inline(text string) string {
+	// What's already been rendered is swapped for placeholders, so the formatting isn't applied
+	// inside code or link addresses. A NUL can't otherwise be in the text since it's removed.
+	text = strings.ReplaceAll(text, "\x00", "")
+	rendered := []string{}
+	hold := func(s string) string {
+		rendered = append(rendered, s)
+		return fmt.Sprintf("\x00%d\x00", len(rendered)-1)
+	}
+
+	parts := strings.Split(text, "`")
+	var withCode strings.Builder
+	for i, part := range parts {
+		// Odd parts are between backticks, unless the last backtick has no partner
+		if i%2 == 1 && i < len(parts)-1 {
+			withCode.WriteString(hold("<code>" + html.EscapeString(part) + "</code>"))
+			continue
+		}
+		if i%2 == 1 {
+			withCode.WriteString("`")
+		}
+		withCode.WriteString(html.EscapeString(part))
+	}
+	text = withCode.String()
+
+	text = link.ReplaceAllStringFunc(text, func(match string) string {
+		m := link.FindStringSubmatch(match)
+		label, address := m[1], html.UnescapeString(m[2])
+		u, err := url.Parse(address)
+		if err != nil || !safeSchemes[strings.ToLower(u.Scheme)] {
+			return label
+		}
+		return hold(fmt.Sprintf(`<a href="%s" rel="nofollow noopener noreferrer">%s</a>`, html.EscapeString(u.String()), label))
+	})
+	text = strong.ReplaceAllStringFunc(text, func(match string) string {
+		m := strong.FindStringSubmatch(match)
+		if m[1] != m[3] {
+			return match
+		}
+		return "<strong>" + m[2] + "</strong>"
+	})
+	// Matches can share the space between them, so it takes another pass to find every other one
+	for {
+		emphasised := emphasis.ReplaceAllStringFunc(text, func(match string) string {
+			m := emphasis.FindStringSubmatch(match)
+			if m[2] != m[4] {
+				return match
+			}
+			return m[1] + "<em>" + m[3] + "</em>" + m[5]
+		})
+		if emphasised == text {
+			break
+		}
+		text = emphasised
+	}
+
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		var i int
+		fmt.Sscanf(placeholder.FindStringSubmatch(match)[1], "%d", &i)
+		return rendered[i]
+	})
+}
@@ -0,0 +1,50 @@
+package markdown
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	for _, tc := range []struct {
+		source string
+		want   string
+	}{
+		{"", ""},
+		{"Hazy and **juicy**", "<p>Hazy and <strong>juicy</strong></p>"},
+		{"A *bit* _thin_", "<p>A <em>bit</em> <em>thin</em></p>"},
+		{"snake_case_name stays", "<p>snake_case_name stays</p>"},
+		{"One\ntwo\n\nThree", "<p>One<br>two</p><p>Three</p>"},
+		{"Try `**this**`", "<p>Try <code>**this**</code></p>"},
+		{"A lone ` backtick", "<p>A lone ` backtick</p>"},
+		{"- Citrus\n- Pine\n\n1. Pour\n2. Sip", "<ul><li>Citrus</li><li>Pine</li></ul><ol><li>Pour</li><li>Sip</li></ol>"},
+		{"> Best beer\n> ever", "<blockquote><p>Best beer<br>ever</p></blockquote>"},
+		{"```\n<b>raw</b>\n  indented\n```\nafter", "<pre><code>&lt;b&gt;raw&lt;/b&gt;\n  indented</code></pre><p>after</p>"},
+		{"[Felon's](https://felons.com.au/beer?a=1&b=2)", `<p><a href="https://felons.com.au/beer?a=1&amp;b=2" rel="nofollow noopener noreferrer">Felon&#39;s</a></p>`},
+		{"[mail](mailto:hi@example.com)", `<p><a href="mailto:hi@example.com" rel="nofollow noopener noreferrer">mail</a></p>`},
+		{"[under_score](https://example.com/a_b_c)", `<p><a href="https://example.com/a_b_c" rel="nofollow noopener noreferrer">under_score</a></p>`},
+	} {
+		if got := Render(tc.source); got != tc.want {
+			t.Errorf("Render(%q):\n got %q\nwant %q", tc.source, got, tc.want)
+		}
+	}
+}
+
+// Nothing in the source comes out as markup it didn't ask for
+func TestRenderSanitises(t *testing.T) {
+	for _, tc := range []struct {
+		source string
+		want   string
+	}{
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{`<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{"[click](javascript:alert(1))", "<p>click)</p>"},
+		{"[click](JavaScript:alert(1))", "<p>click)</p>"},
+		{"[click](data:text/html,hi)", "<p>click</p>"},
+		{`[x](https://example.com/"onmouseover="alert(1))`, `<p><a href="https://example.com/%22onmouseover=%22alert%281" rel="nofollow noopener noreferrer">x</a>)</p>`},
+		{"**<i>bold</i>**", "<p><strong>&lt;i&gt;bold&lt;/i&gt;</strong></p>"},
+		{"- <a href=x>", "<ul><li>&lt;a href=x&gt;</li></ul>"},
+		{"sneaky \x000\x00 placeholder", "<p>sneaky 0 placeholder</p>"},
+	} {
+		if got := Render(tc.source); got != tc.want {
+			t.Errorf("Render(%q):\n got %q\nwant %q", tc.source, got, tc.want)
+		}
+	}
+}
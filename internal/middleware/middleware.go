@@ -14,7 +14,7 @@ type SessionStore interface {
 type Middleware func(http.Handler) http.Handler
 
 // AuthMiddleware factory with dependencies
-func Auth(sessionStore SessionStore, userStore *users.UserStore) Middleware {
+func Auth(sessionStore SessionStore, userStore users.Store) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			userId, err := sessionStore.ValidateSession(r)
@@ -29,6 +29,25 @@ func Auth(sessionStore SessionStore, userStore *users.UserStore) Middleware {
 	}
 }
 
+// AdminMiddleware factory, must be chained after the Auth middleware so the userId is in the context
+func Admin(userStore users.Store) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userId, ok := r.Context().Value("userId").(int64)
+			if !ok {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+			user, err := userStore.GetUserById(r.Context(), userId)
+			if err != nil || !user.IsAdmin {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // LoggingMiddleware for request logging
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,92 @@
+// Package notify sends messages to users outside the app, such as reminders that it's beer
+// o'clock
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+)
+
+// A message for a user
+type Notification struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+}
+
+// Gets notifications to users somehow, e.g. by email
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+var _ Notifier = (*FileNotifier)(nil)
+
+// Logs notifications rather than sending them, for running without anywhere to send them to
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.Printf("notifying %s: %s: %s", notification.Username, notification.Subject, notification.Body)
+	return nil
+}
+
+// Appends notifications to a file as lines of JSON, so tests and people trying reminders out can
+// see what would have been sent
+type FileNotifier struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileNotifier(path string) (*FileNotifier, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileNotifier{file: file}, nil
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, notification Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.file.Write(append(line, '\n'))
+	return err
+}
+
+func (n *FileNotifier) Close() error {
+	return n.file.Close()
+}
+
+// Reads back the notifications a FileNotifier wrote, oldest first
+func ReadFile(path string) ([]Notification, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []Notification{}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	for decoder.More() {
+		var notification Notification
+		if err := decoder.Decode(&notification); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
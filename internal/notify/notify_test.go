@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	sent := []Notification{
+		{UserID: 1, Username: "saltytaro", Subject: "It's beer o'clock", Body: "Friday at 17:00"},
+		{UserID: 2, Username: "guest", Subject: "Nearly beer o'clock", Body: "Line one\nline two"},
+	}
+
+	notifier, err := NewFileNotifier(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, notification := range sent {
+		if err := notifier.Notify(context.Background(), notification); err != nil {
+			t.Fatalf("notifying: %v", err)
+		}
+	}
+	notifier.Close()
+
+	got, err := ReadFile(path)
+	if err != nil || !slices.Equal(got, sent) {
+		t.Errorf("reading notifications: got %+v, %v, want %+v", got, err, sent)
+	}
+
+	// Opening the file again adds to it
+	notifier, _ = NewFileNotifier(path)
+	notifier.Notify(context.Background(), sent[0])
+	notifier.Close()
+	if got, _ := ReadFile(path); len(got) != 3 {
+		t.Errorf("reading notifications after reopening: got %d, want 3", len(got))
+	}
+}
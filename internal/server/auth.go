@@ -12,11 +12,11 @@ import (
 
 type BeerOclockSessionStore struct {
 	sessionStore sessions.Store
-	userStore    *users.UserStore
+	userStore    users.Store
 	logger       *log.Logger
 }
 
-func NewBeerOclockSessionStore(sessionStore sessions.Store, userStore *users.UserStore) *BeerOclockSessionStore {
+func NewBeerOclockSessionStore(sessionStore sessions.Store, userStore users.Store) *BeerOclockSessionStore {
 	return &BeerOclockSessionStore{
 		sessionStore: sessionStore,
 		userStore:    userStore,
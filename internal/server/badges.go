@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store/badges"
+	"beer_oclock/internal/templates"
+)
+
+// Renders the user's badges, worked out from everything they've added and rated. Badges are
+// shown to everyone, whatever the user's activity privacy, since they don't say which beers.
+func (s *server) renderBadges(w http.ResponseWriter, r *http.Request, user db.User) {
+	facts, err := s.badgeStore.GetUserFacts(r.Context(), user.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting badge facts: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data := templates.BadgesData{
+		User:     user,
+		Self:     user.ID == currentUserId(r),
+		Progress: badges.Evaluate(badges.Definitions(), facts),
+		Location: location,
+	}
+	renderTemplate(w, r, templates.Badges(data), "Badges")
+}
+
+// GET /badges
+func (s *server) badgesHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := s.userStore.GetUserById(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	s.renderBadges(w, r, user)
+}
+
+// GET /people/{id}/badges
+func (s *server) personBadgesHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	s.renderBadges(w, r, user)
+}
+
+// GET /leaderboards
+func (s *server) leaderboardsHandler(w http.ResponseWriter, r *http.Request) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// The month is where the user is, defaulting to this one
+	now := time.Now().In(location)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	if param := r.URL.Query().Get("month"); param != "" {
+		month, err = time.ParseInLocation("2006-01", param, location)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when parsing month: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	facts, err := s.badgeStore.GetFacts(r.Context(), month, month.AddDate(0, 1, 0))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting badge facts: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data := templates.LeaderboardsData{
+		Month:   month,
+		Styles:  badges.Leaderboard(facts, "styles"),
+		Brewers: badges.Leaderboard(facts, "brewers"),
+		UserID:  currentUserId(r),
+	}
+	renderTemplate(w, r, templates.Leaderboards(data), "Leaderboards")
+}
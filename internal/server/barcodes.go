@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/ean"
+	"beer_oclock/internal/store/barcodes"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/templates"
+)
+
+// Checks a barcode from a form, returning it in its stored form or why it can't be put on a beer.
+// A blank barcode is fine, since they're optional.
+func (s *server) checkNewBarcode(ctx context.Context, formBarcode string) (string, string, error) {
+	if strings.TrimSpace(formBarcode) == "" {
+		return "", "", nil
+	}
+
+	code, err := ean.Parse(formBarcode)
+	if err != nil {
+		if err, ok := err.(ean.ErrInvalidBarcode); ok {
+			return "", fmt.Sprintf("Barcode %s", err.Reason), nil
+		}
+		return "", "", err
+	}
+
+	_, err = s.barcodeStore.GetBarcode(ctx, code)
+	switch err.(type) {
+	case nil:
+		return "", "That barcode is already on a beer", nil
+	case barcodes.ErrBarcodeNotFound:
+		return code, "", nil
+	default:
+		return "", "", err
+	}
+}
+
+// GET /barcode/{code}
+func (s *server) barcodeHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Looking up barcode: %s", r.PathValue("code"))
+
+	code, err := ean.Parse(r.PathValue("code"))
+	if err != nil {
+		s.logger.Printf("Error when parsing barcode: %v", err)
+		msg := "That barcode couldn't be read"
+		if err, ok := err.(ean.ErrInvalidBarcode); ok {
+			msg = fmt.Sprintf("Barcode %s %s", err.Code, err.Reason)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.BarcodeError(msg), "Barcode")
+		return
+	}
+
+	// Open the beer if it's one of ours
+	barcode, err := s.barcodeStore.GetBarcode(r.Context(), code)
+	switch err.(type) {
+	case nil:
+		http.Redirect(w, r, fmt.Sprintf("/beer/%d", barcode.BeerID), http.StatusSeeOther)
+		return
+	case barcodes.ErrBarcodeNotFound:
+	default:
+		errMsg := fmt.Sprintf("Error when getting barcode: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	brewers, err := s.brewerStore.GetBrewers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting brewers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	weights, err := s.scorecardStore.GetWeights(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	allTags, err := s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Otherwise fill in as much of the form for adding it as the lookup knows. It's only a
+	// convenience, so the form is still shown when the lookup fails.
+	formData := db.Beer{}
+	hints := make(map[string]string)
+	product, err := s.barcodeLookup.LookupProduct(r.Context(), code)
+	switch err.(type) {
+	case nil:
+		formData.Name = product.Name
+		formData.Style = sql.NullString{Valid: product.Style != "", String: product.Style}
+		formData.Abv = product.Abv
+		if product.Brewer != "" {
+			hints["brewer-id"] = fmt.Sprintf("%s isn't one of the brewers yet", product.Brewer)
+			for _, brewer := range brewers {
+				if strings.EqualFold(brewer.Name, product.Brewer) {
+					formData.BrewerID = sql.NullInt64{Valid: true, Int64: brewer.ID}
+					delete(hints, "brewer-id")
+					break
+				}
+			}
+		}
+	case ean.ErrProductNotFound:
+		s.logger.Printf("No product found with barcode %s", code)
+	default:
+		s.logger.Printf("Error when looking up barcode: %v", err)
+	}
+
+	renderTemplate(w, r, templates.AddBeerForm(formData, scorecards.Scores{}, weights, nil, allTags, brewers, code, hints, false), "Add Beer")
+}
+
+// Re-renders the beer's barcodes with an error, as a validation error so HTMX swaps it in
+func (s *server) barcodeError(w http.ResponseWriter, r *http.Request, beerId int64, msg string) {
+	beerBarcodes, err := s.barcodeStore.GetBeerBarcodes(r.Context(), beerId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer barcodes: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	renderTemplate(w, r, templates.BeerBarcodes(beerId, beerBarcodes, msg))
+}
+
+// POST /beer/{id}/barcodes
+func (s *server) addBarcodeHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	beerId := int64(id)
+
+	s.logger.Printf("Adding barcode to beer with id: %d", beerId)
+
+	if _, err := s.beerStore.GetBeer(r.Context(), beerId); err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	formBarcode := r.FormValue("barcode")
+	if strings.TrimSpace(formBarcode) == "" {
+		s.barcodeError(w, r, beerId, "Barcode is required")
+		return
+	}
+	code, barcodeError, err := s.checkNewBarcode(r.Context(), formBarcode)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when checking barcode: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if barcodeError != "" {
+		s.barcodeError(w, r, beerId, barcodeError)
+		return
+	}
+
+	if _, err := s.barcodeStore.AddBarcode(r.Context(), code, beerId); err != nil {
+		errMsg := fmt.Sprintf("Error when adding barcode: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case barcodes.ErrBarcodeAlreadyExists:
+			s.barcodeError(w, r, beerId, "That barcode is already on a beer")
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	beerBarcodes, err := s.barcodeStore.GetBeerBarcodes(r.Context(), beerId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer barcodes: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeerBarcodes(beerId, beerBarcodes, ""))
+}
+
+// DELETE /barcode/{code}
+func (s *server) deleteBarcodeHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting barcode: %s", r.PathValue("code"))
+
+	if _, err := s.barcodeStore.DeleteBarcode(r.Context(), r.PathValue("code")); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting barcode: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case barcodes.ErrBarcodeNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Return nothing so the barcode is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/comments"
+	"beer_oclock/internal/templates"
+)
+
+// Renders the comments on the beer or brewer. body is what was typed for a new comment which
+// couldn't be added, so it isn't lost.
+func (s *server) renderComments(w http.ResponseWriter, r *http.Request, target comments.Target, body string, validationErrors map[string]string, status int) {
+	viewer, err := s.userStore.GetUserById(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := s.commentStore.GetComments(r.Context(), target)
+	var reactions []db.GetReactionsRow
+	if err == nil {
+		reactions, err = s.commentStore.GetReactions(r.Context(), target)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting comments: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data := templates.CommentsData{
+		Target:    target,
+		Threads:   comments.Thread(rows),
+		Reactions: comments.Tally(reactions, viewer.ID),
+		ViewerID:  viewer.ID,
+		IsAdmin:   viewer.IsAdmin,
+		Location:  location,
+		Body:      body,
+	}
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.Comments(data, validationErrors))
+}
+
+// The beer from the id in the path as something to comment on, responding with an error if
+// there isn't one
+func (s *server) pathBeerTarget(w http.ResponseWriter, r *http.Request) (comments.Target, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return comments.Target{}, false
+	}
+
+	if _, err := s.beerStore.GetBeer(r.Context(), int64(id)); err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return comments.Target{}, false
+	}
+	return comments.BeerTarget(int64(id)), true
+}
+
+// The brewer from the id in the path as something to comment on, responding with an error if
+// there isn't one
+func (s *server) pathBrewerTarget(w http.ResponseWriter, r *http.Request) (comments.Target, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return comments.Target{}, false
+	}
+
+	if _, err := s.brewerStore.GetBrewer(r.Context(), int64(id)); err != nil {
+		errMsg := fmt.Sprintf("Error when getting brewer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case store.ErrBrewerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return comments.Target{}, false
+	}
+	return comments.BrewerTarget(int64(id)), true
+}
+
+// The comment from the id in the path, responding with an error if there isn't one. Deleted
+// comments are treated as missing.
+func (s *server) pathComment(w http.ResponseWriter, r *http.Request) (db.Comment, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.Comment{}, false
+	}
+
+	comment, err := s.commentStore.GetComment(r.Context(), int64(id))
+	if err == nil && comment.DeletedAt.Valid {
+		err = comments.ErrCommentNotFound{ID: comment.ID}
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting comment: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case comments.ErrCommentNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return db.Comment{}, false
+	}
+	return comment, true
+}
+
+// What's being commented on, for telling those mentioned
+func (s *server) targetName(ctx context.Context, target comments.Target) (string, error) {
+	if target.BeerID.Valid {
+		beer, err := s.beerStore.GetBeer(ctx, target.BeerID.Int64)
+		return beer.Name, err
+	}
+	brewer, err := s.brewerStore.GetBrewer(ctx, target.BrewerID.Int64)
+	return brewer.Name, err
+}
+
+// Emails the users newly @mentioned in the comment, other than whoever wrote it, through the
+// outbox. Those without an email address can't be told. The comment has already been saved, so
+// failing to tell them is only logged.
+func (s *server) notifyMentions(ctx context.Context, comment db.Comment, before string) {
+	mentioned := comments.NewMentions(before, comment.Body)
+	if len(mentioned) == 0 {
+		return
+	}
+	author, err := s.userStore.GetUserById(ctx, comment.AuthorID)
+	if err != nil {
+		s.logger.Printf("Error when getting author of comment %d: %v", comment.ID, err)
+		return
+	}
+	target := comments.TargetOf(comment)
+	name, err := s.targetName(ctx, target)
+	if err != nil {
+		s.logger.Printf("Error when getting what comment %d is on: %v", comment.ID, err)
+		return
+	}
+
+	for _, username := range mentioned {
+		user, err := s.userStore.GetUserByUsername(ctx, strings.ToLower(username))
+		if err != nil || user.ID == author.ID {
+			continue
+		}
+		userEmail, err := s.emailStore.GetUserEmail(ctx, user.ID)
+		if err != nil {
+			s.logger.Printf("Not telling %s about comment %d, as they have no email address: %v", user.Username, comment.ID, err)
+			continue
+		}
+		subject := fmt.Sprintf("%s mentioned you", author.Username)
+		body := templates.MentionEmail(user.Username, author.Username, name, comment.Body, s.baseURL+target.Path())
+		if err := s.queueEmail(ctx, userEmail.Email, subject, body); err != nil {
+			s.logger.Printf("Error when telling %s about comment %d: %v", user.Username, comment.ID, err)
+		}
+	}
+}
+
+// GET /beer/{id}/comments
+func (s *server) beerCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	if target, ok := s.pathBeerTarget(w, r); ok {
+		s.renderComments(w, r, target, "", nil, http.StatusOK)
+	}
+}
+
+// GET /brewer/{id}/comments
+func (s *server) brewerCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	if target, ok := s.pathBrewerTarget(w, r); ok {
+		s.renderComments(w, r, target, "", nil, http.StatusOK)
+	}
+}
+
+// POST /beer/{id}/comments
+func (s *server) addBeerCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if target, ok := s.pathBeerTarget(w, r); ok {
+		s.addComment(w, r, target)
+	}
+}
+
+// POST /brewer/{id}/comments
+func (s *server) addBrewerCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if target, ok := s.pathBrewerTarget(w, r); ok {
+		s.addComment(w, r, target)
+	}
+}
+
+// Adds a comment, or a reply if there's a parent-id, to what's being commented on
+func (s *server) addComment(w http.ResponseWriter, r *http.Request, target comments.Target) {
+	if err := r.ParseForm(); err != nil {
+		s.logger.Printf("Error when parsing form: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	params := db.AddCommentParams{
+		AuthorID: currentUserId(r),
+		BeerID:   target.BeerID,
+		BrewerID: target.BrewerID,
+		Body:     r.FormValue("body"),
+	}
+	if parentId := r.FormValue("parent-id"); parentId != "" {
+		id, err := strconv.ParseInt(parentId, 10, 64)
+		if err != nil {
+			s.renderComments(w, r, target, params.Body, map[string]string{"parent-id": "Replies must be to a comment"}, http.StatusUnprocessableEntity)
+			return
+		}
+		params.ParentID = sql.NullInt64{Valid: true, Int64: id}
+	}
+
+	comment, err := s.commentStore.AddComment(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding comment: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderComments(w, r, target, params.Body, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderComments(w, r, target, params.Body, map[string]string{err.Field: "This field " + err.Reason}, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+	s.notifyMentions(r.Context(), comment, "")
+
+	s.renderComments(w, r, target, "", nil, http.StatusOK)
+}
+
+// PUT /comment/{id}
+func (s *server) updateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	comment, ok := s.pathComment(w, r)
+	if !ok {
+		return
+	}
+	if comment.AuthorID != currentUserId(r) {
+		errMsg := fmt.Sprintf("Comment %d can only be edited by its author", comment.ID)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusForbidden)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.logger.Printf("Error when parsing form: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := s.commentStore.UpdateComment(r.Context(), comment.ID, r.FormValue("body"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when updating comment: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderComments(w, r, comments.TargetOf(comment), "", map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderComments(w, r, comments.TargetOf(comment), "", map[string]string{err.Field: "This field " + err.Reason}, http.StatusUnprocessableEntity)
+		case comments.ErrCommentNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+	s.notifyMentions(r.Context(), updated, comment.Body)
+
+	s.renderComments(w, r, comments.TargetOf(updated), "", nil, http.StatusOK)
+}
+
+// DELETE /comment/{id}
+//
+// Authors can delete their own comments, and admins can remove anyone's
+func (s *server) deleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	comment, ok := s.pathComment(w, r)
+	if !ok {
+		return
+	}
+	user, err := s.userStore.GetUserById(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if comment.AuthorID != user.ID && !user.IsAdmin {
+		errMsg := fmt.Sprintf("Comment %d can only be deleted by its author or an admin", comment.ID)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusForbidden)
+		return
+	}
+
+	if _, err := s.commentStore.DeleteComment(r.Context(), comment.ID, comment.AuthorID != user.ID); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting comment: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case comments.ErrCommentNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderComments(w, r, comments.TargetOf(comment), "", nil, http.StatusOK)
+}
+
+// POST /comment/{id}/reactions
+func (s *server) toggleReactionHandler(w http.ResponseWriter, r *http.Request) {
+	comment, ok := s.pathComment(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.logger.Printf("Error when parsing form: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := s.commentStore.ToggleReaction(r.Context(), comment.ID, currentUserId(r), r.FormValue("emoji")); err != nil {
+		errMsg := fmt.Sprintf("Error when reacting to comment: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case store.ErrMissingField, store.ErrInvalidField:
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		case comments.ErrCommentNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderComments(w, r, comments.TargetOf(comment), "", nil, http.StatusOK)
+}
@@ -0,0 +1,488 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/templates"
+)
+
+// The session with the id, if the user's in it. Sessions the user isn't in are treated as not
+// being there, like other users' rows.
+func (s *server) getUserDrinkSession(ctx context.Context, id int64, userId int64) (db.DrinkingSession, error) {
+	session, err := s.drinkSessionStore.GetSession(ctx, id)
+	if err != nil {
+		return db.DrinkingSession{}, err
+	}
+	participants, err := s.drinkSessionStore.GetParticipants(ctx, id)
+	if err != nil {
+		return db.DrinkingSession{}, err
+	}
+	if !slices.ContainsFunc(participants, func(p db.GetSessionParticipantsRow) bool { return p.UserID == userId }) {
+		return db.DrinkingSession{}, drinksessions.ErrSessionNotFound{ID: id}
+	}
+	return session, nil
+}
+
+// Who was in the session, where they went and what they had, with the times in the location
+func (s *server) getDrinkSessionData(ctx context.Context, session db.DrinkingSession, location *time.Location) (templates.DrinkSessionData, error) {
+	data := templates.DrinkSessionData{Session: session, Location: location}
+
+	var err error
+	data.Participants, err = s.drinkSessionStore.GetParticipants(ctx, session.ID)
+	if err != nil {
+		return templates.DrinkSessionData{}, err
+	}
+	data.Venues, err = s.drinkSessionStore.GetVenues(ctx, session.ID)
+	if err != nil {
+		return templates.DrinkSessionData{}, err
+	}
+	data.Drinks, err = s.drinkSessionStore.GetDrinks(ctx, session.ID)
+	if err != nil {
+		return templates.DrinkSessionData{}, err
+	}
+	data.Tallies = drinksessions.Tallies(data.Participants, data.Drinks)
+	return data, nil
+}
+
+// Renders the session for one of its participants, with the forms for adding to it
+func (s *server) renderDrinkSession(w http.ResponseWriter, r *http.Request, session db.DrinkingSession, validationErrors map[string]string, status int) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data, err := s.getDrinkSessionData(r.Context(), session, location)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Beers, err = s.beerStore.GetBeers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	data.ShareURL = s.baseURL + "/shared/session/" + session.ShareToken
+	data.CanDelete = session.CreatedBy.Valid && session.CreatedBy.Int64 == currentUserId(r)
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.DrinkSession(data, validationErrors), session.Name)
+}
+
+// Renders the user's sessions with the form for starting another
+func (s *server) renderDrinkSessions(w http.ResponseWriter, r *http.Request, formData db.AddDrinkingSessionParams, startedAt string, validationErrors map[string]string, status int) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := s.drinkSessionStore.GetUserSessions(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking sessions: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.DrinkSessionsForm(sessions, location, formData, startedAt, validationErrors))
+}
+
+// GET /sessions
+func (s *server) drinkSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := s.drinkSessionStore.GetUserSessions(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking sessions: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.DrinkSessions(sessions, location), "Sessions")
+}
+
+// POST /sessions
+func (s *server) addDrinkSessionHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Starting drinking session")
+
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// It starts now unless a time is given, which is where the user is like the drinks in it
+	userId := currentUserId(r)
+	formStartedAt := r.FormValue("started-at")
+	params := db.AddDrinkingSessionParams{
+		Name:      r.FormValue("name"),
+		CreatedBy: sql.NullInt64{Valid: true, Int64: userId},
+		StartedAt: time.Now().In(location),
+	}
+	if formStartedAt != "" {
+		startedAt, err := time.ParseInLocation(drunkAtLayout, formStartedAt, location)
+		if err != nil {
+			s.renderDrinkSessions(w, r, params, formStartedAt, map[string]string{"started-at": "Started must be a date and time"}, http.StatusUnprocessableEntity)
+			return
+		}
+		params.StartedAt = startedAt
+	}
+
+	params.ShareToken, err = drinksessions.NewShareToken()
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when making share link: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	session, err := s.drinkSessionStore.AddSession(r.Context(), params)
+	if err == nil {
+		// Whoever starts a session is in it
+		err = s.drinkSessionStore.AddParticipant(r.Context(), db.AddSessionParticipantParams{SessionID: session.ID, UserID: userId})
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when starting drinking session: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderDrinkSessions(w, r, params, formStartedAt, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderDrinkSessions(w, r, params, formStartedAt, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderDrinkSessions(w, r, db.AddDrinkingSessionParams{}, "", nil, http.StatusOK)
+}
+
+// GET /session/{id}
+func (s *server) getDrinkSessionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderDrinkSession(w, r, session, nil, http.StatusOK)
+}
+
+// DELETE /session/{id}
+func (s *server) deleteDrinkSessionHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting drinking session with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	userId := currentUserId(r)
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), userId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// The other participants can see the session, but only whoever started it can delete it
+	if !session.CreatedBy.Valid || session.CreatedBy.Int64 != userId {
+		errMsg := fmt.Sprintf("Only whoever started drinking session %d can delete it", session.ID)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusForbidden)
+		return
+	}
+
+	if _, err := s.drinkSessionStore.DeleteSession(r.Context(), session.ID); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.drinkSessionsHandler(w, r)
+}
+
+// POST /session/{id}/participants
+func (s *server) addSessionParticipantHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	username := strings.TrimSpace(r.FormValue("username"))
+	s.logger.Printf("Adding %s to drinking session with id: %d", username, id)
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if username == "" {
+		s.renderDrinkSession(w, r, session, map[string]string{"username": "This field is required"}, http.StatusUnprocessableEntity)
+		return
+	}
+
+	user, err := s.userStore.GetUserByUsername(r.Context(), username)
+	if err == nil {
+		err = s.drinkSessionStore.AddParticipant(r.Context(), db.AddSessionParticipantParams{SessionID: session.ID, UserID: user.ID})
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding session participant: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case users.ErrUserNotFound:
+			s.renderDrinkSession(w, r, session, map[string]string{"username": fmt.Sprintf("There's no one called %s", username)}, http.StatusUnprocessableEntity)
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderDrinkSession(w, r, session, nil, http.StatusOK)
+}
+
+// POST /session/{id}/venues
+func (s *server) addSessionVenueHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Printf("Adding venue to drinking session with id: %d", id)
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err == nil {
+		_, err = s.drinkSessionStore.AddVenue(r.Context(), db.AddSessionVenueParams{SessionID: session.ID, Name: r.FormValue("venue")})
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding session venue: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderDrinkSession(w, r, session, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderDrinkSession(w, r, session, nil, http.StatusOK)
+}
+
+// POST /session/{id}/drinks
+func (s *server) logSessionDrinkHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Printf("Logging a drink in drinking session with id: %d", id)
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Like the drinks logged from a beer's page, and then put in the session
+	params, validationErrors := parseDrink(r, location)
+	if beerId, err := strconv.ParseInt(r.FormValue("beer-id"), 10, 64); err != nil {
+		validationErrors["beer-id"] = "Beer must be one of the beers"
+	} else {
+		params.BeerID = beerId
+	}
+	if len(validationErrors) > 0 {
+		s.renderDrinkSession(w, r, session, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	drink, err := s.drinkStore.AddDrink(r.Context(), params)
+	if err == nil {
+		err = s.drinkSessionStore.AddDrink(r.Context(), db.AddSessionDrinkParams{DrinkID: drink.ID, SessionID: session.ID})
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when logging session drink: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderDrinkSession(w, r, session, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		case beers.ErrBeerNotFound:
+			s.renderDrinkSession(w, r, session, map[string]string{"beer-id": "Beer must be one of the beers"}, http.StatusUnprocessableEntity)
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.emitWebhook(r.Context(), webhooks.DrinkLogged, drinkWebhookData(drink, sql.NullInt64{Valid: true, Int64: session.ID}))
+	s.renderDrinkSession(w, r, session, nil, http.StatusOK)
+}
+
+// POST /session/{id}/end
+func (s *server) endDrinkSessionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Printf("Ending drinking session with id: %d", id)
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err == nil {
+		var ended db.DrinkingSession
+		ended, err = s.drinkSessionStore.EndSession(r.Context(), db.EndDrinkingSessionParams{
+			ID:      session.ID,
+			EndedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		})
+		if err == nil {
+			session = ended
+		}
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when ending drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderDrinkSession(w, r, session, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderDrinkSession(w, r, session, nil, http.StatusOK)
+}
+
+// GET /shared/session/{token}
+func (s *server) sharedDrinkSessionHandler(w http.ResponseWriter, r *http.Request) {
+	session, err := s.drinkSessionStore.GetSessionByToken(r.Context(), r.PathValue("token"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting shared drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrShareLinkNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Whoever's looking needn't be logged in, so the times are where the app is
+	data, err := s.getDrinkSessionData(r.Context(), session, s.location)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.SharedDrinkSession(data), session.Name)
+}
@@ -0,0 +1,474 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"beer_oclock/internal/mail"
+	"beer_oclock/internal/notify"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/emails"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/outbox"
+	"beer_oclock/internal/store/resets"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/templates"
+
+	"github.com/a-h/templ"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// How often to send the emails waiting in the outbox
+const outboxInterval = 30 * time.Second
+
+// How many emails are sent each time, so a backlog is worked through a bit at a time
+const outboxBatchSize = 20
+
+// How many of the latest emails the outbox page shows
+const outboxPageSize = 50
+
+// How often to check whether anyone's weekly summary is due
+const summaryInterval = 15 * time.Minute
+
+// The hour on Monday, in the user's time zone, from when the summary of the week before is sent
+const summaryHour = 9
+
+// Renders the email's body in both its versions and puts it in the outbox, to be sent by
+// sendOutbox
+func (s *server) queueEmail(ctx context.Context, to string, subject string, body templ.Component) error {
+	var html bytes.Buffer
+	if err := body.Render(ctx, &html); err != nil {
+		return err
+	}
+	_, err := s.outboxStore.QueueEmail(ctx, db.QueueEmailParams{
+		ToAddress: to,
+		Subject:   subject,
+		TextBody:  mail.PlainText(html.String()),
+		HtmlBody:  html.String(),
+	})
+	return err
+}
+
+// Periodically send the emails in the outbox which are due, until the context is cancelled
+func (s *server) sendOutboxPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendOutbox(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Try to send the emails which are due at now. Ones which fail are tried again later, waiting
+// longer after each failure, until they've had outbox.MaxAttempts tries.
+func (s *server) sendOutbox(ctx context.Context, now time.Time) {
+	due, err := s.outboxStore.GetDueEmails(ctx, now, outboxBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, email := range due {
+		err := s.mailer.Send(ctx, mail.Message{
+			To:      email.ToAddress,
+			Subject: email.Subject,
+			Text:    email.TextBody,
+			HTML:    email.HtmlBody,
+		})
+		if err != nil {
+			s.logger.Printf("Error when sending email %d: %v", email.ID, err)
+			s.outboxStore.SetEmailFailed(ctx, db.SetEmailFailedParams{
+				LastError:     err.Error(),
+				NextAttemptAt: outbox.Retry(email, now),
+				ID:            email.ID,
+			})
+			continue
+		}
+		s.outboxStore.SetEmailSent(ctx, email.ID, now)
+	}
+}
+
+// Periodically queue the weekly summaries which are due, until the context is cancelled
+func (s *server) sendSummariesPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(summaryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendWeeklySummaries(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Queue a summary of last week for everyone who wants one and hasn't had it yet. Weeks turn over
+// at each user's own midnight on Monday, and summaries go out from summaryHour that morning.
+func (s *server) sendWeeklySummaries(ctx context.Context, now time.Time) {
+	all, err := s.emailStore.GetUserEmails(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, row := range all {
+		if !row.UserEmail.WeeklySummary {
+			continue
+		}
+		userNow := now.In(goals.Location(db.Goal{TimeZone: row.TimeZone}, s.location))
+		weekStart, _ := goals.Week(userNow)
+		if sent := row.UserEmail.SummarySentOn; sent.Valid && !sent.Time.Before(weekStart) {
+			continue
+		}
+		if userNow.Weekday() == time.Monday && userNow.Hour() < summaryHour {
+			continue
+		}
+
+		summary, err := s.weeklySummary(ctx, row, weekStart.AddDate(0, 0, -7))
+		if err != nil {
+			s.logger.Printf("Error when summarising the week for user %d: %v", row.UserEmail.UserID, err)
+			continue
+		}
+		subject := fmt.Sprintf("Your week from %s", summary.WeekStart.Format("Mon 2 Jan"))
+		if err := s.queueEmail(ctx, row.UserEmail.Email, subject, templates.WeeklySummaryEmail(summary)); err != nil {
+			s.logger.Printf("Error when queueing weekly summary for user %d: %v", row.UserEmail.UserID, err)
+			continue
+		}
+		s.emailStore.SetSummarySent(ctx, row.UserEmail.UserID, weekStart)
+	}
+}
+
+// What the user drank in the week from weekStart, which is a day as store.Day gives them
+func (s *server) weeklySummary(ctx context.Context, row db.GetUserEmailsRow, weekStart time.Time) (templates.WeeklySummary, error) {
+	userId, weekEnd := row.UserEmail.UserID, weekStart.AddDate(0, 0, 7)
+	summary := templates.WeeklySummary{
+		Username:  row.Username,
+		WeekStart: weekStart,
+		Link:      s.baseURL + "/",
+	}
+
+	days, err := s.drinkStore.GetDrinksPerDay(ctx, userId, weekStart, weekEnd)
+	if err != nil {
+		return summary, err
+	}
+	alcoholMl, drinkingDays := 0.0, 0
+	for _, day := range days {
+		if day.Drinks > 0 {
+			summary.Drinks += day.Drinks
+			alcoholMl += day.AlcoholMl
+			drinkingDays++
+		}
+	}
+	summary.StandardDrinks = drinks.StandardDrinksOfAlcohol(alcoholMl)
+	summary.AlcoholFreeDays = 7 - drinkingDays
+
+	topStyles, err := s.drinkStore.GetTopStyles(ctx, userId, weekStart, weekEnd, 1)
+	if err != nil {
+		return summary, err
+	}
+	if len(topStyles) > 0 && topStyles[0].Name.Valid {
+		summary.TopStyle = topStyles[0].Name.String
+	}
+
+	userGoals, err := s.goalStore.GetGoals(ctx, userId)
+	switch err.(type) {
+	case nil:
+		summary.WeeklyLimit = userGoals.WeeklyLimit.Float64
+	case goals.ErrGoalsNotFound:
+	default:
+		return summary, err
+	}
+	return summary, nil
+}
+
+// Emails the reminder too, if the user has an address and wants reminders by email. It's only
+// logged if it can't be, as the reminder has already got to them another way.
+func (s *server) emailReminder(ctx context.Context, notification notify.Notification) {
+	userEmail, err := s.emailStore.GetUserEmail(ctx, notification.UserID)
+	if err != nil || !userEmail.Reminders {
+		return
+	}
+	body := templates.ReminderEmail(notification.Username, notification.Subject, notification.Body, s.baseURL+"/fridge")
+	if err := s.queueEmail(ctx, userEmail.Email, notification.Subject, body); err != nil {
+		s.logger.Printf("Error when queueing reminder email for user %d: %v", notification.UserID, err)
+	}
+}
+
+// The form for the user's email settings as it starts out, and whether they've saved any
+func (s *server) getEmailSettings(r *http.Request) (db.SetUserEmailParams, bool, error) {
+	userEmail, err := s.emailStore.GetUserEmail(r.Context(), currentUserId(r))
+	switch err.(type) {
+	case nil:
+		return newEmailForm(userEmail), true, nil
+	case emails.ErrUserEmailNotFound:
+		// Everything's on until the user says otherwise
+		return db.SetUserEmailParams{WeeklySummary: true, Reminders: true}, false, nil
+	default:
+		return db.SetUserEmailParams{}, false, err
+	}
+}
+
+func newEmailForm(userEmail db.UserEmail) db.SetUserEmailParams {
+	return db.SetUserEmailParams{
+		Email:         userEmail.Email,
+		WeeklySummary: userEmail.WeeklySummary,
+		Reminders:     userEmail.Reminders,
+	}
+}
+
+// GET /email
+func (s *server) emailSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	formData, saved, err := s.getEmailSettings(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting email settings: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.EmailSettings(formData, saved), "Email")
+}
+
+// PUT /email
+func (s *server) setEmailHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Setting email settings")
+
+	_, saved, err := s.getEmailSettings(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting email settings: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	params := db.SetUserEmailParams{
+		UserID:        currentUserId(r),
+		Email:         strings.TrimSpace(r.FormValue("email")),
+		WeeklySummary: r.FormValue("weekly-summary") == "true",
+		Reminders:     r.FormValue("reminders") == "true",
+	}
+	if params.Email == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.EmailSettingsForm(params, saved, map[string]string{"email": "Email address is required"}))
+		return
+	}
+
+	userEmail, err := s.emailStore.SetUserEmail(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting email settings: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderTemplate(w, r, templates.EmailSettingsForm(params, saved, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}))
+		case emails.ErrEmailAlreadyUsed:
+			w.WriteHeader(http.StatusConflict)
+			renderTemplate(w, r, templates.EmailSettingsForm(params, saved, map[string]string{"email": "Someone else is already using that address"}))
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.EmailSettingsForm(newEmailForm(userEmail), true, nil))
+}
+
+// DELETE /email
+func (s *server) deleteEmailHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting email settings")
+
+	_, err := s.emailStore.DeleteUserEmail(r.Context(), currentUserId(r))
+	switch err.(type) {
+	case nil, emails.ErrUserEmailNotFound:
+	default:
+		errMsg := fmt.Sprintf("Error when deleting email settings: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.EmailSettingsForm(db.SetUserEmailParams{WeeklySummary: true, Reminders: true}, false, nil))
+}
+
+// GET /password/forgot
+func (s *server) forgotPasswordFormHandler(w http.ResponseWriter, r *http.Request) {
+	renderTemplate(w, r, templates.ForgotPasswordForm("", false, nil), "Forgot Password")
+}
+
+// POST /password/forgot
+func (s *server) forgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Requesting a password reset")
+
+	formEmail := strings.TrimSpace(r.FormValue("email"))
+	if formEmail == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.ForgotPasswordForm(formEmail, false, map[string]string{"email": "Email address is required"}))
+		return
+	}
+
+	// Whether or not the address is anyone's, the response is the same, so it can't be used to
+	// find out who has an account
+	userEmail, err := s.emailStore.GetUserEmailByAddress(r.Context(), formEmail)
+	var user db.User
+	if err == nil {
+		user, err = s.userStore.GetUserById(r.Context(), userEmail.UserID)
+	}
+	switch err.(type) {
+	case nil:
+		if err := s.sendPasswordReset(r.Context(), user, userEmail.Email); err != nil {
+			errMsg := fmt.Sprintf("Error when sending password reset: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+	case emails.ErrEmailNotFound, users.ErrUserNotFound:
+		s.logger.Printf("No user to reset the password of: %v", err)
+	default:
+		errMsg := fmt.Sprintf("Error when getting user by email: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.ForgotPasswordForm(formEmail, true, nil))
+}
+
+// Makes a new reset link for the user and emails it to them
+func (s *server) sendPasswordReset(ctx context.Context, user db.User, to string) error {
+	token, tokenHash, err := resets.NewToken()
+	if err != nil {
+		return err
+	}
+	_, err = s.resetStore.AddPasswordReset(ctx, db.AddPasswordResetParams{
+		TokenHash: tokenHash,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(resets.Lifetime),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/password/reset?token=" + url.QueryEscape(token)
+	return s.queueEmail(ctx, to, "Reset your password", templates.PasswordResetEmail(user.Username, link, resets.Lifetime))
+}
+
+// The reset the token from a link is for, if it can still be used
+func (s *server) getUsableReset(ctx context.Context, token string) (db.PasswordReset, error) {
+	reset, err := s.resetStore.GetPasswordReset(ctx, resets.HashToken(token))
+	if err != nil {
+		return db.PasswordReset{}, err
+	}
+	if !resets.Usable(reset, time.Now()) {
+		return db.PasswordReset{}, resets.ErrPasswordResetUsed{}
+	}
+	return reset, nil
+}
+
+// What's shown for links which don't work
+const unusableResetMessage = "This link has expired or has already been used"
+
+// GET /password/reset
+func (s *server) resetPasswordFormHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	if _, err := s.getUsableReset(r.Context(), token); err != nil {
+		errMsg := fmt.Sprintf("Error when getting password reset: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case resets.ErrPasswordResetNotFound, resets.ErrPasswordResetUsed:
+			w.WriteHeader(http.StatusNotFound)
+			renderTemplate(w, r, templates.ResetPasswordForm("", false, map[string]string{"token": unusableResetMessage}), "Reset Password")
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.ResetPasswordForm(token, false, nil), "Reset Password")
+}
+
+// POST /password/reset
+func (s *server) resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Resetting a password")
+
+	token := r.FormValue("token")
+	formPassword := r.FormValue("password")
+	formConfirmPassword := r.FormValue("confirm-password")
+
+	validationErrors := make(map[string]string)
+	if formPassword == "" {
+		validationErrors["password"] = "Password is required"
+	}
+	if formConfirmPassword == "" {
+		validationErrors["confirm-password"] = "Confirm password is required"
+	}
+	if formPassword != formConfirmPassword {
+		validationErrors["confirm-password"] = "Passwords do not match"
+	}
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.ResetPasswordForm(token, false, validationErrors))
+		return
+	}
+
+	// The link is used up before the password's changed, so if it's used twice at once only one
+	// of them changes it
+	reset, err := s.getUsableReset(r.Context(), token)
+	if err == nil {
+		err = s.resetStore.UsePasswordReset(r.Context(), reset.TokenHash, time.Now())
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when using password reset: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case resets.ErrPasswordResetNotFound, resets.ErrPasswordResetUsed:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderTemplate(w, r, templates.ResetPasswordForm("", false, map[string]string{"token": unusableResetMessage}))
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(formPassword), bcrypt.DefaultCost)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when hashing password: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if err := s.userStore.SetUserPassword(r.Context(), reset.UserID, string(passwordHash)); err != nil {
+		errMsg := fmt.Sprintf("Error when setting password: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.ResetPasswordForm("", true, nil))
+}
+
+// GET /outbox
+func (s *server) outboxHandler(w http.ResponseWriter, r *http.Request) {
+	latest, err := s.outboxStore.GetOutbox(r.Context(), outboxPageSize)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting outbox: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Outbox(latest), "Outbox")
+}
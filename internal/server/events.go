@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/events"
+	"beer_oclock/internal/templates"
+)
+
+// How many events a connection can fall behind by before it misses some
+const eventBuffer = 16
+
+// How often to send a comment down an idle connection, so proxies don't time it out
+const eventKeepAlive = 30 * time.Second
+
+// GET /events
+//
+// Streams changes to beers and brewers as server-sent events named after what changed, e.g.
+// "beers". Each event's data is the out-of-band swaps which show the change in the lists, so only
+// the beer or brewer which changed is swapped.
+func (s *server) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errMsg := "Error when streaming events: the connection can't be flushed"
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	received, unsubscribe := s.events.Subscribe(eventBuffer)
+	defer unsubscribe()
+	s.logger.Printf("Streaming events to user %d", currentUserId(r))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	// Browsers reconnect on their own, so say how soon
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-received:
+			if !ok {
+				// The server's shutting down
+				return
+			}
+			var data bytes.Buffer
+			if err := s.renderEvent(r.Context(), &data, currentUserId(r), event); err != nil {
+				s.logger.Printf("Error when rendering event: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\n", event.Topic)
+			// Each line of the data needs a field of its own
+			for _, line := range strings.Split(data.String(), "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// Renders the swaps which show the change to the user, as the lists they're in would show it
+func (s *server) renderEvent(ctx context.Context, w io.Writer, userId int64, event events.Event) error {
+	switch event.Topic {
+	case events.Beers:
+		if event.Action == events.Deleted {
+			return templates.BeerDeletedEvent(event.ID).Render(ctx, w)
+		}
+		beer, err := s.beerStore.GetBeer(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+		beerTags, err := s.tagStore.GetBeerTags(ctx, beer.ID)
+		if err != nil {
+			return err
+		}
+		quantity, err := s.getStockLevel(ctx, beer.ID)
+		if err != nil {
+			return err
+		}
+		wished, err := s.getWished(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if event.Action == events.Updated {
+			return templates.BeerChangedEvent(beer, beerTags, quantity, wished[beer.ID]).Render(ctx, w)
+		}
+		return templates.BeerAddedEvent(beer, beerTags, quantity, wished[beer.ID]).Render(ctx, w)
+	case events.Brewers:
+		if event.Action == events.Deleted {
+			return templates.BrewerDeletedEvent(event.ID).Render(ctx, w)
+		}
+		brewer, err := s.brewerStore.GetBrewer(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+		return templates.BrewerAddedEvent(brewer).Render(ctx, w)
+	}
+	return fmt.Errorf("unknown topic %q", event.Topic)
+}
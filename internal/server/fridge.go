@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/templates"
+)
+
+// The size of a standard can, which the form for adding stock starts with
+const defaultContainerMl = 375
+
+// How many of the beer are in the fridge
+func (s *server) getStockLevel(ctx context.Context, beerId int64) (int64, error) {
+	levels, err := s.stockStore.GetStockLevels(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return levels[beerId], nil
+}
+
+// Reads the form for adding stock, returning what's wrong with it keyed by field
+func parseStock(r *http.Request) (db.AddStockParams, map[string]string) {
+	params := db.AddStockParams{}
+	validationErrors := make(map[string]string)
+
+	if formBeerId := r.FormValue("beer-id"); formBeerId == "" {
+		validationErrors["beer-id"] = "Beer is required"
+	} else if beerId, err := strconv.ParseInt(formBeerId, 10, 64); err != nil {
+		validationErrors["beer-id"] = "Choose a beer from the list"
+	} else {
+		params.BeerID = beerId
+	}
+
+	if quantity, err := strconv.ParseInt(r.FormValue("quantity"), 10, 64); err != nil || quantity < 1 {
+		validationErrors["quantity"] = "Quantity must be a whole number of at least 1"
+	} else {
+		params.Quantity = quantity
+	}
+
+	if containerMl, err := strconv.ParseInt(r.FormValue("container-ml"), 10, 64); err != nil || containerMl < 1 {
+		validationErrors["container-ml"] = "Size must be a whole number of millilitres"
+	} else {
+		params.ContainerMl = containerMl
+	}
+
+	if formPurchasedOn := r.FormValue("purchased-on"); formPurchasedOn == "" {
+		validationErrors["purchased-on"] = "Purchase date is required"
+	} else if purchasedOn, err := time.Parse(time.DateOnly, formPurchasedOn); err != nil {
+		validationErrors["purchased-on"] = "Purchase date must be a date"
+	} else {
+		params.PurchasedOn = purchasedOn
+	}
+
+	if formBestBefore := r.FormValue("best-before"); formBestBefore != "" {
+		bestBefore, err := time.Parse(time.DateOnly, formBestBefore)
+		if err != nil {
+			validationErrors["best-before"] = "Best before must be a date"
+		} else {
+			params.BestBefore = sql.NullTime{Valid: true, Time: bestBefore}
+		}
+	}
+
+	if formPrice := r.FormValue("price"); formPrice != "" {
+		price, err := strconv.ParseFloat(formPrice, 64)
+		if err != nil || price < 0 {
+			validationErrors["price"] = "Price must be a number of at least 0"
+		} else {
+			params.Price = sql.NullFloat64{Valid: true, Float64: price}
+		}
+	}
+
+	params.Currency = strings.ToUpper(strings.TrimSpace(r.FormValue("currency")))
+	if params.Currency == "" {
+		params.Currency = defaultCurrency
+	} else if !stock.ValidCurrency(params.Currency) {
+		validationErrors["currency"] = "Currency must be a three letter code, like AUD"
+	}
+
+	return params, validationErrors
+}
+
+// Renders the fridge and the form for adding to it
+func (s *server) renderFridge(w http.ResponseWriter, r *http.Request, formData db.AddStockParams, validationErrors map[string]string) {
+	fridge, err := s.stockStore.GetFridge(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting fridge: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beers, err := s.beerStore.GetBeers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	renderTemplate(w, r, templates.Fridge(fridge, beers, formData, validationErrors, time.Now()), "Fridge")
+}
+
+// The form for adding stock as it starts out
+func newStockForm() db.AddStockParams {
+	return db.AddStockParams{ContainerMl: defaultContainerMl, PurchasedOn: time.Now(), Currency: defaultCurrency}
+}
+
+// GET /fridge
+func (s *server) fridgeHandler(w http.ResponseWriter, r *http.Request) {
+	s.renderFridge(w, r, newStockForm(), nil)
+}
+
+// POST /fridge
+func (s *server) addStockHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Adding stock")
+
+	params, validationErrors := parseStock(r)
+	if len(validationErrors) > 0 {
+		s.renderFridge(w, r, params, validationErrors)
+		return
+	}
+
+	params.UserID = sql.NullInt64{Valid: true, Int64: currentUserId(r)}
+	if _, err := s.stockStore.AddStock(r.Context(), params); err != nil {
+		errMsg := fmt.Sprintf("Error when adding stock: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderFridge(w, r, params, map[string]string{err.Field: "This field is required"})
+		case store.ErrInvalidField:
+			s.renderFridge(w, r, params, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)})
+		case beers.ErrBeerNotFound:
+			s.renderFridge(w, r, params, map[string]string{"beer-id": fmt.Sprintf("Beer with id %d not found", err.ID)})
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderFridge(w, r, newStockForm(), nil)
+}
+
+// DELETE /stock/{id}
+func (s *server) deleteStockHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting stock with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := s.stockStore.DeleteStock(r.Context(), int64(id)); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting stock: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case stock.ErrStockNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Return nothing so the entry is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /beer/{id}/take
+func (s *server) takeOneHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	beerId := int64(id)
+
+	s.logger.Printf("Taking one of beer with id: %d", beerId)
+
+	taken, err := s.stockStore.TakeOne(r.Context(), beerId)
+	switch err.(type) {
+	case nil:
+		// Whoever took it out is having it
+		if err := s.logTakenDrink(r, taken); err != nil {
+			errMsg := fmt.Sprintf("Error when logging drink: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+	case stock.ErrOutOfStock:
+		// Someone else took the last one, so show that there are none left
+		s.logger.Printf("Error when taking one: %v", err)
+		w.WriteHeader(http.StatusConflict)
+	default:
+		errMsg := fmt.Sprintf("Error when taking one: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	quantity, err := s.getStockLevel(r.Context(), beerId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock level: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.StockBadge(beerId, quantity))
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/templates"
+)
+
+// The user's goals, or the zero goals if they haven't set any
+func (s *server) getGoals(r *http.Request) (db.Goal, error) {
+	userGoals, err := s.goalStore.GetGoals(r.Context(), currentUserId(r))
+	if _, ok := err.(goals.ErrGoalsNotFound); ok {
+		return db.Goal{}, nil
+	}
+	return userGoals, err
+}
+
+// Where the user is, for working out which day and week their drinks are in
+func (s *server) userLocation(r *http.Request) (*time.Location, error) {
+	userGoals, err := s.getGoals(r)
+	if err != nil {
+		return nil, err
+	}
+	return goals.Location(userGoals, s.location), nil
+}
+
+// How the week now is in is going against the user's goals. Now should be where the user is.
+func (s *server) getProgress(r *http.Request, userGoals db.Goal, now time.Time) (goals.Progress, error) {
+	since, until := goals.Week(now)
+	days, err := s.drinkStore.GetDrinksPerDay(r.Context(), currentUserId(r), since, until)
+	if err != nil {
+		return goals.Progress{}, err
+	}
+	return goals.Evaluate(userGoals, days, now), nil
+}
+
+// Why having another standardDrinks would break the user's goals, to ask them if they're sure
+func overLimitWarning(progress goals.Progress, standardDrinks float64) string {
+	limit := progress.Goals.WeeklyLimit
+	if limit.Valid && progress.StandardDrinks+standardDrinks > limit.Float64 {
+		return fmt.Sprintf("That would make %.1f standard drinks this week, over your limit of %g", progress.StandardDrinks+standardDrinks, limit.Float64)
+	}
+	return fmt.Sprintf("That would leave fewer than %d alcohol-free days this week", progress.Goals.AlcoholFreeDays.Int64)
+}
+
+// Reads the form for setting goals, returning what's wrong with it keyed by field. Either goal can
+// be left blank to not have it.
+func parseGoals(r *http.Request) (db.SetGoalsParams, map[string]string) {
+	params := db.SetGoalsParams{UserID: currentUserId(r)}
+	validationErrors := make(map[string]string)
+
+	if formLimit := r.FormValue("weekly-limit"); formLimit != "" {
+		if limit, err := strconv.ParseFloat(formLimit, 64); err != nil || limit <= 0 {
+			validationErrors["weekly-limit"] = "Weekly limit must be a number of standard drinks more than 0"
+		} else {
+			params.WeeklyLimit.Valid, params.WeeklyLimit.Float64 = true, limit
+		}
+	}
+
+	if formDays := r.FormValue("alcohol-free-days"); formDays != "" {
+		if days, err := strconv.ParseInt(formDays, 10, 64); err != nil || days < 1 || days > 7 {
+			validationErrors["alcohol-free-days"] = "Alcohol-free days must be a whole number from 1 to 7"
+		} else {
+			params.AlcoholFreeDays.Valid, params.AlcoholFreeDays.Int64 = true, days
+		}
+	}
+
+	params.ConfirmOverLimit = r.FormValue("confirm-over-limit") == "true"
+
+	params.TimeZone = strings.TrimSpace(r.FormValue("time-zone"))
+	if _, err := time.LoadLocation(params.TimeZone); err != nil {
+		validationErrors["time-zone"] = "Time zone must be one like Australia/Sydney"
+	}
+
+	return params, validationErrors
+}
+
+// The form for setting goals as it starts out
+func newGoalsForm(userGoals db.Goal) db.SetGoalsParams {
+	return db.SetGoalsParams{
+		WeeklyLimit:      userGoals.WeeklyLimit,
+		AlcoholFreeDays:  userGoals.AlcoholFreeDays,
+		ConfirmOverLimit: userGoals.ConfirmOverLimit,
+		TimeZone:         userGoals.TimeZone,
+	}
+}
+
+// Renders the goals form with how this week is going against them
+func (s *server) renderGoals(w http.ResponseWriter, r *http.Request, userGoals db.Goal, formData db.SetGoalsParams, validationErrors map[string]string) {
+	progress, err := s.getProgress(r, userGoals, time.Now().In(goals.Location(userGoals, s.location)))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting progress: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	renderTemplate(w, r, templates.GoalsForm(progress, formData, validationErrors))
+}
+
+// GET /goals
+func (s *server) goalsHandler(w http.ResponseWriter, r *http.Request) {
+	userGoals, err := s.getGoals(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := s.getProgress(r, userGoals, time.Now().In(goals.Location(userGoals, s.location)))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting progress: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Goals(progress, newGoalsForm(userGoals)), "Goals")
+}
+
+// PUT /goals
+func (s *server) setGoalsHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Setting goals")
+
+	current, err := s.getGoals(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	params, validationErrors := parseGoals(r)
+	if len(validationErrors) > 0 {
+		s.renderGoals(w, r, current, params, validationErrors)
+		return
+	}
+
+	userGoals, err := s.goalStore.SetGoals(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting goals: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderGoals(w, r, current, params, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)})
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderGoals(w, r, userGoals, newGoalsForm(userGoals), nil)
+}
+
+// DELETE /goals
+func (s *server) deleteGoalsHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting goals")
+
+	_, err := s.goalStore.DeleteGoals(r.Context(), currentUserId(r))
+	switch err.(type) {
+	case nil, goals.ErrGoalsNotFound:
+	default:
+		errMsg := fmt.Sprintf("Error when deleting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.renderGoals(w, r, db.Goal{}, newGoalsForm(db.Goal{}), nil)
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/images"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/blobs"
+	"beer_oclock/internal/store/photos"
+	"beer_oclock/internal/templates"
+)
+
+// The largest photo which can be uploaded
+const maxPhotoSize = 10 << 20
+
+// The longest side of a thumbnail, in pixels
+const thumbnailSize = 320
+
+// A random name for a blob, so keys can't be guessed or clash
+func newBlobName() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Deletes the photos' images, logging rather than returning errors since the records are already
+// gone by then
+func (s *server) deletePhotoBlobs(ctx context.Context, beerPhotos []db.LabelPhoto) {
+	for _, photo := range beerPhotos {
+		for _, key := range []string{photo.BlobKey, photo.ThumbKey} {
+			if err := s.blobStore.Delete(ctx, key); err != nil {
+				s.logger.Printf("Error when deleting blob %s of photo %d: %v", key, photo.ID, err)
+			}
+		}
+	}
+}
+
+// Permanently removes a beer from the trash along with its label photos
+func (s *server) purgeBeer(ctx context.Context, id int64) (db.Beer, error) {
+	beerPhotos, err := s.photoStore.GetBeerPhotos(ctx, id)
+	if err != nil {
+		return db.Beer{}, err
+	}
+
+	beer, err := s.beerStore.PurgeBeer(ctx, id)
+	if err != nil {
+		return db.Beer{}, err
+	}
+
+	// The database removes the records with the beer, but not every store has foreign keys
+	if _, err := s.photoStore.DeleteBeerPhotos(ctx, id); err != nil {
+		s.logger.Printf("Error when deleting photos of purged beer %d: %v", id, err)
+	}
+	s.deletePhotoBlobs(ctx, beerPhotos)
+	return beer, nil
+}
+
+// Re-renders the beer's photos with an error, as a validation error so HTMX swaps it in
+func (s *server) photoUploadError(w http.ResponseWriter, r *http.Request, beerId int64, msg string) {
+	beerPhotos, err := s.photoStore.GetBeerPhotos(r.Context(), beerId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer photos: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	renderTemplate(w, r, templates.BeerPhotos(beerId, beerPhotos, msg))
+}
+
+// POST /beer/{id}/photos
+func (s *server) uploadPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	beerId := int64(id)
+
+	s.logger.Printf("Uploading photo of beer with id: %d", beerId)
+
+	if _, err := s.beerStore.GetBeer(r.Context(), beerId); err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Leave some room for the rest of the multipart body
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoSize+1<<20)
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		s.logger.Printf("Error when reading uploaded photo: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.photoUploadError(w, r, beerId, fmt.Sprintf("Photos can be at most %d MB", maxPhotoSize>>20))
+		} else {
+			s.photoUploadError(w, r, beerId, "Choose a photo to upload")
+		}
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxPhotoSize+1))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when reading uploaded photo: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if len(data) > maxPhotoSize {
+		s.photoUploadError(w, r, beerId, fmt.Sprintf("Photos can be at most %d MB", maxPhotoSize>>20))
+		return
+	}
+
+	img, contentType, err := images.Decode(data)
+	if err != nil {
+		s.logger.Printf("Error when decoding uploaded photo: %v", err)
+		switch err.(type) {
+		case images.ErrUnsupportedType:
+			s.photoUploadError(w, r, beerId, "Photos must be JPEG, PNG or GIF images")
+		case images.ErrTooManyPixels:
+			s.photoUploadError(w, r, beerId, "That photo has too many pixels, try a smaller one")
+		default:
+			s.photoUploadError(w, r, beerId, "That photo couldn't be read")
+		}
+		return
+	}
+
+	var thumb bytes.Buffer
+	if err := images.EncodeThumbnail(&thumb, images.Thumbnail(img, thumbnailSize)); err != nil {
+		errMsg := fmt.Sprintf("Error when making thumbnail: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	name := newBlobName()
+	blobKey := fmt.Sprintf("labels/%d/%s.%s", beerId, name, strings.TrimPrefix(contentType, "image/"))
+	thumbKey := fmt.Sprintf("labels/%d/%s-thumb.jpeg", beerId, name)
+
+	if err := s.blobStore.Put(r.Context(), blobKey, bytes.NewReader(data)); err != nil {
+		errMsg := fmt.Sprintf("Error when storing photo: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if err := s.blobStore.Put(r.Context(), thumbKey, &thumb); err != nil {
+		s.blobStore.Delete(r.Context(), blobKey)
+		errMsg := fmt.Sprintf("Error when storing thumbnail: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	bounds := img.Bounds()
+	photo, err := s.photoStore.AddPhoto(r.Context(), db.AddLabelPhotoParams{
+		BeerID:      beerId,
+		UserID:      sql.NullInt64{Valid: true, Int64: currentUserId(r)},
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       int64(bounds.Dx()),
+		Height:      int64(bounds.Dy()),
+		BlobKey:     blobKey,
+		ThumbKey:    thumbKey,
+	})
+	if err != nil {
+		s.deletePhotoBlobs(r.Context(), []db.LabelPhoto{{BlobKey: blobKey, ThumbKey: thumbKey}})
+		errMsg := fmt.Sprintf("Error when adding photo: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerPhotos, err := s.photoStore.GetBeerPhotos(r.Context(), photo.BeerID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer photos: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeerPhotos(beerId, beerPhotos, ""))
+}
+
+// GET /photo/{id} or GET /photo/{id}/thumb
+func (s *server) getPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	photo, err := s.photoStore.GetPhoto(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting photo: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case photos.ErrPhotoNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	key, contentType := photo.BlobKey, photo.ContentType
+	if strings.HasSuffix(r.URL.Path, "/thumb") {
+		key, contentType = photo.ThumbKey, images.ThumbnailContentType
+	}
+
+	contents, err := s.blobStore.Get(r.Context(), key)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting photo blob: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case blobs.ErrBlobNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+	defer contents.Close()
+
+	// Photos are only for logged in users, so they mustn't be kept by shared caches
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	io.Copy(w, contents)
+}
+
+// DELETE /photo/{id}
+func (s *server) deletePhotoHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting photo with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	photo, err := s.photoStore.DeletePhoto(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting photo: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case photos.ErrPhotoNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+	s.deletePhotoBlobs(r.Context(), []db.LabelPhoto{photo})
+
+	// Return nothing so the photo is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/notify"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/schedules"
+	"beer_oclock/internal/templates"
+)
+
+// How often to check whether any reminders are due
+const reminderInterval = time.Minute
+
+// Periodically send the reminders which are due, until the context is cancelled
+func (s *server) remindPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(reminderInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendReminders(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send every reminder which is due at now, each in the time zone of the user it's for. Reminders
+// which can't be sent are tried again next time, until that beer o'clock is over.
+func (s *server) sendReminders(ctx context.Context, now time.Time) {
+	all, err := s.scheduleStore.GetAllSchedules(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, row := range all {
+		userNow := now.In(goals.Location(db.Goal{TimeZone: row.TimeZone}, s.location))
+
+		at, due := schedules.Due(row.Schedule, userNow)
+		if !due {
+			continue
+		}
+		notification := reminder(row, at, userNow)
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			s.logger.Printf("Error when sending reminder for schedule %d: %v", row.Schedule.ID, err)
+			continue
+		}
+		s.emailReminder(ctx, notification)
+		s.scheduleStore.SetScheduleReminded(ctx, row.Schedule.ID, now)
+	}
+}
+
+// The reminder about beer o'clock at at, which is either coming up or has started by now
+func reminder(row db.GetAllSchedulesRow, at time.Time, now time.Time) notify.Notification {
+	notification := notify.Notification{UserID: row.Schedule.UserID, Username: row.Username}
+	if at.After(now) {
+		notification.Subject = "Beer o'clock is coming up"
+		notification.Body = fmt.Sprintf("It's beer o'clock at %s on %s.", at.Format("15:04"), at.Format("Monday"))
+	} else {
+		notification.Subject = "It's beer o'clock!"
+		notification.Body = fmt.Sprintf("It's been beer o'clock since %s.", at.Format("15:04"))
+	}
+	return notification
+}
+
+// The user's schedule, and when it's next beer o'clock for them, which is zero if they haven't
+// got a schedule
+func (s *server) getSchedules(r *http.Request) ([]db.Schedule, time.Time, time.Time, error) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	userSchedules, err := s.scheduleStore.GetUserSchedules(r.Context(), currentUserId(r))
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	now := time.Now().In(location)
+	next, _ := schedules.Soonest(userSchedules, now)
+	return userSchedules, next, now, nil
+}
+
+// Reads the form for adding to the schedule, returning what's wrong with it keyed by field
+func parseSchedule(r *http.Request) (db.AddScheduleParams, map[string]string) {
+	params := db.AddScheduleParams{UserID: currentUserId(r)}
+	validationErrors := make(map[string]string)
+
+	if weekday, err := strconv.ParseInt(r.FormValue("weekday"), 10, 64); err != nil || weekday < 0 || weekday > 6 {
+		validationErrors["weekday"] = "Day must be a day of the week"
+	} else {
+		params.Weekday = weekday
+	}
+
+	if timeOfDay, err := time.Parse("15:04", r.FormValue("time")); err != nil {
+		validationErrors["time"] = "Time must be a time of day, like 17:00"
+	} else {
+		params.Minute = int64(timeOfDay.Hour()*60 + timeOfDay.Minute())
+	}
+
+	if formRemindBefore := r.FormValue("remind-before"); formRemindBefore != "" {
+		remindBefore, err := strconv.ParseInt(formRemindBefore, 10, 64)
+		if err != nil || remindBefore < 0 || remindBefore > schedules.MaxRemindBefore {
+			validationErrors["remind-before"] = fmt.Sprintf("Remind me must be a number of minutes from 0 to %d", schedules.MaxRemindBefore)
+		} else {
+			params.RemindBefore = remindBefore
+		}
+	}
+
+	return params, validationErrors
+}
+
+// Renders the schedule with the form for adding to it
+func (s *server) renderSchedule(w http.ResponseWriter, r *http.Request, formData db.AddScheduleParams, validationErrors map[string]string, status int) {
+	userSchedules, next, now, err := s.getSchedules(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting schedule: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.ScheduleForm(userSchedules, next, now, formData, validationErrors))
+}
+
+// GET /schedule
+func (s *server) scheduleHandler(w http.ResponseWriter, r *http.Request) {
+	userSchedules, next, now, err := s.getSchedules(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting schedule: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Schedule(userSchedules, next, now, newScheduleForm()), "Schedule")
+}
+
+// The form for adding to the schedule as it starts out, at the traditional Friday knock-off
+func newScheduleForm() db.AddScheduleParams {
+	return db.AddScheduleParams{Weekday: int64(time.Friday), Minute: 17 * 60}
+}
+
+// POST /schedule
+func (s *server) addScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Adding to schedule")
+
+	params, validationErrors := parseSchedule(r)
+	if len(validationErrors) > 0 {
+		s.renderSchedule(w, r, params, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	_, err := s.scheduleStore.AddSchedule(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding to schedule: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderSchedule(w, r, params, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		case schedules.ErrScheduleAlreadyExists:
+			s.renderSchedule(w, r, params, map[string]string{"time": "Beer o'clock is already then"}, http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderSchedule(w, r, newScheduleForm(), nil, http.StatusOK)
+}
+
+// DELETE /schedule/{id}
+func (s *server) deleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting schedule with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Users can only change their own schedule, and other users' are treated as not being there
+	schedule, err := s.scheduleStore.GetSchedule(r.Context(), int64(id))
+	if err == nil && schedule.UserID != currentUserId(r) {
+		err = schedules.ErrScheduleNotFound{ID: schedule.ID}
+	}
+	if err == nil {
+		_, err = s.scheduleStore.DeleteSchedule(r.Context(), schedule.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting schedule: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case schedules.ErrScheduleNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderSchedule(w, r, newScheduleForm(), nil, http.StatusOK)
+}
+
+// GET /countdown
+func (s *server) countdownHandler(w http.ResponseWriter, r *http.Request) {
+	_, next, now, err := s.getSchedules(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting schedule: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Countdown(next, now))
+}
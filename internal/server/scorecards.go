@@ -0,0 +1,84 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/templates"
+)
+
+// Reads a value for each scorecard dimension from the form, along with a validation error for any
+// that are missing or not a number
+func parseDimensions(r *http.Request) (scorecards.Scores, map[string]string) {
+	values := scorecards.Scores{}
+	validationErrors := make(map[string]string)
+	for i, dimension := range scorecards.Dimensions {
+		formValue := r.FormValue(dimension.Name)
+		if formValue == "" {
+			validationErrors[dimension.Name] = fmt.Sprintf("%s is required", dimension.Label)
+			continue
+		}
+		value, err := strconv.ParseFloat(formValue, 64)
+		if err != nil {
+			validationErrors[dimension.Name] = fmt.Sprintf("%s must be a number", dimension.Label)
+			continue
+		}
+		values[i] = value
+	}
+	return values, validationErrors
+}
+
+// Reads the scorecard in the beer form, which has a score out of 10 for each dimension
+func parseScores(r *http.Request) (scorecards.Scores, map[string]string) {
+	scores, validationErrors := parseDimensions(r)
+	for i, dimension := range scorecards.Dimensions {
+		if _, ok := validationErrors[dimension.Name]; !ok && (scores[i] < 0 || scores[i] > 10) {
+			validationErrors[dimension.Name] = fmt.Sprintf("%s must be between 0 and 10", dimension.Label)
+		}
+	}
+	return scores, validationErrors
+}
+
+// GET /scorecard/weights
+func (s *server) getScorecardWeightsHandler(w http.ResponseWriter, r *http.Request) {
+	weights, err := s.scorecardStore.GetWeights(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.ScorecardWeightsForm(weights, nil, false), "Scorecard Weights")
+}
+
+// PUT /scorecard/weights
+func (s *server) updateScorecardWeightsHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Updating scorecard weights")
+
+	weights, validationErrors := parseDimensions(r)
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.ScorecardWeightsForm(weights, validationErrors, false))
+		return
+	}
+
+	if _, err := s.scorecardStore.SetWeights(r.Context(), weights); err != nil {
+		errMsg := fmt.Sprintf("Error when setting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderTemplate(w, r, templates.ScorecardWeightsForm(weights, map[string]string{err.Field: err.Error()}, false))
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.ScorecardWeightsForm(weights, nil, true))
+}
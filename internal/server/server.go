@@ -7,20 +7,50 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"beer_oclock/internal/db"
+	"beer_oclock/internal/ean"
+	"beer_oclock/internal/events"
+	"beer_oclock/internal/mail"
 	"beer_oclock/internal/middleware"
+	"beer_oclock/internal/notify"
 	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/badges"
+	"beer_oclock/internal/store/barcodes"
 	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/blobs"
 	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/budgets"
+	"beer_oclock/internal/store/comments"
+	"beer_oclock/internal/store/drinklog"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/emails"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/outbox"
+	"beer_oclock/internal/store/photos"
+	"beer_oclock/internal/store/resets"
+	"beer_oclock/internal/store/schedules"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/shouts"
+	"beer_oclock/internal/store/social"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/store/tags"
+	"beer_oclock/internal/store/tastings"
 	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/store/venues"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/store/wishlist"
 	"beer_oclock/internal/templates"
+	"beer_oclock/internal/webhook"
 
 	"github.com/a-h/templ"
 	"github.com/gorilla/sessions"
@@ -29,26 +59,197 @@ import (
 
 const AppName = "Beer O'clock"
 
+// How long deleted items stay in the trash before they are purged, unless overridden by TRASH_RETENTION
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// The stores the server reads and writes through, all of which are required
+type Stores struct {
+	Users      users.Store
+	Brewers    brewers.Store
+	Beers      beers.Store
+	Styles     styles.Store
+	Scorecards scorecards.Store
+	Tags       tags.Store
+	Photos     photos.Store
+	Barcodes   barcodes.Store
+	Stock      stock.Store
+	Budgets    budgets.Store
+	Drinks     drinklog.Store
+	Goals      goals.Store
+	Schedules  schedules.Store
+	Emails     emails.Store
+	Resets     resets.Store
+	Outbox     outbox.Store
+	Webhooks   webhooks.Store
+	// Drinks grouped into nights out
+	DrinkSessions drinksessions.Store
+	// Where beers were had
+	Venues venues.Store
+	// Who bought which rounds in the drinking sessions
+	Shouts shouts.Store
+	// Who follows whom, who can see what they do, and what they've done
+	Social social.Store
+	// What's been said about beers and brewers
+	Comments comments.Store
+	// Blind tastings with their pours and scores
+	Tastings tastings.Store
+	// What badges and leaderboards are worked out from
+	Badges badges.Store
+	// The beers each user wants to try
+	Wishlist wishlist.Store
+	// Where the label photos themselves are kept
+	Blobs blobs.Store
+	// Where barcodes which aren't on any of our beers are looked up
+	Lookup ean.Lookup
+	// How beer o'clock reminders get to users
+	Notifier notify.Notifier
+	// How the emails in the outbox are sent
+	Mailer mail.Sender
+	// Where the beer and brewer stores publish their changes, for the pages showing them to update
+	Events *events.Bus
+	// What the stores' queries are, for changes across several stores to be made in one
+	// transaction. Without them, as with the in-memory stores, the changes are made one by one.
+	Queries db.Querier
+}
+
 type server struct {
-	logger       *log.Logger
-	port         int
-	httpServer   *http.Server
-	userStore    *users.UserStore
-	brewerStore  *brewers.BrewerStore
-	beerStore    *beers.BeerStore
-	sessionStore *BeerOclockSessionStore
+	logger            *log.Logger
+	port              int
+	httpServer        *http.Server
+	userStore         users.Store
+	brewerStore       brewers.Store
+	beerStore         beers.Store
+	styleStore        styles.Store
+	scorecardStore    scorecards.Store
+	tagStore          tags.Store
+	photoStore        photos.Store
+	blobStore         blobs.Store
+	barcodeStore      barcodes.Store
+	barcodeLookup     ean.Lookup
+	stockStore        stock.Store
+	budgetStore       budgets.Store
+	drinkStore        drinklog.Store
+	goalStore         goals.Store
+	scheduleStore     schedules.Store
+	emailStore        emails.Store
+	resetStore        resets.Store
+	outboxStore       outbox.Store
+	webhookStore      webhooks.Store
+	drinkSessionStore drinksessions.Store
+	venueStore        venues.Store
+	shoutStore        shouts.Store
+	socialStore       social.Store
+	commentStore      comments.Store
+	tastingStore      tastings.Store
+	badgeStore        badges.Store
+	wishlistStore     wishlist.Store
+	queries           db.Querier
+	notifier          notify.Notifier
+	mailer            mail.Sender
+	webhookSender     *webhook.Sender
+	events            *events.Bus
+	sessionStore      *BeerOclockSessionStore
+	trashRetention    time.Duration
+	// Where the days drinks are on are worked out for, unless the user's set their own time zone
+	location *time.Location
+	// Where the app is, for links in emails, without a trailing slash
+	baseURL string
 }
 
 // Creat a new server instance with the given logger and port
-func NewServer(logger *log.Logger, port int, userStore *users.UserStore, brewerStore *brewers.BrewerStore, beerStore *beers.BeerStore) (*server, error) {
+func NewServer(logger *log.Logger, port int, stores Stores) (*server, error) {
 	if logger == nil {
 		return nil, fmt.Errorf("logger is required")
 	}
-	if userStore == nil {
-		return nil, fmt.Errorf("userStore is required")
+	if stores.Users == nil {
+		return nil, fmt.Errorf("user store is required")
+	}
+	if stores.Brewers == nil {
+		return nil, fmt.Errorf("brewer store is required")
+	}
+	if stores.Beers == nil {
+		return nil, fmt.Errorf("beer store is required")
+	}
+	if stores.Styles == nil {
+		return nil, fmt.Errorf("style store is required")
+	}
+	if stores.Scorecards == nil {
+		return nil, fmt.Errorf("scorecard store is required")
+	}
+	if stores.Tags == nil {
+		return nil, fmt.Errorf("tag store is required")
+	}
+	if stores.Photos == nil {
+		return nil, fmt.Errorf("photo store is required")
+	}
+	if stores.Barcodes == nil {
+		return nil, fmt.Errorf("barcode store is required")
+	}
+	if stores.Stock == nil {
+		return nil, fmt.Errorf("stock store is required")
+	}
+	if stores.Budgets == nil {
+		return nil, fmt.Errorf("budget store is required")
+	}
+	if stores.Drinks == nil {
+		return nil, fmt.Errorf("drink store is required")
+	}
+	if stores.Goals == nil {
+		return nil, fmt.Errorf("goal store is required")
+	}
+	if stores.Schedules == nil {
+		return nil, fmt.Errorf("schedule store is required")
+	}
+	if stores.Emails == nil {
+		return nil, fmt.Errorf("email store is required")
+	}
+	if stores.Resets == nil {
+		return nil, fmt.Errorf("password reset store is required")
+	}
+	if stores.Outbox == nil {
+		return nil, fmt.Errorf("outbox store is required")
+	}
+	if stores.Webhooks == nil {
+		return nil, fmt.Errorf("webhook store is required")
+	}
+	if stores.DrinkSessions == nil {
+		return nil, fmt.Errorf("drinking session store is required")
+	}
+	if stores.Venues == nil {
+		return nil, fmt.Errorf("venue store is required")
+	}
+	if stores.Shouts == nil {
+		return nil, fmt.Errorf("shout store is required")
+	}
+	if stores.Social == nil {
+		return nil, fmt.Errorf("social store is required")
+	}
+	if stores.Comments == nil {
+		return nil, fmt.Errorf("comment store is required")
+	}
+	if stores.Tastings == nil {
+		return nil, fmt.Errorf("tasting store is required")
+	}
+	if stores.Badges == nil {
+		return nil, fmt.Errorf("badge store is required")
+	}
+	if stores.Wishlist == nil {
+		return nil, fmt.Errorf("wishlist store is required")
 	}
-	if brewerStore == nil {
-		return nil, fmt.Errorf("brewerStore is required")
+	if stores.Blobs == nil {
+		return nil, fmt.Errorf("blob store is required")
+	}
+	if stores.Lookup == nil {
+		return nil, fmt.Errorf("barcode lookup is required")
+	}
+	if stores.Notifier == nil {
+		return nil, fmt.Errorf("notifier is required")
+	}
+	if stores.Mailer == nil {
+		return nil, fmt.Errorf("mailer is required")
+	}
+	if stores.Events == nil {
+		return nil, fmt.Errorf("event bus is required")
 	}
 
 	sessionKeyB64 := os.Getenv("SESSION_KEY")
@@ -63,21 +264,67 @@ func NewServer(logger *log.Logger, port int, userStore *users.UserStore, brewerS
 
 	cookieStore := sessions.NewCookieStore(sessionKeyBytes)
 
+	trashRetention := defaultTrashRetention
+	if trashRetentionStr := os.Getenv("TRASH_RETENTION"); trashRetentionStr != "" {
+		trashRetention, err = time.ParseDuration(trashRetentionStr)
+		if err != nil || trashRetention <= 0 {
+			return nil, fmt.Errorf("TRASH_RETENTION must be a positive duration, e.g. 720h: %v", err)
+		}
+	}
+
+	// Links in emails go to BASE_URL, e.g. https://beer.example.com, which is needed when the app
+	// is behind a proxy or on another port
+	baseURL := strings.TrimSuffix(os.Getenv("BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("http://localhost:%d", port)
+	} else if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("BASE_URL must be an http or https URL, e.g. https://beer.example.com: %v", err)
+	}
+
 	return &server{
-		logger:       logger,
-		port:         port,
-		userStore:    userStore,
-		brewerStore:  brewerStore,
-		beerStore:    beerStore,
-		sessionStore: NewBeerOclockSessionStore(cookieStore, userStore),
+		logger:            logger,
+		port:              port,
+		userStore:         stores.Users,
+		brewerStore:       stores.Brewers,
+		beerStore:         stores.Beers,
+		styleStore:        stores.Styles,
+		scorecardStore:    stores.Scorecards,
+		tagStore:          stores.Tags,
+		photoStore:        stores.Photos,
+		blobStore:         stores.Blobs,
+		barcodeStore:      stores.Barcodes,
+		barcodeLookup:     stores.Lookup,
+		stockStore:        stores.Stock,
+		budgetStore:       stores.Budgets,
+		drinkStore:        stores.Drinks,
+		goalStore:         stores.Goals,
+		scheduleStore:     stores.Schedules,
+		emailStore:        stores.Emails,
+		resetStore:        stores.Resets,
+		outboxStore:       stores.Outbox,
+		webhookStore:      stores.Webhooks,
+		drinkSessionStore: stores.DrinkSessions,
+		venueStore:        stores.Venues,
+		shoutStore:        stores.Shouts,
+		socialStore:       stores.Social,
+		commentStore:      stores.Comments,
+		tastingStore:      stores.Tastings,
+		badgeStore:        stores.Badges,
+		wishlistStore:     stores.Wishlist,
+		queries:           stores.Queries,
+		notifier:          stores.Notifier,
+		mailer:            stores.Mailer,
+		webhookSender:     webhook.NewSender(webhook.DefaultTimeout),
+		events:            stores.Events,
+		sessionStore:      NewBeerOclockSessionStore(cookieStore, stores.Users),
+		trashRetention:    trashRetention,
+		location:          time.Local,
+		baseURL:           baseURL,
 	}, nil
 }
 
-// Start the server
-func (s *server) Start() error {
-	s.logger.Printf("Starting server on port %d", s.port)
-	var stopChan chan os.Signal
-
+// Define the routes and the middleware protecting them
+func (s *server) routes() http.Handler {
 	// define router
 	router := http.NewServeMux()
 
@@ -85,6 +332,7 @@ func (s *server) Start() error {
 	authMiddleware := middleware.Auth(s.sessionStore, s.userStore)
 	loggingMiddleware := middleware.Chain(middleware.ContentType, middleware.Logging)
 	authLoggingMiddleware := middleware.Chain(middleware.ContentType, middleware.Logging, authMiddleware)
+	adminLoggingMiddleware := middleware.Chain(middleware.ContentType, middleware.Logging, authMiddleware, middleware.Admin(s.userStore))
 
 	// unprotected routes:
 	fileServer := http.FileServer(http.Dir("./static"))
@@ -96,9 +344,15 @@ func (s *server) Start() error {
 
 	router.Handle("GET /login", loggingMiddleware(http.HandlerFunc(s.loginFormHandler)))
 	router.Handle("POST /login", loggingMiddleware(http.HandlerFunc(s.loginHandler)))
+	router.Handle("GET /password/forgot", loggingMiddleware(http.HandlerFunc(s.forgotPasswordFormHandler)))
+	router.Handle("POST /password/forgot", loggingMiddleware(http.HandlerFunc(s.forgotPasswordHandler)))
+	router.Handle("GET /password/reset", loggingMiddleware(http.HandlerFunc(s.resetPasswordFormHandler)))
+	router.Handle("POST /password/reset", loggingMiddleware(http.HandlerFunc(s.resetPasswordHandler)))
+	router.Handle("GET /shared/session/{token}", loggingMiddleware(http.HandlerFunc(s.sharedDrinkSessionHandler)))
 
 	// protected routes:
 	router.Handle("GET /", authLoggingMiddleware(http.HandlerFunc(s.homeHandler)))
+	router.Handle("GET /events", authLoggingMiddleware(http.HandlerFunc(s.eventsHandler)))
 
 	router.Handle("GET /logout", authLoggingMiddleware(http.HandlerFunc(s.logoutHandler)))
 	router.Handle("POST /logout", authLoggingMiddleware(http.HandlerFunc(s.logoutHandler)))
@@ -108,12 +362,14 @@ func (s *server) Start() error {
 	router.Handle("DELETE /brewer/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteBrewerHandler)))
 	router.Handle("GET /brewers", authLoggingMiddleware(http.HandlerFunc(s.listBrewersHandler)))
 	router.Handle("GET /brewer/{id}", authLoggingMiddleware(http.HandlerFunc(s.getBrewerHandler)))
+	router.Handle("POST /brewer/{id}/restore", authLoggingMiddleware(http.HandlerFunc(s.restoreBrewerHandler)))
 
 	router.Handle("POST /user", authLoggingMiddleware(http.HandlerFunc(s.addUserHandler)))
 	router.Handle("GET /user/add", authLoggingMiddleware(http.HandlerFunc(s.getUserFormHandler)))
 	router.Handle("DELETE /user/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteUserHandler)))
 	router.Handle("GET /users", authLoggingMiddleware(http.HandlerFunc(s.listUsersHandler)))
 	router.Handle("GET /user/{id}", authLoggingMiddleware(http.HandlerFunc(s.getUserHandler)))
+	router.Handle("POST /user/{id}/restore", authLoggingMiddleware(http.HandlerFunc(s.restoreUserHandler)))
 
 	router.Handle("POST /beer", authLoggingMiddleware(http.HandlerFunc(s.addBeerHandler)))
 	router.Handle("GET /beer/add", authLoggingMiddleware(http.HandlerFunc(s.getBeerFormHandler)))
@@ -123,17 +379,147 @@ func (s *server) Start() error {
 	router.Handle("GET /beer/{id}/edit", authLoggingMiddleware(http.HandlerFunc(s.getBeerFormHandler)))
 	router.Handle("PUT /beer/{id}", authLoggingMiddleware(http.HandlerFunc(s.updateBeerHandler)))
 	router.Handle("POST /beer/search", authLoggingMiddleware(http.HandlerFunc(s.searchBeersHandler)))
+	router.Handle("POST /beer/{id}/restore", authLoggingMiddleware(http.HandlerFunc(s.restoreBeerHandler)))
+	router.Handle("GET /barcode/{code}", authLoggingMiddleware(http.HandlerFunc(s.barcodeHandler)))
+	router.Handle("POST /beer/{id}/barcodes", authLoggingMiddleware(http.HandlerFunc(s.addBarcodeHandler)))
+	router.Handle("DELETE /barcode/{code}", authLoggingMiddleware(http.HandlerFunc(s.deleteBarcodeHandler)))
+	router.Handle("GET /beer/{id}/history", authLoggingMiddleware(http.HandlerFunc(s.getBeerHistoryHandler)))
+	router.Handle("POST /beer/{id}/history/{revisionId}/revert", authLoggingMiddleware(http.HandlerFunc(s.revertBeerHandler)))
+
+	router.Handle("GET /styles/search", authLoggingMiddleware(http.HandlerFunc(s.searchStylesHandler)))
+	router.Handle("GET /styles/check", authLoggingMiddleware(http.HandlerFunc(s.checkStyleHandler)))
+
+	router.Handle("GET /fridge", authLoggingMiddleware(http.HandlerFunc(s.fridgeHandler)))
+	router.Handle("POST /fridge", authLoggingMiddleware(http.HandlerFunc(s.addStockHandler)))
+	router.Handle("DELETE /stock/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteStockHandler)))
+	router.Handle("POST /beer/{id}/take", authLoggingMiddleware(http.HandlerFunc(s.takeOneHandler)))
+
+	router.Handle("GET /spending", authLoggingMiddleware(http.HandlerFunc(s.spendingHandler)))
+	router.Handle("GET /spending.csv", authLoggingMiddleware(http.HandlerFunc(s.exportSpendingHandler)))
+	router.Handle("PUT /budget", authLoggingMiddleware(http.HandlerFunc(s.setBudgetHandler)))
+	router.Handle("DELETE /budget", authLoggingMiddleware(http.HandlerFunc(s.deleteBudgetHandler)))
+
+	router.Handle("GET /stats", authLoggingMiddleware(http.HandlerFunc(s.statsHandler)))
+	router.Handle("POST /beer/{id}/drinks", authLoggingMiddleware(http.HandlerFunc(s.logDrinkHandler)))
+	router.Handle("DELETE /drink/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteDrinkHandler)))
+
+	router.Handle("GET /goals", authLoggingMiddleware(http.HandlerFunc(s.goalsHandler)))
+	router.Handle("PUT /goals", authLoggingMiddleware(http.HandlerFunc(s.setGoalsHandler)))
+	router.Handle("DELETE /goals", authLoggingMiddleware(http.HandlerFunc(s.deleteGoalsHandler)))
+
+	router.Handle("GET /sessions", authLoggingMiddleware(http.HandlerFunc(s.drinkSessionsHandler)))
+	router.Handle("POST /sessions", authLoggingMiddleware(http.HandlerFunc(s.addDrinkSessionHandler)))
+	router.Handle("GET /session/{id}", authLoggingMiddleware(http.HandlerFunc(s.getDrinkSessionHandler)))
+	router.Handle("DELETE /session/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteDrinkSessionHandler)))
+	router.Handle("POST /session/{id}/participants", authLoggingMiddleware(http.HandlerFunc(s.addSessionParticipantHandler)))
+	router.Handle("POST /session/{id}/venues", authLoggingMiddleware(http.HandlerFunc(s.addSessionVenueHandler)))
+	router.Handle("POST /session/{id}/drinks", authLoggingMiddleware(http.HandlerFunc(s.logSessionDrinkHandler)))
+	router.Handle("POST /session/{id}/end", authLoggingMiddleware(http.HandlerFunc(s.endDrinkSessionHandler)))
+	router.Handle("GET /session/{id}/shouts", authLoggingMiddleware(http.HandlerFunc(s.shoutsHandler)))
+	router.Handle("POST /session/{id}/rounds", authLoggingMiddleware(http.HandlerFunc(s.addRoundHandler)))
+	router.Handle("DELETE /round/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteRoundHandler)))
+
+	router.Handle("GET /venues", authLoggingMiddleware(http.HandlerFunc(s.venuesHandler)))
+	router.Handle("POST /venues", authLoggingMiddleware(http.HandlerFunc(s.addVenueHandler)))
+	router.Handle("GET /venues/nearby", authLoggingMiddleware(http.HandlerFunc(s.nearbyVenuesHandler)))
+	router.Handle("GET /venue/{id}", authLoggingMiddleware(http.HandlerFunc(s.getVenueHandler)))
+	router.Handle("POST /venue/{id}/checkins", authLoggingMiddleware(http.HandlerFunc(s.addCheckInHandler)))
+	router.Handle("GET /checkins", authLoggingMiddleware(http.HandlerFunc(s.checkInsHandler)))
+	router.Handle("DELETE /checkin/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteCheckInHandler)))
+
+	router.Handle("GET /feed", authLoggingMiddleware(http.HandlerFunc(s.feedHandler)))
+	router.Handle("GET /people", authLoggingMiddleware(http.HandlerFunc(s.peopleHandler)))
+	router.Handle("GET /people/{id}", authLoggingMiddleware(http.HandlerFunc(s.personHandler)))
+	router.Handle("GET /people/{id}/activity", authLoggingMiddleware(http.HandlerFunc(s.personActivityHandler)))
+	router.Handle("POST /people/{id}/follow", authLoggingMiddleware(http.HandlerFunc(s.followHandler)))
+	router.Handle("DELETE /people/{id}/follow", authLoggingMiddleware(http.HandlerFunc(s.unfollowHandler)))
+	router.Handle("PUT /privacy", authLoggingMiddleware(http.HandlerFunc(s.setPrivacyHandler)))
+
+	router.Handle("GET /beer/{id}/comments", authLoggingMiddleware(http.HandlerFunc(s.beerCommentsHandler)))
+	router.Handle("POST /beer/{id}/comments", authLoggingMiddleware(http.HandlerFunc(s.addBeerCommentHandler)))
+	router.Handle("GET /brewer/{id}/comments", authLoggingMiddleware(http.HandlerFunc(s.brewerCommentsHandler)))
+	router.Handle("POST /brewer/{id}/comments", authLoggingMiddleware(http.HandlerFunc(s.addBrewerCommentHandler)))
+	router.Handle("PUT /comment/{id}", authLoggingMiddleware(http.HandlerFunc(s.updateCommentHandler)))
+	router.Handle("DELETE /comment/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteCommentHandler)))
+	router.Handle("POST /comment/{id}/reactions", authLoggingMiddleware(http.HandlerFunc(s.toggleReactionHandler)))
+
+	router.Handle("GET /tastings", authLoggingMiddleware(http.HandlerFunc(s.tastingsHandler)))
+	router.Handle("POST /tastings", authLoggingMiddleware(http.HandlerFunc(s.addTastingHandler)))
+	router.Handle("GET /tasting/{id}", authLoggingMiddleware(http.HandlerFunc(s.getTastingHandler)))
+	router.Handle("DELETE /tasting/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteTastingHandler)))
+	router.Handle("POST /tasting/{id}/pours", authLoggingMiddleware(http.HandlerFunc(s.addPourHandler)))
+	router.Handle("DELETE /tasting/{id}/pour/{number}", authLoggingMiddleware(http.HandlerFunc(s.removePourHandler)))
+	router.Handle("PUT /tasting/{id}/pour/{number}/scorecard", authLoggingMiddleware(http.HandlerFunc(s.scorePourHandler)))
+	router.Handle("POST /tasting/{id}/reveal", authLoggingMiddleware(http.HandlerFunc(s.revealTastingHandler)))
+
+	router.Handle("GET /badges", authLoggingMiddleware(http.HandlerFunc(s.badgesHandler)))
+	router.Handle("GET /people/{id}/badges", authLoggingMiddleware(http.HandlerFunc(s.personBadgesHandler)))
+	router.Handle("GET /leaderboards", authLoggingMiddleware(http.HandlerFunc(s.leaderboardsHandler)))
+
+	router.Handle("GET /wishlist", authLoggingMiddleware(http.HandlerFunc(s.wishlistHandler)))
+	router.Handle("POST /beer/{id}/wishlist", authLoggingMiddleware(http.HandlerFunc(s.addWishHandler)))
+	router.Handle("PUT /beer/{id}/wishlist", authLoggingMiddleware(http.HandlerFunc(s.updateWishHandler)))
+	router.Handle("DELETE /beer/{id}/wishlist", authLoggingMiddleware(http.HandlerFunc(s.removeWishHandler)))
+
+	router.Handle("GET /schedule", authLoggingMiddleware(http.HandlerFunc(s.scheduleHandler)))
+	router.Handle("POST /schedule", authLoggingMiddleware(http.HandlerFunc(s.addScheduleHandler)))
+	router.Handle("DELETE /schedule/{id}", authLoggingMiddleware(http.HandlerFunc(s.deleteScheduleHandler)))
+	router.Handle("GET /countdown", authLoggingMiddleware(http.HandlerFunc(s.countdownHandler)))
+
+	router.Handle("GET /email", authLoggingMiddleware(http.HandlerFunc(s.emailSettingsHandler)))
+	router.Handle("PUT /email", authLoggingMiddleware(http.HandlerFunc(s.setEmailHandler)))
+	router.Handle("DELETE /email", authLoggingMiddleware(http.HandlerFunc(s.deleteEmailHandler)))
+
+	router.Handle("GET /tags", authLoggingMiddleware(http.HandlerFunc(s.tagCloudHandler)))
+
+	router.Handle("POST /beer/{id}/photos", authLoggingMiddleware(http.HandlerFunc(s.uploadPhotoHandler)))
+	router.Handle("GET /photo/{id}", authLoggingMiddleware(http.HandlerFunc(s.getPhotoHandler)))
+	router.Handle("GET /photo/{id}/thumb", authLoggingMiddleware(http.HandlerFunc(s.getPhotoHandler)))
+	router.Handle("DELETE /photo/{id}", authLoggingMiddleware(http.HandlerFunc(s.deletePhotoHandler)))
+
+	// admin routes:
+	router.Handle("GET /trash", adminLoggingMiddleware(http.HandlerFunc(s.trashHandler)))
+	router.Handle("DELETE /trash/user/{id}", adminLoggingMiddleware(http.HandlerFunc(s.purgeUserHandler)))
+	router.Handle("DELETE /trash/brewer/{id}", adminLoggingMiddleware(http.HandlerFunc(s.purgeBrewerHandler)))
+	router.Handle("DELETE /trash/beer/{id}", adminLoggingMiddleware(http.HandlerFunc(s.purgeBeerHandler)))
+	router.Handle("GET /scorecard/weights", adminLoggingMiddleware(http.HandlerFunc(s.getScorecardWeightsHandler)))
+	router.Handle("PUT /scorecard/weights", adminLoggingMiddleware(http.HandlerFunc(s.updateScorecardWeightsHandler)))
+	router.Handle("GET /outbox", adminLoggingMiddleware(http.HandlerFunc(s.outboxHandler)))
+	router.Handle("GET /webhooks", adminLoggingMiddleware(http.HandlerFunc(s.webhooksHandler)))
+	router.Handle("POST /webhooks", adminLoggingMiddleware(http.HandlerFunc(s.addWebhookHandler)))
+	router.Handle("DELETE /webhook/{id}", adminLoggingMiddleware(http.HandlerFunc(s.deleteWebhookHandler)))
+	router.Handle("GET /webhooks/deliveries", adminLoggingMiddleware(http.HandlerFunc(s.webhookDeliveriesHandler)))
+	router.Handle("DELETE /venue/{id}", adminLoggingMiddleware(http.HandlerFunc(s.deleteVenueHandler)))
+
+	return router
+}
+
+// Start the server
+func (s *server) Start() error {
+	s.logger.Printf("Starting server on port %d", s.port)
+	var stopChan chan os.Signal
 
 	// define server
 	s.httpServer = &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: router,
+		Handler: s.routes(),
 	}
+	// Event streams stay open until the bus is closed, so they'd otherwise hold up shutting down
+	s.httpServer.RegisterOnShutdown(s.events.Close)
 
 	// create channel to listen for signals
 	stopChan = make(chan os.Signal, 1)
 	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 
+	// start background jobs, which run until the server is stopped
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go s.purgeTrashPeriodically(jobsCtx)
+	go s.remindPeriodically(jobsCtx)
+	go s.sendSummariesPeriodically(jobsCtx)
+	go s.sendOutboxPeriodically(jobsCtx)
+	go s.deliverWebhooksPeriodically(jobsCtx)
+
 	go func() {
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error when running server: %s", err)
@@ -141,6 +527,7 @@ func (s *server) Start() error {
 	}()
 
 	<-stopChan
+	stopJobs()
 
 	// Create a context with a timeout of 5 seconds
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -158,6 +545,13 @@ func isHtmxRequest(r *http.Request) bool {
 	return r.Header.Get("HX-Request") == "true"
 }
 
+// A helper function to get the ID of the logged in user, which the auth middleware attaches to the
+// request context
+func currentUserId(r *http.Request) int64 {
+	userId, _ := r.Context().Value("userId").(int64)
+	return userId
+}
+
 // A helper function to respond with a template, either as a full page or just the partial content
 // depending on whether the request was made by HTMX and the HTML verb used (full pages only apply
 // to GET requests) the AppName to the title provided. If the template fails to render, a 500 error
@@ -186,7 +580,13 @@ func renderTemplate(w http.ResponseWriter, r *http.Request, t templ.Component, t
 
 // GET /
 func (s *server) homeHandler(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+	user, err := s.userStore.GetUserById(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
 
 	beers, err := s.beerStore.GetBeers(r.Context())
 	if err != nil {
@@ -195,7 +595,81 @@ func (s *server) homeHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
-	renderTemplate(w, r, templates.Home(beers), "Home")
+
+	tagsByBeer, err := s.tagStore.GetTagsByBeer(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	allTags, err := s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	stockLevels, err := s.stockStore.GetStockLevels(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock levels: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	wished, err := s.getWished(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	budget, err := s.getBudget(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting budget: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	spent, err := s.getMonthSpent(r, budget)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting spending: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	userGoals, err := s.getGoals(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := s.getProgress(r, userGoals, time.Now().In(goals.Location(userGoals, s.location)))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting progress: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	_, next, now, err := s.getSchedules(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting schedule: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	renderTemplate(w, r, templates.Home(user, beers, tagsByBeer, stockLevels, wished, allTags, budget, spent, progress, next, now), "Home")
 }
 
 // GET /login
@@ -257,7 +731,11 @@ func (s *server) addBrewerHandler(w http.ResponseWriter, r *http.Request) {
 			validationErrors[err.Field] = "This field is required"
 			w.WriteHeader(http.StatusUnprocessableEntity)
 		case brewers.ErrBrewerAlreadyExists:
-			validationErrors["name"] = fmt.Sprintf("%s already exists", err.Name)
+			if err.Deleted {
+				validationErrors["name"] = fmt.Sprintf("%s is in the trash, restore it instead", err.Name)
+			} else {
+				validationErrors["name"] = fmt.Sprintf("%s already exists", err.Name)
+			}
 			w.WriteHeader(http.StatusConflict)
 		default:
 			http.Error(w, errMsg, http.StatusInternalServerError)
@@ -266,6 +744,8 @@ func (s *server) addBrewerHandler(w http.ResponseWriter, r *http.Request) {
 		renderTemplate(w, r, templates.AddBrewerForm(formData, validationErrors))
 		return
 	}
+	s.emitWebhook(r.Context(), webhooks.BrewerCreated, brewerWebhookData(brewer))
+	s.recordActivity(r, social.BrewerAdded, sql.NullInt64{}, sql.NullInt64{Valid: true, Int64: brewer.ID})
 
 	renderTemplate(w, r, templates.AddBrewerForm(db.Brewer{}, nil))
 	renderTemplate(w, r, templates.Brewer(brewer))
@@ -286,7 +766,7 @@ func (s *server) deleteBrewerHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
-	_, err = s.brewerStore.DeleteBrewer(r.Context(), int64(id))
+	brewer, err := s.brewerStore.DeleteBrewer(r.Context(), int64(id))
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when deleting brewer: %v", err)
 		s.logger.Print(errMsg)
@@ -294,6 +774,7 @@ func (s *server) deleteBrewerHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, errMsg, http.StatusNoContent)
 		return
 	}
+	s.emitWebhook(r.Context(), webhooks.BrewerDeleted, brewerWebhookData(brewer))
 
 	// Check if that was the last brewer
 	numBrewers, err := s.brewerStore.CountBrewers(r.Context())
@@ -305,12 +786,15 @@ func (s *server) deleteBrewerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if numBrewers == 0 {
-		// If we just deleted the last brewer, render the no brewers template
+		// If we just deleted the last brewer, it's replaced with the no brewers template
 		renderTemplate(w, r, templates.NoBrewers())
-	} else {
-		// Return nothing so the target of the delete request is replaced with nothing, i.e. removed
-		w.WriteHeader(http.StatusNoContent)
+		return
 	}
+
+	// Otherwise the target of the delete request is replaced with nothing, i.e. removed, as the toast
+	// is swapped in out of band, and the deletion can be undone from the toast
+	restoreUrl := fmt.Sprintf("/brewer/%d/restore", brewer.ID)
+	renderTemplate(w, r, templates.UndoToast(fmt.Sprintf("Deleted %s", brewer.Name), restoreUrl))
 }
 
 // GET /brewers
@@ -344,7 +828,7 @@ func (s *server) getBrewerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, templates.Brewer(brewer), brewer.Name)
+	renderTemplate(w, r, templates.BrewerPage(brewer), brewer.Name)
 }
 
 // POST /user
@@ -388,10 +872,28 @@ func (s *server) addUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add the user to the user store
-	s.userStore.AddUser(context.Background(), db.AddUserParams{
+	_, err = s.userStore.AddUser(context.Background(), db.AddUserParams{
 		Username:     formUsername,
 		PasswordHash: string(passwordHash),
 	})
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding user: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case users.ErrUserAlreadyExists:
+			if err.Deleted {
+				validationErrors["username"] = fmt.Sprintf("%s is in the trash, restore it instead", err.Username)
+			} else {
+				validationErrors["username"] = fmt.Sprintf("%s already exists", err.Username)
+			}
+			w.WriteHeader(http.StatusConflict)
+			renderTemplate(w, r, templates.AddUserForm(db.User{Username: formUsername}, validationErrors))
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
 
 	renderTemplate(w, r, templates.AddUserForm(db.User{}, nil))
 }
@@ -411,13 +913,20 @@ func (s *server) deleteUserHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
-	_, err = s.userStore.DeleteUser(r.Context(), int64(id))
+	if int64(id) == currentUserId(r) {
+		errMsg := "You can't delete yourself"
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusForbidden)
+		return
+	}
+	user, err := s.userStore.DeleteUser(r.Context(), int64(id))
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when deleting user: %v", err)
 		s.logger.Print(errMsg)
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
+	s.emitWebhook(r.Context(), webhooks.UserDeleted, userWebhookData(user))
 
 	// Check if that was the last user
 	numUsers, err := s.userStore.CountUsers(r.Context())
@@ -429,12 +938,15 @@ func (s *server) deleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if numUsers == 0 {
-		// If we just deleted the last user, render the no users template
+		// If we just deleted the last user, it's replaced with the no users template
 		renderTemplate(w, r, templates.NoUsers())
-	} else {
-		// Return nothing so the target of the delete request is replaced with nothing, i.e. removed
-		w.WriteHeader(http.StatusNoContent)
+		return
 	}
+
+	// Otherwise the target of the delete request is replaced with nothing, i.e. removed, as the toast
+	// is swapped in out of band, and the deletion can be undone from the toast
+	restoreUrl := fmt.Sprintf("/user/%d/restore", user.ID)
+	renderTemplate(w, r, templates.UndoToast(fmt.Sprintf("Deleted %s", user.Username), restoreUrl))
 }
 
 // GET /users
@@ -471,6 +983,14 @@ func (s *server) getUserHandler(w http.ResponseWriter, r *http.Request) {
 	renderTemplate(w, r, templates.User(user), user.Username)
 }
 
+// The validation message for a beer whose name and brewer are already taken
+func beerExistsMessage(err store.ErrBeerAlreadyExists) string {
+	if err.Deleted {
+		return fmt.Sprintf("%s by brewer %d is in the trash, restore it instead", err.Name, err.BrewerId)
+	}
+	return fmt.Sprintf("%s by brewer %d already exists", err.Name, err.BrewerId)
+}
+
 // POST /beer
 func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 	s.logger.Printf("Adding beer")
@@ -483,8 +1003,8 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 	formName := r.FormValue("name")
 	formStyle := r.FormValue("style")
 	formAbv := r.FormValue("abv")
-	formRating := r.FormValue("rating")
 	formNotes := r.FormValue("notes")
+	formBarcode := r.FormValue("barcode")
 
 	brewers, err := s.brewerStore.GetBrewers(r.Context())
 	if err != nil {
@@ -494,7 +1014,38 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	validationErrors := make(map[string]string)
+	weights, err := s.scorecardStore.GetWeights(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	allTags, err := s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// The scorecard replaces the single rating
+	scores, validationErrors := parseScores(r)
+	tagNames, tagsError := parseTags(r)
+	if tagsError != "" {
+		validationErrors["tags"] = tagsError
+	}
+	barcode, barcodeError, err := s.checkNewBarcode(r.Context(), formBarcode)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when checking barcode: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if barcodeError != "" {
+		validationErrors["barcode"] = barcodeError
+	}
 	if formName == "" {
 		validationErrors["name"] = "Name is required"
 	}
@@ -503,7 +1054,7 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if len(validationErrors) > 0 {
 		w.WriteHeader(http.StatusUnprocessableEntity)
-		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, brewers, validationErrors, false))
+		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, scores, weights, tagNames, allTags, brewers, formBarcode, validationErrors, false))
 		return
 	}
 
@@ -520,14 +1071,6 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 		maybeBrewerID = sql.NullInt64{Valid: true, Int64: int64(brewerID)}
 	}
 
-	rating, err := strconv.ParseFloat(formRating, 64)
-	if err != nil {
-		errMsg := fmt.Sprintf("Error when converting rating to float: %v", err)
-		s.logger.Print(errMsg)
-		http.Error(w, errMsg, http.StatusInternalServerError)
-		return
-	}
-
 	abv, err := strconv.ParseFloat(formAbv, 64)
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when converting ABV to float: %v", err)
@@ -536,12 +1079,12 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	beer, err := s.beerStore.AddBeer(r.Context(), db.AddBeerParams{
+	beer, err := s.beerStore.AddBeer(r.Context(), currentUserId(r), db.AddBeerParams{
 		BrewerID: maybeBrewerID,
 		Name:     formName,
 		Style:    sql.NullString{Valid: true, String: formStyle},
 		Abv:      abv,
-		Rating:   sql.NullFloat64{Valid: true, Float64: rating},
+		Rating:   sql.NullFloat64{Valid: true, Float64: scores.Total(weights)},
 		Notes:    sql.NullString{Valid: true, String: formNotes},
 	})
 	if err != nil {
@@ -558,17 +1101,53 @@ func (s *server) addBeerHandler(w http.ResponseWriter, r *http.Request) {
 			validationErrors["brewer-id"] = fmt.Sprintf("Brewer with id %d not found", err.ID)
 			w.WriteHeader(http.StatusNotFound)
 		case store.ErrBeerAlreadyExists:
-			validationErrors["name"] = fmt.Sprintf("%s by brewer %d already exists", err.Name, err.BrewerId)
+			validationErrors["name"] = beerExistsMessage(err)
 		default:
 			http.Error(w, errMsg, http.StatusInternalServerError)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
-		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, brewers, validationErrors, false))
+		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, scores, weights, tagNames, allTags, brewers, formBarcode, validationErrors, false))
+		return
+	}
+	s.emitWebhook(r.Context(), webhooks.BeerCreated, beerWebhookData(beer))
+	s.recordActivity(r, social.BeerAdded, sql.NullInt64{Valid: true, Int64: beer.ID}, sql.NullInt64{})
+
+	if _, err := s.scorecardStore.SaveScorecard(r.Context(), beer.ID, currentUserId(r), scores); err != nil {
+		errMsg := fmt.Sprintf("Error when saving scorecard: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerTags, err := s.tagStore.SetBeerTags(r.Context(), beer.ID, tagNames)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
 
-	renderTemplate(w, r, templates.AddBeerForm(db.Beer{}, brewers, nil, false))
-	renderTemplate(w, r, templates.Beer(beer))
+	if barcode != "" {
+		if _, err := s.barcodeStore.AddBarcode(r.Context(), barcode, beer.ID); err != nil {
+			errMsg := fmt.Sprintf("Error when adding barcode: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Any new tags are now options in the form
+	allTags, err = s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.AddBeerForm(db.Beer{}, scorecards.Scores{}, weights, nil, allTags, brewers, "", nil, false))
+	// A new beer can't be on anyone's wishlist yet
+	renderTemplate(w, r, templates.Beer(beer, beerTags, 0, false))
 }
 
 // PUT /beer/{id}
@@ -587,7 +1166,6 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 	formName := r.FormValue("name")
 	formStyle := r.FormValue("style")
 	formAbv := r.FormValue("abv")
-	formRating := r.FormValue("rating")
 	formNotes := r.FormValue("notes")
 
 	brewers, err := s.brewerStore.GetBrewers(r.Context())
@@ -598,7 +1176,36 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	weights, err := s.scorecardStore.GetWeights(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	allTags, err := s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// The scorecard is only saved when one was sent, so the beer's details can be edited by
+	// someone who hasn't scored it
+	scored := slices.ContainsFunc(scorecards.Dimensions[:], func(dimension scorecards.Dimension) bool {
+		return r.Form.Has(dimension.Name)
+	})
+	scores := scorecards.Scores{}
 	validationErrors := make(map[string]string)
+	if scored {
+		scores, validationErrors = parseScores(r)
+	}
+	tagNames, tagsError := parseTags(r)
+	if tagsError != "" {
+		validationErrors["tags"] = tagsError
+	}
 	if formName == "" {
 		validationErrors["name"] = "Name is required"
 	}
@@ -607,7 +1214,7 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if len(validationErrors) > 0 {
 		w.WriteHeader(http.StatusUnprocessableEntity)
-		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, brewers, validationErrors, false))
+		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, scores, weights, tagNames, allTags, brewers, "", validationErrors, false))
 		return
 	}
 
@@ -624,14 +1231,6 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 		maybeBrewerID = sql.NullInt64{Valid: true, Int64: int64(brewerID)}
 	}
 
-	rating, err := strconv.ParseFloat(formRating, 64)
-	if err != nil {
-		errMsg := fmt.Sprintf("Error when converting rating to float: %v", err)
-		s.logger.Print(errMsg)
-		http.Error(w, errMsg, http.StatusInternalServerError)
-		return
-	}
-
 	abv, err := strconv.ParseFloat(formAbv, 64)
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when converting ABV to float: %v", err)
@@ -640,14 +1239,41 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	beer, err := s.beerStore.UpdateBeer(r.Context(), db.UpdateBeerParams{
-		ID:       int64(id),
-		BrewerID: maybeBrewerID,
-		Name:     sql.NullString{Valid: true, String: formName},
-		Style:    sql.NullString{Valid: true, String: formStyle},
-		Abv:      sql.NullFloat64{Valid: true, Float64: abv},
-		Rating:   sql.NullFloat64{Valid: true, Float64: rating},
-		Notes:    sql.NullString{Valid: true, String: formNotes},
+	// The beer, the user's scorecard and their wishlist all change together or not at all
+	var beer db.Beer
+	err = db.InTx(r.Context(), s.queries, func(ctx context.Context, _ db.Querier) error {
+		var err error
+		beer, err = s.beerStore.UpdateBeer(ctx, currentUserId(r), db.UpdateBeerParams{
+			ID:       int64(id),
+			BrewerID: maybeBrewerID,
+			Name:     sql.NullString{Valid: true, String: formName},
+			Style:    sql.NullString{Valid: true, String: formStyle},
+			Abv:      sql.NullFloat64{Valid: true, Float64: abv},
+			Notes:    sql.NullString{Valid: true, String: formNotes},
+		})
+		if err != nil || !scored {
+			return err
+		}
+
+		if _, err := s.scorecardStore.SaveScorecard(ctx, beer.ID, currentUserId(r), scores); err != nil {
+			return err
+		}
+		if err := s.wishlistStore.RemoveWish(ctx, currentUserId(r), beer.ID); err != nil {
+			if _, ok := err.(wishlist.ErrWishNotFound); !ok {
+				return err
+			}
+		}
+
+		// The beer's rating is the average of everyone's scorecards
+		summary, err := s.scorecardStore.GetBeerSummary(ctx, beer.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.beerStore.SetBeerRatings(ctx, map[int64]float64{beer.ID: summary.Total}); err != nil {
+			return err
+		}
+		beer.Rating = sql.NullFloat64{Valid: true, Float64: summary.Total}
+		return nil
 	})
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when updating beer: %v", err)
@@ -656,6 +1282,9 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 		validationErrors := make(map[string]string)
 
 		switch err := err.(type) {
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+			return
 		case store.ErrMissingField:
 			validationErrors[err.Field] = "This field is required"
 			w.WriteHeader(http.StatusUnprocessableEntity)
@@ -663,17 +1292,47 @@ func (s *server) updateBeerHandler(w http.ResponseWriter, r *http.Request) {
 			validationErrors["brewer-id"] = fmt.Sprintf("Brewer with id %d not found", err.ID)
 			w.WriteHeader(http.StatusNotFound)
 		case store.ErrBeerAlreadyExists:
-			validationErrors["name"] = fmt.Sprintf("%s by brewer %d already exists", err.Name, err.BrewerId)
+			validationErrors["name"] = beerExistsMessage(err)
 			w.WriteHeader(http.StatusNotFound)
 		default:
 			http.Error(w, errMsg, http.StatusInternalServerError)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
-		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, brewers, validationErrors, true))
+		renderTemplate(w, r, templates.AddBeerForm(db.Beer{Name: formName}, scores, weights, tagNames, allTags, brewers, "", validationErrors, true))
+		return
+	}
+	s.emitWebhook(r.Context(), webhooks.BeerUpdated, beerWebhookData(beer))
+	s.recordActivity(r, social.BeerEdited, sql.NullInt64{Valid: true, Int64: beer.ID}, sql.NullInt64{})
+
+	beerTags, err := s.tagStore.SetBeerTags(r.Context(), beer.ID, tagNames)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
 
-	renderTemplate(w, r, templates.Beer(beer))
+	quantity, err := s.getStockLevel(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock level: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Saving a scorecard took the beer off the user's wishlist
+	wished := false
+	if !scored {
+		wishedBeers, err := s.getWished(r.Context(), currentUserId(r))
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		wished = wishedBeers[beer.ID]
+	}
+	renderTemplate(w, r, templates.Beer(beer, beerTags, quantity, wished))
 }
 
 // GET /beer/add or GET /beer/{id}/edit
@@ -686,6 +1345,22 @@ func (s *server) getBeerFormHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	weights, err := s.scorecardStore.GetWeights(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting scorecard weights: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	allTags, err := s.tagStore.GetTags(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
 	if strings.Contains(r.URL.Path, "/edit") && r.PathValue("id") != "" {
 		id, err := strconv.Atoi(r.PathValue("id"))
 		if err != nil {
@@ -703,11 +1378,40 @@ func (s *server) getBeerFormHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		renderTemplate(w, r, templates.AddBeerForm(beer, brewers, nil, true), "Edit Beer")
+		// Start from the user's own scorecard, or the beer's rating if they haven't scored it yet
+		scores := scorecards.Scores{}
+		scorecard, err := s.scorecardStore.GetScorecard(r.Context(), beer.ID, currentUserId(r))
+		switch err.(type) {
+		case nil:
+			scores = scorecards.ScoresOf(scorecard)
+		case scorecards.ErrScorecardNotFound:
+			for i := range scores {
+				scores[i] = beer.Rating.Float64
+			}
+		default:
+			errMsg := fmt.Sprintf("Error when getting scorecard: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+
+		beerTags, err := s.tagStore.GetBeerTags(r.Context(), beer.ID)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		tagNames := []string{}
+		for _, tag := range beerTags {
+			tagNames = append(tagNames, tag.Name)
+		}
+
+		renderTemplate(w, r, templates.AddBeerForm(beer, scores, weights, tagNames, allTags, brewers, "", nil, true), "Edit Beer")
 		return
 	}
 
-	renderTemplate(w, r, templates.AddBeerForm(db.Beer{}, brewers, nil, false), "Add Beer")
+	renderTemplate(w, r, templates.AddBeerForm(db.Beer{}, scorecards.Scores{}, weights, nil, allTags, brewers, "", nil, false), "Add Beer")
 }
 
 // DELETE /beer/{id}
@@ -720,13 +1424,14 @@ func (s *server) deleteBeerHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
-	_, err = s.beerStore.DeleteBeer(r.Context(), int64(id))
+	beer, err := s.beerStore.DeleteBeer(r.Context(), int64(id))
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when deleting beer: %v", err)
 		s.logger.Print(errMsg)
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
+	s.emitWebhook(r.Context(), webhooks.BeerDeleted, beerWebhookData(beer))
 
 	// Check if that was the last beer
 	numBeers, err := s.beerStore.CountBeers(r.Context())
@@ -738,17 +1443,28 @@ func (s *server) deleteBeerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if numBeers == 0 {
-		// If we just deleted the last beer, render the no beers template
+		// If we just deleted the last beer, it's replaced with the no beers template
 		renderTemplate(w, r, templates.NoBeers())
-	} else {
-		// Return nothing so the target of the delete request is replaced with nothing, i.e. removed
-		w.WriteHeader(http.StatusNoContent)
+		return
 	}
+
+	// Otherwise the target of the delete request is replaced with nothing, i.e. removed, as the toast
+	// is swapped in out of band, and the deletion can be undone from the toast
+	restoreUrl := fmt.Sprintf("/beer/%d/restore", beer.ID)
+	renderTemplate(w, r, templates.UndoToast(fmt.Sprintf("Deleted %s", beer.Name), restoreUrl))
 }
 
-// GET /beers
+// GET /beers or GET /beers?tag={tag}
 func (s *server) listBeersHandler(w http.ResponseWriter, r *http.Request) {
-	beers, err := s.beerStore.GetBeers(r.Context())
+	var beers []db.Beer
+	var err error
+	title := "Beers"
+	if tag := r.FormValue("tag"); tag != "" {
+		beers, err = s.tagStore.GetBeersByTag(r.Context(), tag)
+		title = fmt.Sprintf("Beers tagged %s", tags.Normalize(tag))
+	} else {
+		beers, err = s.beerStore.GetBeers(r.Context())
+	}
 	if err != nil {
 		errMsg := fmt.Sprintf("Error when getting beers: %v", err)
 		s.logger.Print(errMsg)
@@ -756,15 +1472,50 @@ func (s *server) listBeersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, templates.BeersList(beers), "Beers")
+	tagsByBeer, err := s.tagStore.GetTagsByBeer(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	stockLevels, err := s.stockStore.GetStockLevels(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock levels: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	wished, err := s.getWished(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Searches swap just the list, which is already being kept up to date
+	if r.Header.Get("HX-Target") == "beers-list" {
+		renderTemplate(w, r, templates.BeersList(beers, tagsByBeer, stockLevels, wished), title)
+		return
+	}
+	renderTemplate(w, r, templates.LiveBeersList(beers, tagsByBeer, stockLevels, wished), title)
 }
 
 // POST /beer/search
 func (s *server) searchBeersHandler(w http.ResponseWriter, r *http.Request) {
-	// If the query is empty, just list all beers
 	query := r.FormValue("q")
+	tag := r.FormValue("tag")
+
+	// If the query is empty, just list all beers, or all beers with the tag
 	if query == "" {
-		http.Redirect(w, r, "/beers", http.StatusSeeOther)
+		redirectUrl := "/beers"
+		if tag != "" {
+			redirectUrl = fmt.Sprintf("/beers?tag=%s", url.QueryEscape(tag))
+		}
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
 		return
 	}
 
@@ -776,7 +1527,44 @@ func (s *server) searchBeersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, templates.BeersList(beers), "Beers")
+	if tag != "" {
+		tagged, err := s.tagStore.GetBeersByTag(r.Context(), tag)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting beers by tag: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		beers = slices.DeleteFunc(beers, func(beer db.Beer) bool {
+			return !slices.ContainsFunc(tagged, func(t db.Beer) bool { return t.ID == beer.ID })
+		})
+	}
+
+	tagsByBeer, err := s.tagStore.GetTagsByBeer(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	stockLevels, err := s.stockStore.GetStockLevels(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock levels: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	wished, err := s.getWished(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeersList(beers, tagsByBeer, stockLevels, wished), "Beers")
 }
 
 // GET /beer/{id}
@@ -797,7 +1585,139 @@ func (s *server) getBeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, templates.Beer(beer), beer.Name)
+	summary, err := s.scorecardStore.GetBeerSummary(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer scores: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerScorecards, err := s.scorecardStore.GetBeerScorecards(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer scorecards: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerTags, err := s.tagStore.GetBeerTags(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerPhotos, err := s.photoStore.GetBeerPhotos(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer photos: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beerBarcodes, err := s.barcodeStore.GetBeerBarcodes(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer barcodes: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	quantity, err := s.getStockLevel(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock level: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	wished, err := s.getWished(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeerPage(beer, beerTags, quantity, wished[beer.ID], beerPhotos, beerBarcodes, summary, beerScorecards), beer.Name)
+}
+
+// GET /beer/{id}/history
+func (s *server) getBeerHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beer, err := s.beerStore.GetBeer(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	revisions, err := s.beerStore.GetBeerHistory(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer history: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeerHistory(beer, revisions), fmt.Sprintf("History of %s", beer.Name))
+}
+
+// POST /beer/{id}/history/{revisionId}/revert
+func (s *server) revertBeerHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	revisionId, err := strconv.Atoi(r.PathValue("revisionId"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting revision id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Printf("Reverting beer with id %d to revision %d", id, revisionId)
+
+	beer, err := s.beerStore.RevertBeer(r.Context(), currentUserId(r), int64(id), int64(revisionId))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when reverting beer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case beers.ErrBeerNotFound, beers.ErrBeerRevisionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		case store.ErrBrewerNotFound, store.ErrBeerAlreadyExists:
+			http.Error(w, errMsg, http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+	s.emitWebhook(r.Context(), webhooks.BeerUpdated, beerWebhookData(beer))
+	s.recordActivity(r, social.BeerEdited, sql.NullInt64{Valid: true, Int64: beer.ID}, sql.NullInt64{})
+
+	revisions, err := s.beerStore.GetBeerHistory(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer history: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.BeerHistory(beer, revisions))
 }
 
 // POST /login
@@ -0,0 +1,2358 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/ean"
+	"beer_oclock/internal/events"
+	"beer_oclock/internal/mail"
+	"beer_oclock/internal/mail/mailtest"
+	"beer_oclock/internal/notify"
+	"beer_oclock/internal/store/badges"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/blobs"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/outbox"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/storetest"
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/store/tags"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/templates"
+	"beer_oclock/internal/webhook"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A test server with an admin (saltytaro) and a regular user (guest), both with the password
+// "password", and the styles seeded
+func newTestServer(t *testing.T, stores storetest.Stores) *httptest.Server {
+	t.Helper()
+	return newTestServerWithBlobs(t, stores, blobs.NewMemoryBlobStore())
+}
+
+// A test server which keeps photos in the given blob store, so tests can check what's kept there
+func newTestServerWithBlobs(t *testing.T, stores storetest.Stores, blobStore blobs.Store) *httptest.Server {
+	t.Helper()
+	_, ts := newTestApp(t, stores, blobStore, notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+	return ts
+}
+
+// A test server along with the server behind it, so tests can run its background jobs, which
+// sends notifications through the notifier. Emails are only logged unless the test sets s.mailer.
+func newTestApp(t *testing.T, stores storetest.Stores, blobStore blobs.Store, notifier notify.Notifier) (*server, *httptest.Server) {
+	t.Helper()
+	t.Setenv("SESSION_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range []db.AddUserParams{
+		{Username: "saltytaro", PasswordHash: string(passwordHash), IsAdmin: true},
+		{Username: "guest", PasswordHash: string(passwordHash)},
+	} {
+		if _, err := stores.Users.AddUser(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := styles.Seed(context.Background(), stores.Styles); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tags.Seed(context.Background(), stores.Tags); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	bus := events.NewBus()
+	s, err := NewServer(logger, 0, Stores{
+		Users:         stores.Users,
+		Brewers:       brewers.NewPublishingBrewerStore(stores.Brewers, bus),
+		Beers:         beers.NewPublishingBeerStore(stores.Beers, bus),
+		Styles:        stores.Styles,
+		Scorecards:    stores.Scorecards,
+		Tags:          stores.Tags,
+		Photos:        stores.Photos,
+		Barcodes:      stores.Barcodes,
+		Stock:         stores.Stock,
+		Budgets:       stores.Budgets,
+		Drinks:        stores.Drinks,
+		Goals:         stores.Goals,
+		Schedules:     stores.Schedules,
+		Emails:        stores.Emails,
+		Resets:        stores.Resets,
+		Outbox:        stores.Outbox,
+		Webhooks:      stores.Webhooks,
+		DrinkSessions: stores.DrinkSessions,
+		Venues:        stores.Venues,
+		Shouts:        stores.Shouts,
+		Social:        stores.Social,
+		Comments:      stores.Comments,
+		Tastings:      stores.Tastings,
+		Badges:        stores.Badges,
+		Wishlist:      stores.Wishlist,
+		Blobs:         blobStore,
+		Lookup:        ean.DefaultFixtureLookup(),
+		Notifier:      notifier,
+		Mailer:        mail.NewLogSender(logger),
+		Events:        bus,
+		Queries:       stores.Queries,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(s.routes())
+	t.Cleanup(ts.Close)
+	// Closing the server waits for event streams, which end once the bus is closed
+	t.Cleanup(bus.Close)
+	return s, ts
+}
+
+// A client which keeps its session cookie and doesn't follow redirects, so tests can check them
+type testClient struct {
+	t      *testing.T
+	server *httptest.Server
+	client *http.Client
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *testClient {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testClient{
+		t:      t,
+		server: server,
+		client: &http.Client{
+			Jar: jar,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Makes a request, as HTMX would if htmx is set, returning the response with its body read
+func (c *testClient) do(method string, path string, form url.Values, htmx bool) (*http.Response, string) {
+	c.t.Helper()
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return res, string(b)
+}
+
+// Uploads a file as a multipart form, as the browser would
+func (c *testClient) upload(path string, field string, filename string, contents []byte) (*http.Response, string) {
+	c.t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile(field, filename)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	fw.Write(contents)
+	mw.Close()
+
+	req, err := http.NewRequest(http.MethodPost, c.server.URL+path, &body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("HX-Request", "true")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return res, string(b)
+}
+
+func (c *testClient) login(username string, password string) *http.Response {
+	c.t.Helper()
+	res, _ := c.do(http.MethodPost, "/login", url.Values{"username": {username}, "password": {password}}, true)
+	return res
+}
+
+// A client which is already logged in as the given user
+func loggedIn(t *testing.T, server *httptest.Server, username string) *testClient {
+	t.Helper()
+	c := newTestClient(t, server)
+	if res := c.login(username, "password"); res.StatusCode != http.StatusSeeOther {
+		t.Fatalf("logging in as %s: got status %d", username, res.StatusCode)
+	}
+	return c
+}
+
+func expectStatus(t *testing.T, res *http.Response, want int) {
+	t.Helper()
+	if res.StatusCode != want {
+		t.Errorf("%s %s: got status %d, want %d", res.Request.Method, res.Request.URL.Path, res.StatusCode, want)
+	}
+}
+
+func expectBody(t *testing.T, body string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected body to contain %q, got:\n%s", w, body)
+		}
+	}
+}
+
+func expectNotBody(t *testing.T, body string, unwanted ...string) {
+	t.Helper()
+	for _, u := range unwanted {
+		if strings.Contains(body, u) {
+			t.Errorf("expected body not to contain %q, got:\n%s", u, body)
+		}
+	}
+}
+
+func TestLogin(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := newTestClient(t, ts)
+
+		res, _ := c.do(http.MethodGet, "/", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+		if loc := res.Header.Get("Location"); loc != "/login" {
+			t.Errorf("redirect when logged out: got %q, want /login", loc)
+		}
+
+		res, body := c.do(http.MethodGet, "/login", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/login"`)
+
+		res, body = c.do(http.MethodPost, "/login", url.Values{"username": {"saltytaro"}, "password": {"wrong"}}, true)
+		expectStatus(t, res, http.StatusUnauthorized)
+		expectBody(t, body, "Username or password is incorrect")
+
+		res, body = c.do(http.MethodPost, "/login", url.Values{"username": {"nobody"}, "password": {"password"}}, true)
+		expectStatus(t, res, http.StatusUnauthorized)
+		expectBody(t, body, "Username or password is incorrect")
+
+		res, body = c.do(http.MethodPost, "/login", url.Values{"username": {""}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Username is required", "Password is required")
+
+		// Usernames are case-insensitive
+		res = c.login("SaltyTaro", "password")
+		expectStatus(t, res, http.StatusSeeOther)
+
+		res, body = c.do(http.MethodGet, "/", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Welcome to Beer O'Clock", "View Trash")
+
+		res, _ = c.do(http.MethodGet, "/login", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+
+		res, _ = c.do(http.MethodGet, "/logout", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+		res, _ = c.do(http.MethodGet, "/", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+	})
+}
+
+func TestRenderTemplate(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		// A GET from the browser gets a full page with the title
+		res, body := c.do(http.MethodGet, "/beers", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "<head>", "<title>Beers ~ Beer O&#39;clock</title>", `<div id="toasts"`, `id="beers-list"`)
+
+		// The home page has the default title
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, "<title>Home ~ Beer O&#39;clock</title>")
+
+		// HTMX only gets the partial content
+		res, body = c.do(http.MethodGet, "/beers", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beers-list"`)
+		expectNotBody(t, body, "<head>", "<title>")
+
+		// Anything other than a GET only gets the partial content, even from the browser
+		res, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {"pale"}}, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beers-list"`)
+		expectNotBody(t, body, "<head>")
+	})
+}
+
+func TestUsers(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+
+		res, body := c.do(http.MethodGet, "/user/add", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/user"`)
+
+		res, body = c.do(http.MethodPost, "/user", url.Values{"username": {"newbie"}, "password": {"a"}, "confirm-password": {"b"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Passwords do not match")
+
+		res, _ = c.do(http.MethodPost, "/user", url.Values{"username": {"newbie"}, "password": {"pw"}, "confirm-password": {"pw"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		res, body = c.do(http.MethodPost, "/user", url.Values{"username": {"Newbie"}, "password": {"pw"}, "confirm-password": {"pw"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "newbie already exists")
+
+		_, body = c.do(http.MethodGet, "/users", nil, true)
+		expectBody(t, body, "saltytaro", "guest", "newbie")
+
+		// The new user can log in
+		newbie := newTestClient(t, ts)
+		expectStatus(t, newbie.login("newbie", "pw"), http.StatusSeeOther)
+
+		res, body = c.do(http.MethodGet, "/user/3", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "newbie")
+
+		res, body = c.do(http.MethodDelete, "/user/3", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Deleted newbie", `hx-post="/user/3/restore"`)
+
+		// Users can't delete themselves
+		res, body = c.do(http.MethodDelete, "/user/1", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		expectBody(t, body, "You can't delete yourself")
+		_, body = c.do(http.MethodGet, "/users", nil, true)
+		expectBody(t, body, "saltytaro")
+
+		// Deleted users can't log in, and their sessions stop working
+		expectStatus(t, newTestClient(t, ts).login("newbie", "pw"), http.StatusUnauthorized)
+		res, _ = newbie.do(http.MethodGet, "/", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+
+		_, body = c.do(http.MethodGet, "/users", nil, true)
+		expectNotBody(t, body, "newbie")
+
+		// The username stays taken while the user is in the trash
+		res, body = c.do(http.MethodPost, "/user", url.Values{"username": {"newbie"}, "password": {"pw"}, "confirm-password": {"pw"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "newbie is in the trash, restore it instead")
+
+		res, body = c.do(http.MethodPost, "/user/3/restore", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="users-list" hx-swap-oob="beforeend"`, "newbie")
+
+		_, body = c.do(http.MethodGet, "/users", nil, true)
+		expectBody(t, body, "newbie")
+	})
+}
+
+func TestBrewers(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		res, body := c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Location is required")
+
+		res, body = c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="brewer-1"`, "Felon&#39;s", "Brisbane")
+
+		res, body = c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Sydney"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "Felon&#39;s already exists")
+
+		res, body = c.do(http.MethodGet, "/brewer/1", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "<title>Felon&#39;s ~ Beer O&#39;clock</title>")
+
+		res, body = c.do(http.MethodDelete, "/brewer/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		// The last brewer's replaced with the no brewers message, without an undo toast to go with it
+		expectBody(t, body, `id="no-brewers"`)
+		expectNotBody(t, body, "Deleted Felon&#39;s")
+
+		_, body = c.do(http.MethodGet, "/brewers", nil, true)
+		expectBody(t, body, "No brewers found")
+
+		res, body = c.do(http.MethodPost, "/brewer/1/restore", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="brewers-list" hx-swap-oob="beforeend"`, `id="no-brewers" hx-swap-oob="delete"`)
+
+		res, _ = c.do(http.MethodPost, "/brewer/1/restore", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		_, body = c.do(http.MethodGet, "/brewers", nil, true)
+		expectBody(t, body, "Felon&#39;s")
+	})
+}
+
+// Gives every part of the beer form's scorecard the same score
+func setScores(form url.Values, score string) url.Values {
+	for _, dimension := range scorecards.Dimensions {
+		form.Set(dimension.Name, score)
+	}
+	return form
+}
+
+func TestBeers(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+
+		res, body := c.do(http.MethodGet, "/beer/add", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer"`, "Felon&#39;s")
+
+		res, body = c.do(http.MethodPost, "/beer", url.Values{"name": {""}, "abv": {""}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Name is required", "ABV is required")
+
+		beer := url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "style": {"American Pale Ale"}, "abv": {"5.5"}, "notes": {"Citrus"}}
+		setScores(beer, "7.5")
+		res, body = c.do(http.MethodPost, "/beer", beer, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beer-1"`, "Pale", "ABV: 5.50% | Rating: 7.50")
+
+		res, body = c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"99"}, "name": {"Ghost"}, "abv": {"4"}}, "5"), true)
+		expectStatus(t, res, http.StatusNotFound)
+		expectBody(t, body, "Brewer with id 99 not found")
+
+		res, body = c.do(http.MethodGet, "/beer/1", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "<title>Pale ~ Beer O&#39;clock</title>")
+
+		res, body = c.do(http.MethodGet, "/beer/1/edit", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-put="/beer/1"`, `value="Pale"`)
+
+		beer.Set("name", "Pale Ale")
+		beer.Set("abv", "5.8")
+		res, body = c.do(http.MethodPut, "/beer/1", beer, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Pale Ale", "ABV: 5.80%")
+
+		res, body = c.do(http.MethodGet, "/beer/1/history", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Revision 2", "guest", "5.50%", "5.80%")
+
+		// The search is case-insensitive and covers the style and notes
+		for _, q := range []string{"pale", "AMERICAN", "citrus"} {
+			res, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {q}}, true)
+			expectStatus(t, res, http.StatusOK)
+			expectBody(t, body, `id="beer-1"`)
+		}
+		_, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {"lager"}}, true)
+		expectBody(t, body, "No beers found")
+
+		// An empty search lists everything
+		res, _ = c.do(http.MethodPost, "/beer/search", url.Values{"q": {""}}, true)
+		expectStatus(t, res, http.StatusSeeOther)
+		if loc := res.Header.Get("Location"); loc != "/beers" {
+			t.Errorf("redirect for an empty search: got %q, want /beers", loc)
+		}
+
+		res, body = c.do(http.MethodPost, "/beer/1/history/1/revert", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "History of Pale<", "Revision 3")
+
+		res, body = c.do(http.MethodDelete, "/beer/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="no-beers"`)
+		expectNotBody(t, body, "Deleted Pale")
+
+		_, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {"pale"}}, true)
+		expectBody(t, body, "No beers found")
+
+		res, body = c.do(http.MethodPost, "/beer/1/restore", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beers-list" hx-swap-oob="beforeend"`, `id="beer-1"`)
+
+		_, body = c.do(http.MethodGet, "/beers", nil, true)
+		expectBody(t, body, `id="beer-1"`)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		admin := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		guest.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		guest.do(http.MethodDelete, "/brewer/1", nil, true)
+
+		// The name stays taken while the brewer is in the trash
+		res, body := guest.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Sydney"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "Felon&#39;s is in the trash, restore it instead")
+
+		res, _ = guest.do(http.MethodGet, "/trash", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, _ = guest.do(http.MethodDelete, "/trash/brewer/1", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+
+		res, body = admin.do(http.MethodGet, "/trash", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Felon&#39;s", `hx-delete="/trash/brewer/1"`, "No deleted beers")
+
+		res, _ = admin.do(http.MethodDelete, "/trash/brewer/1", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+
+		_, body = admin.do(http.MethodGet, "/trash", nil, true)
+		expectBody(t, body, "No deleted brewers")
+	})
+}
+
+func TestStyles(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		// The beer form has the style picker
+		_, body := c.do(http.MethodGet, "/beer/add", nil, true)
+		expectBody(t, body, `list="style-options-0"`, `hx-get="/styles/search"`, `hx-get="/styles/check"`)
+
+		res, body := c.do(http.MethodGet, "/styles/search?style=stout", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `<option value="Irish Stout">`, `<option value="Oatmeal Stout">`)
+		expectNotBody(t, body, "American IPA")
+
+		_, body = c.do(http.MethodGet, "/styles/search?style=", nil, true)
+		expectNotBody(t, body, "<option")
+
+		// Within the style's range there's only the description
+		_, body = c.do(http.MethodGet, "/styles/check?style=american+ipa&abv=6.5", nil, true)
+		expectBody(t, body, "21A American IPA", "5.5–7.5% ABV")
+		expectNotBody(t, body, "style-warning")
+
+		_, body = c.do(http.MethodGet, "/styles/check?style=American+IPA&abv=9", nil, true)
+		expectBody(t, body, "style-warning", "9.00% ABV is outside the usual range for American IPA (5.5–7.5%)")
+
+		_, body = c.do(http.MethodGet, "/styles/check?style=Hefeweisen&abv=5", nil, true)
+		expectBody(t, body, "Not a listed style", "Did you mean Weissbier?")
+
+		_, body = c.do(http.MethodGet, "/styles/check?style=Pastry+Sour&abv=5", nil, true)
+		expectBody(t, body, "Not a listed style")
+		expectNotBody(t, body, "Did you mean")
+	})
+}
+
+func TestScorecards(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		admin := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		// The form has a slider for each part, weighted by the BJCP points
+		_, body := guest.do(http.MethodGet, "/beer/add", nil, true)
+		expectBody(t, body, `name="aroma"`, `data-weight="12"`, `name="overall"`, "(40% of the total)", "scorecard-total")
+		expectNotBody(t, body, `name="rating"`)
+
+		res, body := guest.do(http.MethodPost, "/beer", url.Values{"name": {"Pale"}, "abv": {"5"}, "aroma": {"11"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Aroma must be between 0 and 10", "Flavour is required")
+
+		// (12*10 + 3*0 + 20*5 + 5*5 + 10*5) / 50
+		form := url.Values{"name": {"Pale"}, "abv": {"5"}, "aroma": {"10"}, "appearance": {"0"}, "flavour": {"5"}, "mouthfeel": {"5"}, "overall": {"5"}}
+		res, body = guest.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Rating: 5.90")
+
+		// Editing starts from the user's scorecard
+		_, body = guest.do(http.MethodGet, "/beer/1/edit", nil, true)
+		expectBody(t, body, `name="aroma" class="slider w-full focus:ring-orange-600" step="0.25" min="0" max="10" value="10.00"`)
+
+		// Only admins can change the weights
+		res, _ = guest.do(http.MethodGet, "/scorecard/weights", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+
+		res, body = admin.do(http.MethodGet, "/scorecard/weights", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-put="/scorecard/weights"`, `value="20"`)
+
+		res, body = admin.do(http.MethodPut, "/scorecard/weights", url.Values{"aroma": {"0"}, "appearance": {"0"}, "flavour": {"0"}, "mouthfeel": {"0"}, "overall": {"0"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "at least one must be more than 0")
+
+		res, body = admin.do(http.MethodPut, "/scorecard/weights", setScores(url.Values{}, "1"), true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Weights saved")
+
+		// The scorecards already saved are weighted again, and so is the beer's rating:
+		// (10 + 0 + 5 + 5 + 5) / 5
+		_, body = guest.do(http.MethodGet, "/beer/1", nil, true)
+		expectBody(t, body, "Rating: 5.00")
+		expectNotBody(t, body, "5.90")
+
+		// The beer's rating becomes the average of everyone's totals
+		form = setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "8")
+		form.Set("aroma", "3")
+		res, body = admin.do(http.MethodPut, "/beer/1", form, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Rating: 6.00")
+
+		res, body = guest.do(http.MethodGet, "/beer/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Average of 2", "6.50", "6.00", "saltytaro", "7.00", "guest", "5.00")
+	})
+}
+
+func TestTags(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		// The form offers the curated vocabulary grouped by category
+		_, body := c.do(http.MethodGet, "/beer/add", nil, true)
+		expectBody(t, body, "Fruity", `name="tags" value="citrus"`, `name="new-tags"`)
+
+		form := setScores(url.Values{"name": {"Pale"}, "abv": {"5"}, "tags": {"citrus", "piney"}, "new-tags": {"Passionfruit, citrus"}}, "7")
+		res, body := c.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `href="/beers?tag=citrus"`, `href="/beers?tag=passionfruit"`, `href="/beers?tag=piney"`)
+
+		form = setScores(url.Values{"name": {"Stout"}, "abv": {"6"}, "tags": {"roasty"}, "new-tags": {strings.Repeat("x", 31)}}, "7")
+		res, body = c.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Tags must be at most 30 characters")
+
+		form.Set("new-tags", "")
+		c.do(http.MethodPost, "/beer", form, true)
+
+		// New tags become options and are checked when editing
+		_, body = c.do(http.MethodGet, "/beer/1/edit", nil, true)
+		expectBody(t, body, `value="passionfruit" class="mr-1" checked`)
+		expectNotBody(t, body, `value="roasty" class="mr-1" checked`)
+
+		res, body = c.do(http.MethodGet, "/beers?tag=Citrus", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beer-1"`)
+		expectNotBody(t, body, `id="beer-2"`)
+
+		_, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {"pale"}, "tag": {"roasty"}}, true)
+		expectBody(t, body, "No beers found")
+		_, body = c.do(http.MethodPost, "/beer/search", url.Values{"q": {"stout"}, "tag": {"roasty"}}, true)
+		expectBody(t, body, `id="beer-2"`)
+
+		res, _ = c.do(http.MethodPost, "/beer/search", url.Values{"q": {""}, "tag": {"stone fruit"}}, true)
+		expectStatus(t, res, http.StatusSeeOther)
+		if loc := res.Header.Get("Location"); loc != "/beers?tag=stone+fruit" {
+			t.Errorf("redirect for an empty search with a tag: got %q", loc)
+		}
+
+		// Editing replaces the tags
+		form = setScores(url.Values{"name": {"Pale"}, "abv": {"5"}, "tags": {"piney"}}, "7")
+		res, body = c.do(http.MethodPut, "/beer/1", form, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `href="/beers?tag=piney"`)
+		expectNotBody(t, body, `href="/beers?tag=citrus"`)
+
+		// Tags on deleted beers don't count
+		c.do(http.MethodDelete, "/beer/2", nil, true)
+		res, body = c.do(http.MethodGet, "/tags", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `title="1 beers" class="text-orange-500 hover:underline text-3xl"`, `class="text-xs text-gray-500 hover:underline">roasty`)
+	})
+}
+
+func TestPhotos(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		blobStore := blobs.NewMemoryBlobStore()
+		ts := newTestServerWithBlobs(t, stores, blobStore)
+		admin := loggedIn(t, ts, "saltytaro")
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+
+		img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		img.Set(0, 0, color.RGBA{R: 0xff, A: 0xff})
+		var label bytes.Buffer
+		if err := png.Encode(&label, img); err != nil {
+			t.Fatal(err)
+		}
+
+		res, body := c.upload("/beer/99/photos", "photo", "label.png", label.Bytes())
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, body = c.upload("/beer/1/photos", "photo", "label.txt", []byte("not a photo"))
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Photos must be JPEG, PNG or GIF images")
+
+		res, body = c.upload("/beer/1/photos", "photo", "label.png", label.Bytes())
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beer-photos"`, `src="/photo/1/thumb"`, `hx-delete="/photo/1"`)
+		if blobStore.Len() != 2 {
+			t.Errorf("blobs after uploading: got %d, want 2", blobStore.Len())
+		}
+
+		res, body = c.do(http.MethodGet, "/photo/1", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		if res.Header.Get("Content-Type") != "image/png" || body != label.String() {
+			t.Errorf("getting photo: got %s of %d bytes", res.Header.Get("Content-Type"), len(body))
+		}
+
+		res, body = c.do(http.MethodGet, "/photo/1/thumb", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		thumb, format, err := image.Decode(strings.NewReader(body))
+		if err != nil || format != "jpeg" || thumb.Bounds().Dx() != 320 || thumb.Bounds().Dy() != 240 {
+			t.Errorf("getting thumbnail: got %s, %v", format, err)
+		}
+
+		_, body = c.do(http.MethodGet, "/beer/1", nil, false)
+		expectBody(t, body, `src="/photo/1/thumb"`)
+
+		res, _ = c.do(http.MethodDelete, "/photo/1", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+		res, _ = c.do(http.MethodGet, "/photo/1", nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+		if blobStore.Len() != 0 {
+			t.Errorf("blobs after deleting: got %d, want 0", blobStore.Len())
+		}
+
+		// Deleting the beer keeps its photos in case it's restored, but purging it removes them
+		c.upload("/beer/1/photos", "photo", "label.png", label.Bytes())
+		c.do(http.MethodDelete, "/beer/1", nil, true)
+		if blobStore.Len() != 2 {
+			t.Errorf("blobs after deleting beer: got %d, want 2", blobStore.Len())
+		}
+		res, _ = admin.do(http.MethodDelete, "/trash/beer/1", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+		if blobStore.Len() != 0 {
+			t.Errorf("blobs after purging beer: got %d, want 0", blobStore.Len())
+		}
+		res, _ = c.do(http.MethodGet, "/photo/2/thumb", nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func TestBarcodes(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/brewer", url.Values{"name": {"Guinness"}, "location": {"Dublin"}}, true)
+
+		res, body := c.do(http.MethodGet, "/barcode/4006381333932", nil, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Barcode 4006381333932 has the wrong check digit")
+
+		// Unknown barcodes fill in the form from the lookup, matching the brewer by name
+		res, body = c.do(http.MethodGet, "/barcode/2000000000039", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer"`, `value="Draught"`, `value="Irish Stout"`, `value="4.20"`, `value="1" selected`, `value="2000000000039"`)
+		_, body = c.do(http.MethodGet, "/barcode/2000000000015", nil, true)
+		expectBody(t, body, `value="Pale Ale"`, "Little Creatures isn&#39;t one of the brewers yet")
+		_, body = c.do(http.MethodGet, "/barcode/4006381333931", nil, true)
+		expectBody(t, body, `hx-post="/beer"`, `value="4006381333931"`)
+
+		form := setScores(url.Values{"brewer-id": {"1"}, "name": {"Draught"}, "abv": {"4.2"}, "barcode": {"96385075"}}, "7")
+		res, body = c.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Barcode has the wrong check digit")
+
+		form.Set("barcode", "2000000000039")
+		res, _ = c.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusOK)
+
+		// Now the barcode opens the beer, including as UPC-A
+		res, _ = c.do(http.MethodGet, "/barcode/2000000000039", nil, true)
+		expectStatus(t, res, http.StatusSeeOther)
+		if loc := res.Header.Get("Location"); loc != "/beer/1" {
+			t.Errorf("redirect for a known barcode: got %q, want /beer/1", loc)
+		}
+		c.do(http.MethodPost, "/beer/1/barcodes", url.Values{"barcode": {"036000291452"}}, true)
+		res, _ = c.do(http.MethodGet, "/barcode/0036000291452", nil, true)
+		if loc := res.Header.Get("Location"); loc != "/beer/1" {
+			t.Errorf("redirect for a UPC-A barcode: got %q, want /beer/1", loc)
+		}
+
+		_, body = c.do(http.MethodGet, "/beer/1", nil, false)
+		expectBody(t, body, `id="beer-barcodes"`, "0036000291452", "2000000000039")
+
+		form = setScores(url.Values{"name": {"Extra Stout"}, "abv": {"5.6"}, "barcode": {"2000000000039"}}, "7")
+		res, body = c.do(http.MethodPost, "/beer", form, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "That barcode is already on a beer")
+
+		res, body = c.do(http.MethodPost, "/beer/1/barcodes", url.Values{"barcode": {"123"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Barcode must be 8, 12 or 13 digits long")
+
+		res, _ = c.do(http.MethodDelete, "/barcode/0036000291452", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+		res, _ = c.do(http.MethodDelete, "/barcode/0036000291452", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		// Deleted beers' barcodes go back to being looked up
+		c.do(http.MethodDelete, "/beer/1", nil, true)
+		res, body = c.do(http.MethodGet, "/barcode/2000000000039", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer"`)
+	})
+}
+
+func TestFridge(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "abv": {"6"}}, "7"), true)
+
+		res, body := c.do(http.MethodGet, "/fridge", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "The fridge is empty", `value="375"`, fmt.Sprintf(`value="%s"`, time.Now().Format(time.DateOnly)))
+
+		res, body = c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"1"}, "quantity": {"0"}, "container-ml": {"375"}, "purchased-on": {"2025-03-01"}, "price": {"-1"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Quantity must be a whole number of at least 1", "Price must be a number of at least 0")
+
+		res, body = c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"1"}, "quantity": {"4"}, "container-ml": {"375"}, "purchased-on": {"2025-03-01"}, "best-before": {"2025-02-01"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must not be before it was bought")
+
+		// A beer which is long past its best-before, and one which keeps for years
+		stale := url.Values{"beer-id": {"1"}, "quantity": {"3"}, "container-ml": {"375"}, "purchased-on": {"2025-03-01"}, "best-before": {"2025-04-01"}, "price": {"4.5"}}
+		res, body = c.do(http.MethodPost, "/fridge", stale, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "3 × 375 ml", "4.50 AUD each", "3.04 per standard drink", "Past its best-before of 1 Apr 2025")
+		fresh := url.Values{"beer-id": {"2"}, "quantity": {"6"}, "container-ml": {"440"}, "purchased-on": {"2025-03-01"}, "best-before": {"2099-01-01"}}
+		c.do(http.MethodPost, "/fridge", fresh, true)
+
+		_, body = c.do(http.MethodGet, "/fridge", nil, true)
+		if strings.Index(body, "Pale") > strings.Index(body, "Stout") {
+			t.Error("the fridge isn't sorted by best-before")
+		}
+
+		// The beer list shows what's in stock, and which are running low
+		_, body = c.do(http.MethodGet, "/beers", nil, true)
+		expectBody(t, body, "3 in the fridge", "6 in the fridge", `hx-post="/beer/1/take"`)
+
+		res, body = c.do(http.MethodPost, "/beer/1/take", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="beer-1-stock"`, "Only 2 left")
+		c.do(http.MethodPost, "/beer/1/take", nil, true)
+		_, body = c.do(http.MethodPost, "/beer/1/take", nil, true)
+		expectNotBody(t, body, "left", "Take one")
+		res, _ = c.do(http.MethodPost, "/beer/1/take", nil, true)
+		expectStatus(t, res, http.StatusConflict)
+
+		_, body = c.do(http.MethodGet, "/beer/2", nil, false)
+		expectBody(t, body, "6 in the fridge")
+
+		res, _ = c.do(http.MethodDelete, "/stock/2", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+		res, _ = c.do(http.MethodDelete, "/stock/2", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		_, body = c.do(http.MethodGet, "/fridge", nil, true)
+		expectBody(t, body, "The fridge is empty")
+	})
+}
+
+func TestSpending(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		today := time.Now().Format(time.DateOnly)
+		res, body := c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"1"}, "quantity": {"4"}, "container-ml": {"375"}, "purchased-on": {today}, "price": {"4.5"}, "currency": {"dollars"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Currency must be a three letter code, like AUD")
+		c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"1"}, "quantity": {"4"}, "container-ml": {"375"}, "purchased-on": {today}, "price": {"4.5"}, "currency": {"aud"}}, true)
+		// Bought without a price, so it isn't in the totals
+		c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"1"}, "quantity": {"1"}, "container-ml": {"375"}, "purchased-on": {today}}, true)
+
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, `hx-get="/spending"`)
+		expectNotBody(t, body, "budget-bar")
+
+		res, body = c.do(http.MethodPut, "/budget", url.Values{"amount": {"0"}, "currency": {"AUD"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Budget must be a number more than 0")
+
+		res, body = c.do(http.MethodPut, "/budget", url.Values{"amount": {"100"}, "currency": {"aud"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "18.00 of 100.00 AUD", `value="18"`)
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, "budget-bar", "18.00 of 100.00 AUD")
+
+		// Spending in other currencies doesn't count towards the budget
+		c.do(http.MethodPut, "/budget", url.Values{"amount": {"10"}, "currency": {"NZD"}}, true)
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, "0.00 of 10.00 NZD")
+		_, body = c.do(http.MethodPut, "/budget", url.Values{"amount": {"10"}, "currency": {"AUD"}}, true)
+		expectBody(t, body, "over-budget", "8.00 AUD over budget")
+
+		res, body = c.do(http.MethodGet, "/spending", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "By drinker", "guest", "18.00 AUD", "5.9", "3.04 AUD", `href="/spending.csv"`)
+
+		res, body = c.do(http.MethodGet, "/spending.csv", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		if got := res.Header.Get("Content-Disposition"); got != `attachment; filename="spending.csv"` {
+			t.Errorf("exporting spending: got Content-Disposition %q", got)
+		}
+		records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+		if err != nil || len(records) != 3 {
+			t.Fatalf("exporting spending: got %q, %v", records, err)
+		}
+		if got := strings.Join(records[1], ","); got != today+",Pale,,,5,guest,4,375,4.50,AUD,18.00,5.92,3.04" {
+			t.Errorf("exporting spending: got %q", got)
+		}
+		if got := strings.Join(records[2], ","); got != today+",Pale,,,5,guest,1,375,,,,1.48," {
+			t.Errorf("exporting spending without a price: got %q", got)
+		}
+
+		res, body = c.do(http.MethodDelete, "/budget", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "You haven")
+	})
+}
+
+func TestStats(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "abv": {"6.2"}}, "8"), true)
+
+		_, body := c.do(http.MethodGet, "/beer/1", nil, true)
+		expectBody(t, body, `hx-post="/beer/1/drinks"`, `value="375"`)
+
+		res, body := c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"0"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Size must be a whole number of millilitres")
+		res, _ = c.do(http.MethodPost, "/beer/999/drinks", url.Values{"serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, body = c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}, "drunk-at": {"2025-03-04T20:00"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Logged 375 ml on Tue 4 Mar")
+		c.do(http.MethodPost, "/beer/2/drinks", url.Values{"serving-ml": {"440"}, "drunk-at": {"2025-03-12T21:30"}}, true)
+
+		res, body = c.do(http.MethodGet, "/stats?from=2025-03-01&to=2025-03-31", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body,
+			"Drinks per week", "<svg", ">3 Mar<", ">31 Mar<", ">5%<", ">6%<", "Nothing to show",
+			// Dry from the 13th to the end of the month
+			"days longest dry streak, 13 Mar to 31 Mar", ">19<",
+			"Stout", "440 ml", "Wed 12 Mar 2025",
+		)
+
+		res, body = c.do(http.MethodGet, "/stats?from=2025-03-10&to=2025-03-01", nil, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "To must not be before from")
+		expectNotBody(t, body, "<svg")
+
+		// Taking one out of the fridge logs it as drunk today
+		c.do(http.MethodPost, "/fridge", url.Values{"beer-id": {"2"}, "quantity": {"2"}, "container-ml": {"330"}, "purchased-on": {"2025-03-01"}}, true)
+		c.do(http.MethodPost, "/beer/2/take", nil, true)
+		_, body = c.do(http.MethodGet, "/stats", nil, true)
+		expectBody(t, body, "330 ml", time.Now().Format("Mon 2 Jan 2006"))
+
+		// Only your own drinks can be deleted
+		other := loggedIn(t, ts, "saltytaro")
+		res, _ = other.do(http.MethodDelete, "/drink/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, _ = c.do(http.MethodDelete, "/drink/1", nil, true)
+		expectStatus(t, res, http.StatusNoContent)
+		_, body = c.do(http.MethodGet, "/stats?from=2025-03-01&to=2025-03-31", nil, true)
+		expectNotBody(t, body, "Tue 4 Mar 2025")
+	})
+}
+
+func TestDrinkSessions(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "abv": {"6.2"}}, "8"), true)
+
+		res, body := c.do(http.MethodGet, "/sessions", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No sessions yet", `hx-post="/sessions"`)
+
+		res, body = c.do(http.MethodPost, "/sessions", url.Values{"name": {" "}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+		res, body = c.do(http.MethodPost, "/sessions", url.Values{"name": {"Friday"}, "started-at": {"soon"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Started must be a date and time")
+
+		res, body = c.do(http.MethodPost, "/sessions", url.Values{"name": {"Friday at the Felon's taproom"}, "started-at": {"2025-03-14T17:00"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-get="/session/1"`, "Friday at the Felon&#39;s taproom", "Fri 14 Mar 17:00 until now")
+
+		// Only participants can see the session, and others can't tell it's there
+		res, _ = guest.do(http.MethodGet, "/session/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, _ = guest.do(http.MethodPost, "/session/1/venues", url.Values{"venue": {"Felon's"}}, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, body = c.do(http.MethodGet, "/session/1", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/session/1/drinks"`, "End Session", "Delete", "/shared/session/")
+
+		res, body = c.do(http.MethodPost, "/session/1/participants", url.Values{"username": {"nobody"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "There&#39;s no one called nobody")
+		res, _ = c.do(http.MethodPost, "/session/1/participants", url.Values{"username": {"guest"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		res, body = c.do(http.MethodPost, "/session/1/venues", url.Values{"venue": {""}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+		c.do(http.MethodPost, "/session/1/venues", url.Values{"venue": {"Felon's"}}, true)
+		c.do(http.MethodPost, "/session/1/venues", url.Values{"venue": {"The Pub"}}, true)
+
+		res, body = c.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"999"}, "serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Beer must be one of the beers")
+		res, body = c.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"1"}, "serving-ml": {"0"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Size must be a whole number of millilitres")
+
+		c.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"1"}, "serving-ml": {"375"}, "drunk-at": {"2025-03-14T17:30"}}, true)
+		guest.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"2"}, "serving-ml": {"570"}, "drunk-at": {"2025-03-14T17:45"}}, true)
+		res, body = c.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"2"}, "serving-ml": {"375"}, "drunk-at": {"2025-03-14T18:15"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		// Each participant's drinks in order with their total, then everyone's in order
+		expectBody(t, body,
+			"Felon&#39;s", "The Pub",
+			"saltytaro", "3.3 standard drinks", "guest", "2.8 standard drinks",
+			"17:30", "17:45", "18:15",
+		)
+		timeline := body[strings.Index(body, "session-timeline"):]
+		if pale, stout := strings.Index(timeline, "had a Pale"), strings.Index(timeline, "had a Stout"); pale < 0 || stout < pale {
+			t.Errorf("got the timeline out of order: %s", timeline)
+		}
+
+		// The drinks are logged like any others
+		_, body = guest.do(http.MethodGet, "/stats?from=2025-03-01&to=2025-03-31", nil, true)
+		expectBody(t, body, "Stout", "570 ml")
+
+		res, body = c.do(http.MethodPost, "/session/1/end", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectNotBody(t, body, "End Session", "until now")
+
+		// Anyone with the link can see the summary, without being able to change it
+		share := regexp.MustCompile(`/shared/session/[A-Za-z0-9_-]+`).FindString(body)
+		if share == "" {
+			t.Fatalf("got no share link in %s", body)
+		}
+		res, body = newTestClient(t, ts).do(http.MethodGet, share, nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Friday at the Felon&#39;s taproom", "saltytaro", "guest", "had a Stout")
+		expectNotBody(t, body, "hx-post", "hx-delete")
+		res, _ = newTestClient(t, ts).do(http.MethodGet, "/shared/session/guess", nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+
+		// Only whoever started it can delete it
+		res, _ = guest.do(http.MethodDelete, "/session/1", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = c.do(http.MethodDelete, "/session/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No sessions yet")
+		res, _ = newTestClient(t, ts).do(http.MethodGet, share, nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func TestVenues(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "abv": {"6.2"}}, "8"), true)
+
+		res, body := c.do(http.MethodGet, "/venues", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No venues yet", `hx-post="/venues"`, "Bottle shop")
+
+		res, body = c.do(http.MethodPost, "/venues", url.Values{"name": {" "}, "type": {"bar"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+		res, body = c.do(http.MethodPost, "/venues", url.Values{"name": {"Felon's"}, "type": {"bar"}, "latitude": {"north"}, "longitude": {"153"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Latitude must be a number", `value="north"`)
+		res, body = c.do(http.MethodPost, "/venues", url.Values{"name": {"Felon's"}, "type": {"bar"}, "latitude": {"-27.46"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+		res, body = c.do(http.MethodPost, "/venues", url.Values{"name": {"Felon's"}, "type": {"pub"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must be a bar, bottle shop or home")
+
+		res, body = c.do(http.MethodPost, "/venues", url.Values{"name": {"Felon's"}, "address": {"5 Boundary St"}, "type": {"bar"}, "latitude": {"-27.4614"}, "longitude": {"153.0355"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-get="/venue/1"`, "Felon&#39;s", "Bar · 5 Boundary St")
+		guest.do(http.MethodPost, "/venues", url.Values{"name": {"Bottle-O"}, "type": {"bottle-shop"}, "latitude": {"-27.4795"}, "longitude": {"153.0265"}}, true)
+		guest.do(http.MethodPost, "/venues", url.Values{"name": {"Harbour Bar"}, "type": {"bar"}, "latitude": {"-33.8568"}, "longitude": {"151.2153"}}, true)
+
+		// Nearest first, within the radius
+		res, body = c.do(http.MethodGet, "/venues/nearby", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Use My Location")
+		expectNotBody(t, body, "nearby-venue ")
+		res, body = c.do(http.MethodGet, "/venues/nearby?latitude=-27.46&longitude=153.035", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Felon&#39;s", "Bottle-O", "0.2 km", "2.3 km")
+		expectNotBody(t, body, "Harbour Bar")
+		if felons, bottleo := strings.Index(body, "Felon&#39;s"), strings.Index(body, "Bottle-O"); felons > bottleo {
+			t.Errorf("got nearby venues out of order: %s", body)
+		}
+		_, body = c.do(http.MethodGet, "/venues/nearby?latitude=-27.46&longitude=153.035&radius=1000", nil, true)
+		expectBody(t, body, "Harbour Bar")
+		_, body = c.do(http.MethodGet, "/venues/nearby?latitude=-30&longitude=140&radius=1", nil, true)
+		expectBody(t, body, "No venues nearby")
+		res, body = c.do(http.MethodGet, "/venues/nearby?latitude=-91&longitude=153&radius=-1", nil, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Latitude must be between -90 and 90", "Radius must be more than 0")
+
+		res, _ = c.do(http.MethodGet, "/venue/999", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, body = c.do(http.MethodGet, "/venue/1", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/venue/1/checkins"`, "No check-ins yet", "-27.46140, 153.03550", `hx-delete="/venue/1"`)
+
+		res, body = c.do(http.MethodPost, "/venue/1/checkins", url.Values{"beer-id": {"999"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Beer must be one of the beers")
+		res, body = c.do(http.MethodPost, "/venue/1/checkins", url.Values{"beer-id": {"1"}, "checked-in-at": {"soon"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "When must be a date and time")
+
+		c.do(http.MethodPost, "/venue/1/checkins", url.Values{"beer-id": {"1"}, "checked-in-at": {"2025-03-14T17:30"}}, true)
+		guest.do(http.MethodPost, "/venue/1/checkins", url.Values{"beer-id": {"2"}, "checked-in-at": {"2025-03-14T18:00"}}, true)
+		c.do(http.MethodPost, "/venue/2/checkins", url.Values{"beer-id": {"2"}, "checked-in-at": {"2025-03-15T12:00"}}, true)
+		res, body = guest.do(http.MethodPost, "/venue/1/checkins", url.Values{"beer-id": {"2"}, "checked-in-at": {"2025-03-14T19:00"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		// Only admins can delete venues
+		expectNotBody(t, body, `hx-delete="/venue/1"`)
+		expectBody(t, body, "guest", "had a Stout", "Fri 14 Mar 19:00")
+		topBeers := body[strings.Index(body, "venue-top-beers"):]
+		if stout, pale := strings.Index(topBeers, "Stout"), strings.Index(topBeers, "Pale"); stout < 0 || pale < stout {
+			t.Errorf("got the most drunk beers out of order: %s", topBeers)
+		}
+
+		res, body = c.do(http.MethodGet, "/checkins", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Stout", "Bottle-O", "Pale", "Felon&#39;s", `hx-delete="/checkin/1"`, `hx-delete="/checkin/3"`)
+		expectNotBody(t, body, `hx-delete="/checkin/2"`)
+
+		// Other users' check-ins can't be deleted, as if they weren't there
+		res, _ = guest.do(http.MethodDelete, "/checkin/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, body = c.do(http.MethodDelete, "/checkin/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectNotBody(t, body, `hx-delete="/checkin/1"`)
+		res, _ = c.do(http.MethodDelete, "/checkin/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, _ = guest.do(http.MethodDelete, "/venue/1", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = c.do(http.MethodDelete, "/venue/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectNotBody(t, body, `hx-get="/venue/1"`)
+		_, body = guest.do(http.MethodGet, "/checkins", nil, true)
+		expectBody(t, body, "No check-ins yet")
+	})
+}
+
+func TestShouts(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/sessions", url.Values{"name": {"Friday"}, "started-at": {"2025-03-14T17:00"}}, true)
+
+		// Before anyone's bought a round it's whoever joined first
+		res, body := c.do(http.MethodGet, "/session/1/shouts", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No rounds yet", "It's saltytaro's shout", `hx-post="/session/1/rounds"`)
+		res, _ = guest.do(http.MethodGet, "/session/1/shouts", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		_, body = c.do(http.MethodGet, "/session/1", nil, true)
+		expectBody(t, body, `hx-get="/session/1/shouts"`)
+
+		c.do(http.MethodPost, "/session/1/participants", url.Values{"username": {"guest"}}, true)
+
+		for _, tc := range []struct {
+			form url.Values
+			want string
+		}{
+			{url.Values{"bought-by": {"999"}, "recipients": {"1", "2"}}, "Bought by must be someone in the session"},
+			{url.Values{"bought-by": {"1"}, "recipients": {"1", "999"}}, "Everyone it&#39;s for must be in the session"},
+			{url.Values{"bought-by": {"1"}}, "This field is required"},
+			{url.Values{"bought-by": {"1"}, "recipients": {"1", "2"}, "cost": {"-1"}}, "Cost must be a number of at least 0"},
+			{url.Values{"bought-by": {"1"}, "recipients": {"1", "2"}, "currency": {"dollars"}}, "Currency must be a three letter code, like AUD"},
+			{url.Values{"bought-by": {"1"}, "recipients": {"1", "2"}, "bought-at": {"soon"}}, "When must be a date and time"},
+		} {
+			res, body = c.do(http.MethodPost, "/session/1/rounds", tc.form, true)
+			expectStatus(t, res, http.StatusUnprocessableEntity)
+			expectBody(t, body, tc.want)
+		}
+
+		res, body = c.do(http.MethodPost, "/session/1/rounds", url.Values{"bought-by": {"1"}, "recipients": {"1", "2"}, "cost": {"20"}, "currency": {"aud"}, "bought-at": {"2025-03-14T17:30"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body,
+			"17:30", "saltytaro</span> shouted saltytaro and guest", "20.00 AUD",
+			"It's guest's shout",
+			"guest</span> owes <span class=\"text-white\">saltytaro</span> 1 drink",
+			"guest</span> pays <span class=\"text-white\">saltytaro</span> 10.00 AUD",
+		)
+
+		// guest shouts back, so they're square on drinks but not on money
+		res, body = guest.do(http.MethodPost, "/session/1/rounds", url.Values{"bought-by": {"2"}, "recipients": {"1", "2"}, "bought-at": {"2025-03-14T18:00"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Everyone's square on drinks", "It's saltytaro's shout", "10.00 AUD")
+
+		// Rounds in sessions the user isn't in can't be deleted, as if they weren't there
+		guest.do(http.MethodPost, "/sessions", url.Values{"name": {"Saturday"}}, true)
+		guest.do(http.MethodPost, "/session/2/rounds", url.Values{"bought-by": {"2"}, "recipients": {"2"}}, true)
+		res, _ = c.do(http.MethodDelete, "/round/3", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, body = guest.do(http.MethodDelete, "/round/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "saltytaro</span> owes", "It's saltytaro's shout")
+		expectNotBody(t, body, "pays", `hx-delete="/round/1"`)
+		res, _ = guest.do(http.MethodDelete, "/round/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func TestSocial(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		// The home page loads the feed after it's shown
+		_, body := c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, `hx-get="/feed"`, `hx-get="/people"`)
+		res, body := c.do(http.MethodGet, "/feed", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Nothing yet from the people you follow")
+
+		guest.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		guest.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		guest.do(http.MethodPut, "/beer/1", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale Ale"}, "abv": {"5"}}, "7"), true)
+
+		// Nothing shows until they're followed
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "Nothing yet from the people you follow")
+
+		res, body = c.do(http.MethodGet, "/people", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/people/2/follow"`, `hx-put="/privacy"`)
+		expectNotBody(t, body, `hx-post="/people/1/follow"`)
+		res, body = c.do(http.MethodPost, "/people/2/follow", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-delete="/people/2/follow"`)
+		res, _ = c.do(http.MethodPost, "/people/1/follow", nil, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		res, _ = c.do(http.MethodPost, "/people/999/follow", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		// Newest first, with who did it
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "edited Pale Ale", "added Pale Ale", "added the brewer Felon&#39;s", `hx-get="/people/2"`)
+		if edited, added := strings.Index(body, "edited Pale Ale"), strings.Index(body, "added the brewer"); edited > added {
+			t.Errorf("got the edit after adding the brewer, want newest first")
+		}
+		expectNotBody(t, body, "Loading more")
+
+		// Friends only shows to those followed back
+		res, body = guest.do(http.MethodPut, "/privacy", url.Values{"visibility": {"secret"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must be public, friends or private")
+		res, _ = guest.do(http.MethodPut, "/privacy", url.Values{"visibility": {"friends"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "Nothing yet from the people you follow")
+		guest.do(http.MethodPost, "/people/1/follow", nil, true)
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "edited Pale Ale")
+
+		// A profile shows the same, and the user always sees their own
+		res, body = c.do(http.MethodGet, "/people/2", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "1 following, 1 followers", `hx-get="/people/2/activity"`, `hx-delete="/people/2/follow"`)
+		guest.do(http.MethodPut, "/privacy", url.Values{"visibility": {"private"}}, true)
+		_, body = c.do(http.MethodGet, "/people/2/activity", nil, true)
+		expectBody(t, body, "Nothing to see yet")
+		_, body = guest.do(http.MethodGet, "/people/2/activity", nil, true)
+		expectBody(t, body, "edited Pale Ale")
+		_, body = guest.do(http.MethodGet, "/people", nil, true)
+		expectBody(t, body, `value="private" selected`)
+
+		// A full page loads the next when it's scrolled to
+		guest.do(http.MethodPut, "/privacy", url.Values{"visibility": {"public"}}, true)
+		for i := range 20 {
+			guest.do(http.MethodPost, "/brewer", url.Values{"name": {fmt.Sprintf("Brewer %d", i)}, "location": {"Brisbane"}}, true)
+		}
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "Brewer 19", `hx-get="/feed?before=4"`, `hx-trigger="revealed"`)
+		expectNotBody(t, body, "Pale Ale")
+		_, body = c.do(http.MethodGet, "/feed?before=4", nil, true)
+		expectBody(t, body, "edited Pale Ale", "added the brewer Felon&#39;s")
+		expectNotBody(t, body, "Brewer 0", "Loading more")
+		res, _ = c.do(http.MethodGet, "/feed?before=soon", nil, true)
+		expectStatus(t, res, http.StatusBadRequest)
+
+		res, _ = c.do(http.MethodDelete, "/people/2/follow", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		_, body = c.do(http.MethodGet, "/feed", nil, true)
+		expectBody(t, body, "Nothing yet from the people you follow")
+	})
+}
+
+func TestComments(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		mailServer := withMailServer(t, s)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+		c.do(http.MethodPut, "/email", url.Values{"email": {"saltytaro@example.com"}}, true)
+		guest.do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}}, true)
+
+		guest.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		guest.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "abv": {"5"}}, "7"), true)
+
+		// Both pages load their comments once shown
+		_, body := c.do(http.MethodGet, "/beer/1", nil, false)
+		expectBody(t, body, `hx-get="/beer/1/comments"`)
+		_, body = c.do(http.MethodGet, "/brewer/1", nil, false)
+		expectBody(t, body, `hx-get="/brewer/1/comments"`)
+		res, body := c.do(http.MethodGet, "/beer/1/comments", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No comments yet", `hx-post="/beer/1/comments"`)
+		res, _ = c.do(http.MethodGet, "/beer/999/comments", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, _ = c.do(http.MethodPost, "/brewer/999/comments", url.Values{"body": {"Hi"}}, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		res, body = c.do(http.MethodPost, "/beer/1/comments", url.Values{"body": {"  "}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+
+		// Markdown is rendered but HTML isn't
+		res, body = guest.do(http.MethodPost, "/beer/1/comments", url.Values{"body": {"**Lovely** drop <script>alert(1)</script> @saltytaro"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "<strong>Lovely</strong> drop &lt;script&gt;", `hx-put="/comment/1"`, `hx-delete="/comment/1"`)
+		expectNotBody(t, body, "<script>", "No comments yet")
+
+		// Replies go under what they reply to
+		res, body = c.do(http.MethodPost, "/beer/1/comments", url.Values{"body": {"Agreed"}, "parent-id": {"1"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `<ul class="ml-6 border-l border-gray-700 pl-4"><li id="comment-2"`)
+		res, body = c.do(http.MethodPost, "/brewer/1/comments", url.Values{"body": {"Wrong page"}, "parent-id": {"1"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must be a comment on the same page", "Wrong page")
+
+		// Reactions toggle
+		res, body = c.do(http.MethodPost, "/comment/1/reactions", url.Values{"emoji": {"🍺"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "reaction-mine")
+		_, body = c.do(http.MethodPost, "/comment/1/reactions", url.Values{"emoji": {"🍺"}}, true)
+		expectNotBody(t, body, "reaction-mine")
+		res, _ = c.do(http.MethodPost, "/comment/1/reactions", url.Values{"emoji": {"🦄"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+
+		// Only the author can edit
+		res, _ = c.do(http.MethodPut, "/comment/1", url.Values{"body": {"Mine now"}}, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = guest.do(http.MethodPut, "/comment/1", url.Values{"body": {"**Lovely** drop @saltytaro @nobody @guest"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "(edited)")
+
+		// Only those newly mentioned are emailed, and not the author
+		s.sendOutbox(context.Background(), time.Now())
+		if got := len(mailServer.Received()); got != 1 {
+			t.Fatalf("got %d emails, want 1", got)
+		}
+		email := receivedEmail(t, mailServer, 0)
+		if email.Subject != "guest mentioned you" || mailServer.Received()[0].To[0] != "saltytaro@example.com" {
+			t.Errorf("got email %q to %v", email.Subject, mailServer.Received()[0].To)
+		}
+		expectBody(t, email.Text, "Hi saltytaro,", "guest mentioned you in a comment on Pale:", "**Lovely** drop", "/beer/1")
+		expectNotBody(t, email.HTML, "<script>")
+
+		// Admins can remove anyone's comments, others only their own
+		res, _ = guest.do(http.MethodDelete, "/comment/2", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = c.do(http.MethodDelete, "/comment/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Removed by a moderator", "Agreed")
+		expectNotBody(t, body, "Lovely")
+		res, body = c.do(http.MethodDelete, "/comment/2", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No comments yet")
+		res, _ = c.do(http.MethodDelete, "/comment/2", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func TestTastings(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Stout"}, "abv": {"6"}}, "7"), true)
+
+		res, body := c.do(http.MethodGet, "/tastings", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No tastings yet", `hx-post="/tastings"`)
+		res, body = c.do(http.MethodPost, "/tastings", url.Values{"name": {"March"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required")
+		res, body = c.do(http.MethodPost, "/tastings", url.Values{"name": {"March"}, "held-at": {"2025-03-07T19:00"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-get="/tasting/1"`, "organised by saltytaro")
+
+		// Only the organiser pours
+		res, _ = guest.do(http.MethodPost, "/tasting/1/pours", url.Values{"beer-id": {"1"}}, true)
+		expectStatus(t, res, http.StatusForbidden)
+		for _, beerId := range []string{"1", "2"} {
+			res, _ = c.do(http.MethodPost, "/tasting/1/pours", url.Values{"beer-id": {beerId}}, true)
+			expectStatus(t, res, http.StatusOK)
+		}
+		res, body = c.do(http.MethodPost, "/tasting/1/pours", url.Values{"beer-id": {"1"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "That beer is already poured")
+		res, _ = c.do(http.MethodPost, "/tasting/999/pours", url.Values{"beer-id": {"1"}}, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		// Nobody else can see what's in the pours
+		_, body = c.do(http.MethodGet, "/tasting/1", nil, true)
+		expectBody(t, body, "Pale by Felon&#39;s", `hx-delete="/tasting/1/pour/2"`, `hx-post="/tasting/1/reveal"`)
+		res, body = guest.do(http.MethodGet, "/tasting/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Pour 1", "Pour 2", `hx-put="/tasting/1/pour/2/scorecard"`)
+		expectNotBody(t, body, "Pale", "Stout", "/reveal", "/pours")
+
+		res, body = guest.do(http.MethodPut, "/tasting/1/pour/1/scorecard", setScores(url.Values{}, "11"), true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Aroma must be between 0 and 10")
+		res, _ = guest.do(http.MethodPut, "/tasting/1/pour/3/scorecard", setScores(url.Values{}, "5"), true)
+		expectStatus(t, res, http.StatusNotFound)
+		for _, score := range []struct {
+			c      *testClient
+			number string
+			score  string
+		}{{guest, "1", "9"}, {guest, "2", "4"}, {c, "1", "8"}, {c, "2", "5"}} {
+			res, _ = score.c.do(http.MethodPut, "/tasting/1/pour/"+score.number+"/scorecard", setScores(url.Values{}, score.score), true)
+			expectStatus(t, res, http.StatusOK)
+		}
+		_, body = guest.do(http.MethodGet, "/tasting/1", nil, true)
+		expectBody(t, body, "2 scored", "Update Scores", `value="9.00"`)
+		expectNotBody(t, body, `value="8.00"`)
+
+		// The reveal ranks the beers and puts the scores into their ratings
+		res, _ = guest.do(http.MethodPost, "/tasting/1/reveal", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = c.do(http.MethodPost, "/tasting/1/reveal", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Results", "Pale by Felon&#39;s", "8.50", "4.50", "(Kendall&#39;s W): 1.00, strong agreement")
+		if pale, stout := strings.Index(body, "Pale by"), strings.Index(body, "Stout by"); pale > stout {
+			t.Errorf("got Stout ranked above Pale, want Pale first")
+		}
+		_, body = guest.do(http.MethodGet, "/tasting/1", nil, true)
+		expectBody(t, body, "Pale by Felon&#39;s")
+		expectNotBody(t, body, "/scorecard")
+		beer, err := stores.Beers.GetBeer(context.Background(), 1)
+		if err != nil || math.Abs(beer.Rating.Float64-8.5) > 1e-9 {
+			t.Errorf("got Pale's rating %v, %v, want 8.5", beer.Rating, err)
+		}
+
+		// After which nothing changes
+		res, _ = c.do(http.MethodPost, "/tasting/1/reveal", nil, true)
+		expectStatus(t, res, http.StatusConflict)
+		res, _ = guest.do(http.MethodPut, "/tasting/1/pour/1/scorecard", setScores(url.Values{}, "1"), true)
+		expectStatus(t, res, http.StatusConflict)
+		res, _ = c.do(http.MethodDelete, "/tasting/1/pour/1", nil, true)
+		expectStatus(t, res, http.StatusConflict)
+
+		res, _ = guest.do(http.MethodDelete, "/tasting/1", nil, true)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = c.do(http.MethodDelete, "/tasting/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "No tastings yet")
+	})
+}
+
+func TestBadges(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		res, body := c.do(http.MethodGet, "/badges", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Your Badges", fmt.Sprintf("0 of %d earned", len(badges.Definitions())), "Puckered Up", "0 of 1")
+
+		c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Tart"}, "style": {"Berliner Weisse (Sour)"}, "abv": {"4"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "style": {"American IPA"}, "abv": {"5"}}, "7"), true)
+		guest.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "style": {"Stout"}, "abv": {"6"}}, "7"), true)
+
+		// Adding a beer with a scorecard rates it too
+		_, body = c.do(http.MethodGet, "/badges", nil, true)
+		expectBody(t, body, fmt.Sprintf("3 of %d earned", len(badges.Definitions())), "Earned "+time.Now().Format("2 Jan 2006"), "2 of 5")
+
+		res, body = guest.do(http.MethodGet, "/people/1/badges", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "saltytaro&#39;s Badges", "3 of")
+		res, _ = guest.do(http.MethodGet, "/people/999/badges", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		_, body = guest.do(http.MethodGet, "/people/1", nil, true)
+		expectBody(t, body, `hx-get="/people/1/badges"`)
+
+		// This month, saltytaro covered two styles and guest one, and guest's beer has no brewer
+		res, body = c.do(http.MethodGet, "/leaderboards", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Leaderboards for "+time.Now().Format("January 2006"), "1. saltytaro", "2. guest")
+		month := time.Now().AddDate(0, 0, -time.Now().Day()+1)
+		expectBody(t, body, `hx-get="/leaderboards?month=`+month.AddDate(0, -1, 0).Format("2006-01")+`"`)
+		_, body = c.do(http.MethodGet, "/leaderboards?month="+month.AddDate(0, -1, 0).Format("2006-01"), nil, true)
+		expectBody(t, body, "Nobody yet this month")
+		expectNotBody(t, body, "saltytaro")
+		res, _ = c.do(http.MethodGet, "/leaderboards?month=March", nil, true)
+		expectStatus(t, res, http.StatusBadRequest)
+	})
+}
+
+func TestWishlist(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"brewer-id": {"1"}, "name": {"Pale"}, "style": {"American IPA"}, "abv": {"5"}}, "7"), true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "style": {"Stout"}, "abv": {"8"}}, "7"), true)
+
+		res, body := guest.do(http.MethodGet, "/beers", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer/1/wishlist"`, `hx-post="/beer/2/wishlist"`, "Want to Try")
+
+		res, body = guest.do(http.MethodPost, "/beer/1/wishlist", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-delete="/beer/1/wishlist"`, "On Wishlist")
+		res, body = guest.do(http.MethodPost, "/beer/1/wishlist", nil, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "On Wishlist")
+		res, _ = guest.do(http.MethodPost, "/beer/999/wishlist", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, _ = guest.do(http.MethodPost, "/beer/2/wishlist", url.Values{"priority": {"1"}, "note": {"For winter"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		// Only the guest's list has them
+		_, body = guest.do(http.MethodGet, "/beers", nil, true)
+		expectBody(t, body, `hx-delete="/beer/1/wishlist"`, `hx-delete="/beer/2/wishlist"`)
+		_, body = c.do(http.MethodGet, "/beers", nil, true)
+		expectNotBody(t, body, `hx-delete="/beer/1/wishlist"`)
+		_, body = c.do(http.MethodGet, "/wishlist", nil, true)
+		expectBody(t, body, "Nothing on your wishlist yet")
+
+		// The highest priority first
+		res, body = guest.do(http.MethodGet, "/wishlist", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "2 of 2 beers", "High priority", "For winter", "Felon&#39;s | American IPA | ABV: 5.00%")
+		if strings.Index(body, `id="wish-2"`) > strings.Index(body, `id="wish-1"`) {
+			t.Errorf("expected the stout before the pale in %s", body)
+		}
+
+		for query, want := range map[string]string{
+			"?style=stout":             `id="wish-2"`,
+			"?brewer-id=1":             `id="wish-1"`,
+			"?min-abv=5&max-abv=6":     `id="wish-1"`,
+			"?style=Stout&max-abv=7.5": "0 of 2 beers",
+		} {
+			res, body = guest.do(http.MethodGet, "/wishlist"+query, nil, true)
+			expectStatus(t, res, http.StatusOK)
+			expectBody(t, body, want)
+			if strings.Count(body, `<li id="wish-`) > 1 {
+				t.Errorf("%s: expected at most one beer in %s", query, body)
+			}
+		}
+		res, body = guest.do(http.MethodGet, "/wishlist?min-abv=strong", nil, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Minimum ABV must be a number")
+
+		res, body = guest.do(http.MethodPut, "/beer/1/wishlist", url.Values{"priority": {"7"}, "note": {"Hazy"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must be between 1 and 3", `value="Hazy"`)
+		res, body = guest.do(http.MethodPut, "/beer/1/wishlist", url.Values{"priority": {"soon"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Priority must be a number")
+		res, body = guest.do(http.MethodPut, "/beer/1/wishlist", url.Values{"priority": {"2"}, "note": {"Hazy"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="wish-1"`, "Medium priority", `value="Hazy"`)
+		res, _ = c.do(http.MethodPut, "/beer/1/wishlist", url.Values{"priority": {"2"}}, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		// Editing the stout without scoring it leaves it on the list, and so does an edit which fails
+		res, body = guest.do(http.MethodPut, "/beer/2", url.Values{"name": {"Stout"}, "style": {"Stout"}, "abv": {"8"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-delete="/beer/2/wishlist"`)
+		res, _ = guest.do(http.MethodPut, "/beer/2", setScores(url.Values{"brewer-id": {"999"}, "name": {"Stout"}, "abv": {"8"}}, "9"), true)
+		expectStatus(t, res, http.StatusNotFound)
+		_, body = guest.do(http.MethodGet, "/wishlist", nil, true)
+		expectBody(t, body, "2 of 2 beers", `id="wish-2"`)
+
+		// Rating the stout takes it off the list
+		res, body = guest.do(http.MethodPut, "/beer/2", setScores(url.Values{"name": {"Stout"}, "style": {"Stout"}, "abv": {"8"}}, "9"), true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer/2/wishlist"`)
+		_, body = guest.do(http.MethodGet, "/wishlist", nil, true)
+		expectBody(t, body, "1 of 1 beers", `id="wish-1"`)
+		expectNotBody(t, body, `id="wish-2"`)
+
+		res, body = guest.do(http.MethodDelete, "/beer/1/wishlist", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/beer/1/wishlist"`)
+		res, _ = guest.do(http.MethodDelete, "/beer/1/wishlist", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		_, body = guest.do(http.MethodGet, "/wishlist", nil, true)
+		expectBody(t, body, "Nothing on your wishlist yet")
+	})
+}
+
+func TestGoals(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+
+		res, body := c.do(http.MethodGet, "/goals", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "You haven't set any goals", `hx-put="/goals"`)
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectNotBody(t, body, `id="traffic-light"`)
+
+		res, body = c.do(http.MethodPut, "/goals", url.Values{"alcohol-free-days": {"8"}, "time-zone": {"Middle/Earth"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Alcohol-free days must be a whole number from 1 to 7", "Time zone must be one like Australia/Sydney")
+
+		res, body = c.do(http.MethodPut, "/goals", url.Values{"weekly-limit": {"2"}, "confirm-over-limit": {"true"}, "time-zone": {"Australia/Sydney"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "0.0 of 2 standard drinks this week", "light-green", `value="Australia/Sydney"`, "checked")
+
+		// A can of 5% is about 1.5 standard drinks, so the second one goes over the limit
+		res, body = c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Logged 375 ml")
+		res, body = c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "That would make 3.0 standard drinks this week, over your limit of 2", `name="confirm"`, "Log It Anyway")
+		expectNotBody(t, body, "Logged 375 ml")
+
+		res, body = c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}, "confirm": {"true"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Logged 375 ml")
+
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, `id="traffic-light"`, "light-red", "3.0 of 2 standard drinks this week")
+
+		// Once over the limit there's nothing more to confirm
+		res, _ = c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		// Other users' goals are their own
+		other := loggedIn(t, ts, "saltytaro")
+		res, _ = other.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}}, true)
+		expectStatus(t, res, http.StatusOK)
+
+		res, body = c.do(http.MethodDelete, "/goals", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "You haven't set any goals")
+	})
+}
+
+func TestSchedule(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		reminders := filepath.Join(t.TempDir(), "reminders.jsonl")
+		notifier, err := notify.NewFileNotifier(reminders)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { notifier.Close() })
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notifier)
+		c := loggedIn(t, ts, "guest")
+
+		res, body := c.do(http.MethodGet, "/schedule", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "When's your beer o'clock?", `hx-post="/schedule"`, `value="17:00"`)
+		expectNotBody(t, body, `hx-get="/countdown"`)
+
+		res, body = c.do(http.MethodPost, "/schedule", url.Values{"weekday": {"5"}, "time": {"5pm"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Time must be a time of day, like 17:00")
+
+		res, body = c.do(http.MethodPost, "/schedule", url.Values{"weekday": {"5"}, "time": {"17:00"}, "remind-before": {"30"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "Friday at 17:00", "reminding 30 minutes before", `hx-get="/countdown"`, `hx-delete="/schedule/1"`)
+
+		res, body = c.do(http.MethodPost, "/schedule", url.Values{"weekday": {"5"}, "time": {"17:00"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "Beer o&#39;clock is already then")
+
+		res, body = c.do(http.MethodGet, "/countdown", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="countdown"`, `hx-trigger="every 1s"`)
+		_, body = c.do(http.MethodGet, "/", nil, false)
+		expectBody(t, body, `hx-get="/countdown"`)
+
+		// Reminders go out in the user's own time, once each
+		c.do(http.MethodPut, "/goals", url.Values{"time-zone": {"Australia/Sydney"}}, true)
+		ctx := context.Background()
+		for _, now := range []time.Time{
+			// 16:29 in Sydney, which is too early
+			time.Date(2025, time.March, 14, 5, 29, 0, 0, time.UTC),
+			time.Date(2025, time.March, 14, 5, 30, 0, 0, time.UTC),
+			time.Date(2025, time.March, 14, 5, 31, 0, 0, time.UTC),
+			time.Date(2025, time.March, 14, 6, 0, 0, 0, time.UTC),
+			// The next week it's running late
+			time.Date(2025, time.March, 21, 6, 10, 0, 0, time.UTC),
+		} {
+			s.sendReminders(ctx, now)
+		}
+		sent, err := notify.ReadFile(reminders)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []notify.Notification{
+			{UserID: 2, Username: "guest", Subject: "Beer o'clock is coming up", Body: "It's beer o'clock at 17:00 on Friday."},
+			{UserID: 2, Username: "guest", Subject: "It's beer o'clock!", Body: "It's been beer o'clock since 17:00."},
+		}
+		if !slices.Equal(sent, want) {
+			t.Errorf("reminders: got %+v, want %+v", sent, want)
+		}
+
+		// Only your own schedule can be changed
+		other := loggedIn(t, ts, "saltytaro")
+		res, _ = other.do(http.MethodDelete, "/schedule/1", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+		res, body = c.do(http.MethodDelete, "/schedule/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "When's your beer o'clock?")
+	})
+}
+
+// Sends the app's emails to an SMTP server in the test, rather than logging them
+func withMailServer(t *testing.T, s *server) *mailtest.Server {
+	t.Helper()
+	mailServer := mailtest.NewServer(t)
+	sender, err := mail.NewSMTPSender(mailServer.Host(), mailServer.Port(), "", "", "Beer O'clock <beer@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mailer = sender
+	return mailServer
+}
+
+// The email at i of those the server was sent, read back into its parts
+func receivedEmail(t *testing.T, mailServer *mailtest.Server, i int) mailtest.Email {
+	t.Helper()
+	received := mailServer.Received()
+	if len(received) <= i {
+		t.Fatalf("got %d emails, want at least %d", len(received), i+1)
+	}
+	email, err := received[i].Email()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return email
+}
+
+func TestEmailSettings(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ts := newTestServer(t, stores)
+		c := loggedIn(t, ts, "guest")
+
+		res, body := c.do(http.MethodGet, "/email", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-put="/email"`, `name="weekly-summary" value="true" class="mr-2" checked`)
+		expectNotBody(t, body, `hx-delete="/email"`)
+
+		res, body = c.do(http.MethodPut, "/email", url.Values{"email": {"guest"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field must be an email address like someone@example.com")
+
+		res, body = c.do(http.MethodPut, "/email", url.Values{"email": {"Guest@Example.com"}, "reminders": {"true"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `value="guest@example.com"`, `name="reminders" value="true" class="mr-2" checked`, `hx-delete="/email"`)
+		expectNotBody(t, body, `name="weekly-summary" value="true" class="mr-2" checked`)
+
+		// Nobody else can use the same address
+		other := loggedIn(t, ts, "saltytaro")
+		res, body = other.do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}}, true)
+		expectStatus(t, res, http.StatusConflict)
+		expectBody(t, body, "Someone else is already using that address")
+
+		res, body = c.do(http.MethodDelete, "/email", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectNotBody(t, body, "guest@example.com", `hx-delete="/email"`)
+		res, _ = other.do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}}, true)
+		expectStatus(t, res, http.StatusOK)
+	})
+}
+
+func TestPasswordReset(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		mailServer := withMailServer(t, s)
+		loggedIn(t, ts, "guest").do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}}, true)
+
+		_, body := newTestClient(t, ts).do(http.MethodGet, "/login", nil, false)
+		expectBody(t, body, `href="/password/forgot"`)
+
+		c := newTestClient(t, ts)
+		res, body := c.do(http.MethodGet, "/password/forgot", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/password/forgot"`)
+
+		// Addresses nobody has get the same answer, without an email
+		res, body = c.do(http.MethodPost, "/password/forgot", url.Values{"email": {"nobody@example.com"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="reset-sent"`)
+		res, body = c.do(http.MethodPost, "/password/forgot", url.Values{"email": {"Guest@example.com"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="reset-sent"`)
+
+		s.sendOutbox(context.Background(), time.Now())
+		if got := len(mailServer.Received()); got != 1 {
+			t.Fatalf("got %d emails, want 1", got)
+		}
+		email := receivedEmail(t, mailServer, 0)
+		if email.Subject != "Reset your password" || mailServer.Received()[0].To[0] != "guest@example.com" {
+			t.Errorf("got email %q to %v", email.Subject, mailServer.Received()[0].To)
+		}
+		link := regexp.MustCompile(`/password/reset\?token=[A-Za-z0-9_-]+`)
+		path := link.FindString(email.Text)
+		if path == "" || link.FindString(email.HTML) != path {
+			t.Fatalf("got no reset link in %q", email.Text)
+		}
+		expectBody(t, email.Text, "Hi guest,", "for the next hour")
+
+		res, body = c.do(http.MethodGet, path, nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `hx-post="/password/reset"`)
+		res, _ = c.do(http.MethodGet, "/password/reset?token=guess", nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+
+		token := strings.TrimPrefix(path, "/password/reset?token=")
+		res, body = c.do(http.MethodPost, "/password/reset", url.Values{"token": {token}, "password": {"hunter2"}, "confirm-password": {"hunter3"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "Passwords do not match")
+		res, body = c.do(http.MethodPost, "/password/reset", url.Values{"token": {token}, "password": {"hunter2"}, "confirm-password": {"hunter2"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, `id="reset-done"`)
+
+		if res := newTestClient(t, ts).login("guest", "password"); res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("logging in with the old password: got status %d", res.StatusCode)
+		}
+		if res := newTestClient(t, ts).login("guest", "hunter2"); res.StatusCode != http.StatusSeeOther {
+			t.Errorf("logging in with the new password: got status %d", res.StatusCode)
+		}
+
+		// Links only work once
+		res, body = c.do(http.MethodPost, "/password/reset", url.Values{"token": {token}, "password": {"again"}, "confirm-password": {"again"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This link has expired or has already been used")
+		res, _ = c.do(http.MethodGet, path, nil, false)
+		expectStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func TestOutbox(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		mailServer := withMailServer(t, s)
+		ctx := context.Background()
+		now := time.Now()
+
+		if err := s.queueEmail(ctx, "guest@example.com", "Cheers", templates.ReminderEmail("guest", "Cheers", "It's beer o'clock.", "http://localhost/fridge")); err != nil {
+			t.Fatal(err)
+		}
+
+		// The server's down at first, so it's tried again after the first backoff
+		mailServer.FailNext(1)
+		s.sendOutbox(ctx, now)
+		s.sendOutbox(ctx, now.Add(outbox.FirstBackoff-time.Second))
+		if got := len(mailServer.Received()); got != 0 {
+			t.Fatalf("got %d emails before retrying, want 0", got)
+		}
+		s.sendOutbox(ctx, now.Add(outbox.FirstBackoff+time.Second))
+		s.sendOutbox(ctx, now.Add(time.Hour))
+		if got := len(mailServer.Received()); got != 1 {
+			t.Fatalf("got %d emails after retrying, want 1", got)
+		}
+		email := receivedEmail(t, mailServer, 0)
+		expectBody(t, email.Text, "Hi guest,", "It's beer o'clock.", "(http://localhost/fridge)")
+		expectBody(t, email.HTML, "<!doctype html>", `href="http://localhost/fridge"`)
+
+		// Until it's given up on
+		s.queueEmail(ctx, "nobody@example.com", "Hello?", templates.ReminderEmail("nobody", "Hello?", "Anyone there?", "http://localhost/"))
+		mailServer.FailNext(outbox.MaxAttempts)
+		for attempt := range outbox.MaxAttempts + 1 {
+			s.sendOutbox(ctx, now.Add(time.Duration(attempt)*outbox.MaxBackoff))
+		}
+		if got := len(mailServer.Received()); got != 1 {
+			t.Errorf("got %d emails after giving up, want 1", got)
+		}
+
+		// Only admins can see the outbox
+		res, _ := loggedIn(t, ts, "guest").do(http.MethodGet, "/outbox", nil, false)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body := loggedIn(t, ts, "saltytaro").do(http.MethodGet, "/outbox", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, "nobody@example.com", fmt.Sprintf("Gave up after %d attempts", outbox.MaxAttempts), "Try again later", "guest@example.com", "Sent ")
+	})
+}
+
+func TestEmailNotifications(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		mailServer := withMailServer(t, s)
+		ctx := context.Background()
+		c := loggedIn(t, ts, "guest")
+
+		c.do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}, "weekly-summary": {"true"}, "reminders": {"true"}}, true)
+		c.do(http.MethodPut, "/goals", url.Values{"weekly-limit": {"2"}, "time-zone": {"Australia/Sydney"}}, true)
+		c.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		for _, drunkAt := range []string{"2025-03-12T19:00", "2025-03-12T20:00", "2025-03-15T18:00", "2025-03-17T18:00"} {
+			c.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}, "drunk-at": {drunkAt}}, true)
+		}
+
+		// Summaries go out from 09:00 on Monday where the user is, once a week
+		for _, now := range []time.Time{
+			// 08:00 Monday in Sydney, which is too early
+			time.Date(2025, time.March, 16, 21, 0, 0, 0, time.UTC),
+			time.Date(2025, time.March, 16, 22, 30, 0, 0, time.UTC),
+			time.Date(2025, time.March, 17, 22, 30, 0, 0, time.UTC),
+		} {
+			s.sendWeeklySummaries(ctx, now)
+		}
+		s.sendOutbox(ctx, time.Now())
+		if got := len(mailServer.Received()); got != 1 {
+			t.Fatalf("got %d summaries, want 1", got)
+		}
+		email := receivedEmail(t, mailServer, 0)
+		if email.Subject != "Your week from Mon 10 Mar" {
+			t.Errorf("got subject %q", email.Subject)
+		}
+		expectBody(t, email.Text, "Hi guest, here's how last week went.", "Drinks 3\n", "Standard drinks 4.4 of 2\n", "Alcohol-free days 5\n", "That's over your weekly limit.")
+
+		// Reminders are emailed as well as sent the usual way, unless the user doesn't want them
+		c.do(http.MethodPost, "/schedule", url.Values{"weekday": {"5"}, "time": {"17:00"}}, true)
+		s.sendReminders(ctx, time.Date(2025, time.March, 21, 6, 0, 0, 0, time.UTC))
+		c.do(http.MethodPut, "/email", url.Values{"email": {"guest@example.com"}}, true)
+		s.sendReminders(ctx, time.Date(2025, time.March, 28, 6, 0, 0, 0, time.UTC))
+		s.sendWeeklySummaries(ctx, time.Date(2025, time.March, 23, 22, 30, 0, 0, time.UTC))
+		s.sendOutbox(ctx, time.Now())
+		if got := len(mailServer.Received()); got != 2 {
+			t.Fatalf("got %d emails, want 2", got)
+		}
+		email = receivedEmail(t, mailServer, 1)
+		if email.Subject != "It's beer o'clock!" {
+			t.Errorf("got subject %q", email.Subject)
+		}
+		expectBody(t, email.Text, "It's been beer o'clock since 17:00.")
+	})
+}
+
+// A webhook receiver which records what it's sent, responding with the status it's set to
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	received []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.received = append(receiver.received, receivedWebhook{header: r.Header, body: body})
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (wr *webhookReceiver) respondWith(status int) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.status = status
+}
+
+func (wr *webhookReceiver) Received() []receivedWebhook {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return slices.Clone(wr.received)
+}
+
+func TestWebhooks(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		receiver := newWebhookReceiver(t)
+		ctx := context.Background()
+		admin := loggedIn(t, ts, "saltytaro")
+
+		// Only admins can manage webhooks
+		res, _ := loggedIn(t, ts, "guest").do(http.MethodGet, "/webhooks", nil, false)
+		expectStatus(t, res, http.StatusForbidden)
+		res, _ = loggedIn(t, ts, "guest").do(http.MethodPost, "/webhooks", url.Values{"url": {receiver.URL}, "events": {"beer.created"}}, true)
+		expectStatus(t, res, http.StatusForbidden)
+
+		res, body := admin.do(http.MethodPost, "/webhooks", url.Values{"url": {receiver.URL}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "This field is required", `value="`+receiver.URL+`"`)
+		res, body = admin.do(http.MethodPost, "/webhooks", url.Values{"url": {"not a url"}, "events": {"beer.created"}}, true)
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		expectBody(t, body, "must be an http or https URL")
+
+		events := url.Values{
+			"url":    {receiver.URL + "/hook"},
+			"secret": {"shh"},
+			"events": {"beer.created", "beer.updated", "brewer.deleted", "user.deleted", "drink.logged"},
+		}
+		res, body = admin.do(http.MethodPost, "/webhooks", events, true)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, receiver.URL+"/hook", "beer.created, beer.updated, brewer.deleted, user.deleted, drink.logged", "shh")
+
+		// Without a secret, one's made up
+		res, body = admin.do(http.MethodPost, "/webhooks", url.Values{"url": {"https://lights.example.com"}, "events": {"beer.deleted"}}, true)
+		expectStatus(t, res, http.StatusOK)
+		if !regexp.MustCompile(`<code>[0-9a-f]{64}</code>`).MatchString(body) {
+			t.Errorf("got no generated secret in %s", body)
+		}
+		res, _ = admin.do(http.MethodDelete, "/webhook/2", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		res, _ = admin.do(http.MethodDelete, "/webhook/2", nil, true)
+		expectStatus(t, res, http.StatusNotFound)
+
+		admin.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}}, "7"), true)
+		beer := setScores(url.Values{"name": {"Pale Ale"}, "abv": {"5.8"}}, "7")
+		admin.do(http.MethodPut, "/beer/1", beer, true)
+		admin.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+		admin.do(http.MethodDelete, "/brewer/1", nil, true)
+		admin.do(http.MethodPost, "/user", url.Values{"username": {"newbie"}, "password": {"pw"}, "confirm-password": {"pw"}}, true)
+		admin.do(http.MethodDelete, "/user/3", nil, true)
+		admin.do(http.MethodPost, "/beer/1/drinks", url.Values{"serving-ml": {"375"}}, true)
+		admin.do(http.MethodPost, "/sessions", url.Values{"name": {"Friday"}}, true)
+		admin.do(http.MethodPost, "/session/1/drinks", url.Values{"beer-id": {"1"}, "serving-ml": {"500"}}, true)
+
+		now := time.Now()
+		s.deliverWebhooks(ctx, now)
+		received := receiver.Received()
+		if len(received) != 6 {
+			t.Fatalf("got %d deliveries, want 6", len(received))
+		}
+		for i, want := range []string{"beer.created", "beer.updated", "brewer.deleted", "user.deleted", "drink.logged", "drink.logged"} {
+			if got := received[i].header.Get(webhook.EventHeader); got != want {
+				t.Errorf("got event %s, want %s", got, want)
+			}
+			if !webhook.Verify("shh", received[i].body, received[i].header.Get(webhook.SignatureHeader)) {
+				t.Errorf("got a bad signature for %s", received[i].body)
+			}
+		}
+
+		var payload struct {
+			Event string
+			Data  struct {
+				ID       int64
+				Name     string
+				Abv      float64
+				BrewerID *int64 `json:"brewer_id"`
+				Location *string
+				Username string
+			}
+		}
+		if err := json.Unmarshal(received[1].body, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Event != "beer.updated" || payload.Data.ID != 1 || payload.Data.Name != "Pale Ale" || payload.Data.Abv != 5.8 || payload.Data.BrewerID != nil {
+			t.Errorf("got beer.updated payload %s", received[1].body)
+		}
+		json.Unmarshal(received[2].body, &payload)
+		if payload.Data.Name != "Felon's" || payload.Data.Location == nil || *payload.Data.Location != "Brisbane" {
+			t.Errorf("got brewer.deleted payload %s", received[2].body)
+		}
+		json.Unmarshal(received[3].body, &payload)
+		if payload.Data.ID != 3 || payload.Data.Username != "newbie" {
+			t.Errorf("got user.deleted payload %s", received[3].body)
+		}
+
+		var drink struct {
+			Data struct {
+				ID        int64
+				UserID    int64  `json:"user_id"`
+				BeerID    int64  `json:"beer_id"`
+				ServingMl int64  `json:"serving_ml"`
+				SessionID *int64 `json:"session_id"`
+			}
+		}
+		json.Unmarshal(received[4].body, &drink)
+		if drink.Data.ID != 1 || drink.Data.UserID != 1 || drink.Data.BeerID != 1 || drink.Data.ServingMl != 375 || drink.Data.SessionID != nil {
+			t.Errorf("got drink.logged payload %s", received[4].body)
+		}
+		json.Unmarshal(received[5].body, &drink)
+		if drink.Data.ID != 2 || drink.Data.ServingMl != 500 || drink.Data.SessionID == nil || *drink.Data.SessionID != 1 {
+			t.Errorf("got drink.logged payload for a session %s", received[5].body)
+		}
+
+		// The receiver's down at first, so it's tried again after the first backoff
+		receiver.respondWith(http.StatusInternalServerError)
+		admin.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Lager"}, "abv": {"4.5"}}, "6"), true)
+		s.deliverWebhooks(ctx, now)
+		receiver.respondWith(http.StatusOK)
+		s.deliverWebhooks(ctx, now.Add(outbox.FirstBackoff-time.Second))
+		if got := len(receiver.Received()); got != 7 {
+			t.Fatalf("got %d deliveries before retrying, want 7", got)
+		}
+		s.deliverWebhooks(ctx, now.Add(outbox.FirstBackoff+time.Second))
+		s.deliverWebhooks(ctx, now.Add(time.Hour))
+		received = receiver.Received()
+		if len(received) != 8 || !bytes.Equal(received[6].body, received[7].body) || received[6].header.Get(webhook.DeliveryHeader) != received[7].header.Get(webhook.DeliveryHeader) {
+			t.Fatalf("got %d deliveries after retrying, want the same one again", len(received))
+		}
+
+		// Until it's given up on
+		receiver.respondWith(http.StatusBadGateway)
+		admin.do(http.MethodPut, "/beer/1", beer, true)
+		for attempt := range webhooks.MaxAttempts + 1 {
+			s.deliverWebhooks(ctx, now.Add(time.Duration(attempt)*outbox.MaxBackoff))
+		}
+		if got := len(receiver.Received()); got != 8+webhooks.MaxAttempts {
+			t.Errorf("got %d deliveries after giving up, want %d", got, 8+webhooks.MaxAttempts)
+		}
+
+		res, _ = loggedIn(t, ts, "guest").do(http.MethodGet, "/webhooks/deliveries", nil, false)
+		expectStatus(t, res, http.StatusForbidden)
+		res, body = admin.do(http.MethodGet, "/webhooks/deliveries", nil, false)
+		expectStatus(t, res, http.StatusOK)
+		expectBody(t, body, fmt.Sprintf("Gave up after %d attempts", webhooks.MaxAttempts), "502", "webhook responded with 502 Bad Gateway", "Delivered ", "user.deleted")
+
+		// Removing a webhook stops events going to it
+		res, body = admin.do(http.MethodDelete, "/webhook/1", nil, true)
+		expectStatus(t, res, http.StatusOK)
+		expectNotBody(t, body, receiver.URL)
+		admin.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Stout"}, "abv": {"6"}}, "8"), true)
+		s.deliverWebhooks(ctx, now.Add(24*time.Hour))
+		if got := len(receiver.Received()); got != 8+webhooks.MaxAttempts {
+			t.Errorf("got %d deliveries after removing the webhook, want %d", got, 8+webhooks.MaxAttempts)
+		}
+	})
+}
+
+// Opens the user's event stream, returning a reader of its lines once it's subscribed
+func (c *testClient) events() *bufio.Reader {
+	c.t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server.URL+"/events", nil)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.t.Cleanup(func() { res.Body.Close() })
+	expectStatus(c.t, res, http.StatusOK)
+	if got := res.Header.Get("Content-Type"); got != "text/event-stream" {
+		c.t.Fatalf("got content type %s, want text/event-stream", got)
+	}
+
+	// The first message is sent once the stream's subscribed
+	stream := bufio.NewReader(res.Body)
+	if got := nextEvent(c.t, stream); got != "retry: 5000" {
+		c.t.Fatalf("got first message %q", got)
+	}
+	return stream
+}
+
+// Reads the next message from an event stream, with its lines joined by newlines
+func nextEvent(t *testing.T, stream *bufio.Reader) string {
+	t.Helper()
+
+	lines := []string{}
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestLiveUpdates(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		s, ts := newTestApp(t, stores, blobs.NewMemoryBlobStore(), notify.NewLogNotifier(log.New(io.Discard, "", 0)))
+		admin := loggedIn(t, ts, "saltytaro")
+		guest := loggedIn(t, ts, "guest")
+
+		res, _ := newTestClient(t, ts).do(http.MethodGet, "/events", nil, false)
+		expectStatus(t, res, http.StatusSeeOther)
+
+		// The lists listen for changes, from outside the part searches replace
+		_, body := guest.do(http.MethodGet, "/beers", nil, true)
+		expectBody(t, body, `hx-ext="sse"`, `sse-connect="/events"`, `sse-swap="beers"`, `hx-swap="none"`)
+		_, body = guest.do(http.MethodPost, "/beer/search", url.Values{"q": {"pale"}}, true)
+		expectNotBody(t, body, `sse-connect`)
+		_, body = guest.do(http.MethodGet, "/brewers", nil, true)
+		expectBody(t, body, `sse-connect="/events"`, `sse-swap="brewers"`, `hx-swap="none"`)
+
+		// Everyone's told about changes, whoever made them, with just what changed to swap
+		guestEvents := guest.events()
+		adminEvents := admin.events()
+		for _, step := range []struct {
+			change func()
+			topic  string
+			data   []string
+		}{
+			{
+				func() {
+					admin.do(http.MethodPost, "/brewer", url.Values{"name": {"Felon's"}, "location": {"Brisbane"}}, true)
+				},
+				"brewers",
+				[]string{`id="brewer-1" hx-swap-oob="delete"`, `hx-swap-oob="beforeend:#brewers-list"`, "Felon&#39;s"},
+			},
+			{
+				func() {
+					admin.do(http.MethodPost, "/beer", setScores(url.Values{"name": {"Pale"}, "abv": {"5"}, "brewer-id": {"1"}}, "7"), true)
+				},
+				"beers",
+				[]string{`id="beer-1" hx-swap-oob="delete"`, `hx-swap-oob="beforeend:#beers-list"`, "Pale"},
+			},
+			{
+				func() {
+					guest.do(http.MethodPut, "/beer/1", setScores(url.Values{"name": {"Pale Ale"}, "abv": {"5.8"}, "brewer-id": {"1"}}, "7"), true)
+				},
+				"beers",
+				[]string{`hx-swap-oob="innerHTML:#beer-1"`, "Pale Ale", "ABV: 5.80%"},
+			},
+			{
+				func() { guest.do(http.MethodDelete, "/beer/1", nil, true) },
+				"beers",
+				[]string{`id="beer-1" hx-swap-oob="delete"`},
+			},
+			{
+				func() { guest.do(http.MethodPost, "/beer/1/restore", nil, true) },
+				"beers",
+				[]string{`hx-swap-oob="beforeend:#beers-list"`, "Pale Ale"},
+			},
+			{
+				func() { admin.do(http.MethodDelete, "/brewer/1", nil, true) },
+				"brewers",
+				[]string{`id="brewer-1" hx-swap-oob="delete"`},
+			},
+		} {
+			step.change()
+			for _, stream := range []*bufio.Reader{guestEvents, adminEvents} {
+				got := nextEvent(t, stream)
+				if !strings.HasPrefix(got, "event: "+step.topic+"\ndata: ") {
+					t.Errorf("got event %q, want a %s event", got, step.topic)
+				}
+				expectBody(t, got, step.data...)
+			}
+		}
+
+		// Changes which don't go through aren't sent
+		admin.do(http.MethodPost, "/beer", setScores(url.Values{"name": {""}, "abv": {"5"}}, "7"), true)
+
+		// Streams end when the server shuts down
+		s.events.Close()
+		if line, err := guestEvents.ReadString('\n'); err != io.EOF {
+			t.Errorf("got %q, %v after shutting down, want the end of the stream", line, err)
+		}
+	})
+}
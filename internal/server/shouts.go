@@ -0,0 +1,237 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/shouts"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/templates"
+)
+
+// Renders who's shouted who in the session, with the form for adding a round. formData is nil
+// for a new form, which suggests whoever's next buying for everyone.
+func (s *server) renderShouts(w http.ResponseWriter, r *http.Request, session db.DrinkingSession, formData *templates.RoundFormData, validationErrors map[string]string, status int) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data := templates.ShoutData{Session: session, Location: location}
+	data.Participants, err = s.drinkSessionStore.GetParticipants(r.Context(), session.ID)
+	if err == nil {
+		data.Rounds, err = s.shoutStore.GetRounds(r.Context(), session.ID)
+	}
+	var recipients []db.GetSessionRoundRecipientsRow
+	if err == nil {
+		recipients, err = s.shoutStore.GetRecipients(r.Context(), session.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting rounds: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	members := shouts.Members(data.Participants, data.Rounds, recipients)
+	data.Recipients = shouts.RecipientsByRound(recipients)
+	data.Balances = shouts.Balances(members, data.Rounds, recipients)
+	data.Next, data.HasNext = shouts.NextToBuy(data.Balances)
+	data.Owings = shouts.Owings(members, data.Rounds, recipients)
+	data.Settlements = shouts.SettleUp(members, data.Rounds, recipients)
+
+	if formData == nil {
+		formData = &templates.RoundFormData{Currency: defaultCurrency}
+		if data.HasNext {
+			formData.BoughtBy = data.Next.UserID
+		}
+		for _, p := range data.Participants {
+			formData.Recipients = append(formData.Recipients, p.UserID)
+		}
+	}
+	data.Form = *formData
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.Shouts(data, validationErrors), session.Name)
+}
+
+// Gets the session with the id in the path if the user's in it, responding with an error if not
+func (s *server) pathDrinkSession(w http.ResponseWriter, r *http.Request) (db.DrinkingSession, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.DrinkingSession{}, false
+	}
+
+	session, err := s.getUserDrinkSession(r.Context(), int64(id), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinking session: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return db.DrinkingSession{}, false
+	}
+	return session, true
+}
+
+// GET /session/{id}/shouts
+func (s *server) shoutsHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.pathDrinkSession(w, r)
+	if !ok {
+		return
+	}
+
+	s.renderShouts(w, r, session, nil, nil, http.StatusOK)
+}
+
+// POST /session/{id}/rounds
+func (s *server) addRoundHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.pathDrinkSession(w, r)
+	if !ok {
+		return
+	}
+
+	s.logger.Printf("Adding round to drinking session with id: %d", session.ID)
+
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	participants, err := s.drinkSessionStore.GetParticipants(r.Context(), session.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting session participants: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	inSession := func(userId int64) bool {
+		return slices.ContainsFunc(participants, func(p db.GetSessionParticipantsRow) bool { return p.UserID == userId })
+	}
+
+	// Rounds can only be bought by and for people in the session, and were bought now unless a
+	// time is given
+	formData := templates.RoundFormData{
+		Cost:     r.FormValue("cost"),
+		Currency: r.FormValue("currency"),
+		BoughtAt: r.FormValue("bought-at"),
+	}
+	params := db.AddRoundParams{SessionID: session.ID, BoughtAt: time.Now()}
+	validationErrors := map[string]string{}
+
+	if boughtBy, err := strconv.ParseInt(r.FormValue("bought-by"), 10, 64); err != nil || !inSession(boughtBy) {
+		validationErrors["bought-by"] = "Bought by must be someone in the session"
+	} else {
+		params.BoughtBy = boughtBy
+		formData.BoughtBy = boughtBy
+	}
+	for _, value := range r.Form["recipients"] {
+		userId, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || !inSession(userId) {
+			validationErrors["recipients"] = "Everyone it's for must be in the session"
+			continue
+		}
+		formData.Recipients = append(formData.Recipients, userId)
+	}
+	if formData.Cost != "" {
+		cost, err := strconv.ParseFloat(formData.Cost, 64)
+		if err != nil || cost < 0 {
+			validationErrors["cost"] = "Cost must be a number of at least 0"
+		} else {
+			params.Cost = sql.NullFloat64{Valid: true, Float64: cost}
+		}
+	}
+	params.Currency = strings.ToUpper(strings.TrimSpace(formData.Currency))
+	if params.Currency == "" {
+		params.Currency = defaultCurrency
+	} else if !stock.ValidCurrency(params.Currency) {
+		validationErrors["currency"] = "Currency must be a three letter code, like AUD"
+	}
+	if formData.BoughtAt != "" {
+		boughtAt, err := time.ParseInLocation(drunkAtLayout, formData.BoughtAt, location)
+		if err != nil {
+			validationErrors["bought-at"] = "When must be a date and time"
+		} else {
+			params.BoughtAt = boughtAt
+		}
+	}
+	if len(validationErrors) > 0 {
+		s.renderShouts(w, r, session, &formData, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if _, err := s.shoutStore.AddRound(r.Context(), params, formData.Recipients); err != nil {
+		errMsg := fmt.Sprintf("Error when adding round: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderShouts(w, r, session, &formData, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderShouts(w, r, session, &formData, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		case drinksessions.ErrSessionNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderShouts(w, r, session, nil, nil, http.StatusOK)
+}
+
+// DELETE /round/{id}
+func (s *server) deleteRoundHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting round with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Anyone in the session can fix its rounds, and to anyone else they aren't there
+	var session db.DrinkingSession
+	round, err := s.shoutStore.GetRound(r.Context(), int64(id))
+	if err == nil {
+		session, err = s.getUserDrinkSession(r.Context(), round.SessionID, currentUserId(r))
+		if _, ok := err.(drinksessions.ErrSessionNotFound); ok {
+			err = shouts.ErrRoundNotFound{ID: round.ID}
+		}
+	}
+	if err == nil {
+		_, err = s.shoutStore.DeleteRound(r.Context(), round.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting round: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case shouts.ErrRoundNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderShouts(w, r, session, nil, nil, http.StatusOK)
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/social"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/templates"
+)
+
+// How many activities are loaded at a time
+const activityPageSize = 20
+
+// Records what the current user did for the feed. The change has already happened, so failing to
+// record it is only logged.
+func (s *server) recordActivity(r *http.Request, kind string, beerId sql.NullInt64, brewerId sql.NullInt64) {
+	_, err := s.socialStore.AddActivity(r.Context(), db.AddActivityParams{
+		UserID:   currentUserId(r),
+		Kind:     kind,
+		BeerID:   beerId,
+		BrewerID: brewerId,
+	})
+	if err != nil {
+		s.logger.Printf("Error when recording %s activity: %v", kind, err)
+	}
+}
+
+// The activity id the page starts before, from the before query parameter, which is 0 for the
+// first page
+func pageBefore(r *http.Request) (int64, error) {
+	before := r.URL.Query().Get("before")
+	if before == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(before, 10, 64)
+}
+
+// Renders a page of activity, linking to the next from the given path if it's full
+func (s *server) renderActivities(w http.ResponseWriter, r *http.Request, rows []db.GetFeedRow, path string, empty string) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	page := templates.ActivityPage{Activities: rows, Location: location, Empty: empty}
+	if len(rows) == activityPageSize {
+		page.NextURL = fmt.Sprintf("%s?before=%d", path, rows[len(rows)-1].Activity.ID)
+	}
+	renderTemplate(w, r, templates.Activities(page))
+}
+
+// The user from the id in the path, responding with an error if there isn't one
+func (s *server) pathUser(w http.ResponseWriter, r *http.Request) (db.User, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.User{}, false
+	}
+
+	user, err := s.userStore.GetUserById(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case users.ErrUserNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return db.User{}, false
+	}
+	return user, true
+}
+
+// GET /feed
+func (s *server) feedHandler(w http.ResponseWriter, r *http.Request) {
+	before, err := pageBefore(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting before to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	rows, err := s.socialStore.GetFeed(r.Context(), currentUserId(r), before, activityPageSize)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting feed: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.renderActivities(w, r, rows, "/feed", "Nothing yet from the people you follow")
+}
+
+// GET /people
+func (s *server) peopleHandler(w http.ResponseWriter, r *http.Request) {
+	data := templates.PeopleData{UserID: currentUserId(r), Following: map[int64]bool{}}
+	var err error
+	data.Users, err = s.userStore.GetUsers(r.Context())
+	var following []db.GetFollowingRow
+	if err == nil {
+		following, err = s.socialStore.GetFollowing(r.Context(), data.UserID)
+	}
+	if err == nil {
+		data.Privacy, err = s.socialStore.GetPrivacy(r.Context(), data.UserID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting people: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	for _, f := range following {
+		data.Following[f.ID] = true
+	}
+
+	renderTemplate(w, r, templates.People(data), "People")
+}
+
+// GET /people/{id}
+func (s *server) personHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	data := templates.PersonData{User: user, Self: user.ID == currentUserId(r)}
+	var err error
+	data.Followers, err = s.socialStore.GetFollowers(r.Context(), user.ID)
+	if err == nil {
+		data.Following, err = s.socialStore.GetFollowing(r.Context(), user.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting follows: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	for _, follower := range data.Followers {
+		if follower.ID == currentUserId(r) {
+			data.Followed = true
+		}
+	}
+
+	renderTemplate(w, r, templates.Person(data), user.Username)
+}
+
+// GET /people/{id}/activity
+func (s *server) personActivityHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	before, err := pageBefore(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting before to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	activities, err := s.socialStore.GetUserActivities(r.Context(), currentUserId(r), user.ID, before, activityPageSize)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting activities: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	rows := make([]db.GetFeedRow, 0, len(activities))
+	for _, activity := range activities {
+		rows = append(rows, db.GetFeedRow(activity))
+	}
+
+	s.renderActivities(w, r, rows, fmt.Sprintf("/people/%d/activity", user.ID), "Nothing to see yet")
+}
+
+// POST /people/{id}/follow
+func (s *server) followHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.socialStore.Follow(r.Context(), currentUserId(r), user.ID); err != nil {
+		errMsg := fmt.Sprintf("Error when following user: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case store.ErrInvalidField:
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		case users.ErrUserNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.FollowButton(user.ID, true))
+}
+
+// DELETE /people/{id}/follow
+func (s *server) unfollowHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.socialStore.Unfollow(r.Context(), currentUserId(r), user.ID); err != nil {
+		errMsg := fmt.Sprintf("Error when unfollowing user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.FollowButton(user.ID, false))
+}
+
+// PUT /privacy
+func (s *server) setPrivacyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.logger.Printf("Error when parsing form: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	params := db.SetPrivacyParams{UserID: currentUserId(r), Visibility: r.FormValue("visibility")}
+	privacy, err := s.socialStore.SetPrivacy(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting privacy: %v", err)
+		s.logger.Print(errMsg)
+		validationErrors := map[string]string{}
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			validationErrors[err.Field] = "This field is required"
+		case store.ErrInvalidField:
+			validationErrors[err.Field] = "This field " + err.Reason
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.PrivacyForm(db.UserPrivacy{Visibility: social.Public}, validationErrors))
+		return
+	}
+
+	renderTemplate(w, r, templates.PrivacyForm(privacy, nil))
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/budgets"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/templates"
+)
+
+// The currency prices and budgets start in, unless one's been chosen
+const defaultCurrency = "AUD"
+
+// How many months of spending are shown, including this one
+const spendingMonths = 12
+
+// The user's budget, or the zero budget if they haven't set one
+func (s *server) getBudget(r *http.Request) (db.Budget, error) {
+	budget, err := s.budgetStore.GetBudget(r.Context(), currentUserId(r))
+	if _, ok := err.(budgets.ErrBudgetNotFound); ok {
+		return db.Budget{}, nil
+	}
+	return budget, err
+}
+
+// What the user has spent this month in the currency of their budget
+func (s *server) getMonthSpent(r *http.Request, budget db.Budget) (float64, error) {
+	if budget.Currency == "" {
+		return 0, nil
+	}
+	spent, err := s.stockStore.GetUserSpending(r.Context(), currentUserId(r), stock.MonthStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	return spent[budget.Currency], nil
+}
+
+// Reads the form for setting a budget, returning what's wrong with it keyed by field
+func parseBudget(r *http.Request) (db.SetBudgetParams, map[string]string) {
+	params := db.SetBudgetParams{UserID: currentUserId(r)}
+	validationErrors := make(map[string]string)
+
+	if amount, err := strconv.ParseFloat(r.FormValue("amount"), 64); err != nil || amount <= 0 {
+		validationErrors["amount"] = "Budget must be a number more than 0"
+	} else {
+		params.Amount = amount
+	}
+
+	params.Currency = strings.ToUpper(strings.TrimSpace(r.FormValue("currency")))
+	if !stock.ValidCurrency(params.Currency) {
+		validationErrors["currency"] = "Currency must be a three letter code, like AUD"
+	}
+
+	return params, validationErrors
+}
+
+// Renders the budget form with how much of the budget has gone this month
+func (s *server) renderBudget(w http.ResponseWriter, r *http.Request, budget db.Budget, formData db.SetBudgetParams, validationErrors map[string]string) {
+	spent, err := s.getMonthSpent(r, budget)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting spending: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	renderTemplate(w, r, templates.BudgetForm(budget, spent, formData, validationErrors))
+}
+
+// The form for setting the budget as it starts out
+func newBudgetForm(budget db.Budget) db.SetBudgetParams {
+	if budget.Currency == "" {
+		return db.SetBudgetParams{Currency: defaultCurrency}
+	}
+	return db.SetBudgetParams{Amount: budget.Amount, Currency: budget.Currency}
+}
+
+// GET /spending
+func (s *server) spendingHandler(w http.ResponseWriter, r *http.Request) {
+	since := stock.MonthStart(time.Now()).AddDate(0, 1-spendingMonths, 0)
+	spending := make(map[stock.GroupBy][]stock.Spending)
+	for _, groupBy := range []stock.GroupBy{stock.ByUser, stock.ByBrewer, stock.ByStyle} {
+		totals, err := s.stockStore.GetSpending(r.Context(), groupBy, since)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting spending: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		spending[groupBy] = totals
+	}
+
+	budget, err := s.getBudget(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting budget: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	spent, err := s.getMonthSpent(r, budget)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting spending: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Spending(spending[stock.ByUser], spending[stock.ByBrewer], spending[stock.ByStyle], budget, spent, newBudgetForm(budget)), "Spending")
+}
+
+// PUT /budget
+func (s *server) setBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Setting budget")
+
+	current, err := s.getBudget(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting budget: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	params, validationErrors := parseBudget(r)
+	if len(validationErrors) > 0 {
+		s.renderBudget(w, r, current, params, validationErrors)
+		return
+	}
+
+	budget, err := s.budgetStore.SetBudget(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when setting budget: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderBudget(w, r, current, params, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)})
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderBudget(w, r, budget, newBudgetForm(budget), nil)
+}
+
+// DELETE /budget
+func (s *server) deleteBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting budget")
+
+	_, err := s.budgetStore.DeleteBudget(r.Context(), currentUserId(r))
+	switch err.(type) {
+	case nil, budgets.ErrBudgetNotFound:
+	default:
+		errMsg := fmt.Sprintf("Error when deleting budget: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	s.renderBudget(w, r, db.Budget{}, newBudgetForm(db.Budget{}), nil)
+}
+
+// The columns of the spending export
+var purchaseColumns = []string{
+	"purchased_on", "beer", "brewer", "style", "abv", "bought_by", "quantity", "container_ml",
+	"price", "currency", "total", "standard_drinks", "cost_per_standard_drink",
+}
+
+func purchaseRecord(p db.GetPurchasesRow) []string {
+	standardDrinks := float64(p.Stock.Bought) * drinks.StandardDrinks(float64(p.Stock.ContainerMl), p.Abv)
+	record := []string{
+		p.Stock.PurchasedOn.Format(time.DateOnly),
+		p.BeerName,
+		p.BrewerName.String,
+		p.Style.String,
+		strconv.FormatFloat(p.Abv, 'f', -1, 64),
+		p.Username.String,
+		strconv.FormatInt(p.Stock.Bought, 10),
+		strconv.FormatInt(p.Stock.ContainerMl, 10),
+		"", "", "",
+		strconv.FormatFloat(standardDrinks, 'f', 2, 64),
+		"",
+	}
+	// Purchases without a price are still exported, with the prices left blank
+	if p.Stock.Price.Valid {
+		total := float64(p.Stock.Bought) * p.Stock.Price.Float64
+		record[8] = strconv.FormatFloat(p.Stock.Price.Float64, 'f', 2, 64)
+		record[9] = p.Stock.Currency
+		record[10] = strconv.FormatFloat(total, 'f', 2, 64)
+		if cost, ok := drinks.CostPerStandardDrink(total, standardDrinks); ok {
+			record[12] = strconv.FormatFloat(cost, 'f', 2, 64)
+		}
+	}
+	return record
+}
+
+// GET /spending.csv
+func (s *server) exportSpendingHandler(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if formSince := r.FormValue("since"); formSince != "" {
+		var err error
+		since, err = time.Parse(time.DateOnly, formSince)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("since must be a date: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	purchases, err := s.stockStore.GetPurchases(r.Context(), since)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting purchases: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="spending.csv"`)
+	out := csv.NewWriter(w)
+	out.Write(purchaseColumns)
+	for _, p := range purchases {
+		out.Write(purchaseRecord(p))
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		s.logger.Printf("Error when writing spending export: %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/drinklog"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/templates"
+)
+
+// How many weeks the stats cover unless a range is chosen, including this one
+const defaultStatsWeeks = 12
+
+// How many styles and brewers are in the top lists
+const topResults = 5
+
+// How the time a drink was drunk comes from the form, as a datetime-local input gives it
+const drunkAtLayout = "2006-01-02T15:04"
+
+// Today where the user is, as a day as store.Day gives them
+func today(location *time.Location) time.Time {
+	return store.Day(time.Now().In(location))
+}
+
+// Reads the range of days the stats are for from the query, from the Monday defaultStatsWeeks
+// weeks ago up to today unless they're given. Both ends are included.
+func parseStatsRange(r *http.Request, location *time.Location) (time.Time, time.Time, map[string]string) {
+	validationErrors := make(map[string]string)
+
+	to := today(location)
+	if formTo := r.FormValue("to"); formTo != "" {
+		parsed, err := time.Parse(time.DateOnly, formTo)
+		if err != nil {
+			validationErrors["to"] = "To must be a date"
+		} else {
+			to = parsed
+		}
+	}
+
+	from := drinklog.WeekStart(to).AddDate(0, 0, -7*(defaultStatsWeeks-1))
+	if formFrom := r.FormValue("from"); formFrom != "" {
+		parsed, err := time.Parse(time.DateOnly, formFrom)
+		if err != nil {
+			validationErrors["from"] = "From must be a date"
+		} else {
+			from = parsed
+		}
+	}
+
+	if len(validationErrors) == 0 && to.Before(from) {
+		validationErrors["to"] = "To must not be before from"
+	}
+	return from, to, validationErrors
+}
+
+// GET /stats
+func (s *server) statsHandler(w http.ResponseWriter, r *http.Request) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	from, to, validationErrors := parseStatsRange(r, location)
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.Stats(templates.StatsData{From: from, To: to}, validationErrors), "Stats")
+		return
+	}
+
+	ctx, userId, until := r.Context(), currentUserId(r), to.AddDate(0, 0, 1)
+	data := templates.StatsData{From: from, To: to}
+
+	data.Days, err = s.drinkStore.GetDrinksPerDay(ctx, userId, from, until)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinks per day: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Abvs, err = s.drinkStore.GetAbvDistribution(ctx, userId, from, until)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting ABV distribution: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Styles, err = s.drinkStore.GetTopStyles(ctx, userId, from, until, topResults)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting top styles: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Brewers, err = s.drinkStore.GetTopBrewers(ctx, userId, from, until, topResults)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting top brewers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Ratings, err = s.drinkStore.GetRatingHistogram(ctx, userId, from, until)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting rating histogram: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data.Drinks, err = s.drinkStore.GetUserDrinks(ctx, userId, from, until)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting drinks: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Stats(data, nil), "Stats")
+}
+
+// Reads the form for logging a drink, returning what's wrong with it keyed by field. It was drunk
+// now unless a time is given, which is in the location.
+func parseDrink(r *http.Request, location *time.Location) (db.AddDrinkParams, map[string]string) {
+	params := db.AddDrinkParams{UserID: currentUserId(r), DrunkAt: time.Now().In(location)}
+	validationErrors := make(map[string]string)
+
+	if servingMl, err := strconv.ParseInt(r.FormValue("serving-ml"), 10, 64); err != nil || servingMl < 1 {
+		validationErrors["serving-ml"] = "Size must be a whole number of millilitres"
+	} else {
+		params.ServingMl = servingMl
+	}
+
+	if formDrunkAt := r.FormValue("drunk-at"); formDrunkAt != "" {
+		drunkAt, err := time.ParseInLocation(drunkAtLayout, formDrunkAt, location)
+		if err != nil {
+			validationErrors["drunk-at"] = "When must be a date and time"
+		} else {
+			params.DrunkAt = drunkAt
+		}
+	}
+
+	return params, validationErrors
+}
+
+// POST /beer/{id}/drinks
+func (s *server) logDrinkHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	beerId := int64(id)
+
+	s.logger.Printf("Logging a drink of beer with id: %d", beerId)
+
+	userGoals, err := s.getGoals(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	formDrunkAt := r.FormValue("drunk-at")
+	params, validationErrors := parseDrink(r, goals.Location(userGoals, s.location))
+	params.BeerID = beerId
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.LogDrink(beerId, params.ServingMl, formDrunkAt, validationErrors, db.Drink{}, ""))
+		return
+	}
+
+	// Users who asked to be stopped going over their goals have to confirm the drink that does it
+	if userGoals.ConfirmOverLimit && r.FormValue("confirm") != "true" {
+		beer, err := s.beerStore.GetBeer(r.Context(), beerId)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting beer: %v", err)
+			s.logger.Print(errMsg)
+			switch err.(type) {
+			case beers.ErrBeerNotFound:
+				http.Error(w, errMsg, http.StatusNotFound)
+			default:
+				http.Error(w, errMsg, http.StatusInternalServerError)
+			}
+			return
+		}
+
+		progress, err := s.getProgress(r, userGoals, params.DrunkAt)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting progress: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+
+		standardDrinks := drinks.StandardDrinks(float64(params.ServingMl), beer.Abv)
+		if goals.WouldExceed(progress, standardDrinks) {
+			w.WriteHeader(http.StatusConflict)
+			renderTemplate(w, r, templates.LogDrink(beerId, params.ServingMl, formDrunkAt, nil, db.Drink{}, overLimitWarning(progress, standardDrinks)))
+			return
+		}
+	}
+
+	drink, err := s.drinkStore.AddDrink(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when logging drink: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderTemplate(w, r, templates.LogDrink(beerId, params.ServingMl, formDrunkAt, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, db.Drink{}, ""))
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.emitWebhook(r.Context(), webhooks.DrinkLogged, drinkWebhookData(drink, sql.NullInt64{}))
+	renderTemplate(w, r, templates.LogDrink(beerId, drink.ServingMl, "", nil, drink, ""))
+}
+
+// Logs that the user drank the beer taken out of the fridge, a whole container of it
+func (s *server) logTakenDrink(r *http.Request, taken db.Stock) error {
+	location, err := s.userLocation(r)
+	if err != nil {
+		return err
+	}
+	drink, err := s.drinkStore.AddDrink(r.Context(), db.AddDrinkParams{
+		UserID:    currentUserId(r),
+		BeerID:    taken.BeerID,
+		ServingMl: taken.ContainerMl,
+		DrunkAt:   time.Now().In(location),
+	})
+	if err != nil {
+		return err
+	}
+	s.emitWebhook(r.Context(), webhooks.DrinkLogged, drinkWebhookData(drink, sql.NullInt64{}))
+	return nil
+}
+
+// DELETE /drink/{id}
+func (s *server) deleteDrinkHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting drink with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Users can only delete their own drinks, and other users' are treated as not being there
+	drink, err := s.drinkStore.GetDrink(r.Context(), int64(id))
+	if err == nil && drink.UserID != currentUserId(r) {
+		err = drinklog.ErrDrinkNotFound{ID: drink.ID}
+	}
+	if err == nil {
+		_, err = s.drinkStore.DeleteDrink(r.Context(), drink.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting drink: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case drinklog.ErrDrinkNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Return nothing so the drink is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
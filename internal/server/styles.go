@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/templates"
+)
+
+// How many styles to suggest while typing a style
+const maxStyleSuggestions = 10
+
+// GET /styles/search
+func (s *server) searchStylesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.FormValue("style")
+	if query == "" {
+		renderTemplate(w, r, templates.StyleOptions(nil))
+		return
+	}
+
+	found, err := s.styleStore.SearchStyles(r.Context(), query, maxStyleSuggestions)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when searching styles: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.StyleOptions(found))
+}
+
+// GET /styles/check
+//
+// Describes the chosen style in the beer form, warning if the ABV is outside the style's range, or
+// suggests a style if what was typed isn't one
+func (s *server) checkStyleHandler(w http.ResponseWriter, r *http.Request) {
+	formStyle := r.FormValue("style")
+	if formStyle == "" {
+		return
+	}
+
+	abv, err := strconv.ParseFloat(r.FormValue("abv"), 64)
+	hasAbv := err == nil
+
+	style, err := s.styleStore.GetStyleByName(r.Context(), formStyle)
+	if err == nil {
+		renderTemplate(w, r, templates.StyleCheck(style, abv, hasAbv))
+		return
+	}
+	if _, ok := err.(styles.ErrStyleNotFound); !ok {
+		errMsg := fmt.Sprintf("Error when getting style: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	all, err := s.styleStore.GetStyles(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting styles: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	suggestion, _ := styles.Match(all, formStyle)
+	renderTemplate(w, r, templates.UnknownStyle(suggestion))
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/tags"
+	"beer_oclock/internal/templates"
+)
+
+// Reads the tags picked in the beer form along with any new ones typed in, returning a validation
+// error if a new one isn't a valid tag
+func parseTags(r *http.Request) ([]string, string) {
+	names := tags.Parse(strings.Join(append(r.Form["tags"], r.FormValue("new-tags")), ","))
+	for _, name := range names {
+		if err := tags.ValidateName(name); err != nil {
+			if err, ok := err.(store.ErrInvalidField); ok {
+				return names, fmt.Sprintf("Tags %s: %q", err.Reason, name)
+			}
+			return names, err.Error()
+		}
+	}
+	return names, ""
+}
+
+// GET /tags
+func (s *server) tagCloudHandler(w http.ResponseWriter, r *http.Request) {
+	counts, err := s.tagStore.GetTagCounts(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tag counts: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.TagCloud(counts), "Tags")
+}
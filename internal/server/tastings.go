@@ -0,0 +1,467 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/tastings"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/templates"
+)
+
+// The tasting from the id in the path, responding with an error if there isn't one
+func (s *server) pathTasting(w http.ResponseWriter, r *http.Request) (db.Tasting, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.Tasting{}, false
+	}
+
+	tasting, err := s.tastingStore.GetTasting(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tasting: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case tastings.ErrTastingNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return db.Tasting{}, false
+	}
+	return tasting, true
+}
+
+// The tasting from the path if the user can run it, which is whoever organised it or an admin.
+// Responds with an error if not.
+func (s *server) organisedTasting(w http.ResponseWriter, r *http.Request) (db.Tasting, bool) {
+	tasting, ok := s.pathTasting(w, r)
+	if !ok {
+		return db.Tasting{}, false
+	}
+	user, err := s.userStore.GetUserById(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.Tasting{}, false
+	}
+	if !canOrganise(tasting, user) {
+		errMsg := fmt.Sprintf("Only whoever organised tasting %d can change it", tasting.ID)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusForbidden)
+		return db.Tasting{}, false
+	}
+	return tasting, true
+}
+
+func canOrganise(tasting db.Tasting, user db.User) bool {
+	return user.IsAdmin || (tasting.OrganiserID.Valid && tasting.OrganiserID.Int64 == user.ID)
+}
+
+// The pour number from the path, responding with an error if it isn't a number
+func (s *server) pathPourNumber(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	number, err := strconv.ParseInt(r.PathValue("number"), 10, 64)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting pour number to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return 0, false
+	}
+	return number, true
+}
+
+// Renders the tasting. Until it's revealed only the organiser sees what's in each pour, and
+// everyone else only sees their own scores. scoredPour is the pour the validation errors are for,
+// if they're from a scorecard.
+func (s *server) renderTasting(w http.ResponseWriter, r *http.Request, tasting db.Tasting, scoredPour int64, validationErrors map[string]string, status int) {
+	ctx := r.Context()
+	user, err := s.userStore.GetUserById(ctx, currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	data := templates.TastingData{
+		Tasting:     tasting,
+		Location:    location,
+		CanOrganise: canOrganise(tasting, user),
+		MyScores:    map[int64]scorecards.Scores{},
+		Scored:      map[int64]int{},
+		ScoredPour:  scoredPour,
+	}
+	if tasting.OrganiserID.Valid {
+		if organiser, err := s.userStore.GetUserById(ctx, tasting.OrganiserID.Int64); err == nil {
+			data.Organiser = organiser.Username
+		}
+	}
+	data.Pours, err = s.tastingStore.GetPours(ctx, tasting.ID)
+	var cards []db.GetTastingScorecardsRow
+	if err == nil {
+		cards, err = s.tastingStore.GetScorecards(ctx, tasting.ID)
+	}
+	if err == nil {
+		data.Weights, err = s.scorecardStore.GetWeights(ctx)
+	}
+	if err == nil && data.CanOrganise && !tasting.RevealedAt.Valid {
+		data.Beers, err = s.beerStore.GetBeers(ctx)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tasting: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	for _, card := range cards {
+		data.Scored[card.TastingScorecard.Number]++
+		if card.TastingScorecard.UserID == user.ID {
+			data.MyScores[card.TastingScorecard.Number] = tastings.ScoresOf(card.TastingScorecard)
+		}
+	}
+	if tasting.RevealedAt.Valid {
+		data.Results = tastings.Rank(data.Pours, cards, data.Weights)
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.Tasting(data, validationErrors), tasting.Name)
+}
+
+// Renders every tasting with the form for organising another
+func (s *server) renderTastings(w http.ResponseWriter, r *http.Request, formData db.AddTastingParams, heldAt string, validationErrors map[string]string, status int) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := s.tastingStore.GetTastings(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tastings: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.TastingsForm(rows, location, formData, heldAt, validationErrors))
+}
+
+// GET /tastings
+func (s *server) tastingsHandler(w http.ResponseWriter, r *http.Request) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := s.tastingStore.GetTastings(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tastings: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Tastings(rows, location), "Tastings")
+}
+
+// POST /tastings
+func (s *server) addTastingHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Organising tasting")
+
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting location: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	formHeldAt := r.FormValue("held-at")
+	params := db.AddTastingParams{
+		Name:        r.FormValue("name"),
+		OrganiserID: sql.NullInt64{Valid: true, Int64: currentUserId(r)},
+	}
+	if formHeldAt != "" {
+		heldAt, err := time.ParseInLocation(drunkAtLayout, formHeldAt, location)
+		if err != nil {
+			s.renderTastings(w, r, params, formHeldAt, map[string]string{"held-at": "When must be a date and time"}, http.StatusUnprocessableEntity)
+			return
+		}
+		params.HeldAt = heldAt
+	}
+
+	if _, err := s.tastingStore.AddTasting(r.Context(), params); err != nil {
+		errMsg := fmt.Sprintf("Error when adding tasting: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderTastings(w, r, params, formHeldAt, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderTastings(w, r, db.AddTastingParams{}, "", nil, http.StatusOK)
+}
+
+// GET /tasting/{id}
+func (s *server) getTastingHandler(w http.ResponseWriter, r *http.Request) {
+	if tasting, ok := s.pathTasting(w, r); ok {
+		s.renderTasting(w, r, tasting, 0, nil, http.StatusOK)
+	}
+}
+
+// DELETE /tasting/{id}
+func (s *server) deleteTastingHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting tasting with id: %s", r.PathValue("id"))
+	tasting, ok := s.organisedTasting(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := s.tastingStore.DeleteTasting(r.Context(), tasting.ID); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting tasting: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case tastings.ErrTastingNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.tastingsHandler(w, r)
+}
+
+// POST /tasting/{id}/pours
+func (s *server) addPourHandler(w http.ResponseWriter, r *http.Request) {
+	tasting, ok := s.organisedTasting(w, r)
+	if !ok {
+		return
+	}
+
+	formBeerId := r.FormValue("beer-id")
+	if formBeerId == "" {
+		s.renderTasting(w, r, tasting, 0, map[string]string{"beer-id": "This field is required"}, http.StatusUnprocessableEntity)
+		return
+	}
+	beerId, err := strconv.ParseInt(formBeerId, 10, 64)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting beer id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := s.tastingStore.AddPour(r.Context(), tasting.ID, beerId); err != nil {
+		errMsg := fmt.Sprintf("Error when adding pour: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case tastings.ErrBeerAlreadyPoured:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"beer-id": "That beer is already poured"}, http.StatusUnprocessableEntity)
+		case beers.ErrBeerNotFound:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"beer-id": fmt.Sprintf("Beer with id %d not found", beerId)}, http.StatusUnprocessableEntity)
+		case tastings.ErrTastingRevealed:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "The beers have already been revealed"}, http.StatusConflict)
+		case tastings.ErrTastingNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderTasting(w, r, tasting, 0, nil, http.StatusOK)
+}
+
+// DELETE /tasting/{id}/pour/{number}
+func (s *server) removePourHandler(w http.ResponseWriter, r *http.Request) {
+	tasting, ok := s.organisedTasting(w, r)
+	if !ok {
+		return
+	}
+	number, ok := s.pathPourNumber(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.tastingStore.RemovePour(r.Context(), tasting.ID, number); err != nil {
+		errMsg := fmt.Sprintf("Error when removing pour: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case tastings.ErrTastingRevealed:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "The beers have already been revealed"}, http.StatusConflict)
+		case tastings.ErrPourNotFound, tastings.ErrTastingNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderTasting(w, r, tasting, 0, nil, http.StatusOK)
+}
+
+// PUT /tasting/{id}/pour/{number}/scorecard
+func (s *server) scorePourHandler(w http.ResponseWriter, r *http.Request) {
+	tasting, ok := s.pathTasting(w, r)
+	if !ok {
+		return
+	}
+	number, ok := s.pathPourNumber(w, r)
+	if !ok {
+		return
+	}
+
+	scores, validationErrors := parseScores(r)
+	if len(validationErrors) > 0 {
+		s.renderTasting(w, r, tasting, number, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if _, err := s.tastingStore.SaveScorecard(r.Context(), tasting.ID, number, currentUserId(r), scores); err != nil {
+		errMsg := fmt.Sprintf("Error when saving tasting scorecard: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			s.renderTasting(w, r, tasting, number, map[string]string{err.Field: "This field " + err.Reason}, http.StatusUnprocessableEntity)
+		case tastings.ErrTastingRevealed:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "The beers have already been revealed"}, http.StatusConflict)
+		case tastings.ErrPourNotFound, tastings.ErrTastingNotFound, users.ErrUserNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderTasting(w, r, tasting, 0, nil, http.StatusOK)
+}
+
+// POST /tasting/{id}/reveal
+//
+// Unmasks the beers, and adds everyone's scores to each beer's scorecards, replacing any they'd
+// already given it. The beers' ratings are worked out again from their scorecards, like when one
+// is edited.
+func (s *server) revealTastingHandler(w http.ResponseWriter, r *http.Request) {
+	tasting, ok := s.organisedTasting(w, r)
+	if !ok {
+		return
+	}
+	s.logger.Printf("Revealing tasting with id: %d", tasting.ID)
+	if tasting.RevealedAt.Valid {
+		s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "The beers have already been revealed"}, http.StatusConflict)
+		return
+	}
+
+	ctx := r.Context()
+	pours, err := s.tastingStore.GetPours(ctx, tasting.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting pours: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	if len(pours) == 0 {
+		s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "Pour at least one beer before the reveal"}, http.StatusUnprocessableEntity)
+		return
+	}
+	cards, err := s.tastingStore.GetScorecards(ctx, tasting.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting tasting scorecards: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// The scores are written before the reveal, so if anything goes wrong it can be revealed
+	// again without any being left out
+	beerIds := map[int64]int64{}
+	for _, pour := range pours {
+		beerIds[pour.Number] = pour.BeerID
+	}
+	rated := map[int64]bool{}
+	for _, card := range cards {
+		beerId := beerIds[card.TastingScorecard.Number]
+		if _, err := s.scorecardStore.SaveScorecard(ctx, beerId, card.TastingScorecard.UserID, tastings.ScoresOf(card.TastingScorecard)); err != nil {
+			errMsg := fmt.Sprintf("Error when saving scorecard: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		s.removeRatedWish(ctx, beerId, card.TastingScorecard.UserID)
+		rated[beerId] = true
+	}
+	for _, pour := range pours {
+		if !rated[pour.BeerID] {
+			continue
+		}
+		summary, err := s.scorecardStore.GetBeerSummary(ctx, pour.BeerID)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when getting beer scores: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		beer, err := s.beerStore.GetBeer(ctx, pour.BeerID)
+		if err == nil {
+			beer, err = s.beerStore.UpdateBeer(ctx, currentUserId(r), db.UpdateBeerParams{
+				ID:     beer.ID,
+				Name:   sql.NullString{Valid: true, String: beer.Name},
+				Rating: sql.NullFloat64{Valid: true, Float64: summary.Total},
+			})
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when updating beer rating: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		s.emitWebhook(ctx, webhooks.BeerUpdated, beerWebhookData(beer))
+	}
+
+	revealed, err := s.tastingStore.Reveal(ctx, tasting.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when revealing tasting: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case tastings.ErrTastingRevealed:
+			s.renderTasting(w, r, tasting, 0, map[string]string{"tasting": "The beers have already been revealed"}, http.StatusConflict)
+		case tastings.ErrTastingNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderTasting(w, r, revealed, 0, nil, http.StatusOK)
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/templates"
+)
+
+// How often to check the trash for items which have outlived the retention period
+const trashPurgeInterval = time.Hour
+
+// Periodically purge anything which has been in the trash for longer than the retention period,
+// until the context is cancelled
+func (s *server) purgeTrashPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purgeTrash(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Permanently remove everything which was deleted before the retention period. The stores log any
+// errors, and the next run will try again.
+func (s *server) purgeTrash(ctx context.Context) {
+	deletedBefore := time.Now().Add(-s.trashRetention)
+
+	// Beers are purged one by one so their label photos are removed with them
+	if deletedBeers, err := s.beerStore.GetDeletedBeers(ctx); err == nil {
+		for _, beer := range deletedBeers {
+			if beer.DeletedAt.Time.Before(deletedBefore) {
+				s.purgeBeer(ctx, beer.ID)
+			}
+		}
+	}
+	s.beerStore.PurgeDeletedBeers(ctx, deletedBefore)
+	s.brewerStore.PurgeDeletedBrewers(ctx, deletedBefore)
+	s.userStore.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+// GET /trash
+func (s *server) trashHandler(w http.ResponseWriter, r *http.Request) {
+	deletedUsers, err := s.userStore.GetDeletedUsers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting deleted users: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	deletedBrewers, err := s.brewerStore.GetDeletedBrewers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting deleted brewers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	deletedBeers, err := s.beerStore.GetDeletedBeers(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting deleted beers: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Trash(s.trashRetention, deletedUsers, deletedBrewers, deletedBeers), "Trash")
+}
+
+// POST /user/{id}/restore
+func (s *server) restoreUserHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Restoring user with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	user, err := s.userStore.RestoreUser(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when restoring user: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case users.ErrUserNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Put the user back in the list, and replace the target of the restore request (the undo toast
+	// or the item in the trash) with nothing
+	renderTemplate(w, r, templates.UserToAppend(user))
+}
+
+// POST /brewer/{id}/restore
+func (s *server) restoreBrewerHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Restoring brewer with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	brewer, err := s.brewerStore.RestoreBrewer(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when restoring brewer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case store.ErrBrewerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Put the brewer back in the list, and replace the target of the restore request (the undo
+	// toast or the item in the trash) with nothing
+	renderTemplate(w, r, templates.BrewerToAppend(brewer))
+}
+
+// POST /beer/{id}/restore
+func (s *server) restoreBeerHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Restoring beer with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	beer, err := s.beerStore.RestoreBeer(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when restoring beer: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	beerTags, err := s.tagStore.GetBeerTags(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting beer tags: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	quantity, err := s.getStockLevel(r.Context(), beer.ID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting stock level: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	wished, err := s.getWished(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Put the beer back in the list, and replace the target of the restore request (the undo toast
+	// or the item in the trash) with nothing
+	renderTemplate(w, r, templates.BeerToAppend(beer, beerTags, quantity, wished[beer.ID]))
+}
+
+// DELETE /trash/user/{id}
+func (s *server) purgeUserHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Purging user with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = s.userStore.PurgeUser(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when purging user: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Return nothing so the item in the trash is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /trash/brewer/{id}
+func (s *server) purgeBrewerHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Purging brewer with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = s.brewerStore.PurgeBrewer(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when purging brewer: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Return nothing so the item in the trash is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /trash/beer/{id}
+func (s *server) purgeBeerHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Purging beer with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = s.purgeBeer(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when purging beer: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Return nothing so the item in the trash is replaced with nothing, i.e. removed
+	w.WriteHeader(http.StatusNoContent)
+}
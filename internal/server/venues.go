@@ -0,0 +1,395 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/venues"
+	"beer_oclock/internal/templates"
+)
+
+// How far away venues are looked for if no radius is given, and the most that are shown
+const (
+	defaultNearbyRadiusKm = 5.0
+	maxNearbyVenues       = 20
+)
+
+// How many of the check-ins and most drunk beers are shown on a venue's page, and how many of the
+// user's own check-ins are listed
+const (
+	maxVenueCheckIns = 20
+	maxVenueTopBeers = 10
+	maxUserCheckIns  = 50
+)
+
+// Parses an optional number from the form, naming it in the validation error
+func parseOptionalFloat(value string, name string) (sql.NullFloat64, string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return sql.NullFloat64{}, ""
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return sql.NullFloat64{}, name + " must be a number"
+	}
+	return sql.NullFloat64{Valid: true, Float64: f}, ""
+}
+
+// Renders the venues with the form for adding another
+func (s *server) renderVenues(w http.ResponseWriter, r *http.Request, formData templates.VenueFormData, validationErrors map[string]string, status int) {
+	allVenues, err := s.venueStore.GetVenues(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting venues: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.VenuesForm(allVenues, formData, validationErrors))
+}
+
+// Renders the venue with the beers most drunk there, the latest check-ins and the form for
+// checking in
+func (s *server) renderVenue(w http.ResponseWriter, r *http.Request, venue db.Venue, validationErrors map[string]string, status int) {
+	data, err := s.getVenueData(r.Context(), venue, currentUserId(r))
+	if err == nil {
+		data.Location, err = s.userLocation(r)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting venue: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.Venue(data, validationErrors), venue.Name)
+}
+
+// Everything shown on the venue's page for the user
+func (s *server) getVenueData(ctx context.Context, venue db.Venue, userId int64) (templates.VenueData, error) {
+	data := templates.VenueData{Venue: venue}
+
+	var err error
+	data.TopBeers, err = s.venueStore.GetVenueTopBeers(ctx, venue.ID, maxVenueTopBeers)
+	if err != nil {
+		return templates.VenueData{}, err
+	}
+	data.CheckIns, err = s.venueStore.GetVenueCheckIns(ctx, venue.ID, maxVenueCheckIns)
+	if err != nil {
+		return templates.VenueData{}, err
+	}
+	data.Beers, err = s.beerStore.GetBeers(ctx)
+	if err != nil {
+		return templates.VenueData{}, err
+	}
+
+	// Anyone can add venues, but only admins can delete them along with everyone's check-ins
+	user, err := s.userStore.GetUserById(ctx, userId)
+	if err != nil {
+		return templates.VenueData{}, err
+	}
+	data.CanDelete = user.IsAdmin
+	return data, nil
+}
+
+// Gets the venue with the id in the path, responding with an error if there isn't one
+func (s *server) pathVenue(w http.ResponseWriter, r *http.Request) (db.Venue, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return db.Venue{}, false
+	}
+
+	venue, err := s.venueStore.GetVenue(r.Context(), int64(id))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting venue: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case venues.ErrVenueNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return db.Venue{}, false
+	}
+	return venue, true
+}
+
+// GET /venues
+func (s *server) venuesHandler(w http.ResponseWriter, r *http.Request) {
+	allVenues, err := s.venueStore.GetVenues(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting venues: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Venues(allVenues), "Venues")
+}
+
+// POST /venues
+func (s *server) addVenueHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Adding venue")
+
+	formData := templates.VenueFormData{
+		Params: db.AddVenueParams{
+			Name:    r.FormValue("name"),
+			Address: r.FormValue("address"),
+			Type:    r.FormValue("type"),
+		},
+		Latitude:  r.FormValue("latitude"),
+		Longitude: r.FormValue("longitude"),
+	}
+
+	validationErrors := map[string]string{}
+	var errMsg string
+	if formData.Params.Latitude, errMsg = parseOptionalFloat(formData.Latitude, "Latitude"); errMsg != "" {
+		validationErrors["latitude"] = errMsg
+	}
+	if formData.Params.Longitude, errMsg = parseOptionalFloat(formData.Longitude, "Longitude"); errMsg != "" {
+		validationErrors["longitude"] = errMsg
+	}
+	if len(validationErrors) > 0 {
+		s.renderVenues(w, r, formData, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if _, err := s.venueStore.AddVenue(r.Context(), formData.Params); err != nil {
+		errMsg := fmt.Sprintf("Error when adding venue: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderVenues(w, r, formData, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderVenues(w, r, formData, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderVenues(w, r, templates.VenueFormData{}, nil, http.StatusOK)
+}
+
+// GET /venues/nearby
+func (s *server) nearbyVenuesHandler(w http.ResponseWriter, r *http.Request) {
+	search := templates.NearbySearch{
+		Latitude:  r.URL.Query().Get("latitude"),
+		Longitude: r.URL.Query().Get("longitude"),
+		Radius:    r.URL.Query().Get("radius"),
+	}
+
+	// Without coordinates there's just the form, which fills them in from the browser's location
+	if search.Latitude == "" && search.Longitude == "" {
+		renderTemplate(w, r, templates.NearbyVenues(search, nil, nil), "Nearby Venues")
+		return
+	}
+
+	validationErrors := map[string]string{}
+	latitude, errMsg := parseOptionalFloat(search.Latitude, "Latitude")
+	if errMsg != "" {
+		validationErrors["latitude"] = errMsg
+	} else if !latitude.Valid {
+		validationErrors["latitude"] = "This field is required"
+	} else if latitude.Float64 < -90 || latitude.Float64 > 90 {
+		validationErrors["latitude"] = "Latitude must be between -90 and 90"
+	}
+	longitude, errMsg := parseOptionalFloat(search.Longitude, "Longitude")
+	if errMsg != "" {
+		validationErrors["longitude"] = errMsg
+	} else if !longitude.Valid {
+		validationErrors["longitude"] = "This field is required"
+	} else if longitude.Float64 < -180 || longitude.Float64 > 180 {
+		validationErrors["longitude"] = "Longitude must be between -180 and 180"
+	}
+	radius := sql.NullFloat64{Valid: true, Float64: defaultNearbyRadiusKm}
+	if search.Radius != "" {
+		radius, errMsg = parseOptionalFloat(search.Radius, "Radius")
+		if errMsg != "" {
+			validationErrors["radius"] = errMsg
+		} else if radius.Float64 <= 0 {
+			validationErrors["radius"] = "Radius must be more than 0"
+		}
+	}
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.NearbyVenues(search, nil, validationErrors), "Nearby Venues")
+		return
+	}
+
+	nearby, err := s.venueStore.GetNearbyVenues(r.Context(), latitude.Float64, longitude.Float64, radius.Float64, maxNearbyVenues)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting nearby venues: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.NearbyVenues(search, nearby, nil), "Nearby Venues")
+}
+
+// GET /venue/{id}
+func (s *server) getVenueHandler(w http.ResponseWriter, r *http.Request) {
+	venue, ok := s.pathVenue(w, r)
+	if !ok {
+		return
+	}
+
+	s.renderVenue(w, r, venue, nil, http.StatusOK)
+}
+
+// DELETE /venue/{id}
+func (s *server) deleteVenueHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting venue with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := s.venueStore.DeleteVenue(r.Context(), int64(id)); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting venue: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case venues.ErrVenueNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.venuesHandler(w, r)
+}
+
+// POST /venue/{id}/checkins
+func (s *server) addCheckInHandler(w http.ResponseWriter, r *http.Request) {
+	venue, ok := s.pathVenue(w, r)
+	if !ok {
+		return
+	}
+
+	s.logger.Printf("Checking in at venue with id: %d", venue.ID)
+
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Checked in now unless a time is given, which is where the user is like for drinks
+	params := db.AddCheckInParams{
+		UserID:      currentUserId(r),
+		VenueID:     venue.ID,
+		CheckedInAt: time.Now(),
+	}
+	validationErrors := map[string]string{}
+	if beerId, err := strconv.ParseInt(r.FormValue("beer-id"), 10, 64); err != nil {
+		validationErrors["beer-id"] = "Beer must be one of the beers"
+	} else {
+		params.BeerID = beerId
+	}
+	if formCheckedInAt := r.FormValue("checked-in-at"); formCheckedInAt != "" {
+		checkedInAt, err := time.ParseInLocation(drunkAtLayout, formCheckedInAt, location)
+		if err != nil {
+			validationErrors["checked-in-at"] = "When must be a date and time"
+		} else {
+			params.CheckedInAt = checkedInAt
+		}
+	}
+	if len(validationErrors) > 0 {
+		s.renderVenue(w, r, venue, validationErrors, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if _, err := s.venueStore.AddCheckIn(r.Context(), params); err != nil {
+		errMsg := fmt.Sprintf("Error when checking in: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderVenue(w, r, venue, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case beers.ErrBeerNotFound:
+			s.renderVenue(w, r, venue, map[string]string{"beer-id": "Beer must be one of the beers"}, http.StatusUnprocessableEntity)
+		case venues.ErrVenueNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderVenue(w, r, venue, nil, http.StatusOK)
+}
+
+// GET /checkins
+func (s *server) checkInsHandler(w http.ResponseWriter, r *http.Request) {
+	location, err := s.userLocation(r)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting goals: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	checkIns, err := s.venueStore.GetUserCheckIns(r.Context(), currentUserId(r), maxUserCheckIns)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting check-ins: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.CheckIns(checkIns, location), "Check-ins")
+}
+
+// DELETE /checkin/{id}
+func (s *server) deleteCheckInHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting check-in with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// Other users' check-ins are treated as not being there
+	checkIn, err := s.venueStore.GetCheckIn(r.Context(), int64(id))
+	if err == nil && checkIn.UserID != currentUserId(r) {
+		err = venues.ErrCheckInNotFound{ID: int64(id)}
+	}
+	if err == nil {
+		_, err = s.venueStore.DeleteCheckIn(r.Context(), checkIn.ID)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when deleting check-in: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case venues.ErrCheckInNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.checkInsHandler(w, r)
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/templates"
+	"beer_oclock/internal/webhook"
+)
+
+// How often to send the deliveries waiting for webhooks
+const webhookInterval = 30 * time.Second
+
+// How many deliveries are sent each time, so a backlog is worked through a bit at a time
+const webhookBatchSize = 20
+
+// How many of the latest deliveries the delivery log shows
+const deliveriesPageSize = 50
+
+// What's posted to webhooks, with the beer, brewer, user or drink the event is about as the data
+type webhookPayload struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type webhookBeer struct {
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	BrewerID *int64   `json:"brewer_id"`
+	Style    *string  `json:"style"`
+	Abv      float64  `json:"abv"`
+	Rating   *float64 `json:"rating"`
+	Notes    *string  `json:"notes"`
+}
+
+type webhookBrewer struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Location *string `json:"location"`
+}
+
+type webhookUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type webhookDrink struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	BeerID    int64     `json:"beer_id"`
+	ServingMl int64     `json:"serving_ml"`
+	DrunkAt   time.Time `json:"drunk_at"`
+	// The drinking session it was logged in, if any
+	SessionID *int64 `json:"session_id"`
+}
+
+// The value if it's set, otherwise nil, which is null in JSON
+func nullable[T any](value T, valid bool) *T {
+	if !valid {
+		return nil
+	}
+	return &value
+}
+
+func beerWebhookData(beer db.Beer) webhookBeer {
+	return webhookBeer{
+		ID:       beer.ID,
+		Name:     beer.Name,
+		BrewerID: nullable(beer.BrewerID.Int64, beer.BrewerID.Valid),
+		Style:    nullable(beer.Style.String, beer.Style.Valid),
+		Abv:      beer.Abv,
+		Rating:   nullable(beer.Rating.Float64, beer.Rating.Valid),
+		Notes:    nullable(beer.Notes.String, beer.Notes.Valid),
+	}
+}
+
+func brewerWebhookData(brewer db.Brewer) webhookBrewer {
+	return webhookBrewer{
+		ID:       brewer.ID,
+		Name:     brewer.Name,
+		Location: nullable(brewer.Location.String, brewer.Location.Valid),
+	}
+}
+
+func userWebhookData(user db.User) webhookUser {
+	return webhookUser{ID: user.ID, Username: user.Username}
+}
+
+func drinkWebhookData(drink db.Drink, sessionId sql.NullInt64) webhookDrink {
+	return webhookDrink{
+		ID:        drink.ID,
+		UserID:    drink.UserID,
+		BeerID:    drink.BeerID,
+		ServingMl: drink.ServingMl,
+		DrunkAt:   drink.DrunkAt,
+		SessionID: nullable(sessionId.Int64, sessionId.Valid),
+	}
+}
+
+// Queues a delivery of the event to each webhook which wants it, to be sent by deliverWebhooks.
+// The change the event is about has already happened, so failing to queue it is only logged.
+func (s *server) emitWebhook(ctx context.Context, event string, data any) {
+	hooks, err := s.webhookStore.GetWebhooks(ctx)
+	if err != nil {
+		s.logger.Printf("Error when getting webhooks for %s: %v", event, err)
+		return
+	}
+
+	var payload []byte
+	for _, hook := range hooks {
+		if !webhooks.Wants(hook, event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(webhookPayload{Event: event, CreatedAt: store.Now(), Data: data})
+			if err != nil {
+				s.logger.Printf("Error when encoding %s payload: %v", event, err)
+				return
+			}
+		}
+		_, err := s.webhookStore.QueueDelivery(ctx, db.QueueDeliveryParams{
+			WebhookID: hook.ID,
+			Event:     event,
+			Payload:   string(payload),
+		})
+		if err != nil {
+			s.logger.Printf("Error when queueing %s for webhook %d: %v", event, hook.ID, err)
+		}
+	}
+}
+
+// Periodically send the deliveries which are due, until the context is cancelled
+func (s *server) deliverWebhooksPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverWebhooks(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Try to send the deliveries which are due at now. Ones which fail are tried again later, waiting
+// longer after each failure, until they've had webhooks.MaxAttempts tries.
+func (s *server) deliverWebhooks(ctx context.Context, now time.Time) {
+	due, err := s.webhookStore.GetDueDeliveries(ctx, now, webhookBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, row := range due {
+		delivery := row.WebhookDelivery
+		status, err := s.webhookSender.Send(ctx, webhook.Delivery{
+			ID:      delivery.ID,
+			URL:     row.Url,
+			Secret:  row.Secret,
+			Event:   delivery.Event,
+			Payload: []byte(delivery.Payload),
+		})
+		lastStatus := sql.NullInt64{Valid: status != 0, Int64: int64(status)}
+		if err != nil {
+			s.logger.Printf("Error when delivering %d to %s: %v", delivery.ID, row.Url, err)
+			s.webhookStore.SetDeliveryFailed(ctx, db.SetDeliveryFailedParams{
+				LastStatus:    lastStatus,
+				LastError:     err.Error(),
+				NextAttemptAt: webhooks.Retry(delivery, now),
+				ID:            delivery.ID,
+			})
+			continue
+		}
+		s.webhookStore.SetDeliveryDelivered(ctx, db.SetDeliveryDeliveredParams{
+			LastStatus:  lastStatus,
+			DeliveredAt: sql.NullTime{Valid: true, Time: now},
+			ID:          delivery.ID,
+		})
+	}
+}
+
+func (s *server) renderWebhooksForm(w http.ResponseWriter, r *http.Request, formData db.AddWebhookParams, validationErrors map[string]string, status int) {
+	hooks, err := s.webhookStore.GetWebhooks(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting webhooks: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(w, r, templates.WebhooksForm(hooks, formData, validationErrors))
+}
+
+// GET /webhooks
+func (s *server) webhooksHandler(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.webhookStore.GetWebhooks(r.Context())
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting webhooks: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.Webhooks(hooks, db.AddWebhookParams{}), "Webhooks")
+}
+
+// POST /webhooks
+func (s *server) addWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Adding webhook")
+	if err := r.ParseForm(); err != nil {
+		errMsg := fmt.Sprintf("Error when parsing form: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	formData := db.AddWebhookParams{
+		Url:    r.FormValue("url"),
+		Secret: strings.TrimSpace(r.FormValue("secret")),
+		Events: strings.Join(r.Form["events"], ","),
+	}
+
+	// Most receivers only need some secret, so one's made up unless the admin has their own
+	params := formData
+	if params.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			errMsg := fmt.Sprintf("Error when making webhook secret: %v", err)
+			s.logger.Print(errMsg)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		params.Secret = secret
+	}
+
+	_, err := s.webhookStore.AddWebhook(r.Context(), params)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding webhook: %v", err)
+		s.logger.Print(errMsg)
+
+		switch err := err.(type) {
+		case store.ErrMissingField:
+			s.renderWebhooksForm(w, r, formData, map[string]string{err.Field: "This field is required"}, http.StatusUnprocessableEntity)
+		case store.ErrInvalidField:
+			s.renderWebhooksForm(w, r, formData, map[string]string{err.Field: fmt.Sprintf("This field %s", err.Reason)}, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderWebhooksForm(w, r, db.AddWebhookParams{}, nil, http.StatusOK)
+}
+
+// DELETE /webhook/{id}
+func (s *server) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("Deleting webhook with id: %s", r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := s.webhookStore.DeleteWebhook(r.Context(), int64(id)); err != nil {
+		errMsg := fmt.Sprintf("Error when deleting webhook: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case webhooks.ErrWebhookNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.renderWebhooksForm(w, r, db.AddWebhookParams{}, nil, http.StatusOK)
+}
+
+// GET /webhooks/deliveries
+func (s *server) webhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := s.webhookStore.GetDeliveries(r.Context(), deliveriesPageSize)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting webhook deliveries: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, templates.WebhookDeliveries(deliveries), "Webhook Deliveries")
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/wishlist"
+	"beer_oclock/internal/templates"
+)
+
+// Takes the beer off the user's wishlist now they've rated it. The rating has already been saved,
+// so failing to is only logged.
+func (s *server) removeRatedWish(ctx context.Context, beerId int64, userId int64) {
+	err := s.wishlistStore.RemoveWish(ctx, userId, beerId)
+	if _, ok := err.(wishlist.ErrWishNotFound); err != nil && !ok {
+		s.logger.Printf("Error when removing rated beer %d from wishlist: %v", beerId, err)
+	}
+}
+
+// Which beers are on the user's wishlist, for showing whether each beer can be added
+func (s *server) getWished(ctx context.Context, userId int64) (map[int64]bool, error) {
+	rows, err := s.wishlistStore.GetWishlist(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	wished := map[int64]bool{}
+	for _, row := range rows {
+		wished[row.Beer.ID] = true
+	}
+	return wished, nil
+}
+
+// The beer id from the path, responding with an error if it isn't a number
+func (s *server) pathBeerId(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when converting id to int: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return 0, false
+	}
+	return int64(id), true
+}
+
+// Parses the optional priority from the form, which is empty for none
+func parsePriority(r *http.Request) (sql.NullInt64, string) {
+	value := strings.TrimSpace(r.FormValue("priority"))
+	if value == "" {
+		return sql.NullInt64{}, ""
+	}
+	priority, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, "Priority must be a number"
+	}
+	return sql.NullInt64{Valid: true, Int64: priority}, ""
+}
+
+// GET /wishlist
+func (s *server) wishlistHandler(w http.ResponseWriter, r *http.Request) {
+	search := templates.WishlistSearch{
+		Style:    r.URL.Query().Get("style"),
+		BrewerID: r.URL.Query().Get("brewer-id"),
+		MinAbv:   r.URL.Query().Get("min-abv"),
+		MaxAbv:   r.URL.Query().Get("max-abv"),
+	}
+
+	validationErrors := map[string]string{}
+	filter := wishlist.Filter{Style: search.Style}
+	if search.BrewerID != "" {
+		brewerId, err := strconv.ParseInt(search.BrewerID, 10, 64)
+		if err != nil {
+			validationErrors["brewer-id"] = "Brewer must be a number"
+		}
+		filter.BrewerID = sql.NullInt64{Valid: err == nil, Int64: brewerId}
+	}
+	var errMsg string
+	if filter.MinAbv, errMsg = parseOptionalFloat(search.MinAbv, "Minimum ABV"); errMsg != "" {
+		validationErrors["min-abv"] = errMsg
+	}
+	if filter.MaxAbv, errMsg = parseOptionalFloat(search.MaxAbv, "Maximum ABV"); errMsg != "" {
+		validationErrors["max-abv"] = errMsg
+	}
+
+	rows, err := s.wishlistStore.GetWishlist(r.Context(), currentUserId(r))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// The choices come from the whole list, so filtering by one doesn't hide the others
+	data := templates.WishlistData{Search: search, Total: len(rows)}
+	data.Styles, data.Brewers = wishlist.Options(rows)
+	if len(validationErrors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.Wishlist(data, validationErrors), "Wishlist")
+		return
+	}
+	data.Rows = filter.Apply(rows)
+	renderTemplate(w, r, templates.Wishlist(data, nil), "Wishlist")
+}
+
+// POST /beer/{id}/wishlist
+func (s *server) addWishHandler(w http.ResponseWriter, r *http.Request) {
+	beerId, ok := s.pathBeerId(w, r)
+	if !ok {
+		return
+	}
+	priority, errMsg := parsePriority(r)
+	if errMsg != "" {
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		return
+	}
+
+	_, err := s.wishlistStore.AddWish(r.Context(), db.AddWishParams{
+		UserID:   currentUserId(r),
+		BeerID:   beerId,
+		Priority: priority,
+		Note:     sql.NullString{Valid: true, String: r.FormValue("note")},
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when adding to wishlist: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case wishlist.ErrAlreadyWished:
+			w.WriteHeader(http.StatusConflict)
+			renderTemplate(w, r, templates.WishlistButton(beerId, true))
+		case beers.ErrBeerNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		case store.ErrInvalidField:
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.WishlistButton(beerId, true))
+}
+
+// PUT /beer/{id}/wishlist
+func (s *server) updateWishHandler(w http.ResponseWriter, r *http.Request) {
+	beerId, ok := s.pathBeerId(w, r)
+	if !ok {
+		return
+	}
+	userId := currentUserId(r)
+
+	rows, err := s.wishlistStore.GetWishlist(r.Context(), userId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when getting wishlist: %v", err)
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	i := slices.IndexFunc(rows, func(row db.GetWishlistRow) bool { return row.Beer.ID == beerId })
+	if i < 0 {
+		errMsg := fmt.Sprintf("Error when updating wish: %v", wishlist.ErrWishNotFound{UserID: userId, BeerID: beerId})
+		s.logger.Print(errMsg)
+		http.Error(w, errMsg, http.StatusNotFound)
+		return
+	}
+	row := rows[i]
+
+	// What was entered is shown back if it's wrong
+	priority, errMsg := parsePriority(r)
+	row.Wishlist.Priority, row.Wishlist.Note = priority, sql.NullString{Valid: true, String: r.FormValue("note")}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderTemplate(w, r, templates.WishlistItem(row, map[string]string{"priority": errMsg}))
+		return
+	}
+
+	wish, err := s.wishlistStore.UpdateWish(r.Context(), db.UpdateWishParams{
+		UserID:   userId,
+		BeerID:   beerId,
+		Priority: row.Wishlist.Priority,
+		Note:     row.Wishlist.Note,
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("Error when updating wish: %v", err)
+		s.logger.Print(errMsg)
+		switch err := err.(type) {
+		case store.ErrInvalidField:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderTemplate(w, r, templates.WishlistItem(row, map[string]string{err.Field: "This field " + err.Reason}))
+		case wishlist.ErrWishNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	row.Wishlist = wish
+	renderTemplate(w, r, templates.WishlistItem(row, nil))
+}
+
+// DELETE /beer/{id}/wishlist
+func (s *server) removeWishHandler(w http.ResponseWriter, r *http.Request) {
+	beerId, ok := s.pathBeerId(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.wishlistStore.RemoveWish(r.Context(), currentUserId(r), beerId); err != nil {
+		errMsg := fmt.Sprintf("Error when removing from wishlist: %v", err)
+		s.logger.Print(errMsg)
+		switch err.(type) {
+		case wishlist.ErrWishNotFound:
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	renderTemplate(w, r, templates.WishlistButton(beerId, false))
+}
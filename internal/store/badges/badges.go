@@ -0,0 +1,67 @@
+package badges
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs for badges and leaderboards, implemented by BadgeStore
+// (backed by the database) and MemoryBadgeStore (for tests). Badges aren't stored, they're worked
+// out from the facts whenever they're shown, so they follow beers being edited or deleted.
+type Store interface {
+	// Everything the user has done, oldest first
+	GetUserFacts(ctx context.Context, userId int64) ([]Fact, error)
+	// Everything everyone did from since until before until, oldest first
+	GetFacts(ctx context.Context, since time.Time, until time.Time) ([]Fact, error)
+}
+
+var _ Store = (*BadgeStore)(nil)
+var _ Store = (*MemoryBadgeStore)(nil)
+
+// What a user did to a beer
+const (
+	Added = "added"
+	Rated = "rated"
+)
+
+// A user adding or rating a beer, with what about the beer badges and leaderboards count
+type Fact struct {
+	UserID   int64
+	Username string
+	Action   string
+	BeerID   int64
+	Style    string
+	// The BJCP category of the beer's style, or "" if the style isn't one of the guidelines
+	Category string
+	// 0 if the beer doesn't have a brewer
+	BrewerID int64
+	// Where the brewer is, as entered
+	City string
+	At   time.Time
+}
+
+// For when facts about all time are wanted. It's far enough away without overflowing anything
+// the databases do with it.
+var forever = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Puts the facts in the order they happened, with adding before rating when they're at the same
+// time as they are when a beer is added with a scorecard
+func sortFacts(facts []Fact) {
+	slices.SortStableFunc(facts, func(a, b Fact) int {
+		if c := a.At.Compare(b.At); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.BeerID, b.BeerID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Action, b.Action)
+	})
+}
+
+// Styles and cities are free text, so they're compared ignoring case and surrounding space
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,102 @@
+package badges
+
+import (
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/store/users"
+	"context"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which works out the same facts as BadgeStore
+// from the other stores. Whoever made a beer's first revision added it, and each scorecard is a
+// rating when it was first saved.
+type MemoryBadgeStore struct {
+	userStore      users.Store
+	beerStore      beers.Store
+	brewerStore    brewers.Store
+	scorecardStore scorecards.Store
+	styleStore     styles.Store
+}
+
+func NewMemoryBadgeStore(userStore users.Store, beerStore beers.Store, brewerStore brewers.Store, scorecardStore scorecards.Store, styleStore styles.Store) *MemoryBadgeStore {
+	return &MemoryBadgeStore{
+		userStore:      userStore,
+		beerStore:      beerStore,
+		brewerStore:    brewerStore,
+		scorecardStore: scorecardStore,
+		styleStore:     styleStore,
+	}
+}
+
+func (bs *MemoryBadgeStore) GetUserFacts(ctx context.Context, userId int64) ([]Fact, error) {
+	facts, err := bs.GetFacts(ctx, time.Time{}, forever)
+	if err != nil {
+		return nil, err
+	}
+	mine := []Fact{}
+	for _, f := range facts {
+		if f.UserID == userId {
+			mine = append(mine, f)
+		}
+	}
+	return mine, nil
+}
+
+func (bs *MemoryBadgeStore) GetFacts(ctx context.Context, since time.Time, until time.Time) ([]Fact, error) {
+	allBeers, err := bs.beerStore.GetBeers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := []Fact{}
+	add := func(fact Fact, userId int64) {
+		if fact.At.Before(since) || !fact.At.Before(until) {
+			return
+		}
+		user, err := bs.userStore.GetUserById(ctx, userId)
+		if err != nil {
+			return
+		}
+		fact.UserID, fact.Username = user.ID, user.Username
+		facts = append(facts, fact)
+	}
+	for _, beer := range allBeers {
+		fact := Fact{BeerID: beer.ID, Style: beer.Style.String, BrewerID: beer.BrewerID.Int64}
+		if beer.BrewerID.Valid {
+			if brewer, err := bs.brewerStore.GetBrewer(ctx, beer.BrewerID.Int64); err == nil {
+				fact.City = brewer.Location.String
+			}
+		}
+		if beer.Style.Valid {
+			if style, err := bs.styleStore.GetStyleByName(ctx, beer.Style.String); err == nil {
+				fact.Category = style.Category
+			}
+		}
+
+		history, err := bs.beerStore.GetBeerHistory(ctx, beer.ID)
+		if err != nil {
+			return nil, err
+		}
+		// The history is newest first
+		if len(history) > 0 {
+			if first := history[len(history)-1].BeerRevision; first.UserID.Valid {
+				fact.Action, fact.At = Added, first.CreatedAt
+				add(fact, first.UserID.Int64)
+			}
+		}
+
+		beerScorecards, err := bs.scorecardStore.GetBeerScorecards(ctx, beer.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range beerScorecards {
+			fact.Action, fact.At = Rated, row.Scorecard.CreatedAt
+			add(fact, row.Scorecard.UserID)
+		}
+	}
+	sortFacts(facts)
+	return facts, nil
+}
@@ -0,0 +1,79 @@
+package badges
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type BadgeStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewBadgeStore(queries db.Querier, logger *log.Logger) *BadgeStore {
+	return &BadgeStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (bs *BadgeStore) GetUserFacts(ctx context.Context, userId int64) ([]Fact, error) {
+	return bs.getFacts(ctx, sql.NullInt64{Valid: true, Int64: userId}, time.Time{}, forever)
+}
+
+func (bs *BadgeStore) GetFacts(ctx context.Context, since time.Time, until time.Time) ([]Fact, error) {
+	return bs.getFacts(ctx, sql.NullInt64{}, since, until)
+}
+
+// The beers added, by whoever added the first revision, and the beers rated, by when the
+// scorecard was first saved, so scoring a beer again doesn't move a badge or leaderboard. A
+// beer's style is in a category if it's one of the styles' names, ignoring case, which is what
+// the style picker and migrating styles give beers. The times are compared in UTC, which is how
+// they're stored.
+func (bs *BadgeStore) getFacts(ctx context.Context, userId sql.NullInt64, since time.Time, until time.Time) ([]Fact, error) {
+	since, until = since.UTC(), until.UTC()
+
+	added, err := bs.queries.GetAddedBeerFacts(ctx, db.GetAddedBeerFactsParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		bs.logger.Printf("error getting added beers: %v", err)
+		return nil, err
+	}
+	rated, err := bs.queries.GetRatedBeerFacts(ctx, db.GetRatedBeerFactsParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		bs.logger.Printf("error getting rated beers: %v", err)
+		return nil, err
+	}
+
+	facts := make([]Fact, 0, len(added)+len(rated))
+	for _, row := range added {
+		facts = append(facts, Fact{
+			UserID:   row.UserID.Int64,
+			Username: row.Username,
+			Action:   Added,
+			BeerID:   row.BeerID,
+			Style:    row.Style.String,
+			Category: row.StyleCategory.String,
+			BrewerID: row.BrewerID.Int64,
+			City:     row.BrewerLocation.String,
+			At:       row.At,
+		})
+	}
+	for _, row := range rated {
+		facts = append(facts, Fact{
+			UserID:   row.UserID,
+			Username: row.Username,
+			Action:   Rated,
+			BeerID:   row.BeerID,
+			Style:    row.Style.String,
+			Category: row.StyleCategory.String,
+			BrewerID: row.BrewerID.Int64,
+			City:     row.BrewerLocation.String,
+			At:       row.At,
+		})
+	}
+	sortFacts(facts)
+	return facts, nil
+}
@@ -0,0 +1,53 @@
+package badges
+
+import (
+	"cmp"
+	"slices"
+)
+
+// A user's place on a leaderboard
+type Standing struct {
+	UserID   int64
+	Username string
+	Count    int
+	// 1 for the most, with ties sharing a rank
+	Rank int
+}
+
+// Ranks the users in the facts by how many different styles or brewers (or anything else a rule
+// can count) their beers covered. Only users with at least one are included.
+func Leaderboard(facts []Fact, count string) []Standing {
+	key := keys[count]
+	covered := map[int64]map[string]bool{}
+	standings := []Standing{}
+	index := map[int64]int{}
+	for _, f := range facts {
+		k := key(f)
+		if k == "" {
+			continue
+		}
+		if _, ok := index[f.UserID]; !ok {
+			index[f.UserID] = len(standings)
+			standings = append(standings, Standing{UserID: f.UserID, Username: f.Username})
+			covered[f.UserID] = map[string]bool{}
+		}
+		covered[f.UserID][k] = true
+	}
+	for i := range standings {
+		standings[i].Count = len(covered[standings[i].UserID])
+	}
+
+	slices.SortFunc(standings, func(a, b Standing) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Username, b.Username)
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Count == standings[i-1].Count {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
@@ -0,0 +1,157 @@
+package badges
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The badges which can be earned, in the order they're shown
+//
+//go:embed badges.json
+var definitionsJson []byte
+
+type Badge struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Rule        Rule   `json:"rule"`
+}
+
+// How a badge is earned. The matching facts are counted, each thing being counted once, and the
+// badge is earned once there are at least Min.
+type Rule struct {
+	// Only facts about beers being added or rated count, or either if it's empty
+	On string `json:"on"`
+	// Only beers whose style contains this count, ignoring case
+	Style string `json:"style"`
+	// Only beers whose style is in this BJCP category count, ignoring case. If Style is given
+	// too, beers matching either count, so a beer given a style like "Sour IPA" still counts.
+	Category string `json:"category"`
+	// What's counted: beers, styles, brewers or cities
+	Count string `json:"count"`
+	// If given, what's counted is counted separately for each style, brewer or city, and the
+	// best one is what counts, e.g. 5 brewers from one city
+	Per string `json:"per"`
+	Min int    `json:"min"`
+}
+
+// What each fact counts as for each kind of thing counted, or "" if it doesn't count, like a
+// beer without a brewer when counting brewers
+var keys = map[string]func(Fact) string{
+	"beers": func(f Fact) string { return strconv.FormatInt(f.BeerID, 10) },
+	"styles": func(f Fact) string {
+		return normalizeKey(f.Style)
+	},
+	"brewers": func(f Fact) string {
+		if f.BrewerID == 0 {
+			return ""
+		}
+		return strconv.FormatInt(f.BrewerID, 10)
+	},
+	"cities": func(f Fact) string { return normalizeKey(f.City) },
+}
+
+func validateRule(rule Rule) error {
+	if rule.On != "" && rule.On != Added && rule.On != Rated {
+		return fmt.Errorf("on must be %s, %s or empty, not %q", Added, Rated, rule.On)
+	}
+	if _, ok := keys[rule.Count]; !ok {
+		return fmt.Errorf("count must be beers, styles, brewers or cities, not %q", rule.Count)
+	}
+	if _, ok := keys[rule.Per]; rule.Per != "" && !ok {
+		return fmt.Errorf("per must be beers, styles, brewers, cities or empty, not %q", rule.Per)
+	}
+	if rule.Min < 1 {
+		return fmt.Errorf("min must be at least 1, not %d", rule.Min)
+	}
+	return nil
+}
+
+func parseDefinitions(definitions []byte) ([]Badge, error) {
+	var badges []Badge
+	if err := json.Unmarshal(definitions, &badges); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, badge := range badges {
+		if badge.ID == "" || seen[badge.ID] {
+			return nil, fmt.Errorf("badge %q: ids must be given and unique", badge.ID)
+		}
+		seen[badge.ID] = true
+		if err := validateRule(badge.Rule); err != nil {
+			return nil, fmt.Errorf("badge %s: %w", badge.ID, err)
+		}
+	}
+	return badges, nil
+}
+
+var Definitions = sync.OnceValue(func() []Badge {
+	badges, err := parseDefinitions(definitionsJson)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded badges.json: %v", err))
+	}
+	return badges
+})
+
+// How far a user is towards a badge
+type Progress struct {
+	Badge Badge
+	// How many of what the rule counts they have, up to the rule's Min
+	Count    int
+	Earned   bool
+	EarnedAt time.Time
+}
+
+// Whether the fact is one the rule counts
+func (rule Rule) matches(f Fact) bool {
+	if rule.On != "" && f.Action != rule.On {
+		return false
+	}
+	if rule.Style == "" && rule.Category == "" {
+		return true
+	}
+	if rule.Style != "" && strings.Contains(normalizeKey(f.Style), normalizeKey(rule.Style)) {
+		return true
+	}
+	return rule.Category != "" && normalizeKey(f.Category) == normalizeKey(rule.Category)
+}
+
+// Works out each badge from the user's facts, which must be oldest first. A badge is earned when
+// the fact which took it to its Min happened.
+func Evaluate(badges []Badge, facts []Fact) []Progress {
+	progress := make([]Progress, len(badges))
+	for i, badge := range badges {
+		progress[i].Badge = badge
+		rule := badge.Rule
+		counted := map[string]map[string]bool{}
+		for _, f := range facts {
+			if !rule.matches(f) {
+				continue
+			}
+			key := keys[rule.Count](f)
+			group := ""
+			if rule.Per != "" {
+				group = keys[rule.Per](f)
+			}
+			if key == "" || (rule.Per != "" && group == "") {
+				continue
+			}
+			if counted[group] == nil {
+				counted[group] = map[string]bool{}
+			}
+			counted[group][key] = true
+			progress[i].Count = max(progress[i].Count, min(len(counted[group]), rule.Min))
+			if progress[i].Count >= rule.Min {
+				progress[i].Earned, progress[i].EarnedAt = true, f.At
+				break
+			}
+		}
+	}
+	return progress
+}
@@ -0,0 +1,118 @@
+package badges
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDefinitions(t *testing.T) {
+	if len(Definitions()) == 0 {
+		t.Fatal("got no badges from the embedded definitions")
+	}
+
+	invalid := map[string]string{
+		"unknown count": `[{"id": "a", "rule": {"count": "hops", "min": 1}}]`,
+		"unknown per":   `[{"id": "a", "rule": {"count": "beers", "per": "hops", "min": 1}}]`,
+		"unknown on":    `[{"id": "a", "rule": {"on": "drunk", "count": "beers", "min": 1}}]`,
+		"no min":        `[{"id": "a", "rule": {"count": "beers"}}]`,
+		"duplicate id":  `[{"id": "a", "rule": {"count": "beers", "min": 1}}, {"id": "a", "rule": {"count": "beers", "min": 1}}]`,
+	}
+	for name, definitions := range invalid {
+		if _, err := parseDefinitions([]byte(definitions)); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	facts := []Fact{
+		{Action: Added, BeerID: 1, Style: "American IPA", BrewerID: 1, City: "Wellington", At: day(1)},
+		{Action: Rated, BeerID: 1, Style: "American IPA", BrewerID: 1, City: "Wellington", At: day(1)},
+		{Action: Added, BeerID: 2, Style: "Gose", Category: "European Sour Ale", BrewerID: 2, City: " wellington", At: day(2)},
+		{Action: Added, BeerID: 3, Style: "american ipa", BrewerID: 3, City: "Auckland", At: day(3)},
+		{Action: Rated, BeerID: 3, Style: "american ipa", BrewerID: 3, City: "Auckland", At: day(4)},
+		{Action: Added, BeerID: 4, Style: "Stout", At: day(5)},
+	}
+	badges := []Badge{
+		{ID: "first-sour", Rule: Rule{Category: "european sour ale", Count: "beers", Min: 1}},
+		{ID: "two-rated", Rule: Rule{On: Rated, Count: "beers", Min: 2}},
+		{ID: "three-styles", Rule: Rule{Count: "styles", Min: 3}},
+		{ID: "two-brewers-one-city", Rule: Rule{Count: "brewers", Per: "cities", Min: 2}},
+		{ID: "three-brewers", Rule: Rule{Count: "brewers", Min: 3}},
+	}
+
+	want := []struct {
+		count    int
+		earned   bool
+		earnedAt time.Time
+	}{
+		{1, true, day(2)},
+		{2, true, day(4)},
+		// Styles are compared ignoring case, so there are only three by the stout
+		{3, true, day(5)},
+		{2, true, day(2)},
+		// The stout has no brewer so doesn't count
+		{3, true, day(3)},
+	}
+	progress := Evaluate(badges, facts)
+	for i, w := range want {
+		got := progress[i]
+		if got.Count != w.count || got.Earned != w.earned || !got.EarnedAt.Equal(w.earnedAt) {
+			t.Errorf("%s: got count %d earned %v at %v, want %+v", got.Badge.ID, got.Count, got.Earned, got.EarnedAt, w)
+		}
+	}
+
+	progress = Evaluate([]Badge{{ID: "five-styles", Rule: Rule{Count: "styles", Min: 5}}}, facts)
+	if progress[0].Count != 3 || progress[0].Earned {
+		t.Errorf("got %+v, want 3 of 5 and not earned", progress[0])
+	}
+
+	// None of the BJCP sours have sour in their name, so the embedded badge goes by category,
+	// while a beer given a free text sour style still counts
+	i := slices.IndexFunc(Definitions(), func(b Badge) bool { return b.ID == "first-sour" })
+	if i < 0 {
+		t.Fatal("no first-sour badge in the embedded definitions")
+	}
+	sours := [][]Fact{
+		{{Action: Rated, BeerID: 1, Style: "Gose", Category: "European Sour Ale", At: day(1)}},
+		{{Action: Added, BeerID: 1, Style: "Sour IPA", At: day(1)}},
+	}
+	for _, f := range sours {
+		if progress := Evaluate(Definitions()[i:i+1], f); !progress[0].Earned {
+			t.Errorf("first-sour with %+v: got %+v, want it earned", f, progress[0])
+		}
+	}
+	if progress := Evaluate(Definitions()[i:i+1], facts[:1]); progress[0].Earned {
+		t.Errorf("first-sour with an IPA: got %+v, want it not earned", progress[0])
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	facts := []Fact{
+		{UserID: 1, Username: "alice", BeerID: 1, Style: "IPA", BrewerID: 1},
+		{UserID: 1, Username: "alice", BeerID: 2, Style: "ipa", BrewerID: 2},
+		{UserID: 2, Username: "bob", BeerID: 3, Style: "Stout", BrewerID: 1},
+		{UserID: 2, Username: "bob", BeerID: 4, Style: "Lager", BrewerID: 3},
+		{UserID: 3, Username: "carol", BeerID: 5, Style: "Sour"},
+		{UserID: 4, Username: "dave", BeerID: 6, Style: ""},
+	}
+
+	styles := Leaderboard(facts, "styles")
+	want := []Standing{{2, "bob", 2, 1}, {1, "alice", 1, 2}, {3, "carol", 1, 2}}
+	if len(styles) != len(want) {
+		t.Fatalf("got styles %+v, want %+v", styles, want)
+	}
+	for i := range want {
+		if styles[i] != want[i] {
+			t.Errorf("styles %d: got %+v, want %+v", i, styles[i], want[i])
+		}
+	}
+
+	brewers := Leaderboard(facts, "brewers")
+	want = []Standing{{1, "alice", 2, 1}, {2, "bob", 2, 1}}
+	if len(brewers) != len(want) || brewers[0] != want[0] || brewers[1] != want[1] {
+		t.Errorf("got brewers %+v, want %+v", brewers, want)
+	}
+}
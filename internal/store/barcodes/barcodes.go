@@ -0,0 +1,19 @@
+package barcodes
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+)
+
+// The operations the rest of the app needs on the barcodes of beers, implemented by BarcodeStore
+// (backed by the database) and MemoryBarcodeStore (for tests). Codes are expected to have been
+// checked with ean.Parse already.
+type Store interface {
+	AddBarcode(ctx context.Context, code string, beerId int64) (db.Barcode, error)
+	GetBarcode(ctx context.Context, code string) (db.Barcode, error)
+	GetBeerBarcodes(ctx context.Context, beerId int64) ([]db.Barcode, error)
+	DeleteBarcode(ctx context.Context, code string) (db.Barcode, error)
+}
+
+var _ Store = (*BarcodeStore)(nil)
+var _ Store = (*MemoryBarcodeStore)(nil)
@@ -0,0 +1,19 @@
+package barcodes
+
+import "fmt"
+
+type ErrBarcodeAlreadyExists struct {
+	Code string
+}
+
+func (e ErrBarcodeAlreadyExists) Error() string {
+	return fmt.Sprintf("barcode %s is already on a beer", e.Code)
+}
+
+type ErrBarcodeNotFound struct {
+	Code string
+}
+
+func (e ErrBarcodeNotFound) Error() string {
+	return fmt.Sprintf("barcode %s not found", e.Code)
+}
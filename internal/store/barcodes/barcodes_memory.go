@@ -0,0 +1,101 @@
+package barcodes
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as BarcodeStore. The beer store stands in for the foreign key and for telling
+// whether a beer is in the trash.
+type MemoryBarcodeStore struct {
+	mu        sync.Mutex
+	beerStore beers.Store
+	barcodes  []db.Barcode
+}
+
+func NewMemoryBarcodeStore(beerStore beers.Store) *MemoryBarcodeStore {
+	return &MemoryBarcodeStore{
+		beerStore: beerStore,
+	}
+}
+
+// Whether the beer exists and isn't in the trash
+func (bs *MemoryBarcodeStore) live(ctx context.Context, beerId int64) bool {
+	_, err := bs.beerStore.GetBeer(ctx, beerId)
+	return err == nil
+}
+
+func (bs *MemoryBarcodeStore) AddBarcode(ctx context.Context, code string, beerId int64) (db.Barcode, error) {
+	if !bs.live(ctx, beerId) {
+		return db.Barcode{}, beers.ErrBeerNotFound{ID: beerId}
+	}
+
+	bs.mu.Lock()
+	i := slices.IndexFunc(bs.barcodes, func(b db.Barcode) bool { return b.Code == code })
+	var owner int64
+	if i >= 0 {
+		owner = bs.barcodes[i].BeerID
+	}
+	bs.mu.Unlock()
+
+	// The beer store has its own lock, so check the current owner without holding ours
+	if i >= 0 && bs.live(ctx, owner) {
+		return db.Barcode{}, ErrBarcodeAlreadyExists{Code: code}
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	barcode := db.Barcode{Code: code, BeerID: beerId, CreatedAt: store.Now()}
+	bs.barcodes = slices.DeleteFunc(bs.barcodes, func(b db.Barcode) bool { return b.Code == code })
+	bs.barcodes = append(bs.barcodes, barcode)
+	return barcode, nil
+}
+
+func (bs *MemoryBarcodeStore) GetBarcode(ctx context.Context, code string) (db.Barcode, error) {
+	bs.mu.Lock()
+	i := slices.IndexFunc(bs.barcodes, func(b db.Barcode) bool { return b.Code == code })
+	var barcode db.Barcode
+	if i >= 0 {
+		barcode = bs.barcodes[i]
+	}
+	bs.mu.Unlock()
+
+	if i < 0 || !bs.live(ctx, barcode.BeerID) {
+		return db.Barcode{}, ErrBarcodeNotFound{Code: code}
+	}
+	return barcode, nil
+}
+
+func (bs *MemoryBarcodeStore) GetBeerBarcodes(ctx context.Context, beerId int64) ([]db.Barcode, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	barcodes := []db.Barcode{}
+	for _, b := range bs.barcodes {
+		if b.BeerID == beerId {
+			barcodes = append(barcodes, b)
+		}
+	}
+	slices.SortFunc(barcodes, func(a, b db.Barcode) int { return cmp.Compare(a.Code, b.Code) })
+	return barcodes, nil
+}
+
+func (bs *MemoryBarcodeStore) DeleteBarcode(ctx context.Context, code string) (db.Barcode, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := slices.IndexFunc(bs.barcodes, func(b db.Barcode) bool { return b.Code == code })
+	if i < 0 {
+		return db.Barcode{}, ErrBarcodeNotFound{Code: code}
+	}
+	barcode := bs.barcodes[i]
+	bs.barcodes = slices.Delete(bs.barcodes, i, i+1)
+	return barcode, nil
+}
@@ -0,0 +1,78 @@
+package barcodes
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type BarcodeStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewBarcodeStore(queries db.Querier, logger *log.Logger) *BarcodeStore {
+	return &BarcodeStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Puts the barcode on the beer. A barcode can only be on one beer, but it's taken from beers in
+// the trash.
+func (bs *BarcodeStore) AddBarcode(ctx context.Context, code string, beerId int64) (db.Barcode, error) {
+	barcode, err := bs.queries.AddBarcode(ctx, db.AddBarcodeParams{Code: code, BeerID: beerId})
+	if err != nil {
+		// Nothing is returned when the code is already on a beer which isn't in the trash
+		if err == sql.ErrNoRows {
+			return db.Barcode{}, ErrBarcodeAlreadyExists{Code: code}
+		}
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Barcode{}, beers.ErrBeerNotFound{ID: beerId}
+		}
+		bs.logger.Printf("error adding barcode: %v", err)
+		return db.Barcode{}, err
+	}
+
+	bs.logger.Printf("barcode added: %v", barcode)
+	return barcode, nil
+}
+
+// The barcode, as long as its beer isn't in the trash
+func (bs *BarcodeStore) GetBarcode(ctx context.Context, code string) (db.Barcode, error) {
+	barcode, err := bs.queries.GetBarcode(ctx, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Barcode{}, ErrBarcodeNotFound{Code: code}
+		}
+		bs.logger.Printf("error getting barcode: %v", err)
+		return db.Barcode{}, err
+	}
+	return barcode, nil
+}
+
+func (bs *BarcodeStore) GetBeerBarcodes(ctx context.Context, beerId int64) ([]db.Barcode, error) {
+	barcodes, err := bs.queries.GetBeerBarcodes(ctx, beerId)
+	if err != nil {
+		bs.logger.Printf("error getting beer barcodes: %v", err)
+		return nil, err
+	}
+	return barcodes, nil
+}
+
+func (bs *BarcodeStore) DeleteBarcode(ctx context.Context, code string) (db.Barcode, error) {
+	barcode, err := bs.queries.DeleteBarcode(ctx, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Barcode{}, ErrBarcodeNotFound{Code: code}
+		}
+		bs.logger.Printf("error deleting barcode: %v", err)
+		return db.Barcode{}, err
+	}
+
+	bs.logger.Printf("barcode deleted: %v", barcode)
+	return barcode, nil
+}
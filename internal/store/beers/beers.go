@@ -0,0 +1,37 @@
+package beers
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// The operations the rest of the app needs on beers, implemented by BeerStore (backed by the
+// database) and MemoryBeerStore (for tests)
+type Store interface {
+	AddBeer(ctx context.Context, authorId int64, params db.AddBeerParams) (db.Beer, error)
+	GetBeer(ctx context.Context, id int64) (db.Beer, error)
+	GetBeers(ctx context.Context) ([]db.Beer, error)
+	DeleteBeer(ctx context.Context, id int64) (db.Beer, error)
+	RestoreBeer(ctx context.Context, id int64) (db.Beer, error)
+	GetDeletedBeers(ctx context.Context) ([]db.Beer, error)
+	PurgeBeer(ctx context.Context, id int64) (db.Beer, error)
+	PurgeDeletedBeers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	CountBeers(ctx context.Context) (int64, error)
+	UpdateBeer(ctx context.Context, authorId int64, params db.UpdateBeerParams) (db.Beer, error)
+	SearchBeers(ctx context.Context, query sql.NullString) ([]db.Beer, error)
+	GetBeerStyles(ctx context.Context) ([]string, error)
+	// Changes the style of every beer with the old style to the new one, recording a revision with
+	// no author for each
+	RenameBeerStyle(ctx context.Context, oldStyle string, newStyle string) (int64, error)
+	// Sets the ratings of the beers given by id, for when they're worked out again from the
+	// scorecards. No revisions are recorded, since the scorecards are the record of how a rating
+	// came about, and changing the weights would otherwise add a revision to every scored beer.
+	SetBeerRatings(ctx context.Context, ratings map[int64]float64) error
+	GetBeerHistory(ctx context.Context, id int64) ([]Revision, error)
+	RevertBeer(ctx context.Context, authorId int64, id int64, revisionId int64) (db.Beer, error)
+}
+
+var _ Store = (*BeerStore)(nil)
+var _ Store = (*MemoryBeerStore)(nil)
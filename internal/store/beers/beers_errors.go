@@ -9,3 +9,12 @@ type ErrBeerNotFound struct {
 func (e ErrBeerNotFound) Error() string {
 	return fmt.Sprintf("beer with id %d not found", e.ID)
 }
+
+type ErrBeerRevisionNotFound struct {
+	BeerID     int64
+	RevisionID int64
+}
+
+func (e ErrBeerRevisionNotFound) Error() string {
+	return fmt.Sprintf("revision with id %d not found for beer with id %d", e.RevisionID, e.BeerID)
+}
@@ -0,0 +1,55 @@
+package beers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/events"
+	"context"
+)
+
+// A Store which publishes an event each time a beer is added, changed, deleted or restored, so
+// open pages can show it. Wraps either of the other implementations.
+type PublishingBeerStore struct {
+	Store
+	publisher events.Publisher
+}
+
+var _ Store = (*PublishingBeerStore)(nil)
+
+func NewPublishingBeerStore(store Store, publisher events.Publisher) *PublishingBeerStore {
+	return &PublishingBeerStore{Store: store, publisher: publisher}
+}
+
+// Publishes the action on the beer if the change it's from went through, once any transaction it's
+// part of has been committed so the change can be seen
+func (ps *PublishingBeerStore) publish(ctx context.Context, action string, beer db.Beer, err error) (db.Beer, error) {
+	if err == nil {
+		event := events.Event{Topic: events.Beers, Action: action, ID: beer.ID}
+		db.AfterCommit(ctx, func() { ps.publisher.Publish(event) })
+	}
+	return beer, err
+}
+
+func (ps *PublishingBeerStore) AddBeer(ctx context.Context, authorId int64, params db.AddBeerParams) (db.Beer, error) {
+	beer, err := ps.Store.AddBeer(ctx, authorId, params)
+	return ps.publish(ctx, events.Created, beer, err)
+}
+
+func (ps *PublishingBeerStore) UpdateBeer(ctx context.Context, authorId int64, params db.UpdateBeerParams) (db.Beer, error) {
+	beer, err := ps.Store.UpdateBeer(ctx, authorId, params)
+	return ps.publish(ctx, events.Updated, beer, err)
+}
+
+func (ps *PublishingBeerStore) RevertBeer(ctx context.Context, authorId int64, id int64, revisionId int64) (db.Beer, error) {
+	beer, err := ps.Store.RevertBeer(ctx, authorId, id, revisionId)
+	return ps.publish(ctx, events.Updated, beer, err)
+}
+
+func (ps *PublishingBeerStore) DeleteBeer(ctx context.Context, id int64) (db.Beer, error) {
+	beer, err := ps.Store.DeleteBeer(ctx, id)
+	return ps.publish(ctx, events.Deleted, beer, err)
+}
+
+func (ps *PublishingBeerStore) RestoreBeer(ctx context.Context, id int64) (db.Beer, error) {
+	beer, err := ps.Store.RestoreBeer(ctx, id)
+	return ps.publish(ctx, events.Restored, beer, err)
+}
@@ -0,0 +1,84 @@
+package beers
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"fmt"
+)
+
+// A single field which differs between a revision and the one before it
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// A revision of a beer along with what changed since the previous revision. The first revision
+// of a beer lists every field which was set when it was added.
+type Revision struct {
+	db.GetBeerRevisionsRow
+	Number  int
+	Changes []FieldChange
+}
+
+// Pairs up each revision (ordered oldest first) with the one before it to work out the changes
+func diffRevisions(rows []db.GetBeerRevisionsRow) []Revision {
+	revisions := make([]Revision, len(rows))
+	var prev *db.GetBeerRevisionsRow
+	for i, row := range rows {
+		revisions[i] = Revision{
+			GetBeerRevisionsRow: row,
+			Number:              i + 1,
+			Changes:             diffRevision(prev, row),
+		}
+		prev = &rows[i]
+	}
+	return revisions
+}
+
+func diffRevision(prev *db.GetBeerRevisionsRow, curr db.GetBeerRevisionsRow) []FieldChange {
+	if prev == nil {
+		prev = &db.GetBeerRevisionsRow{}
+	}
+	from := revisionFields(*prev)
+	to := revisionFields(curr)
+
+	changes := []FieldChange{}
+	for i := range to {
+		if from[i][1] != to[i][1] {
+			changes = append(changes, FieldChange{Field: to[i][0], From: from[i][1], To: to[i][1]})
+		}
+	}
+	return changes
+}
+
+// The displayed fields of a revision as (name, value) pairs, in the order they appear on the form
+func revisionFields(row db.GetBeerRevisionsRow) [][2]string {
+	rev := row.BeerRevision
+	brewer := ""
+	if rev.BrewerID.Valid {
+		brewer = fmt.Sprintf("#%d", rev.BrewerID.Int64)
+		if row.BrewerName.Valid {
+			brewer = row.BrewerName.String
+		}
+	}
+	abv := ""
+	if rev.ID != 0 {
+		abv = fmt.Sprintf("%.2f%%", rev.Abv)
+	}
+	return [][2]string{
+		{"Brewer", brewer},
+		{"Name", rev.Name},
+		{"Style", rev.Style.String},
+		{"ABV", abv},
+		{"Rating", formatNullFloat(rev.Rating)},
+		{"Notes", rev.Notes.String},
+	}
+}
+
+func formatNullFloat(f sql.NullFloat64) string {
+	if !f.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", f.Float64)
+}
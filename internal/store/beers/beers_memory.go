@@ -0,0 +1,399 @@
+package beers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as BeerStore. The brewer and user stores stand in for the foreign keys.
+type MemoryBeerStore struct {
+	mu             sync.Mutex
+	brewerStore    brewers.Store
+	userStore      users.Store
+	lastId         int64
+	beers          []db.Beer
+	lastRevisionId int64
+	revisions      []db.BeerRevision
+}
+
+func NewMemoryBeerStore(brewerStore brewers.Store, userStore users.Store) *MemoryBeerStore {
+	return &MemoryBeerStore{
+		brewerStore: brewerStore,
+		userStore:   userStore,
+	}
+}
+
+// The index of the beer with the given id, or -1 if there isn't one. Must be called with the lock
+// held.
+func (bs *MemoryBeerStore) find(id int64, deleted bool) int {
+	return slices.IndexFunc(bs.beers, func(b db.Beer) bool {
+		return b.ID == id && b.DeletedAt.Valid == deleted
+	})
+}
+
+// Returns an error if another beer already has this name and brewer. Like the unique constraint
+// in the database, beers without a brewer never clash. Must be called with the lock held.
+func (bs *MemoryBeerStore) clash(id int64, name string, brewerId sql.NullInt64) error {
+	if !brewerId.Valid {
+		return nil
+	}
+	i := slices.IndexFunc(bs.beers, func(b db.Beer) bool {
+		return b.ID != id && b.Name == name && b.BrewerID == brewerId
+	})
+	if i < 0 {
+		return nil
+	}
+	return store.ErrBeerAlreadyExists{BrewerId: brewerId.Int64, Name: name, Deleted: bs.beers[i].DeletedAt.Valid}
+}
+
+func (bs *MemoryBeerStore) checkBrewer(ctx context.Context, brewerId sql.NullInt64) error {
+	if !brewerId.Valid {
+		return nil
+	}
+	if _, err := bs.brewerStore.GetBrewer(ctx, brewerId.Int64); err != nil {
+		return store.ErrBrewerNotFound{ID: brewerId.Int64}
+	}
+	return nil
+}
+
+// Must be called with the lock held
+func (bs *MemoryBeerStore) addRevision(authorId int64, beer db.Beer) {
+	bs.lastRevisionId++
+	bs.revisions = append(bs.revisions, db.BeerRevision{
+		ID:        bs.lastRevisionId,
+		BeerID:    beer.ID,
+		UserID:    sql.NullInt64{Valid: authorId != 0, Int64: authorId},
+		CreatedAt: store.Now(),
+		Name:      beer.Name,
+		BrewerID:  beer.BrewerID,
+		Style:     beer.Style,
+		Abv:       beer.Abv,
+		Rating:    beer.Rating,
+		Notes:     beer.Notes,
+	})
+}
+
+func (bs *MemoryBeerStore) AddBeer(ctx context.Context, authorId int64, params db.AddBeerParams) (db.Beer, error) {
+	zero := db.Beer{}
+
+	if params.Name == "" {
+		return zero, store.ErrMissingField{Field: "name"}
+	}
+	if params.Abv < 0 {
+		return zero, store.ErrInvalidField{Field: "abv", Reason: "must be >= 0"}
+	}
+	if !params.Rating.Valid {
+		return zero, store.ErrMissingField{Field: "rating"}
+	} else if params.Rating.Float64 < 0 || params.Rating.Float64 > 10 {
+		return zero, store.ErrInvalidField{Field: "rating", Reason: "must be between 0 and 10"}
+	}
+	if err := bs.checkBrewer(ctx, params.BrewerID); err != nil {
+		return zero, err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if err := bs.clash(0, params.Name, params.BrewerID); err != nil {
+		return zero, err
+	}
+
+	bs.lastId++
+	beer := db.Beer{
+		ID:       bs.lastId,
+		Name:     params.Name,
+		BrewerID: params.BrewerID,
+		Style:    params.Style,
+		Abv:      params.Abv,
+		Rating:   params.Rating,
+		Notes:    params.Notes,
+	}
+	bs.beers = append(bs.beers, beer)
+	bs.addRevision(authorId, beer)
+	return beer, nil
+}
+
+func (bs *MemoryBeerStore) GetBeer(ctx context.Context, id int64) (db.Beer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, false)
+	if i < 0 {
+		return db.Beer{}, ErrBeerNotFound{ID: id}
+	}
+	return bs.beers[i], nil
+}
+
+func (bs *MemoryBeerStore) GetBeers(ctx context.Context) ([]db.Beer, error) {
+	return bs.filter(func(b db.Beer) bool { return !b.DeletedAt.Valid }), nil
+}
+
+// The beers matching the predicate, in the order they were added
+func (bs *MemoryBeerStore) filter(pred func(db.Beer) bool) []db.Beer {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	beers := []db.Beer{}
+	for _, b := range bs.beers {
+		if pred(b) {
+			beers = append(beers, b)
+		}
+	}
+	return beers
+}
+
+func (bs *MemoryBeerStore) DeleteBeer(ctx context.Context, id int64) (db.Beer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, false)
+	if i < 0 {
+		return db.Beer{}, ErrBeerNotFound{ID: id}
+	}
+	bs.beers[i].DeletedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	return bs.beers[i], nil
+}
+
+func (bs *MemoryBeerStore) RestoreBeer(ctx context.Context, id int64) (db.Beer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, true)
+	if i < 0 {
+		return db.Beer{}, ErrBeerNotFound{ID: id}
+	}
+	bs.beers[i].DeletedAt = sql.NullTime{}
+	return bs.beers[i], nil
+}
+
+func (bs *MemoryBeerStore) GetDeletedBeers(ctx context.Context) ([]db.Beer, error) {
+	beers := bs.filter(func(b db.Beer) bool { return b.DeletedAt.Valid })
+	slices.SortStableFunc(beers, func(a, b db.Beer) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	})
+	return beers, nil
+}
+
+// Must be called with the lock held
+func (bs *MemoryBeerStore) purge(purged func(db.Beer) bool) int64 {
+	ids := []int64{}
+	bs.beers = slices.DeleteFunc(bs.beers, func(b db.Beer) bool {
+		if purged(b) {
+			ids = append(ids, b.ID)
+			return true
+		}
+		return false
+	})
+	bs.revisions = slices.DeleteFunc(bs.revisions, func(r db.BeerRevision) bool {
+		return slices.Contains(ids, r.BeerID)
+	})
+	return int64(len(ids))
+}
+
+func (bs *MemoryBeerStore) PurgeBeer(ctx context.Context, id int64) (db.Beer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, true)
+	if i < 0 {
+		return db.Beer{}, ErrBeerNotFound{ID: id}
+	}
+	beer := bs.beers[i]
+	bs.purge(func(b db.Beer) bool { return b.ID == id })
+	return beer, nil
+}
+
+func (bs *MemoryBeerStore) PurgeDeletedBeers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	return bs.purge(func(b db.Beer) bool {
+		return b.DeletedAt.Valid && b.DeletedAt.Time.Before(deletedBefore)
+	}), nil
+}
+
+func (bs *MemoryBeerStore) CountBeers(ctx context.Context) (int64, error) {
+	beers, _ := bs.GetBeers(ctx)
+	return int64(len(beers)), nil
+}
+
+func (bs *MemoryBeerStore) UpdateBeer(ctx context.Context, authorId int64, params db.UpdateBeerParams) (db.Beer, error) {
+	zero := db.Beer{}
+
+	if params.Name.String == "" {
+		return zero, store.ErrMissingField{Field: "name"}
+	}
+	if params.Rating.Float64 < 0 || params.Rating.Float64 > 10 {
+		return zero, store.ErrInvalidField{Field: "rating", Reason: "must be between 0 and 10"}
+	}
+
+	// Only the fields which are set are updated, like the coalesce in the query
+	return bs.save(ctx, authorId, params.ID, params.BrewerID, func(beer *db.Beer) {
+		if params.Name.Valid {
+			beer.Name = params.Name.String
+		}
+		if params.BrewerID.Valid {
+			beer.BrewerID = params.BrewerID
+		}
+		if params.Style.Valid {
+			beer.Style = params.Style
+		}
+		if params.Abv.Valid {
+			beer.Abv = params.Abv.Float64
+		}
+		if params.Rating.Valid {
+			beer.Rating = params.Rating
+		}
+		if params.Notes.Valid {
+			beer.Notes = params.Notes
+		}
+	})
+}
+
+// Applies the change to a beer which isn't in the trash and records it as a new revision
+func (bs *MemoryBeerStore) save(ctx context.Context, authorId int64, id int64, brewerId sql.NullInt64, change func(beer *db.Beer)) (db.Beer, error) {
+	zero := db.Beer{}
+
+	bs.mu.Lock()
+	i := bs.find(id, false)
+	bs.mu.Unlock()
+	if i < 0 {
+		return zero, ErrBeerNotFound{ID: id}
+	}
+	if err := bs.checkBrewer(ctx, brewerId); err != nil {
+		return zero, err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	// Look the beer up again in case it changed while the lock was released
+	i = bs.find(id, false)
+	if i < 0 {
+		return zero, ErrBeerNotFound{ID: id}
+	}
+
+	beer := bs.beers[i]
+	change(&beer)
+
+	if err := bs.clash(beer.ID, beer.Name, beer.BrewerID); err != nil {
+		return zero, err
+	}
+
+	bs.beers[i] = beer
+	bs.addRevision(authorId, beer)
+	return beer, nil
+}
+
+func (bs *MemoryBeerStore) SearchBeers(ctx context.Context, query sql.NullString) ([]db.Beer, error) {
+	// LIKE is case-insensitive in the database
+	q := strings.ToLower(query.String)
+	matches := func(s string) bool { return strings.Contains(strings.ToLower(s), q) }
+
+	return bs.filter(func(b db.Beer) bool {
+		return !b.DeletedAt.Valid && (matches(b.Name) || matches(b.Style.String) || matches(b.Notes.String))
+	}), nil
+}
+
+func (bs *MemoryBeerStore) GetBeerStyles(ctx context.Context) ([]string, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	styles := []string{}
+	for _, b := range bs.beers {
+		if b.Style.String != "" && !slices.Contains(styles, b.Style.String) {
+			styles = append(styles, b.Style.String)
+		}
+	}
+	slices.Sort(styles)
+	return styles, nil
+}
+
+func (bs *MemoryBeerStore) RenameBeerStyle(ctx context.Context, oldStyle string, newStyle string) (int64, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	count := int64(0)
+	for i := range bs.beers {
+		if bs.beers[i].Style.Valid && bs.beers[i].Style.String == oldStyle {
+			bs.beers[i].Style = sql.NullString{Valid: true, String: newStyle}
+			bs.addRevision(0, bs.beers[i])
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (bs *MemoryBeerStore) SetBeerRatings(ctx context.Context, ratings map[int64]float64) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	for i, beer := range bs.beers {
+		if rating, ok := ratings[beer.ID]; ok {
+			bs.beers[i].Rating = sql.NullFloat64{Valid: true, Float64: rating}
+		}
+	}
+	return nil
+}
+
+func (bs *MemoryBeerStore) GetBeerHistory(ctx context.Context, id int64) ([]Revision, error) {
+	bs.mu.Lock()
+	revisions := []db.BeerRevision{}
+	for _, r := range bs.revisions {
+		if r.BeerID == id {
+			revisions = append(revisions, r)
+		}
+	}
+	bs.mu.Unlock()
+
+	// Join in the author and brewer names the way the query does
+	rows := make([]db.GetBeerRevisionsRow, len(revisions))
+	for i, r := range revisions {
+		rows[i].BeerRevision = r
+		if user, err := bs.userStore.GetUser(ctx, r.UserID.Int64); r.UserID.Valid && err == nil {
+			rows[i].Author = sql.NullString{Valid: true, String: user.Username}
+		}
+		if brewer, err := bs.brewerStore.GetBrewer(ctx, r.BrewerID.Int64); r.BrewerID.Valid && err == nil {
+			rows[i].BrewerName = sql.NullString{Valid: true, String: brewer.Name}
+		}
+	}
+
+	history := diffRevisions(rows)
+	slices.Reverse(history)
+	return history, nil
+}
+
+func (bs *MemoryBeerStore) RevertBeer(ctx context.Context, authorId int64, id int64, revisionId int64) (db.Beer, error) {
+	bs.mu.Lock()
+	i := slices.IndexFunc(bs.revisions, func(r db.BeerRevision) bool {
+		return r.ID == revisionId && r.BeerID == id
+	})
+	var revision db.BeerRevision
+	if i >= 0 {
+		revision = bs.revisions[i]
+	}
+	bs.mu.Unlock()
+
+	if i < 0 {
+		return db.Beer{}, ErrBeerRevisionNotFound{BeerID: id, RevisionID: revisionId}
+	}
+
+	// Every field is put back, including those which were empty at the revision
+	return bs.save(ctx, authorId, id, revision.BrewerID, func(beer *db.Beer) {
+		beer.Name = revision.Name
+		beer.BrewerID = revision.BrewerID
+		beer.Style = revision.Style
+		beer.Abv = revision.Abv
+		beer.Rating = revision.Rating
+		beer.Notes = revision.Notes
+	})
+}
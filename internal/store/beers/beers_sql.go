@@ -0,0 +1,362 @@
+package beers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+type BeerStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewBeerStore(queries db.Querier, logger *log.Logger) *BeerStore {
+	return &BeerStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Adds a beer and records it as the first revision in the beer's history, attributed to the given
+// author
+func (bs *BeerStore) AddBeer(ctx context.Context, authorId int64, params db.AddBeerParams) (db.Beer, error) {
+	zero := db.Beer{}
+
+	if params.Name == "" {
+		return zero, store.ErrMissingField{Field: "name"}
+	}
+	if params.Abv < 0 {
+		return zero, store.ErrInvalidField{Field: "abv", Reason: "must be >= 0"}
+	}
+	if !params.Rating.Valid {
+		return zero, store.ErrMissingField{Field: "rating"}
+	} else if params.Rating.Float64 < 0 || params.Rating.Float64 > 10 {
+		return zero, store.ErrInvalidField{Field: "rating", Reason: "must be between 0 and 10"}
+	}
+
+	// The beer and its first revision are saved together, so a beer never goes without its history
+	var beer db.Beer
+	err := db.InTx(ctx, bs.queries, func(ctx context.Context, queries db.Querier) error {
+		var err error
+		if beer, err = queries.AddBeer(ctx, params); err != nil {
+			return err
+		}
+		return bs.addRevision(ctx, queries, authorId, beer)
+	})
+	if err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.ForeignKeyConstraint:
+			return zero, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+		case store.UniqueConstraint:
+			return zero, bs.alreadyExists(ctx, params.Name, params.BrewerID)
+		}
+		bs.logger.Printf("error adding beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer added: %v", beer)
+	return beer, nil
+}
+
+func (bs *BeerStore) GetBeer(ctx context.Context, id int64) (db.Beer, error) {
+	beer, err := db.Joined(ctx, bs.queries).GetBeerById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Beer{}, ErrBeerNotFound{ID: id}
+		}
+		bs.logger.Printf("error getting beer: %v", err)
+		return db.Beer{}, err
+	}
+	return beer, nil
+}
+
+func (bs *BeerStore) GetBeers(ctx context.Context) ([]db.Beer, error) {
+	beers, err := db.Joined(ctx, bs.queries).GetBeers(ctx)
+	if err != nil {
+		bs.logger.Printf("error getting beers: %v", err)
+		return nil, err
+	}
+	return beers, nil
+}
+
+func (bs *BeerStore) DeleteBeer(ctx context.Context, id int64) (db.Beer, error) {
+	zero := db.Beer{}
+
+	beer, err := db.Joined(ctx, bs.queries).DeleteBeer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerNotFound{ID: id}
+		}
+		bs.logger.Printf("error deleting beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer deleted: %v", beer)
+	return beer, nil
+}
+
+func (bs *BeerStore) RestoreBeer(ctx context.Context, id int64) (db.Beer, error) {
+	zero := db.Beer{}
+
+	beer, err := db.Joined(ctx, bs.queries).RestoreBeer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerNotFound{ID: id}
+		}
+		bs.logger.Printf("error restoring beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer restored: %v", beer)
+	return beer, nil
+}
+
+func (bs *BeerStore) GetDeletedBeers(ctx context.Context) ([]db.Beer, error) {
+	beers, err := db.Joined(ctx, bs.queries).GetDeletedBeers(ctx)
+	if err != nil {
+		bs.logger.Printf("error getting deleted beers: %v", err)
+		return nil, err
+	}
+	return beers, nil
+}
+
+// Builds the error for a name clash, noting when the other beer is in the trash
+func (bs *BeerStore) alreadyExists(ctx context.Context, name string, brewerId sql.NullInt64) store.ErrBeerAlreadyExists {
+	beers, err := bs.GetDeletedBeers(ctx)
+	deleted := err == nil && slices.ContainsFunc(beers, func(b db.Beer) bool { return b.Name == name && b.BrewerID == brewerId })
+	return store.ErrBeerAlreadyExists{BrewerId: brewerId.Int64, Name: name, Deleted: deleted}
+}
+
+// Permanently removes a beer which has already been moved to the trash
+func (bs *BeerStore) PurgeBeer(ctx context.Context, id int64) (db.Beer, error) {
+	zero := db.Beer{}
+
+	beer, err := db.Joined(ctx, bs.queries).PurgeBeer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerNotFound{ID: id}
+		}
+		bs.logger.Printf("error purging beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer purged: %v", beer)
+	return beer, nil
+}
+
+// Permanently removes every beer that was moved to the trash before the given time, returning
+// how many were removed
+func (bs *BeerStore) PurgeDeletedBeers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	count, err := db.Joined(ctx, bs.queries).PurgeDeletedBeers(ctx, sql.NullTime{Valid: true, Time: deletedBefore.UTC()})
+	if err != nil {
+		bs.logger.Printf("error purging deleted beers: %v", err)
+		return 0, err
+	}
+	if count > 0 {
+		bs.logger.Printf("purged %d deleted beers", count)
+	}
+	return count, nil
+}
+
+func (bs *BeerStore) CountBeers(ctx context.Context) (int64, error) {
+	count, err := db.Joined(ctx, bs.queries).CountBeers(ctx)
+	if err != nil {
+		bs.logger.Printf("error counting beers: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Updates a beer and records the result as a new revision in the beer's history, attributed to the
+// given author
+func (bs *BeerStore) UpdateBeer(ctx context.Context, authorId int64, params db.UpdateBeerParams) (db.Beer, error) {
+	zero := db.Beer{}
+
+	if params.Name.String == "" {
+		return zero, store.ErrMissingField{Field: "name"}
+	}
+	if params.Rating.Float64 < 0 || params.Rating.Float64 > 10 {
+		return zero, store.ErrInvalidField{Field: "rating", Reason: "must be between 0 and 10"}
+	}
+
+	var beer db.Beer
+	err := db.InTx(ctx, bs.queries, func(ctx context.Context, queries db.Querier) error {
+		var err error
+		if beer, err = queries.UpdateBeer(ctx, params); err != nil {
+			return err
+		}
+		return bs.addRevision(ctx, queries, authorId, beer)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerNotFound{ID: params.ID}
+		}
+		switch store.ViolatedConstraint(err) {
+		case store.ForeignKeyConstraint:
+			return zero, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+		case store.UniqueConstraint:
+			// The brewer is left as it was when the update doesn't name one
+			brewerId := params.BrewerID
+			if !brewerId.Valid {
+				if current, err := db.Joined(ctx, bs.queries).GetBeerById(ctx, params.ID); err == nil {
+					brewerId = current.BrewerID
+				}
+			}
+			return zero, bs.alreadyExists(ctx, params.Name.String, brewerId)
+		}
+		bs.logger.Printf("error updating beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer updated: %v", beer)
+	return beer, nil
+}
+
+func (bs *BeerStore) SearchBeers(ctx context.Context, query sql.NullString) ([]db.Beer, error) {
+	beers, err := db.Joined(ctx, bs.queries).SearchBeers(ctx, query)
+	if err != nil {
+		bs.logger.Printf("error searching beers: %v", err)
+		return nil, err
+	}
+	return beers, nil
+}
+
+// The distinct styles given to beers, including those in the trash
+func (bs *BeerStore) GetBeerStyles(ctx context.Context) ([]string, error) {
+	rows, err := db.Joined(ctx, bs.queries).GetBeerStyles(ctx)
+	if err != nil {
+		bs.logger.Printf("error getting beer styles: %v", err)
+		return nil, err
+	}
+	styles := make([]string, len(rows))
+	for i, style := range rows {
+		styles[i] = style.String
+	}
+	return styles, nil
+}
+
+// Changes the style of every beer with the old style to the new one, returning how many changed.
+// The app rather than anyone in particular made the change, so the revisions have no author.
+func (bs *BeerStore) RenameBeerStyle(ctx context.Context, oldStyle string, newStyle string) (int64, error) {
+	var renamed []db.Beer
+	err := db.InTx(ctx, bs.queries, func(ctx context.Context, queries db.Querier) error {
+		var err error
+		renamed, err = queries.RenameBeerStyle(ctx, db.RenameBeerStyleParams{
+			OldStyle: sql.NullString{Valid: true, String: oldStyle},
+			NewStyle: sql.NullString{Valid: true, String: newStyle},
+		})
+		if err != nil {
+			return err
+		}
+		for _, beer := range renamed {
+			if err := bs.addRevision(ctx, queries, 0, beer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		bs.logger.Printf("error renaming beer style: %v", err)
+		return 0, err
+	}
+	return int64(len(renamed)), nil
+}
+
+func (bs *BeerStore) SetBeerRatings(ctx context.Context, ratings map[int64]float64) error {
+	for _, id := range slices.Sorted(maps.Keys(ratings)) {
+		err := db.Joined(ctx, bs.queries).SetBeerRating(ctx, db.SetBeerRatingParams{
+			ID:     id,
+			Rating: sql.NullFloat64{Valid: true, Float64: ratings[id]},
+		})
+		if err != nil {
+			bs.logger.Printf("error setting beer rating: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshots the beer into its history, with the queries of the transaction the beer was saved in
+func (bs *BeerStore) addRevision(ctx context.Context, queries db.Querier, authorId int64, beer db.Beer) error {
+	_, err := queries.AddBeerRevision(ctx, db.AddBeerRevisionParams{
+		BeerID:   beer.ID,
+		UserID:   sql.NullInt64{Valid: authorId != 0, Int64: authorId},
+		Name:     beer.Name,
+		BrewerID: beer.BrewerID,
+		Style:    beer.Style,
+		Abv:      beer.Abv,
+		Rating:   beer.Rating,
+		Notes:    beer.Notes,
+	})
+	if err != nil {
+		bs.logger.Printf("error adding revision for beer %d: %v", beer.ID, err)
+	}
+	return err
+}
+
+// Gets every revision of a beer, newest first, along with what changed in each one
+func (bs *BeerStore) GetBeerHistory(ctx context.Context, id int64) ([]Revision, error) {
+	rows, err := db.Joined(ctx, bs.queries).GetBeerRevisions(ctx, id)
+	if err != nil {
+		bs.logger.Printf("error getting beer revisions: %v", err)
+		return nil, err
+	}
+
+	revisions := diffRevisions(rows)
+	slices.Reverse(revisions)
+	return revisions, nil
+}
+
+// Puts a beer back the way it was at the given revision, which is recorded as a new revision
+func (bs *BeerStore) RevertBeer(ctx context.Context, authorId int64, id int64, revisionId int64) (db.Beer, error) {
+	zero := db.Beer{}
+
+	revision, err := db.Joined(ctx, bs.queries).GetBeerRevision(ctx, db.GetBeerRevisionParams{ID: revisionId, BeerID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerRevisionNotFound{BeerID: id, RevisionID: revisionId}
+		}
+		bs.logger.Printf("error getting beer revision: %v", err)
+		return zero, err
+	}
+
+	var beer db.Beer
+	err = db.InTx(ctx, bs.queries, func(ctx context.Context, queries db.Querier) error {
+		var err error
+		beer, err = queries.RevertBeer(ctx, db.RevertBeerParams{
+			ID:       id,
+			Name:     revision.Name,
+			BrewerID: revision.BrewerID,
+			Style:    revision.Style,
+			Abv:      revision.Abv,
+			Rating:   revision.Rating,
+			Notes:    revision.Notes,
+		})
+		if err != nil {
+			return err
+		}
+		return bs.addRevision(ctx, queries, authorId, beer)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrBeerNotFound{ID: id}
+		}
+		switch store.ViolatedConstraint(err) {
+		case store.ForeignKeyConstraint:
+			return zero, store.ErrBrewerNotFound{ID: revision.BrewerID.Int64}
+		case store.UniqueConstraint:
+			return zero, bs.alreadyExists(ctx, revision.Name, revision.BrewerID)
+		}
+		bs.logger.Printf("error reverting beer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("beer reverted: %v", beer)
+	return beer, nil
+}
@@ -0,0 +1,31 @@
+// Package blobs stores the contents of files, such as label photos, by key. Keys are slash
+// separated paths like "labels/1/photo.jpg".
+package blobs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// Where blobs are kept, implemented by FileBlobStore (a directory on disk) and MemoryBlobStore (for
+// tests)
+type Store interface {
+	Put(ctx context.Context, key string, contents io.Reader) error
+	// The caller must close the contents
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Deleting a blob which doesn't exist isn't an error, so cleaning up can be retried
+	Delete(ctx context.Context, key string) error
+}
+
+var _ Store = (*FileBlobStore)(nil)
+var _ Store = (*MemoryBlobStore)(nil)
+
+// Keys must be relative paths without any . or .. elements, so they can't escape a directory
+func validateKey(key string) error {
+	if !fs.ValidPath(key) || key == "." || strings.Contains(key, `\`) {
+		return ErrInvalidKey{Key: key}
+	}
+	return nil
+}
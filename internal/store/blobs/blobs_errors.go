@@ -0,0 +1,19 @@
+package blobs
+
+import "fmt"
+
+type ErrInvalidKey struct {
+	Key string
+}
+
+func (e ErrInvalidKey) Error() string {
+	return fmt.Sprintf("blob key %q is invalid", e.Key)
+}
+
+type ErrBlobNotFound struct {
+	Key string
+}
+
+func (e ErrBlobNotFound) Error() string {
+	return fmt.Sprintf("blob %s not found", e.Key)
+}
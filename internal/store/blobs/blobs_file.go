@@ -0,0 +1,100 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Keeps each blob as a file under a directory, at the path of its key
+type FileBlobStore struct {
+	dir    string
+	logger *log.Logger
+}
+
+// Creates the directory if it doesn't exist yet
+func NewFileBlobStore(dir string, logger *log.Logger) (*FileBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &FileBlobStore{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+func (bs *FileBlobStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(bs.dir, filepath.FromSlash(key)), nil
+}
+
+// Writes to a temporary file which is renamed into place, so a blob is never seen half written
+func (bs *FileBlobStore) Put(ctx context.Context, key string, contents io.Reader) error {
+	path, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		bs.logger.Printf("error creating blob directory: %v", err)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		bs.logger.Printf("error creating blob: %v", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, contents); err != nil {
+		tmp.Close()
+		bs.logger.Printf("error writing blob: %v", err)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		bs.logger.Printf("error writing blob: %v", err)
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		bs.logger.Printf("error writing blob: %v", err)
+		return err
+	}
+
+	bs.logger.Printf("blob stored: %s", key)
+	return nil
+}
+
+func (bs *FileBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := bs.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrBlobNotFound{Key: key}
+		}
+		bs.logger.Printf("error reading blob: %v", err)
+		return nil, err
+	}
+	return f, nil
+}
+
+func (bs *FileBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		bs.logger.Printf("error deleting blob: %v", err)
+		return err
+	}
+	bs.logger.Printf("blob deleted: %s", key)
+	return nil
+}
@@ -0,0 +1,71 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which returns the same errors as FileBlobStore
+type MemoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+func (bs *MemoryBlobStore) Put(ctx context.Context, key string, contents io.Reader) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.blobs[key] = data
+	return nil
+}
+
+func (bs *MemoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	data, ok := bs.blobs[key]
+	if !ok {
+		return nil, ErrBlobNotFound{Key: key}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (bs *MemoryBlobStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	delete(bs.blobs, key)
+	return nil
+}
+
+// How many blobs are stored, so tests can check they're cleaned up
+func (bs *MemoryBlobStore) Len() int {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	return len(bs.blobs)
+}
@@ -0,0 +1,58 @@
+package blobs
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestBlobStores(t *testing.T) {
+	fileStore, err := NewFileBlobStore(t.TempDir(), log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, bs := range map[string]Store{"file": fileStore, "memory": NewMemoryBlobStore()} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for _, key := range []string{"", ".", "../escape", "/abs", "labels/../../escape", `labels\1`} {
+				if err := bs.Put(ctx, key, strings.NewReader("x")); err != (ErrInvalidKey{Key: key}) {
+					t.Errorf("putting blob with key %q: got %v", key, err)
+				}
+			}
+
+			if _, err := bs.Get(ctx, "labels/1/a.jpg"); err != (ErrBlobNotFound{Key: "labels/1/a.jpg"}) {
+				t.Errorf("getting missing blob: got %v", err)
+			}
+
+			if err := bs.Put(ctx, "labels/1/a.jpg", strings.NewReader("first")); err != nil {
+				t.Fatal(err)
+			}
+			if err := bs.Put(ctx, "labels/1/a.jpg", strings.NewReader("second")); err != nil {
+				t.Fatal(err)
+			}
+			rc, err := bs.Get(ctx, "labels/1/a.jpg")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			if string(data) != "second" {
+				t.Errorf("getting blob: got %q, want %q", data, "second")
+			}
+
+			if err := bs.Delete(ctx, "labels/1/a.jpg"); err != nil {
+				t.Errorf("deleting blob: %v", err)
+			}
+			if err := bs.Delete(ctx, "labels/1/a.jpg"); err != nil {
+				t.Errorf("deleting blob again: %v", err)
+			}
+			if _, err := bs.Get(ctx, "labels/1/a.jpg"); err == nil {
+				t.Errorf("getting deleted blob: expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package brewers
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"time"
+)
+
+// The operations the rest of the app needs on brewers, implemented by BrewerStore (backed by the
+// database) and MemoryBrewerStore (for tests)
+type Store interface {
+	AddBrewer(ctx context.Context, params db.AddBrewerParams) (db.Brewer, error)
+	GetBrewer(ctx context.Context, id int64) (db.Brewer, error)
+	GetBrewers(ctx context.Context) ([]db.Brewer, error)
+	DeleteBrewer(ctx context.Context, id int64) (db.Brewer, error)
+	RestoreBrewer(ctx context.Context, id int64) (db.Brewer, error)
+	GetDeletedBrewers(ctx context.Context) ([]db.Brewer, error)
+	PurgeBrewer(ctx context.Context, id int64) (db.Brewer, error)
+	PurgeDeletedBrewers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	CountBrewers(ctx context.Context) (int64, error)
+}
+
+var _ Store = (*BrewerStore)(nil)
+var _ Store = (*MemoryBrewerStore)(nil)
@@ -2,10 +2,15 @@ package brewers
 
 import "fmt"
 
+// Deleted is set when the name belongs to a brewer in the trash, which can be restored instead
 type ErrBrewerAlreadyExists struct {
-	Name string
+	Name    string
+	Deleted bool
 }
 
 func (e ErrBrewerAlreadyExists) Error() string {
+	if e.Deleted {
+		return fmt.Sprintf("brewer with name %s is in the trash", e.Name)
+	}
 	return fmt.Sprintf("brewer with name %s already exists", e.Name)
 }
@@ -0,0 +1,45 @@
+package brewers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/events"
+	"context"
+)
+
+// A Store which publishes an event each time a brewer is added, deleted or restored, so open
+// pages can show it. Wraps either of the other implementations.
+type PublishingBrewerStore struct {
+	Store
+	publisher events.Publisher
+}
+
+var _ Store = (*PublishingBrewerStore)(nil)
+
+func NewPublishingBrewerStore(store Store, publisher events.Publisher) *PublishingBrewerStore {
+	return &PublishingBrewerStore{Store: store, publisher: publisher}
+}
+
+// Publishes the action on the brewer if the change it's from went through, once any transaction
+// it's part of has been committed so the change can be seen
+func (ps *PublishingBrewerStore) publish(ctx context.Context, action string, brewer db.Brewer, err error) (db.Brewer, error) {
+	if err == nil {
+		event := events.Event{Topic: events.Brewers, Action: action, ID: brewer.ID}
+		db.AfterCommit(ctx, func() { ps.publisher.Publish(event) })
+	}
+	return brewer, err
+}
+
+func (ps *PublishingBrewerStore) AddBrewer(ctx context.Context, params db.AddBrewerParams) (db.Brewer, error) {
+	brewer, err := ps.Store.AddBrewer(ctx, params)
+	return ps.publish(ctx, events.Created, brewer, err)
+}
+
+func (ps *PublishingBrewerStore) DeleteBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	brewer, err := ps.Store.DeleteBrewer(ctx, id)
+	return ps.publish(ctx, events.Deleted, brewer, err)
+}
+
+func (ps *PublishingBrewerStore) RestoreBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	brewer, err := ps.Store.RestoreBrewer(ctx, id)
+	return ps.publish(ctx, events.Restored, brewer, err)
+}
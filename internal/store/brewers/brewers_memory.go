@@ -0,0 +1,147 @@
+package brewers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as BrewerStore
+type MemoryBrewerStore struct {
+	mu      sync.Mutex
+	lastId  int64
+	brewers []db.Brewer
+}
+
+func NewMemoryBrewerStore() *MemoryBrewerStore {
+	return &MemoryBrewerStore{}
+}
+
+// The index of the brewer with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (bs *MemoryBrewerStore) find(id int64, deleted bool) int {
+	return slices.IndexFunc(bs.brewers, func(b db.Brewer) bool {
+		return b.ID == id && b.DeletedAt.Valid == deleted
+	})
+}
+
+func (bs *MemoryBrewerStore) AddBrewer(ctx context.Context, params db.AddBrewerParams) (db.Brewer, error) {
+	if params.Name == "" {
+		return db.Brewer{}, store.ErrMissingField{Field: "name"}
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	// Names stay taken while the brewer is in the trash, like the unique constraint in the database
+	if i := slices.IndexFunc(bs.brewers, func(b db.Brewer) bool { return b.Name == params.Name }); i >= 0 {
+		return db.Brewer{}, ErrBrewerAlreadyExists{Name: params.Name, Deleted: bs.brewers[i].DeletedAt.Valid}
+	}
+
+	bs.lastId++
+	brewer := db.Brewer{
+		ID:       bs.lastId,
+		Name:     params.Name,
+		Location: params.Location,
+	}
+	bs.brewers = append(bs.brewers, brewer)
+	return brewer, nil
+}
+
+func (bs *MemoryBrewerStore) GetBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, false)
+	if i < 0 {
+		return db.Brewer{}, store.ErrBrewerNotFound{ID: id}
+	}
+	return bs.brewers[i], nil
+}
+
+func (bs *MemoryBrewerStore) GetBrewers(ctx context.Context) ([]db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	brewers := []db.Brewer{}
+	for _, b := range bs.brewers {
+		if !b.DeletedAt.Valid {
+			brewers = append(brewers, b)
+		}
+	}
+	return brewers, nil
+}
+
+func (bs *MemoryBrewerStore) DeleteBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, false)
+	if i < 0 {
+		return db.Brewer{}, store.ErrBrewerNotFound{ID: id}
+	}
+	bs.brewers[i].DeletedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	return bs.brewers[i], nil
+}
+
+func (bs *MemoryBrewerStore) RestoreBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, true)
+	if i < 0 {
+		return db.Brewer{}, store.ErrBrewerNotFound{ID: id}
+	}
+	bs.brewers[i].DeletedAt = sql.NullTime{}
+	return bs.brewers[i], nil
+}
+
+func (bs *MemoryBrewerStore) GetDeletedBrewers(ctx context.Context) ([]db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	brewers := []db.Brewer{}
+	for _, b := range bs.brewers {
+		if b.DeletedAt.Valid {
+			brewers = append(brewers, b)
+		}
+	}
+	slices.SortStableFunc(brewers, func(a, b db.Brewer) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	})
+	return brewers, nil
+}
+
+func (bs *MemoryBrewerStore) PurgeBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	i := bs.find(id, true)
+	if i < 0 {
+		return db.Brewer{}, store.ErrBrewerNotFound{ID: id}
+	}
+	brewer := bs.brewers[i]
+	bs.brewers = slices.Delete(bs.brewers, i, i+1)
+	return brewer, nil
+}
+
+func (bs *MemoryBrewerStore) PurgeDeletedBrewers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	before := len(bs.brewers)
+	bs.brewers = slices.DeleteFunc(bs.brewers, func(b db.Brewer) bool {
+		return b.DeletedAt.Valid && b.DeletedAt.Time.Before(deletedBefore)
+	})
+	return int64(before - len(bs.brewers)), nil
+}
+
+func (bs *MemoryBrewerStore) CountBrewers(ctx context.Context) (int64, error) {
+	brewers, _ := bs.GetBrewers(ctx)
+	return int64(len(brewers)), nil
+}
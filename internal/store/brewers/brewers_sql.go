@@ -0,0 +1,151 @@
+package brewers
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"log"
+	"slices"
+	"time"
+)
+
+type BrewerStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewBrewerStore(queries db.Querier, logger *log.Logger) *BrewerStore {
+	return &BrewerStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (bs *BrewerStore) AddBrewer(ctx context.Context, params db.AddBrewerParams) (db.Brewer, error) {
+	zero := db.Brewer{}
+
+	if params.Name == "" {
+		return zero, store.ErrMissingField{Field: "name"}
+	}
+
+	brewer, err := bs.queries.AddBrewer(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.UniqueConstraint {
+			return zero, ErrBrewerAlreadyExists{Name: params.Name, Deleted: bs.inTrash(ctx, params.Name)}
+		}
+		bs.logger.Printf("error adding brewer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("brewer added: %v", brewer)
+	return brewer, nil
+}
+
+func (bs *BrewerStore) GetBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	brewer, err := bs.queries.GetBrewerById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Brewer{}, store.ErrBrewerNotFound{ID: id}
+		}
+		bs.logger.Printf("error getting brewer: %v", err)
+		return db.Brewer{}, err
+	}
+	return brewer, nil
+}
+
+func (bs *BrewerStore) GetBrewers(ctx context.Context) ([]db.Brewer, error) {
+	brewers, err := bs.queries.GetBrewers(ctx)
+	if err != nil {
+		bs.logger.Printf("error getting brewers: %v", err)
+		return nil, err
+	}
+	return brewers, nil
+}
+
+func (bs *BrewerStore) DeleteBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	zero := db.Brewer{}
+
+	brewer, err := bs.queries.DeleteBrewer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, store.ErrBrewerNotFound{ID: id}
+		}
+		bs.logger.Printf("error deleting brewer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("brewer deleted: %v", brewer)
+	return brewer, nil
+}
+
+func (bs *BrewerStore) RestoreBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	zero := db.Brewer{}
+
+	brewer, err := bs.queries.RestoreBrewer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, store.ErrBrewerNotFound{ID: id}
+		}
+		bs.logger.Printf("error restoring brewer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("brewer restored: %v", brewer)
+	return brewer, nil
+}
+
+func (bs *BrewerStore) GetDeletedBrewers(ctx context.Context) ([]db.Brewer, error) {
+	brewers, err := bs.queries.GetDeletedBrewers(ctx)
+	if err != nil {
+		bs.logger.Printf("error getting deleted brewers: %v", err)
+		return nil, err
+	}
+	return brewers, nil
+}
+
+// Reports whether the name belongs to a brewer in the trash
+func (bs *BrewerStore) inTrash(ctx context.Context, name string) bool {
+	brewers, err := bs.GetDeletedBrewers(ctx)
+	return err == nil && slices.ContainsFunc(brewers, func(b db.Brewer) bool { return b.Name == name })
+}
+
+// Permanently removes a brewer which has already been moved to the trash
+func (bs *BrewerStore) PurgeBrewer(ctx context.Context, id int64) (db.Brewer, error) {
+	zero := db.Brewer{}
+
+	brewer, err := bs.queries.PurgeBrewer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, store.ErrBrewerNotFound{ID: id}
+		}
+		bs.logger.Printf("error purging brewer: %v", err)
+		return zero, err
+	}
+
+	bs.logger.Printf("brewer purged: %v", brewer)
+	return brewer, nil
+}
+
+// Permanently removes every brewer that was moved to the trash before the given time, returning
+// how many were removed
+func (bs *BrewerStore) PurgeDeletedBrewers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	count, err := bs.queries.PurgeDeletedBrewers(ctx, sql.NullTime{Valid: true, Time: deletedBefore.UTC()})
+	if err != nil {
+		bs.logger.Printf("error purging deleted brewers: %v", err)
+		return 0, err
+	}
+	if count > 0 {
+		bs.logger.Printf("purged %d deleted brewers", count)
+	}
+	return count, nil
+}
+
+func (bs *BrewerStore) CountBrewers(ctx context.Context) (int64, error) {
+	count, err := bs.queries.CountBrewers(ctx)
+	if err != nil {
+		bs.logger.Printf("error counting brewers: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
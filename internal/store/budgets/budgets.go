@@ -0,0 +1,36 @@
+package budgets
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/stock"
+	"context"
+	"strings"
+)
+
+// The operations the rest of the app needs on users' monthly budgets, implemented by BudgetStore
+// (backed by the database) and MemoryBudgetStore (for tests). Each user has at most one budget,
+// in a single currency.
+type Store interface {
+	SetBudget(ctx context.Context, params db.SetBudgetParams) (db.Budget, error)
+	GetBudget(ctx context.Context, userId int64) (db.Budget, error)
+	DeleteBudget(ctx context.Context, userId int64) (db.Budget, error)
+}
+
+var _ Store = (*BudgetStore)(nil)
+var _ Store = (*MemoryBudgetStore)(nil)
+
+func validateBudget(params db.SetBudgetParams) error {
+	if params.Amount <= 0 {
+		return store.ErrInvalidField{Field: "amount", Reason: "must be more than 0"}
+	}
+	if !stock.ValidCurrency(params.Currency) {
+		return store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}
+	}
+	return nil
+}
+
+func normalizeBudget(params db.SetBudgetParams) db.SetBudgetParams {
+	params.Currency = strings.ToUpper(strings.TrimSpace(params.Currency))
+	return params
+}
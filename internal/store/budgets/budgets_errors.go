@@ -0,0 +1,11 @@
+package budgets
+
+import "fmt"
+
+type ErrBudgetNotFound struct {
+	UserID int64
+}
+
+func (e ErrBudgetNotFound) Error() string {
+	return fmt.Sprintf("no budget for user with id %d", e.UserID)
+}
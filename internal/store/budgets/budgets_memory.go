@@ -0,0 +1,69 @@
+package budgets
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as BudgetStore. The user store stands in for the foreign key.
+type MemoryBudgetStore struct {
+	mu        sync.Mutex
+	userStore users.Store
+	budgets   map[int64]db.Budget
+}
+
+func NewMemoryBudgetStore(userStore users.Store) *MemoryBudgetStore {
+	return &MemoryBudgetStore{
+		userStore: userStore,
+		budgets:   make(map[int64]db.Budget),
+	}
+}
+
+func (bs *MemoryBudgetStore) SetBudget(ctx context.Context, params db.SetBudgetParams) (db.Budget, error) {
+	if err := validateBudget(params); err != nil {
+		return db.Budget{}, err
+	}
+	if _, err := bs.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.Budget{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+	params = normalizeBudget(params)
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	budget := db.Budget{
+		UserID:    params.UserID,
+		Amount:    params.Amount,
+		Currency:  params.Currency,
+		UpdatedAt: store.Now(),
+	}
+	bs.budgets[params.UserID] = budget
+	return budget, nil
+}
+
+func (bs *MemoryBudgetStore) GetBudget(ctx context.Context, userId int64) (db.Budget, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	budget, ok := bs.budgets[userId]
+	if !ok {
+		return db.Budget{}, ErrBudgetNotFound{UserID: userId}
+	}
+	return budget, nil
+}
+
+func (bs *MemoryBudgetStore) DeleteBudget(ctx context.Context, userId int64) (db.Budget, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	budget, ok := bs.budgets[userId]
+	if !ok {
+		return db.Budget{}, ErrBudgetNotFound{UserID: userId}
+	}
+	delete(bs.budgets, userId)
+	return budget, nil
+}
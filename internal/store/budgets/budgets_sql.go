@@ -0,0 +1,67 @@
+package budgets
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type BudgetStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewBudgetStore(queries db.Querier, logger *log.Logger) *BudgetStore {
+	return &BudgetStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Sets the user's budget, replacing the one they had
+func (bs *BudgetStore) SetBudget(ctx context.Context, params db.SetBudgetParams) (db.Budget, error) {
+	if err := validateBudget(params); err != nil {
+		return db.Budget{}, err
+	}
+
+	budget, err := bs.queries.SetBudget(ctx, normalizeBudget(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Budget{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		bs.logger.Printf("error setting budget: %v", err)
+		return db.Budget{}, err
+	}
+
+	bs.logger.Printf("budget set: %v", budget)
+	return budget, nil
+}
+
+func (bs *BudgetStore) GetBudget(ctx context.Context, userId int64) (db.Budget, error) {
+	budget, err := bs.queries.GetBudget(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Budget{}, ErrBudgetNotFound{UserID: userId}
+		}
+		bs.logger.Printf("error getting budget: %v", err)
+		return db.Budget{}, err
+	}
+	return budget, nil
+}
+
+func (bs *BudgetStore) DeleteBudget(ctx context.Context, userId int64) (db.Budget, error) {
+	budget, err := bs.queries.DeleteBudget(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Budget{}, ErrBudgetNotFound{UserID: userId}
+		}
+		bs.logger.Printf("error deleting budget: %v", err)
+		return db.Budget{}, err
+	}
+
+	bs.logger.Printf("budget deleted: %v", budget)
+	return budget, nil
+}
@@ -0,0 +1,112 @@
+package comments
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// The longest a comment can be, in characters
+const MaxBodyLength = 5000
+
+// What comments can be reacted with, in the order they're shown
+var Emojis = []string{"👍", "❤️", "😂", "🍺", "🤔", "👎"}
+
+// What's being commented on: either a beer or a brewer
+type Target struct {
+	BeerID   sql.NullInt64
+	BrewerID sql.NullInt64
+}
+
+func BeerTarget(beerId int64) Target {
+	return Target{BeerID: sql.NullInt64{Valid: true, Int64: beerId}}
+}
+
+func BrewerTarget(brewerId int64) Target {
+	return Target{BrewerID: sql.NullInt64{Valid: true, Int64: brewerId}}
+}
+
+// What the comment is on
+func TargetOf(comment db.Comment) Target {
+	return Target{BeerID: comment.BeerID, BrewerID: comment.BrewerID}
+}
+
+// Where the page of what's being commented on is, e.g. /beer/1
+func (t Target) Path() string {
+	if t.BeerID.Valid {
+		return fmt.Sprintf("/beer/%d", t.BeerID.Int64)
+	}
+	return fmt.Sprintf("/brewer/%d", t.BrewerID.Int64)
+}
+
+// The operations the rest of the app needs on comments on beers and brewers and the reactions to
+// them, implemented by CommentStore (backed by the database) and MemoryCommentStore (for tests)
+type Store interface {
+	AddComment(ctx context.Context, params db.AddCommentParams) (db.Comment, error)
+	GetComment(ctx context.Context, id int64) (db.Comment, error)
+	// The comments on the beer or brewer, oldest first, including deleted ones
+	GetComments(ctx context.Context, target Target) ([]db.GetCommentsRow, error)
+	UpdateComment(ctx context.Context, id int64, body string) (db.Comment, error)
+	// Blanks the comment. It's moderated if an admin deleted it rather than its author.
+	DeleteComment(ctx context.Context, id int64, moderated bool) (db.Comment, error)
+	// Adds the user's reaction to the comment, or takes it away if they'd already reacted with
+	// that emoji, returning whether it was added
+	ToggleReaction(ctx context.Context, commentId int64, userId int64, emoji string) (bool, error)
+	GetReactions(ctx context.Context, target Target) ([]db.GetReactionsRow, error)
+}
+
+var _ Store = (*CommentStore)(nil)
+var _ Store = (*MemoryCommentStore)(nil)
+
+func validateBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return store.ErrMissingField{Field: "body"}
+	}
+	if utf8.RuneCountInString(body) > MaxBodyLength {
+		return store.ErrInvalidField{Field: "body", Reason: fmt.Sprintf("must be at most %d characters", MaxBodyLength)}
+	}
+	return nil
+}
+
+func validateComment(params db.AddCommentParams) error {
+	if !params.BeerID.Valid && !params.BrewerID.Valid {
+		return store.ErrMissingField{Field: "beer-id"}
+	}
+	if params.BeerID.Valid && params.BrewerID.Valid {
+		return store.ErrInvalidField{Field: "brewer-id", Reason: "must not be given along with a beer"}
+	}
+	return validateBody(params.Body)
+}
+
+// Checks a reply is to a comment on the same beer or brewer
+func validateParent(params db.AddCommentParams, parent db.Comment, err error) error {
+	if err != nil {
+		if _, ok := err.(ErrCommentNotFound); ok {
+			return store.ErrInvalidField{Field: "parent-id", Reason: "must be a comment on the same page"}
+		}
+		return err
+	}
+	if TargetOf(parent) != (Target{BeerID: params.BeerID, BrewerID: params.BrewerID}) {
+		return store.ErrInvalidField{Field: "parent-id", Reason: "must be a comment on the same page"}
+	}
+	return nil
+}
+
+func validateEmoji(emoji string) error {
+	if emoji == "" {
+		return store.ErrMissingField{Field: "emoji"}
+	}
+	if !slices.Contains(Emojis, emoji) {
+		return store.ErrInvalidField{Field: "emoji", Reason: "must be one of " + strings.Join(Emojis, " ")}
+	}
+	return nil
+}
+
+func normalizeBody(body string) string {
+	return strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+}
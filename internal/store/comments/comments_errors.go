@@ -0,0 +1,11 @@
+package comments
+
+import "fmt"
+
+type ErrCommentNotFound struct {
+	ID int64
+}
+
+func (e ErrCommentNotFound) Error() string {
+	return fmt.Sprintf("comment with id %d not found", e.ID)
+}
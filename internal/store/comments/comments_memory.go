@@ -0,0 +1,203 @@
+package comments
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as CommentStore. The beer and brewer stores stand in for the foreign keys, and
+// the user store for them and the joins.
+type MemoryCommentStore struct {
+	mu          sync.Mutex
+	userStore   users.Store
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	lastId      int64
+	comments    []db.Comment
+	reactions   []db.CommentReaction
+}
+
+func NewMemoryCommentStore(userStore users.Store, beerStore beers.Store, brewerStore brewers.Store) *MemoryCommentStore {
+	return &MemoryCommentStore{
+		userStore:   userStore,
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+	}
+}
+
+// The index of the comment with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (cs *MemoryCommentStore) find(id int64) int {
+	return slices.IndexFunc(cs.comments, func(c db.Comment) bool { return c.ID == id })
+}
+
+func (cs *MemoryCommentStore) AddComment(ctx context.Context, params db.AddCommentParams) (db.Comment, error) {
+	if err := validateComment(params); err != nil {
+		return db.Comment{}, err
+	}
+	params.Body = normalizeBody(params.Body)
+	if params.ParentID.Valid {
+		parent, err := cs.GetComment(ctx, params.ParentID.Int64)
+		if err := validateParent(params, parent, err); err != nil {
+			return db.Comment{}, err
+		}
+	}
+	if params.BeerID.Valid {
+		if _, err := cs.beerStore.GetBeer(ctx, params.BeerID.Int64); err != nil {
+			return db.Comment{}, beers.ErrBeerNotFound{ID: params.BeerID.Int64}
+		}
+	}
+	if params.BrewerID.Valid {
+		if _, err := cs.brewerStore.GetBrewer(ctx, params.BrewerID.Int64); err != nil {
+			return db.Comment{}, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+		}
+	}
+	if _, err := cs.userStore.GetUserById(ctx, params.AuthorID); err != nil {
+		return db.Comment{}, users.ErrUserNotFound{ID: params.AuthorID}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.lastId++
+	comment := db.Comment{
+		ID:        cs.lastId,
+		AuthorID:  params.AuthorID,
+		BeerID:    params.BeerID,
+		BrewerID:  params.BrewerID,
+		ParentID:  params.ParentID,
+		Body:      params.Body,
+		CreatedAt: store.Now(),
+	}
+	cs.comments = append(cs.comments, comment)
+	return comment, nil
+}
+
+func (cs *MemoryCommentStore) GetComment(ctx context.Context, id int64) (db.Comment, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	i := cs.find(id)
+	if i < 0 {
+		return db.Comment{}, ErrCommentNotFound{ID: id}
+	}
+	return cs.comments[i], nil
+}
+
+func (cs *MemoryCommentStore) GetComments(ctx context.Context, target Target) ([]db.GetCommentsRow, error) {
+	cs.mu.Lock()
+	matching := []db.Comment{}
+	for _, c := range cs.comments {
+		if TargetOf(c) == target {
+			matching = append(matching, c)
+		}
+	}
+	cs.mu.Unlock()
+
+	rows := []db.GetCommentsRow{}
+	for _, c := range matching {
+		author, err := cs.userStore.GetUserById(ctx, c.AuthorID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetCommentsRow{Comment: c, Username: author.Username})
+	}
+	return rows, nil
+}
+
+func (cs *MemoryCommentStore) UpdateComment(ctx context.Context, id int64, body string) (db.Comment, error) {
+	if err := validateBody(body); err != nil {
+		return db.Comment{}, err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	i := cs.find(id)
+	if i < 0 || cs.comments[i].DeletedAt.Valid {
+		return db.Comment{}, ErrCommentNotFound{ID: id}
+	}
+	cs.comments[i].Body = normalizeBody(body)
+	cs.comments[i].EditedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	return cs.comments[i], nil
+}
+
+func (cs *MemoryCommentStore) DeleteComment(ctx context.Context, id int64, moderated bool) (db.Comment, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	i := cs.find(id)
+	if i < 0 || cs.comments[i].DeletedAt.Valid {
+		return db.Comment{}, ErrCommentNotFound{ID: id}
+	}
+	cs.comments[i].Body = ""
+	cs.comments[i].DeletedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	cs.comments[i].Moderated = moderated
+	return cs.comments[i], nil
+}
+
+func (cs *MemoryCommentStore) ToggleReaction(ctx context.Context, commentId int64, userId int64, emoji string) (bool, error) {
+	if err := validateEmoji(emoji); err != nil {
+		return false, err
+	}
+	comment, err := cs.GetComment(ctx, commentId)
+	if err != nil {
+		return false, err
+	}
+	if comment.DeletedAt.Valid {
+		return false, ErrCommentNotFound{ID: commentId}
+	}
+	if _, err := cs.userStore.GetUserById(ctx, userId); err != nil {
+		return false, users.ErrUserNotFound{ID: userId}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	mine := func(r db.CommentReaction) bool {
+		return r.CommentID == commentId && r.UserID == userId && r.Emoji == emoji
+	}
+	if slices.ContainsFunc(cs.reactions, mine) {
+		cs.reactions = slices.DeleteFunc(cs.reactions, mine)
+		return false, nil
+	}
+	cs.reactions = append(cs.reactions, db.CommentReaction{CommentID: commentId, UserID: userId, Emoji: emoji, CreatedAt: store.Now()})
+	return true, nil
+}
+
+func (cs *MemoryCommentStore) GetReactions(ctx context.Context, target Target) ([]db.GetReactionsRow, error) {
+	cs.mu.Lock()
+	onTarget := map[int64]bool{}
+	for _, c := range cs.comments {
+		if TargetOf(c) == target {
+			onTarget[c.ID] = true
+		}
+	}
+	matching := []db.CommentReaction{}
+	for _, r := range cs.reactions {
+		if onTarget[r.CommentID] {
+			matching = append(matching, r)
+		}
+	}
+	cs.mu.Unlock()
+
+	// In the order they were added, which is already by time, so only the comments need sorting
+	slices.SortStableFunc(matching, func(a, b db.CommentReaction) int { return cmp.Compare(a.CommentID, b.CommentID) })
+	rows := []db.GetReactionsRow{}
+	for _, r := range matching {
+		if _, err := cs.userStore.GetUserById(ctx, r.UserID); err != nil {
+			continue
+		}
+		rows = append(rows, db.GetReactionsRow{CommentID: r.CommentID, UserID: r.UserID, Emoji: r.Emoji})
+	}
+	return rows, nil
+}
@@ -0,0 +1,157 @@
+package comments
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type CommentStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewCommentStore(queries db.Querier, logger *log.Logger) *CommentStore {
+	return &CommentStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (cs *CommentStore) AddComment(ctx context.Context, params db.AddCommentParams) (db.Comment, error) {
+	if err := validateComment(params); err != nil {
+		return db.Comment{}, err
+	}
+	params.Body = normalizeBody(params.Body)
+	if params.ParentID.Valid {
+		parent, err := cs.GetComment(ctx, params.ParentID.Int64)
+		if err := validateParent(params, parent, err); err != nil {
+			return db.Comment{}, err
+		}
+	}
+
+	comment, err := cs.queries.AddComment(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if params.BeerID.Valid {
+				if _, err := cs.queries.GetBeerById(ctx, params.BeerID.Int64); err == sql.ErrNoRows {
+					return db.Comment{}, beers.ErrBeerNotFound{ID: params.BeerID.Int64}
+				}
+			}
+			if params.BrewerID.Valid {
+				if _, err := cs.queries.GetBrewerById(ctx, params.BrewerID.Int64); err == sql.ErrNoRows {
+					return db.Comment{}, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+				}
+			}
+			return db.Comment{}, users.ErrUserNotFound{ID: params.AuthorID}
+		}
+		cs.logger.Printf("error adding comment: %v", err)
+		return db.Comment{}, err
+	}
+
+	cs.logger.Printf("comment added: %d by user %d", comment.ID, comment.AuthorID)
+	return comment, nil
+}
+
+func (cs *CommentStore) GetComment(ctx context.Context, id int64) (db.Comment, error) {
+	comment, err := cs.queries.GetComment(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Comment{}, ErrCommentNotFound{ID: id}
+		}
+		cs.logger.Printf("error getting comment: %v", err)
+		return db.Comment{}, err
+	}
+	return comment, nil
+}
+
+func (cs *CommentStore) GetComments(ctx context.Context, target Target) ([]db.GetCommentsRow, error) {
+	comments, err := cs.queries.GetComments(ctx, db.GetCommentsParams(target))
+	if err != nil {
+		cs.logger.Printf("error getting comments: %v", err)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Deleted comments can't be edited
+func (cs *CommentStore) UpdateComment(ctx context.Context, id int64, body string) (db.Comment, error) {
+	if err := validateBody(body); err != nil {
+		return db.Comment{}, err
+	}
+
+	comment, err := cs.queries.UpdateComment(ctx, db.UpdateCommentParams{ID: id, Body: normalizeBody(body)})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Comment{}, ErrCommentNotFound{ID: id}
+		}
+		cs.logger.Printf("error updating comment: %v", err)
+		return db.Comment{}, err
+	}
+
+	cs.logger.Printf("comment updated: %d", comment.ID)
+	return comment, nil
+}
+
+func (cs *CommentStore) DeleteComment(ctx context.Context, id int64, moderated bool) (db.Comment, error) {
+	comment, err := cs.queries.DeleteComment(ctx, db.DeleteCommentParams{ID: id, Moderated: moderated})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Comment{}, ErrCommentNotFound{ID: id}
+		}
+		cs.logger.Printf("error deleting comment: %v", err)
+		return db.Comment{}, err
+	}
+
+	cs.logger.Printf("comment deleted: %d, moderated: %t", comment.ID, comment.Moderated)
+	return comment, nil
+}
+
+// Deleted comments can't be reacted to
+func (cs *CommentStore) ToggleReaction(ctx context.Context, commentId int64, userId int64, emoji string) (bool, error) {
+	if err := validateEmoji(emoji); err != nil {
+		return false, err
+	}
+	comment, err := cs.GetComment(ctx, commentId)
+	if err != nil {
+		return false, err
+	}
+	if comment.DeletedAt.Valid {
+		return false, ErrCommentNotFound{ID: commentId}
+	}
+
+	params := db.DeleteReactionParams{CommentID: commentId, UserID: userId, Emoji: emoji}
+	removed, err := cs.queries.DeleteReaction(ctx, params)
+	if err != nil {
+		cs.logger.Printf("error deleting reaction: %v", err)
+		return false, err
+	}
+	if removed > 0 {
+		cs.logger.Printf("reaction removed: %s from comment %d by user %d", emoji, commentId, userId)
+		return false, nil
+	}
+
+	if err := cs.queries.AddReaction(ctx, db.AddReactionParams(params)); err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return false, users.ErrUserNotFound{ID: userId}
+		}
+		cs.logger.Printf("error adding reaction: %v", err)
+		return false, err
+	}
+
+	cs.logger.Printf("reaction added: %s to comment %d by user %d", emoji, commentId, userId)
+	return true, nil
+}
+
+func (cs *CommentStore) GetReactions(ctx context.Context, target Target) ([]db.GetReactionsRow, error) {
+	reactions, err := cs.queries.GetReactions(ctx, db.GetReactionsParams(target))
+	if err != nil {
+		cs.logger.Printf("error getting reactions: %v", err)
+		return nil, err
+	}
+	return reactions, nil
+}
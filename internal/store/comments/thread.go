@@ -0,0 +1,103 @@
+package comments
+
+import (
+	"beer_oclock/internal/db"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A comment and the replies to it, and to them
+type Node struct {
+	Row     db.GetCommentsRow
+	Replies []Node
+}
+
+// Arranges the comments, oldest first, into threads. Deleted comments are only kept to hold
+// replies, and replies to comments that aren't there, such as those by deleted users, start
+// threads of their own.
+func Thread(rows []db.GetCommentsRow) []Node {
+	ids := map[int64]bool{}
+	for _, row := range rows {
+		ids[row.Comment.ID] = true
+	}
+	children := map[int64][]db.GetCommentsRow{}
+	roots := []db.GetCommentsRow{}
+	for _, row := range rows {
+		if row.Comment.ParentID.Valid && ids[row.Comment.ParentID.Int64] {
+			children[row.Comment.ParentID.Int64] = append(children[row.Comment.ParentID.Int64], row)
+		} else {
+			roots = append(roots, row)
+		}
+	}
+
+	var build func(rows []db.GetCommentsRow) []Node
+	build = func(rows []db.GetCommentsRow) []Node {
+		nodes := []Node{}
+		for _, row := range rows {
+			node := Node{Row: row, Replies: build(children[row.Comment.ID])}
+			if row.Comment.DeletedAt.Valid && len(node.Replies) == 0 {
+				continue
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// How many reacted to a comment with an emoji, and whether the user looking was one of them
+type Reaction struct {
+	Emoji string
+	Count int
+	Mine  bool
+}
+
+// The reactions to each comment, in the order of Emojis, leaving out those no one's used
+func Tally(rows []db.GetReactionsRow, viewerId int64) map[int64][]Reaction {
+	counts := map[int64]map[string]*Reaction{}
+	for _, row := range rows {
+		if counts[row.CommentID] == nil {
+			counts[row.CommentID] = map[string]*Reaction{}
+		}
+		reaction := counts[row.CommentID][row.Emoji]
+		if reaction == nil {
+			reaction = &Reaction{Emoji: row.Emoji}
+			counts[row.CommentID][row.Emoji] = reaction
+		}
+		reaction.Count++
+		reaction.Mine = reaction.Mine || row.UserID == viewerId
+	}
+
+	tallies := map[int64][]Reaction{}
+	for commentId, byEmoji := range counts {
+		for _, emoji := range Emojis {
+			if reaction, ok := byEmoji[emoji]; ok {
+				tallies[commentId] = append(tallies[commentId], *reaction)
+			}
+		}
+	}
+	return tallies
+}
+
+var mention = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]*\w)`)
+
+// The usernames @mentioned in the body, each once in the order they're first mentioned
+func Mentions(body string) []string {
+	usernames := []string{}
+	for _, m := range mention.FindAllStringSubmatch(body, -1) {
+		if !slices.Contains(usernames, m[1]) {
+			usernames = append(usernames, m[1])
+		}
+	}
+	return usernames
+}
+
+// The usernames mentioned in the body which weren't in what it was before, for telling only
+// those newly mentioned when a comment is edited
+func NewMentions(before string, after string) []string {
+	old := Mentions(before)
+	return slices.DeleteFunc(Mentions(after), func(username string) bool {
+		return slices.ContainsFunc(old, func(o string) bool { return strings.EqualFold(o, username) })
+	})
+}
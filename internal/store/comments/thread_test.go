@@ -0,0 +1,94 @@
+package comments
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func comment(id int64, parentId int64, deleted bool) db.GetCommentsRow {
+	c := db.Comment{ID: id, Body: "body"}
+	if parentId != 0 {
+		c.ParentID = sql.NullInt64{Valid: true, Int64: parentId}
+	}
+	if deleted {
+		c.DeletedAt = sql.NullTime{Valid: true, Time: time.Now()}
+		c.Body = ""
+	}
+	return db.GetCommentsRow{Comment: c}
+}
+
+// The ids in the threads, with the replies to each in brackets after it
+func shape(nodes []Node) []any {
+	out := []any{}
+	for _, node := range nodes {
+		out = append(out, node.Row.Comment.ID)
+		if len(node.Replies) > 0 {
+			out = append(out, shape(node.Replies))
+		}
+	}
+	return out
+}
+
+func TestThread(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rows []db.GetCommentsRow
+		want []any
+	}{
+		{"none", nil, []any{}},
+		{"flat", []db.GetCommentsRow{comment(1, 0, false), comment(2, 0, false)}, []any{int64(1), int64(2)}},
+		{
+			"nested",
+			[]db.GetCommentsRow{comment(1, 0, false), comment(2, 1, false), comment(3, 0, false), comment(4, 2, false), comment(5, 1, false)},
+			[]any{int64(1), []any{int64(2), []any{int64(4)}, int64(5)}, int64(3)},
+		},
+		{
+			"deleted comments only hold replies",
+			[]db.GetCommentsRow{comment(1, 0, true), comment(2, 1, false), comment(3, 0, true), comment(4, 3, true)},
+			[]any{int64(1), []any{int64(2)}},
+		},
+		{"replies to missing comments start threads", []db.GetCommentsRow{comment(2, 1, false), comment(3, 2, false)}, []any{int64(2), []any{int64(3)}}},
+	} {
+		if got := shape(Thread(tc.rows)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTally(t *testing.T) {
+	got := Tally([]db.GetReactionsRow{
+		{CommentID: 1, UserID: 1, Emoji: "🍺"},
+		{CommentID: 1, UserID: 2, Emoji: "👍"},
+		{CommentID: 1, UserID: 2, Emoji: "🍺"},
+		{CommentID: 2, UserID: 2, Emoji: "😂"},
+	}, 1)
+	want := map[int64][]Reaction{
+		1: {{Emoji: "👍", Count: 1}, {Emoji: "🍺", Count: 2, Mine: true}},
+		2: {{Emoji: "😂", Count: 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	for body, want := range map[string][]string{
+		"":                                       {},
+		"@saltytaro try this":                    {"saltytaro"},
+		"Thanks @guest, @bob_2 and @guest.":      {"guest", "bob_2"},
+		"Ask @first.last-name!":                  {"first.last-name"},
+		"email me at me@example.com or @@double": {},
+		"(@paren)":                               {"paren"},
+	} {
+		if got := Mentions(body); !slices.Equal(got, want) {
+			t.Errorf("Mentions(%q): got %v, want %v", body, got, want)
+		}
+	}
+	if got := NewMentions("hi @guest", "hi @Guest and @bob"); !slices.Equal(got, []string{"bob"}) {
+		t.Errorf("NewMentions: got %v, want [bob]", got)
+	}
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// The kind of constraint a database error says was violated, whichever driver returned it
+type Constraint int
+
+const (
+	NoConstraint Constraint = iota
+	UniqueConstraint
+	ForeignKeyConstraint
+)
+
+// The PostgreSQL error codes for the same violations, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+func ViolatedConstraint(err error) Constraint {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		// PostgreSQL reports a duplicate primary key as a unique violation too
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return UniqueConstraint
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return ForeignKeyConstraint
+		}
+		return NoConstraint
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return UniqueConstraint
+		case pgForeignKeyViolation:
+			return ForeignKeyConstraint
+		}
+	}
+	return NoConstraint
+}
@@ -0,0 +1,47 @@
+package drinklog
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"time"
+)
+
+// The operations the rest of the app needs on the drinks users have had, implemented by
+// DrinkStore (backed by the database) and MemoryDrinkStore (for tests). Ranges of days run from
+// since up to but not including until, both of which are days as store.Day gives them.
+type Store interface {
+	AddDrink(ctx context.Context, params db.AddDrinkParams) (db.Drink, error)
+	GetDrink(ctx context.Context, id int64) (db.Drink, error)
+	GetUserDrinks(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetUserDrinksRow, error)
+	DeleteDrink(ctx context.Context, id int64) (db.Drink, error)
+	GetDrinksPerDay(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetDrinksPerDayRow, error)
+	GetAbvDistribution(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetAbvDistributionRow, error)
+	GetTopStyles(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopStylesRow, error)
+	GetTopBrewers(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopBrewersRow, error)
+	GetRatingHistogram(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetRatingHistogramRow, error)
+}
+
+var _ Store = (*DrinkStore)(nil)
+var _ Store = (*MemoryDrinkStore)(nil)
+
+// The size of a drink unless another's given, a standard can
+const DefaultServingMl = 375
+
+func validateDrink(params db.AddDrinkParams) error {
+	if params.ServingMl < 1 {
+		return store.ErrInvalidField{Field: "serving-ml", Reason: "must be at least 1"}
+	}
+	if params.DrunkAt.IsZero() {
+		return store.ErrMissingField{Field: "drunk-at"}
+	}
+	return nil
+}
+
+// The day a drink is on is the day it was where it was had, so drunk_at should be in the
+// drinker's time zone. It's then stored in UTC like the other timestamps.
+func normalizeDrink(params db.AddDrinkParams) db.AddDrinkParams {
+	params.DrunkOn = store.Day(params.DrunkAt)
+	params.DrunkAt = params.DrunkAt.UTC().Truncate(time.Second)
+	return params
+}
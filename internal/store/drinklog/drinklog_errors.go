@@ -0,0 +1,11 @@
+package drinklog
+
+import "fmt"
+
+type ErrDrinkNotFound struct {
+	ID int64
+}
+
+func (e ErrDrinkNotFound) Error() string {
+	return fmt.Sprintf("drink with id %d not found", e.ID)
+}
@@ -0,0 +1,263 @@
+package drinklog
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"cmp"
+	"context"
+	"database/sql"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as DrinkStore. The beer store stands in for the foreign key, and it and the
+// brewer store for the joins in the stats.
+type MemoryDrinkStore struct {
+	mu          sync.Mutex
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	lastId      int64
+	drinks      []db.Drink
+}
+
+func NewMemoryDrinkStore(beerStore beers.Store, brewerStore brewers.Store) *MemoryDrinkStore {
+	return &MemoryDrinkStore{
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+	}
+}
+
+// A drink with the beer it was of, and the name of the beer's brewer if it has one
+type joinedDrink struct {
+	drink  db.Drink
+	beer   db.Beer
+	brewer string
+}
+
+// The user's drinks in the range, joined to their beers and brewers like the queries do, in the
+// order they were added
+func (ds *MemoryDrinkStore) joined(ctx context.Context, userId int64, since time.Time, until time.Time) ([]joinedDrink, error) {
+	beersById := map[int64]db.Beer{}
+	for _, get := range []func(context.Context) ([]db.Beer, error){ds.beerStore.GetBeers, ds.beerStore.GetDeletedBeers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, beer := range all {
+			beersById[beer.ID] = beer
+		}
+	}
+	brewerNames := map[int64]string{}
+	for _, get := range []func(context.Context) ([]db.Brewer, error){ds.brewerStore.GetBrewers, ds.brewerStore.GetDeletedBrewers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, brewer := range all {
+			brewerNames[brewer.ID] = brewer.Name
+		}
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	joined := []joinedDrink{}
+	for _, drink := range ds.drinks {
+		beer, ok := beersById[drink.BeerID]
+		if !ok || drink.UserID != userId || drink.DrunkOn.Before(since) || !drink.DrunkOn.Before(until) {
+			continue
+		}
+		var brewer string
+		if beer.BrewerID.Valid {
+			brewer = brewerNames[beer.BrewerID.Int64]
+		}
+		joined = append(joined, joinedDrink{drink: drink, beer: beer, brewer: brewer})
+	}
+	return joined, nil
+}
+
+// Counts the drinks by the key, leaving out those with an empty key, most first and then by key
+func countBy(drinks []joinedDrink, key func(joinedDrink) string, maxResults int64) []db.GetTopBrewersRow {
+	counts := map[string]int64{}
+	for _, d := range drinks {
+		if k := key(d); k != "" {
+			counts[k]++
+		}
+	}
+
+	rows := []db.GetTopBrewersRow{}
+	for name, drinks := range counts {
+		rows = append(rows, db.GetTopBrewersRow{Name: name, Drinks: drinks})
+	}
+	slices.SortFunc(rows, func(a, b db.GetTopBrewersRow) int {
+		if c := cmp.Compare(b.Drinks, a.Drinks); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return rows[:min(int64(len(rows)), max(maxResults, 0))]
+}
+
+func (ds *MemoryDrinkStore) AddDrink(ctx context.Context, params db.AddDrinkParams) (db.Drink, error) {
+	if err := validateDrink(params); err != nil {
+		return db.Drink{}, err
+	}
+	if _, err := ds.beerStore.GetBeer(ctx, params.BeerID); err != nil {
+		return db.Drink{}, beers.ErrBeerNotFound{ID: params.BeerID}
+	}
+	params = normalizeDrink(params)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.lastId++
+	drink := db.Drink{
+		ID:        ds.lastId,
+		UserID:    params.UserID,
+		BeerID:    params.BeerID,
+		ServingMl: params.ServingMl,
+		DrunkAt:   params.DrunkAt,
+		DrunkOn:   params.DrunkOn,
+		CreatedAt: store.Now(),
+	}
+	ds.drinks = append(ds.drinks, drink)
+	return drink, nil
+}
+
+func (ds *MemoryDrinkStore) GetDrink(ctx context.Context, id int64) (db.Drink, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	i := slices.IndexFunc(ds.drinks, func(d db.Drink) bool { return d.ID == id })
+	if i < 0 {
+		return db.Drink{}, ErrDrinkNotFound{ID: id}
+	}
+	return ds.drinks[i], nil
+}
+
+func (ds *MemoryDrinkStore) GetUserDrinks(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetUserDrinksRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	drinks := []db.GetUserDrinksRow{}
+	for _, d := range joined {
+		drinks = append(drinks, db.GetUserDrinksRow{Drink: d.drink, BeerName: d.beer.Name, Abv: d.beer.Abv})
+	}
+	slices.SortFunc(drinks, func(a, b db.GetUserDrinksRow) int {
+		if c := b.Drink.DrunkAt.Compare(a.Drink.DrunkAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Drink.ID, a.Drink.ID)
+	})
+	return drinks, nil
+}
+
+func (ds *MemoryDrinkStore) DeleteDrink(ctx context.Context, id int64) (db.Drink, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	i := slices.IndexFunc(ds.drinks, func(d db.Drink) bool { return d.ID == id })
+	if i < 0 {
+		return db.Drink{}, ErrDrinkNotFound{ID: id}
+	}
+	drink := ds.drinks[i]
+	ds.drinks = slices.Delete(ds.drinks, i, i+1)
+	return drink, nil
+}
+
+func (ds *MemoryDrinkStore) GetDrinksPerDay(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetDrinksPerDayRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := map[time.Time]*db.GetDrinksPerDayRow{}
+	for _, d := range joined {
+		day, ok := byDay[d.drink.DrunkOn]
+		if !ok {
+			day = &db.GetDrinksPerDayRow{DrunkOn: d.drink.DrunkOn}
+			byDay[d.drink.DrunkOn] = day
+		}
+		day.Drinks++
+		day.AlcoholMl += float64(d.drink.ServingMl) * d.beer.Abv / 100
+	}
+
+	days := []db.GetDrinksPerDayRow{}
+	for _, day := range byDay {
+		days = append(days, *day)
+	}
+	slices.SortFunc(days, func(a, b db.GetDrinksPerDayRow) int { return a.DrunkOn.Compare(b.DrunkOn) })
+	return days, nil
+}
+
+func (ds *MemoryDrinkStore) GetAbvDistribution(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetAbvDistributionRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]int64{}
+	for _, d := range joined {
+		counts[int64(math.Floor(d.beer.Abv))]++
+	}
+
+	distribution := []db.GetAbvDistributionRow{}
+	for abv, drinks := range counts {
+		distribution = append(distribution, db.GetAbvDistributionRow{Abv: abv, Drinks: drinks})
+	}
+	slices.SortFunc(distribution, func(a, b db.GetAbvDistributionRow) int { return cmp.Compare(a.Abv, b.Abv) })
+	return distribution, nil
+}
+
+func (ds *MemoryDrinkStore) GetTopStyles(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopStylesRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	styles := []db.GetTopStylesRow{}
+	for _, row := range countBy(joined, func(d joinedDrink) string { return d.beer.Style.String }, maxResults) {
+		styles = append(styles, db.GetTopStylesRow{Name: sql.NullString{Valid: true, String: row.Name}, Drinks: row.Drinks})
+	}
+	return styles, nil
+}
+
+func (ds *MemoryDrinkStore) GetTopBrewers(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopBrewersRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return countBy(joined, func(d joinedDrink) string { return d.brewer }, maxResults), nil
+}
+
+func (ds *MemoryDrinkStore) GetRatingHistogram(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetRatingHistogramRow, error) {
+	joined, err := ds.joined(ctx, userId, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	rated := map[int64]int64{}
+	for _, d := range joined {
+		if d.beer.Rating.Valid {
+			rated[d.beer.ID] = int64(math.Floor(d.beer.Rating.Float64))
+		}
+	}
+	counts := map[int64]int64{}
+	for _, rating := range rated {
+		counts[rating]++
+	}
+
+	histogram := []db.GetRatingHistogramRow{}
+	for rating, beers := range counts {
+		histogram = append(histogram, db.GetRatingHistogramRow{Rating: rating, Beers: beers})
+	}
+	slices.SortFunc(histogram, func(a, b db.GetRatingHistogramRow) int { return cmp.Compare(a.Rating, b.Rating) })
+	return histogram, nil
+}
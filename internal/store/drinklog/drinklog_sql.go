@@ -0,0 +1,129 @@
+package drinklog
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type DrinkStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewDrinkStore(queries db.Querier, logger *log.Logger) *DrinkStore {
+	return &DrinkStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ds *DrinkStore) AddDrink(ctx context.Context, params db.AddDrinkParams) (db.Drink, error) {
+	if err := validateDrink(params); err != nil {
+		return db.Drink{}, err
+	}
+
+	drink, err := ds.queries.AddDrink(ctx, normalizeDrink(params))
+	if err != nil {
+		// The user is whoever's logged in, so it's the beer that's missing
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Drink{}, beers.ErrBeerNotFound{ID: params.BeerID}
+		}
+		ds.logger.Printf("error adding drink: %v", err)
+		return db.Drink{}, err
+	}
+
+	ds.logger.Printf("drink added: %v", drink)
+	return drink, nil
+}
+
+func (ds *DrinkStore) GetDrink(ctx context.Context, id int64) (db.Drink, error) {
+	drink, err := ds.queries.GetDrink(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Drink{}, ErrDrinkNotFound{ID: id}
+		}
+		ds.logger.Printf("error getting drink: %v", err)
+		return db.Drink{}, err
+	}
+	return drink, nil
+}
+
+// The user's drinks in the range, latest first, including beers which are in the trash
+func (ds *DrinkStore) GetUserDrinks(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetUserDrinksRow, error) {
+	drinks, err := ds.queries.GetUserDrinks(ctx, db.GetUserDrinksParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		ds.logger.Printf("error getting user drinks: %v", err)
+		return nil, err
+	}
+	return drinks, nil
+}
+
+func (ds *DrinkStore) DeleteDrink(ctx context.Context, id int64) (db.Drink, error) {
+	drink, err := ds.queries.DeleteDrink(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Drink{}, ErrDrinkNotFound{ID: id}
+		}
+		ds.logger.Printf("error deleting drink: %v", err)
+		return db.Drink{}, err
+	}
+
+	ds.logger.Printf("drink deleted: %v", drink)
+	return drink, nil
+}
+
+// How many drinks the user had on each day of the range they had any, and how much alcohol was
+// in them, earliest first
+func (ds *DrinkStore) GetDrinksPerDay(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetDrinksPerDayRow, error) {
+	days, err := ds.queries.GetDrinksPerDay(ctx, db.GetDrinksPerDayParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		ds.logger.Printf("error getting drinks per day: %v", err)
+		return nil, err
+	}
+	return days, nil
+}
+
+// How many drinks the user had of beers of each whole number ABV, lowest first
+func (ds *DrinkStore) GetAbvDistribution(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetAbvDistributionRow, error) {
+	distribution, err := ds.queries.GetAbvDistribution(ctx, db.GetAbvDistributionParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		ds.logger.Printf("error getting ABV distribution: %v", err)
+		return nil, err
+	}
+	return distribution, nil
+}
+
+// The styles the user drank the most of, leaving out beers without a style
+func (ds *DrinkStore) GetTopStyles(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopStylesRow, error) {
+	styles, err := ds.queries.GetTopStyles(ctx, db.GetTopStylesParams{UserID: userId, Since: since, Until: until, MaxResults: maxResults})
+	if err != nil {
+		ds.logger.Printf("error getting top styles: %v", err)
+		return nil, err
+	}
+	return styles, nil
+}
+
+// The brewers the user drank the most of, leaving out beers without a brewer
+func (ds *DrinkStore) GetTopBrewers(ctx context.Context, userId int64, since time.Time, until time.Time, maxResults int64) ([]db.GetTopBrewersRow, error) {
+	brewers, err := ds.queries.GetTopBrewers(ctx, db.GetTopBrewersParams{UserID: userId, Since: since, Until: until, MaxResults: maxResults})
+	if err != nil {
+		ds.logger.Printf("error getting top brewers: %v", err)
+		return nil, err
+	}
+	return brewers, nil
+}
+
+// How many of the different beers the user drank had each whole number rating, lowest first
+func (ds *DrinkStore) GetRatingHistogram(ctx context.Context, userId int64, since time.Time, until time.Time) ([]db.GetRatingHistogramRow, error) {
+	histogram, err := ds.queries.GetRatingHistogram(ctx, db.GetRatingHistogramParams{UserID: userId, Since: since, Until: until})
+	if err != nil {
+		ds.logger.Printf("error getting rating histogram: %v", err)
+		return nil, err
+	}
+	return histogram, nil
+}
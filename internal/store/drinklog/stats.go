@@ -0,0 +1,82 @@
+package drinklog
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"beer_oclock/internal/store"
+	"time"
+)
+
+// The Monday of the week the day is in
+func WeekStart(day time.Time) time.Time {
+	day = store.Day(day)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// The days from a to b, which are both days as store.Day gives them
+func daysBetween(a time.Time, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// The drinks had in the week starting on Monday Start
+type Week struct {
+	Start     time.Time
+	Drinks    int64
+	AlcoholMl float64
+}
+
+func (w Week) StandardDrinks() float64 {
+	return drinks.StandardDrinksOfAlcohol(w.AlcoholMl)
+}
+
+// Totals up the days, as GetDrinksPerDay gives them, into every week the range touches,
+// including the weeks without any drinks. The first and last weeks can be partly outside the
+// range, and only count the days inside it.
+func Weekly(days []db.GetDrinksPerDayRow, since time.Time, until time.Time) []Week {
+	first := WeekStart(since)
+	weeks := []Week{}
+	for start := first; start.Before(until); start = start.AddDate(0, 0, 7) {
+		weeks = append(weeks, Week{Start: start})
+	}
+
+	for _, day := range days {
+		i := daysBetween(first, day.DrunkOn) / 7
+		if day.DrunkOn.Before(since) || !day.DrunkOn.Before(until) || i >= len(weeks) {
+			continue
+		}
+		weeks[i].Drinks += day.Drinks
+		weeks[i].AlcoholMl += day.AlcoholMl
+	}
+	return weeks
+}
+
+// A run of days without a drink, starting on Start
+type Streak struct {
+	Start time.Time
+	Days  int
+}
+
+// The last day of the streak
+func (s Streak) End() time.Time {
+	return s.Start.AddDate(0, 0, s.Days-1)
+}
+
+// The longest run of days in the range without a drink, from the days, as GetDrinksPerDay gives
+// them, with any drinks. If there's more than one, it's the first.
+func LongestDryStreak(days []db.GetDrinksPerDayRow, since time.Time, until time.Time) Streak {
+	longest := Streak{}
+	next := since
+	for _, day := range days {
+		if day.DrunkOn.Before(since) || !day.DrunkOn.Before(until) {
+			continue
+		}
+		if dry := daysBetween(next, day.DrunkOn); dry > longest.Days {
+			longest = Streak{Start: next, Days: dry}
+		}
+		next = day.DrunkOn.AddDate(0, 0, 1)
+	}
+	if dry := daysBetween(next, until); dry > longest.Days {
+		longest = Streak{Start: next, Days: dry}
+	}
+	return longest
+}
@@ -0,0 +1,89 @@
+package drinklog
+
+import (
+	"beer_oclock/internal/db"
+	"testing"
+	"time"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestWeekStart(t *testing.T) {
+	for _, tc := range []struct {
+		day  time.Time
+		want time.Time
+	}{
+		{day(time.March, 3), day(time.March, 3)},
+		{day(time.March, 9), day(time.March, 3)},
+		{day(time.March, 10), day(time.March, 10)},
+		// Across the end of the month and year
+		{day(time.April, 2), day(time.March, 31)},
+		{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)},
+		// Late on Sunday night somewhere ahead of UTC is still Sunday
+		{time.Date(2025, time.March, 9, 23, 30, 0, 0, time.FixedZone("AEDT", 11*60*60)), day(time.March, 3)},
+	} {
+		if got := WeekStart(tc.day); !got.Equal(tc.want) {
+			t.Errorf("WeekStart(%v): got %v, want %v", tc.day, got, tc.want)
+		}
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	days := []db.GetDrinksPerDayRow{
+		{DrunkOn: day(time.March, 4), Drinks: 9, AlcoholMl: 90},
+		{DrunkOn: day(time.March, 5), Drinks: 2, AlcoholMl: 20},
+		{DrunkOn: day(time.March, 9), Drinks: 3, AlcoholMl: 30},
+		{DrunkOn: day(time.March, 10), Drinks: 1, AlcoholMl: 10},
+		{DrunkOn: day(time.March, 21), Drinks: 4, AlcoholMl: 40},
+	}
+
+	// From a Wednesday to a Thursday, so the first and last weeks are only partly in it
+	weeks := Weekly(days, day(time.March, 5), day(time.March, 21))
+	want := []Week{
+		{Start: day(time.March, 3), Drinks: 5, AlcoholMl: 50},
+		{Start: day(time.March, 10), Drinks: 1, AlcoholMl: 10},
+		{Start: day(time.March, 17)},
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("Weekly: got %+v, want %+v", weeks, want)
+	}
+	for i := range want {
+		if !weeks[i].Start.Equal(want[i].Start) || weeks[i].Drinks != want[i].Drinks || weeks[i].AlcoholMl != want[i].AlcoholMl {
+			t.Errorf("Weekly: got %+v, want %+v", weeks[i], want[i])
+		}
+	}
+}
+
+func TestLongestDryStreak(t *testing.T) {
+	since, until := day(time.March, 1), day(time.April, 1)
+	for _, tc := range []struct {
+		name  string
+		drunk []time.Time
+		want  Streak
+	}{
+		{"no drinks", nil, Streak{Start: since, Days: 31}},
+		{"every day", func() (drunk []time.Time) {
+			for d := since; d.Before(until); d = d.AddDate(0, 0, 1) {
+				drunk = append(drunk, d)
+			}
+			return drunk
+		}(), Streak{}},
+		{"in the middle", []time.Time{day(time.March, 3), day(time.March, 20), day(time.March, 30)}, Streak{Start: day(time.March, 4), Days: 16}},
+		{"at the start", []time.Time{day(time.March, 25)}, Streak{Start: since, Days: 24}},
+		{"at the end", []time.Time{day(time.March, 2)}, Streak{Start: day(time.March, 3), Days: 29}},
+		{"the first of two", []time.Time{day(time.March, 11), day(time.March, 22)}, Streak{Start: since, Days: 10}},
+	} {
+		days := []db.GetDrinksPerDayRow{}
+		for _, d := range tc.drunk {
+			days = append(days, db.GetDrinksPerDayRow{DrunkOn: d, Drinks: 1})
+		}
+		if got := LongestDryStreak(days, since, until); got != tc.want {
+			t.Errorf("LongestDryStreak %s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+	if end := (Streak{Start: day(time.March, 4), Days: 16}).End(); !end.Equal(day(time.March, 19)) {
+		t.Errorf("Streak.End: got %v", end)
+	}
+}
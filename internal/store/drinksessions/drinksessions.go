@@ -0,0 +1,92 @@
+package drinksessions
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on drinking sessions, implemented by SessionStore
+// (backed by the database) and MemorySessionStore (for tests). A session groups the drinks its
+// participants had on a night out, along with the venues they went to.
+type Store interface {
+	AddSession(ctx context.Context, params db.AddDrinkingSessionParams) (db.DrinkingSession, error)
+	GetSession(ctx context.Context, id int64) (db.DrinkingSession, error)
+	GetSessionByToken(ctx context.Context, shareToken string) (db.DrinkingSession, error)
+	GetUserSessions(ctx context.Context, userId int64) ([]db.DrinkingSession, error)
+	EndSession(ctx context.Context, params db.EndDrinkingSessionParams) (db.DrinkingSession, error)
+	DeleteSession(ctx context.Context, id int64) (db.DrinkingSession, error)
+	AddParticipant(ctx context.Context, params db.AddSessionParticipantParams) error
+	GetParticipants(ctx context.Context, sessionId int64) ([]db.GetSessionParticipantsRow, error)
+	AddVenue(ctx context.Context, params db.AddSessionVenueParams) (db.DrinkingSessionVenue, error)
+	GetVenues(ctx context.Context, sessionId int64) ([]db.DrinkingSessionVenue, error)
+	AddDrink(ctx context.Context, params db.AddSessionDrinkParams) error
+	GetDrinks(ctx context.Context, sessionId int64) ([]db.GetSessionDrinksRow, error)
+}
+
+var _ Store = (*SessionStore)(nil)
+var _ Store = (*MemorySessionStore)(nil)
+
+// How many random bytes are in a share token, enough that they can't be guessed
+const tokenBytes = 18
+
+// Makes a token for a session's share link. Unlike password resets the token itself is stored,
+// so the link can be shown to the participants again.
+func NewShareToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func validateSession(params db.AddDrinkingSessionParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return store.ErrMissingField{Field: "name"}
+	}
+	if params.StartedAt.IsZero() {
+		return store.ErrMissingField{Field: "started-at"}
+	}
+	if params.ShareToken == "" {
+		return store.ErrMissingField{Field: "share-token"}
+	}
+	return nil
+}
+
+func normalizeSession(params db.AddDrinkingSessionParams) db.AddDrinkingSessionParams {
+	params.Name = strings.TrimSpace(params.Name)
+	params.StartedAt = params.StartedAt.UTC().Truncate(time.Second)
+	return params
+}
+
+// A session can't end before it started
+func validateEnd(session db.DrinkingSession, params db.EndDrinkingSessionParams) error {
+	if !params.EndedAt.Valid {
+		return store.ErrMissingField{Field: "ended-at"}
+	}
+	if params.EndedAt.Time.Before(session.StartedAt) {
+		return store.ErrInvalidField{Field: "ended-at", Reason: "must not be before the session started"}
+	}
+	return nil
+}
+
+func normalizeEnd(params db.EndDrinkingSessionParams) db.EndDrinkingSessionParams {
+	params.EndedAt.Time = params.EndedAt.Time.UTC().Truncate(time.Second)
+	return params
+}
+
+func validateVenue(params db.AddSessionVenueParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return store.ErrMissingField{Field: "venue"}
+	}
+	return nil
+}
+
+func normalizeVenue(params db.AddSessionVenueParams) db.AddSessionVenueParams {
+	params.Name = strings.TrimSpace(params.Name)
+	return params
+}
@@ -0,0 +1,26 @@
+package drinksessions
+
+import "fmt"
+
+type ErrSessionNotFound struct {
+	ID int64
+}
+
+func (e ErrSessionNotFound) Error() string {
+	return fmt.Sprintf("drinking session with id %d not found", e.ID)
+}
+
+// The token's left out, since it's as good as a password for seeing the session
+type ErrShareLinkNotFound struct{}
+
+func (e ErrShareLinkNotFound) Error() string {
+	return "no drinking session has that share link"
+}
+
+type ErrDrinkAlreadyInSession struct {
+	DrinkID int64
+}
+
+func (e ErrDrinkAlreadyInSession) Error() string {
+	return fmt.Sprintf("drink with id %d is already in a session", e.DrinkID)
+}
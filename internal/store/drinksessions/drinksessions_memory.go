@@ -0,0 +1,294 @@
+package drinksessions
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/drinklog"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as SessionStore. The user and drink stores stand in for the foreign keys, and
+// they and the beer store for the joins.
+type MemorySessionStore struct {
+	mu           sync.Mutex
+	userStore    users.Store
+	drinkStore   drinklog.Store
+	beerStore    beers.Store
+	lastId       int64
+	lastVenueId  int64
+	sessions     []db.DrinkingSession
+	participants []db.DrinkingSessionParticipant
+	venues       []db.DrinkingSessionVenue
+	drinks       []db.DrinkingSessionDrink
+}
+
+func NewMemorySessionStore(userStore users.Store, drinkStore drinklog.Store, beerStore beers.Store) *MemorySessionStore {
+	return &MemorySessionStore{
+		userStore:  userStore,
+		drinkStore: drinkStore,
+		beerStore:  beerStore,
+	}
+}
+
+// The index of the session with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ss *MemorySessionStore) find(id int64) int {
+	return slices.IndexFunc(ss.sessions, func(s db.DrinkingSession) bool { return s.ID == id })
+}
+
+func (ss *MemorySessionStore) AddSession(ctx context.Context, params db.AddDrinkingSessionParams) (db.DrinkingSession, error) {
+	if err := validateSession(params); err != nil {
+		return db.DrinkingSession{}, err
+	}
+	if params.CreatedBy.Valid {
+		if _, err := ss.userStore.GetUserById(ctx, params.CreatedBy.Int64); err != nil {
+			return db.DrinkingSession{}, users.ErrUserNotFound{ID: params.CreatedBy.Int64}
+		}
+	}
+	params = normalizeSession(params)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.lastId++
+	session := db.DrinkingSession{
+		ID:         ss.lastId,
+		Name:       params.Name,
+		CreatedBy:  params.CreatedBy,
+		StartedAt:  params.StartedAt,
+		ShareToken: params.ShareToken,
+		CreatedAt:  store.Now(),
+	}
+	ss.sessions = append(ss.sessions, session)
+	return session, nil
+}
+
+func (ss *MemorySessionStore) GetSession(ctx context.Context, id int64) (db.DrinkingSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := ss.find(id)
+	if i < 0 {
+		return db.DrinkingSession{}, ErrSessionNotFound{ID: id}
+	}
+	return ss.sessions[i], nil
+}
+
+func (ss *MemorySessionStore) GetSessionByToken(ctx context.Context, shareToken string) (db.DrinkingSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.sessions, func(s db.DrinkingSession) bool { return s.ShareToken == shareToken })
+	if i < 0 {
+		return db.DrinkingSession{}, ErrShareLinkNotFound{}
+	}
+	return ss.sessions[i], nil
+}
+
+func (ss *MemorySessionStore) GetUserSessions(ctx context.Context, userId int64) ([]db.DrinkingSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	sessions := []db.DrinkingSession{}
+	for _, p := range ss.participants {
+		if p.UserID == userId {
+			sessions = append(sessions, ss.sessions[ss.find(p.SessionID)])
+		}
+	}
+	slices.SortFunc(sessions, func(a, b db.DrinkingSession) int {
+		if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return sessions, nil
+}
+
+func (ss *MemorySessionStore) EndSession(ctx context.Context, params db.EndDrinkingSessionParams) (db.DrinkingSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := ss.find(params.ID)
+	if i < 0 {
+		return db.DrinkingSession{}, ErrSessionNotFound{ID: params.ID}
+	}
+	if err := validateEnd(ss.sessions[i], params); err != nil {
+		return db.DrinkingSession{}, err
+	}
+	ss.sessions[i].EndedAt = normalizeEnd(params).EndedAt
+	return ss.sessions[i], nil
+}
+
+func (ss *MemorySessionStore) DeleteSession(ctx context.Context, id int64) (db.DrinkingSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := ss.find(id)
+	if i < 0 {
+		return db.DrinkingSession{}, ErrSessionNotFound{ID: id}
+	}
+	session := ss.sessions[i]
+	ss.sessions = slices.Delete(ss.sessions, i, i+1)
+	ss.participants = slices.DeleteFunc(ss.participants, func(p db.DrinkingSessionParticipant) bool { return p.SessionID == id })
+	ss.venues = slices.DeleteFunc(ss.venues, func(v db.DrinkingSessionVenue) bool { return v.SessionID == id })
+	ss.drinks = slices.DeleteFunc(ss.drinks, func(d db.DrinkingSessionDrink) bool { return d.SessionID == id })
+	return session, nil
+}
+
+func (ss *MemorySessionStore) AddParticipant(ctx context.Context, params db.AddSessionParticipantParams) error {
+	if _, err := ss.userStore.GetUserById(ctx, params.UserID); err != nil {
+		if _, err := ss.GetSession(ctx, params.SessionID); err != nil {
+			return err
+		}
+		return users.ErrUserNotFound{ID: params.UserID}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.find(params.SessionID) < 0 {
+		return ErrSessionNotFound{ID: params.SessionID}
+	}
+	if slices.ContainsFunc(ss.participants, func(p db.DrinkingSessionParticipant) bool {
+		return p.SessionID == params.SessionID && p.UserID == params.UserID
+	}) {
+		return nil
+	}
+	ss.participants = append(ss.participants, db.DrinkingSessionParticipant{
+		SessionID: params.SessionID,
+		UserID:    params.UserID,
+		JoinedAt:  store.Now(),
+	})
+	return nil
+}
+
+func (ss *MemorySessionStore) GetParticipants(ctx context.Context, sessionId int64) ([]db.GetSessionParticipantsRow, error) {
+	ss.mu.Lock()
+	participants := slices.Clone(ss.participants)
+	ss.mu.Unlock()
+
+	rows := []db.GetSessionParticipantsRow{}
+	for _, p := range participants {
+		if p.SessionID != sessionId {
+			continue
+		}
+		// Like the join, which leaves out deleted users
+		user, err := ss.userStore.GetUserById(ctx, p.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetSessionParticipantsRow{UserID: user.ID, Username: user.Username, JoinedAt: p.JoinedAt})
+	}
+	slices.SortStableFunc(rows, func(a, b db.GetSessionParticipantsRow) int {
+		if c := a.JoinedAt.Compare(b.JoinedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return rows, nil
+}
+
+func (ss *MemorySessionStore) AddVenue(ctx context.Context, params db.AddSessionVenueParams) (db.DrinkingSessionVenue, error) {
+	if err := validateVenue(params); err != nil {
+		return db.DrinkingSessionVenue{}, err
+	}
+	params = normalizeVenue(params)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.find(params.SessionID) < 0 {
+		return db.DrinkingSessionVenue{}, ErrSessionNotFound{ID: params.SessionID}
+	}
+	ss.lastVenueId++
+	venue := db.DrinkingSessionVenue{
+		ID:        ss.lastVenueId,
+		SessionID: params.SessionID,
+		Name:      params.Name,
+		CreatedAt: store.Now(),
+	}
+	ss.venues = append(ss.venues, venue)
+	return venue, nil
+}
+
+func (ss *MemorySessionStore) GetVenues(ctx context.Context, sessionId int64) ([]db.DrinkingSessionVenue, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	venues := []db.DrinkingSessionVenue{}
+	for _, v := range ss.venues {
+		if v.SessionID == sessionId {
+			venues = append(venues, v)
+		}
+	}
+	return venues, nil
+}
+
+func (ss *MemorySessionStore) AddDrink(ctx context.Context, params db.AddSessionDrinkParams) error {
+	if _, err := ss.drinkStore.GetDrink(ctx, params.DrinkID); err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.find(params.SessionID) < 0 {
+		return ErrSessionNotFound{ID: params.SessionID}
+	}
+	if slices.ContainsFunc(ss.drinks, func(d db.DrinkingSessionDrink) bool { return d.DrinkID == params.DrinkID }) {
+		return ErrDrinkAlreadyInSession{DrinkID: params.DrinkID}
+	}
+	ss.drinks = append(ss.drinks, db.DrinkingSessionDrink{DrinkID: params.DrinkID, SessionID: params.SessionID})
+	return nil
+}
+
+func (ss *MemorySessionStore) GetDrinks(ctx context.Context, sessionId int64) ([]db.GetSessionDrinksRow, error) {
+	beersById := map[int64]db.Beer{}
+	for _, get := range []func(context.Context) ([]db.Beer, error){ss.beerStore.GetBeers, ss.beerStore.GetDeletedBeers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, beer := range all {
+			beersById[beer.ID] = beer
+		}
+	}
+
+	ss.mu.Lock()
+	sessionDrinks := slices.Clone(ss.drinks)
+	ss.mu.Unlock()
+
+	rows := []db.GetSessionDrinksRow{}
+	for _, d := range sessionDrinks {
+		if d.SessionID != sessionId {
+			continue
+		}
+		// Drinks that have been deleted since, and deleted users, are left out like the joins do
+		drink, err := ss.drinkStore.GetDrink(ctx, d.DrinkID)
+		if err != nil {
+			continue
+		}
+		user, err := ss.userStore.GetUserById(ctx, drink.UserID)
+		if err != nil {
+			continue
+		}
+		beer, ok := beersById[drink.BeerID]
+		if !ok {
+			continue
+		}
+		rows = append(rows, db.GetSessionDrinksRow{Drink: drink, BeerName: beer.Name, Abv: beer.Abv, Username: user.Username})
+	}
+	slices.SortFunc(rows, func(a, b db.GetSessionDrinksRow) int {
+		if c := a.Drink.DrunkAt.Compare(b.Drink.DrunkAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Drink.ID, b.Drink.ID)
+	})
+	return rows, nil
+}
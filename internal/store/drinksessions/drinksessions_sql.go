@@ -0,0 +1,192 @@
+package drinksessions
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type SessionStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewSessionStore(queries db.Querier, logger *log.Logger) *SessionStore {
+	return &SessionStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ss *SessionStore) AddSession(ctx context.Context, params db.AddDrinkingSessionParams) (db.DrinkingSession, error) {
+	if err := validateSession(params); err != nil {
+		return db.DrinkingSession{}, err
+	}
+
+	session, err := ss.queries.AddDrinkingSession(ctx, normalizeSession(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.DrinkingSession{}, users.ErrUserNotFound{ID: params.CreatedBy.Int64}
+		}
+		ss.logger.Printf("error adding drinking session: %v", err)
+		return db.DrinkingSession{}, err
+	}
+
+	ss.logger.Printf("drinking session added: %d %s", session.ID, session.Name)
+	return session, nil
+}
+
+func (ss *SessionStore) GetSession(ctx context.Context, id int64) (db.DrinkingSession, error) {
+	session, err := ss.queries.GetDrinkingSession(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.DrinkingSession{}, ErrSessionNotFound{ID: id}
+		}
+		ss.logger.Printf("error getting drinking session: %v", err)
+		return db.DrinkingSession{}, err
+	}
+	return session, nil
+}
+
+func (ss *SessionStore) GetSessionByToken(ctx context.Context, shareToken string) (db.DrinkingSession, error) {
+	session, err := ss.queries.GetDrinkingSessionByToken(ctx, shareToken)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.DrinkingSession{}, ErrShareLinkNotFound{}
+		}
+		ss.logger.Printf("error getting drinking session by token: %v", err)
+		return db.DrinkingSession{}, err
+	}
+	return session, nil
+}
+
+// The sessions the user's in, latest first
+func (ss *SessionStore) GetUserSessions(ctx context.Context, userId int64) ([]db.DrinkingSession, error) {
+	sessions, err := ss.queries.GetUserDrinkingSessions(ctx, userId)
+	if err != nil {
+		ss.logger.Printf("error getting user drinking sessions: %v", err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (ss *SessionStore) EndSession(ctx context.Context, params db.EndDrinkingSessionParams) (db.DrinkingSession, error) {
+	session, err := ss.GetSession(ctx, params.ID)
+	if err != nil {
+		return db.DrinkingSession{}, err
+	}
+	if err := validateEnd(session, params); err != nil {
+		return db.DrinkingSession{}, err
+	}
+
+	session, err = ss.queries.EndDrinkingSession(ctx, normalizeEnd(params))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.DrinkingSession{}, ErrSessionNotFound{ID: params.ID}
+		}
+		ss.logger.Printf("error ending drinking session: %v", err)
+		return db.DrinkingSession{}, err
+	}
+
+	ss.logger.Printf("drinking session ended: %d at %v", session.ID, session.EndedAt.Time)
+	return session, nil
+}
+
+// Deletes the session along with its participants and venues, but not the drinks had in it
+func (ss *SessionStore) DeleteSession(ctx context.Context, id int64) (db.DrinkingSession, error) {
+	session, err := ss.queries.DeleteDrinkingSession(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.DrinkingSession{}, ErrSessionNotFound{ID: id}
+		}
+		ss.logger.Printf("error deleting drinking session: %v", err)
+		return db.DrinkingSession{}, err
+	}
+
+	ss.logger.Printf("drinking session deleted: %d %s", session.ID, session.Name)
+	return session, nil
+}
+
+// Adds the user to the session, which does nothing if they're already in it
+func (ss *SessionStore) AddParticipant(ctx context.Context, params db.AddSessionParticipantParams) error {
+	if err := ss.queries.AddSessionParticipant(ctx, params); err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if _, err := ss.GetSession(ctx, params.SessionID); err != nil {
+				return err
+			}
+			return users.ErrUserNotFound{ID: params.UserID}
+		}
+		ss.logger.Printf("error adding session participant: %v", err)
+		return err
+	}
+	return nil
+}
+
+// The session's participants in the order they joined
+func (ss *SessionStore) GetParticipants(ctx context.Context, sessionId int64) ([]db.GetSessionParticipantsRow, error) {
+	participants, err := ss.queries.GetSessionParticipants(ctx, sessionId)
+	if err != nil {
+		ss.logger.Printf("error getting session participants: %v", err)
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (ss *SessionStore) AddVenue(ctx context.Context, params db.AddSessionVenueParams) (db.DrinkingSessionVenue, error) {
+	if err := validateVenue(params); err != nil {
+		return db.DrinkingSessionVenue{}, err
+	}
+
+	venue, err := ss.queries.AddSessionVenue(ctx, normalizeVenue(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.DrinkingSessionVenue{}, ErrSessionNotFound{ID: params.SessionID}
+		}
+		ss.logger.Printf("error adding session venue: %v", err)
+		return db.DrinkingSessionVenue{}, err
+	}
+
+	ss.logger.Printf("session venue added: %d %s to session %d", venue.ID, venue.Name, venue.SessionID)
+	return venue, nil
+}
+
+// The session's venues in the order they were added
+func (ss *SessionStore) GetVenues(ctx context.Context, sessionId int64) ([]db.DrinkingSessionVenue, error) {
+	venues, err := ss.queries.GetSessionVenues(ctx, sessionId)
+	if err != nil {
+		ss.logger.Printf("error getting session venues: %v", err)
+		return nil, err
+	}
+	return venues, nil
+}
+
+// Puts a drink that's been logged in the session
+func (ss *SessionStore) AddDrink(ctx context.Context, params db.AddSessionDrinkParams) error {
+	if err := ss.queries.AddSessionDrink(ctx, params); err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.UniqueConstraint:
+			return ErrDrinkAlreadyInSession{DrinkID: params.DrinkID}
+		case store.ForeignKeyConstraint:
+			// The drink's only just been logged, so it's the session that's missing
+			return ErrSessionNotFound{ID: params.SessionID}
+		}
+		ss.logger.Printf("error adding session drink: %v", err)
+		return err
+	}
+
+	ss.logger.Printf("session drink added: %d to session %d", params.DrinkID, params.SessionID)
+	return nil
+}
+
+// The drinks had in the session in the order they were had
+func (ss *SessionStore) GetDrinks(ctx context.Context, sessionId int64) ([]db.GetSessionDrinksRow, error) {
+	sessionDrinks, err := ss.queries.GetSessionDrinks(ctx, sessionId)
+	if err != nil {
+		ss.logger.Printf("error getting session drinks: %v", err)
+		return nil, err
+	}
+	return sessionDrinks, nil
+}
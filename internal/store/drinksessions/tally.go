@@ -0,0 +1,38 @@
+package drinksessions
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+)
+
+// A participant's part in a session: their drinks in the order they had them, and how many
+// standard drinks that came to
+type Tally struct {
+	UserID         int64
+	Username       string
+	Drinks         []db.GetSessionDrinksRow
+	StandardDrinks float64
+}
+
+// Splits the session's drinks up by participant, in the order the participants joined. Anyone
+// who had a drink in the session without being a participant any more still gets a tally, after
+// the participants.
+func Tallies(participants []db.GetSessionParticipantsRow, sessionDrinks []db.GetSessionDrinksRow) []Tally {
+	tallies := []Tally{}
+	index := map[int64]int{}
+	for _, p := range participants {
+		index[p.UserID] = len(tallies)
+		tallies = append(tallies, Tally{UserID: p.UserID, Username: p.Username, Drinks: []db.GetSessionDrinksRow{}})
+	}
+	for _, d := range sessionDrinks {
+		i, ok := index[d.Drink.UserID]
+		if !ok {
+			i = len(tallies)
+			index[d.Drink.UserID] = i
+			tallies = append(tallies, Tally{UserID: d.Drink.UserID, Username: d.Username, Drinks: []db.GetSessionDrinksRow{}})
+		}
+		tallies[i].Drinks = append(tallies[i].Drinks, d)
+		tallies[i].StandardDrinks += drinks.StandardDrinks(float64(d.Drink.ServingMl), d.Abv)
+	}
+	return tallies
+}
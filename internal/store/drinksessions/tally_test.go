@@ -0,0 +1,50 @@
+package drinksessions
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"math"
+	"testing"
+)
+
+func TestTallies(t *testing.T) {
+	participants := []db.GetSessionParticipantsRow{
+		{UserID: 2, Username: "bob"},
+		{UserID: 1, Username: "alice"},
+		{UserID: 3, Username: "carol"},
+	}
+	drink := func(id int64, userId int64, username string, servingMl int64, abv float64) db.GetSessionDrinksRow {
+		return db.GetSessionDrinksRow{Drink: db.Drink{ID: id, UserID: userId, ServingMl: servingMl}, Abv: abv, Username: username}
+	}
+	sessionDrinks := []db.GetSessionDrinksRow{
+		drink(1, 1, "alice", 375, 4.8),
+		drink(2, 2, "bob", 570, 5.2),
+		drink(3, 1, "alice", 375, 6),
+		drink(4, 4, "dave", 285, 3.5),
+	}
+
+	tallies := Tallies(participants, sessionDrinks)
+	if len(tallies) != 4 {
+		t.Fatalf("got %d tallies, want 4: %+v", len(tallies), tallies)
+	}
+
+	// In the order the participants joined, then anyone else who had a drink
+	for i, want := range []string{"bob", "alice", "carol", "dave"} {
+		if tallies[i].Username != want {
+			t.Errorf("tally %d is for %s, want %s", i, tallies[i].Username, want)
+		}
+	}
+
+	alice := tallies[1]
+	if len(alice.Drinks) != 2 || alice.Drinks[0].Drink.ID != 1 || alice.Drinks[1].Drink.ID != 3 {
+		t.Errorf("got alice's drinks %+v, want 1 then 3", alice.Drinks)
+	}
+	if want := drinks.StandardDrinks(375, 4.8) + drinks.StandardDrinks(375, 6); math.Abs(alice.StandardDrinks-want) > 1e-9 {
+		t.Errorf("got alice's standard drinks %v, want %v", alice.StandardDrinks, want)
+	}
+
+	// Participants who haven't had anything yet still show up, with nothing
+	if carol := tallies[2]; len(carol.Drinks) != 0 || carol.StandardDrinks != 0 {
+		t.Errorf("got carol's tally %+v, want nothing", carol)
+	}
+}
@@ -0,0 +1,47 @@
+package emails
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on users' email addresses, implemented by EmailStore
+// (backed by the database) and MemoryEmailStore (for tests). Each user has at most one address,
+// and no two users share one.
+type Store interface {
+	SetUserEmail(ctx context.Context, params db.SetUserEmailParams) (db.UserEmail, error)
+	GetUserEmail(ctx context.Context, userId int64) (db.UserEmail, error)
+	GetUserEmailByAddress(ctx context.Context, email string) (db.UserEmail, error)
+	GetUserEmails(ctx context.Context) ([]db.GetUserEmailsRow, error)
+	SetSummarySent(ctx context.Context, userId int64, sentOn time.Time) error
+	DeleteUserEmail(ctx context.Context, userId int64) (db.UserEmail, error)
+}
+
+var _ Store = (*EmailStore)(nil)
+var _ Store = (*MemoryEmailStore)(nil)
+
+func validateUserEmail(params db.SetUserEmailParams) error {
+	email := strings.TrimSpace(params.Email)
+	if email == "" {
+		return store.ErrMissingField{Field: "email"}
+	}
+	// Just the address, without a name in front of it or angle brackets around it
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return store.ErrInvalidField{Field: "email", Reason: "must be an email address like someone@example.com"}
+	}
+	return nil
+}
+
+// Addresses are kept in lower case, so the same address can't be used twice by changing its case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func normalizeUserEmail(params db.SetUserEmailParams) db.SetUserEmailParams {
+	params.Email = NormalizeEmail(params.Email)
+	return params
+}
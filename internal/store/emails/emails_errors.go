@@ -0,0 +1,27 @@
+package emails
+
+import "fmt"
+
+type ErrUserEmailNotFound struct {
+	UserID int64
+}
+
+func (e ErrUserEmailNotFound) Error() string {
+	return fmt.Sprintf("no email address for user with id %d", e.UserID)
+}
+
+type ErrEmailNotFound struct {
+	Email string
+}
+
+func (e ErrEmailNotFound) Error() string {
+	return fmt.Sprintf("no user with email address %s", e.Email)
+}
+
+type ErrEmailAlreadyUsed struct {
+	Email string
+}
+
+func (e ErrEmailAlreadyUsed) Error() string {
+	return fmt.Sprintf("email address %s is already used by another user", e.Email)
+}
@@ -0,0 +1,139 @@
+package emails
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as EmailStore. The user store stands in for the foreign key, and it and the goal
+// store for the joins.
+type MemoryEmailStore struct {
+	mu        sync.Mutex
+	userStore users.Store
+	goalStore goals.Store
+	emails    map[int64]db.UserEmail
+}
+
+func NewMemoryEmailStore(userStore users.Store, goalStore goals.Store) *MemoryEmailStore {
+	return &MemoryEmailStore{
+		userStore: userStore,
+		goalStore: goalStore,
+		emails:    make(map[int64]db.UserEmail),
+	}
+}
+
+func (es *MemoryEmailStore) SetUserEmail(ctx context.Context, params db.SetUserEmailParams) (db.UserEmail, error) {
+	if err := validateUserEmail(params); err != nil {
+		return db.UserEmail{}, err
+	}
+	if _, err := es.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.UserEmail{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+	params = normalizeUserEmail(params)
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for _, e := range es.emails {
+		if e.Email == params.Email && e.UserID != params.UserID {
+			return db.UserEmail{}, ErrEmailAlreadyUsed{Email: params.Email}
+		}
+	}
+
+	email := db.UserEmail{
+		UserID:        params.UserID,
+		Email:         params.Email,
+		WeeklySummary: params.WeeklySummary,
+		Reminders:     params.Reminders,
+		SummarySentOn: es.emails[params.UserID].SummarySentOn,
+		UpdatedAt:     store.Now(),
+	}
+	es.emails[params.UserID] = email
+	return email, nil
+}
+
+func (es *MemoryEmailStore) GetUserEmail(ctx context.Context, userId int64) (db.UserEmail, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	email, ok := es.emails[userId]
+	if !ok {
+		return db.UserEmail{}, ErrUserEmailNotFound{UserID: userId}
+	}
+	return email, nil
+}
+
+func (es *MemoryEmailStore) GetUserEmailByAddress(ctx context.Context, email string) (db.UserEmail, error) {
+	email = NormalizeEmail(email)
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for _, e := range es.emails {
+		if e.Email == email {
+			return e, nil
+		}
+	}
+	return db.UserEmail{}, ErrEmailNotFound{Email: email}
+}
+
+func (es *MemoryEmailStore) GetUserEmails(ctx context.Context) ([]db.GetUserEmailsRow, error) {
+	allUsers, err := es.userStore.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeZones := map[int64]string{}
+	for _, user := range allUsers {
+		userGoals, err := es.goalStore.GetGoals(ctx, user.ID)
+		if err == nil {
+			timeZones[user.ID] = userGoals.TimeZone
+		} else if _, ok := err.(goals.ErrGoalsNotFound); !ok {
+			return nil, err
+		}
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	rows := []db.GetUserEmailsRow{}
+	for _, user := range allUsers {
+		if email, ok := es.emails[user.ID]; ok {
+			rows = append(rows, db.GetUserEmailsRow{UserEmail: email, Username: user.Username, TimeZone: timeZones[user.ID]})
+		}
+	}
+	slices.SortFunc(rows, func(a, b db.GetUserEmailsRow) int {
+		return int(a.UserEmail.UserID - b.UserEmail.UserID)
+	})
+	return rows, nil
+}
+
+func (es *MemoryEmailStore) SetSummarySent(ctx context.Context, userId int64, sentOn time.Time) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if email, ok := es.emails[userId]; ok {
+		email.SummarySentOn = sql.NullTime{Valid: true, Time: sentOn}
+		es.emails[userId] = email
+	}
+	return nil
+}
+
+func (es *MemoryEmailStore) DeleteUserEmail(ctx context.Context, userId int64) (db.UserEmail, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	email, ok := es.emails[userId]
+	if !ok {
+		return db.UserEmail{}, ErrUserEmailNotFound{UserID: userId}
+	}
+	delete(es.emails, userId)
+	return email, nil
+}
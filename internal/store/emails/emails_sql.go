@@ -0,0 +1,108 @@
+package emails
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type EmailStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewEmailStore(queries db.Querier, logger *log.Logger) *EmailStore {
+	return &EmailStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Sets the user's address and which emails they want, replacing what they had
+func (es *EmailStore) SetUserEmail(ctx context.Context, params db.SetUserEmailParams) (db.UserEmail, error) {
+	if err := validateUserEmail(params); err != nil {
+		return db.UserEmail{}, err
+	}
+	params = normalizeUserEmail(params)
+
+	email, err := es.queries.SetUserEmail(ctx, params)
+	if err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.UniqueConstraint:
+			return db.UserEmail{}, ErrEmailAlreadyUsed{Email: params.Email}
+		case store.ForeignKeyConstraint:
+			return db.UserEmail{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		es.logger.Printf("error setting user email: %v", err)
+		return db.UserEmail{}, err
+	}
+
+	es.logger.Printf("user email set: %v", email)
+	return email, nil
+}
+
+func (es *EmailStore) GetUserEmail(ctx context.Context, userId int64) (db.UserEmail, error) {
+	email, err := es.queries.GetUserEmail(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UserEmail{}, ErrUserEmailNotFound{UserID: userId}
+		}
+		es.logger.Printf("error getting user email: %v", err)
+		return db.UserEmail{}, err
+	}
+	return email, nil
+}
+
+func (es *EmailStore) GetUserEmailByAddress(ctx context.Context, email string) (db.UserEmail, error) {
+	email = NormalizeEmail(email)
+	userEmail, err := es.queries.GetUserEmailByAddress(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UserEmail{}, ErrEmailNotFound{Email: email}
+		}
+		es.logger.Printf("error getting user email: %v", err)
+		return db.UserEmail{}, err
+	}
+	return userEmail, nil
+}
+
+// Everyone's addresses, except for users in the trash, with their usernames and time zones
+func (es *EmailStore) GetUserEmails(ctx context.Context) ([]db.GetUserEmailsRow, error) {
+	emails, err := es.queries.GetUserEmails(ctx)
+	if err != nil {
+		es.logger.Printf("error getting user emails: %v", err)
+		return nil, err
+	}
+	return emails, nil
+}
+
+// Records the day the user's weekly summary was last sent, so it's only sent once a week
+func (es *EmailStore) SetSummarySent(ctx context.Context, userId int64, sentOn time.Time) error {
+	err := es.queries.SetSummarySent(ctx, db.SetSummarySentParams{
+		SummarySentOn: sql.NullTime{Valid: true, Time: sentOn},
+		UserID:        userId,
+	})
+	if err != nil {
+		es.logger.Printf("error setting summary sent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (es *EmailStore) DeleteUserEmail(ctx context.Context, userId int64) (db.UserEmail, error) {
+	email, err := es.queries.DeleteUserEmail(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UserEmail{}, ErrUserEmailNotFound{UserID: userId}
+		}
+		es.logger.Printf("error deleting user email: %v", err)
+		return db.UserEmail{}, err
+	}
+
+	es.logger.Printf("user email deleted: %v", email)
+	return email, nil
+}
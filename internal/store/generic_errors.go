@@ -27,11 +27,16 @@ func (e ErrBrewerNotFound) Error() string {
 	return fmt.Sprintf("brewer with id %d not found", e.ID)
 }
 
+// Deleted is set when the name belongs to a beer in the trash, which can be restored instead
 type ErrBeerAlreadyExists struct {
 	BrewerId int64
 	Name     string
+	Deleted  bool
 }
 
 func (e ErrBeerAlreadyExists) Error() string {
+	if e.Deleted {
+		return fmt.Sprintf("beer with name %s for brewer with id %d is in the trash", e.Name, e.BrewerId)
+	}
 	return fmt.Sprintf("beer with name %s already exists for brewer with id %d", e.Name, e.BrewerId)
 }
@@ -0,0 +1,56 @@
+package goals
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"strings"
+	"time"
+
+	// Time zones are checked against the database built into the binary, so they're the same
+	// wherever the server runs
+	_ "time/tzdata"
+)
+
+// The operations the rest of the app needs on users' drinking goals, implemented by GoalStore
+// (backed by the database) and MemoryGoalStore (for tests). Each user has at most one set of
+// goals.
+type Store interface {
+	SetGoals(ctx context.Context, params db.SetGoalsParams) (db.Goal, error)
+	GetGoals(ctx context.Context, userId int64) (db.Goal, error)
+	DeleteGoals(ctx context.Context, userId int64) (db.Goal, error)
+}
+
+var _ Store = (*GoalStore)(nil)
+var _ Store = (*MemoryGoalStore)(nil)
+
+func validateGoals(params db.SetGoalsParams) error {
+	if params.WeeklyLimit.Valid && params.WeeklyLimit.Float64 <= 0 {
+		return store.ErrInvalidField{Field: "weekly-limit", Reason: "must be more than 0"}
+	}
+	if params.AlcoholFreeDays.Valid && (params.AlcoholFreeDays.Int64 < 1 || params.AlcoholFreeDays.Int64 > 7) {
+		return store.ErrInvalidField{Field: "alcohol-free-days", Reason: "must be between 1 and 7"}
+	}
+	if _, err := time.LoadLocation(strings.TrimSpace(params.TimeZone)); err != nil {
+		return store.ErrInvalidField{Field: "time-zone", Reason: "must be a time zone like Australia/Sydney"}
+	}
+	return nil
+}
+
+func normalizeGoals(params db.SetGoalsParams) db.SetGoalsParams {
+	params.TimeZone = strings.TrimSpace(params.TimeZone)
+	return params
+}
+
+// Where the user is, or the fallback if they haven't said. The time zone was checked when the
+// goals were set, but falls back too if it's since been dropped from the database.
+func Location(goals db.Goal, fallback *time.Location) *time.Location {
+	if goals.TimeZone == "" {
+		return fallback
+	}
+	location, err := time.LoadLocation(goals.TimeZone)
+	if err != nil {
+		return fallback
+	}
+	return location
+}
@@ -0,0 +1,11 @@
+package goals
+
+import "fmt"
+
+type ErrGoalsNotFound struct {
+	UserID int64
+}
+
+func (e ErrGoalsNotFound) Error() string {
+	return fmt.Sprintf("no goals for user with id %d", e.UserID)
+}
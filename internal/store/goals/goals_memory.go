@@ -0,0 +1,71 @@
+package goals
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as GoalStore. The user store stands in for the foreign key.
+type MemoryGoalStore struct {
+	mu        sync.Mutex
+	userStore users.Store
+	goals     map[int64]db.Goal
+}
+
+func NewMemoryGoalStore(userStore users.Store) *MemoryGoalStore {
+	return &MemoryGoalStore{
+		userStore: userStore,
+		goals:     make(map[int64]db.Goal),
+	}
+}
+
+func (gs *MemoryGoalStore) SetGoals(ctx context.Context, params db.SetGoalsParams) (db.Goal, error) {
+	if err := validateGoals(params); err != nil {
+		return db.Goal{}, err
+	}
+	if _, err := gs.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.Goal{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+	params = normalizeGoals(params)
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	goals := db.Goal{
+		UserID:           params.UserID,
+		WeeklyLimit:      params.WeeklyLimit,
+		AlcoholFreeDays:  params.AlcoholFreeDays,
+		ConfirmOverLimit: params.ConfirmOverLimit,
+		TimeZone:         params.TimeZone,
+		UpdatedAt:        store.Now(),
+	}
+	gs.goals[params.UserID] = goals
+	return goals, nil
+}
+
+func (gs *MemoryGoalStore) GetGoals(ctx context.Context, userId int64) (db.Goal, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	goals, ok := gs.goals[userId]
+	if !ok {
+		return db.Goal{}, ErrGoalsNotFound{UserID: userId}
+	}
+	return goals, nil
+}
+
+func (gs *MemoryGoalStore) DeleteGoals(ctx context.Context, userId int64) (db.Goal, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	goals, ok := gs.goals[userId]
+	if !ok {
+		return db.Goal{}, ErrGoalsNotFound{UserID: userId}
+	}
+	delete(gs.goals, userId)
+	return goals, nil
+}
@@ -0,0 +1,67 @@
+package goals
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type GoalStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewGoalStore(queries db.Querier, logger *log.Logger) *GoalStore {
+	return &GoalStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Sets the user's goals, replacing the ones they had
+func (gs *GoalStore) SetGoals(ctx context.Context, params db.SetGoalsParams) (db.Goal, error) {
+	if err := validateGoals(params); err != nil {
+		return db.Goal{}, err
+	}
+
+	goals, err := gs.queries.SetGoals(ctx, normalizeGoals(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Goal{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		gs.logger.Printf("error setting goals: %v", err)
+		return db.Goal{}, err
+	}
+
+	gs.logger.Printf("goals set: %v", goals)
+	return goals, nil
+}
+
+func (gs *GoalStore) GetGoals(ctx context.Context, userId int64) (db.Goal, error) {
+	goals, err := gs.queries.GetGoals(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Goal{}, ErrGoalsNotFound{UserID: userId}
+		}
+		gs.logger.Printf("error getting goals: %v", err)
+		return db.Goal{}, err
+	}
+	return goals, nil
+}
+
+func (gs *GoalStore) DeleteGoals(ctx context.Context, userId int64) (db.Goal, error) {
+	goals, err := gs.queries.DeleteGoals(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Goal{}, ErrGoalsNotFound{UserID: userId}
+		}
+		gs.logger.Printf("error deleting goals: %v", err)
+		return db.Goal{}, err
+	}
+
+	gs.logger.Printf("goals deleted: %v", goals)
+	return goals, nil
+}
@@ -0,0 +1,136 @@
+package goals
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/drinklog"
+	"time"
+)
+
+// How the week is going against the user's goals, like a traffic light
+type Light int
+
+const (
+	// The user hasn't set any goals to measure against
+	NoLight Light = iota
+	Green
+	// Close to the weekly limit, or with no drinking days to spare
+	Amber
+	// Over the weekly limit, or with too few days left to reach the alcohol-free days
+	Red
+)
+
+func (l Light) String() string {
+	switch l {
+	case Green:
+		return "green"
+	case Amber:
+		return "amber"
+	case Red:
+		return "red"
+	default:
+		return ""
+	}
+}
+
+// How much of the weekly limit can be drunk before the light goes amber
+const AmberFraction = 0.75
+
+// A week of drinking measured against the user's goals, as of Today
+type Progress struct {
+	Goals     db.Goal
+	WeekStart time.Time
+	Today     time.Time
+	// The drinks had all week, including any logged ahead of today
+	StandardDrinks float64
+	DrankToday     bool
+	// The days before today without a drink
+	AlcoholFreeDays int
+	// The most alcohol-free days the week can still end up with, counting today and the days
+	// after it that don't have a drink logged yet
+	PossibleAlcoholFreeDays int
+	Light                   Light
+}
+
+// The standard drinks left before the weekly limit, which is negative once over it
+func (p Progress) Remaining() float64 {
+	return p.Goals.WeeklyLimit.Float64 - p.StandardDrinks
+}
+
+// The days after today until the end of the week
+func (p Progress) DaysLeft() int {
+	return 6 - int(p.Today.Sub(p.WeekStart).Hours()/24)
+}
+
+// The Monday starting the week now is in, and the Monday after, for GetDrinksPerDay. Now should
+// be in the user's time zone, so the week turns over at their midnight.
+func Week(now time.Time) (since time.Time, until time.Time) {
+	since = drinklog.WeekStart(now)
+	return since, since.AddDate(0, 0, 7)
+}
+
+// Measures the week now is in against the goals, given the days of drinking in it from
+// GetDrinksPerDay. Days outside the week are ignored, so a wider range is fine.
+func Evaluate(goals db.Goal, days []db.GetDrinksPerDayRow, now time.Time) Progress {
+	since, until := Week(now)
+	progress := Progress{Goals: goals, WeekStart: since, Today: store.Day(now)}
+
+	drinkingDays := map[time.Time]bool{}
+	alcoholMl := 0.0
+	for _, day := range days {
+		if day.DrunkOn.Before(since) || !day.DrunkOn.Before(until) || day.Drinks == 0 {
+			continue
+		}
+		alcoholMl += day.AlcoholMl
+		drinkingDays[day.DrunkOn] = true
+	}
+	progress.StandardDrinks = drinks.StandardDrinksOfAlcohol(alcoholMl)
+	progress.DrankToday = drinkingDays[progress.Today]
+
+	for day := since; day.Before(until); day = day.AddDate(0, 0, 1) {
+		if drinkingDays[day] {
+			continue
+		}
+		if day.Before(progress.Today) {
+			progress.AlcoholFreeDays++
+		}
+		progress.PossibleAlcoholFreeDays++
+	}
+
+	progress.Light = light(progress)
+	return progress
+}
+
+func light(p Progress) Light {
+	limit, target := p.Goals.WeeklyLimit, p.Goals.AlcoholFreeDays
+	if !limit.Valid && !target.Valid {
+		return NoLight
+	}
+	switch {
+	case limit.Valid && p.StandardDrinks > limit.Float64:
+		return Red
+	case target.Valid && int64(p.PossibleAlcoholFreeDays) < target.Int64:
+		return Red
+	case limit.Valid && p.StandardDrinks >= AmberFraction*limit.Float64:
+		return Amber
+	// Another day of drinking this week would miss the target, unless the week's over bar today
+	// and today's already a drinking day
+	case target.Valid && int64(p.PossibleAlcoholFreeDays) == target.Int64 && (p.DaysLeft() > 0 || !p.DrankToday):
+		return Amber
+	}
+	return Green
+}
+
+// Whether having another standardDrinks today would break a goal that wasn't already broken,
+// going over the weekly limit or leaving too few days for the alcohol-free days
+func WouldExceed(p Progress, standardDrinks float64) bool {
+	limit, target := p.Goals.WeeklyLimit, p.Goals.AlcoholFreeDays
+	if limit.Valid && p.StandardDrinks <= limit.Float64 && p.StandardDrinks+standardDrinks > limit.Float64 {
+		return true
+	}
+	if target.Valid && !p.DrankToday && int64(p.PossibleAlcoholFreeDays) == target.Int64 {
+		return true
+	}
+	return false
+}
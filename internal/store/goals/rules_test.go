@@ -0,0 +1,186 @@
+package goals
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// A day with the given standard drinks on it
+func drank(on time.Time, standardDrinks float64) db.GetDrinksPerDayRow {
+	return db.GetDrinksPerDayRow{
+		DrunkOn:   on,
+		Drinks:    1,
+		AlcoholMl: standardDrinks * drinks.GramsPerStandardDrink / drinks.EthanolDensity,
+	}
+}
+
+func limit(standardDrinks float64) sql.NullFloat64 {
+	return sql.NullFloat64{Valid: true, Float64: standardDrinks}
+}
+
+func freeDays(days int64) sql.NullInt64 {
+	return sql.NullInt64{Valid: true, Int64: days}
+}
+
+func TestEvaluateWeek(t *testing.T) {
+	days := []db.GetDrinksPerDayRow{
+		// Sunday, the week before
+		drank(day(time.March, 9), 5),
+		drank(day(time.March, 10), 2),
+		drank(day(time.March, 12), 3),
+		// Monday, the week after
+		drank(day(time.March, 17), 4),
+	}
+	for _, tc := range []struct {
+		name      string
+		now       time.Time
+		weekStart time.Time
+		standard  float64
+		free      int
+		possible  int
+	}{
+		{"Sunday night", time.Date(2025, time.March, 9, 23, 59, 0, 0, time.UTC), day(time.March, 3), 5, 6, 6},
+		{"Monday morning", time.Date(2025, time.March, 10, 0, 1, 0, 0, time.UTC), day(time.March, 10), 5, 0, 5},
+		{"Wednesday", day(time.March, 12), day(time.March, 10), 5, 1, 5},
+		{"Sunday", day(time.March, 16), day(time.March, 10), 5, 4, 5},
+		{"Next Monday", day(time.March, 17), day(time.March, 17), 4, 0, 6},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Evaluate(db.Goal{}, days, tc.now)
+			if !got.WeekStart.Equal(tc.weekStart) {
+				t.Errorf("week start: got %v, want %v", got.WeekStart, tc.weekStart)
+			}
+			if math.Abs(got.StandardDrinks-tc.standard) > 0.001 {
+				t.Errorf("standard drinks: got %v, want %v", got.StandardDrinks, tc.standard)
+			}
+			if got.AlcoholFreeDays != tc.free || got.PossibleAlcoholFreeDays != tc.possible {
+				t.Errorf("alcohol-free days: got %d of a possible %d, want %d of %d", got.AlcoholFreeDays, got.PossibleAlcoholFreeDays, tc.free, tc.possible)
+			}
+			if got.Light != NoLight {
+				t.Errorf("light without goals: got %v", got.Light)
+			}
+		})
+	}
+}
+
+func TestEvaluateAcrossMonthsAndYears(t *testing.T) {
+	days := []db.GetDrinksPerDayRow{
+		drank(day(time.March, 31), 1),
+		drank(day(time.April, 6), 1),
+		drank(time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC), 1),
+		drank(time.Date(2026, time.January, 4, 0, 0, 0, 0, time.UTC), 1),
+	}
+	for _, tc := range []struct {
+		now       time.Time
+		weekStart time.Time
+	}{
+		{day(time.April, 2), day(time.March, 31)},
+		{time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC), time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)},
+	} {
+		got := Evaluate(db.Goal{}, days, tc.now)
+		if !got.WeekStart.Equal(tc.weekStart) {
+			t.Errorf("%v: week start: got %v, want %v", tc.now, got.WeekStart, tc.weekStart)
+		}
+		if math.Abs(got.StandardDrinks-2) > 0.001 {
+			t.Errorf("%v: standard drinks: got %v, want 2", tc.now, got.StandardDrinks)
+		}
+	}
+}
+
+func TestEvaluateTimeZones(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := []db.GetDrinksPerDayRow{drank(day(time.March, 9), 1), drank(day(time.March, 10), 2)}
+
+	// The same moment is Monday in Sydney, still Sunday in UTC, and Sunday morning in New York,
+	// the day its clocks went forward
+	instant := time.Date(2025, time.March, 9, 14, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		location  *time.Location
+		weekStart time.Time
+		today     time.Time
+		standard  float64
+	}{
+		{sydney, day(time.March, 10), day(time.March, 10), 2},
+		{time.UTC, day(time.March, 3), day(time.March, 9), 1},
+		{newYork, day(time.March, 3), day(time.March, 9), 1},
+		{time.FixedZone("UTC+10", 10*60*60), day(time.March, 10), day(time.March, 10), 2},
+	} {
+		got := Evaluate(db.Goal{}, days, instant.In(tc.location))
+		if !got.WeekStart.Equal(tc.weekStart) || !got.Today.Equal(tc.today) {
+			t.Errorf("%v: got week %v and today %v, want %v and %v", tc.location, got.WeekStart, got.Today, tc.weekStart, tc.today)
+		}
+		if math.Abs(got.StandardDrinks-tc.standard) > 0.001 {
+			t.Errorf("%v: standard drinks: got %v, want %v", tc.location, got.StandardDrinks, tc.standard)
+		}
+		if !got.DrankToday {
+			t.Errorf("%v: should have drunk today", tc.location)
+		}
+	}
+}
+
+func TestLight(t *testing.T) {
+	wednesday := day(time.March, 12)
+	sunday := day(time.March, 16)
+	for _, tc := range []struct {
+		name  string
+		goals db.Goal
+		days  []db.GetDrinksPerDayRow
+		now   time.Time
+		want  Light
+	}{
+		{"under the limit", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 7)}, wednesday, Green},
+		{"close to the limit", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 7.5)}, wednesday, Amber},
+		{"at the limit", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 10)}, wednesday, Amber},
+		{"over the limit", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 6), drank(wednesday, 5)}, wednesday, Red},
+		{"last week doesn't count", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(day(time.March, 9), 20)}, wednesday, Green},
+		{"days to spare", db.Goal{AlcoholFreeDays: freeDays(4)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1)}, wednesday, Green},
+		{"no days to spare", db.Goal{AlcoholFreeDays: freeDays(4)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1), drank(day(time.March, 11), 1), drank(wednesday, 1)}, wednesday, Amber},
+		{"target missed", db.Goal{AlcoholFreeDays: freeDays(5)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1), drank(day(time.March, 11), 1), drank(wednesday, 1)}, wednesday, Red},
+		{"target met on Sunday", db.Goal{AlcoholFreeDays: freeDays(6)}, []db.GetDrinksPerDayRow{drank(sunday, 1)}, sunday, Green},
+		{"target at risk on Sunday", db.Goal{AlcoholFreeDays: freeDays(7)}, nil, sunday, Amber},
+		{"worst goal wins", db.Goal{WeeklyLimit: limit(10), AlcoholFreeDays: freeDays(6)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1), drank(day(time.March, 11), 1)}, wednesday, Red},
+	} {
+		if got := Evaluate(tc.goals, tc.days, tc.now).Light; got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestWouldExceed(t *testing.T) {
+	wednesday := day(time.March, 12)
+	for _, tc := range []struct {
+		name     string
+		goals    db.Goal
+		days     []db.GetDrinksPerDayRow
+		standard float64
+		want     bool
+	}{
+		{"no goals", db.Goal{}, []db.GetDrinksPerDayRow{drank(wednesday, 100)}, 1, false},
+		{"stays under", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(wednesday, 8)}, 2, false},
+		{"goes over", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(wednesday, 8)}, 2.5, true},
+		{"already over", db.Goal{WeeklyLimit: limit(10)}, []db.GetDrinksPerDayRow{drank(wednesday, 11)}, 1, false},
+		{"uses the last spare day", db.Goal{AlcoholFreeDays: freeDays(6)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1)}, 1, true},
+		{"already drinking today", db.Goal{AlcoholFreeDays: freeDays(5)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1), drank(wednesday, 1)}, 1, false},
+		{"days to spare", db.Goal{AlcoholFreeDays: freeDays(5)}, []db.GetDrinksPerDayRow{drank(day(time.March, 10), 1)}, 1, false},
+	} {
+		progress := Evaluate(tc.goals, tc.days, wednesday)
+		if got := WouldExceed(progress, tc.standard); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
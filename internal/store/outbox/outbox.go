@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on the emails waiting to be sent, implemented by
+// OutboxStore (backed by the database) and MemoryOutboxStore (for tests). An email is due once
+// its next_attempt_at has passed, and is no longer due once it's been sent or given up on.
+type Store interface {
+	QueueEmail(ctx context.Context, params db.QueueEmailParams) (db.Outbox, error)
+	GetDueEmails(ctx context.Context, now time.Time, maxResults int64) ([]db.Outbox, error)
+	GetOutbox(ctx context.Context, maxResults int64) ([]db.Outbox, error)
+	SetEmailSent(ctx context.Context, id int64, sentAt time.Time) error
+	SetEmailFailed(ctx context.Context, params db.SetEmailFailedParams) error
+}
+
+var _ Store = (*OutboxStore)(nil)
+var _ Store = (*MemoryOutboxStore)(nil)
+
+// How many times sending an email is tried before it's given up on
+const MaxAttempts = 8
+
+// How long to wait before trying again after the first failure, which doubles after each one
+// after that up to MaxBackoff
+const (
+	FirstBackoff = time.Minute
+	MaxBackoff   = 6 * time.Hour
+)
+
+func validateEmail(params db.QueueEmailParams) error {
+	if strings.TrimSpace(params.ToAddress) == "" {
+		return store.ErrMissingField{Field: "to-address"}
+	}
+	if strings.TrimSpace(params.Subject) == "" {
+		return store.ErrMissingField{Field: "subject"}
+	}
+	return nil
+}
+
+// Emails are sent as soon as possible unless they're queued for later
+func normalizeEmail(params db.QueueEmailParams) db.QueueEmailParams {
+	if !params.NextAttemptAt.Valid {
+		params.NextAttemptAt = sql.NullTime{Valid: true, Time: store.Now()}
+	}
+	params.NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+	return params
+}
+
+// How long to wait after the given number of failed attempts before trying again
+func Backoff(attempts int64) time.Duration {
+	backoff := FirstBackoff
+	for i := int64(1); i < attempts && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, MaxBackoff)
+}
+
+// When to try sending the email again after another failed attempt at the time, or NULL to give
+// up on it if that was the last one
+func Retry(email db.Outbox, now time.Time) sql.NullTime {
+	attempts := email.Attempts + 1
+	if attempts >= MaxAttempts {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Valid: true, Time: now.Add(Backoff(attempts))}
+}
@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which returns the same emails in the same order
+// as OutboxStore
+type MemoryOutboxStore struct {
+	mu     sync.Mutex
+	lastId int64
+	emails []db.Outbox
+}
+
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{}
+}
+
+func (ob *MemoryOutboxStore) QueueEmail(ctx context.Context, params db.QueueEmailParams) (db.Outbox, error) {
+	if err := validateEmail(params); err != nil {
+		return db.Outbox{}, err
+	}
+	params = normalizeEmail(params)
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.lastId++
+	email := db.Outbox{
+		ID:            ob.lastId,
+		ToAddress:     params.ToAddress,
+		Subject:       params.Subject,
+		TextBody:      params.TextBody,
+		HtmlBody:      params.HtmlBody,
+		NextAttemptAt: params.NextAttemptAt,
+		CreatedAt:     store.Now(),
+	}
+	ob.emails = append(ob.emails, email)
+	return email, nil
+}
+
+func (ob *MemoryOutboxStore) GetDueEmails(ctx context.Context, now time.Time, maxResults int64) ([]db.Outbox, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	due := []db.Outbox{}
+	for _, e := range ob.emails {
+		if e.NextAttemptAt.Valid && !e.NextAttemptAt.Time.After(now) {
+			due = append(due, e)
+		}
+	}
+	slices.SortFunc(due, func(a, b db.Outbox) int {
+		return cmp.Or(a.NextAttemptAt.Time.Compare(b.NextAttemptAt.Time), cmp.Compare(a.ID, b.ID))
+	})
+	return due[:min(int64(len(due)), maxResults)], nil
+}
+
+func (ob *MemoryOutboxStore) GetOutbox(ctx context.Context, maxResults int64) ([]db.Outbox, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	emails := slices.Clone(ob.emails)
+	slices.Reverse(emails)
+	return emails[:min(int64(len(emails)), maxResults)], nil
+}
+
+// The index of the email with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ob *MemoryOutboxStore) find(id int64) int {
+	return slices.IndexFunc(ob.emails, func(e db.Outbox) bool { return e.ID == id })
+}
+
+func (ob *MemoryOutboxStore) SetEmailSent(ctx context.Context, id int64, sentAt time.Time) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if i := ob.find(id); i >= 0 {
+		ob.emails[i].Attempts++
+		ob.emails[i].SentAt = sql.NullTime{Valid: true, Time: sentAt.UTC().Truncate(time.Second)}
+		ob.emails[i].NextAttemptAt = sql.NullTime{}
+		ob.emails[i].LastError = ""
+	}
+	return nil
+}
+
+func (ob *MemoryOutboxStore) SetEmailFailed(ctx context.Context, params db.SetEmailFailedParams) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if i := ob.find(params.ID); i >= 0 {
+		ob.emails[i].Attempts++
+		ob.emails[i].LastError = params.LastError
+		ob.emails[i].NextAttemptAt = params.NextAttemptAt
+		if params.NextAttemptAt.Valid {
+			ob.emails[i].NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type OutboxStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewOutboxStore(queries db.Querier, logger *log.Logger) *OutboxStore {
+	return &OutboxStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ob *OutboxStore) QueueEmail(ctx context.Context, params db.QueueEmailParams) (db.Outbox, error) {
+	if err := validateEmail(params); err != nil {
+		return db.Outbox{}, err
+	}
+
+	email, err := ob.queries.QueueEmail(ctx, normalizeEmail(params))
+	if err != nil {
+		ob.logger.Printf("error queueing email: %v", err)
+		return db.Outbox{}, err
+	}
+
+	// Just who it's to and what it's about, as the bodies can have password reset links in them
+	ob.logger.Printf("email queued: %d to %s: %s", email.ID, email.ToAddress, email.Subject)
+	return email, nil
+}
+
+// The emails which should be tried now, the ones which have waited longest first
+func (ob *OutboxStore) GetDueEmails(ctx context.Context, now time.Time, maxResults int64) ([]db.Outbox, error) {
+	emails, err := ob.queries.GetDueEmails(ctx, db.GetDueEmailsParams{
+		Now:        sql.NullTime{Valid: true, Time: now.UTC()},
+		MaxResults: maxResults,
+	})
+	if err != nil {
+		ob.logger.Printf("error getting due emails: %v", err)
+		return nil, err
+	}
+	return emails, nil
+}
+
+// The latest emails, whether they've been sent or not
+func (ob *OutboxStore) GetOutbox(ctx context.Context, maxResults int64) ([]db.Outbox, error) {
+	emails, err := ob.queries.GetOutbox(ctx, maxResults)
+	if err != nil {
+		ob.logger.Printf("error getting outbox: %v", err)
+		return nil, err
+	}
+	return emails, nil
+}
+
+func (ob *OutboxStore) SetEmailSent(ctx context.Context, id int64, sentAt time.Time) error {
+	err := ob.queries.SetEmailSent(ctx, db.SetEmailSentParams{
+		SentAt: sql.NullTime{Valid: true, Time: sentAt.UTC().Truncate(time.Second)},
+		ID:     id,
+	})
+	if err != nil {
+		ob.logger.Printf("error setting email sent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (ob *OutboxStore) SetEmailFailed(ctx context.Context, params db.SetEmailFailedParams) error {
+	params.NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+	if err := ob.queries.SetEmailFailed(ctx, params); err != nil {
+		ob.logger.Printf("error setting email failed: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"beer_oclock/internal/db"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int64
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{9, 256 * time.Minute},
+		// Capped rather than waiting for days
+		{10, MaxBackoff},
+		{100, MaxBackoff},
+	} {
+		if got := Backoff(tc.attempts); got != tc.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+
+	retry := Retry(db.Outbox{Attempts: 0}, now)
+	if !retry.Valid || !retry.Time.Equal(now.Add(time.Minute)) {
+		t.Errorf("after the first failure got %v, want a retry at %v", retry, now.Add(time.Minute))
+	}
+
+	retry = Retry(db.Outbox{Attempts: MaxAttempts - 2}, now)
+	if want := now.Add(Backoff(MaxAttempts - 1)); !retry.Valid || !retry.Time.Equal(want) {
+		t.Errorf("before the last attempt got %v, want a retry at %v", retry, want)
+	}
+
+	if retry := Retry(db.Outbox{Attempts: MaxAttempts - 1}, now); retry.Valid {
+		t.Errorf("after the last attempt got a retry at %v, want it given up on", retry.Time)
+	}
+}
@@ -0,0 +1,20 @@
+package photos
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+)
+
+// The operations the rest of the app needs on the records of label photos, implemented by
+// PhotoStore (backed by the database) and MemoryPhotoStore (for tests). The images themselves are
+// kept in a blobs.Store.
+type Store interface {
+	AddPhoto(ctx context.Context, params db.AddLabelPhotoParams) (db.LabelPhoto, error)
+	GetPhoto(ctx context.Context, id int64) (db.LabelPhoto, error)
+	GetBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error)
+	DeletePhoto(ctx context.Context, id int64) (db.LabelPhoto, error)
+	DeleteBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error)
+}
+
+var _ Store = (*PhotoStore)(nil)
+var _ Store = (*MemoryPhotoStore)(nil)
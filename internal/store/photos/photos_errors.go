@@ -0,0 +1,11 @@
+package photos
+
+import "fmt"
+
+type ErrPhotoNotFound struct {
+	ID int64
+}
+
+func (e ErrPhotoNotFound) Error() string {
+	return fmt.Sprintf("photo with id %d not found", e.ID)
+}
@@ -0,0 +1,97 @@
+package photos
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as PhotoStore. The beer store stands in for the foreign key.
+type MemoryPhotoStore struct {
+	mu        sync.Mutex
+	beerStore beers.Store
+	lastId    int64
+	photos    []db.LabelPhoto
+}
+
+func NewMemoryPhotoStore(beerStore beers.Store) *MemoryPhotoStore {
+	return &MemoryPhotoStore{
+		beerStore: beerStore,
+	}
+}
+
+func (ps *MemoryPhotoStore) AddPhoto(ctx context.Context, params db.AddLabelPhotoParams) (db.LabelPhoto, error) {
+	if _, err := ps.beerStore.GetBeer(ctx, params.BeerID); err != nil {
+		return db.LabelPhoto{}, beers.ErrBeerNotFound{ID: params.BeerID}
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.lastId++
+	photo := db.LabelPhoto{
+		ID:          ps.lastId,
+		BeerID:      params.BeerID,
+		UserID:      params.UserID,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		Width:       params.Width,
+		Height:      params.Height,
+		BlobKey:     params.BlobKey,
+		ThumbKey:    params.ThumbKey,
+		CreatedAt:   store.Now(),
+	}
+	ps.photos = append(ps.photos, photo)
+	return photo, nil
+}
+
+func (ps *MemoryPhotoStore) GetPhoto(ctx context.Context, id int64) (db.LabelPhoto, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	i := slices.IndexFunc(ps.photos, func(p db.LabelPhoto) bool { return p.ID == id })
+	if i < 0 {
+		return db.LabelPhoto{}, ErrPhotoNotFound{ID: id}
+	}
+	return ps.photos[i], nil
+}
+
+func (ps *MemoryPhotoStore) GetBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	photos := []db.LabelPhoto{}
+	for _, p := range ps.photos {
+		if p.BeerID == beerId {
+			photos = append(photos, p)
+		}
+	}
+	return photos, nil
+}
+
+func (ps *MemoryPhotoStore) DeletePhoto(ctx context.Context, id int64) (db.LabelPhoto, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	i := slices.IndexFunc(ps.photos, func(p db.LabelPhoto) bool { return p.ID == id })
+	if i < 0 {
+		return db.LabelPhoto{}, ErrPhotoNotFound{ID: id}
+	}
+	photo := ps.photos[i]
+	ps.photos = slices.Delete(ps.photos, i, i+1)
+	return photo, nil
+}
+
+func (ps *MemoryPhotoStore) DeleteBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error) {
+	photos, _ := ps.GetBeerPhotos(ctx, beerId)
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.photos = slices.DeleteFunc(ps.photos, func(p db.LabelPhoto) bool { return p.BeerID == beerId })
+	return photos, nil
+}
@@ -0,0 +1,84 @@
+package photos
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type PhotoStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewPhotoStore(queries db.Querier, logger *log.Logger) *PhotoStore {
+	return &PhotoStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ps *PhotoStore) AddPhoto(ctx context.Context, params db.AddLabelPhotoParams) (db.LabelPhoto, error) {
+	photo, err := ps.queries.AddLabelPhoto(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.LabelPhoto{}, beers.ErrBeerNotFound{ID: params.BeerID}
+		}
+		ps.logger.Printf("error adding photo: %v", err)
+		return db.LabelPhoto{}, err
+	}
+
+	ps.logger.Printf("photo added: %v", photo)
+	return photo, nil
+}
+
+func (ps *PhotoStore) GetPhoto(ctx context.Context, id int64) (db.LabelPhoto, error) {
+	photo, err := ps.queries.GetLabelPhoto(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.LabelPhoto{}, ErrPhotoNotFound{ID: id}
+		}
+		ps.logger.Printf("error getting photo: %v", err)
+		return db.LabelPhoto{}, err
+	}
+	return photo, nil
+}
+
+func (ps *PhotoStore) GetBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error) {
+	photos, err := ps.queries.GetBeerLabelPhotos(ctx, beerId)
+	if err != nil {
+		ps.logger.Printf("error getting beer photos: %v", err)
+		return nil, err
+	}
+	return photos, nil
+}
+
+// Deletes the record of the photo, returning it so its blobs can be deleted too
+func (ps *PhotoStore) DeletePhoto(ctx context.Context, id int64) (db.LabelPhoto, error) {
+	photo, err := ps.queries.DeleteLabelPhoto(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.LabelPhoto{}, ErrPhotoNotFound{ID: id}
+		}
+		ps.logger.Printf("error deleting photo: %v", err)
+		return db.LabelPhoto{}, err
+	}
+
+	ps.logger.Printf("photo deleted: %v", photo)
+	return photo, nil
+}
+
+// Deletes the records of all the beer's photos, returning them so their blobs can be deleted too
+func (ps *PhotoStore) DeleteBeerPhotos(ctx context.Context, beerId int64) ([]db.LabelPhoto, error) {
+	photos, err := ps.queries.DeleteBeerLabelPhotos(ctx, beerId)
+	if err != nil {
+		ps.logger.Printf("error deleting beer photos: %v", err)
+		return nil, err
+	}
+
+	ps.logger.Printf("%d photos of beer %d deleted", len(photos), beerId)
+	return photos, nil
+}
@@ -0,0 +1,50 @@
+package resets
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// The operations the rest of the app needs on password reset links, implemented by ResetStore
+// (backed by the database) and MemoryResetStore (for tests). Only the hash of each link's token
+// is stored, see NewToken.
+type Store interface {
+	AddPasswordReset(ctx context.Context, params db.AddPasswordResetParams) (db.PasswordReset, error)
+	GetPasswordReset(ctx context.Context, tokenHash string) (db.PasswordReset, error)
+	UsePasswordReset(ctx context.Context, tokenHash string, usedAt time.Time) error
+}
+
+var _ Store = (*ResetStore)(nil)
+var _ Store = (*MemoryResetStore)(nil)
+
+// How long a link works for after it's sent
+const Lifetime = time.Hour
+
+// How many random bytes are in a token, enough that they can't be guessed
+const tokenBytes = 32
+
+// Makes a token for a new link, and the hash of it to store. The token only goes in the link.
+func NewToken() (string, string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// The hash of a token from a link, for looking up the reset it's for
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Whether the link can still be used to reset the password at the time
+func Usable(reset db.PasswordReset, now time.Time) bool {
+	return !reset.UsedAt.Valid && now.Before(reset.ExpiresAt)
+}
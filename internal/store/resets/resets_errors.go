@@ -0,0 +1,13 @@
+package resets
+
+type ErrPasswordResetNotFound struct{}
+
+func (e ErrPasswordResetNotFound) Error() string {
+	return "password reset not found"
+}
+
+type ErrPasswordResetUsed struct{}
+
+func (e ErrPasswordResetUsed) Error() string {
+	return "password reset has already been used"
+}
@@ -0,0 +1,71 @@
+package resets
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as ResetStore. The user store stands in for the foreign key.
+type MemoryResetStore struct {
+	mu        sync.Mutex
+	userStore users.Store
+	resets    map[string]db.PasswordReset
+}
+
+func NewMemoryResetStore(userStore users.Store) *MemoryResetStore {
+	return &MemoryResetStore{
+		userStore: userStore,
+		resets:    make(map[string]db.PasswordReset),
+	}
+}
+
+func (rs *MemoryResetStore) AddPasswordReset(ctx context.Context, params db.AddPasswordResetParams) (db.PasswordReset, error) {
+	if _, err := rs.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.PasswordReset{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	reset := db.PasswordReset{
+		TokenHash: params.TokenHash,
+		UserID:    params.UserID,
+		ExpiresAt: params.ExpiresAt.UTC(),
+		CreatedAt: store.Now(),
+	}
+	rs.resets[params.TokenHash] = reset
+	return reset, nil
+}
+
+func (rs *MemoryResetStore) GetPasswordReset(ctx context.Context, tokenHash string) (db.PasswordReset, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	reset, ok := rs.resets[tokenHash]
+	if !ok {
+		return db.PasswordReset{}, ErrPasswordResetNotFound{}
+	}
+	return reset, nil
+}
+
+func (rs *MemoryResetStore) UsePasswordReset(ctx context.Context, tokenHash string, usedAt time.Time) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	reset, ok := rs.resets[tokenHash]
+	if !ok {
+		return ErrPasswordResetNotFound{}
+	}
+	if reset.UsedAt.Valid {
+		return ErrPasswordResetUsed{}
+	}
+	reset.UsedAt = sql.NullTime{Valid: true, Time: usedAt.UTC()}
+	rs.resets[tokenHash] = reset
+	return nil
+}
@@ -0,0 +1,70 @@
+package resets
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type ResetStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewResetStore(queries db.Querier, logger *log.Logger) *ResetStore {
+	return &ResetStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (rs *ResetStore) AddPasswordReset(ctx context.Context, params db.AddPasswordResetParams) (db.PasswordReset, error) {
+	params.ExpiresAt = params.ExpiresAt.UTC()
+	reset, err := rs.queries.AddPasswordReset(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.PasswordReset{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		rs.logger.Printf("error adding password reset: %v", err)
+		return db.PasswordReset{}, err
+	}
+
+	// The token hash isn't logged, as little as it would help anyone
+	rs.logger.Printf("password reset added for user with id %d", reset.UserID)
+	return reset, nil
+}
+
+func (rs *ResetStore) GetPasswordReset(ctx context.Context, tokenHash string) (db.PasswordReset, error) {
+	reset, err := rs.queries.GetPasswordReset(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.PasswordReset{}, ErrPasswordResetNotFound{}
+		}
+		rs.logger.Printf("error getting password reset: %v", err)
+		return db.PasswordReset{}, err
+	}
+	return reset, nil
+}
+
+// Marks the link as used, failing if it already was, so each link resets the password only once
+func (rs *ResetStore) UsePasswordReset(ctx context.Context, tokenHash string, usedAt time.Time) error {
+	count, err := rs.queries.UsePasswordReset(ctx, db.UsePasswordResetParams{
+		UsedAt:    sql.NullTime{Valid: true, Time: usedAt.UTC()},
+		TokenHash: tokenHash,
+	})
+	if err != nil {
+		rs.logger.Printf("error using password reset: %v", err)
+		return err
+	}
+	if count == 0 {
+		if _, err := rs.GetPasswordReset(ctx, tokenHash); err != nil {
+			return err
+		}
+		return ErrPasswordResetUsed{}
+	}
+	return nil
+}
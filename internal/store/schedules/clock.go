@@ -0,0 +1,63 @@
+package schedules
+
+import (
+	"beer_oclock/internal/db"
+	"time"
+)
+
+// How long beer o'clock lasts once it starts. Reminders which are running late, such as when the
+// server was down, still go out until it's over.
+const Length = time.Hour
+
+// When the schedule next comes around at or after now, in now's location. Where daylight saving
+// skips the time it's moved on by the gap, so 02:30 is 03:30 when the clocks go forward an hour.
+func Next(schedule db.Schedule, now time.Time) time.Time {
+	days := (int(schedule.Weekday) - int(now.Weekday()) + 7) % 7
+	next := at(schedule, now, days)
+	if next.Before(now) {
+		next = at(schedule, now, days+7)
+	}
+	return next
+}
+
+// The schedule's time on the day days after now
+func at(schedule db.Schedule, now time.Time, days int) time.Time {
+	hour, minute := int(schedule.Minute)/60, int(schedule.Minute)%60
+	t := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, now.Location())
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+
+	// The time was skipped, and time.Date doesn't say which side of the gap it picks, so it's
+	// read with the offset from before the clocks went forward
+	_, offset := t.Add(-2 * time.Hour).Zone()
+	wall := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, time.UTC)
+	return wall.Add(-time.Duration(offset) * time.Second).In(now.Location())
+}
+
+// The soonest beer o'clock, counting one which is still going at now, or false if there's no
+// schedule
+func Soonest(schedules []db.Schedule, now time.Time) (time.Time, bool) {
+	var soonest time.Time
+	for _, schedule := range schedules {
+		next := Next(schedule, now.Add(-Length))
+		if soonest.IsZero() || next.Before(soonest) {
+			soonest = next
+		}
+	}
+	return soonest, !soonest.IsZero()
+}
+
+// The beer o'clock a reminder is due for at now, if one is: its reminder time has come, it hasn't
+// been reminded about already, and it's not over yet. Now should be in the user's location.
+func Due(schedule db.Schedule, now time.Time) (time.Time, bool) {
+	next := Next(schedule, now.Add(-Length))
+	remindAt := next.Add(-time.Duration(schedule.RemindBefore) * time.Minute)
+	if remindAt.After(now) {
+		return next, false
+	}
+	if schedule.RemindedAt.Valid && !schedule.RemindedAt.Time.Before(remindAt) {
+		return next, false
+	}
+	return next, true
+}
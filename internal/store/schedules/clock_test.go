@@ -0,0 +1,100 @@
+package schedules
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// Friday at 17:00
+var friday = db.Schedule{Weekday: int64(time.Friday), Minute: 17 * 60}
+
+func TestNext(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sunday := db.Schedule{Weekday: int64(time.Sunday), Minute: 2*60 + 30}
+
+	for _, tc := range []struct {
+		name     string
+		schedule db.Schedule
+		now      time.Time
+		want     time.Time
+	}{
+		{"earlier in the week", friday, time.Date(2025, time.March, 11, 9, 0, 0, 0, time.UTC), time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)},
+		{"earlier on the day", friday, time.Date(2025, time.March, 14, 16, 59, 0, 0, time.UTC), time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)},
+		{"right on time", friday, time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC), time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)},
+		{"just missed it", friday, time.Date(2025, time.March, 14, 17, 0, 1, 0, time.UTC), time.Date(2025, time.March, 21, 17, 0, 0, 0, time.UTC)},
+		{"across the end of the year", friday, time.Date(2025, time.December, 29, 12, 0, 0, 0, time.UTC), time.Date(2026, time.January, 2, 17, 0, 0, 0, time.UTC)},
+		// In the user's own time, which is a different day in UTC
+		{"ahead of UTC", friday, time.Date(2025, time.March, 14, 4, 0, 0, 0, time.UTC).In(sydney), time.Date(2025, time.March, 14, 17, 0, 0, 0, sydney)},
+		// 02:30 didn't happen when New York's clocks went forward, so it's 03:30 instead
+		{"skipped by daylight saving", sunday, time.Date(2025, time.March, 8, 12, 0, 0, 0, newYork), time.Date(2025, time.March, 9, 3, 30, 0, 0, newYork)},
+		// Sydney's clocks went back from 03:00 to 02:00, so 02:30 happened twice
+		{"repeated by daylight saving", sunday, time.Date(2025, time.April, 5, 12, 0, 0, 0, sydney), time.Date(2025, time.April, 6, 2, 30, 0, 0, sydney)},
+	} {
+		if got := Next(tc.schedule, tc.now); !got.Equal(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSoonest(t *testing.T) {
+	wednesday := db.Schedule{Weekday: int64(time.Wednesday), Minute: 18 * 60}
+	schedules := []db.Schedule{friday, wednesday}
+
+	if _, ok := Soonest(nil, time.Now()); ok {
+		t.Errorf("soonest without schedules: should be none")
+	}
+	for _, tc := range []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC), time.Date(2025, time.March, 12, 18, 0, 0, 0, time.UTC)},
+		{time.Date(2025, time.March, 13, 9, 0, 0, 0, time.UTC), time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)},
+		// Still going until it's been on for Length
+		{time.Date(2025, time.March, 14, 17, 59, 0, 0, time.UTC), time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)},
+		{time.Date(2025, time.March, 14, 18, 1, 0, 0, time.UTC), time.Date(2025, time.March, 19, 18, 0, 0, 0, time.UTC)},
+	} {
+		if got, ok := Soonest(schedules, tc.now); !ok || !got.Equal(tc.want) {
+			t.Errorf("soonest at %v: got %v, want %v", tc.now, got, tc.want)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	remindedAt := func(t time.Time) sql.NullTime { return sql.NullTime{Valid: true, Time: t} }
+	early := friday
+	early.RemindBefore = 30
+	dayBefore := friday
+	dayBefore.RemindBefore = MaxRemindBefore
+	reminded := early
+	reminded.RemindedAt = remindedAt(time.Date(2025, time.March, 14, 16, 30, 0, 0, time.UTC))
+	remindedLastWeek := early
+	remindedLastWeek.RemindedAt = remindedAt(time.Date(2025, time.March, 7, 16, 30, 0, 0, time.UTC))
+
+	for _, tc := range []struct {
+		name     string
+		schedule db.Schedule
+		now      time.Time
+		want     bool
+	}{
+		{"too early", early, time.Date(2025, time.March, 14, 16, 29, 0, 0, time.UTC), false},
+		{"in time", early, time.Date(2025, time.March, 14, 16, 30, 0, 0, time.UTC), true},
+		{"running late", friday, time.Date(2025, time.March, 14, 17, 45, 0, 0, time.UTC), true},
+		{"over", friday, time.Date(2025, time.March, 14, 18, 1, 0, 0, time.UTC), false},
+		{"a day ahead", dayBefore, time.Date(2025, time.March, 13, 17, 0, 0, 0, time.UTC), true},
+		{"already reminded", reminded, time.Date(2025, time.March, 14, 16, 45, 0, 0, time.UTC), false},
+		{"reminded last week", remindedLastWeek, time.Date(2025, time.March, 14, 16, 45, 0, 0, time.UTC), true},
+	} {
+		if _, got := Due(tc.schedule, tc.now); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
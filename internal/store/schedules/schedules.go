@@ -0,0 +1,39 @@
+package schedules
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"time"
+)
+
+// The operations the rest of the app needs on users' beer o'clock schedules, implemented by
+// ScheduleStore (backed by the database) and MemoryScheduleStore (for tests)
+type Store interface {
+	AddSchedule(ctx context.Context, params db.AddScheduleParams) (db.Schedule, error)
+	GetSchedule(ctx context.Context, id int64) (db.Schedule, error)
+	GetUserSchedules(ctx context.Context, userId int64) ([]db.Schedule, error)
+	// Every schedule of the users who aren't deleted, for sending reminders
+	GetAllSchedules(ctx context.Context) ([]db.GetAllSchedulesRow, error)
+	SetScheduleReminded(ctx context.Context, id int64, remindedAt time.Time) error
+	DeleteSchedule(ctx context.Context, id int64) (db.Schedule, error)
+}
+
+var _ Store = (*ScheduleStore)(nil)
+var _ Store = (*MemoryScheduleStore)(nil)
+
+// The most minutes ahead a reminder can be sent, a whole day
+const MaxRemindBefore = 24 * 60
+
+func validateSchedule(params db.AddScheduleParams) error {
+	if params.Weekday < int64(time.Sunday) || params.Weekday > int64(time.Saturday) {
+		return store.ErrInvalidField{Field: "weekday", Reason: "must be a day of the week"}
+	}
+	if params.Minute < 0 || params.Minute >= 24*60 {
+		return store.ErrInvalidField{Field: "time", Reason: "must be a time of day"}
+	}
+	if params.RemindBefore < 0 || params.RemindBefore > MaxRemindBefore {
+		return store.ErrInvalidField{Field: "remind-before", Reason: "must be between 0 and 1440 minutes"}
+	}
+	return nil
+}
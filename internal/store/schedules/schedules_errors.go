@@ -0,0 +1,23 @@
+package schedules
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrScheduleNotFound struct {
+	ID int64
+}
+
+func (e ErrScheduleNotFound) Error() string {
+	return fmt.Sprintf("schedule with id %d not found", e.ID)
+}
+
+type ErrScheduleAlreadyExists struct {
+	Weekday time.Weekday
+	Minute  int64
+}
+
+func (e ErrScheduleAlreadyExists) Error() string {
+	return fmt.Sprintf("beer o'clock is already at %02d:%02d on %s", e.Minute/60, e.Minute%60, e.Weekday)
+}
@@ -0,0 +1,147 @@
+package schedules
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as ScheduleStore. The user store stands in for the foreign key, and it and the
+// goal store for the join to find where everyone is.
+type MemoryScheduleStore struct {
+	mu        sync.Mutex
+	userStore users.Store
+	goalStore goals.Store
+	lastId    int64
+	schedules []db.Schedule
+}
+
+func NewMemoryScheduleStore(userStore users.Store, goalStore goals.Store) *MemoryScheduleStore {
+	return &MemoryScheduleStore{
+		userStore: userStore,
+		goalStore: goalStore,
+	}
+}
+
+func (ss *MemoryScheduleStore) AddSchedule(ctx context.Context, params db.AddScheduleParams) (db.Schedule, error) {
+	if err := validateSchedule(params); err != nil {
+		return db.Schedule{}, err
+	}
+	if _, err := ss.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.Schedule{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, schedule := range ss.schedules {
+		if schedule.UserID == params.UserID && schedule.Weekday == params.Weekday && schedule.Minute == params.Minute {
+			return db.Schedule{}, ErrScheduleAlreadyExists{Weekday: time.Weekday(params.Weekday), Minute: params.Minute}
+		}
+	}
+
+	ss.lastId++
+	schedule := db.Schedule{
+		ID:           ss.lastId,
+		UserID:       params.UserID,
+		Weekday:      params.Weekday,
+		Minute:       params.Minute,
+		RemindBefore: params.RemindBefore,
+		CreatedAt:    store.Now(),
+	}
+	ss.schedules = append(ss.schedules, schedule)
+	return schedule, nil
+}
+
+func (ss *MemoryScheduleStore) GetSchedule(ctx context.Context, id int64) (db.Schedule, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, schedule := range ss.schedules {
+		if schedule.ID == id {
+			return schedule, nil
+		}
+	}
+	return db.Schedule{}, ErrScheduleNotFound{ID: id}
+}
+
+func (ss *MemoryScheduleStore) GetUserSchedules(ctx context.Context, userId int64) ([]db.Schedule, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	schedules := []db.Schedule{}
+	for _, schedule := range ss.schedules {
+		if schedule.UserID == userId {
+			schedules = append(schedules, schedule)
+		}
+	}
+	slices.SortStableFunc(schedules, func(a, b db.Schedule) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.Minute, b.Minute))
+	})
+	return schedules, nil
+}
+
+func (ss *MemoryScheduleStore) GetAllSchedules(ctx context.Context) ([]db.GetAllSchedulesRow, error) {
+	allUsers, err := ss.userStore.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usernames := map[int64]string{}
+	timeZones := map[int64]string{}
+	for _, user := range allUsers {
+		usernames[user.ID] = user.Username
+		userGoals, err := ss.goalStore.GetGoals(ctx, user.ID)
+		if err == nil {
+			timeZones[user.ID] = userGoals.TimeZone
+		} else if _, ok := err.(goals.ErrGoalsNotFound); !ok {
+			return nil, err
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	rows := []db.GetAllSchedulesRow{}
+	for _, schedule := range ss.schedules {
+		username, ok := usernames[schedule.UserID]
+		if !ok {
+			continue
+		}
+		rows = append(rows, db.GetAllSchedulesRow{Schedule: schedule, Username: username, TimeZone: timeZones[schedule.UserID]})
+	}
+	return rows, nil
+}
+
+func (ss *MemoryScheduleStore) SetScheduleReminded(ctx context.Context, id int64, remindedAt time.Time) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	// Like an UPDATE, a schedule which isn't there isn't an error
+	for i, schedule := range ss.schedules {
+		if schedule.ID == id {
+			ss.schedules[i].RemindedAt = sql.NullTime{Valid: true, Time: remindedAt.UTC()}
+		}
+	}
+	return nil
+}
+
+func (ss *MemoryScheduleStore) DeleteSchedule(ctx context.Context, id int64) (db.Schedule, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for i, schedule := range ss.schedules {
+		if schedule.ID == id {
+			ss.schedules = slices.Delete(ss.schedules, i, i+1)
+			return schedule, nil
+		}
+	}
+	return db.Schedule{}, ErrScheduleNotFound{ID: id}
+}
@@ -0,0 +1,102 @@
+package schedules
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type ScheduleStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewScheduleStore(queries db.Querier, logger *log.Logger) *ScheduleStore {
+	return &ScheduleStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ss *ScheduleStore) AddSchedule(ctx context.Context, params db.AddScheduleParams) (db.Schedule, error) {
+	if err := validateSchedule(params); err != nil {
+		return db.Schedule{}, err
+	}
+
+	schedule, err := ss.queries.AddSchedule(ctx, params)
+	if err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.UniqueConstraint:
+			return db.Schedule{}, ErrScheduleAlreadyExists{Weekday: time.Weekday(params.Weekday), Minute: params.Minute}
+		case store.ForeignKeyConstraint:
+			return db.Schedule{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		ss.logger.Printf("error adding schedule: %v", err)
+		return db.Schedule{}, err
+	}
+
+	ss.logger.Printf("schedule added: %v", schedule)
+	return schedule, nil
+}
+
+func (ss *ScheduleStore) GetSchedule(ctx context.Context, id int64) (db.Schedule, error) {
+	schedule, err := ss.queries.GetSchedule(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Schedule{}, ErrScheduleNotFound{ID: id}
+		}
+		ss.logger.Printf("error getting schedule: %v", err)
+		return db.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// The user's schedule through the week, starting on Sunday
+func (ss *ScheduleStore) GetUserSchedules(ctx context.Context, userId int64) ([]db.Schedule, error) {
+	schedules, err := ss.queries.GetUserSchedules(ctx, userId)
+	if err != nil {
+		ss.logger.Printf("error getting user schedules: %v", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (ss *ScheduleStore) GetAllSchedules(ctx context.Context) ([]db.GetAllSchedulesRow, error) {
+	schedules, err := ss.queries.GetAllSchedules(ctx)
+	if err != nil {
+		ss.logger.Printf("error getting all schedules: %v", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Records that a reminder for the schedule went out
+func (ss *ScheduleStore) SetScheduleReminded(ctx context.Context, id int64, remindedAt time.Time) error {
+	err := ss.queries.SetScheduleReminded(ctx, db.SetScheduleRemindedParams{
+		ID:         id,
+		RemindedAt: sql.NullTime{Valid: true, Time: remindedAt.UTC()},
+	})
+	if err != nil {
+		ss.logger.Printf("error setting schedule reminded: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (ss *ScheduleStore) DeleteSchedule(ctx context.Context, id int64) (db.Schedule, error) {
+	schedule, err := ss.queries.DeleteSchedule(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Schedule{}, ErrScheduleNotFound{ID: id}
+		}
+		ss.logger.Printf("error deleting schedule: %v", err)
+		return db.Schedule{}, err
+	}
+
+	ss.logger.Printf("schedule deleted: %v", schedule)
+	return schedule, nil
+}
@@ -0,0 +1,127 @@
+package scorecards
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+)
+
+// The operations the rest of the app needs on scorecards, implemented by ScorecardStore (backed by
+// the database) and MemoryScorecardStore (for tests)
+type Store interface {
+	GetWeights(ctx context.Context) (Scores, error)
+	SetWeights(ctx context.Context, weights Scores) (Scores, error)
+	SaveScorecard(ctx context.Context, beerId int64, userId int64, scores Scores) (db.Scorecard, error)
+	GetScorecard(ctx context.Context, beerId int64, userId int64) (db.Scorecard, error)
+	GetBeerScorecards(ctx context.Context, beerId int64) ([]db.GetBeerScorecardsRow, error)
+	GetBeerSummary(ctx context.Context, beerId int64) (Summary, error)
+}
+
+var _ Store = (*ScorecardStore)(nil)
+var _ Store = (*MemoryScorecardStore)(nil)
+
+// The parts of a beer which are scored, in the order they're shown
+const (
+	Aroma = iota
+	Appearance
+	Flavour
+	Mouthfeel
+	Overall
+	NumDimensions
+)
+
+type Dimension struct {
+	// The form field for the dimension
+	Name  string
+	Label string
+}
+
+var Dimensions = [NumDimensions]Dimension{
+	{Name: "aroma", Label: "Aroma"},
+	{Name: "appearance", Label: "Appearance"},
+	{Name: "flavour", Label: "Flavour"},
+	{Name: "mouthfeel", Label: "Mouthfeel"},
+	{Name: "overall", Label: "Overall"},
+}
+
+// A score out of 10 for each dimension, or how much each dimension counts towards the total
+type Scores [NumDimensions]float64
+
+// The points each part is worth on a BJCP scoresheet, out of 50
+var DefaultWeights = Scores{12, 3, 20, 5, 10}
+
+// The weighted average of the scores, which is out of 10 like each score
+func (s Scores) Total(weights Scores) float64 {
+	total, sum := 0.0, 0.0
+	for i := range s {
+		total += s[i] * weights[i]
+		sum += weights[i]
+	}
+	if sum == 0 {
+		return 0
+	}
+	return total / sum
+}
+
+// A dimension's share of the total, as a percentage
+func (s Scores) Percent(dimension int) float64 {
+	sum := 0.0
+	for _, w := range s {
+		sum += w
+	}
+	if sum == 0 {
+		return 0
+	}
+	return s[dimension] / sum * 100
+}
+
+func ScoresOf(scorecard db.Scorecard) Scores {
+	return Scores{scorecard.Aroma, scorecard.Appearance, scorecard.Flavour, scorecard.Mouthfeel, scorecard.Overall}
+}
+
+// Weights the scorecards' totals by the weights, in place, and works out the rating of each beer
+// they're for, which is the average of its totals like Summary.Total
+func reweigh(scorecards []db.Scorecard, weights Scores) map[int64]float64 {
+	sums, counts := map[int64]float64{}, map[int64]int{}
+	for i := range scorecards {
+		scorecards[i].Total = ScoresOf(scorecards[i]).Total(weights)
+		sums[scorecards[i].BeerID] += scorecards[i].Total
+		counts[scorecards[i].BeerID]++
+	}
+	ratings := make(map[int64]float64, len(sums))
+	for beerId, sum := range sums {
+		ratings[beerId] = sum / float64(counts[beerId])
+	}
+	return ratings
+}
+
+// The average scores given to a beer
+type Summary struct {
+	Count    int64
+	Averages Scores
+	// The average of the weighted totals, which is what's stored as the beer's rating
+	Total float64
+}
+
+func validateScores(scores Scores) error {
+	for i, score := range scores {
+		if score < 0 || score > 10 {
+			return store.ErrInvalidField{Field: Dimensions[i].Name, Reason: "must be between 0 and 10"}
+		}
+	}
+	return nil
+}
+
+func validateWeights(weights Scores) error {
+	sum := 0.0
+	for i, weight := range weights {
+		if weight < 0 {
+			return store.ErrInvalidField{Field: Dimensions[i].Name, Reason: "must be >= 0"}
+		}
+		sum += weight
+	}
+	if sum == 0 {
+		return store.ErrInvalidField{Field: "weights", Reason: "at least one must be more than 0"}
+	}
+	return nil
+}
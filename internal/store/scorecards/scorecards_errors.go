@@ -0,0 +1,12 @@
+package scorecards
+
+import "fmt"
+
+type ErrScorecardNotFound struct {
+	BeerID int64
+	UserID int64
+}
+
+func (e ErrScorecardNotFound) Error() string {
+	return fmt.Sprintf("scorecard for beer with id %d by user with id %d not found", e.BeerID, e.UserID)
+}
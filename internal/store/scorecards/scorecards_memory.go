@@ -0,0 +1,154 @@
+package scorecards
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as ScorecardStore. The beer and user stores stand in for the foreign keys.
+type MemoryScorecardStore struct {
+	mu         sync.Mutex
+	beerStore  beers.Store
+	userStore  users.Store
+	weights    *Scores
+	lastId     int64
+	scorecards []db.Scorecard
+}
+
+func NewMemoryScorecardStore(beerStore beers.Store, userStore users.Store) *MemoryScorecardStore {
+	return &MemoryScorecardStore{
+		beerStore: beerStore,
+		userStore: userStore,
+	}
+}
+
+func (ss *MemoryScorecardStore) GetWeights(ctx context.Context) (Scores, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.weights == nil {
+		return DefaultWeights, nil
+	}
+	return *ss.weights, nil
+}
+
+func (ss *MemoryScorecardStore) SetWeights(ctx context.Context, weights Scores) (Scores, error) {
+	if err := validateWeights(weights); err != nil {
+		return Scores{}, err
+	}
+
+	ss.mu.Lock()
+	ss.weights = &weights
+	ratings := reweigh(ss.scorecards, weights)
+	ss.mu.Unlock()
+
+	if err := ss.beerStore.SetBeerRatings(ctx, ratings); err != nil {
+		return Scores{}, err
+	}
+	return weights, nil
+}
+
+func (ss *MemoryScorecardStore) SaveScorecard(ctx context.Context, beerId int64, userId int64, scores Scores) (db.Scorecard, error) {
+	if err := validateScores(scores); err != nil {
+		return db.Scorecard{}, err
+	}
+	if _, err := ss.beerStore.GetBeer(ctx, beerId); err != nil {
+		return db.Scorecard{}, beers.ErrBeerNotFound{ID: beerId}
+	}
+	weights, _ := ss.GetWeights(ctx)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	scorecard := db.Scorecard{
+		BeerID:     beerId,
+		UserID:     userId,
+		Aroma:      scores[Aroma],
+		Appearance: scores[Appearance],
+		Flavour:    scores[Flavour],
+		Mouthfeel:  scores[Mouthfeel],
+		Overall:    scores[Overall],
+		Total:      scores.Total(weights),
+		UpdatedAt:  store.Now(),
+	}
+	scorecard.CreatedAt = scorecard.UpdatedAt
+
+	// Replace the user's existing scorecard, like the upsert in the query
+	i := slices.IndexFunc(ss.scorecards, func(s db.Scorecard) bool { return s.BeerID == beerId && s.UserID == userId })
+	if i >= 0 {
+		scorecard.ID, scorecard.CreatedAt = ss.scorecards[i].ID, ss.scorecards[i].CreatedAt
+		ss.scorecards[i] = scorecard
+		return scorecard, nil
+	}
+
+	ss.lastId++
+	scorecard.ID = ss.lastId
+	ss.scorecards = append(ss.scorecards, scorecard)
+	return scorecard, nil
+}
+
+func (ss *MemoryScorecardStore) GetScorecard(ctx context.Context, beerId int64, userId int64) (db.Scorecard, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.scorecards, func(s db.Scorecard) bool { return s.BeerID == beerId && s.UserID == userId })
+	if i < 0 {
+		return db.Scorecard{}, ErrScorecardNotFound{BeerID: beerId, UserID: userId}
+	}
+	return ss.scorecards[i], nil
+}
+
+// The beer's scorecards, in the order they were added
+func (ss *MemoryScorecardStore) beerScorecards(beerId int64) []db.Scorecard {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	scorecards := []db.Scorecard{}
+	for _, s := range ss.scorecards {
+		if s.BeerID == beerId {
+			scorecards = append(scorecards, s)
+		}
+	}
+	return scorecards
+}
+
+func (ss *MemoryScorecardStore) GetBeerScorecards(ctx context.Context, beerId int64) ([]db.GetBeerScorecardsRow, error) {
+	rows := []db.GetBeerScorecardsRow{}
+	for _, s := range ss.beerScorecards(beerId) {
+		// Join in the username the way the query does
+		user, err := ss.userStore.GetUser(ctx, s.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetBeerScorecardsRow{Scorecard: s, Username: user.Username})
+	}
+	slices.SortStableFunc(rows, func(a, b db.GetBeerScorecardsRow) int {
+		return cmp.Compare(b.Scorecard.Total, a.Scorecard.Total)
+	})
+	return rows, nil
+}
+
+func (ss *MemoryScorecardStore) GetBeerSummary(ctx context.Context, beerId int64) (Summary, error) {
+	summary := Summary{}
+	for _, s := range ss.beerScorecards(beerId) {
+		summary.Count++
+		for i, score := range ScoresOf(s) {
+			summary.Averages[i] += score
+		}
+		summary.Total += s.Total
+	}
+	if summary.Count > 0 {
+		for i := range summary.Averages {
+			summary.Averages[i] /= float64(summary.Count)
+		}
+		summary.Total /= float64(summary.Count)
+	}
+	return summary, nil
+}
@@ -0,0 +1,157 @@
+package scorecards
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+	"maps"
+	"slices"
+)
+
+type ScorecardStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewScorecardStore(queries db.Querier, logger *log.Logger) *ScorecardStore {
+	return &ScorecardStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// The household's weights, or the defaults if they haven't been set
+func (ss *ScorecardStore) GetWeights(ctx context.Context) (Scores, error) {
+	weights, err := db.Joined(ctx, ss.queries).GetScorecardWeights(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultWeights, nil
+		}
+		ss.logger.Printf("error getting scorecard weights: %v", err)
+		return Scores{}, err
+	}
+	return Scores{weights.Aroma, weights.Appearance, weights.Flavour, weights.Mouthfeel, weights.Overall}, nil
+}
+
+// Sets the weights, and weights every scorecard's total and works out every scored beer's rating
+// again with them, all in one transaction so the totals never disagree with the weights
+func (ss *ScorecardStore) SetWeights(ctx context.Context, weights Scores) (Scores, error) {
+	if err := validateWeights(weights); err != nil {
+		return Scores{}, err
+	}
+
+	err := db.InTx(ctx, ss.queries, func(ctx context.Context, queries db.Querier) error {
+		_, err := queries.SetScorecardWeights(ctx, db.SetScorecardWeightsParams{
+			Aroma:      weights[Aroma],
+			Appearance: weights[Appearance],
+			Flavour:    weights[Flavour],
+			Mouthfeel:  weights[Mouthfeel],
+			Overall:    weights[Overall],
+		})
+		if err != nil {
+			return err
+		}
+
+		scorecards, err := queries.GetScorecards(ctx)
+		if err != nil {
+			return err
+		}
+		ratings := reweigh(scorecards, weights)
+		for _, scorecard := range scorecards {
+			err := queries.SetScorecardTotal(ctx, db.SetScorecardTotalParams{ID: scorecard.ID, Total: scorecard.Total})
+			if err != nil {
+				return err
+			}
+		}
+		for _, beerId := range slices.Sorted(maps.Keys(ratings)) {
+			err := queries.SetBeerRating(ctx, db.SetBeerRatingParams{
+				ID:     beerId,
+				Rating: sql.NullFloat64{Valid: true, Float64: ratings[beerId]},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ss.logger.Printf("error setting scorecard weights: %v", err)
+		return Scores{}, err
+	}
+
+	ss.logger.Printf("scorecard weights set: %v", weights)
+	return weights, nil
+}
+
+// Saves the user's scorecard for the beer, replacing any they already had, with the total weighted
+// by the current weights
+func (ss *ScorecardStore) SaveScorecard(ctx context.Context, beerId int64, userId int64, scores Scores) (db.Scorecard, error) {
+	zero := db.Scorecard{}
+
+	if err := validateScores(scores); err != nil {
+		return zero, err
+	}
+	weights, err := ss.GetWeights(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	scorecard, err := db.Joined(ctx, ss.queries).SaveScorecard(ctx, db.SaveScorecardParams{
+		BeerID:     beerId,
+		UserID:     userId,
+		Aroma:      scores[Aroma],
+		Appearance: scores[Appearance],
+		Flavour:    scores[Flavour],
+		Mouthfeel:  scores[Mouthfeel],
+		Overall:    scores[Overall],
+		Total:      scores.Total(weights),
+	})
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return zero, beers.ErrBeerNotFound{ID: beerId}
+		}
+		ss.logger.Printf("error saving scorecard: %v", err)
+		return zero, err
+	}
+
+	ss.logger.Printf("scorecard saved: %v", scorecard)
+	return scorecard, nil
+}
+
+func (ss *ScorecardStore) GetScorecard(ctx context.Context, beerId int64, userId int64) (db.Scorecard, error) {
+	scorecard, err := db.Joined(ctx, ss.queries).GetScorecard(ctx, db.GetScorecardParams{BeerID: beerId, UserID: userId})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Scorecard{}, ErrScorecardNotFound{BeerID: beerId, UserID: userId}
+		}
+		ss.logger.Printf("error getting scorecard: %v", err)
+		return db.Scorecard{}, err
+	}
+	return scorecard, nil
+}
+
+// Everyone's scorecards for the beer, highest total first
+func (ss *ScorecardStore) GetBeerScorecards(ctx context.Context, beerId int64) ([]db.GetBeerScorecardsRow, error) {
+	scorecards, err := db.Joined(ctx, ss.queries).GetBeerScorecards(ctx, beerId)
+	if err != nil {
+		ss.logger.Printf("error getting beer scorecards: %v", err)
+		return nil, err
+	}
+	return scorecards, nil
+}
+
+func (ss *ScorecardStore) GetBeerSummary(ctx context.Context, beerId int64) (Summary, error) {
+	averages, err := db.Joined(ctx, ss.queries).GetBeerScoreAverages(ctx, beerId)
+	if err != nil {
+		ss.logger.Printf("error getting beer score averages: %v", err)
+		return Summary{}, err
+	}
+	return Summary{
+		Count:    averages.Count,
+		Averages: Scores{averages.Aroma, averages.Appearance, averages.Flavour, averages.Mouthfeel, averages.Overall},
+		Total:    averages.Total,
+	}, nil
+}
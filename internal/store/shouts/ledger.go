@@ -0,0 +1,241 @@
+package shouts
+
+import (
+	"beer_oclock/internal/db"
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Someone in the shout
+type Member struct {
+	UserID   int64
+	Username string
+}
+
+// Where a member stands: the rounds they've bought, the drinks in them they bought for others and
+// the drinks others have bought them. Their own drink in a round they bought doesn't count either
+// way.
+type Balance struct {
+	Member
+	Rounds   int
+	Shouted  int
+	Received int
+}
+
+// The drinks the member is up, or down if it's negative
+func (b Balance) Net() int {
+	return b.Shouted - b.Received
+}
+
+// One member owing another drinks, after what they've bought each other is taken off
+type Owing struct {
+	From   Member
+	To     Member
+	Drinks int
+}
+
+// A payment that squares up what the rounds cost
+type Settlement struct {
+	From     Member
+	To       Member
+	Amount   float64
+	Currency string
+}
+
+// Everyone in the shout: the participants in the order they joined, then anyone else who bought or
+// was bought a round, in the order they first did
+func Members(participants []db.GetSessionParticipantsRow, rounds []db.GetSessionRoundsRow, recipients []db.GetSessionRoundRecipientsRow) []Member {
+	members := []Member{}
+	seen := map[int64]bool{}
+	add := func(userId int64, username string) {
+		if !seen[userId] {
+			seen[userId] = true
+			members = append(members, Member{UserID: userId, Username: username})
+		}
+	}
+	for _, p := range participants {
+		add(p.UserID, p.Username)
+	}
+	byRound := RecipientsByRound(recipients)
+	for _, r := range rounds {
+		add(r.Round.BoughtBy, r.Username)
+		for _, m := range byRound[r.Round.ID] {
+			add(m.UserID, m.Username)
+		}
+	}
+	return members
+}
+
+// Who each round was bought for, by the round's id
+func RecipientsByRound(recipients []db.GetSessionRoundRecipientsRow) map[int64][]Member {
+	byRound := map[int64][]Member{}
+	for _, r := range recipients {
+		byRound[r.RoundID] = append(byRound[r.RoundID], Member{UserID: r.UserID, Username: r.Username})
+	}
+	return byRound
+}
+
+// Each member's balance, in the same order as the members
+func Balances(members []Member, rounds []db.GetSessionRoundsRow, recipients []db.GetSessionRoundRecipientsRow) []Balance {
+	balances := make([]Balance, len(members))
+	index := map[int64]int{}
+	for i, m := range members {
+		balances[i].Member = m
+		index[m.UserID] = i
+	}
+
+	byRound := RecipientsByRound(recipients)
+	for _, r := range rounds {
+		buyer, ok := index[r.Round.BoughtBy]
+		if !ok {
+			continue
+		}
+		balances[buyer].Rounds++
+		for _, m := range byRound[r.Round.ID] {
+			if i, ok := index[m.UserID]; ok && m.UserID != r.Round.BoughtBy {
+				balances[buyer].Shouted++
+				balances[i].Received++
+			}
+		}
+	}
+	return balances
+}
+
+// Who should buy the next round: whoever's furthest behind, then whoever's bought the fewest
+// rounds, then whoever joined first. There's no one if there are no members.
+func NextToBuy(balances []Balance) (Balance, bool) {
+	if len(balances) == 0 {
+		return Balance{}, false
+	}
+	// The first of those equally behind wins, since MinFunc returns the first minimum
+	return slices.MinFunc(balances, func(a, b Balance) int {
+		if c := cmp.Compare(a.Net(), b.Net()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Rounds, b.Rounds)
+	}), true
+}
+
+// How many drinks each pair of members owe each other once what they've bought each other is
+// netted off, in the order of the members. Pairs who are square are left out.
+func Owings(members []Member, rounds []db.GetSessionRoundsRow, recipients []db.GetSessionRoundRecipientsRow) []Owing {
+	index := map[int64]int{}
+	for i, m := range members {
+		index[m.UserID] = i
+	}
+	// bought[a][b] is how many drinks a bought b
+	bought := make([][]int, len(members))
+	for i := range bought {
+		bought[i] = make([]int, len(members))
+	}
+
+	byRound := RecipientsByRound(recipients)
+	for _, r := range rounds {
+		buyer, ok := index[r.Round.BoughtBy]
+		if !ok {
+			continue
+		}
+		for _, m := range byRound[r.Round.ID] {
+			if i, ok := index[m.UserID]; ok && i != buyer {
+				bought[buyer][i]++
+			}
+		}
+	}
+
+	owings := []Owing{}
+	for a := range members {
+		for b := a + 1; b < len(members); b++ {
+			switch net := bought[a][b] - bought[b][a]; {
+			case net > 0:
+				owings = append(owings, Owing{From: members[b], To: members[a], Drinks: net})
+			case net < 0:
+				owings = append(owings, Owing{From: members[a], To: members[b], Drinks: -net})
+			}
+		}
+	}
+	return owings
+}
+
+// The fewest payments that square up what the rounds with costs came to, with each round's cost
+// split evenly between who it was for. The sums are done in cents, with any cents that don't
+// split evenly going to the recipients with the lowest ids. They're grouped by currency in
+// alphabetical order, and within each the biggest debts are paid to the biggest creditors first.
+func SettleUp(members []Member, rounds []db.GetSessionRoundsRow, recipients []db.GetSessionRoundRecipientsRow) []Settlement {
+	index := map[int64]int{}
+	for i, m := range members {
+		index[m.UserID] = i
+	}
+
+	// What each member is owed in cents, or owes if it's negative, by currency
+	owed := map[string][]int64{}
+	byRound := RecipientsByRound(recipients)
+	for _, r := range rounds {
+		roundRecipients := byRound[r.Round.ID]
+		buyer, ok := index[r.Round.BoughtBy]
+		if !r.Round.Cost.Valid || len(roundRecipients) == 0 || !ok {
+			continue
+		}
+		if owed[r.Round.Currency] == nil {
+			owed[r.Round.Currency] = make([]int64, len(members))
+		}
+		cents := int64(math.Round(r.Round.Cost.Float64 * 100))
+		share, remainder := cents/int64(len(roundRecipients)), cents%int64(len(roundRecipients))
+		for j, m := range roundRecipients {
+			i, ok := index[m.UserID]
+			if !ok {
+				continue
+			}
+			memberShare := share
+			if int64(j) < remainder {
+				memberShare++
+			}
+			owed[r.Round.Currency][i] -= memberShare
+			owed[r.Round.Currency][buyer] += memberShare
+		}
+	}
+
+	currencies := make([]string, 0, len(owed))
+	for currency := range owed {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	settlements := []Settlement{}
+	for _, currency := range currencies {
+		type position struct {
+			member int
+			cents  int64
+		}
+		var debtors, creditors []position
+		for i, cents := range owed[currency] {
+			switch {
+			case cents < 0:
+				debtors = append(debtors, position{i, -cents})
+			case cents > 0:
+				creditors = append(creditors, position{i, cents})
+			}
+		}
+		// Biggest first, and otherwise in the order of the members
+		biggestFirst := func(a, b position) int { return cmp.Compare(b.cents, a.cents) }
+		slices.SortStableFunc(debtors, biggestFirst)
+		slices.SortStableFunc(creditors, biggestFirst)
+
+		for len(debtors) > 0 && len(creditors) > 0 {
+			cents := min(debtors[0].cents, creditors[0].cents)
+			settlements = append(settlements, Settlement{
+				From:     members[debtors[0].member],
+				To:       members[creditors[0].member],
+				Amount:   float64(cents) / 100,
+				Currency: currency,
+			})
+			if debtors[0].cents -= cents; debtors[0].cents == 0 {
+				debtors = debtors[1:]
+			}
+			if creditors[0].cents -= cents; creditors[0].cents == 0 {
+				creditors = creditors[1:]
+			}
+		}
+	}
+	return settlements
+}
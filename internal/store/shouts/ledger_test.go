@@ -0,0 +1,152 @@
+package shouts
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"testing"
+)
+
+var (
+	alice = Member{UserID: 1, Username: "alice"}
+	bob   = Member{UserID: 2, Username: "bob"}
+	carol = Member{UserID: 3, Username: "carol"}
+	dave  = Member{UserID: 4, Username: "dave"}
+)
+
+// A night out where alice shouts everyone then bob does, with an odd cost that doesn't split
+// evenly, then alice gets carol one and carol gets bob one in another currency
+func night() ([]db.GetSessionRoundsRow, []db.GetSessionRoundRecipientsRow) {
+	var rounds []db.GetSessionRoundsRow
+	var recipients []db.GetSessionRoundRecipientsRow
+	round := func(buyer Member, cost sql.NullFloat64, currency string, members ...Member) {
+		id := int64(len(rounds) + 1)
+		rounds = append(rounds, db.GetSessionRoundsRow{
+			Round:    db.Round{ID: id, BoughtBy: buyer.UserID, Cost: cost, Currency: currency},
+			Username: buyer.Username,
+		})
+		for _, m := range members {
+			recipients = append(recipients, db.GetSessionRoundRecipientsRow{RoundID: id, UserID: m.UserID, Username: m.Username})
+		}
+	}
+	round(alice, sql.NullFloat64{Valid: true, Float64: 30}, "AUD", alice, bob, carol)
+	round(bob, sql.NullFloat64{Valid: true, Float64: 31}, "AUD", alice, bob, carol)
+	round(alice, sql.NullFloat64{}, "AUD", alice, carol)
+	round(carol, sql.NullFloat64{Valid: true, Float64: 10}, "NZD", bob)
+	return rounds, recipients
+}
+
+func TestMembers(t *testing.T) {
+	participants := []db.GetSessionParticipantsRow{{UserID: 2, Username: "bob"}, {UserID: 1, Username: "alice"}}
+	rounds := []db.GetSessionRoundsRow{
+		{Round: db.Round{ID: 1, BoughtBy: 3}, Username: "carol"},
+		{Round: db.Round{ID: 2, BoughtBy: 1}, Username: "alice"},
+	}
+	recipients := []db.GetSessionRoundRecipientsRow{
+		{RoundID: 1, UserID: 4, Username: "dave"},
+		{RoundID: 1, UserID: 2, Username: "bob"},
+		{RoundID: 2, UserID: 3, Username: "carol"},
+	}
+
+	// The participants in the order they joined, then anyone else as they turn up in the rounds
+	members := Members(participants, rounds, recipients)
+	want := []Member{bob, alice, carol, dave}
+	if len(members) != len(want) {
+		t.Fatalf("got members %+v, want %+v", members, want)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("member %d is %+v, want %+v", i, members[i], want[i])
+		}
+	}
+}
+
+func TestBalances(t *testing.T) {
+	rounds, recipients := night()
+	balances := Balances([]Member{alice, bob, carol}, rounds, recipients)
+
+	for i, want := range []Balance{
+		{Member: alice, Rounds: 2, Shouted: 3, Received: 1},
+		{Member: bob, Rounds: 1, Shouted: 2, Received: 2},
+		{Member: carol, Rounds: 1, Shouted: 1, Received: 3},
+	} {
+		if balances[i] != want {
+			t.Errorf("balance %d is %+v, want %+v", i, balances[i], want)
+		}
+	}
+	if net := balances[2].Net(); net != -2 {
+		t.Errorf("got carol %d drinks up, want -2", net)
+	}
+}
+
+func TestNextToBuy(t *testing.T) {
+	rounds, recipients := night()
+
+	// Whoever's furthest behind
+	if next, ok := NextToBuy(Balances([]Member{alice, bob, carol}, rounds, recipients)); !ok || next.Member != carol {
+		t.Errorf("got %+v next, want carol", next)
+	}
+
+	// Then whoever's bought the fewest rounds
+	balances := []Balance{
+		{Member: alice, Rounds: 2, Shouted: 2, Received: 2},
+		{Member: bob, Rounds: 1, Shouted: 1, Received: 1},
+		{Member: carol, Rounds: 3, Shouted: 5, Received: 1},
+	}
+	if next, _ := NextToBuy(balances); next.Member != bob {
+		t.Errorf("got %+v next, want bob", next)
+	}
+
+	// Then whoever joined first
+	if next, _ := NextToBuy([]Balance{{Member: bob}, {Member: alice}}); next.Member != bob {
+		t.Errorf("got %+v next before anyone's bought a round, want bob", next)
+	}
+
+	if _, ok := NextToBuy(nil); ok {
+		t.Errorf("got someone next without any members")
+	}
+}
+
+func TestOwings(t *testing.T) {
+	rounds, recipients := night()
+	owings := Owings([]Member{alice, bob, carol}, rounds, recipients)
+
+	// alice and bob have shouted each other one each, and so have bob and carol
+	want := []Owing{{From: carol, To: alice, Drinks: 2}}
+	if len(owings) != len(want) {
+		t.Fatalf("got owings %+v, want %+v", owings, want)
+	}
+	for i := range want {
+		if owings[i] != want[i] {
+			t.Errorf("owing %d is %+v, want %+v", i, owings[i], want[i])
+		}
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	rounds, recipients := night()
+	settlements := SettleUp([]Member{alice, bob, carol}, rounds, recipients)
+
+	// In AUD alice is owed 20.00 for her round and owes 10.34 for bob's, since she has the lowest
+	// id and gets the odd cent. bob is owed 20.67 and owes 10.00, and carol owes the lot. The
+	// round without a cost doesn't count.
+	want := []Settlement{
+		{From: carol, To: bob, Amount: 10.67, Currency: "AUD"},
+		{From: carol, To: alice, Amount: 9.66, Currency: "AUD"},
+		{From: bob, To: carol, Amount: 10, Currency: "NZD"},
+	}
+	if len(settlements) != len(want) {
+		t.Fatalf("got settlements %+v, want %+v", settlements, want)
+	}
+	for i := range want {
+		if settlements[i] != want[i] {
+			t.Errorf("settlement %d is %+v, want %+v", i, settlements[i], want[i])
+		}
+	}
+
+	// Nothing to settle without costs
+	rounds = []db.GetSessionRoundsRow{{Round: db.Round{ID: 1, BoughtBy: 1, Currency: "AUD"}, Username: "alice"}}
+	recipients = []db.GetSessionRoundRecipientsRow{{RoundID: 1, UserID: 2, Username: "bob"}}
+	if got := SettleUp([]Member{alice, bob}, rounds, recipients); len(got) != 0 {
+		t.Errorf("got settlements %+v without costs", got)
+	}
+}
@@ -0,0 +1,49 @@
+package shouts
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/stock"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on the rounds bought in drinking sessions, implemented
+// by ShoutStore (backed by the database) and MemoryShoutStore (for tests)
+type Store interface {
+	// Adds the round along with who it was bought for
+	AddRound(ctx context.Context, params db.AddRoundParams, recipients []int64) (db.Round, error)
+	GetRound(ctx context.Context, id int64) (db.Round, error)
+	GetRounds(ctx context.Context, sessionId int64) ([]db.GetSessionRoundsRow, error)
+	GetRecipients(ctx context.Context, sessionId int64) ([]db.GetSessionRoundRecipientsRow, error)
+	DeleteRound(ctx context.Context, id int64) (db.Round, error)
+}
+
+var _ Store = (*ShoutStore)(nil)
+var _ Store = (*MemoryShoutStore)(nil)
+
+func validateRound(params db.AddRoundParams, recipients []int64) error {
+	if len(recipients) == 0 {
+		return store.ErrMissingField{Field: "recipients"}
+	}
+	if params.BoughtAt.IsZero() {
+		return store.ErrMissingField{Field: "bought-at"}
+	}
+	if params.Cost.Valid && params.Cost.Float64 < 0 {
+		return store.ErrInvalidField{Field: "cost", Reason: "must not be negative"}
+	}
+	if !stock.ValidCurrency(params.Currency) {
+		return store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}
+	}
+	return nil
+}
+
+func normalizeRound(params db.AddRoundParams, recipients []int64) (db.AddRoundParams, []int64) {
+	params.Currency = strings.ToUpper(strings.TrimSpace(params.Currency))
+	params.BoughtAt = params.BoughtAt.UTC().Truncate(time.Second)
+	recipients = slices.Clone(recipients)
+	slices.Sort(recipients)
+	return params, slices.Compact(recipients)
+}
@@ -0,0 +1,11 @@
+package shouts
+
+import "fmt"
+
+type ErrRoundNotFound struct {
+	ID int64
+}
+
+func (e ErrRoundNotFound) Error() string {
+	return fmt.Sprintf("round with id %d not found", e.ID)
+}
@@ -0,0 +1,169 @@
+package shouts
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as ShoutStore. The user and drinking session stores stand in for the foreign
+// keys, and the user store for the joins.
+type MemoryShoutStore struct {
+	mu           sync.Mutex
+	userStore    users.Store
+	sessionStore drinksessions.Store
+	lastId       int64
+	rounds       []db.Round
+	recipients   []db.RoundRecipient
+}
+
+func NewMemoryShoutStore(userStore users.Store, sessionStore drinksessions.Store) *MemoryShoutStore {
+	return &MemoryShoutStore{
+		userStore:    userStore,
+		sessionStore: sessionStore,
+	}
+}
+
+// The index of the round with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ss *MemoryShoutStore) find(id int64) int {
+	return slices.IndexFunc(ss.rounds, func(r db.Round) bool { return r.ID == id })
+}
+
+func (ss *MemoryShoutStore) AddRound(ctx context.Context, params db.AddRoundParams, recipients []int64) (db.Round, error) {
+	if err := validateRound(params, recipients); err != nil {
+		return db.Round{}, err
+	}
+	params, recipients = normalizeRound(params, recipients)
+	if _, err := ss.sessionStore.GetSession(ctx, params.SessionID); err != nil {
+		return db.Round{}, err
+	}
+	for _, userId := range append([]int64{params.BoughtBy}, recipients...) {
+		if _, err := ss.userStore.GetUserById(ctx, userId); err != nil {
+			return db.Round{}, users.ErrUserNotFound{ID: userId}
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.lastId++
+	round := db.Round{
+		ID:        ss.lastId,
+		SessionID: params.SessionID,
+		BoughtBy:  params.BoughtBy,
+		Cost:      params.Cost,
+		Currency:  params.Currency,
+		BoughtAt:  params.BoughtAt,
+		CreatedAt: store.Now(),
+	}
+	ss.rounds = append(ss.rounds, round)
+	for _, userId := range recipients {
+		ss.recipients = append(ss.recipients, db.RoundRecipient{RoundID: round.ID, UserID: userId})
+	}
+	return round, nil
+}
+
+func (ss *MemoryShoutStore) GetRound(ctx context.Context, id int64) (db.Round, error) {
+	ss.mu.Lock()
+	i := ss.find(id)
+	var round db.Round
+	if i >= 0 {
+		round = ss.rounds[i]
+	}
+	ss.mu.Unlock()
+
+	if i < 0 {
+		return db.Round{}, ErrRoundNotFound{ID: id}
+	}
+	// The rounds of a deleted session go with it
+	if _, err := ss.sessionStore.GetSession(ctx, round.SessionID); err != nil {
+		return db.Round{}, ErrRoundNotFound{ID: id}
+	}
+	return round, nil
+}
+
+func (ss *MemoryShoutStore) GetRounds(ctx context.Context, sessionId int64) ([]db.GetSessionRoundsRow, error) {
+	if _, err := ss.sessionStore.GetSession(ctx, sessionId); err != nil {
+		return []db.GetSessionRoundsRow{}, nil
+	}
+
+	ss.mu.Lock()
+	rounds := slices.Clone(ss.rounds)
+	ss.mu.Unlock()
+
+	rows := []db.GetSessionRoundsRow{}
+	for _, r := range rounds {
+		if r.SessionID != sessionId {
+			continue
+		}
+		// Like the join, which leaves out deleted users
+		user, err := ss.userStore.GetUserById(ctx, r.BoughtBy)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetSessionRoundsRow{Round: r, Username: user.Username})
+	}
+	slices.SortFunc(rows, func(a, b db.GetSessionRoundsRow) int {
+		if c := a.Round.BoughtAt.Compare(b.Round.BoughtAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Round.ID, b.Round.ID)
+	})
+	return rows, nil
+}
+
+func (ss *MemoryShoutStore) GetRecipients(ctx context.Context, sessionId int64) ([]db.GetSessionRoundRecipientsRow, error) {
+	if _, err := ss.sessionStore.GetSession(ctx, sessionId); err != nil {
+		return []db.GetSessionRoundRecipientsRow{}, nil
+	}
+
+	ss.mu.Lock()
+	inSession := map[int64]bool{}
+	for _, r := range ss.rounds {
+		if r.SessionID == sessionId {
+			inSession[r.ID] = true
+		}
+	}
+	recipients := slices.Clone(ss.recipients)
+	ss.mu.Unlock()
+
+	rows := []db.GetSessionRoundRecipientsRow{}
+	for _, r := range recipients {
+		if !inSession[r.RoundID] {
+			continue
+		}
+		user, err := ss.userStore.GetUserById(ctx, r.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetSessionRoundRecipientsRow{RoundID: r.RoundID, UserID: r.UserID, Username: user.Username})
+	}
+	slices.SortFunc(rows, func(a, b db.GetSessionRoundRecipientsRow) int {
+		if c := cmp.Compare(a.RoundID, b.RoundID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return rows, nil
+}
+
+func (ss *MemoryShoutStore) DeleteRound(ctx context.Context, id int64) (db.Round, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := ss.find(id)
+	if i < 0 {
+		return db.Round{}, ErrRoundNotFound{ID: id}
+	}
+	round := ss.rounds[i]
+	ss.rounds = slices.Delete(ss.rounds, i, i+1)
+	ss.recipients = slices.DeleteFunc(ss.recipients, func(r db.RoundRecipient) bool { return r.RoundID == id })
+	return round, nil
+}
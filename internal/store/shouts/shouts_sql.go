@@ -0,0 +1,108 @@
+package shouts
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type ShoutStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewShoutStore(queries db.Querier, logger *log.Logger) *ShoutStore {
+	return &ShoutStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Adds the round, then who it was for. If one of them isn't there the round is deleted again, so
+// there are never rounds bought for no one.
+func (ss *ShoutStore) AddRound(ctx context.Context, params db.AddRoundParams, recipients []int64) (db.Round, error) {
+	if err := validateRound(params, recipients); err != nil {
+		return db.Round{}, err
+	}
+	params, recipients = normalizeRound(params, recipients)
+
+	round, err := ss.queries.AddRound(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if _, err := ss.queries.GetDrinkingSession(ctx, params.SessionID); err == sql.ErrNoRows {
+				return db.Round{}, drinksessions.ErrSessionNotFound{ID: params.SessionID}
+			}
+			return db.Round{}, users.ErrUserNotFound{ID: params.BoughtBy}
+		}
+		ss.logger.Printf("error adding round: %v", err)
+		return db.Round{}, err
+	}
+
+	for _, userId := range recipients {
+		err := ss.queries.AddRoundRecipient(ctx, db.AddRoundRecipientParams{RoundID: round.ID, UserID: userId})
+		if err == nil {
+			continue
+		}
+		if _, err := ss.queries.DeleteRound(ctx, round.ID); err != nil {
+			ss.logger.Printf("error deleting round %d without its recipients: %v", round.ID, err)
+		}
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Round{}, users.ErrUserNotFound{ID: userId}
+		}
+		ss.logger.Printf("error adding round recipient: %v", err)
+		return db.Round{}, err
+	}
+
+	ss.logger.Printf("round added: %d bought by %d for %d people", round.ID, round.BoughtBy, len(recipients))
+	return round, nil
+}
+
+func (ss *ShoutStore) GetRound(ctx context.Context, id int64) (db.Round, error) {
+	round, err := ss.queries.GetRound(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Round{}, ErrRoundNotFound{ID: id}
+		}
+		ss.logger.Printf("error getting round: %v", err)
+		return db.Round{}, err
+	}
+	return round, nil
+}
+
+// The rounds bought in the session, in the order they were bought
+func (ss *ShoutStore) GetRounds(ctx context.Context, sessionId int64) ([]db.GetSessionRoundsRow, error) {
+	rounds, err := ss.queries.GetSessionRounds(ctx, sessionId)
+	if err != nil {
+		ss.logger.Printf("error getting session rounds: %v", err)
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// Who each round in the session was bought for
+func (ss *ShoutStore) GetRecipients(ctx context.Context, sessionId int64) ([]db.GetSessionRoundRecipientsRow, error) {
+	recipients, err := ss.queries.GetSessionRoundRecipients(ctx, sessionId)
+	if err != nil {
+		ss.logger.Printf("error getting session round recipients: %v", err)
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func (ss *ShoutStore) DeleteRound(ctx context.Context, id int64) (db.Round, error) {
+	round, err := ss.queries.DeleteRound(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Round{}, ErrRoundNotFound{ID: id}
+		}
+		ss.logger.Printf("error deleting round: %v", err)
+		return db.Round{}, err
+	}
+
+	ss.logger.Printf("round deleted: %d", round.ID)
+	return round, nil
+}
@@ -0,0 +1,107 @@
+package social
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"math"
+	"slices"
+)
+
+// Who can see a user's activity
+const (
+	// Anyone
+	Public = "public"
+	// Only those the user follows who follow them back
+	Friends = "friends"
+	// No one but the user
+	Private = "private"
+)
+
+// Visibilities in the order they're offered
+var Visibilities = []string{Public, Friends, Private}
+
+// What an activity records
+const (
+	BeerAdded   = "beer-added"
+	BeerEdited  = "beer-edited"
+	BrewerAdded = "brewer-added"
+)
+
+// The operations the rest of the app needs on who follows whom, who can see each user's activity
+// and the activity itself, implemented by SocialStore (backed by the database) and
+// MemorySocialStore (for tests). Feeds are newest first and paged by activity id: each page
+// starts before the last id of the one before, and a before of 0 starts from the newest.
+type Store interface {
+	Follow(ctx context.Context, followerId int64, followeeId int64) error
+	Unfollow(ctx context.Context, followerId int64, followeeId int64) error
+	GetFollowing(ctx context.Context, userId int64) ([]db.GetFollowingRow, error)
+	GetFollowers(ctx context.Context, userId int64) ([]db.GetFollowersRow, error)
+	SetPrivacy(ctx context.Context, params db.SetPrivacyParams) (db.UserPrivacy, error)
+	// The user's privacy setting, which is public if they've never set one
+	GetPrivacy(ctx context.Context, userId int64) (db.UserPrivacy, error)
+	AddActivity(ctx context.Context, params db.AddActivityParams) (db.Activity, error)
+	// The activity of the users the viewer follows that the viewer may see
+	GetFeed(ctx context.Context, viewerId int64, before int64, limit int64) ([]db.GetFeedRow, error)
+	// The user's activity that the viewer may see
+	GetUserActivities(ctx context.Context, viewerId int64, userId int64, before int64, limit int64) ([]db.GetUserActivitiesRow, error)
+}
+
+var _ Store = (*SocialStore)(nil)
+var _ Store = (*MemorySocialStore)(nil)
+
+func validateFollow(followerId int64, followeeId int64) error {
+	if followerId == followeeId {
+		return store.ErrInvalidField{Field: "followee", Reason: "must be someone else"}
+	}
+	return nil
+}
+
+func validatePrivacy(params db.SetPrivacyParams) error {
+	if params.Visibility == "" {
+		return store.ErrMissingField{Field: "visibility"}
+	}
+	if !slices.Contains(Visibilities, params.Visibility) {
+		return store.ErrInvalidField{Field: "visibility", Reason: "must be public, friends or private"}
+	}
+	return nil
+}
+
+func validateActivity(params db.AddActivityParams) error {
+	switch params.Kind {
+	case BeerAdded, BeerEdited:
+		if !params.BeerID.Valid {
+			return store.ErrMissingField{Field: "beer-id"}
+		}
+	case BrewerAdded:
+		if !params.BrewerID.Valid {
+			return store.ErrMissingField{Field: "brewer-id"}
+		}
+	case "":
+		return store.ErrMissingField{Field: "kind"}
+	default:
+		return store.ErrInvalidField{Field: "kind", Reason: "must be beer-added, beer-edited or brewer-added"}
+	}
+	return nil
+}
+
+// Where a page of activity starts
+func startBefore(before int64) int64 {
+	if before <= 0 {
+		return math.MaxInt64
+	}
+	return before
+}
+
+// Whether a viewer may see the activity of a user with the given privacy setting. Users can
+// always see their own, and friends are those who follow each other.
+func canSee(visibility string, own bool, friends bool) bool {
+	switch {
+	case own:
+		return true
+	case visibility == Friends:
+		return friends
+	default:
+		return visibility != Private
+	}
+}
@@ -0,0 +1,251 @@
+package social
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as SocialStore. The user, beer and brewer stores stand in for the foreign keys
+// and the joins, so activity on deleted beers and brewers is left out the same way.
+type MemorySocialStore struct {
+	mu          sync.Mutex
+	userStore   users.Store
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	lastId      int64
+	follows     []db.Follow
+	privacy     map[int64]db.UserPrivacy
+	activities  []db.Activity
+}
+
+func NewMemorySocialStore(userStore users.Store, beerStore beers.Store, brewerStore brewers.Store) *MemorySocialStore {
+	return &MemorySocialStore{
+		userStore:   userStore,
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+		privacy:     map[int64]db.UserPrivacy{},
+	}
+}
+
+// Whether the follower follows the followee. Must be called with the lock held.
+func (ss *MemorySocialStore) isFollowing(followerId int64, followeeId int64) bool {
+	return slices.ContainsFunc(ss.follows, func(f db.Follow) bool {
+		return f.FollowerID == followerId && f.FolloweeID == followeeId
+	})
+}
+
+// Whether the viewer may see the user's activity. Must be called with the lock held.
+func (ss *MemorySocialStore) visible(viewerId int64, userId int64) bool {
+	visibility := Public
+	if privacy, ok := ss.privacy[userId]; ok {
+		visibility = privacy.Visibility
+	}
+	return canSee(visibility, viewerId == userId, ss.isFollowing(viewerId, userId) && ss.isFollowing(userId, viewerId))
+}
+
+func (ss *MemorySocialStore) Follow(ctx context.Context, followerId int64, followeeId int64) error {
+	if err := validateFollow(followerId, followeeId); err != nil {
+		return err
+	}
+	for _, userId := range []int64{followerId, followeeId} {
+		if _, err := ss.userStore.GetUserById(ctx, userId); err != nil {
+			return users.ErrUserNotFound{ID: userId}
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if !ss.isFollowing(followerId, followeeId) {
+		ss.follows = append(ss.follows, db.Follow{FollowerID: followerId, FolloweeID: followeeId, CreatedAt: store.Now()})
+	}
+	return nil
+}
+
+func (ss *MemorySocialStore) Unfollow(ctx context.Context, followerId int64, followeeId int64) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.follows = slices.DeleteFunc(ss.follows, func(f db.Follow) bool {
+		return f.FollowerID == followerId && f.FolloweeID == followeeId
+	})
+	return nil
+}
+
+// The users with the given ids who haven't been deleted, by username
+func (ss *MemorySocialStore) usersByName(ctx context.Context, ids []int64) []db.User {
+	found := []db.User{}
+	for _, id := range ids {
+		if user, err := ss.userStore.GetUserById(ctx, id); err == nil {
+			found = append(found, user)
+		}
+	}
+	slices.SortFunc(found, func(a, b db.User) int { return cmp.Compare(a.Username, b.Username) })
+	return found
+}
+
+func (ss *MemorySocialStore) GetFollowing(ctx context.Context, userId int64) ([]db.GetFollowingRow, error) {
+	ss.mu.Lock()
+	ids := []int64{}
+	for _, f := range ss.follows {
+		if f.FollowerID == userId {
+			ids = append(ids, f.FolloweeID)
+		}
+	}
+	ss.mu.Unlock()
+
+	following := []db.GetFollowingRow{}
+	for _, user := range ss.usersByName(ctx, ids) {
+		following = append(following, db.GetFollowingRow{ID: user.ID, Username: user.Username})
+	}
+	return following, nil
+}
+
+func (ss *MemorySocialStore) GetFollowers(ctx context.Context, userId int64) ([]db.GetFollowersRow, error) {
+	ss.mu.Lock()
+	ids := []int64{}
+	for _, f := range ss.follows {
+		if f.FolloweeID == userId {
+			ids = append(ids, f.FollowerID)
+		}
+	}
+	ss.mu.Unlock()
+
+	followers := []db.GetFollowersRow{}
+	for _, user := range ss.usersByName(ctx, ids) {
+		followers = append(followers, db.GetFollowersRow{ID: user.ID, Username: user.Username})
+	}
+	return followers, nil
+}
+
+func (ss *MemorySocialStore) SetPrivacy(ctx context.Context, params db.SetPrivacyParams) (db.UserPrivacy, error) {
+	if err := validatePrivacy(params); err != nil {
+		return db.UserPrivacy{}, err
+	}
+	if _, err := ss.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.UserPrivacy{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	privacy := db.UserPrivacy{UserID: params.UserID, Visibility: params.Visibility, UpdatedAt: store.Now()}
+	ss.privacy[params.UserID] = privacy
+	return privacy, nil
+}
+
+func (ss *MemorySocialStore) GetPrivacy(ctx context.Context, userId int64) (db.UserPrivacy, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if privacy, ok := ss.privacy[userId]; ok {
+		return privacy, nil
+	}
+	return db.UserPrivacy{UserID: userId, Visibility: Public}, nil
+}
+
+func (ss *MemorySocialStore) AddActivity(ctx context.Context, params db.AddActivityParams) (db.Activity, error) {
+	if err := validateActivity(params); err != nil {
+		return db.Activity{}, err
+	}
+	if params.BeerID.Valid {
+		if _, err := ss.beerStore.GetBeer(ctx, params.BeerID.Int64); err != nil {
+			return db.Activity{}, beers.ErrBeerNotFound{ID: params.BeerID.Int64}
+		}
+	}
+	if params.BrewerID.Valid {
+		if _, err := ss.brewerStore.GetBrewer(ctx, params.BrewerID.Int64); err != nil {
+			return db.Activity{}, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+		}
+	}
+	if _, err := ss.userStore.GetUserById(ctx, params.UserID); err != nil {
+		return db.Activity{}, users.ErrUserNotFound{ID: params.UserID}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.lastId++
+	activity := db.Activity{
+		ID:        ss.lastId,
+		UserID:    params.UserID,
+		Kind:      params.Kind,
+		BeerID:    params.BeerID,
+		BrewerID:  params.BrewerID,
+		CreatedAt: store.Now(),
+	}
+	ss.activities = append(ss.activities, activity)
+	return activity, nil
+}
+
+// The activity matching from before the given id that the viewer may see, newest first, with who
+// did it and the name of what they did it to
+func (ss *MemorySocialStore) latest(ctx context.Context, viewerId int64, before int64, limit int64, match func(db.Activity) bool) []db.GetFeedRow {
+	before = startBefore(before)
+
+	ss.mu.Lock()
+	candidates := []db.Activity{}
+	for _, activity := range slices.Backward(ss.activities) {
+		if activity.ID < before && match(activity) && ss.visible(viewerId, activity.UserID) {
+			candidates = append(candidates, activity)
+		}
+	}
+	ss.mu.Unlock()
+
+	rows := []db.GetFeedRow{}
+	for _, activity := range candidates {
+		if int64(len(rows)) >= limit {
+			break
+		}
+		user, err := ss.userStore.GetUserById(ctx, activity.UserID)
+		if err != nil {
+			continue
+		}
+		row := db.GetFeedRow{Activity: activity, Username: user.Username}
+		if activity.BeerID.Valid {
+			beer, err := ss.beerStore.GetBeer(ctx, activity.BeerID.Int64)
+			if err != nil {
+				continue
+			}
+			row.BeerName.String, row.BeerName.Valid = beer.Name, true
+		}
+		if activity.BrewerID.Valid {
+			brewer, err := ss.brewerStore.GetBrewer(ctx, activity.BrewerID.Int64)
+			if err != nil {
+				continue
+			}
+			row.BrewerName.String, row.BrewerName.Valid = brewer.Name, true
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (ss *MemorySocialStore) GetFeed(ctx context.Context, viewerId int64, before int64, limit int64) ([]db.GetFeedRow, error) {
+	ss.mu.Lock()
+	followed := map[int64]bool{}
+	for _, f := range ss.follows {
+		if f.FollowerID == viewerId {
+			followed[f.FolloweeID] = true
+		}
+	}
+	ss.mu.Unlock()
+
+	return ss.latest(ctx, viewerId, before, limit, func(a db.Activity) bool { return followed[a.UserID] }), nil
+}
+
+func (ss *MemorySocialStore) GetUserActivities(ctx context.Context, viewerId int64, userId int64, before int64, limit int64) ([]db.GetUserActivitiesRow, error) {
+	activities := []db.GetUserActivitiesRow{}
+	for _, row := range ss.latest(ctx, viewerId, before, limit, func(a db.Activity) bool { return a.UserID == userId }) {
+		activities = append(activities, db.GetUserActivitiesRow(row))
+	}
+	return activities, nil
+}
@@ -0,0 +1,161 @@
+package social
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type SocialStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewSocialStore(queries db.Querier, logger *log.Logger) *SocialStore {
+	return &SocialStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Following someone already followed does nothing
+func (ss *SocialStore) Follow(ctx context.Context, followerId int64, followeeId int64) error {
+	if err := validateFollow(followerId, followeeId); err != nil {
+		return err
+	}
+
+	err := ss.queries.Follow(ctx, db.FollowParams{FollowerID: followerId, FolloweeID: followeeId})
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if _, err := ss.queries.GetUserById(ctx, followerId); err == sql.ErrNoRows {
+				return users.ErrUserNotFound{ID: followerId}
+			}
+			return users.ErrUserNotFound{ID: followeeId}
+		}
+		ss.logger.Printf("error following user: %v", err)
+		return err
+	}
+
+	ss.logger.Printf("user %d followed %d", followerId, followeeId)
+	return nil
+}
+
+// Unfollowing someone not followed does nothing
+func (ss *SocialStore) Unfollow(ctx context.Context, followerId int64, followeeId int64) error {
+	if err := ss.queries.Unfollow(ctx, db.UnfollowParams{FollowerID: followerId, FolloweeID: followeeId}); err != nil {
+		ss.logger.Printf("error unfollowing user: %v", err)
+		return err
+	}
+
+	ss.logger.Printf("user %d unfollowed %d", followerId, followeeId)
+	return nil
+}
+
+// Who the user follows, by username
+func (ss *SocialStore) GetFollowing(ctx context.Context, userId int64) ([]db.GetFollowingRow, error) {
+	following, err := ss.queries.GetFollowing(ctx, userId)
+	if err != nil {
+		ss.logger.Printf("error getting following: %v", err)
+		return nil, err
+	}
+	return following, nil
+}
+
+// Who follows the user, by username
+func (ss *SocialStore) GetFollowers(ctx context.Context, userId int64) ([]db.GetFollowersRow, error) {
+	followers, err := ss.queries.GetFollowers(ctx, userId)
+	if err != nil {
+		ss.logger.Printf("error getting followers: %v", err)
+		return nil, err
+	}
+	return followers, nil
+}
+
+func (ss *SocialStore) SetPrivacy(ctx context.Context, params db.SetPrivacyParams) (db.UserPrivacy, error) {
+	if err := validatePrivacy(params); err != nil {
+		return db.UserPrivacy{}, err
+	}
+
+	privacy, err := ss.queries.SetPrivacy(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.UserPrivacy{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		ss.logger.Printf("error setting privacy: %v", err)
+		return db.UserPrivacy{}, err
+	}
+
+	ss.logger.Printf("privacy set: user %d is %s", privacy.UserID, privacy.Visibility)
+	return privacy, nil
+}
+
+func (ss *SocialStore) GetPrivacy(ctx context.Context, userId int64) (db.UserPrivacy, error) {
+	privacy, err := ss.queries.GetPrivacy(ctx, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UserPrivacy{UserID: userId, Visibility: Public}, nil
+		}
+		ss.logger.Printf("error getting privacy: %v", err)
+		return db.UserPrivacy{}, err
+	}
+	return privacy, nil
+}
+
+func (ss *SocialStore) AddActivity(ctx context.Context, params db.AddActivityParams) (db.Activity, error) {
+	if err := validateActivity(params); err != nil {
+		return db.Activity{}, err
+	}
+
+	activity, err := ss.queries.AddActivity(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if params.BeerID.Valid {
+				if _, err := ss.queries.GetBeerById(ctx, params.BeerID.Int64); err == sql.ErrNoRows {
+					return db.Activity{}, beers.ErrBeerNotFound{ID: params.BeerID.Int64}
+				}
+			}
+			if params.BrewerID.Valid {
+				if _, err := ss.queries.GetBrewerById(ctx, params.BrewerID.Int64); err == sql.ErrNoRows {
+					return db.Activity{}, store.ErrBrewerNotFound{ID: params.BrewerID.Int64}
+				}
+			}
+			return db.Activity{}, users.ErrUserNotFound{ID: params.UserID}
+		}
+		ss.logger.Printf("error adding activity: %v", err)
+		return db.Activity{}, err
+	}
+
+	ss.logger.Printf("activity added: %d, %s by user %d", activity.ID, activity.Kind, activity.UserID)
+	return activity, nil
+}
+
+func (ss *SocialStore) GetFeed(ctx context.Context, viewerId int64, before int64, limit int64) ([]db.GetFeedRow, error) {
+	feed, err := ss.queries.GetFeed(ctx, db.GetFeedParams{
+		ViewerID:   viewerId,
+		Before:     startBefore(before),
+		MaxResults: limit,
+	})
+	if err != nil {
+		ss.logger.Printf("error getting feed: %v", err)
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (ss *SocialStore) GetUserActivities(ctx context.Context, viewerId int64, userId int64, before int64, limit int64) ([]db.GetUserActivitiesRow, error) {
+	activities, err := ss.queries.GetUserActivities(ctx, db.GetUserActivitiesParams{
+		UserID:     userId,
+		Before:     startBefore(before),
+		ViewerID:   viewerId,
+		MaxResults: limit,
+	})
+	if err != nil {
+		ss.logger.Printf("error getting user activities: %v", err)
+		return nil, err
+	}
+	return activities, nil
+}
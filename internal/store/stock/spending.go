@@ -0,0 +1,96 @@
+package stock
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/drinks"
+	"cmp"
+	"slices"
+	"time"
+)
+
+// What spending is totalled by, within each month
+type GroupBy int
+
+const (
+	ByUser GroupBy = iota
+	ByBrewer
+	ByStyle
+)
+
+// What was spent in a month and currency on the user's, brewer's or style's beers. Amounts in
+// different currencies are never added together.
+type Spending struct {
+	// As YYYY-MM
+	Month    string
+	Currency string
+	// The username, brewer or style, which is empty when it isn't known
+	Name       string
+	Containers int64
+	Total      float64
+	// The pure alcohol in everything that was bought
+	AlcoholMl float64
+}
+
+func (s Spending) StandardDrinks() float64 {
+	return drinks.StandardDrinksOfAlcohol(s.AlcoholMl)
+}
+
+// What each standard drink cost, false when nothing bought had any alcohol in it
+func (s Spending) CostPerStandardDrink() (float64, bool) {
+	return drinks.CostPerStandardDrink(s.Total, s.StandardDrinks())
+}
+
+// Midnight UTC on the first of the month, which is when spending for the month starts
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Orders totals like the queries do: latest month first, then the most spent, then by name
+func byMonthAndTotal(a, b Spending) int {
+	if c := cmp.Compare(b.Month, a.Month); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Total, a.Total); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Currency, b.Currency)
+}
+
+// Totals up the purchases with a price the way the spending queries do, for the memory store
+func totalSpending(purchases []db.GetPurchasesRow, groupBy GroupBy) []Spending {
+	type key struct{ month, currency, name string }
+	totals := map[key]*Spending{}
+	for _, p := range purchases {
+		if !p.Stock.Price.Valid {
+			continue
+		}
+		var name string
+		switch groupBy {
+		case ByUser:
+			name = p.Username.String
+		case ByBrewer:
+			name = p.BrewerName.String
+		case ByStyle:
+			name = p.Style.String
+		}
+		k := key{p.Stock.PurchasedOn.Format("2006-01"), p.Stock.Currency, name}
+		total, ok := totals[k]
+		if !ok {
+			total = &Spending{Month: k.month, Currency: k.currency, Name: k.name}
+			totals[k] = total
+		}
+		total.Containers += p.Stock.Bought
+		total.Total += float64(p.Stock.Bought) * p.Stock.Price.Float64
+		total.AlcoholMl += float64(p.Stock.Bought*p.Stock.ContainerMl) * p.Abv / 100
+	}
+
+	spending := make([]Spending, 0, len(totals))
+	for _, total := range totals {
+		spending = append(spending, *total)
+	}
+	slices.SortFunc(spending, byMonthAndTotal)
+	return spending
+}
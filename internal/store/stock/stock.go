@@ -0,0 +1,104 @@
+package stock
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The operations the rest of the app needs on what's in the fridge, implemented by StockStore
+// (backed by the database) and MemoryStockStore (for tests)
+type Store interface {
+	AddStock(ctx context.Context, params db.AddStockParams) (db.Stock, error)
+	GetStock(ctx context.Context, id int64) (db.Stock, error)
+	GetFridge(ctx context.Context) ([]db.GetFridgeRow, error)
+	GetStockLevels(ctx context.Context) (map[int64]int64, error)
+	TakeOne(ctx context.Context, beerId int64) (db.Stock, error)
+	DeleteStock(ctx context.Context, id int64) (db.Stock, error)
+	GetSpending(ctx context.Context, groupBy GroupBy, since time.Time) ([]Spending, error)
+	GetUserSpending(ctx context.Context, userId int64, since time.Time) (map[string]float64, error)
+	GetPurchases(ctx context.Context, since time.Time) ([]db.GetPurchasesRow, error)
+}
+
+var _ Store = (*StockStore)(nil)
+var _ Store = (*MemoryStockStore)(nil)
+
+// How many of a beer are left when it's shown as running low
+const LowStock = 2
+
+// Whether the beer is running low, but not out
+func IsLow(quantity int64) bool {
+	return quantity > 0 && quantity <= LowStock
+}
+
+func validateStock(params db.AddStockParams) error {
+	if params.Quantity < 1 {
+		return store.ErrInvalidField{Field: "quantity", Reason: "must be at least 1"}
+	}
+	if params.ContainerMl < 1 {
+		return store.ErrInvalidField{Field: "container-ml", Reason: "must be at least 1"}
+	}
+	if params.PurchasedOn.IsZero() {
+		return store.ErrMissingField{Field: "purchased-on"}
+	}
+	if params.BestBefore.Valid && params.BestBefore.Time.Before(params.PurchasedOn) {
+		return store.ErrInvalidField{Field: "best-before", Reason: "must not be before it was bought"}
+	}
+	if params.Price.Valid && params.Price.Float64 < 0 {
+		return store.ErrInvalidField{Field: "price", Reason: "must not be negative"}
+	}
+	if !ValidCurrency(params.Currency) {
+		return store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}
+	}
+	return nil
+}
+
+// Whether the currency looks like an ISO 4217 code. It isn't checked against the list, so a
+// currency that's new or made up for the trip still works.
+func ValidCurrency(currency string) bool {
+	currency = strings.TrimSpace(currency)
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if !unicode.IsLetter(c) || c > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// Entries are kept once they're empty, as the record of what was spent, so how many were
+// bought is set from how many are going in
+func normalizeStock(params db.AddStockParams) db.AddStockParams {
+	params.Bought = params.Quantity
+	params.Currency = strings.ToUpper(strings.TrimSpace(params.Currency))
+	params.PurchasedOn = store.Day(params.PurchasedOn)
+	if params.BestBefore.Valid {
+		params.BestBefore.Time = store.Day(params.BestBefore.Time)
+	}
+	return params
+}
+
+// How many days before its best-before a beer is shown as needing drinking soon
+const SoonDays = 14
+
+// How close the beer is to its best-before on the day: "past", "soon" or "" if there's no rush
+func Freshness(bestBefore sql.NullTime, today time.Time) string {
+	if !bestBefore.Valid {
+		return ""
+	}
+	today = store.Day(today)
+	switch {
+	case bestBefore.Time.Before(today):
+		return "past"
+	case bestBefore.Time.Before(today.AddDate(0, 0, SoonDays+1)):
+		return "soon"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,19 @@
+package stock
+
+import "fmt"
+
+type ErrStockNotFound struct {
+	ID int64
+}
+
+func (e ErrStockNotFound) Error() string {
+	return fmt.Sprintf("stock with id %d not found", e.ID)
+}
+
+type ErrOutOfStock struct {
+	BeerID int64
+}
+
+func (e ErrOutOfStock) Error() string {
+	return fmt.Sprintf("beer with id %d is out of stock", e.BeerID)
+}
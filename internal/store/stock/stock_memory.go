@@ -0,0 +1,247 @@
+package stock
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as StockStore. The beer store stands in for the foreign key and the join which
+// leaves out beers in the trash, and the brewer and user stores for the joins in the spending
+// totals.
+type MemoryStockStore struct {
+	mu          sync.Mutex
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	userStore   users.Store
+	lastId      int64
+	stock       []db.Stock
+}
+
+func NewMemoryStockStore(beerStore beers.Store, brewerStore brewers.Store, userStore users.Store) *MemoryStockStore {
+	return &MemoryStockStore{
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+		userStore:   userStore,
+	}
+}
+
+// Orders entries like the queries do: soonest best-before first, then those without one, then by
+// when they were bought
+func byBestBefore(a, b db.Stock) int {
+	if a.BestBefore.Valid != b.BestBefore.Valid {
+		if a.BestBefore.Valid {
+			return -1
+		}
+		return 1
+	}
+	if c := a.BestBefore.Time.Compare(b.BestBefore.Time); c != 0 {
+		return c
+	}
+	if c := a.PurchasedOn.Compare(b.PurchasedOn); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+func (ss *MemoryStockStore) AddStock(ctx context.Context, params db.AddStockParams) (db.Stock, error) {
+	if err := validateStock(params); err != nil {
+		return db.Stock{}, err
+	}
+	if _, err := ss.beerStore.GetBeer(ctx, params.BeerID); err != nil {
+		return db.Stock{}, beers.ErrBeerNotFound{ID: params.BeerID}
+	}
+	params = normalizeStock(params)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.lastId++
+	stock := db.Stock{
+		ID:          ss.lastId,
+		BeerID:      params.BeerID,
+		UserID:      params.UserID,
+		Quantity:    params.Quantity,
+		Bought:      params.Bought,
+		ContainerMl: params.ContainerMl,
+		PurchasedOn: params.PurchasedOn,
+		BestBefore:  params.BestBefore,
+		Price:       params.Price,
+		Currency:    params.Currency,
+		CreatedAt:   store.Now(),
+	}
+	ss.stock = append(ss.stock, stock)
+	return stock, nil
+}
+
+func (ss *MemoryStockStore) GetStock(ctx context.Context, id int64) (db.Stock, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.stock, func(s db.Stock) bool { return s.ID == id })
+	if i < 0 {
+		return db.Stock{}, ErrStockNotFound{ID: id}
+	}
+	return ss.stock[i], nil
+}
+
+func (ss *MemoryStockStore) GetFridge(ctx context.Context) ([]db.GetFridgeRow, error) {
+	live, err := ss.beerStore.GetBeers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(live))
+	for _, beer := range live {
+		names[beer.ID] = beer.Name
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	fridge := []db.GetFridgeRow{}
+	for _, s := range slices.SortedFunc(slices.Values(ss.stock), byBestBefore) {
+		if name, ok := names[s.BeerID]; ok && s.Quantity > 0 {
+			fridge = append(fridge, db.GetFridgeRow{Stock: s, BeerName: name})
+		}
+	}
+	return fridge, nil
+}
+
+func (ss *MemoryStockStore) GetStockLevels(ctx context.Context) (map[int64]int64, error) {
+	fridge, err := ss.GetFridge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[int64]int64)
+	for _, row := range fridge {
+		levels[row.Stock.BeerID] += row.Stock.Quantity
+	}
+	return levels, nil
+}
+
+func (ss *MemoryStockStore) TakeOne(ctx context.Context, beerId int64) (db.Stock, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	first := -1
+	for i, s := range ss.stock {
+		if s.BeerID == beerId && s.Quantity > 0 && (first < 0 || byBestBefore(s, ss.stock[first]) < 0) {
+			first = i
+		}
+	}
+	if first < 0 {
+		return db.Stock{}, ErrOutOfStock{BeerID: beerId}
+	}
+
+	ss.stock[first].Quantity--
+	return ss.stock[first], nil
+}
+
+func (ss *MemoryStockStore) DeleteStock(ctx context.Context, id int64) (db.Stock, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.stock, func(s db.Stock) bool { return s.ID == id })
+	if i < 0 {
+		return db.Stock{}, ErrStockNotFound{ID: id}
+	}
+	stock := ss.stock[i]
+	ss.stock = slices.Delete(ss.stock, i, i+1)
+	return stock, nil
+}
+
+func (ss *MemoryStockStore) GetSpending(ctx context.Context, groupBy GroupBy, since time.Time) ([]Spending, error) {
+	if groupBy != ByUser && groupBy != ByBrewer && groupBy != ByStyle {
+		return nil, fmt.Errorf("unknown spending grouping %d", groupBy)
+	}
+	purchases, err := ss.GetPurchases(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	return totalSpending(purchases, groupBy), nil
+}
+
+func (ss *MemoryStockStore) GetUserSpending(ctx context.Context, userId int64, since time.Time) (map[string]float64, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	since = store.Day(since)
+	totals := make(map[string]float64)
+	for _, s := range ss.stock {
+		if s.UserID.Valid && s.UserID.Int64 == userId && s.Price.Valid && !s.PurchasedOn.Before(since) {
+			totals[s.Currency] += float64(s.Bought) * s.Price.Float64
+		}
+	}
+	return totals, nil
+}
+
+func (ss *MemoryStockStore) GetPurchases(ctx context.Context, since time.Time) ([]db.GetPurchasesRow, error) {
+	beersById := map[int64]db.Beer{}
+	for _, get := range []func(context.Context) ([]db.Beer, error){ss.beerStore.GetBeers, ss.beerStore.GetDeletedBeers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, beer := range all {
+			beersById[beer.ID] = beer
+		}
+	}
+	brewerNames := map[int64]string{}
+	for _, get := range []func(context.Context) ([]db.Brewer, error){ss.brewerStore.GetBrewers, ss.brewerStore.GetDeletedBrewers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, brewer := range all {
+			brewerNames[brewer.ID] = brewer.Name
+		}
+	}
+	usernames := map[int64]string{}
+	for _, get := range []func(context.Context) ([]db.User, error){ss.userStore.GetUsers, ss.userStore.GetDeletedUsers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range all {
+			usernames[user.ID] = user.Username
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	since = store.Day(since)
+	purchases := []db.GetPurchasesRow{}
+	for _, s := range ss.stock {
+		beer, ok := beersById[s.BeerID]
+		if !ok || s.PurchasedOn.Before(since) {
+			continue
+		}
+		row := db.GetPurchasesRow{Stock: s, BeerName: beer.Name, Abv: beer.Abv, Style: beer.Style}
+		if name, ok := brewerNames[beer.BrewerID.Int64]; ok && beer.BrewerID.Valid {
+			row.BrewerName = sql.NullString{String: name, Valid: true}
+		}
+		if name, ok := usernames[s.UserID.Int64]; ok && s.UserID.Valid {
+			row.Username = sql.NullString{String: name, Valid: true}
+		}
+		purchases = append(purchases, row)
+	}
+	slices.SortFunc(purchases, func(a, b db.GetPurchasesRow) int {
+		if c := a.Stock.PurchasedOn.Compare(b.Stock.PurchasedOn); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Stock.ID, b.Stock.ID)
+	})
+	return purchases, nil
+}
@@ -0,0 +1,171 @@
+package stock
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+type StockStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewStockStore(queries db.Querier, logger *log.Logger) *StockStore {
+	return &StockStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ss *StockStore) AddStock(ctx context.Context, params db.AddStockParams) (db.Stock, error) {
+	if err := validateStock(params); err != nil {
+		return db.Stock{}, err
+	}
+
+	stock, err := ss.queries.AddStock(ctx, normalizeStock(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Stock{}, beers.ErrBeerNotFound{ID: params.BeerID}
+		}
+		ss.logger.Printf("error adding stock: %v", err)
+		return db.Stock{}, err
+	}
+
+	ss.logger.Printf("stock added: %v", stock)
+	return stock, nil
+}
+
+func (ss *StockStore) GetStock(ctx context.Context, id int64) (db.Stock, error) {
+	stock, err := ss.queries.GetStock(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Stock{}, ErrStockNotFound{ID: id}
+		}
+		ss.logger.Printf("error getting stock: %v", err)
+		return db.Stock{}, err
+	}
+	return stock, nil
+}
+
+// Everything in the fridge, soonest best-before first, not counting beers in the trash
+func (ss *StockStore) GetFridge(ctx context.Context) ([]db.GetFridgeRow, error) {
+	fridge, err := ss.queries.GetFridge(ctx)
+	if err != nil {
+		ss.logger.Printf("error getting fridge: %v", err)
+		return nil, err
+	}
+	return fridge, nil
+}
+
+// How many of each beer are in the fridge, keyed by beer id. Beers which aren't there are left
+// out.
+func (ss *StockStore) GetStockLevels(ctx context.Context) (map[int64]int64, error) {
+	rows, err := ss.queries.GetStockLevels(ctx)
+	if err != nil {
+		ss.logger.Printf("error getting stock levels: %v", err)
+		return nil, err
+	}
+
+	levels := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		levels[row.BeerID] = row.Quantity
+	}
+	return levels, nil
+}
+
+// Takes one of the beer out of the fridge, from the entry which goes off first, returning that
+// entry as it was left. Empty entries stay, as the record of what was bought, but aren't in the
+// fridge any more.
+func (ss *StockStore) TakeOne(ctx context.Context, beerId int64) (db.Stock, error) {
+	stock, err := ss.queries.TakeStock(ctx, beerId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Stock{}, ErrOutOfStock{BeerID: beerId}
+		}
+		ss.logger.Printf("error taking stock: %v", err)
+		return db.Stock{}, err
+	}
+	return stock, nil
+}
+
+func (ss *StockStore) DeleteStock(ctx context.Context, id int64) (db.Stock, error) {
+	stock, err := ss.queries.DeleteStock(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Stock{}, ErrStockNotFound{ID: id}
+		}
+		ss.logger.Printf("error deleting stock: %v", err)
+		return db.Stock{}, err
+	}
+
+	ss.logger.Printf("stock deleted: %v", stock)
+	return stock, nil
+}
+
+// What was spent each month since the day, totalled by user, brewer or style. Purchases without a
+// price are left out.
+func (ss *StockStore) GetSpending(ctx context.Context, groupBy GroupBy, since time.Time) ([]Spending, error) {
+	var spending []Spending
+	var err error
+	switch groupBy {
+	case ByUser:
+		var rows []db.GetSpendingByUserRow
+		rows, err = ss.queries.GetSpendingByUser(ctx, store.Day(since))
+		for _, row := range rows {
+			spending = append(spending, Spending(row))
+		}
+	case ByBrewer:
+		var rows []db.GetSpendingByBrewerRow
+		rows, err = ss.queries.GetSpendingByBrewer(ctx, store.Day(since))
+		for _, row := range rows {
+			spending = append(spending, Spending(row))
+		}
+	case ByStyle:
+		var rows []db.GetSpendingByStyleRow
+		rows, err = ss.queries.GetSpendingByStyle(ctx, store.Day(since))
+		for _, row := range rows {
+			spending = append(spending, Spending(row))
+		}
+	default:
+		return nil, fmt.Errorf("unknown spending grouping %d", groupBy)
+	}
+	if err != nil {
+		ss.logger.Printf("error getting spending: %v", err)
+		return nil, err
+	}
+	return spending, nil
+}
+
+// What the user has spent since the day, keyed by currency
+func (ss *StockStore) GetUserSpending(ctx context.Context, userId int64, since time.Time) (map[string]float64, error) {
+	rows, err := ss.queries.GetUserSpending(ctx, db.GetUserSpendingParams{
+		UserID:      sql.NullInt64{Int64: userId, Valid: true},
+		PurchasedOn: store.Day(since),
+	})
+	if err != nil {
+		ss.logger.Printf("error getting user spending: %v", err)
+		return nil, err
+	}
+
+	totals := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Total
+	}
+	return totals, nil
+}
+
+// Everything bought since the day, oldest first, including beers which are in the trash
+func (ss *StockStore) GetPurchases(ctx context.Context, since time.Time) ([]db.GetPurchasesRow, error) {
+	purchases, err := ss.queries.GetPurchases(ctx, store.Day(since))
+	if err != nil {
+		ss.logger.Printf("error getting purchases: %v", err)
+		return nil, err
+	}
+	return purchases, nil
+}
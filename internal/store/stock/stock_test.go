@@ -0,0 +1,46 @@
+package stock
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestIsLow(t *testing.T) {
+	for quantity, want := range map[int64]bool{0: false, 1: true, LowStock: true, LowStock + 1: false} {
+		if got := IsLow(quantity); got != want {
+			t.Errorf("IsLow(%d): got %v, want %v", quantity, got, want)
+		}
+	}
+}
+
+func TestFreshness(t *testing.T) {
+	// Late in the day, which shouldn't matter
+	today := time.Date(2025, time.March, 10, 23, 30, 0, 0, time.UTC)
+	on := func(year int, month time.Month, day int) sql.NullTime {
+		return sql.NullTime{Valid: true, Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	for _, tc := range []struct {
+		bestBefore sql.NullTime
+		want       string
+	}{
+		{sql.NullTime{}, ""},
+		{on(2025, time.March, 9), "past"},
+		{on(2025, time.March, 10), "soon"},
+		{on(2025, time.March, 24), "soon"},
+		{on(2025, time.March, 25), ""},
+	} {
+		if got := Freshness(tc.bestBefore, today); got != tc.want {
+			t.Errorf("Freshness(%v): got %q, want %q", tc.bestBefore.Time, got, tc.want)
+		}
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	for currency, want := range map[string]bool{"AUD": true, "nzd": true, " EUR ": true, "": false, "A$": false, "AU1": false, "DOLLARS": false, "ÅUD": false} {
+		if got := ValidCurrency(currency); got != want {
+			t.Errorf("ValidCurrency(%q): got %v, want %v", currency, got, want)
+		}
+	}
+}
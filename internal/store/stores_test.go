@@ -0,0 +1,2508 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/events"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/badges"
+	"beer_oclock/internal/store/barcodes"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/budgets"
+	"beer_oclock/internal/store/comments"
+	"beer_oclock/internal/store/drinklog"
+	"beer_oclock/internal/store/drinksessions"
+	"beer_oclock/internal/store/emails"
+	"beer_oclock/internal/store/goals"
+	"beer_oclock/internal/store/photos"
+	"beer_oclock/internal/store/resets"
+	"beer_oclock/internal/store/schedules"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/shouts"
+	"beer_oclock/internal/store/social"
+	"beer_oclock/internal/store/stock"
+	"beer_oclock/internal/store/storetest"
+	"beer_oclock/internal/store/styles"
+	"beer_oclock/internal/store/tags"
+	"beer_oclock/internal/store/tastings"
+	"beer_oclock/internal/store/users"
+	"beer_oclock/internal/store/venues"
+	"beer_oclock/internal/store/webhooks"
+	"beer_oclock/internal/store/wishlist"
+)
+
+func TestUserStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		us := stores.Users
+
+		if _, err := us.AddUser(ctx, db.AddUserParams{PasswordHash: "hash"}); err != (store.ErrMissingField{Field: "username"}) {
+			t.Errorf("adding user without username: got %v", err)
+		}
+
+		user, err := us.AddUser(ctx, db.AddUserParams{Username: "SaltyTaro", PasswordHash: "hash"})
+		if err != nil {
+			t.Fatalf("adding user: %v", err)
+		}
+		if user.Username != "saltytaro" || !user.CreatedAt.Valid {
+			t.Errorf("added user: got %+v", user)
+		}
+
+		_, err = us.AddUser(ctx, db.AddUserParams{Username: "SALTYTARO", PasswordHash: "hash"})
+		if _, ok := err.(users.ErrUserAlreadyExists); !ok {
+			t.Errorf("adding duplicate user: got %v", err)
+		}
+
+		if got, err := us.GetUserByUsername(ctx, "saltytaro"); err != nil || got.ID != user.ID {
+			t.Errorf("getting user by username: got %+v, %v", got, err)
+		}
+		if _, err := us.GetUserByUsername(ctx, "nobody"); err == nil {
+			t.Errorf("getting missing user by username: expected an error")
+		}
+
+		if _, err := us.DeleteUser(ctx, user.ID); err != nil {
+			t.Fatalf("deleting user: %v", err)
+		}
+		if _, err := us.GetUser(ctx, user.ID); err != (users.ErrUserNotFound{ID: user.ID}) {
+			t.Errorf("getting deleted user: got %v", err)
+		}
+		if _, err := us.DeleteUser(ctx, user.ID); err != (users.ErrUserNotFound{ID: user.ID}) {
+			t.Errorf("deleting deleted user: got %v", err)
+		}
+		if count, _ := us.CountUsers(ctx); count != 0 {
+			t.Errorf("counting users: got %d, want 0", count)
+		}
+		if deleted, _ := us.GetDeletedUsers(ctx); len(deleted) != 1 || !deleted[0].DeletedAt.Valid {
+			t.Errorf("getting deleted users: got %+v", deleted)
+		}
+		// The username is still taken while the user is in the trash
+		if _, err := us.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"}); err != (users.ErrUserAlreadyExists{Username: "saltytaro", Deleted: true}) {
+			t.Errorf("adding user with the username of a deleted one: got %v", err)
+		}
+
+		if _, err := us.RestoreUser(ctx, user.ID); err != nil {
+			t.Fatalf("restoring user: %v", err)
+		}
+		if _, err := us.RestoreUser(ctx, user.ID); err != (users.ErrUserNotFound{ID: user.ID}) {
+			t.Errorf("restoring user which isn't deleted: got %v", err)
+		}
+		if _, err := us.PurgeUser(ctx, user.ID); err != (users.ErrUserNotFound{ID: user.ID}) {
+			t.Errorf("purging user which isn't deleted: got %v", err)
+		}
+
+		us.DeleteUser(ctx, user.ID)
+		if n, err := us.PurgeDeletedUsers(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+			t.Errorf("purging users deleted over an hour ago: got %d, %v", n, err)
+		}
+		if n, err := us.PurgeDeletedUsers(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+			t.Errorf("purging users deleted before an hour from now: got %d, %v", n, err)
+		}
+		if _, err := us.RestoreUser(ctx, user.ID); err != (users.ErrUserNotFound{ID: user.ID}) {
+			t.Errorf("restoring purged user: got %v", err)
+		}
+	})
+}
+
+func TestBrewerStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Brewers
+
+		if _, err := bs.AddBrewer(ctx, db.AddBrewerParams{}); err != (store.ErrMissingField{Field: "name"}) {
+			t.Errorf("adding brewer without name: got %v", err)
+		}
+
+		brewer, err := bs.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's", Location: sql.NullString{Valid: true, String: "Brisbane"}})
+		if err != nil {
+			t.Fatalf("adding brewer: %v", err)
+		}
+		if _, err := bs.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"}); err != (brewers.ErrBrewerAlreadyExists{Name: "Felon's"}) {
+			t.Errorf("adding duplicate brewer: got %v", err)
+		}
+
+		if got, err := bs.GetBrewer(ctx, brewer.ID); err != nil || got != brewer {
+			t.Errorf("getting brewer: got %+v, %v", got, err)
+		}
+		if _, err := bs.GetBrewer(ctx, 999); err != (store.ErrBrewerNotFound{ID: 999}) {
+			t.Errorf("getting missing brewer: got %v", err)
+		}
+
+		if _, err := bs.DeleteBrewer(ctx, brewer.ID); err != nil {
+			t.Fatalf("deleting brewer: %v", err)
+		}
+		if all, _ := bs.GetBrewers(ctx); len(all) != 0 {
+			t.Errorf("getting brewers after delete: got %+v", all)
+		}
+		// The name is still taken while the brewer is in the trash
+		if _, err := bs.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"}); err != (brewers.ErrBrewerAlreadyExists{Name: "Felon's", Deleted: true}) {
+			t.Errorf("adding brewer with the name of a deleted one: got %v", err)
+		}
+
+		if restored, err := bs.RestoreBrewer(ctx, brewer.ID); err != nil || restored != brewer {
+			t.Errorf("restoring brewer: got %+v, %v", restored, err)
+		}
+		if count, _ := bs.CountBrewers(ctx); count != 1 {
+			t.Errorf("counting brewers: got %d, want 1", count)
+		}
+
+		bs.DeleteBrewer(ctx, brewer.ID)
+		if _, err := bs.PurgeBrewer(ctx, brewer.ID); err != nil {
+			t.Errorf("purging brewer: %v", err)
+		}
+		if deleted, _ := bs.GetDeletedBrewers(ctx); len(deleted) != 0 {
+			t.Errorf("getting deleted brewers after purge: got %+v", deleted)
+		}
+	})
+}
+
+func TestBeerStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Beers
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		brewerId := sql.NullInt64{Valid: true, Int64: brewer.ID}
+		rating := sql.NullFloat64{Valid: true, Float64: 7.5}
+
+		invalid := []struct {
+			params db.AddBeerParams
+			want   error
+		}{
+			{db.AddBeerParams{Abv: 5, Rating: rating}, store.ErrMissingField{Field: "name"}},
+			{db.AddBeerParams{Name: "Pale", Abv: -1, Rating: rating}, store.ErrInvalidField{Field: "abv", Reason: "must be >= 0"}},
+			{db.AddBeerParams{Name: "Pale", Abv: 5}, store.ErrMissingField{Field: "rating"}},
+			{db.AddBeerParams{Name: "Pale", Abv: 5, Rating: sql.NullFloat64{Valid: true, Float64: 11}}, store.ErrInvalidField{Field: "rating", Reason: "must be between 0 and 10"}},
+			{db.AddBeerParams{Name: "Pale", Abv: 5, Rating: rating, BrewerID: sql.NullInt64{Valid: true, Int64: 999}}, store.ErrBrewerNotFound{ID: 999}},
+		}
+		for _, tc := range invalid {
+			if _, err := bs.AddBeer(ctx, author.ID, tc.params); err != tc.want {
+				t.Errorf("adding beer %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		beer, err := bs.AddBeer(ctx, author.ID, db.AddBeerParams{
+			Name:     "Pale",
+			BrewerID: brewerId,
+			Style:    sql.NullString{Valid: true, String: "American Pale Ale"},
+			Abv:      5.5,
+			Rating:   rating,
+			Notes:    sql.NullString{Valid: true, String: "Citrus and pine"},
+		})
+		if err != nil {
+			t.Fatalf("adding beer: %v", err)
+		}
+		_, err = bs.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", BrewerID: brewerId, Abv: 4, Rating: rating})
+		if _, ok := err.(store.ErrBeerAlreadyExists); !ok {
+			t.Errorf("adding duplicate beer: got %v", err)
+		}
+		// Beers without a brewer never clash
+		for range 2 {
+			if _, err := bs.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Homebrew", Abv: 4, Rating: rating}); err != nil {
+				t.Errorf("adding beer without a brewer: %v", err)
+			}
+		}
+
+		for query, want := range map[string]int{"pale": 1, "PINE": 1, "brew": 2, "lager": 0} {
+			found, err := bs.SearchBeers(ctx, sql.NullString{Valid: true, String: query})
+			if err != nil || len(found) != want {
+				t.Errorf("searching for %q: got %d beers (%v), want %d", query, len(found), err, want)
+			}
+		}
+
+		updated, err := bs.UpdateBeer(ctx, author.ID, db.UpdateBeerParams{
+			ID:   beer.ID,
+			Name: sql.NullString{Valid: true, String: "Pale Ale"},
+			Abv:  sql.NullFloat64{Valid: true, Float64: 5.8},
+		})
+		if err != nil {
+			t.Fatalf("updating beer: %v", err)
+		}
+		if updated.Name != "Pale Ale" || updated.Abv != 5.8 || updated.Style != beer.Style || updated.Rating != beer.Rating {
+			t.Errorf("updated beer: got %+v", updated)
+		}
+		_, err = bs.UpdateBeer(ctx, author.ID, db.UpdateBeerParams{ID: 999, Name: sql.NullString{Valid: true, String: "Ghost"}})
+		if err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("updating missing beer: got %v", err)
+		}
+
+		history, err := bs.GetBeerHistory(ctx, beer.ID)
+		if err != nil || len(history) != 2 {
+			t.Fatalf("getting beer history: got %d revisions, %v", len(history), err)
+		}
+		latest := history[0]
+		if latest.Number != 2 || latest.Author.String != "saltytaro" {
+			t.Errorf("latest revision: got %+v", latest)
+		}
+		wantChanges := []beers.FieldChange{{Field: "Name", From: "Pale", To: "Pale Ale"}, {Field: "ABV", From: "5.50%", To: "5.80%"}}
+		if len(latest.Changes) != len(wantChanges) || latest.Changes[0] != wantChanges[0] || latest.Changes[1] != wantChanges[1] {
+			t.Errorf("latest revision changes: got %+v, want %+v", latest.Changes, wantChanges)
+		}
+		if first := history[1]; first.BrewerName.String != "Felon's" || len(first.Changes) != 6 {
+			t.Errorf("first revision: got %+v", first)
+		}
+
+		reverted, err := bs.RevertBeer(ctx, author.ID, beer.ID, history[1].BeerRevision.ID)
+		if err != nil || reverted.Name != "Pale" || reverted.Abv != 5.5 {
+			t.Errorf("reverting beer: got %+v, %v", reverted, err)
+		}
+		_, err = bs.RevertBeer(ctx, author.ID, beer.ID+1, history[1].BeerRevision.ID)
+		if _, ok := err.(beers.ErrBeerRevisionNotFound); !ok {
+			t.Errorf("reverting beer to another beer's revision: got %v", err)
+		}
+
+		if _, err := bs.DeleteBeer(ctx, beer.ID); err != nil {
+			t.Fatalf("deleting beer: %v", err)
+		}
+		if _, err := bs.GetBeer(ctx, beer.ID); err != (beers.ErrBeerNotFound{ID: beer.ID}) {
+			t.Errorf("getting deleted beer: got %v", err)
+		}
+		if found, _ := bs.SearchBeers(ctx, sql.NullString{Valid: true, String: "pale"}); len(found) != 0 {
+			t.Errorf("searching finds deleted beer: got %+v", found)
+		}
+		if count, _ := bs.CountBeers(ctx); count != 2 {
+			t.Errorf("counting beers: got %d, want 2", count)
+		}
+		// The name and brewer are still taken while the beer is in the trash
+		_, err = bs.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", BrewerID: brewerId, Abv: 4, Rating: rating})
+		if err != (store.ErrBeerAlreadyExists{BrewerId: brewerId.Int64, Name: "Pale", Deleted: true}) {
+			t.Errorf("adding beer with the name of a deleted one: got %v", err)
+		}
+		if _, err := bs.RestoreBeer(ctx, beer.ID); err != nil {
+			t.Errorf("restoring beer: %v", err)
+		}
+		if all, _ := bs.GetBeers(ctx); len(all) != 3 {
+			t.Errorf("getting beers after restore: got %d, want 3", len(all))
+		}
+	})
+}
+
+func TestRevertBeerClearsFields(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Beers
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		beer, err := bs.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Homebrew", Abv: 4, Rating: sql.NullFloat64{Valid: true, Float64: 6}})
+		if err != nil {
+			t.Fatalf("adding beer: %v", err)
+		}
+		_, err = bs.UpdateBeer(ctx, author.ID, db.UpdateBeerParams{
+			ID:       beer.ID,
+			Name:     sql.NullString{Valid: true, String: "Homebrew"},
+			BrewerID: sql.NullInt64{Valid: true, Int64: brewer.ID},
+			Style:    sql.NullString{Valid: true, String: "Stout"},
+			Notes:    sql.NullString{Valid: true, String: "Added later"},
+		})
+		if err != nil {
+			t.Fatalf("updating beer: %v", err)
+		}
+		history, err := bs.GetBeerHistory(ctx, beer.ID)
+		if err != nil || len(history) != 2 {
+			t.Fatalf("getting beer history: got %d revisions, %v", len(history), err)
+		}
+
+		reverted, err := bs.RevertBeer(ctx, author.ID, beer.ID, history[1].BeerRevision.ID)
+		if err != nil {
+			t.Fatalf("reverting beer: %v", err)
+		}
+		if reverted.BrewerID.Valid || reverted.Style.Valid || reverted.Notes.Valid {
+			t.Errorf("reverted beer kept fields which were empty: got %+v", reverted)
+		}
+		if got, _ := bs.GetBeer(ctx, beer.ID); got.BrewerID.Valid || got.Style.Valid || got.Notes.Valid {
+			t.Errorf("getting reverted beer: got %+v", got)
+		}
+	})
+}
+
+func TestPublishingStores(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bus := events.NewBus()
+		received, unsubscribe := bus.Subscribe(10)
+		defer unsubscribe()
+		bs := brewers.NewPublishingBrewerStore(stores.Brewers, bus)
+		beerStore := beers.NewPublishingBeerStore(stores.Beers, bus)
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		felons, _ := bs.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		pale, err := beerStore.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", Abv: 4.8, Rating: sql.NullFloat64{Valid: true, Float64: 7}, BrewerID: sql.NullInt64{Valid: true, Int64: felons.ID}})
+		if err != nil {
+			t.Fatalf("adding beer: %v", err)
+		}
+		beerStore.UpdateBeer(ctx, alice.ID, db.UpdateBeerParams{ID: pale.ID, Name: sql.NullString{Valid: true, String: "Pale Ale"}})
+		history, err := beerStore.GetBeerHistory(ctx, pale.ID)
+		if err != nil || len(history) != 2 {
+			t.Fatalf("getting beer history: got %d revisions, %v", len(history), err)
+		}
+		beerStore.RevertBeer(ctx, alice.ID, pale.ID, history[1].BeerRevision.ID)
+		beerStore.DeleteBeer(ctx, pale.ID)
+		beerStore.RestoreBeer(ctx, pale.ID)
+		bs.DeleteBrewer(ctx, felons.ID)
+		bs.RestoreBrewer(ctx, felons.ID)
+
+		// Changes which don't go through aren't published
+		if _, err := bs.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"}); err == nil {
+			t.Error("adding the same brewer again: got no error")
+		}
+		if _, err := beerStore.DeleteBeer(ctx, 999); err == nil {
+			t.Error("deleting a missing beer: got no error")
+		}
+
+		want := []events.Event{
+			{Topic: events.Brewers, Action: events.Created, ID: felons.ID},
+			{Topic: events.Beers, Action: events.Created, ID: pale.ID},
+			{Topic: events.Beers, Action: events.Updated, ID: pale.ID},
+			{Topic: events.Beers, Action: events.Updated, ID: pale.ID},
+			{Topic: events.Beers, Action: events.Deleted, ID: pale.ID},
+			{Topic: events.Beers, Action: events.Restored, ID: pale.ID},
+			{Topic: events.Brewers, Action: events.Deleted, ID: felons.ID},
+			{Topic: events.Brewers, Action: events.Restored, ID: felons.ID},
+		}
+		got := []events.Event{}
+		for len(received) > 0 {
+			got = append(got, <-received)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got events %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestStyleStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Styles
+
+		if _, err := ss.AddStyle(ctx, db.AddStyleParams{Code: "1A", Category: "Lager", AbvMin: 3, AbvMax: 4}); err != (store.ErrMissingField{Field: "name"}) {
+			t.Errorf("adding style without name: got %v", err)
+		}
+		if _, err := ss.AddStyle(ctx, db.AddStyleParams{Code: "1A", Category: "Lager", Name: "Lager", AbvMin: 5, AbvMax: 4}); err == nil {
+			t.Errorf("adding style with backwards ABV range: expected an error")
+		}
+
+		added, err := styles.Seed(ctx, ss)
+		if err != nil {
+			t.Fatalf("seeding styles: %v", err)
+		}
+		if added != len(styles.Guidelines()) {
+			t.Errorf("seeding styles: added %d, want %d", added, len(styles.Guidelines()))
+		}
+
+		// Seeding again only adds what's missing
+		if added, err := styles.Seed(ctx, ss); err != nil || added != 0 {
+			t.Errorf("seeding styles again: added %d, %v", added, err)
+		}
+		all, _ := ss.GetStyles(ctx)
+		if len(all) != len(styles.Guidelines()) || all[0].Code != "1A" {
+			t.Fatalf("getting styles: got %d starting with %+v", len(all), all[0])
+		}
+
+		ipa, err := ss.GetStyleByName(ctx, "american ipa")
+		if err != nil || ipa.Code != "21A" || ipa.AbvMin != 5.5 || ipa.AbvMax != 7.5 {
+			t.Errorf("getting style by name: got %+v, %v", ipa, err)
+		}
+		if _, err := ss.GetStyleByName(ctx, "Pastry Sour"); err != (styles.ErrStyleNotFound{Name: "Pastry Sour"}) {
+			t.Errorf("getting missing style by name: got %v", err)
+		}
+		if got, err := ss.GetStyle(ctx, ipa.ID); err != nil || got != ipa {
+			t.Errorf("getting style: got %+v, %v", got, err)
+		}
+
+		found, _ := ss.SearchStyles(ctx, "IPA", 3)
+		if len(found) != 3 || found[0].Name != "English IPA" {
+			t.Errorf("searching styles: got %+v", found)
+		}
+		if found, _ := ss.SearchStyles(ctx, "21a", 10); len(found) != 1 || found[0].ID != ipa.ID {
+			t.Errorf("searching styles by code: got %+v", found)
+		}
+	})
+}
+
+func TestMigrateBeerStyles(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		if _, err := styles.Seed(ctx, stores.Styles); err != nil {
+			t.Fatal(err)
+		}
+
+		var first db.Beer
+		for i, style := range []string{"ipa", "India Pale Ale", "American IPA", "hefeweizen", "Pastry Sour", ""} {
+			beer, err := stores.Beers.AddBeer(ctx, 0, db.AddBeerParams{
+				Name:   fmt.Sprintf("Beer %d", i),
+				Style:  sql.NullString{Valid: true, String: style},
+				Rating: sql.NullFloat64{Valid: true, Float64: 5},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 {
+				first = beer
+			}
+		}
+
+		migrated, err := styles.MigrateBeerStyles(ctx, stores.Styles, stores.Beers, log.New(io.Discard, "", 0))
+		if err != nil || migrated != 3 {
+			t.Errorf("migrating styles: migrated %d, %v", migrated, err)
+		}
+
+		got, _ := stores.Beers.GetBeerStyles(ctx)
+		want := []string{"American IPA", "Pastry Sour", "Weissbier"}
+		if !slices.Equal(got, want) {
+			t.Errorf("beer styles after migrating: got %v, want %v", got, want)
+		}
+
+		// The new style is recorded in the beer's history, by no one in particular
+		history, err := stores.Beers.GetBeerHistory(ctx, first.ID)
+		if err != nil || len(history) != 2 {
+			t.Fatalf("getting history after migrating: got %d revisions, %v", len(history), err)
+		}
+		if latest := history[0].BeerRevision; latest.Style.String != "American IPA" || latest.UserID.Valid {
+			t.Errorf("revision after migrating: got %+v", latest)
+		}
+	})
+}
+
+func TestScorecardStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Scorecards
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		beer, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: sql.NullFloat64{Valid: true, Float64: 5}})
+
+		if weights, err := ss.GetWeights(ctx); err != nil || weights != scorecards.DefaultWeights {
+			t.Errorf("getting weights before they're set: got %v, %v", weights, err)
+		}
+		if _, err := ss.SetWeights(ctx, scorecards.Scores{}); err == nil {
+			t.Errorf("setting all weights to 0: expected an error")
+		}
+		if _, err := ss.SetWeights(ctx, scorecards.Scores{1, -1, 1, 1, 1}); err != (store.ErrInvalidField{Field: "appearance", Reason: "must be >= 0"}) {
+			t.Errorf("setting a negative weight: got %v", err)
+		}
+
+		if _, err := ss.SaveScorecard(ctx, beer.ID, alice.ID, scorecards.Scores{5, 5, 11, 5, 5}); err != (store.ErrInvalidField{Field: "flavour", Reason: "must be between 0 and 10"}) {
+			t.Errorf("saving scorecard with a score over 10: got %v", err)
+		}
+		if _, err := ss.SaveScorecard(ctx, 999, alice.ID, scorecards.Scores{5, 5, 5, 5, 5}); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("saving scorecard for a missing beer: got %v", err)
+		}
+
+		// The total is weighted by the BJCP points: (12*10 + 3*0 + 20*5 + 5*5 + 10*5) / 50
+		scorecard, err := ss.SaveScorecard(ctx, beer.ID, alice.ID, scorecards.Scores{10, 0, 5, 5, 5})
+		if err != nil || scorecard.Total != 5.9 || scorecard.Aroma != 10 {
+			t.Errorf("saving scorecard: got %+v, %v", scorecard, err)
+		}
+
+		// New weights apply to the scorecards already saved too
+		if _, err := ss.SetWeights(ctx, scorecards.Scores{1, 1, 1, 1, 1}); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := ss.GetScorecard(ctx, beer.ID, alice.ID); err != nil || got.Total != 5 {
+			t.Errorf("getting scorecard after changing weights: got %+v, %v", got, err)
+		}
+		if _, err := ss.GetScorecard(ctx, beer.ID, bob.ID); err != (scorecards.ErrScorecardNotFound{BeerID: beer.ID, UserID: bob.ID}) {
+			t.Errorf("getting missing scorecard: got %v", err)
+		}
+
+		// Saving again replaces the user's scorecard
+		if _, err := ss.SaveScorecard(ctx, beer.ID, alice.ID, scorecards.Scores{4, 4, 4, 4, 4}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ss.SaveScorecard(ctx, beer.ID, bob.ID, scorecards.Scores{8, 6, 8, 8, 10}); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, _ := ss.GetBeerScorecards(ctx, beer.ID)
+		if len(rows) != 2 || rows[0].Username != "bob" || rows[0].Scorecard.Total != 8 || rows[1].Username != "alice" {
+			t.Errorf("getting beer scorecards: got %+v", rows)
+		}
+
+		summary, err := ss.GetBeerSummary(ctx, beer.ID)
+		want := scorecards.Summary{Count: 2, Averages: scorecards.Scores{6, 5, 6, 6, 7}, Total: 6}
+		if err != nil || summary != want {
+			t.Errorf("getting beer summary: got %+v, %v, want %+v", summary, err, want)
+		}
+		if summary, _ := ss.GetBeerSummary(ctx, 999); summary.Count != 0 {
+			t.Errorf("getting summary of a beer without scorecards: got %+v", summary)
+		}
+
+		// Changing the weights works out the totals and the beer's rating again
+		if _, err := ss.SetWeights(ctx, scorecards.Scores{0, 0, 0, 0, 1}); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := ss.GetScorecard(ctx, beer.ID, bob.ID); err != nil || got.Total != 10 {
+			t.Errorf("getting scorecard after changing weights again: got %+v, %v", got, err)
+		}
+		if summary, _ := ss.GetBeerSummary(ctx, beer.ID); summary.Total != 7 {
+			t.Errorf("getting beer summary after changing weights: got %+v, want a total of 7", summary)
+		}
+		if got, err := stores.Beers.GetBeer(ctx, beer.ID); err != nil || got.Rating != (sql.NullFloat64{Valid: true, Float64: 7}) {
+			t.Errorf("getting beer after changing weights: got %+v, %v, want it rated 7", got, err)
+		}
+	})
+}
+
+func TestTagStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ts := stores.Tags
+
+		added, err := tags.Seed(ctx, ts)
+		if err != nil || added == 0 {
+			t.Fatalf("seeding tags: added %d, %v", added, err)
+		}
+		if added, err := tags.Seed(ctx, ts); err != nil || added != 0 {
+			t.Errorf("seeding tags again: added %d, %v", added, err)
+		}
+
+		if _, err := ts.AddTag(ctx, db.AddTagParams{Name: "  "}); err != (store.ErrMissingField{Field: "name"}) {
+			t.Errorf("adding blank tag: got %v", err)
+		}
+		if _, err := ts.AddTag(ctx, db.AddTagParams{Name: "Citrus"}); err != (tags.ErrTagAlreadyExists{Name: "citrus"}) {
+			t.Errorf("adding existing tag: got %v", err)
+		}
+		citrus, err := ts.GetTagByName(ctx, "CITRUS")
+		if err != nil || citrus.Category.String != "Fruity" {
+			t.Errorf("getting tag by name: got %+v, %v", citrus, err)
+		}
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		rating := sql.NullFloat64{Valid: true, Float64: 7}
+		pale, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+
+		if _, err := ts.SetBeerTags(ctx, 999, []string{"citrus"}); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("tagging missing beer: got %v", err)
+		}
+
+		// Unknown tags are added without a category
+		paleTags, err := ts.SetBeerTags(ctx, pale.ID, []string{"piney", "Passion Fruit", "citrus"})
+		if err != nil || len(paleTags) != 3 || paleTags[0].Name != "citrus" || paleTags[1].Name != "passion fruit" || paleTags[1].Category.Valid {
+			t.Errorf("tagging beer: got %+v, %v", paleTags, err)
+		}
+		if _, err := ts.SetBeerTags(ctx, stout.ID, []string{"roasty", "citrus"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Setting the tags again replaces them
+		if paleTags, _ := ts.SetBeerTags(ctx, pale.ID, []string{"citrus", "piney"}); len(paleTags) != 2 {
+			t.Errorf("retagging beer: got %+v", paleTags)
+		}
+
+		byBeer, _ := ts.GetTagsByBeer(ctx)
+		if len(byBeer[pale.ID]) != 2 || len(byBeer[stout.ID]) != 2 || byBeer[stout.ID][1].Name != "roasty" {
+			t.Errorf("getting tags by beer: got %+v", byBeer)
+		}
+
+		if tagged, _ := ts.GetBeersByTag(ctx, "Citrus"); len(tagged) != 2 || tagged[0].ID != pale.ID {
+			t.Errorf("getting beers by tag: got %+v", tagged)
+		}
+
+		// Deleted beers aren't found or counted
+		stores.Beers.DeleteBeer(ctx, stout.ID)
+		if tagged, _ := ts.GetBeersByTag(ctx, "roasty"); len(tagged) != 0 {
+			t.Errorf("getting beers by tag after deleting: got %+v", tagged)
+		}
+		counts, _ := ts.GetTagCounts(ctx)
+		got := map[string]int64{}
+		for _, c := range counts {
+			got[c.Tag.Name] = c.BeerCount
+		}
+		if got["citrus"] != 1 || got["roasty"] != 0 || got["passion fruit"] != 0 || len(counts) != added+1 {
+			t.Errorf("getting tag counts: got %v", got)
+		}
+	})
+}
+
+func TestPhotoStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ps := stores.Photos
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		rating := sql.NullFloat64{Valid: true, Float64: 7}
+		pale, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: rating})
+		userId := sql.NullInt64{Valid: true, Int64: author.ID}
+
+		params := func(beerId int64, key string) db.AddLabelPhotoParams {
+			return db.AddLabelPhotoParams{
+				BeerID: beerId, UserID: userId, ContentType: "image/png", Size: 100, Width: 20, Height: 10,
+				BlobKey: key + ".png", ThumbKey: key + "-thumb.jpeg",
+			}
+		}
+
+		if _, err := ps.AddPhoto(ctx, params(999, "missing")); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("adding photo of missing beer: got %v", err)
+		}
+		front, err := ps.AddPhoto(ctx, params(pale.ID, "front"))
+		if err != nil || front.BlobKey != "front.png" || front.Width != 20 {
+			t.Fatalf("adding photo: got %+v, %v", front, err)
+		}
+		back, _ := ps.AddPhoto(ctx, params(pale.ID, "back"))
+
+		if got, err := ps.GetPhoto(ctx, front.ID); err != nil || got.ThumbKey != "front-thumb.jpeg" {
+			t.Errorf("getting photo: got %+v, %v", got, err)
+		}
+		if _, err := ps.GetPhoto(ctx, 999); err != (photos.ErrPhotoNotFound{ID: 999}) {
+			t.Errorf("getting missing photo: got %v", err)
+		}
+		if got, _ := ps.GetBeerPhotos(ctx, pale.ID); len(got) != 2 || got[0].ID != front.ID {
+			t.Errorf("getting beer photos: got %+v", got)
+		}
+
+		if deleted, err := ps.DeletePhoto(ctx, front.ID); err != nil || deleted.BlobKey != "front.png" {
+			t.Errorf("deleting photo: got %+v, %v", deleted, err)
+		}
+		if _, err := ps.DeletePhoto(ctx, front.ID); err != (photos.ErrPhotoNotFound{ID: front.ID}) {
+			t.Errorf("deleting photo again: got %v", err)
+		}
+
+		if deleted, err := ps.DeleteBeerPhotos(ctx, pale.ID); err != nil || len(deleted) != 1 || deleted[0].ID != back.ID {
+			t.Errorf("deleting beer photos: got %+v, %v", deleted, err)
+		}
+		if got, _ := ps.GetBeerPhotos(ctx, pale.ID); len(got) != 0 {
+			t.Errorf("getting beer photos after deleting: got %+v", got)
+		}
+	})
+}
+
+func TestBarcodeStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Barcodes
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		rating := sql.NullFloat64{Valid: true, Float64: 7}
+		pale, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+
+		if _, err := bs.AddBarcode(ctx, "96385074", 999); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("adding barcode to missing beer: got %v", err)
+		}
+		if barcode, err := bs.AddBarcode(ctx, "96385074", pale.ID); err != nil || barcode.BeerID != pale.ID {
+			t.Fatalf("adding barcode: got %+v, %v", barcode, err)
+		}
+		bs.AddBarcode(ctx, "4006381333931", pale.ID)
+		if _, err := bs.AddBarcode(ctx, "96385074", stout.ID); err != (barcodes.ErrBarcodeAlreadyExists{Code: "96385074"}) {
+			t.Errorf("adding barcode of another beer: got %v", err)
+		}
+
+		if barcode, err := bs.GetBarcode(ctx, "96385074"); err != nil || barcode.BeerID != pale.ID {
+			t.Errorf("getting barcode: got %+v, %v", barcode, err)
+		}
+		if _, err := bs.GetBarcode(ctx, "0036000291452"); err != (barcodes.ErrBarcodeNotFound{Code: "0036000291452"}) {
+			t.Errorf("getting missing barcode: got %v", err)
+		}
+		if got, _ := bs.GetBeerBarcodes(ctx, pale.ID); len(got) != 2 || got[0].Code != "4006381333931" {
+			t.Errorf("getting beer barcodes: got %+v", got)
+		}
+
+		// The barcodes of beers in the trash aren't found, and can be put on other beers
+		stores.Beers.DeleteBeer(ctx, pale.ID)
+		if _, err := bs.GetBarcode(ctx, "96385074"); err != (barcodes.ErrBarcodeNotFound{Code: "96385074"}) {
+			t.Errorf("getting barcode of deleted beer: got %v", err)
+		}
+		if barcode, err := bs.AddBarcode(ctx, "96385074", stout.ID); err != nil || barcode.BeerID != stout.ID {
+			t.Errorf("taking barcode of deleted beer: got %+v, %v", barcode, err)
+		}
+		if got, _ := bs.GetBeerBarcodes(ctx, pale.ID); len(got) != 1 {
+			t.Errorf("getting barcodes of deleted beer: got %+v", got)
+		}
+
+		if barcode, err := bs.DeleteBarcode(ctx, "96385074"); err != nil || barcode.BeerID != stout.ID {
+			t.Errorf("deleting barcode: got %+v, %v", barcode, err)
+		}
+		if _, err := bs.DeleteBarcode(ctx, "96385074"); err != (barcodes.ErrBarcodeNotFound{Code: "96385074"}) {
+			t.Errorf("deleting barcode again: got %v", err)
+		}
+	})
+}
+
+func TestStockStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Stock
+
+		author, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		rating := sql.NullFloat64{Valid: true, Float64: 7}
+		pale, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, author.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+
+		day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+		bestBefore := func(d int) sql.NullTime { return sql.NullTime{Valid: true, Time: day(d)} }
+		params := func(beerId int64, quantity int64, bb sql.NullTime) db.AddStockParams {
+			return db.AddStockParams{
+				BeerID: beerId, Quantity: quantity, ContainerMl: 375, PurchasedOn: day(1), BestBefore: bb,
+				Price: sql.NullFloat64{Valid: true, Float64: 4.5}, Currency: "aud",
+			}
+		}
+		badCurrency := params(pale.ID, 1, sql.NullTime{})
+		badCurrency.Currency = "A$"
+
+		for _, tc := range []struct {
+			params db.AddStockParams
+			want   error
+		}{
+			{params(999, 1, bestBefore(20)), beers.ErrBeerNotFound{ID: 999}},
+			{params(pale.ID, 0, bestBefore(20)), store.ErrInvalidField{Field: "quantity", Reason: "must be at least 1"}},
+			{params(pale.ID, 1, sql.NullTime{Valid: true, Time: day(1).Add(-time.Hour)}), store.ErrInvalidField{Field: "best-before", Reason: "must not be before it was bought"}},
+			{badCurrency, store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}},
+		} {
+			if _, err := ss.AddStock(ctx, tc.params); err != tc.want {
+				t.Errorf("adding stock %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		// Times are stored as the day they're on
+		lateBatch := params(pale.ID, 2, sql.NullTime{Valid: true, Time: day(30).Add(15 * time.Hour)})
+		late, err := ss.AddStock(ctx, lateBatch)
+		if err != nil || !late.BestBefore.Time.Equal(day(30)) || !late.PurchasedOn.Equal(day(1)) || late.Bought != 2 || late.Currency != "AUD" {
+			t.Fatalf("adding stock: got %+v, %v", late, err)
+		}
+		early, _ := ss.AddStock(ctx, params(pale.ID, 1, bestBefore(10)))
+		undated, _ := ss.AddStock(ctx, params(stout.ID, 6, sql.NullTime{}))
+
+		if got, err := ss.GetStock(ctx, early.ID); err != nil || got.Quantity != 1 || got.ContainerMl != 375 {
+			t.Errorf("getting stock: got %+v, %v", got, err)
+		}
+		if _, err := ss.GetStock(ctx, 999); err != (stock.ErrStockNotFound{ID: 999}) {
+			t.Errorf("getting missing stock: got %v", err)
+		}
+
+		fridge, _ := ss.GetFridge(ctx)
+		if len(fridge) != 3 || fridge[0].Stock.ID != early.ID || fridge[1].Stock.ID != late.ID || fridge[2].Stock.ID != undated.ID || fridge[2].BeerName != "Stout" {
+			t.Errorf("getting fridge: got %+v", fridge)
+		}
+		if levels, _ := ss.GetStockLevels(ctx); levels[pale.ID] != 3 || levels[stout.ID] != 6 {
+			t.Errorf("getting stock levels: got %v", levels)
+		}
+
+		// Beers are taken from the entry which goes off first, which is kept once it's empty, but
+		// isn't in the fridge
+		if taken, err := ss.TakeOne(ctx, pale.ID); err != nil || taken.ID != early.ID || taken.Quantity != 0 {
+			t.Errorf("taking one: got %+v, %v", taken, err)
+		}
+		if got, err := ss.GetStock(ctx, early.ID); err != nil || got.Quantity != 0 || got.Bought != 1 {
+			t.Errorf("getting empty stock: got %+v, %v", got, err)
+		}
+		if fridge, _ := ss.GetFridge(ctx); len(fridge) != 2 {
+			t.Errorf("getting fridge after emptying an entry: got %+v", fridge)
+		}
+		ss.TakeOne(ctx, pale.ID)
+		ss.TakeOne(ctx, pale.ID)
+		if _, err := ss.TakeOne(ctx, pale.ID); err != (stock.ErrOutOfStock{BeerID: pale.ID}) {
+			t.Errorf("taking one when out of stock: got %v", err)
+		}
+		if levels, _ := ss.GetStockLevels(ctx); len(levels) != 1 {
+			t.Errorf("getting stock levels after running out: got %v", levels)
+		}
+
+		// Beers in the trash aren't in the fridge
+		stores.Beers.DeleteBeer(ctx, stout.ID)
+		if fridge, _ := ss.GetFridge(ctx); len(fridge) != 0 {
+			t.Errorf("getting fridge after deleting beer: got %+v", fridge)
+		}
+		stores.Beers.RestoreBeer(ctx, stout.ID)
+
+		if deleted, err := ss.DeleteStock(ctx, undated.ID); err != nil || deleted.Quantity != 6 {
+			t.Errorf("deleting stock: got %+v, %v", deleted, err)
+		}
+		if _, err := ss.DeleteStock(ctx, undated.ID); err != (stock.ErrStockNotFound{ID: undated.ID}) {
+			t.Errorf("deleting stock again: got %v", err)
+		}
+	})
+}
+
+func TestStockSpending(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Stock
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		felons, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		stone, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Stone & Wood"})
+		rating := sql.NullFloat64{Valid: true, Float64: 7}
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{
+			Name: "Pale", Abv: 5, Rating: rating,
+			BrewerID: sql.NullInt64{Valid: true, Int64: felons.ID}, Style: sql.NullString{Valid: true, String: "Pale Ale"},
+		})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{
+			Name: "Stout", Abv: 6, Rating: rating,
+			BrewerID: sql.NullInt64{Valid: true, Int64: stone.ID}, Style: sql.NullString{Valid: true, String: "Stout"},
+		})
+
+		buy := func(user db.User, beer db.Beer, quantity int64, month time.Month, day int, price float64, currency string) {
+			t.Helper()
+			params := db.AddStockParams{
+				BeerID: beer.ID, UserID: sql.NullInt64{Valid: true, Int64: user.ID}, Quantity: quantity,
+				ContainerMl: 375, PurchasedOn: time.Date(2025, month, day, 0, 0, 0, 0, time.UTC), Currency: currency,
+			}
+			if price >= 0 {
+				params.Price = sql.NullFloat64{Valid: true, Float64: price}
+			}
+			if _, err := ss.AddStock(ctx, params); err != nil {
+				t.Fatalf("adding stock: %v", err)
+			}
+		}
+		buy(alice, pale, 4, time.March, 1, 4.5, "AUD")
+		buy(alice, pale, 1, time.March, 2, -1, "AUD")
+		buy(bob, stout, 6, time.March, 3, 5, "AUD")
+		buy(alice, stout, 2, time.March, 5, 3, "EUR")
+		buy(alice, pale, 1, time.February, 10, 10, "AUD")
+		buy(alice, pale, 1, time.January, 1, 100, "AUD")
+
+		// What's been drunk and what's in the trash has still been paid for
+		ss.TakeOne(ctx, pale.ID)
+		stores.Beers.DeleteBeer(ctx, stout.ID)
+
+		since := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+		for _, tc := range []struct {
+			groupBy stock.GroupBy
+			want    []string
+		}{
+			{stock.ByUser, []string{"2025-03 AUD bob 30.00", "2025-03 AUD alice 18.00", "2025-03 EUR alice 6.00", "2025-02 AUD alice 10.00"}},
+			{stock.ByBrewer, []string{"2025-03 AUD Stone & Wood 30.00", "2025-03 AUD Felon's 18.00", "2025-03 EUR Stone & Wood 6.00", "2025-02 AUD Felon's 10.00"}},
+			{stock.ByStyle, []string{"2025-03 AUD Stout 30.00", "2025-03 AUD Pale Ale 18.00", "2025-03 EUR Stout 6.00", "2025-02 AUD Pale Ale 10.00"}},
+		} {
+			spending, err := ss.GetSpending(ctx, tc.groupBy, since)
+			if err != nil {
+				t.Fatalf("getting spending by %d: %v", tc.groupBy, err)
+			}
+			got := []string{}
+			for _, s := range spending {
+				got = append(got, fmt.Sprintf("%s %s %s %.2f", s.Month, s.Currency, s.Name, s.Total))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("getting spending by %d: got %q, want %q", tc.groupBy, got, tc.want)
+			}
+			if s := spending[0]; s.Containers != 6 || s.AlcoholMl != 135 {
+				t.Errorf("getting spending by %d: got %+v", tc.groupBy, s)
+			}
+		}
+
+		march := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+		if got, err := ss.GetUserSpending(ctx, alice.ID, march); err != nil || len(got) != 2 || got["AUD"] != 18 || got["EUR"] != 6 {
+			t.Errorf("getting user spending: got %v, %v", got, err)
+		}
+		if got, _ := ss.GetUserSpending(ctx, 999, march); len(got) != 0 {
+			t.Errorf("getting spending of user who hasn't bought anything: got %v", got)
+		}
+
+		purchases, err := ss.GetPurchases(ctx, since)
+		if err != nil || len(purchases) != 5 {
+			t.Fatalf("getting purchases: got %+v, %v", purchases, err)
+		}
+		if p := purchases[0]; p.BeerName != "Pale" || p.BrewerName.String != "Felon's" || p.Username.String != "alice" || p.Abv != 5 {
+			t.Errorf("getting purchases: got %+v first", p)
+		}
+		if p := purchases[2]; p.Stock.Price.Valid || p.Stock.Bought != 1 {
+			t.Errorf("getting purchases: got %+v third", p)
+		}
+	})
+}
+
+func TestBudgetStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Budgets
+
+		user, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+
+		for _, tc := range []struct {
+			params db.SetBudgetParams
+			want   error
+		}{
+			{db.SetBudgetParams{UserID: user.ID, Amount: 0, Currency: "AUD"}, store.ErrInvalidField{Field: "amount", Reason: "must be more than 0"}},
+			{db.SetBudgetParams{UserID: user.ID, Amount: 100, Currency: "dollars"}, store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}},
+			{db.SetBudgetParams{UserID: 999, Amount: 100, Currency: "AUD"}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := bs.SetBudget(ctx, tc.params); err != tc.want {
+				t.Errorf("setting budget %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		if _, err := bs.GetBudget(ctx, user.ID); err != (budgets.ErrBudgetNotFound{UserID: user.ID}) {
+			t.Errorf("getting missing budget: got %v", err)
+		}
+		if budget, err := bs.SetBudget(ctx, db.SetBudgetParams{UserID: user.ID, Amount: 100, Currency: "aud"}); err != nil || budget.Currency != "AUD" {
+			t.Errorf("setting budget: got %+v, %v", budget, err)
+		}
+		// Setting it again replaces it
+		bs.SetBudget(ctx, db.SetBudgetParams{UserID: user.ID, Amount: 80, Currency: "NZD"})
+		if budget, err := bs.GetBudget(ctx, user.ID); err != nil || budget.Amount != 80 || budget.Currency != "NZD" {
+			t.Errorf("getting budget: got %+v, %v", budget, err)
+		}
+
+		if budget, err := bs.DeleteBudget(ctx, user.ID); err != nil || budget.Amount != 80 {
+			t.Errorf("deleting budget: got %+v, %v", budget, err)
+		}
+		if _, err := bs.DeleteBudget(ctx, user.ID); err != (budgets.ErrBudgetNotFound{UserID: user.ID}) {
+			t.Errorf("deleting budget again: got %v", err)
+		}
+	})
+}
+
+func TestGoalStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		gs := stores.Goals
+
+		user, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		limit := sql.NullFloat64{Valid: true, Float64: 10}
+
+		for _, tc := range []struct {
+			params db.SetGoalsParams
+			want   error
+		}{
+			{db.SetGoalsParams{UserID: user.ID, WeeklyLimit: sql.NullFloat64{Valid: true, Float64: 0}}, store.ErrInvalidField{Field: "weekly-limit", Reason: "must be more than 0"}},
+			{db.SetGoalsParams{UserID: user.ID, AlcoholFreeDays: sql.NullInt64{Valid: true, Int64: 8}}, store.ErrInvalidField{Field: "alcohol-free-days", Reason: "must be between 1 and 7"}},
+			{db.SetGoalsParams{UserID: user.ID, TimeZone: "Middle/Earth"}, store.ErrInvalidField{Field: "time-zone", Reason: "must be a time zone like Australia/Sydney"}},
+			{db.SetGoalsParams{UserID: 999, WeeklyLimit: limit}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := gs.SetGoals(ctx, tc.params); err != tc.want {
+				t.Errorf("setting goals %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		if _, err := gs.GetGoals(ctx, user.ID); err != (goals.ErrGoalsNotFound{UserID: user.ID}) {
+			t.Errorf("getting missing goals: got %v", err)
+		}
+		params := db.SetGoalsParams{UserID: user.ID, WeeklyLimit: limit, ConfirmOverLimit: true, TimeZone: " Australia/Sydney "}
+		if got, err := gs.SetGoals(ctx, params); err != nil || got.TimeZone != "Australia/Sydney" || !got.ConfirmOverLimit {
+			t.Errorf("setting goals: got %+v, %v", got, err)
+		}
+		// Setting them again replaces them, including clearing the limit
+		params = db.SetGoalsParams{UserID: user.ID, AlcoholFreeDays: sql.NullInt64{Valid: true, Int64: 3}}
+		gs.SetGoals(ctx, params)
+		if got, err := gs.GetGoals(ctx, user.ID); err != nil || got.WeeklyLimit.Valid || got.AlcoholFreeDays.Int64 != 3 || got.ConfirmOverLimit || got.TimeZone != "" {
+			t.Errorf("getting goals: got %+v, %v", got, err)
+		}
+
+		if got, err := gs.DeleteGoals(ctx, user.ID); err != nil || got.AlcoholFreeDays.Int64 != 3 {
+			t.Errorf("deleting goals: got %+v, %v", got, err)
+		}
+		if _, err := gs.DeleteGoals(ctx, user.ID); err != (goals.ErrGoalsNotFound{UserID: user.ID}) {
+			t.Errorf("deleting goals again: got %v", err)
+		}
+	})
+}
+
+func TestScheduleStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Schedules
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		friday := int64(time.Friday)
+
+		for _, tc := range []struct {
+			params db.AddScheduleParams
+			want   error
+		}{
+			{db.AddScheduleParams{UserID: alice.ID, Weekday: 7, Minute: 17 * 60}, store.ErrInvalidField{Field: "weekday", Reason: "must be a day of the week"}},
+			{db.AddScheduleParams{UserID: alice.ID, Weekday: friday, Minute: 24 * 60}, store.ErrInvalidField{Field: "time", Reason: "must be a time of day"}},
+			{db.AddScheduleParams{UserID: alice.ID, Weekday: friday, Minute: 17 * 60, RemindBefore: -1}, store.ErrInvalidField{Field: "remind-before", Reason: "must be between 0 and 1440 minutes"}},
+			{db.AddScheduleParams{UserID: 999, Weekday: friday, Minute: 17 * 60}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ss.AddSchedule(ctx, tc.params); err != tc.want {
+				t.Errorf("adding schedule %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		fridayKnockOff, err := ss.AddSchedule(ctx, db.AddScheduleParams{UserID: alice.ID, Weekday: friday, Minute: 17 * 60, RemindBefore: 30})
+		if err != nil {
+			t.Fatalf("adding schedule: %v", err)
+		}
+		want := schedules.ErrScheduleAlreadyExists{Weekday: time.Friday, Minute: 17 * 60}
+		if _, err := ss.AddSchedule(ctx, db.AddScheduleParams{UserID: alice.ID, Weekday: friday, Minute: 17 * 60}); err != want {
+			t.Errorf("adding duplicate schedule: got %v, want %v", err, want)
+		}
+		sunday, _ := ss.AddSchedule(ctx, db.AddScheduleParams{UserID: alice.ID, Weekday: int64(time.Sunday), Minute: 14 * 60})
+		ss.AddSchedule(ctx, db.AddScheduleParams{UserID: bob.ID, Weekday: friday, Minute: 17 * 60})
+
+		if got, err := ss.GetUserSchedules(ctx, alice.ID); err != nil || len(got) != 2 || got[0].ID != sunday.ID || got[1].ID != fridayKnockOff.ID {
+			t.Errorf("getting user schedules: got %+v, %v", got, err)
+		}
+
+		// Everyone's schedules come with where they are, and deleted users don't get reminders
+		stores.Goals.SetGoals(ctx, db.SetGoalsParams{UserID: alice.ID, TimeZone: "Australia/Sydney"})
+		stores.Users.DeleteUser(ctx, bob.ID)
+		all, err := ss.GetAllSchedules(ctx)
+		if err != nil || len(all) != 2 {
+			t.Fatalf("getting all schedules: got %+v, %v", all, err)
+		}
+		if all[0].Username != "alice" || all[0].TimeZone != "Australia/Sydney" || all[1].Schedule.ID != sunday.ID {
+			t.Errorf("getting all schedules: got %+v", all)
+		}
+
+		remindedAt := time.Date(2025, time.March, 14, 6, 30, 0, 0, time.UTC)
+		if err := ss.SetScheduleReminded(ctx, fridayKnockOff.ID, remindedAt); err != nil {
+			t.Errorf("setting schedule reminded: %v", err)
+		}
+		if got, err := ss.GetSchedule(ctx, fridayKnockOff.ID); err != nil || !got.RemindedAt.Valid || !got.RemindedAt.Time.Equal(remindedAt) {
+			t.Errorf("getting reminded schedule: got %+v, %v", got, err)
+		}
+
+		if _, err := ss.DeleteSchedule(ctx, sunday.ID); err != nil {
+			t.Errorf("deleting schedule: %v", err)
+		}
+		if _, err := ss.GetSchedule(ctx, sunday.ID); err != (schedules.ErrScheduleNotFound{ID: sunday.ID}) {
+			t.Errorf("getting deleted schedule: got %v", err)
+		}
+		if _, err := ss.DeleteSchedule(ctx, sunday.ID); err != (schedules.ErrScheduleNotFound{ID: sunday.ID}) {
+			t.Errorf("deleting schedule again: got %v", err)
+		}
+	})
+}
+
+func TestEmailStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		es := stores.Emails
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+
+		for _, tc := range []struct {
+			params db.SetUserEmailParams
+			want   error
+		}{
+			{db.SetUserEmailParams{UserID: alice.ID, Email: " "}, store.ErrMissingField{Field: "email"}},
+			{db.SetUserEmailParams{UserID: alice.ID, Email: "alice"}, store.ErrInvalidField{Field: "email", Reason: "must be an email address like someone@example.com"}},
+			{db.SetUserEmailParams{UserID: alice.ID, Email: "Alice <alice@example.com>"}, store.ErrInvalidField{Field: "email", Reason: "must be an email address like someone@example.com"}},
+			{db.SetUserEmailParams{UserID: 999, Email: "nobody@example.com"}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := es.SetUserEmail(ctx, tc.params); err != tc.want {
+				t.Errorf("setting email %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		if _, err := es.GetUserEmail(ctx, alice.ID); err != (emails.ErrUserEmailNotFound{UserID: alice.ID}) {
+			t.Errorf("getting missing email: got %v", err)
+		}
+		got, err := es.SetUserEmail(ctx, db.SetUserEmailParams{UserID: alice.ID, Email: " Alice@Example.com ", WeeklySummary: true, Reminders: true})
+		if err != nil || got.Email != "alice@example.com" || !got.WeeklySummary || !got.Reminders {
+			t.Errorf("setting email: got %+v, %v", got, err)
+		}
+		// Addresses can't be shared, whatever their case
+		want := emails.ErrEmailAlreadyUsed{Email: "alice@example.com"}
+		if _, err := es.SetUserEmail(ctx, db.SetUserEmailParams{UserID: bob.ID, Email: "ALICE@example.com"}); err != want {
+			t.Errorf("setting someone else's email: got %v, want %v", err, want)
+		}
+		es.SetUserEmail(ctx, db.SetUserEmailParams{UserID: bob.ID, Email: "bob@example.com", WeeklySummary: true})
+
+		if got, err := es.GetUserEmailByAddress(ctx, "ALICE@example.com"); err != nil || got.UserID != alice.ID {
+			t.Errorf("getting email by address: got %+v, %v", got, err)
+		}
+		if _, err := es.GetUserEmailByAddress(ctx, "carol@example.com"); err != (emails.ErrEmailNotFound{Email: "carol@example.com"}) {
+			t.Errorf("getting missing email by address: got %v", err)
+		}
+
+		// Setting it again replaces the settings, but remembers when the summary was sent
+		sentOn := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+		if err := es.SetSummarySent(ctx, alice.ID, sentOn); err != nil {
+			t.Errorf("setting summary sent: %v", err)
+		}
+		es.SetUserEmail(ctx, db.SetUserEmailParams{UserID: alice.ID, Email: "alice@example.org"})
+		if got, err := es.GetUserEmail(ctx, alice.ID); err != nil || got.Email != "alice@example.org" || got.WeeklySummary || got.Reminders || !got.SummarySentOn.Time.Equal(sentOn) {
+			t.Errorf("getting email: got %+v, %v", got, err)
+		}
+
+		// Everyone's addresses come with where they are, and deleted users don't get emails
+		stores.Goals.SetGoals(ctx, db.SetGoalsParams{UserID: alice.ID, TimeZone: "Australia/Sydney"})
+		all, err := es.GetUserEmails(ctx)
+		if err != nil || len(all) != 2 || all[0].Username != "alice" || all[0].TimeZone != "Australia/Sydney" || all[1].UserEmail.Email != "bob@example.com" || all[1].TimeZone != "" {
+			t.Errorf("getting all emails: got %+v, %v", all, err)
+		}
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if all, err := es.GetUserEmails(ctx); err != nil || len(all) != 1 {
+			t.Errorf("getting all emails without deleted users: got %+v, %v", all, err)
+		}
+
+		if got, err := es.DeleteUserEmail(ctx, alice.ID); err != nil || got.Email != "alice@example.org" {
+			t.Errorf("deleting email: got %+v, %v", got, err)
+		}
+		if _, err := es.DeleteUserEmail(ctx, alice.ID); err != (emails.ErrUserEmailNotFound{UserID: alice.ID}) {
+			t.Errorf("deleting email again: got %v", err)
+		}
+	})
+}
+
+func TestResetStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		rs := stores.Resets
+
+		user, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "saltytaro", PasswordHash: "hash"})
+		now := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+
+		token, tokenHash, err := resets.NewToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokenHash == token || resets.HashToken(token) != tokenHash {
+			t.Errorf("got token %q with hash %q", token, tokenHash)
+		}
+
+		if _, err := rs.AddPasswordReset(ctx, db.AddPasswordResetParams{TokenHash: tokenHash, UserID: 999, ExpiresAt: now}); err != (users.ErrUserNotFound{ID: 999}) {
+			t.Errorf("adding reset for missing user: got %v", err)
+		}
+		reset, err := rs.AddPasswordReset(ctx, db.AddPasswordResetParams{TokenHash: tokenHash, UserID: user.ID, ExpiresAt: now.Add(resets.Lifetime)})
+		if err != nil {
+			t.Fatalf("adding reset: %v", err)
+		}
+
+		if got, err := rs.GetPasswordReset(ctx, tokenHash); err != nil || got.UserID != user.ID || !got.ExpiresAt.Equal(reset.ExpiresAt) || got.UsedAt.Valid {
+			t.Errorf("getting reset: got %+v, %v", got, err)
+		}
+		if _, err := rs.GetPasswordReset(ctx, resets.HashToken("guess")); err != (resets.ErrPasswordResetNotFound{}) {
+			t.Errorf("getting missing reset: got %v", err)
+		}
+		if !resets.Usable(reset, now) || resets.Usable(reset, now.Add(resets.Lifetime)) {
+			t.Errorf("reset expiring at %v isn't only usable before then", reset.ExpiresAt)
+		}
+
+		// Each link only works once
+		if err := rs.UsePasswordReset(ctx, tokenHash, now); err != nil {
+			t.Errorf("using reset: %v", err)
+		}
+		if err := rs.UsePasswordReset(ctx, tokenHash, now); err != (resets.ErrPasswordResetUsed{}) {
+			t.Errorf("using reset again: got %v", err)
+		}
+		if err := rs.UsePasswordReset(ctx, resets.HashToken("guess"), now); err != (resets.ErrPasswordResetNotFound{}) {
+			t.Errorf("using missing reset: got %v", err)
+		}
+		if got, _ := rs.GetPasswordReset(ctx, tokenHash); !got.UsedAt.Time.Equal(now) || resets.Usable(got, now) {
+			t.Errorf("getting used reset: got %+v", got)
+		}
+
+		if err := stores.Users.SetUserPassword(ctx, user.ID, "new hash"); err != nil {
+			t.Errorf("setting password: %v", err)
+		}
+		if got, _ := stores.Users.GetUserById(ctx, user.ID); got.PasswordHash != "new hash" {
+			t.Errorf("got password hash %q after setting it", got.PasswordHash)
+		}
+	})
+}
+
+func TestOutboxStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ob := stores.Outbox
+
+		now := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+		later := sql.NullTime{Valid: true, Time: now.Add(time.Hour)}
+
+		if _, err := ob.QueueEmail(ctx, db.QueueEmailParams{Subject: "Hello"}); err != (store.ErrMissingField{Field: "to-address"}) {
+			t.Errorf("queueing email without an address: got %v", err)
+		}
+		if _, err := ob.QueueEmail(ctx, db.QueueEmailParams{ToAddress: "a@example.com"}); err != (store.ErrMissingField{Field: "subject"}) {
+			t.Errorf("queueing email without a subject: got %v", err)
+		}
+
+		first, err := ob.QueueEmail(ctx, db.QueueEmailParams{ToAddress: "a@example.com", Subject: "First", TextBody: "text", HtmlBody: "<p>html</p>", NextAttemptAt: sql.NullTime{Valid: true, Time: now}})
+		if err != nil || first.Attempts != 0 || first.SentAt.Valid || first.TextBody != "text" || first.HtmlBody != "<p>html</p>" {
+			t.Fatalf("queueing email: got %+v, %v", first, err)
+		}
+		second, _ := ob.QueueEmail(ctx, db.QueueEmailParams{ToAddress: "b@example.com", Subject: "Second", NextAttemptAt: sql.NullTime{Valid: true, Time: now.Add(-time.Minute)}})
+		third, _ := ob.QueueEmail(ctx, db.QueueEmailParams{ToAddress: "c@example.com", Subject: "Third", NextAttemptAt: later})
+
+		// The longest waiting first, and not the ones which are for later
+		due, err := ob.GetDueEmails(ctx, now, 10)
+		if err != nil || len(due) != 2 || due[0].ID != second.ID || due[1].ID != first.ID {
+			t.Errorf("getting due emails: got %+v, %v", due, err)
+		}
+		if due, err := ob.GetDueEmails(ctx, now, 1); err != nil || len(due) != 1 {
+			t.Errorf("getting one due email: got %+v, %v", due, err)
+		}
+
+		if err := ob.SetEmailFailed(ctx, db.SetEmailFailedParams{LastError: "451 Try again later", NextAttemptAt: later, ID: second.ID}); err != nil {
+			t.Errorf("setting email failed: %v", err)
+		}
+		if err := ob.SetEmailSent(ctx, first.ID, now); err != nil {
+			t.Errorf("setting email sent: %v", err)
+		}
+		if due, err := ob.GetDueEmails(ctx, now, 10); err != nil || len(due) != 0 {
+			t.Errorf("getting due emails after sending: got %+v, %v", due, err)
+		}
+		due, err = ob.GetDueEmails(ctx, later.Time, 10)
+		if err != nil || len(due) != 2 || due[0].ID != second.ID || due[1].ID != third.ID {
+			t.Errorf("getting due emails later: got %+v, %v", due, err)
+		}
+
+		// Giving up on an email means it's never due again
+		ob.SetEmailFailed(ctx, db.SetEmailFailedParams{LastError: "550 No such user", ID: third.ID})
+
+		latest, err := ob.GetOutbox(ctx, 10)
+		if err != nil || len(latest) != 3 {
+			t.Fatalf("getting outbox: got %+v, %v", latest, err)
+		}
+		if got := latest[0]; got.ID != third.ID || got.Attempts != 1 || got.NextAttemptAt.Valid || got.LastError != "550 No such user" {
+			t.Errorf("got given up email %+v", got)
+		}
+		if got := latest[1]; got.ID != second.ID || got.Attempts != 1 || !got.NextAttemptAt.Time.Equal(later.Time) || got.LastError != "451 Try again later" {
+			t.Errorf("got failed email %+v", got)
+		}
+		if got := latest[2]; got.ID != first.ID || got.Attempts != 1 || !got.SentAt.Time.Equal(now) || got.NextAttemptAt.Valid {
+			t.Errorf("got sent email %+v", got)
+		}
+		if latest, err := ob.GetOutbox(ctx, 1); err != nil || len(latest) != 1 || latest[0].ID != third.ID {
+			t.Errorf("getting latest email: got %+v, %v", latest, err)
+		}
+	})
+}
+
+func TestWebhookStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ws := stores.Webhooks
+
+		now := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+		later := sql.NullTime{Valid: true, Time: now.Add(time.Hour)}
+
+		for _, tc := range []struct {
+			params db.AddWebhookParams
+			want   error
+		}{
+			{db.AddWebhookParams{Secret: "s", Events: webhooks.BeerCreated}, store.ErrMissingField{Field: "url"}},
+			{db.AddWebhookParams{Url: "ftp://example.com", Secret: "s", Events: webhooks.BeerCreated}, store.ErrInvalidField{Field: "url", Reason: "must be an http or https URL, e.g. https://example.com/hook"}},
+			{db.AddWebhookParams{Url: "https://example.com/hook", Events: webhooks.BeerCreated}, store.ErrMissingField{Field: "secret"}},
+			{db.AddWebhookParams{Url: "https://example.com/hook", Secret: "s", Events: " , "}, store.ErrMissingField{Field: "events"}},
+			{db.AddWebhookParams{Url: "https://example.com/hook", Secret: "s", Events: "beer.drunk"}, store.ErrInvalidField{Field: "events", Reason: "beer.drunk isn't a type of event"}},
+		} {
+			if _, err := ws.AddWebhook(ctx, tc.params); err != tc.want {
+				t.Errorf("adding webhook %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		// Events are kept in the usual order, once each
+		lights, err := ws.AddWebhook(ctx, db.AddWebhookParams{Url: " https://lights.local/hook ", Secret: "s1", Events: "user.deleted,beer.created, beer.created"})
+		if err != nil || lights.Url != "https://lights.local/hook" || lights.Events != "beer.created,user.deleted" {
+			t.Fatalf("adding webhook: got %+v, %v", lights, err)
+		}
+		if !webhooks.Wants(lights, webhooks.UserDeleted) || webhooks.Wants(lights, webhooks.BeerUpdated) {
+			t.Errorf("got webhook wanting the wrong events: %+v", lights)
+		}
+		speaker, _ := ws.AddWebhook(ctx, db.AddWebhookParams{Url: "http://speaker.local", Secret: "s2", Events: webhooks.BeerUpdated})
+
+		if got, err := ws.GetWebhook(ctx, lights.ID); err != nil || got != lights {
+			t.Errorf("getting webhook: got %+v, %v", got, err)
+		}
+		if _, err := ws.GetWebhook(ctx, 999); err != (webhooks.ErrWebhookNotFound{ID: 999}) {
+			t.Errorf("getting missing webhook: got %v", err)
+		}
+		if all, err := ws.GetWebhooks(ctx); err != nil || len(all) != 2 || all[0].ID != lights.ID || all[1].ID != speaker.ID {
+			t.Errorf("getting webhooks: got %+v, %v", all, err)
+		}
+
+		if _, err := ws.QueueDelivery(ctx, db.QueueDeliveryParams{WebhookID: 999, Event: webhooks.BeerCreated, Payload: "{}"}); err != (webhooks.ErrWebhookNotFound{ID: 999}) {
+			t.Errorf("queueing delivery to a missing webhook: got %v", err)
+		}
+		first, err := ws.QueueDelivery(ctx, db.QueueDeliveryParams{WebhookID: lights.ID, Event: webhooks.BeerCreated, Payload: `{"n":1}`, NextAttemptAt: sql.NullTime{Valid: true, Time: now}})
+		if err != nil || first.Attempts != 0 || first.DeliveredAt.Valid || first.LastStatus.Valid || first.Payload != `{"n":1}` {
+			t.Fatalf("queueing delivery: got %+v, %v", first, err)
+		}
+		second, _ := ws.QueueDelivery(ctx, db.QueueDeliveryParams{WebhookID: speaker.ID, Event: webhooks.BeerUpdated, Payload: `{"n":2}`, NextAttemptAt: sql.NullTime{Valid: true, Time: now.Add(-time.Minute)}})
+		third, _ := ws.QueueDelivery(ctx, db.QueueDeliveryParams{WebhookID: lights.ID, Event: webhooks.UserDeleted, Payload: `{"n":3}`, NextAttemptAt: later})
+
+		// The longest waiting first, with where they're going
+		due, err := ws.GetDueDeliveries(ctx, now, 10)
+		if err != nil || len(due) != 2 || due[0].WebhookDelivery.ID != second.ID || due[1].WebhookDelivery.ID != first.ID {
+			t.Fatalf("getting due deliveries: got %+v, %v", due, err)
+		}
+		if due[0].Url != "http://speaker.local" || due[0].Secret != "s2" {
+			t.Errorf("got due delivery %+v", due[0])
+		}
+		if due, err := ws.GetDueDeliveries(ctx, now, 1); err != nil || len(due) != 1 {
+			t.Errorf("getting one due delivery: got %+v, %v", due, err)
+		}
+
+		failed := db.SetDeliveryFailedParams{LastStatus: sql.NullInt64{Valid: true, Int64: 500}, LastError: "webhook responded with 500", NextAttemptAt: later, ID: second.ID}
+		if err := ws.SetDeliveryFailed(ctx, failed); err != nil {
+			t.Errorf("setting delivery failed: %v", err)
+		}
+		delivered := db.SetDeliveryDeliveredParams{LastStatus: sql.NullInt64{Valid: true, Int64: 204}, DeliveredAt: sql.NullTime{Valid: true, Time: now}, ID: first.ID}
+		if err := ws.SetDeliveryDelivered(ctx, delivered); err != nil {
+			t.Errorf("setting delivery delivered: %v", err)
+		}
+		if due, err := ws.GetDueDeliveries(ctx, now, 10); err != nil || len(due) != 0 {
+			t.Errorf("getting due deliveries after delivering: got %+v, %v", due, err)
+		}
+		if due, err := ws.GetDueDeliveries(ctx, later.Time, 10); err != nil || len(due) != 2 {
+			t.Errorf("getting due deliveries later: got %+v, %v", due, err)
+		}
+
+		latest, err := ws.GetDeliveries(ctx, 10)
+		if err != nil || len(latest) != 3 || latest[0].WebhookDelivery.ID != third.ID || latest[0].Url != "https://lights.local/hook" {
+			t.Fatalf("getting deliveries: got %+v, %v", latest, err)
+		}
+		if got := latest[1].WebhookDelivery; got.Attempts != 1 || got.LastStatus.Int64 != 500 || !got.NextAttemptAt.Time.Equal(later.Time) || got.LastError == "" {
+			t.Errorf("got failed delivery %+v", got)
+		}
+		if got := latest[2].WebhookDelivery; got.Attempts != 1 || got.LastStatus.Int64 != 204 || !got.DeliveredAt.Time.Equal(now) || got.NextAttemptAt.Valid {
+			t.Errorf("got delivered delivery %+v", got)
+		}
+
+		// Deleting a webhook takes its deliveries with it
+		if _, err := ws.DeleteWebhook(ctx, lights.ID); err != nil {
+			t.Errorf("deleting webhook: %v", err)
+		}
+		if _, err := ws.DeleteWebhook(ctx, lights.ID); err != (webhooks.ErrWebhookNotFound{ID: lights.ID}) {
+			t.Errorf("deleting deleted webhook: got %v", err)
+		}
+		if latest, err := ws.GetDeliveries(ctx, 10); err != nil || len(latest) != 1 || latest[0].WebhookDelivery.ID != second.ID {
+			t.Errorf("getting deliveries after deleting webhook: got %+v, %v", latest, err)
+		}
+	})
+}
+
+func TestDrinkStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ds := stores.Drinks
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		felons, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		stone, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Stone & Wood"})
+		beer := func(name string, abv float64, rating float64, brewer db.Brewer, style string) db.Beer {
+			t.Helper()
+			params := db.AddBeerParams{
+				Name: name, Abv: abv, Rating: sql.NullFloat64{Valid: true, Float64: rating},
+				BrewerID: sql.NullInt64{Valid: true, Int64: brewer.ID},
+			}
+			if style != "" {
+				params.Style = sql.NullString{Valid: true, String: style}
+			}
+			beer, err := stores.Beers.AddBeer(ctx, alice.ID, params)
+			if err != nil {
+				t.Fatalf("adding beer: %v", err)
+			}
+			return beer
+		}
+		pale := beer("Pale", 4.8, 7.5, felons, "Pale Ale")
+		lager := beer("Lager", 5.2, 6, felons, "Lager")
+		stout := beer("Stout", 6, 8, stone, "Stout")
+		mystery := beer("Mystery", 4.2, 7, stone, "")
+
+		// Sydney is ahead of UTC, so drinks late in the evening there are on the day before in UTC
+		sydney := time.FixedZone("AEDT", 11*60*60)
+		at := func(d int, hour int) time.Time { return time.Date(2025, time.March, d, hour, 0, 0, 0, sydney) }
+
+		for _, tc := range []struct {
+			params db.AddDrinkParams
+			want   error
+		}{
+			{db.AddDrinkParams{UserID: alice.ID, BeerID: 999, ServingMl: 375, DrunkAt: at(1, 20)}, beers.ErrBeerNotFound{ID: 999}},
+			{db.AddDrinkParams{UserID: alice.ID, BeerID: pale.ID, ServingMl: 0, DrunkAt: at(1, 20)}, store.ErrInvalidField{Field: "serving-ml", Reason: "must be at least 1"}},
+			{db.AddDrinkParams{UserID: alice.ID, BeerID: pale.ID, ServingMl: 375}, store.ErrMissingField{Field: "drunk-at"}},
+		} {
+			if _, err := ds.AddDrink(ctx, tc.params); err != tc.want {
+				t.Errorf("adding drink %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		drink := func(user db.User, beer db.Beer, drunkAt time.Time) db.Drink {
+			t.Helper()
+			drink, err := ds.AddDrink(ctx, db.AddDrinkParams{UserID: user.ID, BeerID: beer.ID, ServingMl: 375, DrunkAt: drunkAt})
+			if err != nil {
+				t.Fatalf("adding drink: %v", err)
+			}
+			return drink
+		}
+		first := drink(alice, pale, at(3, 9))
+		if !first.DrunkOn.Equal(time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)) || !first.DrunkAt.Equal(at(3, 9)) || first.DrunkAt.Location() != time.UTC {
+			t.Errorf("adding drink: got %+v", first)
+		}
+		drink(alice, pale, at(3, 20))
+		drink(alice, lager, at(3, 21))
+		drink(alice, stout, at(5, 22))
+		drink(alice, mystery, at(5, 23))
+		drink(alice, stout, at(9, 19))
+		drink(bob, stout, at(4, 19))
+		// Outside the range
+		drink(alice, lager, at(20, 19))
+
+		// Drinks of beers in the trash still count
+		stores.Beers.DeleteBeer(ctx, stout.ID)
+
+		since, until := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+		drinks, err := ds.GetUserDrinks(ctx, alice.ID, since, until)
+		if err != nil || len(drinks) != 6 || drinks[0].BeerName != "Stout" || drinks[5].Drink.ID != first.ID || drinks[5].Abv != 4.8 {
+			t.Errorf("getting user drinks: got %+v, %v", drinks, err)
+		}
+
+		days, err := ds.GetDrinksPerDay(ctx, alice.ID, since, until)
+		if err != nil || len(days) != 3 {
+			t.Fatalf("getting drinks per day: got %+v, %v", days, err)
+		}
+		if !days[0].DrunkOn.Equal(first.DrunkOn) || days[0].Drinks != 3 || days[1].Drinks != 2 || days[2].Drinks != 1 || days[2].AlcoholMl != 22.5 {
+			t.Errorf("getting drinks per day: got %+v", days)
+		}
+
+		abvs, err := ds.GetAbvDistribution(ctx, alice.ID, since, until)
+		if got := fmt.Sprint(abvs); err != nil || got != "[{4 3} {5 1} {6 2}]" {
+			t.Errorf("getting ABV distribution: got %s, %v", got, err)
+		}
+
+		styles, err := ds.GetTopStyles(ctx, alice.ID, since, until, 2)
+		if err != nil || len(styles) != 2 || styles[0].Name.String != "Pale Ale" || styles[0].Drinks != 2 || styles[1].Name.String != "Stout" {
+			t.Errorf("getting top styles: got %+v, %v", styles, err)
+		}
+
+		brewers, err := ds.GetTopBrewers(ctx, alice.ID, since, until, 5)
+		if got := fmt.Sprint(brewers); err != nil || got != "[{Felon's 3} {Stone & Wood 3}]" {
+			t.Errorf("getting top brewers: got %s, %v", got, err)
+		}
+
+		// Each beer counts once, however many of it were drunk
+		ratings, err := ds.GetRatingHistogram(ctx, alice.ID, since, until)
+		if got := fmt.Sprint(ratings); err != nil || got != "[{6 1} {7 2} {8 1}]" {
+			t.Errorf("getting rating histogram: got %s, %v", got, err)
+		}
+
+		if got, _ := ds.GetDrinksPerDay(ctx, bob.ID, since, until); len(got) != 1 || got[0].Drinks != 1 {
+			t.Errorf("getting another user's drinks per day: got %+v", got)
+		}
+
+		if deleted, err := ds.DeleteDrink(ctx, first.ID); err != nil || deleted.ID != first.ID {
+			t.Errorf("deleting drink: got %+v, %v", deleted, err)
+		}
+		if _, err := ds.GetDrink(ctx, first.ID); err != (drinklog.ErrDrinkNotFound{ID: first.ID}) {
+			t.Errorf("getting deleted drink: got %v", err)
+		}
+		if _, err := ds.DeleteDrink(ctx, first.ID); err != (drinklog.ErrDrinkNotFound{ID: first.ID}) {
+			t.Errorf("deleting drink again: got %v", err)
+		}
+	})
+}
+
+func TestDrinkSessionStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.DrinkSessions
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", Abv: 4.8, Rating: sql.NullFloat64{Valid: true, Float64: 7}})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: sql.NullFloat64{Valid: true, Float64: 8}})
+
+		start := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+		createdBy := sql.NullInt64{Valid: true, Int64: alice.ID}
+
+		for _, tc := range []struct {
+			params db.AddDrinkingSessionParams
+			want   error
+		}{
+			{db.AddDrinkingSessionParams{Name: " ", StartedAt: start, ShareToken: "t"}, store.ErrMissingField{Field: "name"}},
+			{db.AddDrinkingSessionParams{Name: "Friday", ShareToken: "t"}, store.ErrMissingField{Field: "started-at"}},
+			{db.AddDrinkingSessionParams{Name: "Friday", StartedAt: start}, store.ErrMissingField{Field: "share-token"}},
+			{db.AddDrinkingSessionParams{Name: "Friday", StartedAt: start, ShareToken: "t", CreatedBy: sql.NullInt64{Valid: true, Int64: 999}}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ss.AddSession(ctx, tc.params); err != tc.want {
+				t.Errorf("adding session %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		friday, err := ss.AddSession(ctx, db.AddDrinkingSessionParams{Name: " Friday at the Felon's taproom ", CreatedBy: createdBy, StartedAt: start, ShareToken: "friday"})
+		if err != nil || friday.Name != "Friday at the Felon's taproom" || friday.EndedAt.Valid {
+			t.Fatalf("adding session: got %+v, %v", friday, err)
+		}
+		saturday, _ := ss.AddSession(ctx, db.AddDrinkingSessionParams{Name: "Saturday", CreatedBy: createdBy, StartedAt: start.Add(24 * time.Hour), ShareToken: "saturday"})
+
+		if got, err := ss.GetSession(ctx, friday.ID); err != nil || got != friday {
+			t.Errorf("getting session: got %+v, %v", got, err)
+		}
+		if _, err := ss.GetSession(ctx, 999); err != (drinksessions.ErrSessionNotFound{ID: 999}) {
+			t.Errorf("getting missing session: got %v", err)
+		}
+		if got, err := ss.GetSessionByToken(ctx, "friday"); err != nil || got.ID != friday.ID {
+			t.Errorf("getting session by token: got %+v, %v", got, err)
+		}
+		if _, err := ss.GetSessionByToken(ctx, "nope"); err != (drinksessions.ErrShareLinkNotFound{}) {
+			t.Errorf("getting session by a wrong token: got %v", err)
+		}
+
+		// Adding someone twice leaves them in once
+		for _, userId := range []int64{alice.ID, bob.ID, alice.ID} {
+			if err := ss.AddParticipant(ctx, db.AddSessionParticipantParams{SessionID: friday.ID, UserID: userId}); err != nil {
+				t.Errorf("adding participant %d: %v", userId, err)
+			}
+		}
+		ss.AddParticipant(ctx, db.AddSessionParticipantParams{SessionID: saturday.ID, UserID: alice.ID})
+		if err := ss.AddParticipant(ctx, db.AddSessionParticipantParams{SessionID: friday.ID, UserID: 999}); err != (users.ErrUserNotFound{ID: 999}) {
+			t.Errorf("adding missing participant: got %v", err)
+		}
+		if err := ss.AddParticipant(ctx, db.AddSessionParticipantParams{SessionID: 999, UserID: bob.ID}); err != (drinksessions.ErrSessionNotFound{ID: 999}) {
+			t.Errorf("adding participant to a missing session: got %v", err)
+		}
+		participants, err := ss.GetParticipants(ctx, friday.ID)
+		if err != nil || len(participants) != 2 || participants[0].Username != "alice" || participants[1].Username != "bob" {
+			t.Errorf("getting participants: got %+v, %v", participants, err)
+		}
+
+		// Latest first
+		if got, err := ss.GetUserSessions(ctx, alice.ID); err != nil || len(got) != 2 || got[0].ID != saturday.ID || got[1].ID != friday.ID {
+			t.Errorf("getting alice's sessions: got %+v, %v", got, err)
+		}
+		if got, err := ss.GetUserSessions(ctx, bob.ID); err != nil || len(got) != 1 || got[0].ID != friday.ID {
+			t.Errorf("getting bob's sessions: got %+v, %v", got, err)
+		}
+
+		if _, err := ss.AddVenue(ctx, db.AddSessionVenueParams{SessionID: friday.ID, Name: " "}); err != (store.ErrMissingField{Field: "venue"}) {
+			t.Errorf("adding venue without a name: got %v", err)
+		}
+		if _, err := ss.AddVenue(ctx, db.AddSessionVenueParams{SessionID: 999, Name: "Nowhere"}); err != (drinksessions.ErrSessionNotFound{ID: 999}) {
+			t.Errorf("adding venue to a missing session: got %v", err)
+		}
+		ss.AddVenue(ctx, db.AddSessionVenueParams{SessionID: friday.ID, Name: " Felon's "})
+		ss.AddVenue(ctx, db.AddSessionVenueParams{SessionID: friday.ID, Name: "The Pub"})
+		if venues, err := ss.GetVenues(ctx, friday.ID); err != nil || len(venues) != 2 || venues[0].Name != "Felon's" || venues[1].Name != "The Pub" {
+			t.Errorf("getting venues: got %+v, %v", venues, err)
+		}
+
+		drink := func(user db.User, beer db.Beer, minutes int) db.Drink {
+			t.Helper()
+			drink, err := stores.Drinks.AddDrink(ctx, db.AddDrinkParams{UserID: user.ID, BeerID: beer.ID, ServingMl: 375, DrunkAt: start.Add(time.Duration(minutes) * time.Minute)})
+			if err != nil {
+				t.Fatalf("adding drink: %v", err)
+			}
+			if err := ss.AddDrink(ctx, db.AddSessionDrinkParams{DrinkID: drink.ID, SessionID: friday.ID}); err != nil {
+				t.Fatalf("adding drink to session: %v", err)
+			}
+			return drink
+		}
+		// Added out of order, but listed in the order they were had
+		second := drink(bob, stout, 30)
+		first := drink(alice, pale, 10)
+		third := drink(alice, stout, 60)
+
+		if err := ss.AddDrink(ctx, db.AddSessionDrinkParams{DrinkID: first.ID, SessionID: saturday.ID}); err != (drinksessions.ErrDrinkAlreadyInSession{DrinkID: first.ID}) {
+			t.Errorf("adding a drink to a second session: got %v", err)
+		}
+		if err := ss.AddDrink(ctx, db.AddSessionDrinkParams{DrinkID: first.ID, SessionID: 999}); err == nil {
+			t.Errorf("adding a drink to a missing session: got no error")
+		}
+
+		sessionDrinks, err := ss.GetDrinks(ctx, friday.ID)
+		if err != nil || len(sessionDrinks) != 3 {
+			t.Fatalf("getting session drinks: got %+v, %v", sessionDrinks, err)
+		}
+		for i, want := range []db.Drink{first, second, third} {
+			if sessionDrinks[i].Drink.ID != want.ID {
+				t.Errorf("session drink %d is %d, want %d", i, sessionDrinks[i].Drink.ID, want.ID)
+			}
+		}
+		if d := sessionDrinks[1]; d.Username != "bob" || d.BeerName != "Stout" || d.Abv != 6 {
+			t.Errorf("got session drink %+v", d)
+		}
+
+		// A drink deleted from the log goes from the session too
+		stores.Drinks.DeleteDrink(ctx, third.ID)
+		if got, _ := ss.GetDrinks(ctx, friday.ID); len(got) != 2 {
+			t.Errorf("got %d session drinks after deleting one, want 2", len(got))
+		}
+
+		// Deleted users are left out
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if got, _ := ss.GetParticipants(ctx, friday.ID); len(got) != 1 || got[0].UserID != alice.ID {
+			t.Errorf("got participants %+v after deleting bob", got)
+		}
+		if got, _ := ss.GetDrinks(ctx, friday.ID); len(got) != 1 || got[0].Drink.ID != first.ID {
+			t.Errorf("got session drinks %+v after deleting bob", got)
+		}
+
+		if _, err := ss.EndSession(ctx, db.EndDrinkingSessionParams{ID: friday.ID, EndedAt: sql.NullTime{Valid: true, Time: start.Add(-time.Minute)}}); err != (store.ErrInvalidField{Field: "ended-at", Reason: "must not be before the session started"}) {
+			t.Errorf("ending session before it started: got %v", err)
+		}
+		if _, err := ss.EndSession(ctx, db.EndDrinkingSessionParams{ID: 999, EndedAt: sql.NullTime{Valid: true, Time: start}}); err != (drinksessions.ErrSessionNotFound{ID: 999}) {
+			t.Errorf("ending missing session: got %v", err)
+		}
+		end := start.Add(5 * time.Hour)
+		if ended, err := ss.EndSession(ctx, db.EndDrinkingSessionParams{ID: friday.ID, EndedAt: sql.NullTime{Valid: true, Time: end}}); err != nil || !ended.EndedAt.Valid || !ended.EndedAt.Time.Equal(end) {
+			t.Errorf("ending session: got %+v, %v", ended, err)
+		}
+
+		// The drinks are kept, just not in a session any more
+		if deleted, err := ss.DeleteSession(ctx, friday.ID); err != nil || deleted.ID != friday.ID {
+			t.Errorf("deleting session: got %+v, %v", deleted, err)
+		}
+		if _, err := ss.GetSession(ctx, friday.ID); err != (drinksessions.ErrSessionNotFound{ID: friday.ID}) {
+			t.Errorf("getting deleted session: got %v", err)
+		}
+		if got, _ := ss.GetUserSessions(ctx, alice.ID); len(got) != 1 || got[0].ID != saturday.ID {
+			t.Errorf("got alice's sessions %+v after deleting one", got)
+		}
+		if _, err := stores.Drinks.GetDrink(ctx, first.ID); err != nil {
+			t.Errorf("getting a drink from a deleted session: %v", err)
+		}
+		if _, err := ss.DeleteSession(ctx, friday.ID); err != (drinksessions.ErrSessionNotFound{ID: friday.ID}) {
+			t.Errorf("deleting session again: got %v", err)
+		}
+	})
+}
+
+func TestVenueStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		vs := stores.Venues
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", Abv: 4.8, Rating: sql.NullFloat64{Valid: true, Float64: 7}})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: sql.NullFloat64{Valid: true, Float64: 8}})
+		lager, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Lager", Abv: 4.5, Rating: sql.NullFloat64{Valid: true, Float64: 6}})
+
+		coord := func(f float64) sql.NullFloat64 { return sql.NullFloat64{Valid: true, Float64: f} }
+
+		for _, tc := range []struct {
+			params db.AddVenueParams
+			want   error
+		}{
+			{db.AddVenueParams{Name: " ", Type: venues.Bar}, store.ErrMissingField{Field: "name"}},
+			{db.AddVenueParams{Name: "Felon's"}, store.ErrMissingField{Field: "type"}},
+			{db.AddVenueParams{Name: "Felon's", Type: "pub"}, store.ErrInvalidField{Field: "type", Reason: "must be a bar, bottle shop or home"}},
+			{db.AddVenueParams{Name: "Felon's", Type: venues.Bar, Latitude: coord(-27.46)}, store.ErrMissingField{Field: "longitude"}},
+			{db.AddVenueParams{Name: "Felon's", Type: venues.Bar, Longitude: coord(153.03)}, store.ErrMissingField{Field: "latitude"}},
+			{db.AddVenueParams{Name: "Felon's", Type: venues.Bar, Latitude: coord(-91), Longitude: coord(153.03)}, store.ErrInvalidField{Field: "latitude", Reason: "must be between -90 and 90"}},
+			{db.AddVenueParams{Name: "Felon's", Type: venues.Bar, Latitude: coord(-27.46), Longitude: coord(181)}, store.ErrInvalidField{Field: "longitude", Reason: "must be between -180 and 180"}},
+		} {
+			if _, err := vs.AddVenue(ctx, tc.params); err != tc.want {
+				t.Errorf("adding venue %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		felons, err := vs.AddVenue(ctx, db.AddVenueParams{Name: " Felon's ", Address: " 5 Boundary St ", Type: venues.Bar, Latitude: coord(-27.4614), Longitude: coord(153.0355)})
+		if err != nil || felons.Name != "Felon's" || felons.Address != "5 Boundary St" || felons.Latitude.Float64 != -27.4614 {
+			t.Fatalf("adding venue: got %+v, %v", felons, err)
+		}
+		// About 2 km from Felon's
+		bottleo, _ := vs.AddVenue(ctx, db.AddVenueParams{Name: "Bottle-O", Type: venues.BottleShop, Latitude: coord(-27.4795), Longitude: coord(153.0265)})
+		home, _ := vs.AddVenue(ctx, db.AddVenueParams{Name: "Alice's place", Type: venues.Home})
+		sydney, _ := vs.AddVenue(ctx, db.AddVenueParams{Name: "Harbour Bar", Type: venues.Bar, Latitude: coord(-33.8568), Longitude: coord(151.2153)})
+
+		if got, err := vs.GetVenue(ctx, felons.ID); err != nil || got != felons {
+			t.Errorf("getting venue: got %+v, %v", got, err)
+		}
+		if _, err := vs.GetVenue(ctx, 999); err != (venues.ErrVenueNotFound{ID: 999}) {
+			t.Errorf("getting missing venue: got %v", err)
+		}
+		if got, err := vs.GetVenues(ctx); err != nil || len(got) != 4 || got[0].ID != home.ID || got[1].ID != bottleo.ID || got[3].ID != sydney.ID {
+			t.Errorf("getting venues: got %+v, %v", got, err)
+		}
+
+		// Nearest first, leaving out those too far away and those without coordinates
+		nearby, err := vs.GetNearbyVenues(ctx, -27.4600, 153.0350, 5, 10)
+		if err != nil || len(nearby) != 2 || nearby[0].Venue.ID != felons.ID || nearby[1].Venue.ID != bottleo.ID {
+			t.Fatalf("getting nearby venues: got %+v, %v", nearby, err)
+		}
+		if d := nearby[1].DistanceKm; d < 2 || d > 3 {
+			t.Errorf("got Bottle-O %.2f km away", d)
+		}
+		if got, _ := vs.GetNearbyVenues(ctx, -27.4600, 153.0350, 5, 1); len(got) != 1 {
+			t.Errorf("got %d nearby venues with a limit of 1", len(got))
+		}
+		if got, _ := vs.GetNearbyVenues(ctx, -27.4600, 153.0350, 1000, 10); len(got) != 3 || got[2].Venue.ID != sydney.ID {
+			t.Errorf("got nearby venues %+v within 1000 km", got)
+		}
+
+		start := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+		if _, err := vs.AddCheckIn(ctx, db.AddCheckInParams{UserID: alice.ID, BeerID: pale.ID, VenueID: felons.ID}); err != (store.ErrMissingField{Field: "checked-in-at"}) {
+			t.Errorf("adding check-in without a time: got %v", err)
+		}
+		if _, err := vs.AddCheckIn(ctx, db.AddCheckInParams{UserID: alice.ID, BeerID: pale.ID, VenueID: 999, CheckedInAt: start}); err != (venues.ErrVenueNotFound{ID: 999}) {
+			t.Errorf("adding check-in at a missing venue: got %v", err)
+		}
+		if _, err := vs.AddCheckIn(ctx, db.AddCheckInParams{UserID: alice.ID, BeerID: 999, VenueID: felons.ID, CheckedInAt: start}); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("adding check-in of a missing beer: got %v", err)
+		}
+
+		checkIn := func(user db.User, beer db.Beer, venue db.Venue, minutes int) db.CheckIn {
+			t.Helper()
+			checkIn, err := vs.AddCheckIn(ctx, db.AddCheckInParams{UserID: user.ID, BeerID: beer.ID, VenueID: venue.ID, CheckedInAt: start.Add(time.Duration(minutes) * time.Minute)})
+			if err != nil {
+				t.Fatalf("adding check-in: %v", err)
+			}
+			return checkIn
+		}
+		first := checkIn(alice, pale, felons, 0)
+		checkIn(bob, stout, felons, 10)
+		checkIn(bob, stout, felons, 20)
+		checkIn(alice, lager, felons, 30)
+		last := checkIn(alice, pale, bottleo, 40)
+
+		if got, err := vs.GetCheckIn(ctx, first.ID); err != nil || got != first {
+			t.Errorf("getting check-in: got %+v, %v", got, err)
+		}
+		if _, err := vs.GetCheckIn(ctx, 999); err != (venues.ErrCheckInNotFound{ID: 999}) {
+			t.Errorf("getting missing check-in: got %v", err)
+		}
+
+		userCheckIns, err := vs.GetUserCheckIns(ctx, alice.ID, 10)
+		if err != nil || len(userCheckIns) != 3 || userCheckIns[0].CheckIn.ID != last.ID || userCheckIns[0].VenueName != "Bottle-O" || userCheckIns[2].BeerName != "Pale" {
+			t.Errorf("getting alice's check-ins: got %+v, %v", userCheckIns, err)
+		}
+		venueCheckIns, err := vs.GetVenueCheckIns(ctx, felons.ID, 3)
+		if err != nil || len(venueCheckIns) != 3 || venueCheckIns[0].BeerName != "Lager" || venueCheckIns[1].Username != "bob" {
+			t.Errorf("getting Felon's check-ins: got %+v, %v", venueCheckIns, err)
+		}
+
+		// Most had first, then by name
+		topBeers, err := vs.GetVenueTopBeers(ctx, felons.ID, 10)
+		if err != nil || len(topBeers) != 3 {
+			t.Fatalf("getting top beers: got %+v, %v", topBeers, err)
+		}
+		for i, want := range []db.GetVenueTopBeersRow{{BeerID: stout.ID, Name: "Stout", CheckIns: 2}, {BeerID: lager.ID, Name: "Lager", CheckIns: 1}, {BeerID: pale.ID, Name: "Pale", CheckIns: 1}} {
+			if topBeers[i] != want {
+				t.Errorf("top beer %d is %+v, want %+v", i, topBeers[i], want)
+			}
+		}
+
+		// Deleted users' check-ins are left out
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if got, _ := vs.GetVenueCheckIns(ctx, felons.ID, 10); len(got) != 2 {
+			t.Errorf("got %d check-ins at Felon's after deleting bob, want 2", len(got))
+		}
+
+		if deleted, err := vs.DeleteCheckIn(ctx, first.ID); err != nil || deleted.ID != first.ID {
+			t.Errorf("deleting check-in: got %+v, %v", deleted, err)
+		}
+		if _, err := vs.DeleteCheckIn(ctx, first.ID); err != (venues.ErrCheckInNotFound{ID: first.ID}) {
+			t.Errorf("deleting check-in again: got %v", err)
+		}
+
+		// The check-ins at a deleted venue go with it
+		if deleted, err := vs.DeleteVenue(ctx, bottleo.ID); err != nil || deleted.ID != bottleo.ID {
+			t.Errorf("deleting venue: got %+v, %v", deleted, err)
+		}
+		if _, err := vs.GetCheckIn(ctx, last.ID); err != (venues.ErrCheckInNotFound{ID: last.ID}) {
+			t.Errorf("getting a check-in at a deleted venue: got %v", err)
+		}
+		if _, err := vs.DeleteVenue(ctx, bottleo.ID); err != (venues.ErrVenueNotFound{ID: bottleo.ID}) {
+			t.Errorf("deleting venue again: got %v", err)
+		}
+	})
+}
+
+func TestShoutStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Shouts
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		carol, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "carol", PasswordHash: "hash"})
+
+		start := time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)
+		friday, _ := stores.DrinkSessions.AddSession(ctx, db.AddDrinkingSessionParams{Name: "Friday", StartedAt: start, ShareToken: "friday"})
+		saturday, _ := stores.DrinkSessions.AddSession(ctx, db.AddDrinkingSessionParams{Name: "Saturday", StartedAt: start, ShareToken: "saturday"})
+
+		round := func(sessionId int64, boughtBy int64, minutes int) db.AddRoundParams {
+			return db.AddRoundParams{SessionID: sessionId, BoughtBy: boughtBy, Currency: "AUD", BoughtAt: start.Add(time.Duration(minutes) * time.Minute)}
+		}
+		everyone := []int64{alice.ID, bob.ID, carol.ID}
+
+		for _, tc := range []struct {
+			params     db.AddRoundParams
+			recipients []int64
+			want       error
+		}{
+			{round(friday.ID, alice.ID, 0), nil, store.ErrMissingField{Field: "recipients"}},
+			{db.AddRoundParams{SessionID: friday.ID, BoughtBy: alice.ID, Currency: "AUD"}, everyone, store.ErrMissingField{Field: "bought-at"}},
+			{db.AddRoundParams{SessionID: friday.ID, BoughtBy: alice.ID, Currency: "AUD", BoughtAt: start, Cost: sql.NullFloat64{Valid: true, Float64: -1}}, everyone, store.ErrInvalidField{Field: "cost", Reason: "must not be negative"}},
+			{db.AddRoundParams{SessionID: friday.ID, BoughtBy: alice.ID, Currency: "dollars", BoughtAt: start}, everyone, store.ErrInvalidField{Field: "currency", Reason: "must be a three letter code, like AUD"}},
+			{round(999, alice.ID, 0), everyone, drinksessions.ErrSessionNotFound{ID: 999}},
+			{round(friday.ID, 999, 0), everyone, users.ErrUserNotFound{ID: 999}},
+			{round(friday.ID, alice.ID, 0), []int64{bob.ID, 999}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ss.AddRound(ctx, tc.params, tc.recipients); err != tc.want {
+				t.Errorf("adding round %+v for %v: got %v, want %v", tc.params, tc.recipients, err, tc.want)
+			}
+		}
+		// The round for someone who isn't there isn't kept
+		if got, _ := ss.GetRounds(ctx, friday.ID); len(got) != 0 {
+			t.Errorf("got rounds %+v after failing to add them", got)
+		}
+
+		// Added out of order, but listed in the order they were bought. Someone listed twice is
+		// only bought the one drink.
+		second, err := ss.AddRound(ctx, round(friday.ID, bob.ID, 30), []int64{carol.ID, bob.ID, alice.ID, bob.ID})
+		if err != nil {
+			t.Fatalf("adding round: %v", err)
+		}
+		params := round(friday.ID, alice.ID, 10)
+		params.Cost = sql.NullFloat64{Valid: true, Float64: 31.5}
+		params.Currency = " aud "
+		first, err := ss.AddRound(ctx, params, everyone)
+		if err != nil || first.Currency != "AUD" || first.Cost.Float64 != 31.5 || !first.BoughtAt.Equal(start.Add(10*time.Minute)) {
+			t.Fatalf("adding round: got %+v, %v", first, err)
+		}
+		ss.AddRound(ctx, round(saturday.ID, carol.ID, 0), []int64{alice.ID})
+
+		if got, err := ss.GetRound(ctx, first.ID); err != nil || got != first {
+			t.Errorf("getting round: got %+v, %v", got, err)
+		}
+		if _, err := ss.GetRound(ctx, 999); err != (shouts.ErrRoundNotFound{ID: 999}) {
+			t.Errorf("getting missing round: got %v", err)
+		}
+
+		rounds, err := ss.GetRounds(ctx, friday.ID)
+		if err != nil || len(rounds) != 2 || rounds[0].Round.ID != first.ID || rounds[0].Username != "alice" || rounds[1].Round.ID != second.ID || rounds[1].Username != "bob" {
+			t.Errorf("getting rounds: got %+v, %v", rounds, err)
+		}
+		recipients, err := ss.GetRecipients(ctx, friday.ID)
+		if err != nil || len(recipients) != 6 {
+			t.Fatalf("getting recipients: got %+v, %v", recipients, err)
+		}
+		if r := recipients[0]; r.RoundID != second.ID || r.UserID != alice.ID || r.Username != "alice" {
+			t.Errorf("got recipient %+v, want alice in bob's round", r)
+		}
+
+		// Deleted users are left out, along with the rounds they bought
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if got, _ := ss.GetRounds(ctx, friday.ID); len(got) != 1 || got[0].Round.ID != first.ID {
+			t.Errorf("got rounds %+v after deleting bob", got)
+		}
+		if got, _ := ss.GetRecipients(ctx, friday.ID); len(got) != 4 {
+			t.Errorf("got %d recipients after deleting bob, want 4", len(got))
+		}
+
+		if deleted, err := ss.DeleteRound(ctx, first.ID); err != nil || deleted.ID != first.ID {
+			t.Errorf("deleting round: got %+v, %v", deleted, err)
+		}
+		if got, _ := ss.GetRecipients(ctx, friday.ID); len(got) != 2 {
+			t.Errorf("got %d recipients after deleting a round, want 2", len(got))
+		}
+		if _, err := ss.DeleteRound(ctx, first.ID); err != (shouts.ErrRoundNotFound{ID: first.ID}) {
+			t.Errorf("deleting round again: got %v", err)
+		}
+
+		// The rounds in a deleted session go with it
+		stores.DrinkSessions.DeleteSession(ctx, saturday.ID)
+		if got, _ := ss.GetRounds(ctx, saturday.ID); len(got) != 0 {
+			t.Errorf("got rounds %+v in a deleted session", got)
+		}
+	})
+}
+
+func TestSocialStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ss := stores.Social
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		carol, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "carol", PasswordHash: "hash"})
+
+		if err := ss.Follow(ctx, alice.ID, alice.ID); err != (store.ErrInvalidField{Field: "followee", Reason: "must be someone else"}) {
+			t.Errorf("following yourself: got %v", err)
+		}
+		if err := ss.Follow(ctx, alice.ID, 999); err != (users.ErrUserNotFound{ID: 999}) {
+			t.Errorf("following a missing user: got %v", err)
+		}
+		// Following twice is the same as once
+		for _, f := range [][2]int64{{alice.ID, bob.ID}, {alice.ID, bob.ID}, {alice.ID, carol.ID}, {bob.ID, alice.ID}} {
+			if err := ss.Follow(ctx, f[0], f[1]); err != nil {
+				t.Fatalf("following: %v", err)
+			}
+		}
+		if got, err := ss.GetFollowing(ctx, alice.ID); err != nil || len(got) != 2 || got[0].Username != "bob" || got[1].Username != "carol" {
+			t.Errorf("getting following: got %+v, %v", got, err)
+		}
+		if got, err := ss.GetFollowers(ctx, alice.ID); err != nil || len(got) != 1 || got[0].ID != bob.ID {
+			t.Errorf("getting followers: got %+v, %v", got, err)
+		}
+
+		if got, err := ss.GetPrivacy(ctx, alice.ID); err != nil || got.Visibility != social.Public {
+			t.Errorf("getting privacy before setting it: got %+v, %v", got, err)
+		}
+		if _, err := ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: alice.ID, Visibility: "secret"}); err != (store.ErrInvalidField{Field: "visibility", Reason: "must be public, friends or private"}) {
+			t.Errorf("setting invalid privacy: got %v", err)
+		}
+		if _, err := ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: 999, Visibility: social.Private}); err != (users.ErrUserNotFound{ID: 999}) {
+			t.Errorf("setting privacy of a missing user: got %v", err)
+		}
+
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		pale, _ := stores.Beers.AddBeer(ctx, bob.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: sql.NullFloat64{Valid: true, Float64: 5}})
+		lager, _ := stores.Beers.AddBeer(ctx, carol.ID, db.AddBeerParams{Name: "Lager", Abv: 4, Rating: sql.NullFloat64{Valid: true, Float64: 3}})
+		beerId := func(id int64) sql.NullInt64 { return sql.NullInt64{Valid: true, Int64: id} }
+
+		for _, tc := range []struct {
+			params db.AddActivityParams
+			want   error
+		}{
+			{db.AddActivityParams{UserID: bob.ID}, store.ErrMissingField{Field: "kind"}},
+			{db.AddActivityParams{UserID: bob.ID, Kind: "beer-drunk", BeerID: beerId(pale.ID)}, store.ErrInvalidField{Field: "kind", Reason: "must be beer-added, beer-edited or brewer-added"}},
+			{db.AddActivityParams{UserID: bob.ID, Kind: social.BeerAdded}, store.ErrMissingField{Field: "beer-id"}},
+			{db.AddActivityParams{UserID: bob.ID, Kind: social.BrewerAdded}, store.ErrMissingField{Field: "brewer-id"}},
+			{db.AddActivityParams{UserID: bob.ID, Kind: social.BeerAdded, BeerID: beerId(999)}, beers.ErrBeerNotFound{ID: 999}},
+			{db.AddActivityParams{UserID: 999, Kind: social.BeerAdded, BeerID: beerId(pale.ID)}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ss.AddActivity(ctx, tc.params); err != tc.want {
+				t.Errorf("adding activity %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		added := []db.Activity{}
+		for _, params := range []db.AddActivityParams{
+			{UserID: bob.ID, Kind: social.BrewerAdded, BrewerID: sql.NullInt64{Valid: true, Int64: brewer.ID}},
+			{UserID: bob.ID, Kind: social.BeerAdded, BeerID: beerId(pale.ID)},
+			{UserID: carol.ID, Kind: social.BeerAdded, BeerID: beerId(lager.ID)},
+			{UserID: bob.ID, Kind: social.BeerEdited, BeerID: beerId(pale.ID)},
+			{UserID: alice.ID, Kind: social.BeerEdited, BeerID: beerId(lager.ID)},
+		} {
+			activity, err := ss.AddActivity(ctx, params)
+			if err != nil {
+				t.Fatalf("adding activity: %v", err)
+			}
+			added = append(added, activity)
+		}
+
+		ids := func(rows []db.GetFeedRow) []int64 {
+			ids := []int64{}
+			for _, row := range rows {
+				ids = append(ids, row.Activity.ID)
+			}
+			return ids
+		}
+		feed := func(viewerId int64, before int64, limit int64) []int64 {
+			rows, err := ss.GetFeed(ctx, viewerId, before, limit)
+			if err != nil {
+				t.Fatalf("getting feed: %v", err)
+			}
+			return ids(rows)
+		}
+
+		// Newest first, only of those followed, a page at a time
+		if got, want := feed(alice.ID, 0, 10), []int64{added[3].ID, added[2].ID, added[1].ID, added[0].ID}; !slices.Equal(got, want) {
+			t.Errorf("got feed %v, want %v", got, want)
+		}
+		if got, want := feed(alice.ID, 0, 2), []int64{added[3].ID, added[2].ID}; !slices.Equal(got, want) {
+			t.Errorf("got first page %v, want %v", got, want)
+		}
+		if got, want := feed(alice.ID, added[2].ID, 2), []int64{added[1].ID, added[0].ID}; !slices.Equal(got, want) {
+			t.Errorf("got second page %v, want %v", got, want)
+		}
+		rows, _ := ss.GetFeed(ctx, alice.ID, 0, 1)
+		if len(rows) != 1 || rows[0].Username != "bob" || rows[0].BeerName.String != "Pale" {
+			t.Errorf("got feed %+v, want bob's edit of Pale", rows)
+		}
+		rows, _ = ss.GetFeed(ctx, alice.ID, added[1].ID, 1)
+		if len(rows) != 1 || rows[0].BrewerName.String != "Felon's" || rows[0].BeerName.Valid {
+			t.Errorf("got feed %+v, want bob adding Felon's", rows)
+		}
+
+		// Friends only shows to those followed back, and private to no one but the user
+		ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: bob.ID, Visibility: social.Friends})
+		ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: carol.ID, Visibility: social.Friends})
+		if got, want := feed(alice.ID, 0, 10), []int64{added[3].ID, added[1].ID, added[0].ID}; !slices.Equal(got, want) {
+			t.Errorf("got feed %v with bob and carol friends only, want %v", got, want)
+		}
+		if privacy, err := ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: bob.ID, Visibility: social.Private}); err != nil || privacy.Visibility != social.Private {
+			t.Errorf("setting privacy: got %+v, %v", privacy, err)
+		}
+		if got, err := ss.GetPrivacy(ctx, bob.ID); err != nil || got.Visibility != social.Private {
+			t.Errorf("getting privacy: got %+v, %v", got, err)
+		}
+		if got := feed(alice.ID, 0, 10); len(got) != 0 {
+			t.Errorf("got feed %v with bob private and carol friends only", got)
+		}
+
+		userActivities := func(viewerId int64, userId int64) []int64 {
+			rows, err := ss.GetUserActivities(ctx, viewerId, userId, 0, 10)
+			if err != nil {
+				t.Fatalf("getting user activities: %v", err)
+			}
+			ids := []int64{}
+			for _, row := range rows {
+				ids = append(ids, row.Activity.ID)
+			}
+			return ids
+		}
+		if got := userActivities(alice.ID, bob.ID); len(got) != 0 {
+			t.Errorf("got bob's private activities %v", got)
+		}
+		if got, want := userActivities(bob.ID, bob.ID), []int64{added[3].ID, added[1].ID, added[0].ID}; !slices.Equal(got, want) {
+			t.Errorf("got own activities %v, want %v", got, want)
+		}
+		// Alice's activity is public, even to those she doesn't follow
+		if got, want := userActivities(carol.ID, alice.ID), []int64{added[4].ID}; !slices.Equal(got, want) {
+			t.Errorf("got alice's activities %v, want %v", got, want)
+		}
+		ss.Follow(ctx, carol.ID, alice.ID)
+		if got, want := userActivities(alice.ID, carol.ID), []int64{added[2].ID}; !slices.Equal(got, want) {
+			t.Errorf("got carol's activities %v as a friend, want %v", got, want)
+		}
+
+		// Unfollowing takes them out of the feed
+		ss.SetPrivacy(ctx, db.SetPrivacyParams{UserID: bob.ID, Visibility: social.Public})
+		if err := ss.Unfollow(ctx, alice.ID, bob.ID); err != nil {
+			t.Errorf("unfollowing: %v", err)
+		}
+		if got, want := feed(alice.ID, 0, 10), []int64{added[2].ID}; !slices.Equal(got, want) {
+			t.Errorf("got feed %v after unfollowing bob, want %v", got, want)
+		}
+
+		// Deleted beers and users are left out
+		stores.Beers.DeleteBeer(ctx, lager.ID)
+		if got := feed(alice.ID, 0, 10); len(got) != 0 {
+			t.Errorf("got feed %v after deleting the lager", got)
+		}
+		ss.Follow(ctx, alice.ID, bob.ID)
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if got := feed(alice.ID, 0, 10); len(got) != 0 {
+			t.Errorf("got feed %v after deleting bob", got)
+		}
+		if got, _ := ss.GetFollowing(ctx, alice.ID); len(got) != 1 || got[0].ID != carol.ID {
+			t.Errorf("got following %+v after deleting bob", got)
+		}
+	})
+}
+
+func TestCommentStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		cs := stores.Comments
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", Abv: 5, Rating: sql.NullFloat64{Valid: true, Float64: 5}})
+		onPale := comments.BeerTarget(pale.ID)
+		onBrewer := comments.BrewerTarget(brewer.ID)
+		id := func(id int64) sql.NullInt64 { return sql.NullInt64{Valid: true, Int64: id} }
+
+		first, err := cs.AddComment(ctx, db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), Body: "  So **hazy**\r\n"})
+		if err != nil || first.Body != "So **hazy**" || first.DeletedAt.Valid {
+			t.Fatalf("adding comment: got %+v, %v", first, err)
+		}
+		onOther, _ := cs.AddComment(ctx, db.AddCommentParams{AuthorID: bob.ID, BrewerID: id(brewer.ID), Body: "Great brewery"})
+
+		for _, tc := range []struct {
+			params db.AddCommentParams
+			want   error
+		}{
+			{db.AddCommentParams{AuthorID: alice.ID, Body: "Nowhere"}, store.ErrMissingField{Field: "beer-id"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), BrewerID: id(brewer.ID), Body: "Both"}, store.ErrInvalidField{Field: "brewer-id", Reason: "must not be given along with a beer"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), Body: " \n "}, store.ErrMissingField{Field: "body"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), Body: strings.Repeat("🍺", comments.MaxBodyLength+1)}, store.ErrInvalidField{Field: "body", Reason: "must be at most 5000 characters"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), ParentID: id(onOther.ID), Body: "Wrong page"}, store.ErrInvalidField{Field: "parent-id", Reason: "must be a comment on the same page"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(pale.ID), ParentID: id(999), Body: "Missing"}, store.ErrInvalidField{Field: "parent-id", Reason: "must be a comment on the same page"}},
+			{db.AddCommentParams{AuthorID: alice.ID, BeerID: id(999), Body: "No beer"}, beers.ErrBeerNotFound{ID: 999}},
+			{db.AddCommentParams{AuthorID: alice.ID, BrewerID: id(999), Body: "No brewer"}, store.ErrBrewerNotFound{ID: 999}},
+			{db.AddCommentParams{AuthorID: 999, BeerID: id(pale.ID), Body: "No one"}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := cs.AddComment(ctx, tc.params); err != tc.want {
+				t.Errorf("adding comment %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		reply, err := cs.AddComment(ctx, db.AddCommentParams{AuthorID: bob.ID, BeerID: id(pale.ID), ParentID: id(first.ID), Body: "Agreed"})
+		if err != nil || reply.ParentID.Int64 != first.ID {
+			t.Fatalf("adding reply: got %+v, %v", reply, err)
+		}
+		if got, err := cs.GetComment(ctx, reply.ID); err != nil || got != reply {
+			t.Errorf("getting comment: got %+v, %v", got, err)
+		}
+		if _, err := cs.GetComment(ctx, 999); err != (comments.ErrCommentNotFound{ID: 999}) {
+			t.Errorf("getting missing comment: got %v", err)
+		}
+
+		rows, err := cs.GetComments(ctx, onPale)
+		if err != nil || len(rows) != 2 || rows[0].Comment.ID != first.ID || rows[0].Username != "alice" || rows[1].Comment.ID != reply.ID || rows[1].Username != "bob" {
+			t.Errorf("getting comments: got %+v, %v", rows, err)
+		}
+		if rows, _ := cs.GetComments(ctx, onBrewer); len(rows) != 1 || rows[0].Comment.ID != onOther.ID {
+			t.Errorf("getting brewer comments: got %+v", rows)
+		}
+
+		edited, err := cs.UpdateComment(ctx, first.ID, "So *very* hazy")
+		if err != nil || edited.Body != "So *very* hazy" || !edited.EditedAt.Valid {
+			t.Errorf("updating comment: got %+v, %v", edited, err)
+		}
+		if _, err := cs.UpdateComment(ctx, first.ID, ""); err != (store.ErrMissingField{Field: "body"}) {
+			t.Errorf("updating comment to nothing: got %v", err)
+		}
+		if _, err := cs.UpdateComment(ctx, 999, "Hi"); err != (comments.ErrCommentNotFound{ID: 999}) {
+			t.Errorf("updating missing comment: got %v", err)
+		}
+
+		// Reacting again with the same emoji takes it back
+		for _, tc := range []struct {
+			userId int64
+			emoji  string
+			added  bool
+		}{
+			{alice.ID, "🍺", true},
+			{bob.ID, "🍺", true},
+			{bob.ID, "👍", true},
+			{bob.ID, "👍", false},
+		} {
+			if added, err := cs.ToggleReaction(ctx, first.ID, tc.userId, tc.emoji); err != nil || added != tc.added {
+				t.Errorf("toggling %s by %d: got %t, %v, want %t", tc.emoji, tc.userId, added, err, tc.added)
+			}
+		}
+		if _, err := cs.ToggleReaction(ctx, first.ID, alice.ID, "🦄"); err != (store.ErrInvalidField{Field: "emoji", Reason: "must be one of " + strings.Join(comments.Emojis, " ")}) {
+			t.Errorf("reacting with an unknown emoji: got %v", err)
+		}
+		if _, err := cs.ToggleReaction(ctx, 999, alice.ID, "🍺"); err != (comments.ErrCommentNotFound{ID: 999}) {
+			t.Errorf("reacting to a missing comment: got %v", err)
+		}
+		if _, err := cs.ToggleReaction(ctx, first.ID, 999, "🍺"); err != (users.ErrUserNotFound{ID: 999}) {
+			t.Errorf("reacting as a missing user: got %v", err)
+		}
+		cs.ToggleReaction(ctx, onOther.ID, alice.ID, "❤️")
+		reactions, err := cs.GetReactions(ctx, onPale)
+		if err != nil || len(reactions) != 2 || reactions[0].CommentID != first.ID || reactions[0].Emoji != "🍺" {
+			t.Errorf("getting reactions: got %+v, %v", reactions, err)
+		}
+
+		// Deleting blanks the comment but keeps it for its replies, and it can't be changed after
+		deleted, err := cs.DeleteComment(ctx, first.ID, true)
+		if err != nil || deleted.Body != "" || !deleted.DeletedAt.Valid || !deleted.Moderated {
+			t.Errorf("deleting comment: got %+v, %v", deleted, err)
+		}
+		if _, err := cs.DeleteComment(ctx, first.ID, false); err != (comments.ErrCommentNotFound{ID: first.ID}) {
+			t.Errorf("deleting comment again: got %v", err)
+		}
+		if _, err := cs.UpdateComment(ctx, first.ID, "Back"); err != (comments.ErrCommentNotFound{ID: first.ID}) {
+			t.Errorf("updating deleted comment: got %v", err)
+		}
+		if _, err := cs.ToggleReaction(ctx, first.ID, bob.ID, "😂"); err != (comments.ErrCommentNotFound{ID: first.ID}) {
+			t.Errorf("reacting to deleted comment: got %v", err)
+		}
+		if rows, _ := cs.GetComments(ctx, onPale); len(rows) != 2 {
+			t.Errorf("got %d comments after deleting one, want 2", len(rows))
+		}
+
+		// Those by deleted users are left out
+		stores.Users.DeleteUser(ctx, bob.ID)
+		if rows, _ := cs.GetComments(ctx, onPale); len(rows) != 1 || rows[0].Comment.ID != first.ID {
+			t.Errorf("got comments %+v after deleting bob", rows)
+		}
+		if reactions, _ := cs.GetReactions(ctx, onPale); len(reactions) != 1 || reactions[0].UserID != alice.ID {
+			t.Errorf("got reactions %+v after deleting bob", reactions)
+		}
+	})
+}
+
+func TestTastingStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ts := stores.Tastings
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		rating := sql.NullFloat64{Valid: true, Float64: 5}
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", BrewerID: sql.NullInt64{Valid: true, Int64: brewer.ID}, Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+		organiser := sql.NullInt64{Valid: true, Int64: alice.ID}
+		heldAt := time.Date(2025, 3, 7, 19, 0, 0, 0, time.UTC)
+
+		tasting, err := ts.AddTasting(ctx, db.AddTastingParams{Name: " March IPAs ", OrganiserID: organiser, HeldAt: heldAt})
+		if err != nil || tasting.Name != "March IPAs" || !tasting.HeldAt.Equal(heldAt) || tasting.RevealedAt.Valid {
+			t.Fatalf("adding tasting: got %+v, %v", tasting, err)
+		}
+		later, _ := ts.AddTasting(ctx, db.AddTastingParams{Name: "April", HeldAt: heldAt.AddDate(0, 1, 0)})
+		for _, tc := range []struct {
+			params db.AddTastingParams
+			want   error
+		}{
+			{db.AddTastingParams{Name: " ", HeldAt: heldAt}, store.ErrMissingField{Field: "name"}},
+			{db.AddTastingParams{Name: "When?"}, store.ErrMissingField{Field: "held-at"}},
+			{db.AddTastingParams{Name: "Who?", OrganiserID: sql.NullInt64{Valid: true, Int64: 999}, HeldAt: heldAt}, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ts.AddTasting(ctx, tc.params); err != tc.want {
+				t.Errorf("adding tasting %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+		if _, err := ts.GetTasting(ctx, 999); err != (tastings.ErrTastingNotFound{ID: 999}) {
+			t.Errorf("getting missing tasting: got %v", err)
+		}
+
+		// The latest first, with who organised them
+		rows, err := ts.GetTastings(ctx)
+		if err != nil || len(rows) != 2 || rows[0].Tasting.ID != later.ID || rows[0].Username.Valid || rows[1].Tasting.ID != tasting.ID || rows[1].Username.String != "alice" {
+			t.Errorf("getting tastings: got %+v, %v", rows, err)
+		}
+
+		// Pours are numbered in the order they're poured, and keep their numbers
+		for i, beerId := range []int64{pale.ID, stout.ID} {
+			pour, err := ts.AddPour(ctx, tasting.ID, beerId)
+			if err != nil || pour.Number != int64(i+1) || pour.BeerID != beerId {
+				t.Errorf("adding pour %d: got %+v, %v", i+1, pour, err)
+			}
+		}
+		if _, err := ts.AddPour(ctx, tasting.ID, pale.ID); err != (tastings.ErrBeerAlreadyPoured{TastingID: tasting.ID, BeerID: pale.ID}) {
+			t.Errorf("pouring a beer twice: got %v", err)
+		}
+		if _, err := ts.AddPour(ctx, tasting.ID, 999); err != (beers.ErrBeerNotFound{ID: 999}) {
+			t.Errorf("pouring a missing beer: got %v", err)
+		}
+		if _, err := ts.AddPour(ctx, 999, pale.ID); err != (tastings.ErrTastingNotFound{ID: 999}) {
+			t.Errorf("pouring in a missing tasting: got %v", err)
+		}
+		pours, err := ts.GetPours(ctx, tasting.ID)
+		want := []db.GetPoursRow{
+			{TastingID: tasting.ID, Number: 1, BeerID: pale.ID, BeerName: "Pale", BrewerName: sql.NullString{Valid: true, String: "Felon's"}},
+			{TastingID: tasting.ID, Number: 2, BeerID: stout.ID, BeerName: "Stout"},
+		}
+		if err != nil || !slices.Equal(pours, want) {
+			t.Errorf("getting pours: got %+v, %v, want %+v", pours, err, want)
+		}
+
+		scores := scorecards.Scores{7, 8, 9, 6, 7}
+		scorecard, err := ts.SaveScorecard(ctx, tasting.ID, 1, bob.ID, scores)
+		if err != nil || tastings.ScoresOf(scorecard) != scores || scorecard.UserID != bob.ID {
+			t.Errorf("saving scorecard: got %+v, %v", scorecard, err)
+		}
+		ts.SaveScorecard(ctx, tasting.ID, 1, alice.ID, scorecards.Scores{1, 1, 1, 1, 1})
+		// Scoring again replaces what was there
+		ts.SaveScorecard(ctx, tasting.ID, 2, bob.ID, scorecards.Scores{1, 1, 1, 1, 1})
+		ts.SaveScorecard(ctx, tasting.ID, 2, bob.ID, scorecards.Scores{5, 5, 5, 5, 5})
+		for _, tc := range []struct {
+			number int64
+			userId int64
+			scores scorecards.Scores
+			want   error
+		}{
+			{1, bob.ID, scorecards.Scores{11, 5, 5, 5, 5}, store.ErrInvalidField{Field: "aroma", Reason: "must be between 0 and 10"}},
+			{3, bob.ID, scores, tastings.ErrPourNotFound{TastingID: tasting.ID, Number: 3}},
+			{1, 999, scores, users.ErrUserNotFound{ID: 999}},
+		} {
+			if _, err := ts.SaveScorecard(ctx, tasting.ID, tc.number, tc.userId, tc.scores); err != tc.want {
+				t.Errorf("saving scorecard for pour %d by %d: got %v, want %v", tc.number, tc.userId, err, tc.want)
+			}
+		}
+		cards, err := ts.GetScorecards(ctx, tasting.ID)
+		if err != nil || len(cards) != 3 {
+			t.Fatalf("getting scorecards: got %+v, %v", cards, err)
+		}
+		for i, want := range []struct {
+			number   int64
+			username string
+			overall  float64
+		}{{1, "alice", 1}, {1, "bob", 7}, {2, "bob", 5}} {
+			if got := cards[i]; got.TastingScorecard.Number != want.number || got.Username != want.username || got.TastingScorecard.Overall != want.overall {
+				t.Errorf("scorecard %d: got %+v, want %+v", i, got, want)
+			}
+		}
+
+		// Removing a pour takes its scores with it
+		third, _ := ts.AddPour(ctx, later.ID, pale.ID)
+		ts.SaveScorecard(ctx, later.ID, third.Number, bob.ID, scores)
+		if err := ts.RemovePour(ctx, later.ID, third.Number); err != nil {
+			t.Errorf("removing pour: %v", err)
+		}
+		if err := ts.RemovePour(ctx, later.ID, third.Number); err != (tastings.ErrPourNotFound{TastingID: later.ID, Number: third.Number}) {
+			t.Errorf("removing missing pour: got %v", err)
+		}
+		if cards, _ := ts.GetScorecards(ctx, later.ID); len(cards) != 0 {
+			t.Errorf("got scorecards for a removed pour: %+v", cards)
+		}
+
+		// Nothing changes after the reveal
+		revealed, err := ts.Reveal(ctx, tasting.ID)
+		if err != nil || !revealed.RevealedAt.Valid {
+			t.Errorf("revealing: got %+v, %v", revealed, err)
+		}
+		isRevealed := tastings.ErrTastingRevealed{ID: tasting.ID}
+		if _, err := ts.Reveal(ctx, tasting.ID); err != isRevealed {
+			t.Errorf("revealing again: got %v", err)
+		}
+		if _, err := ts.Reveal(ctx, 999); err != (tastings.ErrTastingNotFound{ID: 999}) {
+			t.Errorf("revealing missing tasting: got %v", err)
+		}
+		if _, err := ts.AddPour(ctx, tasting.ID, pale.ID); err != isRevealed {
+			t.Errorf("pouring after the reveal: got %v", err)
+		}
+		if err := ts.RemovePour(ctx, tasting.ID, 1); err != isRevealed {
+			t.Errorf("removing a pour after the reveal: got %v", err)
+		}
+		if _, err := ts.SaveScorecard(ctx, tasting.ID, 1, bob.ID, scores); err != isRevealed {
+			t.Errorf("scoring after the reveal: got %v", err)
+		}
+
+		if _, err := ts.DeleteTasting(ctx, tasting.ID); err != nil {
+			t.Errorf("deleting tasting: %v", err)
+		}
+		if _, err := ts.DeleteTasting(ctx, tasting.ID); err != (tastings.ErrTastingNotFound{ID: tasting.ID}) {
+			t.Errorf("deleting tasting again: got %v", err)
+		}
+		if pours, _ := ts.GetPours(ctx, tasting.ID); len(pours) != 0 {
+			t.Errorf("got pours for a deleted tasting: %+v", pours)
+		}
+	})
+}
+
+func TestBadgeStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		bs := stores.Badges
+
+		if _, err := styles.Seed(ctx, stores.Styles); err != nil {
+			t.Fatalf("seeding styles: %v", err)
+		}
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Garage Project", Location: sql.NullString{Valid: true, String: "Wellington"}})
+		brewerId := sql.NullInt64{Valid: true, Int64: brewer.ID}
+		rating := sql.NullFloat64{Valid: true, Float64: 4}
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", BrewerID: brewerId, Style: sql.NullString{Valid: true, String: "american ipa"}, Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+		// Editing a beer doesn't make whoever edited it the one who added it
+		if _, err := stores.Beers.UpdateBeer(ctx, bob.ID, db.UpdateBeerParams{ID: stout.ID, Name: sql.NullString{Valid: true, String: "Oatmeal Stout"}}); err != nil {
+			t.Fatalf("updating beer: %v", err)
+		}
+		if _, err := stores.Scorecards.SaveScorecard(ctx, pale.ID, bob.ID, scorecards.Scores{7, 7, 7, 7, 7}); err != nil {
+			t.Fatalf("saving scorecard: %v", err)
+		}
+
+		facts, err := bs.GetUserFacts(ctx, alice.ID)
+		if err != nil || len(facts) != 2 {
+			t.Fatalf("getting alice's facts: got %+v, %v", facts, err)
+		}
+		want := badges.Fact{UserID: alice.ID, Username: "alice", Action: badges.Added, BeerID: pale.ID, Style: "american ipa", Category: "IPA", BrewerID: brewer.ID, City: "Wellington"}
+		if want.At = facts[0].At; facts[0] != want || want.At.IsZero() {
+			t.Errorf("got alice's first fact %+v, want %+v", facts[0], want)
+		}
+		if got := facts[1]; got.Action != badges.Added || got.BeerID != stout.ID || got.BrewerID != 0 || got.City != "" || got.Category != "" {
+			t.Errorf("got alice's second fact %+v, want the stout without a brewer", got)
+		}
+
+		facts, err = bs.GetUserFacts(ctx, bob.ID)
+		if err != nil || len(facts) != 1 || facts[0].Action != badges.Rated || facts[0].BeerID != pale.ID || facts[0].Username != "bob" {
+			t.Errorf("getting bob's facts: got %+v, %v", facts, err)
+		}
+		// Scoring the beer again doesn't change when it was rated
+		ratedAt := facts[0].At
+		if _, err := stores.Scorecards.SaveScorecard(ctx, pale.ID, bob.ID, scorecards.Scores{8, 8, 8, 8, 8}); err != nil {
+			t.Fatalf("saving scorecard again: %v", err)
+		}
+		facts, err = bs.GetUserFacts(ctx, bob.ID)
+		if err != nil || len(facts) != 1 || !facts[0].At.Equal(ratedAt) {
+			t.Errorf("getting bob's facts after scoring again: got %+v, %v, want rated at %v", facts, err, ratedAt)
+		}
+
+		now := time.Now()
+		facts, err = bs.GetFacts(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+		if err != nil || len(facts) != 3 {
+			t.Errorf("getting this hour's facts: got %+v, %v", facts, err)
+		}
+		facts, err = bs.GetFacts(ctx, now.Add(time.Hour), now.Add(2*time.Hour))
+		if err != nil || len(facts) != 0 {
+			t.Errorf("getting next hour's facts: got %+v, %v", facts, err)
+		}
+
+		// Deleted beers don't count
+		if _, err := stores.Beers.DeleteBeer(ctx, pale.ID); err != nil {
+			t.Fatalf("deleting beer: %v", err)
+		}
+		facts, err = bs.GetUserFacts(ctx, bob.ID)
+		if err != nil || len(facts) != 0 {
+			t.Errorf("getting bob's facts after deleting the beer: got %+v, %v", facts, err)
+		}
+	})
+}
+
+func TestWishlistStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, stores storetest.Stores) {
+		ctx := context.Background()
+		ws := stores.Wishlist
+
+		alice, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "alice", PasswordHash: "hash"})
+		bob, _ := stores.Users.AddUser(ctx, db.AddUserParams{Username: "bob", PasswordHash: "hash"})
+		brewer, _ := stores.Brewers.AddBrewer(ctx, db.AddBrewerParams{Name: "Felon's"})
+		rating := sql.NullFloat64{Valid: true, Float64: 4}
+		pale, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Pale", BrewerID: sql.NullInt64{Valid: true, Int64: brewer.ID}, Abv: 5, Rating: rating})
+		stout, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Stout", Abv: 6, Rating: rating})
+		lager, _ := stores.Beers.AddBeer(ctx, alice.ID, db.AddBeerParams{Name: "Lager", Abv: 4, Rating: rating})
+		high := sql.NullInt64{Valid: true, Int64: wishlist.HighPriority}
+
+		wish, err := ws.AddWish(ctx, db.AddWishParams{UserID: alice.ID, BeerID: stout.ID, Note: sql.NullString{Valid: true, String: " "}})
+		if err != nil || wish.Priority.Valid || wish.Note.Valid || wish.CreatedAt.IsZero() {
+			t.Fatalf("adding wish: got %+v, %v", wish, err)
+		}
+		if _, err := ws.AddWish(ctx, db.AddWishParams{UserID: alice.ID, BeerID: pale.ID, Priority: high, Note: sql.NullString{Valid: true, String: " On tap at Felon's "}}); err != nil {
+			t.Fatalf("adding wish: %v", err)
+		}
+		if _, err := ws.AddWish(ctx, db.AddWishParams{UserID: alice.ID, BeerID: lager.ID, Priority: sql.NullInt64{Valid: true, Int64: wishlist.LowPriority}}); err != nil {
+			t.Fatalf("adding wish: %v", err)
+		}
+		if _, err := ws.AddWish(ctx, db.AddWishParams{UserID: bob.ID, BeerID: stout.ID}); err != nil {
+			t.Fatalf("adding bob's wish: %v", err)
+		}
+		for _, tc := range []struct {
+			params db.AddWishParams
+			want   error
+		}{
+			{db.AddWishParams{UserID: alice.ID, BeerID: stout.ID}, wishlist.ErrAlreadyWished{UserID: alice.ID, BeerID: stout.ID}},
+			{db.AddWishParams{UserID: alice.ID, BeerID: 999}, beers.ErrBeerNotFound{ID: 999}},
+			{db.AddWishParams{UserID: bob.ID, BeerID: pale.ID, Priority: sql.NullInt64{Valid: true, Int64: 4}}, store.ErrInvalidField{Field: "priority", Reason: "must be between 1 and 3"}},
+		} {
+			if _, err := ws.AddWish(ctx, tc.params); err != tc.want {
+				t.Errorf("adding wish %+v: got %v, want %v", tc.params, err, tc.want)
+			}
+		}
+
+		// The highest priority first, and those without one last
+		rows, err := ws.GetWishlist(ctx, alice.ID)
+		if err != nil || len(rows) != 3 {
+			t.Fatalf("getting wishlist: got %+v, %v", rows, err)
+		}
+		if got := rows[0]; got.Beer.ID != pale.ID || got.Wishlist.Note.String != "On tap at Felon's" || got.BrewerName.String != "Felon's" {
+			t.Errorf("got first wish %+v, want the pale with its note and brewer", got)
+		}
+		if rows[1].Beer.ID != lager.ID || rows[2].Beer.ID != stout.ID || rows[2].BrewerName.Valid {
+			t.Errorf("got wishes %+v, want the lager then the stout", rows)
+		}
+
+		wish, err = ws.UpdateWish(ctx, db.UpdateWishParams{UserID: alice.ID, BeerID: stout.ID, Priority: high, Note: sql.NullString{Valid: true, String: "Winter"}})
+		if err != nil || wish.Priority != high || wish.Note.String != "Winter" {
+			t.Errorf("updating wish: got %+v, %v", wish, err)
+		}
+		if _, err := ws.UpdateWish(ctx, db.UpdateWishParams{UserID: bob.ID, BeerID: pale.ID}); err != (wishlist.ErrWishNotFound{UserID: bob.ID, BeerID: pale.ID}) {
+			t.Errorf("updating missing wish: got %v", err)
+		}
+		if _, err := ws.UpdateWish(ctx, db.UpdateWishParams{UserID: alice.ID, BeerID: stout.ID, Priority: sql.NullInt64{Valid: true, Int64: 0}}); err != (store.ErrInvalidField{Field: "priority", Reason: "must be between 1 and 3"}) {
+			t.Errorf("updating wish with a bad priority: got %v", err)
+		}
+
+		if err := ws.RemoveWish(ctx, alice.ID, stout.ID); err != nil {
+			t.Errorf("removing wish: %v", err)
+		}
+		if err := ws.RemoveWish(ctx, alice.ID, stout.ID); err != (wishlist.ErrWishNotFound{UserID: alice.ID, BeerID: stout.ID}) {
+			t.Errorf("removing wish twice: got %v", err)
+		}
+		// Bob's list is his own
+		if rows, err := ws.GetWishlist(ctx, bob.ID); err != nil || len(rows) != 1 || rows[0].Beer.ID != stout.ID {
+			t.Errorf("getting bob's wishlist: got %+v, %v", rows, err)
+		}
+
+		// Beers in the trash are left out
+		if _, err := stores.Beers.DeleteBeer(ctx, lager.ID); err != nil {
+			t.Fatalf("deleting beer: %v", err)
+		}
+		if rows, err := ws.GetWishlist(ctx, alice.ID); err != nil || len(rows) != 1 || rows[0].Beer.ID != pale.ID {
+			t.Errorf("getting wishlist after deleting a beer: got %+v, %v", rows, err)
+		}
+	})
+}
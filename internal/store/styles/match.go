@@ -0,0 +1,133 @@
+package styles
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// How similar free text has to be to a style's name or one of its aliases, from 0 to 1, to be
+// taken as a misspelling of it
+const matchThreshold = 0.8
+
+var accents = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss", "é", "e", "è", "e", "ê", "e")
+
+// Lower cases the text, drops accents and punctuation and collapses spaces, so "Kölsch" and
+// "kolsch" or "Hazy-IPA" and "hazy ipa" compare equal
+func normalize(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// The aliases from the embedded guidelines, keyed by style name
+func aliases() map[string][]string {
+	byName := map[string][]string{}
+	for _, g := range Guidelines() {
+		byName[g.Name] = g.Aliases
+	}
+	return byName
+}
+
+// Finds the style some free text most likely refers to. The text matches a style if it's the
+// style's name, code or one of its common aliases ("ipa", "hefeweizen"), ignoring case, accents
+// and punctuation, or failing that if it's a close misspelling of the name or an alias.
+func Match(styles []db.Style, text string) (db.Style, bool) {
+	q := normalize(text)
+	if q == "" {
+		return db.Style{}, false
+	}
+
+	styleAliases := aliases()
+	best, bestSimilarity := db.Style{}, 0.0
+	for _, style := range styles {
+		names := []string{style.Name}
+		names = append(names, styleAliases[style.Name]...)
+
+		if q == normalize(style.Code) {
+			return style, true
+		}
+		for _, name := range names {
+			n := normalize(name)
+			if q == n {
+				return style, true
+			}
+			if s := similarity(q, n); s > bestSimilarity {
+				best, bestSimilarity = style, s
+			}
+		}
+	}
+
+	if bestSimilarity >= matchThreshold {
+		return best, true
+	}
+	return db.Style{}, false
+}
+
+// The Levenshtein distance between a and b, scaled to 1 when they're equal and 0 when they have
+// nothing in common
+func similarity(a string, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := max(len(ar), len(br))
+	if longest == 0 {
+		return 1
+	}
+
+	// The distances from the prefixes of a to the previous and current prefix of b
+	prev := make([]int, len(ar)+1)
+	curr := make([]int, len(ar)+1)
+	for i := range prev {
+		prev[i] = i
+	}
+	for j := 1; j <= len(br); j++ {
+		curr[0] = j
+		for i := 1; i <= len(ar); i++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[i] = min(prev[i]+1, curr[i-1]+1, prev[i-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(ar)])/float64(longest)
+}
+
+// Maps the free text styles existing beers were given onto the style names, so "ipa", "IPA" and
+// "India Pale Ale" all become "American IPA". Styles which don't match are left as they are.
+// Returns how many beers were changed.
+func MigrateBeerStyles(ctx context.Context, styleStore Store, beerStore beers.Store, logger *log.Logger) (int64, error) {
+	styles, err := styleStore.GetStyles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	beerStyles, err := beerStore.GetBeerStyles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := int64(0)
+	for _, beerStyle := range beerStyles {
+		style, ok := Match(styles, beerStyle)
+		if !ok {
+			logger.Printf("no style matches %q, leaving it as it is", beerStyle)
+			continue
+		}
+		if style.Name == beerStyle {
+			continue
+		}
+
+		count, err := beerStore.RenameBeerStyle(ctx, beerStyle, style.Name)
+		if err != nil {
+			return migrated, err
+		}
+		logger.Printf("style %q mapped to %q for %d beers", beerStyle, style.Name, count)
+		migrated += count
+	}
+	return migrated, nil
+}
@@ -0,0 +1,59 @@
+package styles
+
+import (
+	"beer_oclock/internal/db"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	var all []db.Style
+	for i, g := range Guidelines() {
+		all = append(all, db.Style{ID: int64(i + 1), Code: g.Code, Name: g.Name})
+	}
+
+	for _, tt := range []struct {
+		text string
+		want string
+	}{
+		{"American IPA", "American IPA"},
+		{"american ipa", "American IPA"},
+		{"IPA", "American IPA"},
+		{"India Pale Ale", "American IPA"},
+		{"  India-Pale-Ale ", "American IPA"},
+		{"Amercan IPA", "American IPA"},
+		{"21A", "American IPA"},
+		{"NEIPA", "Hazy IPA"},
+		{"Kolsch", "Kölsch"},
+		{"Hefeweizen", "Weissbier"},
+		{"Oatmeal Stuot", "Oatmeal Stout"},
+		{"Imperial IPA", "Double IPA"},
+		{"Pastry Sour", ""},
+		{"APA", "American Pale Ale"},
+		{"", ""},
+	} {
+		got, ok := Match(all, tt.text)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Match(%q): got %s, want no match", tt.text, got.Name)
+			}
+			continue
+		}
+		if !ok || got.Name != tt.want {
+			t.Errorf("Match(%q): got %q (%v), want %q", tt.text, got.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestGuidelines(t *testing.T) {
+	codes := map[string]bool{}
+	names := map[string]bool{}
+	for _, g := range Guidelines() {
+		if codes[g.Code] || names[g.Name] {
+			t.Errorf("duplicate style %s %s", g.Code, g.Name)
+		}
+		codes[g.Code], names[g.Name] = true, true
+		if g.Abv[0] > g.Abv[1] || g.Ibu[0] > g.Ibu[1] || g.Srm[0] > g.Srm[1] || g.Description == "" {
+			t.Errorf("invalid guideline %+v", g)
+		}
+	}
+}
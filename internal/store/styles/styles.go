@@ -0,0 +1,82 @@
+package styles
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// The operations the rest of the app needs on beer styles, implemented by StyleStore (backed by
+// the database) and MemoryStyleStore (for tests)
+type Store interface {
+	AddStyle(ctx context.Context, params db.AddStyleParams) (db.Style, error)
+	GetStyle(ctx context.Context, id int64) (db.Style, error)
+	GetStyleByName(ctx context.Context, name string) (db.Style, error)
+	GetStyles(ctx context.Context) ([]db.Style, error)
+	SearchStyles(ctx context.Context, query string, maxResults int64) ([]db.Style, error)
+}
+
+var _ Store = (*StyleStore)(nil)
+var _ Store = (*MemoryStyleStore)(nil)
+
+// The style guidelines the styles table is seeded from, based on the BJCP 2021 guidelines. Each
+// range is [min, max].
+//
+//go:embed styles.json
+var guidelinesJson []byte
+
+type Guideline struct {
+	Code        string     `json:"code"`
+	Category    string     `json:"category"`
+	Name        string     `json:"name"`
+	Abv         [2]float64 `json:"abv"`
+	Ibu         [2]float64 `json:"ibu"`
+	Srm         [2]float64 `json:"srm"`
+	Description string     `json:"description"`
+	// Other names people commonly give the style, used when matching free text to a style
+	Aliases []string `json:"aliases"`
+}
+
+var Guidelines = sync.OnceValue(func() []Guideline {
+	var guidelines []Guideline
+	if err := json.Unmarshal(guidelinesJson, &guidelines); err != nil {
+		panic(fmt.Sprintf("invalid embedded styles.json: %v", err))
+	}
+	return guidelines
+})
+
+// Adds any of the embedded guidelines which aren't in the store yet, returning how many were added
+func Seed(ctx context.Context, styleStore Store) (int, error) {
+	added := 0
+	for _, g := range Guidelines() {
+		_, err := styleStore.AddStyle(ctx, db.AddStyleParams{
+			Code:        g.Code,
+			Category:    g.Category,
+			Name:        g.Name,
+			AbvMin:      g.Abv[0],
+			AbvMax:      g.Abv[1],
+			IbuMin:      g.Ibu[0],
+			IbuMax:      g.Ibu[1],
+			SrmMin:      g.Srm[0],
+			SrmMax:      g.Srm[1],
+			Description: g.Description,
+		})
+		if err != nil {
+			if errors.As(err, &ErrStyleAlreadyExists{}) {
+				continue
+			}
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Whether the ABV is within the style's guidelines
+func AbvInRange(style db.Style, abv float64) bool {
+	return abv >= style.AbvMin && abv <= style.AbvMax
+}
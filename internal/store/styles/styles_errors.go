@@ -0,0 +1,23 @@
+package styles
+
+import "fmt"
+
+type ErrStyleAlreadyExists struct {
+	Name string
+}
+
+func (e ErrStyleAlreadyExists) Error() string {
+	return fmt.Sprintf("style with name or code %s already exists", e.Name)
+}
+
+type ErrStyleNotFound struct {
+	ID   int64
+	Name string
+}
+
+func (e ErrStyleNotFound) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("style with name %s not found", e.Name)
+	}
+	return fmt.Sprintf("style with id %d not found", e.ID)
+}
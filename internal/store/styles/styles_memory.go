@@ -0,0 +1,97 @@
+package styles
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as StyleStore
+type MemoryStyleStore struct {
+	mu     sync.Mutex
+	lastId int64
+	styles []db.Style
+}
+
+func NewMemoryStyleStore() *MemoryStyleStore {
+	return &MemoryStyleStore{}
+}
+
+func (ss *MemoryStyleStore) AddStyle(ctx context.Context, params db.AddStyleParams) (db.Style, error) {
+	if err := validateStyle(params); err != nil {
+		return db.Style{}, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if slices.ContainsFunc(ss.styles, func(s db.Style) bool { return s.Name == params.Name || s.Code == params.Code }) {
+		return db.Style{}, ErrStyleAlreadyExists{Name: params.Name}
+	}
+
+	ss.lastId++
+	style := db.Style{
+		ID:          ss.lastId,
+		Code:        params.Code,
+		Category:    params.Category,
+		Name:        params.Name,
+		AbvMin:      params.AbvMin,
+		AbvMax:      params.AbvMax,
+		IbuMin:      params.IbuMin,
+		IbuMax:      params.IbuMax,
+		SrmMin:      params.SrmMin,
+		SrmMax:      params.SrmMax,
+		Description: params.Description,
+	}
+	ss.styles = append(ss.styles, style)
+	return style, nil
+}
+
+func (ss *MemoryStyleStore) GetStyle(ctx context.Context, id int64) (db.Style, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.styles, func(s db.Style) bool { return s.ID == id })
+	if i < 0 {
+		return db.Style{}, ErrStyleNotFound{ID: id}
+	}
+	return ss.styles[i], nil
+}
+
+func (ss *MemoryStyleStore) GetStyleByName(ctx context.Context, name string) (db.Style, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := slices.IndexFunc(ss.styles, func(s db.Style) bool { return strings.EqualFold(s.Name, name) })
+	if i < 0 {
+		return db.Style{}, ErrStyleNotFound{Name: name}
+	}
+	return ss.styles[i], nil
+}
+
+func (ss *MemoryStyleStore) GetStyles(ctx context.Context) ([]db.Style, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return slices.Clone(ss.styles), nil
+}
+
+func (ss *MemoryStyleStore) SearchStyles(ctx context.Context, query string, maxResults int64) ([]db.Style, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	q := strings.ToLower(query)
+	styles := []db.Style{}
+	for _, s := range ss.styles {
+		if int64(len(styles)) >= maxResults {
+			break
+		}
+		if strings.Contains(strings.ToLower(s.Name), q) || strings.Contains(strings.ToLower(s.Category), q) || strings.EqualFold(s.Code, query) {
+			styles = append(styles, s)
+		}
+	}
+	return styles, nil
+}
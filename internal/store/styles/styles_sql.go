@@ -0,0 +1,104 @@
+package styles
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type StyleStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewStyleStore(queries db.Querier, logger *log.Logger) *StyleStore {
+	return &StyleStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func validateStyle(params db.AddStyleParams) error {
+	if params.Code == "" {
+		return store.ErrMissingField{Field: "code"}
+	}
+	if params.Category == "" {
+		return store.ErrMissingField{Field: "category"}
+	}
+	if params.Name == "" {
+		return store.ErrMissingField{Field: "name"}
+	}
+	if params.AbvMin < 0 || params.AbvMin > params.AbvMax {
+		return store.ErrInvalidField{Field: "abv", Reason: "must be a range of >= 0"}
+	}
+	return nil
+}
+
+func (ss *StyleStore) AddStyle(ctx context.Context, params db.AddStyleParams) (db.Style, error) {
+	zero := db.Style{}
+
+	if err := validateStyle(params); err != nil {
+		return zero, err
+	}
+
+	style, err := ss.queries.AddStyle(ctx, params)
+	if err != nil {
+		// Nothing is returned when the insert is skipped because of a clash
+		if err == sql.ErrNoRows {
+			return zero, ErrStyleAlreadyExists{Name: params.Name}
+		}
+		ss.logger.Printf("error adding style: %v", err)
+		return zero, err
+	}
+
+	return style, nil
+}
+
+func (ss *StyleStore) GetStyle(ctx context.Context, id int64) (db.Style, error) {
+	style, err := ss.queries.GetStyleById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Style{}, ErrStyleNotFound{ID: id}
+		}
+		ss.logger.Printf("error getting style: %v", err)
+		return db.Style{}, err
+	}
+	return style, nil
+}
+
+// Gets a style by its name, ignoring case
+func (ss *StyleStore) GetStyleByName(ctx context.Context, name string) (db.Style, error) {
+	style, err := ss.queries.GetStyleByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Style{}, ErrStyleNotFound{Name: name}
+		}
+		ss.logger.Printf("error getting style by name: %v", err)
+		return db.Style{}, err
+	}
+	return style, nil
+}
+
+func (ss *StyleStore) GetStyles(ctx context.Context) ([]db.Style, error) {
+	styles, err := ss.queries.GetStyles(ctx)
+	if err != nil {
+		ss.logger.Printf("error getting styles: %v", err)
+		return nil, err
+	}
+	return styles, nil
+}
+
+// The first few styles whose name or category contains the query, or whose code is the query
+func (ss *StyleStore) SearchStyles(ctx context.Context, query string, maxResults int64) ([]db.Style, error) {
+	styles, err := ss.queries.SearchStyles(ctx, db.SearchStylesParams{
+		Query:      sql.NullString{Valid: true, String: query},
+		MaxResults: maxResults,
+	})
+	if err != nil {
+		ss.logger.Printf("error searching styles: %v", err)
+		return nil, err
+	}
+	return styles, nil
+}
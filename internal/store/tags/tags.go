@@ -0,0 +1,100 @@
+package tags
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// The operations the rest of the app needs on flavour tags, implemented by TagStore (backed by the
+// database) and MemoryTagStore (for tests)
+type Store interface {
+	AddTag(ctx context.Context, params db.AddTagParams) (db.Tag, error)
+	GetTagByName(ctx context.Context, name string) (db.Tag, error)
+	GetTags(ctx context.Context) ([]db.Tag, error)
+	GetTagCounts(ctx context.Context) ([]db.GetTagCountsRow, error)
+	GetBeerTags(ctx context.Context, beerId int64) ([]db.Tag, error)
+	GetTagsByBeer(ctx context.Context) (map[int64][]db.Tag, error)
+	SetBeerTags(ctx context.Context, beerId int64, names []string) ([]db.Tag, error)
+	GetBeersByTag(ctx context.Context, name string) ([]db.Beer, error)
+}
+
+var _ Store = (*TagStore)(nil)
+var _ Store = (*MemoryTagStore)(nil)
+
+// The longest a tag can be, so they fit on a chip
+const maxNameLength = 30
+
+// The curated tags, grouped by their category on the flavour wheel
+var Vocabulary = []struct {
+	Category string
+	Names    []string
+}{
+	{"Fruity", []string{"citrus", "tropical", "stone fruit", "berry", "dark fruit", "apple"}},
+	{"Hoppy", []string{"piney", "resinous", "floral", "grassy", "herbal", "dank"}},
+	{"Malty", []string{"bready", "biscuity", "caramel", "toffee", "honey"}},
+	{"Roasty", []string{"roasty", "coffee", "chocolate", "smoky"}},
+	{"Yeasty", []string{"banana", "clove", "peppery", "funky"}},
+	{"Sour", []string{"sour", "tart", "lactic"}},
+	{"Character", []string{"sweet", "bitter", "dry", "crisp", "boozy", "vanilla", "oaky"}},
+}
+
+// Adds any of the curated tags which aren't in the store yet, returning how many were added
+func Seed(ctx context.Context, tagStore Store) (int, error) {
+	added := 0
+	for _, group := range Vocabulary {
+		for _, name := range group.Names {
+			_, err := tagStore.AddTag(ctx, db.AddTagParams{
+				Name:     name,
+				Category: sql.NullString{Valid: true, String: group.Category},
+			})
+			if err != nil {
+				if errors.As(err, &ErrTagAlreadyExists{}) {
+					continue
+				}
+				return added, err
+			}
+			added++
+		}
+	}
+	return added, nil
+}
+
+// Tags are lowercase with single spaces, so "Stone  Fruit" and "stone fruit" are the same tag
+func Normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// Splits a comma separated list of tags, normalizing them and leaving out blanks and repeats
+func Parse(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		name = Normalize(name)
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Checks a normalized tag name, so forms can check new tags before saving anything
+func ValidateName(name string) error {
+	if name == "" {
+		return store.ErrMissingField{Field: "name"}
+	}
+	if len(name) > maxNameLength {
+		return store.ErrInvalidField{Field: "name", Reason: "must be at most 30 characters"}
+	}
+	if strings.Contains(name, ",") {
+		return store.ErrInvalidField{Field: "name", Reason: "must not contain commas"}
+	}
+	return nil
+}
+
+func validateTag(params db.AddTagParams) error {
+	return ValidateName(params.Name)
+}
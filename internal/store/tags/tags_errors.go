@@ -0,0 +1,19 @@
+package tags
+
+import "fmt"
+
+type ErrTagAlreadyExists struct {
+	Name string
+}
+
+func (e ErrTagAlreadyExists) Error() string {
+	return fmt.Sprintf("tag %s already exists", e.Name)
+}
+
+type ErrTagNotFound struct {
+	Name string
+}
+
+func (e ErrTagNotFound) Error() string {
+	return fmt.Sprintf("tag %s not found", e.Name)
+}
@@ -0,0 +1,176 @@
+package tags
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store/beers"
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as TagStore. The beer store stands in for the foreign key.
+type MemoryTagStore struct {
+	mu        sync.Mutex
+	beerStore beers.Store
+	lastId    int64
+	tags      []db.Tag
+	beerTags  []db.BeerTag
+}
+
+func NewMemoryTagStore(beerStore beers.Store) *MemoryTagStore {
+	return &MemoryTagStore{
+		beerStore: beerStore,
+	}
+}
+
+func byName(a, b db.Tag) int {
+	return cmp.Compare(a.Name, b.Name)
+}
+
+func (ts *MemoryTagStore) AddTag(ctx context.Context, params db.AddTagParams) (db.Tag, error) {
+	params.Name = Normalize(params.Name)
+	if err := validateTag(params); err != nil {
+		return db.Tag{}, err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if slices.ContainsFunc(ts.tags, func(t db.Tag) bool { return t.Name == params.Name }) {
+		return db.Tag{}, ErrTagAlreadyExists{Name: params.Name}
+	}
+
+	ts.lastId++
+	tag := db.Tag{ID: ts.lastId, Name: params.Name, Category: params.Category}
+	ts.tags = append(ts.tags, tag)
+	return tag, nil
+}
+
+func (ts *MemoryTagStore) GetTagByName(ctx context.Context, name string) (db.Tag, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	name = Normalize(name)
+	i := slices.IndexFunc(ts.tags, func(t db.Tag) bool { return t.Name == name })
+	if i < 0 {
+		return db.Tag{}, ErrTagNotFound{Name: name}
+	}
+	return ts.tags[i], nil
+}
+
+func (ts *MemoryTagStore) GetTags(ctx context.Context) ([]db.Tag, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return slices.SortedFunc(slices.Values(ts.tags), byName), nil
+}
+
+func (ts *MemoryTagStore) GetTagCounts(ctx context.Context) ([]db.GetTagCountsRow, error) {
+	// Only beers which haven't been deleted count, like the join in the query
+	live, err := ts.beerStore.GetBeers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, _ := ts.GetTags(ctx)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	counts := make([]db.GetTagCountsRow, len(tags))
+	for i, tag := range tags {
+		counts[i].Tag = tag
+		for _, bt := range ts.beerTags {
+			if bt.TagID == tag.ID && slices.ContainsFunc(live, func(b db.Beer) bool { return b.ID == bt.BeerID }) {
+				counts[i].BeerCount++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// The tags of the beer, or of every beer if beerId is 0
+func (ts *MemoryTagStore) tagsOf(beerId int64) map[int64][]db.Tag {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	tagsByBeer := make(map[int64][]db.Tag)
+	for _, bt := range ts.beerTags {
+		if beerId != 0 && bt.BeerID != beerId {
+			continue
+		}
+		i := slices.IndexFunc(ts.tags, func(t db.Tag) bool { return t.ID == bt.TagID })
+		tagsByBeer[bt.BeerID] = append(tagsByBeer[bt.BeerID], ts.tags[i])
+	}
+	for _, tags := range tagsByBeer {
+		slices.SortFunc(tags, byName)
+	}
+	return tagsByBeer
+}
+
+func (ts *MemoryTagStore) GetBeerTags(ctx context.Context, beerId int64) ([]db.Tag, error) {
+	tags := ts.tagsOf(beerId)[beerId]
+	if tags == nil {
+		tags = []db.Tag{}
+	}
+	return tags, nil
+}
+
+func (ts *MemoryTagStore) GetTagsByBeer(ctx context.Context) (map[int64][]db.Tag, error) {
+	return ts.tagsOf(0), nil
+}
+
+func (ts *MemoryTagStore) SetBeerTags(ctx context.Context, beerId int64, names []string) ([]db.Tag, error) {
+	ts.mu.Lock()
+	ts.beerTags = slices.DeleteFunc(ts.beerTags, func(bt db.BeerTag) bool { return bt.BeerID == beerId })
+	ts.mu.Unlock()
+
+	for _, name := range names {
+		tag, err := ts.AddTag(ctx, db.AddTagParams{Name: name})
+		if errors.As(err, &ErrTagAlreadyExists{}) {
+			tag, err = ts.GetTagByName(ctx, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := ts.beerStore.GetBeer(ctx, beerId); err != nil {
+			return nil, beers.ErrBeerNotFound{ID: beerId}
+		}
+
+		ts.mu.Lock()
+		bt := db.BeerTag{BeerID: beerId, TagID: tag.ID}
+		if !slices.Contains(ts.beerTags, bt) {
+			ts.beerTags = append(ts.beerTags, bt)
+		}
+		ts.mu.Unlock()
+	}
+
+	return ts.GetBeerTags(ctx, beerId)
+}
+
+func (ts *MemoryTagStore) GetBeersByTag(ctx context.Context, name string) ([]db.Beer, error) {
+	live, err := ts.beerStore.GetBeers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := ts.GetTagByName(ctx, name)
+	if err != nil {
+		return []db.Beer{}, nil
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	tagged := []db.Beer{}
+	for _, beer := range live {
+		if slices.Contains(ts.beerTags, db.BeerTag{BeerID: beer.ID, TagID: tag.ID}) {
+			tagged = append(tagged, beer)
+		}
+	}
+	return tagged, nil
+}
@@ -0,0 +1,138 @@
+package tags
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+)
+
+type TagStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewTagStore(queries db.Querier, logger *log.Logger) *TagStore {
+	return &TagStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ts *TagStore) AddTag(ctx context.Context, params db.AddTagParams) (db.Tag, error) {
+	zero := db.Tag{}
+
+	params.Name = Normalize(params.Name)
+	if err := validateTag(params); err != nil {
+		return zero, err
+	}
+
+	tag, err := ts.queries.AddTag(ctx, params)
+	if err != nil {
+		// Nothing is returned when the insert is skipped because of a clash
+		if err == sql.ErrNoRows {
+			return zero, ErrTagAlreadyExists{Name: params.Name}
+		}
+		ts.logger.Printf("error adding tag: %v", err)
+		return zero, err
+	}
+
+	return tag, nil
+}
+
+func (ts *TagStore) GetTagByName(ctx context.Context, name string) (db.Tag, error) {
+	name = Normalize(name)
+	tag, err := ts.queries.GetTagByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Tag{}, ErrTagNotFound{Name: name}
+		}
+		ts.logger.Printf("error getting tag by name: %v", err)
+		return db.Tag{}, err
+	}
+	return tag, nil
+}
+
+func (ts *TagStore) GetTags(ctx context.Context) ([]db.Tag, error) {
+	tags, err := ts.queries.GetTags(ctx)
+	if err != nil {
+		ts.logger.Printf("error getting tags: %v", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Every tag with how many beers it's on, not counting deleted beers
+func (ts *TagStore) GetTagCounts(ctx context.Context) ([]db.GetTagCountsRow, error) {
+	counts, err := ts.queries.GetTagCounts(ctx)
+	if err != nil {
+		ts.logger.Printf("error getting tag counts: %v", err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (ts *TagStore) GetBeerTags(ctx context.Context, beerId int64) ([]db.Tag, error) {
+	tags, err := ts.queries.GetBeerTags(ctx, beerId)
+	if err != nil {
+		ts.logger.Printf("error getting beer tags: %v", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// The tags of every beer, in one query for listing beers
+func (ts *TagStore) GetTagsByBeer(ctx context.Context) (map[int64][]db.Tag, error) {
+	rows, err := ts.queries.GetAllBeerTags(ctx)
+	if err != nil {
+		ts.logger.Printf("error getting all beer tags: %v", err)
+		return nil, err
+	}
+	tagsByBeer := make(map[int64][]db.Tag)
+	for _, row := range rows {
+		tagsByBeer[row.BeerID] = append(tagsByBeer[row.BeerID], row.Tag)
+	}
+	return tagsByBeer, nil
+}
+
+// Replaces the beer's tags with the named ones, adding any which don't exist yet as user tags
+func (ts *TagStore) SetBeerTags(ctx context.Context, beerId int64, names []string) ([]db.Tag, error) {
+	if err := ts.queries.ClearBeerTags(ctx, beerId); err != nil {
+		ts.logger.Printf("error clearing beer tags: %v", err)
+		return nil, err
+	}
+
+	for _, name := range names {
+		tag, err := ts.AddTag(ctx, db.AddTagParams{Name: name})
+		if errors.As(err, &ErrTagAlreadyExists{}) {
+			tag, err = ts.GetTagByName(ctx, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = ts.queries.AddBeerTag(ctx, db.AddBeerTagParams{BeerID: beerId, TagID: tag.ID})
+		if err != nil {
+			if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+				return nil, beers.ErrBeerNotFound{ID: beerId}
+			}
+			ts.logger.Printf("error adding beer tag: %v", err)
+			return nil, err
+		}
+	}
+
+	ts.logger.Printf("tags of beer %d set to %v", beerId, names)
+	return ts.GetBeerTags(ctx, beerId)
+}
+
+func (ts *TagStore) GetBeersByTag(ctx context.Context, name string) ([]db.Beer, error) {
+	beers, err := ts.queries.GetBeersByTag(ctx, Normalize(name))
+	if err != nil {
+		ts.logger.Printf("error getting beers by tag: %v", err)
+		return nil, err
+	}
+	return beers, nil
+}
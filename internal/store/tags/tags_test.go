@@ -0,0 +1,22 @@
+package tags
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"", []string{}},
+		{"Citrus", []string{"citrus"}},
+		{" stone   Fruit ,citrus,, CITRUS ", []string{"stone fruit", "citrus"}},
+	}
+	for _, tc := range tests {
+		if got := Parse(tc.list); !slices.Equal(got, tc.want) {
+			t.Errorf("Parse(%q) = %q, want %q", tc.list, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package tastings
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store/scorecards"
+	"cmp"
+	"slices"
+)
+
+// Someone who scored at least one pour
+type Rater struct {
+	ID       int64
+	Username string
+}
+
+// How a pour did once it's revealed
+type Ranking struct {
+	Pour db.GetPoursRow
+	// 1 for the best, with ties sharing the higher rank. Pours nobody scored come last.
+	Rank int
+	// How many scored it, and the average of their weighted totals
+	Count int
+	Mean  float64
+	// Each rater's weighted total for the pour, by user id
+	Totals map[int64]float64
+}
+
+type Results struct {
+	Rankings []Ranking
+	Raters   []Rater
+	// Kendall's coefficient of concordance between the raters who scored every pour, from 0 when
+	// they agree on nothing to 1 when they rank the pours the same. It's only Measured when at
+	// least two raters have scored at least two pours, and not all their scores were tied.
+	Agreement float64
+	Measured  bool
+}
+
+// Ranks the pours by their average weighted total, and works out how much the raters agreed
+func Rank(pours []db.GetPoursRow, cards []db.GetTastingScorecardsRow, weights scorecards.Scores) Results {
+	results := Results{}
+	byNumber := map[int64]int{}
+	for _, pour := range pours {
+		byNumber[pour.Number] = len(results.Rankings)
+		results.Rankings = append(results.Rankings, Ranking{Pour: pour, Totals: map[int64]float64{}})
+	}
+
+	scored := map[int64]int{}
+	for _, card := range cards {
+		i, ok := byNumber[card.TastingScorecard.Number]
+		if !ok {
+			continue
+		}
+		userId := card.TastingScorecard.UserID
+		if _, ok := scored[userId]; !ok {
+			results.Raters = append(results.Raters, Rater{ID: userId, Username: card.Username})
+		}
+		scored[userId]++
+		total := ScoresOf(card.TastingScorecard).Total(weights)
+		results.Rankings[i].Totals[userId] = total
+		results.Rankings[i].Count++
+		results.Rankings[i].Mean += total
+	}
+	for i := range results.Rankings {
+		if results.Rankings[i].Count > 0 {
+			results.Rankings[i].Mean /= float64(results.Rankings[i].Count)
+		}
+	}
+	slices.SortFunc(results.Raters, func(a, b Rater) int { return cmp.Compare(a.Username, b.Username) })
+
+	slices.SortStableFunc(results.Rankings, func(a, b Ranking) int {
+		if c := cmp.Compare(min(b.Count, 1), min(a.Count, 1)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Mean, a.Mean)
+	})
+	for i := range results.Rankings {
+		results.Rankings[i].Rank = i + 1
+		if i > 0 {
+			prev, this := results.Rankings[i-1], results.Rankings[i]
+			if prev.Mean == this.Mean && min(prev.Count, 1) == min(this.Count, 1) {
+				results.Rankings[i].Rank = prev.Rank
+			}
+		}
+	}
+
+	// Only those who scored everything can be compared like for like
+	complete := [][]float64{}
+	for _, rater := range results.Raters {
+		if scored[rater.ID] < len(pours) {
+			continue
+		}
+		totals := make([]float64, len(pours))
+		for i, ranking := range results.Rankings {
+			totals[i] = ranking.Totals[rater.ID]
+		}
+		complete = append(complete, totals)
+	}
+	results.Agreement, results.Measured = kendallW(complete)
+	return results
+}
+
+// Kendall's W for the raters' scores of the same items, corrected for ties. Each rater's scores
+// are turned into ranks, and W compares how spread out the items' rank sums are with how spread
+// out they'd be if every rater agreed.
+func kendallW(scores [][]float64) (float64, bool) {
+	m := len(scores)
+	if m < 2 || len(scores[0]) < 2 {
+		return 0, false
+	}
+	n := len(scores[0])
+
+	sums := make([]float64, n)
+	ties := 0.0
+	for _, rater := range scores {
+		ranks, t := fractionalRanks(rater)
+		for i, rank := range ranks {
+			sums[i] += rank
+		}
+		ties += t
+	}
+
+	mean := float64(m) * float64(n+1) / 2
+	s := 0.0
+	for _, sum := range sums {
+		s += (sum - mean) * (sum - mean)
+	}
+	fm, fn := float64(m), float64(n)
+	denominator := fm*fm*(fn*fn*fn-fn) - fm*ties
+	if denominator <= 0 {
+		return 0, false
+	}
+	return 12 * s / denominator, true
+}
+
+// Ranks the scores from 1 for the highest, with tied scores sharing the average of their ranks.
+// Also returns the sum of t³ - t over each group of t tied scores.
+func fractionalRanks(scores []float64) ([]float64, float64) {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(scores[b], scores[a]) })
+
+	ranks := make([]float64, len(scores))
+	ties := 0.0
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			end++
+		}
+		// Ranks start+1 to end, averaged
+		rank := float64(start+1+end) / 2
+		for _, i := range order[start:end] {
+			ranks[i] = rank
+		}
+		t := float64(end - start)
+		ties += t*t*t - t
+		start = end
+	}
+	return ranks, ties
+}
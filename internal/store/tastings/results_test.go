@@ -0,0 +1,87 @@
+package tastings
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store/scorecards"
+	"math"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	pours := []db.GetPoursRow{
+		{Number: 1, BeerID: 10, BeerName: "Pale"},
+		{Number: 2, BeerID: 20, BeerName: "Stout"},
+		{Number: 3, BeerID: 30, BeerName: "Lager"},
+		{Number: 4, BeerID: 40, BeerName: "Sour"},
+	}
+	// With even weights, giving every dimension the same score makes that the total
+	card := func(number int64, userId int64, username string, score float64) db.GetTastingScorecardsRow {
+		return db.GetTastingScorecardsRow{
+			TastingScorecard: db.TastingScorecard{Number: number, UserID: userId, Aroma: score, Appearance: score, Flavour: score, Mouthfeel: score, Overall: score},
+			Username:         username,
+		}
+	}
+	cards := []db.GetTastingScorecardsRow{
+		card(1, 2, "bob", 6), card(1, 1, "alice", 8),
+		card(2, 2, "bob", 9), card(2, 1, "alice", 9),
+		card(3, 2, "bob", 8), card(3, 1, "alice", 6),
+	}
+
+	results := Rank(pours, cards, scorecards.Scores{1, 1, 1, 1, 1})
+	if len(results.Raters) != 2 || results.Raters[0].Username != "alice" || results.Raters[1].Username != "bob" {
+		t.Errorf("got raters %+v, want alice then bob", results.Raters)
+	}
+
+	// The tied pours share a rank, and the one nobody scored comes last
+	want := []struct {
+		number int64
+		rank   int
+		count  int
+		mean   float64
+	}{{2, 1, 2, 9}, {1, 2, 2, 7}, {3, 2, 2, 7}, {4, 4, 0, 0}}
+	for i, w := range want {
+		got := results.Rankings[i]
+		if got.Pour.Number != w.number || got.Rank != w.rank || got.Count != w.count || math.Abs(got.Mean-w.mean) > 1e-9 {
+			t.Errorf("ranking %d: got pour %d rank %d count %d mean %v, want %+v", i, got.Pour.Number, got.Rank, got.Count, got.Mean, w)
+		}
+	}
+	if bob := results.Rankings[1].Totals[2]; math.Abs(bob-6) > 1e-9 {
+		t.Errorf("got bob's total for pour 1 %v, want 6", bob)
+	}
+
+	// Nobody scored every pour, so there's nothing to compare
+	if results.Measured {
+		t.Errorf("got agreement %v, want it not measured", results.Agreement)
+	}
+	results = Rank(pours[:3], cards, scorecards.Scores{1, 1, 1, 1, 1})
+	if !results.Measured {
+		t.Fatalf("got agreement not measured, want it measured")
+	}
+	// Ranks 3, 1, 2 and 2, 1, 3 give rank sums of 5, 2 and 5, so S = 6 and W = 12 * 6 / (4 * 24)
+	if want := 0.75; math.Abs(results.Agreement-want) > 1e-9 {
+		t.Errorf("got agreement %v, want %v", results.Agreement, want)
+	}
+}
+
+func TestKendallW(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   [][]float64
+		want     float64
+		measured bool
+	}{
+		{"the same order", [][]float64{{9, 7, 5}, {8, 6, 1}, {10, 9, 8}}, 1, true},
+		{"opposite orders", [][]float64{{1, 2, 3}, {3, 2, 1}}, 0, true},
+		// Rank sums of 2.5, 3.5 and 6 give S = 6.5, and the tie takes 2 * 6 off 4 * 24
+		{"ties", [][]float64{{9, 9, 5}, {9, 7, 5}}, 12 * 6.5 / 84, true},
+		{"all tied", [][]float64{{5, 5, 5}, {5, 5, 5}}, 0, false},
+		{"one rater", [][]float64{{1, 2, 3}}, 0, false},
+		{"one pour", [][]float64{{1}, {2}}, 0, false},
+	}
+	for _, test := range tests {
+		got, measured := kendallW(test.scores)
+		if measured != test.measured || math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, measured, test.want, test.measured)
+		}
+	}
+}
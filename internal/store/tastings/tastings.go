@@ -0,0 +1,68 @@
+package tastings
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/scorecards"
+	"context"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on blind tastings, implemented by TastingStore (backed
+// by the database) and MemoryTastingStore (for tests). The organiser pours beers as numbered
+// glasses, everyone scores them without knowing which is which, and then they're revealed. Once
+// revealed, the pours and scores can't change.
+type Store interface {
+	AddTasting(ctx context.Context, params db.AddTastingParams) (db.Tasting, error)
+	GetTasting(ctx context.Context, id int64) (db.Tasting, error)
+	GetTastings(ctx context.Context) ([]db.GetTastingsRow, error)
+	DeleteTasting(ctx context.Context, id int64) (db.Tasting, error)
+	Reveal(ctx context.Context, id int64) (db.Tasting, error)
+	AddPour(ctx context.Context, tastingId int64, beerId int64) (db.TastingPour, error)
+	RemovePour(ctx context.Context, tastingId int64, number int64) error
+	GetPours(ctx context.Context, tastingId int64) ([]db.GetPoursRow, error)
+	SaveScorecard(ctx context.Context, tastingId int64, number int64, userId int64, scores scorecards.Scores) (db.TastingScorecard, error)
+	GetScorecards(ctx context.Context, tastingId int64) ([]db.GetTastingScorecardsRow, error)
+}
+
+var _ Store = (*TastingStore)(nil)
+var _ Store = (*MemoryTastingStore)(nil)
+
+func ScoresOf(scorecard db.TastingScorecard) scorecards.Scores {
+	return scorecards.Scores{scorecard.Aroma, scorecard.Appearance, scorecard.Flavour, scorecard.Mouthfeel, scorecard.Overall}
+}
+
+func validateTasting(params db.AddTastingParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return store.ErrMissingField{Field: "name"}
+	}
+	if params.HeldAt.IsZero() {
+		return store.ErrMissingField{Field: "held-at"}
+	}
+	return nil
+}
+
+func normalizeTasting(params db.AddTastingParams) db.AddTastingParams {
+	params.Name = strings.TrimSpace(params.Name)
+	params.HeldAt = params.HeldAt.UTC().Truncate(time.Second)
+	return params
+}
+
+// Scores are out of 10, the same as scorecards
+func validateScores(scores scorecards.Scores) error {
+	for i, score := range scores {
+		if score < 0 || score > 10 {
+			return store.ErrInvalidField{Field: scorecards.Dimensions[i].Name, Reason: "must be between 0 and 10"}
+		}
+	}
+	return nil
+}
+
+// Nothing about a tasting can change once it's been revealed
+func checkNotRevealed(tasting db.Tasting) error {
+	if tasting.RevealedAt.Valid {
+		return ErrTastingRevealed{ID: tasting.ID}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package tastings
+
+import "fmt"
+
+type ErrTastingNotFound struct {
+	ID int64
+}
+
+func (e ErrTastingNotFound) Error() string {
+	return fmt.Sprintf("tasting with id %d not found", e.ID)
+}
+
+type ErrPourNotFound struct {
+	TastingID int64
+	Number    int64
+}
+
+func (e ErrPourNotFound) Error() string {
+	return fmt.Sprintf("pour %d not found in tasting %d", e.Number, e.TastingID)
+}
+
+type ErrBeerAlreadyPoured struct {
+	TastingID int64
+	BeerID    int64
+}
+
+func (e ErrBeerAlreadyPoured) Error() string {
+	return fmt.Sprintf("beer with id %d is already poured in tasting %d", e.BeerID, e.TastingID)
+}
+
+type ErrTastingRevealed struct {
+	ID int64
+}
+
+func (e ErrTastingRevealed) Error() string {
+	return fmt.Sprintf("tasting with id %d has already been revealed", e.ID)
+}
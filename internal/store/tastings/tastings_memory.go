@@ -0,0 +1,282 @@
+package tastings
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as TastingStore. The user and beer stores stand in for the foreign keys, and
+// along with the brewer store for the joins.
+type MemoryTastingStore struct {
+	mu          sync.Mutex
+	userStore   users.Store
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	lastId      int64
+	tastings    []db.Tasting
+	pours       []db.TastingPour
+	scorecards  []db.TastingScorecard
+}
+
+func NewMemoryTastingStore(userStore users.Store, beerStore beers.Store, brewerStore brewers.Store) *MemoryTastingStore {
+	return &MemoryTastingStore{
+		userStore:   userStore,
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+	}
+}
+
+// The index of the tasting with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ts *MemoryTastingStore) find(id int64) int {
+	return slices.IndexFunc(ts.tastings, func(t db.Tasting) bool { return t.ID == id })
+}
+
+// The tasting, if it's there and hasn't been revealed. Must be called with the lock held.
+func (ts *MemoryTastingStore) findUnrevealed(id int64) (int, error) {
+	i := ts.find(id)
+	if i < 0 {
+		return i, ErrTastingNotFound{ID: id}
+	}
+	return i, checkNotRevealed(ts.tastings[i])
+}
+
+func (ts *MemoryTastingStore) AddTasting(ctx context.Context, params db.AddTastingParams) (db.Tasting, error) {
+	if err := validateTasting(params); err != nil {
+		return db.Tasting{}, err
+	}
+	if params.OrganiserID.Valid {
+		if _, err := ts.userStore.GetUserById(ctx, params.OrganiserID.Int64); err != nil {
+			return db.Tasting{}, users.ErrUserNotFound{ID: params.OrganiserID.Int64}
+		}
+	}
+	params = normalizeTasting(params)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.lastId++
+	tasting := db.Tasting{
+		ID:          ts.lastId,
+		Name:        params.Name,
+		OrganiserID: params.OrganiserID,
+		HeldAt:      params.HeldAt,
+		CreatedAt:   store.Now(),
+	}
+	ts.tastings = append(ts.tastings, tasting)
+	return tasting, nil
+}
+
+func (ts *MemoryTastingStore) GetTasting(ctx context.Context, id int64) (db.Tasting, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i := ts.find(id)
+	if i < 0 {
+		return db.Tasting{}, ErrTastingNotFound{ID: id}
+	}
+	return ts.tastings[i], nil
+}
+
+func (ts *MemoryTastingStore) GetTastings(ctx context.Context) ([]db.GetTastingsRow, error) {
+	ts.mu.Lock()
+	tastings := slices.Clone(ts.tastings)
+	ts.mu.Unlock()
+
+	slices.SortFunc(tastings, func(a, b db.Tasting) int {
+		if c := b.HeldAt.Compare(a.HeldAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	rows := []db.GetTastingsRow{}
+	for _, t := range tastings {
+		row := db.GetTastingsRow{Tasting: t}
+		if t.OrganiserID.Valid {
+			if organiser, err := ts.userStore.GetUserById(ctx, t.OrganiserID.Int64); err == nil {
+				row.Username = sql.NullString{Valid: true, String: organiser.Username}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (ts *MemoryTastingStore) DeleteTasting(ctx context.Context, id int64) (db.Tasting, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i := ts.find(id)
+	if i < 0 {
+		return db.Tasting{}, ErrTastingNotFound{ID: id}
+	}
+	tasting := ts.tastings[i]
+	ts.tastings = slices.Delete(ts.tastings, i, i+1)
+	ts.pours = slices.DeleteFunc(ts.pours, func(p db.TastingPour) bool { return p.TastingID == id })
+	ts.scorecards = slices.DeleteFunc(ts.scorecards, func(s db.TastingScorecard) bool { return s.TastingID == id })
+	return tasting, nil
+}
+
+func (ts *MemoryTastingStore) Reveal(ctx context.Context, id int64) (db.Tasting, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i, err := ts.findUnrevealed(id)
+	if err != nil {
+		return db.Tasting{}, err
+	}
+	ts.tastings[i].RevealedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	return ts.tastings[i], nil
+}
+
+func (ts *MemoryTastingStore) AddPour(ctx context.Context, tastingId int64, beerId int64) (db.TastingPour, error) {
+	if _, err := ts.beerStore.GetBeer(ctx, beerId); err != nil {
+		if _, err := ts.GetTasting(ctx, tastingId); err != nil {
+			return db.TastingPour{}, err
+		}
+		return db.TastingPour{}, beers.ErrBeerNotFound{ID: beerId}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, err := ts.findUnrevealed(tastingId); err != nil {
+		return db.TastingPour{}, err
+	}
+	number := int64(0)
+	for _, p := range ts.pours {
+		if p.TastingID != tastingId {
+			continue
+		}
+		if p.BeerID == beerId {
+			return db.TastingPour{}, ErrBeerAlreadyPoured{TastingID: tastingId, BeerID: beerId}
+		}
+		number = max(number, p.Number)
+	}
+	pour := db.TastingPour{TastingID: tastingId, Number: number + 1, BeerID: beerId}
+	ts.pours = append(ts.pours, pour)
+	return pour, nil
+}
+
+func (ts *MemoryTastingStore) RemovePour(ctx context.Context, tastingId int64, number int64) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, err := ts.findUnrevealed(tastingId); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(ts.pours, func(p db.TastingPour) bool { return p.TastingID == tastingId && p.Number == number })
+	if i < 0 {
+		return ErrPourNotFound{TastingID: tastingId, Number: number}
+	}
+	ts.pours = slices.Delete(ts.pours, i, i+1)
+	ts.scorecards = slices.DeleteFunc(ts.scorecards, func(s db.TastingScorecard) bool {
+		return s.TastingID == tastingId && s.Number == number
+	})
+	return nil
+}
+
+func (ts *MemoryTastingStore) GetPours(ctx context.Context, tastingId int64) ([]db.GetPoursRow, error) {
+	ts.mu.Lock()
+	pours := []db.TastingPour{}
+	for _, p := range ts.pours {
+		if p.TastingID == tastingId {
+			pours = append(pours, p)
+		}
+	}
+	ts.mu.Unlock()
+
+	slices.SortFunc(pours, func(a, b db.TastingPour) int { return cmp.Compare(a.Number, b.Number) })
+	rows := []db.GetPoursRow{}
+	for _, p := range pours {
+		beer, err := ts.beerStore.GetBeer(ctx, p.BeerID)
+		if err != nil {
+			continue
+		}
+		row := db.GetPoursRow{TastingID: p.TastingID, Number: p.Number, BeerID: p.BeerID, BeerName: beer.Name}
+		if beer.BrewerID.Valid {
+			if brewer, err := ts.brewerStore.GetBrewer(ctx, beer.BrewerID.Int64); err == nil {
+				row.BrewerName = sql.NullString{Valid: true, String: brewer.Name}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (ts *MemoryTastingStore) SaveScorecard(ctx context.Context, tastingId int64, number int64, userId int64, scores scorecards.Scores) (db.TastingScorecard, error) {
+	if err := validateScores(scores); err != nil {
+		return db.TastingScorecard{}, err
+	}
+	if _, err := ts.userStore.GetUserById(ctx, userId); err != nil {
+		return db.TastingScorecard{}, users.ErrUserNotFound{ID: userId}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, err := ts.findUnrevealed(tastingId); err != nil {
+		return db.TastingScorecard{}, err
+	}
+	if !slices.ContainsFunc(ts.pours, func(p db.TastingPour) bool { return p.TastingID == tastingId && p.Number == number }) {
+		return db.TastingScorecard{}, ErrPourNotFound{TastingID: tastingId, Number: number}
+	}
+
+	scorecard := db.TastingScorecard{
+		TastingID:  tastingId,
+		Number:     number,
+		UserID:     userId,
+		Aroma:      scores[scorecards.Aroma],
+		Appearance: scores[scorecards.Appearance],
+		Flavour:    scores[scorecards.Flavour],
+		Mouthfeel:  scores[scorecards.Mouthfeel],
+		Overall:    scores[scorecards.Overall],
+		UpdatedAt:  store.Now(),
+	}
+	i := slices.IndexFunc(ts.scorecards, func(s db.TastingScorecard) bool {
+		return s.TastingID == tastingId && s.Number == number && s.UserID == userId
+	})
+	if i < 0 {
+		ts.scorecards = append(ts.scorecards, scorecard)
+	} else {
+		ts.scorecards[i] = scorecard
+	}
+	return scorecard, nil
+}
+
+func (ts *MemoryTastingStore) GetScorecards(ctx context.Context, tastingId int64) ([]db.GetTastingScorecardsRow, error) {
+	ts.mu.Lock()
+	matching := []db.TastingScorecard{}
+	for _, s := range ts.scorecards {
+		if s.TastingID == tastingId {
+			matching = append(matching, s)
+		}
+	}
+	ts.mu.Unlock()
+
+	rows := []db.GetTastingScorecardsRow{}
+	for _, s := range matching {
+		user, err := ts.userStore.GetUserById(ctx, s.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetTastingScorecardsRow{TastingScorecard: s, Username: user.Username})
+	}
+	slices.SortFunc(rows, func(a, b db.GetTastingScorecardsRow) int {
+		if c := cmp.Compare(a.TastingScorecard.Number, b.TastingScorecard.Number); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Username, b.Username)
+	})
+	return rows, nil
+}
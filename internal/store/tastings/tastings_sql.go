@@ -0,0 +1,210 @@
+package tastings
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/scorecards"
+	"beer_oclock/internal/store/users"
+	"context"
+	"database/sql"
+	"log"
+	"slices"
+)
+
+type TastingStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewTastingStore(queries db.Querier, logger *log.Logger) *TastingStore {
+	return &TastingStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ts *TastingStore) AddTasting(ctx context.Context, params db.AddTastingParams) (db.Tasting, error) {
+	if err := validateTasting(params); err != nil {
+		return db.Tasting{}, err
+	}
+	params = normalizeTasting(params)
+
+	tasting, err := ts.queries.AddTasting(ctx, params)
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.Tasting{}, users.ErrUserNotFound{ID: params.OrganiserID.Int64}
+		}
+		ts.logger.Printf("error adding tasting: %v", err)
+		return db.Tasting{}, err
+	}
+
+	ts.logger.Printf("tasting added: %d", tasting.ID)
+	return tasting, nil
+}
+
+func (ts *TastingStore) GetTasting(ctx context.Context, id int64) (db.Tasting, error) {
+	tasting, err := ts.queries.GetTasting(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Tasting{}, ErrTastingNotFound{ID: id}
+		}
+		ts.logger.Printf("error getting tasting: %v", err)
+		return db.Tasting{}, err
+	}
+	return tasting, nil
+}
+
+// Every tasting with who organised it, the latest first
+func (ts *TastingStore) GetTastings(ctx context.Context) ([]db.GetTastingsRow, error) {
+	tastings, err := ts.queries.GetTastings(ctx)
+	if err != nil {
+		ts.logger.Printf("error getting tastings: %v", err)
+		return nil, err
+	}
+	return tastings, nil
+}
+
+func (ts *TastingStore) DeleteTasting(ctx context.Context, id int64) (db.Tasting, error) {
+	tasting, err := ts.queries.DeleteTasting(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Tasting{}, ErrTastingNotFound{ID: id}
+		}
+		ts.logger.Printf("error deleting tasting: %v", err)
+		return db.Tasting{}, err
+	}
+
+	ts.logger.Printf("tasting deleted: %d", id)
+	return tasting, nil
+}
+
+func (ts *TastingStore) Reveal(ctx context.Context, id int64) (db.Tasting, error) {
+	tasting, err := ts.queries.RevealTasting(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Either there's no such tasting or it's already been revealed
+			tasting, err := ts.GetTasting(ctx, id)
+			if err != nil {
+				return db.Tasting{}, err
+			}
+			return db.Tasting{}, ErrTastingRevealed{ID: tasting.ID}
+		}
+		ts.logger.Printf("error revealing tasting: %v", err)
+		return db.Tasting{}, err
+	}
+
+	ts.logger.Printf("tasting revealed: %d", id)
+	return tasting, nil
+}
+
+// Pours the beer as the next numbered glass
+func (ts *TastingStore) AddPour(ctx context.Context, tastingId int64, beerId int64) (db.TastingPour, error) {
+	tasting, err := ts.GetTasting(ctx, tastingId)
+	if err != nil {
+		return db.TastingPour{}, err
+	}
+	if err := checkNotRevealed(tasting); err != nil {
+		return db.TastingPour{}, err
+	}
+
+	pour, err := ts.queries.AddPour(ctx, db.AddPourParams{TastingID: tastingId, BeerID: beerId})
+	if err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.ForeignKeyConstraint:
+			return db.TastingPour{}, beers.ErrBeerNotFound{ID: beerId}
+		case store.UniqueConstraint:
+			pours, getErr := ts.GetPours(ctx, tastingId)
+			if getErr == nil && slices.ContainsFunc(pours, func(p db.GetPoursRow) bool { return p.BeerID == beerId }) {
+				return db.TastingPour{}, ErrBeerAlreadyPoured{TastingID: tastingId, BeerID: beerId}
+			}
+		}
+		ts.logger.Printf("error adding pour: %v", err)
+		return db.TastingPour{}, err
+	}
+
+	ts.logger.Printf("pour added: %d in tasting %d", pour.Number, tastingId)
+	return pour, nil
+}
+
+// Removes the pour along with everyone's scores for it. The other pours keep their numbers.
+func (ts *TastingStore) RemovePour(ctx context.Context, tastingId int64, number int64) error {
+	tasting, err := ts.GetTasting(ctx, tastingId)
+	if err != nil {
+		return err
+	}
+	if err := checkNotRevealed(tasting); err != nil {
+		return err
+	}
+
+	removed, err := ts.queries.DeletePour(ctx, db.DeletePourParams{TastingID: tastingId, Number: number})
+	if err != nil {
+		ts.logger.Printf("error removing pour: %v", err)
+		return err
+	}
+	if removed == 0 {
+		return ErrPourNotFound{TastingID: tastingId, Number: number}
+	}
+
+	ts.logger.Printf("pour removed: %d from tasting %d", number, tastingId)
+	return nil
+}
+
+// The pours in order, with what's in them. It's up to the caller not to show the beers before
+// the reveal.
+func (ts *TastingStore) GetPours(ctx context.Context, tastingId int64) ([]db.GetPoursRow, error) {
+	pours, err := ts.queries.GetPours(ctx, tastingId)
+	if err != nil {
+		ts.logger.Printf("error getting pours: %v", err)
+		return nil, err
+	}
+	return pours, nil
+}
+
+// Saves the user's scores for the pour, replacing any they already gave it
+func (ts *TastingStore) SaveScorecard(ctx context.Context, tastingId int64, number int64, userId int64, scores scorecards.Scores) (db.TastingScorecard, error) {
+	if err := validateScores(scores); err != nil {
+		return db.TastingScorecard{}, err
+	}
+	tasting, err := ts.GetTasting(ctx, tastingId)
+	if err != nil {
+		return db.TastingScorecard{}, err
+	}
+	if err := checkNotRevealed(tasting); err != nil {
+		return db.TastingScorecard{}, err
+	}
+
+	scorecard, err := ts.queries.SaveTastingScorecard(ctx, db.SaveTastingScorecardParams{
+		TastingID:  tastingId,
+		Number:     number,
+		UserID:     userId,
+		Aroma:      scores[scorecards.Aroma],
+		Appearance: scores[scorecards.Appearance],
+		Flavour:    scores[scorecards.Flavour],
+		Mouthfeel:  scores[scorecards.Mouthfeel],
+		Overall:    scores[scorecards.Overall],
+	})
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if _, err := ts.queries.GetUserById(ctx, userId); err == sql.ErrNoRows {
+				return db.TastingScorecard{}, users.ErrUserNotFound{ID: userId}
+			}
+			return db.TastingScorecard{}, ErrPourNotFound{TastingID: tastingId, Number: number}
+		}
+		ts.logger.Printf("error saving tasting scorecard: %v", err)
+		return db.TastingScorecard{}, err
+	}
+
+	ts.logger.Printf("tasting scorecard saved: pour %d in tasting %d by user %d", number, tastingId, userId)
+	return scorecard, nil
+}
+
+// Everyone's scores, by pour and then by who gave them
+func (ts *TastingStore) GetScorecards(ctx context.Context, tastingId int64) ([]db.GetTastingScorecardsRow, error) {
+	scorecards, err := ts.queries.GetTastingScorecards(ctx, tastingId)
+	if err != nil {
+		ts.logger.Printf("error getting tasting scorecards: %v", err)
+		return nil, err
+	}
+	return scorecards, nil
+}
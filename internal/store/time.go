@@ -0,0 +1,14 @@
+package store
+
+import "time"
+
+// The current time as the database records it for CURRENT_TIMESTAMP, i.e. UTC to the second, so
+// the in-memory stores produce the same timestamps as the database
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// Dates are stored as midnight UTC, so the same day is stored the same way wherever it's from
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
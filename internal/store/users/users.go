@@ -0,0 +1,28 @@
+package users
+
+import (
+	"beer_oclock/internal/db"
+	"context"
+	"time"
+)
+
+// The operations the rest of the app needs on users, implemented by UserStore (backed by the
+// database) and MemoryUserStore (for tests)
+type Store interface {
+	AddUser(ctx context.Context, params db.AddUserParams) (db.User, error)
+	GetUser(ctx context.Context, id int64) (db.User, error)
+	GetUsers(ctx context.Context) ([]db.User, error)
+	GetUserById(ctx context.Context, id int64) (db.User, error)
+	GetUserByUsername(ctx context.Context, username string) (db.User, error)
+	DeleteUser(ctx context.Context, id int64) (db.User, error)
+	RestoreUser(ctx context.Context, id int64) (db.User, error)
+	GetDeletedUsers(ctx context.Context) ([]db.User, error)
+	PurgeUser(ctx context.Context, id int64) (db.User, error)
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
+	SetUserLastLogin(ctx context.Context, id int64) error
+	SetUserPassword(ctx context.Context, id int64, passwordHash string) error
+}
+
+var _ Store = (*UserStore)(nil)
+var _ Store = (*MemoryUserStore)(nil)
@@ -2,11 +2,16 @@ package users
 
 import "fmt"
 
+// Deleted is set when the username belongs to a user in the trash, which can be restored instead
 type ErrUserAlreadyExists struct {
 	Username string
+	Deleted  bool
 }
 
 func (e ErrUserAlreadyExists) Error() string {
+	if e.Deleted {
+		return fmt.Sprintf("user with username %s is in the trash", e.Username)
+	}
 	return fmt.Sprintf("user with username %s already exists", e.Username)
 }
 
@@ -0,0 +1,188 @@
+package users
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as UserStore
+type MemoryUserStore struct {
+	mu     sync.Mutex
+	lastId int64
+	users  []db.User
+}
+
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{}
+}
+
+// The index of the user with the given id, or -1 if there isn't one. Must be called with the lock
+// held.
+func (us *MemoryUserStore) find(id int64, deleted bool) int {
+	return slices.IndexFunc(us.users, func(u db.User) bool {
+		return u.ID == id && u.DeletedAt.Valid == deleted
+	})
+}
+
+func (us *MemoryUserStore) AddUser(ctx context.Context, params db.AddUserParams) (db.User, error) {
+	if params.Username == "" {
+		return db.User{}, store.ErrMissingField{Field: "username"}
+	}
+
+	params.Username = strings.ToLower(params.Username)
+
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	// Usernames stay taken while the user is in the trash, like the unique constraint in the database
+	if i := slices.IndexFunc(us.users, func(u db.User) bool { return u.Username == params.Username }); i >= 0 {
+		return db.User{}, ErrUserAlreadyExists{Username: params.Username, Deleted: us.users[i].DeletedAt.Valid}
+	}
+
+	us.lastId++
+	user := db.User{
+		ID:           us.lastId,
+		Username:     params.Username,
+		PasswordHash: params.PasswordHash,
+		IsAdmin:      params.IsAdmin,
+		CreatedAt:    sql.NullTime{Valid: true, Time: store.Now()},
+	}
+	us.users = append(us.users, user)
+	return user, nil
+}
+
+func (us *MemoryUserStore) GetUser(ctx context.Context, id int64) (db.User, error) {
+	return us.GetUserById(ctx, id)
+}
+
+func (us *MemoryUserStore) GetUsers(ctx context.Context) ([]db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	users := []db.User{}
+	for _, u := range us.users {
+		if !u.DeletedAt.Valid {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (us *MemoryUserStore) GetUserById(ctx context.Context, id int64) (db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	i := us.find(id, false)
+	if i < 0 {
+		return db.User{}, ErrUserNotFound{ID: id}
+	}
+	return us.users[i], nil
+}
+
+func (us *MemoryUserStore) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	for _, u := range us.users {
+		if u.Username == username && !u.DeletedAt.Valid {
+			return u, nil
+		}
+	}
+	return db.User{}, ErrUserNotFound{Username: username}
+}
+
+func (us *MemoryUserStore) DeleteUser(ctx context.Context, id int64) (db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	i := us.find(id, false)
+	if i < 0 {
+		return db.User{}, ErrUserNotFound{ID: id}
+	}
+	us.users[i].DeletedAt = sql.NullTime{Valid: true, Time: store.Now()}
+	return us.users[i], nil
+}
+
+func (us *MemoryUserStore) RestoreUser(ctx context.Context, id int64) (db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	i := us.find(id, true)
+	if i < 0 {
+		return db.User{}, ErrUserNotFound{ID: id}
+	}
+	us.users[i].DeletedAt = sql.NullTime{}
+	return us.users[i], nil
+}
+
+func (us *MemoryUserStore) GetDeletedUsers(ctx context.Context) ([]db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	users := []db.User{}
+	for _, u := range us.users {
+		if u.DeletedAt.Valid {
+			users = append(users, u)
+		}
+	}
+	slices.SortStableFunc(users, func(a, b db.User) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	})
+	return users, nil
+}
+
+func (us *MemoryUserStore) PurgeUser(ctx context.Context, id int64) (db.User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	i := us.find(id, true)
+	if i < 0 {
+		return db.User{}, ErrUserNotFound{ID: id}
+	}
+	user := us.users[i]
+	us.users = slices.Delete(us.users, i, i+1)
+	return user, nil
+}
+
+func (us *MemoryUserStore) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	before := len(us.users)
+	us.users = slices.DeleteFunc(us.users, func(u db.User) bool {
+		return u.DeletedAt.Valid && u.DeletedAt.Time.Before(deletedBefore)
+	})
+	return int64(before - len(us.users)), nil
+}
+
+func (us *MemoryUserStore) CountUsers(ctx context.Context) (int64, error) {
+	users, _ := us.GetUsers(ctx)
+	return int64(len(users)), nil
+}
+
+func (us *MemoryUserStore) SetUserLastLogin(ctx context.Context, id int64) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if i := us.find(id, false); i >= 0 {
+		us.users[i].LastLogin = sql.NullTime{Valid: true, Time: store.Now()}
+	}
+	return nil
+}
+
+func (us *MemoryUserStore) SetUserPassword(ctx context.Context, id int64, passwordHash string) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if i := us.find(id, false); i >= 0 {
+		us.users[i].PasswordHash = passwordHash
+	}
+	return nil
+}
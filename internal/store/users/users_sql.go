@@ -6,18 +6,17 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"slices"
 	"strings"
-
-	"modernc.org/sqlite"
-	sqlite3 "modernc.org/sqlite/lib"
+	"time"
 )
 
 type UserStore struct {
-	queries *db.Queries
+	queries db.Querier
 	logger  *log.Logger
 }
 
-func NewUserStore(queries *db.Queries, logger *log.Logger) *UserStore {
+func NewUserStore(queries db.Querier, logger *log.Logger) *UserStore {
 	return &UserStore{
 		logger:  logger,
 		queries: queries,
@@ -37,11 +36,8 @@ func (us *UserStore) AddUser(ctx context.Context, params db.AddUserParams) (db.U
 	// Add the user to the database
 	user, err := us.queries.AddUser(ctx, params)
 	if err != nil {
-		if sqlErr, ok := err.(*sqlite.Error); ok {
-			if sqlErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
-				return zero, ErrUserAlreadyExists{Username: params.Username}
-			}
-			us.logger.Printf("error adding user: %v, %v", err, sqlErr)
+		if store.ViolatedConstraint(err) == store.UniqueConstraint {
+			return zero, ErrUserAlreadyExists{Username: params.Username, Deleted: us.inTrash(ctx, params.Username)}
 		}
 		us.logger.Printf("error adding user: %v", err)
 		return zero, err
@@ -107,10 +103,8 @@ func (us *UserStore) DeleteUser(ctx context.Context, id int64) (db.User, error)
 
 	user, err := us.queries.DeleteUser(ctx, id)
 	if err != nil {
-		if sqlErr, ok := err.(*sqlite.Error); ok {
-			if sqlErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
-				return zero, ErrUserNotFound{ID: id}
-			}
+		if err == sql.ErrNoRows {
+			return zero, ErrUserNotFound{ID: id}
 		}
 		us.logger.Printf("error deleting user: %v", err)
 		return zero, err
@@ -120,6 +114,68 @@ func (us *UserStore) DeleteUser(ctx context.Context, id int64) (db.User, error)
 	return user, nil
 }
 
+func (us *UserStore) RestoreUser(ctx context.Context, id int64) (db.User, error) {
+	zero := db.User{}
+
+	user, err := us.queries.RestoreUser(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrUserNotFound{ID: id}
+		}
+		us.logger.Printf("error restoring user: %v", err)
+		return zero, err
+	}
+
+	us.logger.Printf("user restored: %v", user)
+	return user, nil
+}
+
+func (us *UserStore) GetDeletedUsers(ctx context.Context) ([]db.User, error) {
+	users, err := us.queries.GetDeletedUsers(ctx)
+	if err != nil {
+		us.logger.Printf("error getting deleted users: %v", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// Reports whether the username belongs to a user in the trash
+func (us *UserStore) inTrash(ctx context.Context, username string) bool {
+	users, err := us.GetDeletedUsers(ctx)
+	return err == nil && slices.ContainsFunc(users, func(u db.User) bool { return u.Username == username })
+}
+
+// Permanently removes a user which has already been moved to the trash
+func (us *UserStore) PurgeUser(ctx context.Context, id int64) (db.User, error) {
+	zero := db.User{}
+
+	user, err := us.queries.PurgeUser(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return zero, ErrUserNotFound{ID: id}
+		}
+		us.logger.Printf("error purging user: %v", err)
+		return zero, err
+	}
+
+	us.logger.Printf("user purged: %v", user)
+	return user, nil
+}
+
+// Permanently removes every user that was moved to the trash before the given time, returning
+// how many were removed
+func (us *UserStore) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	count, err := us.queries.PurgeDeletedUsers(ctx, sql.NullTime{Valid: true, Time: deletedBefore.UTC()})
+	if err != nil {
+		us.logger.Printf("error purging deleted users: %v", err)
+		return 0, err
+	}
+	if count > 0 {
+		us.logger.Printf("purged %d deleted users", count)
+	}
+	return count, nil
+}
+
 func (us *UserStore) CountUsers(ctx context.Context) (int64, error) {
 	count, err := us.queries.CountUsers(ctx)
 	if err != nil {
@@ -137,3 +193,12 @@ func (us *UserStore) SetUserLastLogin(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+func (us *UserStore) SetUserPassword(ctx context.Context, id int64, passwordHash string) error {
+	err := us.queries.SetUserPassword(ctx, db.SetUserPasswordParams{PasswordHash: passwordHash, ID: id})
+	if err != nil {
+		us.logger.Printf("error setting user password: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package venues
+
+import (
+	"beer_oclock/internal/db"
+	"cmp"
+	"math"
+	"slices"
+)
+
+// The mean radius of the Earth
+const EarthRadiusKm = 6371.0
+
+// How far a degree of latitude is, anywhere
+const kmPerDegree = EarthRadiusKm * math.Pi / 180
+
+// A venue and how far it is from where the user is
+type NearbyVenue struct {
+	Venue      db.Venue
+	DistanceKm float64
+}
+
+// The great circle distance between two points, by the haversine formula
+func Distance(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	phi1, phi2 := latitude1*math.Pi/180, latitude2*math.Pi/180
+	dPhi := (latitude2 - latitude1) * math.Pi / 180
+	dLambda := (longitude2 - longitude1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// A box of latitudes and longitudes holding everywhere within the radius of the point, so the
+// database only has to look at the venues which could be near it. Near the poles, and across the
+// 180th meridian, the box goes all the way round.
+func Box(latitude float64, longitude float64, radiusKm float64) db.GetVenuesInBoxParams {
+	dLatitude := radiusKm / kmPerDegree
+	box := db.GetVenuesInBoxParams{
+		MinLatitude:  max(latitude-dLatitude, -90),
+		MaxLatitude:  min(latitude+dLatitude, 90),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+	if box.MinLatitude == -90 || box.MaxLatitude == 90 {
+		return box
+	}
+
+	// The degrees of longitude get shorter towards the poles, so the box is widest at the edge
+	// nearest one
+	widest := max(math.Abs(box.MinLatitude), math.Abs(box.MaxLatitude))
+	dLongitude := radiusKm / (kmPerDegree * math.Cos(widest*math.Pi/180))
+	if longitude-dLongitude >= -180 && longitude+dLongitude <= 180 {
+		box.MinLongitude, box.MaxLongitude = longitude-dLongitude, longitude+dLongitude
+	}
+	return box
+}
+
+// The venues within the radius of the point, nearest first and then by name. Venues without
+// coordinates are never near anywhere.
+func Nearest(venues []db.Venue, latitude float64, longitude float64, radiusKm float64, maxResults int64) []NearbyVenue {
+	nearby := []NearbyVenue{}
+	for _, venue := range venues {
+		if !venue.Latitude.Valid || !venue.Longitude.Valid {
+			continue
+		}
+		distance := Distance(latitude, longitude, venue.Latitude.Float64, venue.Longitude.Float64)
+		if distance <= radiusKm {
+			nearby = append(nearby, NearbyVenue{Venue: venue, DistanceKm: distance})
+		}
+	}
+	slices.SortFunc(nearby, func(a, b NearbyVenue) int {
+		if c := cmp.Compare(a.DistanceKm, b.DistanceKm); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Venue.Name, b.Venue.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Venue.ID, b.Venue.ID)
+	})
+	return nearby[:min(int64(len(nearby)), max(maxResults, 0))]
+}
@@ -0,0 +1,79 @@
+package venues
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same place", -33.8688, 151.2093, -33.8688, 151.2093, 0},
+		{"Sydney to Melbourne", -33.8688, 151.2093, -37.8136, 144.9631, 713.4},
+		{"a degree of latitude", 0, 0, 1, 0, 111.2},
+		// Across the 180th meridian is the short way round
+		{"across the date line", 0, 179.5, 0, -179.5, 111.2},
+		{"pole to pole", 90, 0, -90, 0, 20015.1},
+	} {
+		got := Distance(tc.lat1, tc.lon1, tc.lat2, tc.lon2)
+		if math.Abs(got-tc.want) > 0.1 {
+			t.Errorf("%s: got %.1f km, want %.1f km", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBox(t *testing.T) {
+	box := Box(-33.8688, 151.2093, 10)
+	if box.MinLatitude >= -33.8688 || box.MaxLatitude <= -33.8688 || box.MinLongitude >= 151.2093 || box.MaxLongitude <= 151.2093 {
+		t.Fatalf("got box %+v not around the point", box)
+	}
+	// Each edge is the radius away or further
+	for _, corner := range [][2]float64{{box.MinLatitude, 151.2093}, {box.MaxLatitude, 151.2093}, {-33.8688, box.MinLongitude}, {-33.8688, box.MaxLongitude}} {
+		if d := Distance(-33.8688, 151.2093, corner[0], corner[1]); d < 10-1e-6 {
+			t.Errorf("got an edge of the box at %v only %.2f km away", corner, d)
+		}
+	}
+
+	if box := Box(0, 179.99, 10); box.MinLongitude != -180 || box.MaxLongitude != 180 {
+		t.Errorf("got box %+v across the date line, want all the way round", box)
+	}
+	if box := Box(89.99, 0, 10); box.MaxLatitude != 90 || box.MinLongitude != -180 || box.MaxLongitude != 180 {
+		t.Errorf("got box %+v near the pole, want all the way round", box)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	at := func(id int64, name string, lat float64, lon float64) db.Venue {
+		return db.Venue{ID: id, Name: name, Latitude: sql.NullFloat64{Valid: true, Float64: lat}, Longitude: sql.NullFloat64{Valid: true, Float64: lon}}
+	}
+	venues := []db.Venue{
+		at(1, "Far", -34.5, 151.2),
+		at(2, "Next door", -33.8690, 151.2093),
+		{ID: 3, Name: "Nowhere"},
+		at(4, "Down the road", -33.88, 151.21),
+		at(5, "Also next door", -33.8690, 151.2093),
+	}
+
+	nearby := Nearest(venues, -33.8688, 151.2093, 5, 10)
+	want := []int64{5, 2, 4}
+	if len(nearby) != len(want) {
+		t.Fatalf("got %+v, want venues %v", nearby, want)
+	}
+	for i, id := range want {
+		if nearby[i].Venue.ID != id {
+			t.Errorf("venue %d is %d, want %d", i, nearby[i].Venue.ID, id)
+		}
+	}
+	if nearby[2].DistanceKm < 1 || nearby[2].DistanceKm > 2 {
+		t.Errorf("got %.2f km down the road", nearby[2].DistanceKm)
+	}
+
+	if got := Nearest(venues, -33.8688, 151.2093, 5, 1); len(got) != 1 || got[0].Venue.ID != 5 {
+		t.Errorf("got %+v with one result", got)
+	}
+}
@@ -0,0 +1,95 @@
+package venues
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on venues and the check-ins at them, implemented by
+// VenueStore (backed by the database) and MemoryVenueStore (for tests)
+type Store interface {
+	AddVenue(ctx context.Context, params db.AddVenueParams) (db.Venue, error)
+	GetVenue(ctx context.Context, id int64) (db.Venue, error)
+	GetVenues(ctx context.Context) ([]db.Venue, error)
+	GetNearbyVenues(ctx context.Context, latitude float64, longitude float64, radiusKm float64, maxResults int64) ([]NearbyVenue, error)
+	DeleteVenue(ctx context.Context, id int64) (db.Venue, error)
+	AddCheckIn(ctx context.Context, params db.AddCheckInParams) (db.CheckIn, error)
+	GetCheckIn(ctx context.Context, id int64) (db.CheckIn, error)
+	DeleteCheckIn(ctx context.Context, id int64) (db.CheckIn, error)
+	GetUserCheckIns(ctx context.Context, userId int64, maxResults int64) ([]db.GetUserCheckInsRow, error)
+	GetVenueCheckIns(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueCheckInsRow, error)
+	GetVenueTopBeers(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueTopBeersRow, error)
+}
+
+var _ Store = (*VenueStore)(nil)
+var _ Store = (*MemoryVenueStore)(nil)
+
+// The types of venue
+const (
+	Bar        = "bar"
+	BottleShop = "bottle-shop"
+	Home       = "home"
+)
+
+// Every type of venue, in the order they're listed in
+var Types = []string{Bar, BottleShop, Home}
+
+// How the type of venue is shown
+func TypeName(venueType string) string {
+	switch venueType {
+	case Bar:
+		return "Bar"
+	case BottleShop:
+		return "Bottle shop"
+	case Home:
+		return "Home"
+	}
+	return venueType
+}
+
+func validateVenue(params db.AddVenueParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return store.ErrMissingField{Field: "name"}
+	}
+	if params.Type == "" {
+		return store.ErrMissingField{Field: "type"}
+	}
+	if !slices.Contains(Types, params.Type) {
+		return store.ErrInvalidField{Field: "type", Reason: "must be a bar, bottle shop or home"}
+	}
+	if params.Latitude.Valid != params.Longitude.Valid {
+		if params.Latitude.Valid {
+			return store.ErrMissingField{Field: "longitude"}
+		}
+		return store.ErrMissingField{Field: "latitude"}
+	}
+	if params.Latitude.Valid && (params.Latitude.Float64 < -90 || params.Latitude.Float64 > 90) {
+		return store.ErrInvalidField{Field: "latitude", Reason: "must be between -90 and 90"}
+	}
+	if params.Longitude.Valid && (params.Longitude.Float64 < -180 || params.Longitude.Float64 > 180) {
+		return store.ErrInvalidField{Field: "longitude", Reason: "must be between -180 and 180"}
+	}
+	return nil
+}
+
+func normalizeVenue(params db.AddVenueParams) db.AddVenueParams {
+	params.Name = strings.TrimSpace(params.Name)
+	params.Address = strings.TrimSpace(params.Address)
+	return params
+}
+
+func validateCheckIn(params db.AddCheckInParams) error {
+	if params.CheckedInAt.IsZero() {
+		return store.ErrMissingField{Field: "checked-in-at"}
+	}
+	return nil
+}
+
+func normalizeCheckIn(params db.AddCheckInParams) db.AddCheckInParams {
+	params.CheckedInAt = params.CheckedInAt.UTC().Truncate(time.Second)
+	return params
+}
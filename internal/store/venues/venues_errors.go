@@ -0,0 +1,19 @@
+package venues
+
+import "fmt"
+
+type ErrVenueNotFound struct {
+	ID int64
+}
+
+func (e ErrVenueNotFound) Error() string {
+	return fmt.Sprintf("venue with id %d not found", e.ID)
+}
+
+type ErrCheckInNotFound struct {
+	ID int64
+}
+
+func (e ErrCheckInNotFound) Error() string {
+	return fmt.Sprintf("check-in with id %d not found", e.ID)
+}
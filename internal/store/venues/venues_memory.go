@@ -0,0 +1,272 @@
+package venues
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/users"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as VenueStore. The beer store stands in for the foreign key, and it and the user
+// store for the joins.
+type MemoryVenueStore struct {
+	mu            sync.Mutex
+	userStore     users.Store
+	beerStore     beers.Store
+	lastId        int64
+	lastCheckInId int64
+	venues        []db.Venue
+	checkIns      []db.CheckIn
+}
+
+func NewMemoryVenueStore(userStore users.Store, beerStore beers.Store) *MemoryVenueStore {
+	return &MemoryVenueStore{
+		userStore: userStore,
+		beerStore: beerStore,
+	}
+}
+
+// The index of the venue with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (vs *MemoryVenueStore) find(id int64) int {
+	return slices.IndexFunc(vs.venues, func(v db.Venue) bool { return v.ID == id })
+}
+
+// The index of the check-in with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (vs *MemoryVenueStore) findCheckIn(id int64) int {
+	return slices.IndexFunc(vs.checkIns, func(c db.CheckIn) bool { return c.ID == id })
+}
+
+// The names of every beer, including those in the trash, for joining to
+func (vs *MemoryVenueStore) beerNames(ctx context.Context) (map[int64]string, error) {
+	names := map[int64]string{}
+	for _, get := range []func(context.Context) ([]db.Beer, error){vs.beerStore.GetBeers, vs.beerStore.GetDeletedBeers} {
+		all, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, beer := range all {
+			names[beer.ID] = beer.Name
+		}
+	}
+	return names, nil
+}
+
+// The check-ins matching, latest first. Must be called with the lock held.
+func (vs *MemoryVenueStore) latestCheckIns(match func(db.CheckIn) bool) []db.CheckIn {
+	checkIns := []db.CheckIn{}
+	for _, c := range vs.checkIns {
+		if match(c) {
+			checkIns = append(checkIns, c)
+		}
+	}
+	slices.SortFunc(checkIns, func(a, b db.CheckIn) int {
+		if c := b.CheckedInAt.Compare(a.CheckedInAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return checkIns
+}
+
+func (vs *MemoryVenueStore) AddVenue(ctx context.Context, params db.AddVenueParams) (db.Venue, error) {
+	if err := validateVenue(params); err != nil {
+		return db.Venue{}, err
+	}
+	params = normalizeVenue(params)
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	vs.lastId++
+	venue := db.Venue{
+		ID:        vs.lastId,
+		Name:      params.Name,
+		Address:   params.Address,
+		Type:      params.Type,
+		Latitude:  params.Latitude,
+		Longitude: params.Longitude,
+		CreatedAt: store.Now(),
+	}
+	vs.venues = append(vs.venues, venue)
+	return venue, nil
+}
+
+func (vs *MemoryVenueStore) GetVenue(ctx context.Context, id int64) (db.Venue, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	i := vs.find(id)
+	if i < 0 {
+		return db.Venue{}, ErrVenueNotFound{ID: id}
+	}
+	return vs.venues[i], nil
+}
+
+func (vs *MemoryVenueStore) GetVenues(ctx context.Context) ([]db.Venue, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	venues := slices.Clone(vs.venues)
+	slices.SortFunc(venues, func(a, b db.Venue) int {
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return venues, nil
+}
+
+func (vs *MemoryVenueStore) GetNearbyVenues(ctx context.Context, latitude float64, longitude float64, radiusKm float64, maxResults int64) ([]NearbyVenue, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	return Nearest(vs.venues, latitude, longitude, radiusKm, maxResults), nil
+}
+
+func (vs *MemoryVenueStore) DeleteVenue(ctx context.Context, id int64) (db.Venue, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	i := vs.find(id)
+	if i < 0 {
+		return db.Venue{}, ErrVenueNotFound{ID: id}
+	}
+	venue := vs.venues[i]
+	vs.venues = slices.Delete(vs.venues, i, i+1)
+	vs.checkIns = slices.DeleteFunc(vs.checkIns, func(c db.CheckIn) bool { return c.VenueID == id })
+	return venue, nil
+}
+
+func (vs *MemoryVenueStore) AddCheckIn(ctx context.Context, params db.AddCheckInParams) (db.CheckIn, error) {
+	if err := validateCheckIn(params); err != nil {
+		return db.CheckIn{}, err
+	}
+	if _, err := vs.GetVenue(ctx, params.VenueID); err != nil {
+		return db.CheckIn{}, err
+	}
+	if _, err := vs.beerStore.GetBeer(ctx, params.BeerID); err != nil {
+		return db.CheckIn{}, beers.ErrBeerNotFound{ID: params.BeerID}
+	}
+	params = normalizeCheckIn(params)
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	vs.lastCheckInId++
+	checkIn := db.CheckIn{
+		ID:          vs.lastCheckInId,
+		UserID:      params.UserID,
+		BeerID:      params.BeerID,
+		VenueID:     params.VenueID,
+		CheckedInAt: params.CheckedInAt,
+		CreatedAt:   store.Now(),
+	}
+	vs.checkIns = append(vs.checkIns, checkIn)
+	return checkIn, nil
+}
+
+func (vs *MemoryVenueStore) GetCheckIn(ctx context.Context, id int64) (db.CheckIn, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	i := vs.findCheckIn(id)
+	if i < 0 {
+		return db.CheckIn{}, ErrCheckInNotFound{ID: id}
+	}
+	return vs.checkIns[i], nil
+}
+
+func (vs *MemoryVenueStore) DeleteCheckIn(ctx context.Context, id int64) (db.CheckIn, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	i := vs.findCheckIn(id)
+	if i < 0 {
+		return db.CheckIn{}, ErrCheckInNotFound{ID: id}
+	}
+	checkIn := vs.checkIns[i]
+	vs.checkIns = slices.Delete(vs.checkIns, i, i+1)
+	return checkIn, nil
+}
+
+func (vs *MemoryVenueStore) GetUserCheckIns(ctx context.Context, userId int64, maxResults int64) ([]db.GetUserCheckInsRow, error) {
+	beerNames, err := vs.beerNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	rows := []db.GetUserCheckInsRow{}
+	for _, c := range vs.latestCheckIns(func(c db.CheckIn) bool { return c.UserID == userId }) {
+		beerName, ok := beerNames[c.BeerID]
+		if !ok {
+			continue
+		}
+		rows = append(rows, db.GetUserCheckInsRow{CheckIn: c, BeerName: beerName, VenueName: vs.venues[vs.find(c.VenueID)].Name})
+	}
+	return rows[:min(int64(len(rows)), max(maxResults, 0))], nil
+}
+
+func (vs *MemoryVenueStore) GetVenueCheckIns(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueCheckInsRow, error) {
+	beerNames, err := vs.beerNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.mu.Lock()
+	checkIns := vs.latestCheckIns(func(c db.CheckIn) bool { return c.VenueID == venueId })
+	vs.mu.Unlock()
+
+	rows := []db.GetVenueCheckInsRow{}
+	for _, c := range checkIns {
+		beerName, ok := beerNames[c.BeerID]
+		if !ok {
+			continue
+		}
+		// Like the join, which leaves out deleted users
+		user, err := vs.userStore.GetUserById(ctx, c.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, db.GetVenueCheckInsRow{CheckIn: c, BeerName: beerName, Username: user.Username})
+	}
+	return rows[:min(int64(len(rows)), max(maxResults, 0))], nil
+}
+
+func (vs *MemoryVenueStore) GetVenueTopBeers(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueTopBeersRow, error) {
+	beerNames, err := vs.beerNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	counts := map[int64]int64{}
+	for _, c := range vs.checkIns {
+		if _, ok := beerNames[c.BeerID]; ok && c.VenueID == venueId {
+			counts[c.BeerID]++
+		}
+	}
+
+	rows := []db.GetVenueTopBeersRow{}
+	for beerId, checkIns := range counts {
+		rows = append(rows, db.GetVenueTopBeersRow{BeerID: beerId, Name: beerNames[beerId], CheckIns: checkIns})
+	}
+	slices.SortFunc(rows, func(a, b db.GetVenueTopBeersRow) int {
+		if c := cmp.Compare(b.CheckIns, a.CheckIns); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return rows[:min(int64(len(rows)), max(maxResults, 0))], nil
+}
@@ -0,0 +1,163 @@
+package venues
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type VenueStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewVenueStore(queries db.Querier, logger *log.Logger) *VenueStore {
+	return &VenueStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (vs *VenueStore) AddVenue(ctx context.Context, params db.AddVenueParams) (db.Venue, error) {
+	if err := validateVenue(params); err != nil {
+		return db.Venue{}, err
+	}
+
+	venue, err := vs.queries.AddVenue(ctx, normalizeVenue(params))
+	if err != nil {
+		vs.logger.Printf("error adding venue: %v", err)
+		return db.Venue{}, err
+	}
+
+	vs.logger.Printf("venue added: %d %s", venue.ID, venue.Name)
+	return venue, nil
+}
+
+func (vs *VenueStore) GetVenue(ctx context.Context, id int64) (db.Venue, error) {
+	venue, err := vs.queries.GetVenue(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Venue{}, ErrVenueNotFound{ID: id}
+		}
+		vs.logger.Printf("error getting venue: %v", err)
+		return db.Venue{}, err
+	}
+	return venue, nil
+}
+
+// Every venue, by name
+func (vs *VenueStore) GetVenues(ctx context.Context) ([]db.Venue, error) {
+	venues, err := vs.queries.GetVenues(ctx)
+	if err != nil {
+		vs.logger.Printf("error getting venues: %v", err)
+		return nil, err
+	}
+	return venues, nil
+}
+
+// The venues within the radius of the point, nearest first. The database narrows them down to
+// those in a box around the point, and the distances are worked out from there.
+func (vs *VenueStore) GetNearbyVenues(ctx context.Context, latitude float64, longitude float64, radiusKm float64, maxResults int64) ([]NearbyVenue, error) {
+	venues, err := vs.queries.GetVenuesInBox(ctx, Box(latitude, longitude, radiusKm))
+	if err != nil {
+		vs.logger.Printf("error getting nearby venues: %v", err)
+		return nil, err
+	}
+	return Nearest(venues, latitude, longitude, radiusKm, maxResults), nil
+}
+
+// Deletes the venue along with the check-ins at it
+func (vs *VenueStore) DeleteVenue(ctx context.Context, id int64) (db.Venue, error) {
+	venue, err := vs.queries.DeleteVenue(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Venue{}, ErrVenueNotFound{ID: id}
+		}
+		vs.logger.Printf("error deleting venue: %v", err)
+		return db.Venue{}, err
+	}
+
+	vs.logger.Printf("venue deleted: %d %s", venue.ID, venue.Name)
+	return venue, nil
+}
+
+func (vs *VenueStore) AddCheckIn(ctx context.Context, params db.AddCheckInParams) (db.CheckIn, error) {
+	if err := validateCheckIn(params); err != nil {
+		return db.CheckIn{}, err
+	}
+
+	checkIn, err := vs.queries.AddCheckIn(ctx, normalizeCheckIn(params))
+	if err != nil {
+		// The user is whoever's logged in, so it's the venue or the beer that's missing
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			if _, err := vs.GetVenue(ctx, params.VenueID); err != nil {
+				return db.CheckIn{}, err
+			}
+			return db.CheckIn{}, beers.ErrBeerNotFound{ID: params.BeerID}
+		}
+		vs.logger.Printf("error adding check-in: %v", err)
+		return db.CheckIn{}, err
+	}
+
+	vs.logger.Printf("check-in added: %v", checkIn)
+	return checkIn, nil
+}
+
+func (vs *VenueStore) GetCheckIn(ctx context.Context, id int64) (db.CheckIn, error) {
+	checkIn, err := vs.queries.GetCheckIn(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.CheckIn{}, ErrCheckInNotFound{ID: id}
+		}
+		vs.logger.Printf("error getting check-in: %v", err)
+		return db.CheckIn{}, err
+	}
+	return checkIn, nil
+}
+
+func (vs *VenueStore) DeleteCheckIn(ctx context.Context, id int64) (db.CheckIn, error) {
+	checkIn, err := vs.queries.DeleteCheckIn(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.CheckIn{}, ErrCheckInNotFound{ID: id}
+		}
+		vs.logger.Printf("error deleting check-in: %v", err)
+		return db.CheckIn{}, err
+	}
+
+	vs.logger.Printf("check-in deleted: %v", checkIn)
+	return checkIn, nil
+}
+
+// Where the user's had what, latest first
+func (vs *VenueStore) GetUserCheckIns(ctx context.Context, userId int64, maxResults int64) ([]db.GetUserCheckInsRow, error) {
+	checkIns, err := vs.queries.GetUserCheckIns(ctx, db.GetUserCheckInsParams{UserID: userId, MaxResults: maxResults})
+	if err != nil {
+		vs.logger.Printf("error getting user check-ins: %v", err)
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// Who's had what at the venue, latest first
+func (vs *VenueStore) GetVenueCheckIns(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueCheckInsRow, error) {
+	checkIns, err := vs.queries.GetVenueCheckIns(ctx, db.GetVenueCheckInsParams{VenueID: venueId, MaxResults: maxResults})
+	if err != nil {
+		vs.logger.Printf("error getting venue check-ins: %v", err)
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// The beers had most at the venue, most first
+func (vs *VenueStore) GetVenueTopBeers(ctx context.Context, venueId int64, maxResults int64) ([]db.GetVenueTopBeersRow, error) {
+	topBeers, err := vs.queries.GetVenueTopBeers(ctx, db.GetVenueTopBeersParams{VenueID: venueId, MaxResults: maxResults})
+	if err != nil {
+		vs.logger.Printf("error getting venue top beers: %v", err)
+		return nil, err
+	}
+	return topBeers, nil
+}
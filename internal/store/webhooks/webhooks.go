@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/outbox"
+	"context"
+	"database/sql"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The operations the rest of the app needs on webhooks and the deliveries to them, implemented by
+// WebhookStore (backed by the database) and MemoryWebhookStore (for tests). Like the emails in the
+// outbox, a delivery is due once its next_attempt_at has passed, and is no longer due once it's
+// been delivered or given up on.
+type Store interface {
+	AddWebhook(ctx context.Context, params db.AddWebhookParams) (db.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (db.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]db.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) (db.Webhook, error)
+	QueueDelivery(ctx context.Context, params db.QueueDeliveryParams) (db.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, maxResults int64) ([]db.GetDueDeliveriesRow, error)
+	GetDeliveries(ctx context.Context, maxResults int64) ([]db.GetDeliveriesRow, error)
+	SetDeliveryDelivered(ctx context.Context, params db.SetDeliveryDeliveredParams) error
+	SetDeliveryFailed(ctx context.Context, params db.SetDeliveryFailedParams) error
+}
+
+var _ Store = (*WebhookStore)(nil)
+var _ Store = (*MemoryWebhookStore)(nil)
+
+// The types of event webhooks can be sent
+const (
+	BeerCreated   = "beer.created"
+	BeerUpdated   = "beer.updated"
+	BeerDeleted   = "beer.deleted"
+	BrewerCreated = "brewer.created"
+	BrewerDeleted = "brewer.deleted"
+	UserDeleted   = "user.deleted"
+	DrinkLogged   = "drink.logged"
+)
+
+// Every type of event, in the order they're listed in
+var Events = []string{BeerCreated, BeerUpdated, BeerDeleted, BrewerCreated, BrewerDeleted, UserDeleted, DrinkLogged}
+
+// How many times delivering an event is tried before it's given up on. The wait between tries is
+// the same as for emails, see outbox.Backoff.
+const MaxAttempts = 8
+
+func validateWebhook(params db.AddWebhookParams) error {
+	if strings.TrimSpace(params.Url) == "" {
+		return store.ErrMissingField{Field: "url"}
+	}
+	if u, err := url.Parse(strings.TrimSpace(params.Url)); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return store.ErrInvalidField{Field: "url", Reason: "must be an http or https URL, e.g. https://example.com/hook"}
+	}
+	if params.Secret == "" {
+		return store.ErrMissingField{Field: "secret"}
+	}
+	if len(EventList(params.Events)) == 0 {
+		return store.ErrMissingField{Field: "events"}
+	}
+	for _, event := range EventList(params.Events) {
+		if !slices.Contains(Events, event) {
+			return store.ErrInvalidField{Field: "events", Reason: event + " isn't a type of event"}
+		}
+	}
+	return nil
+}
+
+// Events are kept in the order of Events, once each
+func normalizeWebhook(params db.AddWebhookParams) db.AddWebhookParams {
+	params.Url = strings.TrimSpace(params.Url)
+	events := EventList(params.Events)
+	params.Events = strings.Join(slices.DeleteFunc(slices.Clone(Events), func(e string) bool {
+		return !slices.Contains(events, e)
+	}), ",")
+	return params
+}
+
+// The types of event in a webhook's comma separated list
+func EventList(events string) []string {
+	list := []string{}
+	for _, event := range strings.Split(events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			list = append(list, event)
+		}
+	}
+	return list
+}
+
+// Whether the webhook wants to be sent the type of event
+func Wants(webhook db.Webhook, event string) bool {
+	return slices.Contains(EventList(webhook.Events), event)
+}
+
+// Deliveries are tried as soon as possible unless they're queued for later
+func normalizeDelivery(params db.QueueDeliveryParams) db.QueueDeliveryParams {
+	if !params.NextAttemptAt.Valid {
+		params.NextAttemptAt = sql.NullTime{Valid: true, Time: store.Now()}
+	}
+	params.NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+	return params
+}
+
+// When to try delivering again after another failed attempt at the time, or NULL to give up on
+// it if that was the last one
+func Retry(delivery db.WebhookDelivery, now time.Time) sql.NullTime {
+	attempts := delivery.Attempts + 1
+	if attempts >= MaxAttempts {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Valid: true, Time: now.Add(outbox.Backoff(attempts))}
+}
@@ -0,0 +1,11 @@
+package webhooks
+
+import "fmt"
+
+type ErrWebhookNotFound struct {
+	ID int64
+}
+
+func (e ErrWebhookNotFound) Error() string {
+	return fmt.Sprintf("webhook with id %d not found", e.ID)
+}
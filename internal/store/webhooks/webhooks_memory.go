@@ -0,0 +1,174 @@
+package webhooks
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An in-memory implementation of Store for tests, which returns the same webhooks and deliveries
+// in the same order as WebhookStore
+type MemoryWebhookStore struct {
+	mu             sync.Mutex
+	lastId         int64
+	lastDeliveryId int64
+	webhooks       []db.Webhook
+	deliveries     []db.WebhookDelivery
+}
+
+func NewMemoryWebhookStore() *MemoryWebhookStore {
+	return &MemoryWebhookStore{}
+}
+
+func (ws *MemoryWebhookStore) AddWebhook(ctx context.Context, params db.AddWebhookParams) (db.Webhook, error) {
+	if err := validateWebhook(params); err != nil {
+		return db.Webhook{}, err
+	}
+	params = normalizeWebhook(params)
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.lastId++
+	webhook := db.Webhook{
+		ID:        ws.lastId,
+		Url:       params.Url,
+		Secret:    params.Secret,
+		Events:    params.Events,
+		CreatedAt: store.Now(),
+	}
+	ws.webhooks = append(ws.webhooks, webhook)
+	return webhook, nil
+}
+
+// The index of the webhook with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ws *MemoryWebhookStore) find(id int64) int {
+	return slices.IndexFunc(ws.webhooks, func(w db.Webhook) bool { return w.ID == id })
+}
+
+// The index of the delivery with the given id, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ws *MemoryWebhookStore) findDelivery(id int64) int {
+	return slices.IndexFunc(ws.deliveries, func(d db.WebhookDelivery) bool { return d.ID == id })
+}
+
+func (ws *MemoryWebhookStore) GetWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if i := ws.find(id); i >= 0 {
+		return ws.webhooks[i], nil
+	}
+	return db.Webhook{}, ErrWebhookNotFound{ID: id}
+}
+
+func (ws *MemoryWebhookStore) GetWebhooks(ctx context.Context) ([]db.Webhook, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return slices.Clone(ws.webhooks), nil
+}
+
+func (ws *MemoryWebhookStore) DeleteWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	i := ws.find(id)
+	if i < 0 {
+		return db.Webhook{}, ErrWebhookNotFound{ID: id}
+	}
+	webhook := ws.webhooks[i]
+	ws.webhooks = slices.Delete(ws.webhooks, i, i+1)
+	ws.deliveries = slices.DeleteFunc(ws.deliveries, func(d db.WebhookDelivery) bool { return d.WebhookID == id })
+	return webhook, nil
+}
+
+func (ws *MemoryWebhookStore) QueueDelivery(ctx context.Context, params db.QueueDeliveryParams) (db.WebhookDelivery, error) {
+	params = normalizeDelivery(params)
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.find(params.WebhookID) < 0 {
+		return db.WebhookDelivery{}, ErrWebhookNotFound{ID: params.WebhookID}
+	}
+
+	ws.lastDeliveryId++
+	delivery := db.WebhookDelivery{
+		ID:            ws.lastDeliveryId,
+		WebhookID:     params.WebhookID,
+		Event:         params.Event,
+		Payload:       params.Payload,
+		NextAttemptAt: params.NextAttemptAt,
+		CreatedAt:     store.Now(),
+	}
+	ws.deliveries = append(ws.deliveries, delivery)
+	return delivery, nil
+}
+
+func (ws *MemoryWebhookStore) GetDueDeliveries(ctx context.Context, now time.Time, maxResults int64) ([]db.GetDueDeliveriesRow, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	due := []db.GetDueDeliveriesRow{}
+	for _, d := range ws.deliveries {
+		if d.NextAttemptAt.Valid && !d.NextAttemptAt.Time.After(now) {
+			webhook := ws.webhooks[ws.find(d.WebhookID)]
+			due = append(due, db.GetDueDeliveriesRow{WebhookDelivery: d, Url: webhook.Url, Secret: webhook.Secret})
+		}
+	}
+	slices.SortFunc(due, func(a, b db.GetDueDeliveriesRow) int {
+		return cmp.Or(
+			a.WebhookDelivery.NextAttemptAt.Time.Compare(b.WebhookDelivery.NextAttemptAt.Time),
+			cmp.Compare(a.WebhookDelivery.ID, b.WebhookDelivery.ID),
+		)
+	})
+	return due[:min(int64(len(due)), maxResults)], nil
+}
+
+func (ws *MemoryWebhookStore) GetDeliveries(ctx context.Context, maxResults int64) ([]db.GetDeliveriesRow, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	deliveries := []db.GetDeliveriesRow{}
+	for _, d := range slices.Backward(ws.deliveries) {
+		deliveries = append(deliveries, db.GetDeliveriesRow{WebhookDelivery: d, Url: ws.webhooks[ws.find(d.WebhookID)].Url})
+	}
+	return deliveries[:min(int64(len(deliveries)), maxResults)], nil
+}
+
+func (ws *MemoryWebhookStore) SetDeliveryDelivered(ctx context.Context, params db.SetDeliveryDeliveredParams) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if i := ws.findDelivery(params.ID); i >= 0 {
+		ws.deliveries[i].Attempts++
+		ws.deliveries[i].LastStatus = params.LastStatus
+		ws.deliveries[i].DeliveredAt = sql.NullTime{Valid: true, Time: params.DeliveredAt.Time.UTC().Truncate(time.Second)}
+		ws.deliveries[i].NextAttemptAt = sql.NullTime{}
+		ws.deliveries[i].LastError = ""
+	}
+	return nil
+}
+
+func (ws *MemoryWebhookStore) SetDeliveryFailed(ctx context.Context, params db.SetDeliveryFailedParams) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if i := ws.findDelivery(params.ID); i >= 0 {
+		ws.deliveries[i].Attempts++
+		ws.deliveries[i].LastStatus = params.LastStatus
+		ws.deliveries[i].LastError = params.LastError
+		ws.deliveries[i].NextAttemptAt = params.NextAttemptAt
+		if params.NextAttemptAt.Valid {
+			ws.deliveries[i].NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+		}
+	}
+	return nil
+}
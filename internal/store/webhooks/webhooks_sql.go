@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type WebhookStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewWebhookStore(queries db.Querier, logger *log.Logger) *WebhookStore {
+	return &WebhookStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ws *WebhookStore) AddWebhook(ctx context.Context, params db.AddWebhookParams) (db.Webhook, error) {
+	if err := validateWebhook(params); err != nil {
+		return db.Webhook{}, err
+	}
+
+	webhook, err := ws.queries.AddWebhook(ctx, normalizeWebhook(params))
+	if err != nil {
+		ws.logger.Printf("error adding webhook: %v", err)
+		return db.Webhook{}, err
+	}
+
+	// Not the secret, which only the webhook's receiver should know
+	ws.logger.Printf("webhook added: %d to %s for %s", webhook.ID, webhook.Url, webhook.Events)
+	return webhook, nil
+}
+
+func (ws *WebhookStore) GetWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	webhook, err := ws.queries.GetWebhook(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Webhook{}, ErrWebhookNotFound{ID: id}
+		}
+		ws.logger.Printf("error getting webhook: %v", err)
+		return db.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Every webhook, oldest first
+func (ws *WebhookStore) GetWebhooks(ctx context.Context) ([]db.Webhook, error) {
+	webhooks, err := ws.queries.GetWebhooks(ctx)
+	if err != nil {
+		ws.logger.Printf("error getting webhooks: %v", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Deletes the webhook along with its deliveries, including any still waiting to go
+func (ws *WebhookStore) DeleteWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	webhook, err := ws.queries.DeleteWebhook(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Webhook{}, ErrWebhookNotFound{ID: id}
+		}
+		ws.logger.Printf("error deleting webhook: %v", err)
+		return db.Webhook{}, err
+	}
+
+	ws.logger.Printf("webhook deleted: %d to %s", webhook.ID, webhook.Url)
+	return webhook, nil
+}
+
+func (ws *WebhookStore) QueueDelivery(ctx context.Context, params db.QueueDeliveryParams) (db.WebhookDelivery, error) {
+	delivery, err := ws.queries.QueueDelivery(ctx, normalizeDelivery(params))
+	if err != nil {
+		if store.ViolatedConstraint(err) == store.ForeignKeyConstraint {
+			return db.WebhookDelivery{}, ErrWebhookNotFound{ID: params.WebhookID}
+		}
+		ws.logger.Printf("error queueing delivery: %v", err)
+		return db.WebhookDelivery{}, err
+	}
+
+	ws.logger.Printf("delivery queued: %d to webhook %d: %s", delivery.ID, delivery.WebhookID, delivery.Event)
+	return delivery, nil
+}
+
+// The deliveries which should be tried now, the ones which have waited longest first
+func (ws *WebhookStore) GetDueDeliveries(ctx context.Context, now time.Time, maxResults int64) ([]db.GetDueDeliveriesRow, error) {
+	deliveries, err := ws.queries.GetDueDeliveries(ctx, db.GetDueDeliveriesParams{
+		Now:        sql.NullTime{Valid: true, Time: now.UTC()},
+		MaxResults: maxResults,
+	})
+	if err != nil {
+		ws.logger.Printf("error getting due deliveries: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// The latest deliveries, whether they've been delivered or not
+func (ws *WebhookStore) GetDeliveries(ctx context.Context, maxResults int64) ([]db.GetDeliveriesRow, error) {
+	deliveries, err := ws.queries.GetDeliveries(ctx, maxResults)
+	if err != nil {
+		ws.logger.Printf("error getting deliveries: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (ws *WebhookStore) SetDeliveryDelivered(ctx context.Context, params db.SetDeliveryDeliveredParams) error {
+	params.DeliveredAt.Time = params.DeliveredAt.Time.UTC().Truncate(time.Second)
+	if err := ws.queries.SetDeliveryDelivered(ctx, params); err != nil {
+		ws.logger.Printf("error setting delivery delivered: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (ws *WebhookStore) SetDeliveryFailed(ctx context.Context, params db.SetDeliveryFailedParams) error {
+	params.NextAttemptAt.Time = params.NextAttemptAt.Time.UTC().Truncate(time.Second)
+	if err := ws.queries.SetDeliveryFailed(ctx, params); err != nil {
+		ws.logger.Printf("error setting delivery failed: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package wishlist
+
+import (
+	"beer_oclock/internal/db"
+	"cmp"
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+// What the wishlist page is narrowed down to. Each part that isn't given matches everything.
+type Filter struct {
+	// Matched ignoring case, since styles are free text
+	Style    string
+	BrewerID sql.NullInt64
+	// Inclusive
+	MinAbv sql.NullFloat64
+	MaxAbv sql.NullFloat64
+}
+
+func (f Filter) matches(row db.GetWishlistRow) bool {
+	if f.Style != "" && !strings.EqualFold(strings.TrimSpace(row.Beer.Style.String), strings.TrimSpace(f.Style)) {
+		return false
+	}
+	if f.BrewerID.Valid && row.Beer.BrewerID.Int64 != f.BrewerID.Int64 {
+		return false
+	}
+	if f.MinAbv.Valid && row.Beer.Abv < f.MinAbv.Float64 {
+		return false
+	}
+	return !f.MaxAbv.Valid || row.Beer.Abv <= f.MaxAbv.Float64
+}
+
+// The rows matching the filter, in the same order
+func (f Filter) Apply(rows []db.GetWishlistRow) []db.GetWishlistRow {
+	matching := []db.GetWishlistRow{}
+	for _, row := range rows {
+		if f.matches(row) {
+			matching = append(matching, row)
+		}
+	}
+	return matching
+}
+
+// A brewer which can be filtered by
+type BrewerOption struct {
+	ID   int64
+	Name string
+}
+
+// The styles and brewers of the beers on the list, alphabetically, for choosing what to filter by.
+// Styles differing only by case are the same style.
+func Options(rows []db.GetWishlistRow) ([]string, []BrewerOption) {
+	styles := []string{}
+	brewers := []BrewerOption{}
+	for _, row := range rows {
+		style := strings.TrimSpace(row.Beer.Style.String)
+		if style != "" && !slices.ContainsFunc(styles, func(s string) bool { return strings.EqualFold(s, style) }) {
+			styles = append(styles, style)
+		}
+		if row.BrewerName.Valid && !slices.ContainsFunc(brewers, func(b BrewerOption) bool { return b.ID == row.Beer.BrewerID.Int64 }) {
+			brewers = append(brewers, BrewerOption{ID: row.Beer.BrewerID.Int64, Name: row.BrewerName.String})
+		}
+	}
+	slices.SortFunc(styles, func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	slices.SortFunc(brewers, func(a, b BrewerOption) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) })
+	return styles, brewers
+}
@@ -0,0 +1,56 @@
+package wishlist
+
+import (
+	"beer_oclock/internal/db"
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	row := func(beerId int64, style string, brewerId int64, brewerName string, abv float64) db.GetWishlistRow {
+		r := db.GetWishlistRow{Beer: db.Beer{ID: beerId, Abv: abv}}
+		if style != "" {
+			r.Beer.Style = sql.NullString{Valid: true, String: style}
+		}
+		if brewerId != 0 {
+			r.Beer.BrewerID = sql.NullInt64{Valid: true, Int64: brewerId}
+			r.BrewerName = sql.NullString{Valid: true, String: brewerName}
+		}
+		return r
+	}
+	rows := []db.GetWishlistRow{
+		row(1, "Stout", 2, "Garage Project", 8),
+		row(2, "american IPA", 1, "Behemoth", 6.5),
+		row(3, "American IPA", 2, "Garage Project", 5),
+		row(4, "", 0, "", 4.5),
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{"nothing", Filter{}, []int64{1, 2, 3, 4}},
+		{"style ignoring case", Filter{Style: "American IPA "}, []int64{2, 3}},
+		{"brewer", Filter{BrewerID: sql.NullInt64{Valid: true, Int64: 2}}, []int64{1, 3}},
+		{"abv range inclusive", Filter{MinAbv: sql.NullFloat64{Valid: true, Float64: 5}, MaxAbv: sql.NullFloat64{Valid: true, Float64: 6.5}}, []int64{2, 3}},
+		{"everything", Filter{Style: "american ipa", BrewerID: sql.NullInt64{Valid: true, Int64: 2}, MaxAbv: sql.NullFloat64{Valid: true, Float64: 5}}, []int64{3}},
+	} {
+		got := []int64{}
+		for _, r := range tc.filter.Apply(rows) {
+			got = append(got, r.Beer.ID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got beers %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	styles, brewers := Options(rows)
+	if !slices.Equal(styles, []string{"american IPA", "Stout"}) {
+		t.Errorf("got styles %v", styles)
+	}
+	if !slices.Equal(brewers, []BrewerOption{{1, "Behemoth"}, {2, "Garage Project"}}) {
+		t.Errorf("got brewers %v", brewers)
+	}
+}
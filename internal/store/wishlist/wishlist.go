@@ -0,0 +1,43 @@
+package wishlist
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// The operations the rest of the app needs on each user's list of beers they want to try,
+// implemented by WishlistStore (backed by the database) and MemoryWishlistStore (for tests)
+type Store interface {
+	AddWish(ctx context.Context, params db.AddWishParams) (db.Wishlist, error)
+	UpdateWish(ctx context.Context, params db.UpdateWishParams) (db.Wishlist, error)
+	RemoveWish(ctx context.Context, userId int64, beerId int64) error
+	// The user's list, the highest priority first and then the oldest, without beers in the trash
+	GetWishlist(ctx context.Context, userId int64) ([]db.GetWishlistRow, error)
+}
+
+var _ Store = (*WishlistStore)(nil)
+var _ Store = (*MemoryWishlistStore)(nil)
+
+// How much the user wants to try a beer, from the most to the least. Priority is optional.
+const (
+	HighPriority   = 1
+	MediumPriority = 2
+	LowPriority    = 3
+)
+
+func validatePriority(priority sql.NullInt64) error {
+	if priority.Valid && (priority.Int64 < HighPriority || priority.Int64 > LowPriority) {
+		return store.ErrInvalidField{Field: "priority", Reason: fmt.Sprintf("must be between %d and %d", HighPriority, LowPriority)}
+	}
+	return nil
+}
+
+// A note that's only space is no note
+func normalizeNote(note sql.NullString) sql.NullString {
+	text := strings.TrimSpace(note.String)
+	return sql.NullString{Valid: note.Valid && text != "", String: text}
+}
@@ -0,0 +1,21 @@
+package wishlist
+
+import "fmt"
+
+type ErrWishNotFound struct {
+	UserID int64
+	BeerID int64
+}
+
+func (e ErrWishNotFound) Error() string {
+	return fmt.Sprintf("beer with id %d is not on the wishlist of user %d", e.BeerID, e.UserID)
+}
+
+type ErrAlreadyWished struct {
+	UserID int64
+	BeerID int64
+}
+
+func (e ErrAlreadyWished) Error() string {
+	return fmt.Sprintf("beer with id %d is already on the wishlist of user %d", e.BeerID, e.UserID)
+}
@@ -0,0 +1,135 @@
+package wishlist
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"beer_oclock/internal/store/brewers"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+)
+
+// An in-memory implementation of Store for tests, which enforces the same constraints and returns
+// the same errors as WishlistStore. The beer store stands in for the foreign key, and along with
+// the brewer store for the joins.
+type MemoryWishlistStore struct {
+	mu          sync.Mutex
+	beerStore   beers.Store
+	brewerStore brewers.Store
+	wishes      []db.Wishlist
+}
+
+func NewMemoryWishlistStore(beerStore beers.Store, brewerStore brewers.Store) *MemoryWishlistStore {
+	return &MemoryWishlistStore{
+		beerStore:   beerStore,
+		brewerStore: brewerStore,
+	}
+}
+
+// The index of the user's wish for the beer, or -1 if there isn't one. Must be called with the
+// lock held.
+func (ws *MemoryWishlistStore) find(userId int64, beerId int64) int {
+	return slices.IndexFunc(ws.wishes, func(w db.Wishlist) bool { return w.UserID == userId && w.BeerID == beerId })
+}
+
+func (ws *MemoryWishlistStore) AddWish(ctx context.Context, params db.AddWishParams) (db.Wishlist, error) {
+	if err := validatePriority(params.Priority); err != nil {
+		return db.Wishlist{}, err
+	}
+	if _, err := ws.beerStore.GetBeer(ctx, params.BeerID); err != nil {
+		return db.Wishlist{}, beers.ErrBeerNotFound{ID: params.BeerID}
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.find(params.UserID, params.BeerID) >= 0 {
+		return db.Wishlist{}, ErrAlreadyWished{UserID: params.UserID, BeerID: params.BeerID}
+	}
+	wish := db.Wishlist{
+		UserID:    params.UserID,
+		BeerID:    params.BeerID,
+		Priority:  params.Priority,
+		Note:      normalizeNote(params.Note),
+		CreatedAt: store.Now(),
+	}
+	ws.wishes = append(ws.wishes, wish)
+	return wish, nil
+}
+
+func (ws *MemoryWishlistStore) UpdateWish(ctx context.Context, params db.UpdateWishParams) (db.Wishlist, error) {
+	if err := validatePriority(params.Priority); err != nil {
+		return db.Wishlist{}, err
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	i := ws.find(params.UserID, params.BeerID)
+	if i < 0 {
+		return db.Wishlist{}, ErrWishNotFound{UserID: params.UserID, BeerID: params.BeerID}
+	}
+	ws.wishes[i].Priority = params.Priority
+	ws.wishes[i].Note = normalizeNote(params.Note)
+	return ws.wishes[i], nil
+}
+
+func (ws *MemoryWishlistStore) RemoveWish(ctx context.Context, userId int64, beerId int64) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	i := ws.find(userId, beerId)
+	if i < 0 {
+		return ErrWishNotFound{UserID: userId, BeerID: beerId}
+	}
+	ws.wishes = slices.Delete(ws.wishes, i, i+1)
+	return nil
+}
+
+func (ws *MemoryWishlistStore) GetWishlist(ctx context.Context, userId int64) ([]db.GetWishlistRow, error) {
+	ws.mu.Lock()
+	wishes := []db.Wishlist{}
+	for _, w := range ws.wishes {
+		if w.UserID == userId {
+			wishes = append(wishes, w)
+		}
+	}
+	ws.mu.Unlock()
+
+	// Highest priority first with no priority last, then the oldest, as the query orders them
+	slices.SortStableFunc(wishes, func(a, b db.Wishlist) int {
+		if a.Priority.Valid != b.Priority.Valid {
+			if a.Priority.Valid {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(a.Priority.Int64, b.Priority.Int64); c != 0 {
+			return c
+		}
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.BeerID, b.BeerID)
+	})
+
+	// Beers in the trash aren't found, so they're left out the way the query leaves them out
+	rows := []db.GetWishlistRow{}
+	for _, w := range wishes {
+		beer, err := ws.beerStore.GetBeer(ctx, w.BeerID)
+		if err != nil {
+			continue
+		}
+		row := db.GetWishlistRow{Wishlist: w, Beer: beer}
+		if beer.BrewerID.Valid {
+			if brewer, err := ws.brewerStore.GetBrewer(ctx, beer.BrewerID.Int64); err == nil {
+				row.BrewerName = sql.NullString{Valid: true, String: brewer.Name}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
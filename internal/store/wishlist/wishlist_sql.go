@@ -0,0 +1,85 @@
+package wishlist
+
+import (
+	"beer_oclock/internal/db"
+	"beer_oclock/internal/store"
+	"beer_oclock/internal/store/beers"
+	"context"
+	"database/sql"
+	"log"
+)
+
+type WishlistStore struct {
+	queries db.Querier
+	logger  *log.Logger
+}
+
+func NewWishlistStore(queries db.Querier, logger *log.Logger) *WishlistStore {
+	return &WishlistStore{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (ws *WishlistStore) AddWish(ctx context.Context, params db.AddWishParams) (db.Wishlist, error) {
+	if err := validatePriority(params.Priority); err != nil {
+		return db.Wishlist{}, err
+	}
+	params.Note = normalizeNote(params.Note)
+
+	wish, err := db.Joined(ctx, ws.queries).AddWish(ctx, params)
+	if err != nil {
+		switch store.ViolatedConstraint(err) {
+		case store.ForeignKeyConstraint:
+			// The user is the one signed in, so it's the beer that's missing
+			return db.Wishlist{}, beers.ErrBeerNotFound{ID: params.BeerID}
+		case store.UniqueConstraint:
+			return db.Wishlist{}, ErrAlreadyWished{UserID: params.UserID, BeerID: params.BeerID}
+		}
+		ws.logger.Printf("error adding wish: %v", err)
+		return db.Wishlist{}, err
+	}
+
+	ws.logger.Printf("wish added: beer %d for user %d", params.BeerID, params.UserID)
+	return wish, nil
+}
+
+func (ws *WishlistStore) UpdateWish(ctx context.Context, params db.UpdateWishParams) (db.Wishlist, error) {
+	if err := validatePriority(params.Priority); err != nil {
+		return db.Wishlist{}, err
+	}
+	params.Note = normalizeNote(params.Note)
+
+	wish, err := db.Joined(ctx, ws.queries).UpdateWish(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Wishlist{}, ErrWishNotFound{UserID: params.UserID, BeerID: params.BeerID}
+		}
+		ws.logger.Printf("error updating wish: %v", err)
+		return db.Wishlist{}, err
+	}
+	return wish, nil
+}
+
+func (ws *WishlistStore) RemoveWish(ctx context.Context, userId int64, beerId int64) error {
+	removed, err := db.Joined(ctx, ws.queries).DeleteWish(ctx, db.DeleteWishParams{UserID: userId, BeerID: beerId})
+	if err != nil {
+		ws.logger.Printf("error removing wish: %v", err)
+		return err
+	}
+	if removed == 0 {
+		return ErrWishNotFound{UserID: userId, BeerID: beerId}
+	}
+
+	ws.logger.Printf("wish removed: beer %d for user %d", beerId, userId)
+	return nil
+}
+
+func (ws *WishlistStore) GetWishlist(ctx context.Context, userId int64) ([]db.GetWishlistRow, error) {
+	rows, err := db.Joined(ctx, ws.queries).GetWishlist(ctx, userId)
+	if err != nil {
+		ws.logger.Printf("error getting wishlist: %v", err)
+		return nil, err
+	}
+	return rows, nil
+}
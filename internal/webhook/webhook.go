@@ -0,0 +1,104 @@
+// Package webhook sends events to the URLs admins have subscribed, signed so the receivers can
+// check they came from the app
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The headers sent with each delivery. The signature is "sha256=" and the hex encoded
+// HMAC-SHA256 of the body, keyed with the webhook's secret.
+const (
+	EventHeader     = "X-Beer-Oclock-Event"
+	DeliveryHeader  = "X-Beer-Oclock-Delivery"
+	SignatureHeader = "X-Beer-Oclock-Signature"
+)
+
+// How long a receiver has to respond before the delivery counts as failed
+const DefaultTimeout = 10 * time.Second
+
+// An event to send to a webhook
+type Delivery struct {
+	ID      int64
+	URL     string
+	Secret  string
+	Event   string
+	Payload []byte
+}
+
+// The receiver didn't respond with a 2xx status
+type ErrStatus struct {
+	StatusCode int
+}
+
+func (e ErrStatus) Error() string {
+	return fmt.Sprintf("webhook responded with %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Makes a new secret for signing a webhook's payloads, 32 random bytes hex encoded
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// The signature of the body with the secret, as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Whether the signature is the body's with the secret, for receivers to check deliveries with.
+// The comparison takes the same time however much of the signature matches.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Posts deliveries to their webhooks
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Posts the delivery's payload to its webhook, returning the status the receiver responded with,
+// or 0 if it didn't. Anything but a 2xx status is an ErrStatus.
+func (s *Sender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Beer-Oclock-Webhook")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Read a little of the response so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, ErrStatus{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	// From RFC 4231's second test case
+	got := Sign("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"beer.created"}`)
+	signature := Sign("secret", body)
+
+	if !Verify("secret", body, signature) {
+		t.Error("Verify() = false for the body's signature")
+	}
+	if Verify("other secret", body, signature) {
+		t.Error("Verify() = true with the wrong secret")
+	}
+	if Verify("secret", []byte(`{"event":"beer.deleted"}`), signature) {
+		t.Error("Verify() = true for a different body")
+	}
+	if Verify("secret", body, "") {
+		t.Error("Verify() = true without a signature")
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(DefaultTimeout)
+	delivery := Delivery{
+		ID:      7,
+		URL:     receiver.URL + "/hook",
+		Secret:  "secret",
+		Event:   "beer.created",
+		Payload: []byte(`{"event":"beer.created"}`),
+	}
+
+	got, err := sender.Send(context.Background(), delivery)
+	if err != nil || got != http.StatusNoContent {
+		t.Fatalf("Send() = %d, %v, want 204", got, err)
+	}
+	if received.Method != http.MethodPost || received.URL.Path != "/hook" {
+		t.Errorf("Got %s %s, want POST /hook", received.Method, received.URL.Path)
+	}
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("Got body %s, want %s", body, delivery.Payload)
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		EventHeader:    "beer.created",
+		DeliveryHeader: "7",
+	}
+	for header, want := range headers {
+		if got := received.Header.Get(header); got != want {
+			t.Errorf("Got %s %q, want %q", header, got, want)
+		}
+	}
+	if !Verify("secret", body, received.Header.Get(SignatureHeader)) {
+		t.Errorf("Signature %s doesn't match the body", received.Header.Get(SignatureHeader))
+	}
+
+	status = http.StatusInternalServerError
+	got, err = sender.Send(context.Background(), delivery)
+	var statusErr ErrStatus
+	if got != http.StatusInternalServerError || !errors.As(err, &statusErr) {
+		t.Errorf("Send() = %d, %v, want 500 and ErrStatus", got, err)
+	}
+
+	receiver.Close()
+	if got, err := sender.Send(context.Background(), delivery); got != 0 || err == nil {
+		t.Errorf("Send() to a closed server = %d, %v, want 0 and an error", got, err)
+	}
+}